@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+// AuditRecord, record of one completed cleaning operation. Fingerprints are SHA-256 hashes: of the
+// request records or the input file, and of the response body or the output file.
+type AuditRecord struct {
+	RequestID         string    `json:"request_id"`
+	Time              time.Time `json:"time"`
+	Tenant            string    `json:"tenant,omitempty"`
+	Client            string    `json:"client"`    // Remote address of the caller
+	Operation         string    `json:"operation"` // Path of the endpoint, e.g. /clean-file
+	Pipeline          string    `json:"pipeline,omitempty"`
+	Actions           []string  `json:"actions"` // Resolved actions, pipeline actions first
+	InputPath         string    `json:"input_path,omitempty"`
+	InputFingerprint  string    `json:"input_fingerprint"`
+	OutputPath        string    `json:"output_path,omitempty"`
+	OutputFingerprint string    `json:"output_fingerprint"`
+	JobID             string    `json:"job_id,omitempty"`
+	RowsIn            int       `json:"rows_in"`
+	RowsOut           int       `json:"rows_out"`
+	Cached            bool      `json:"cached,omitempty"` // Served from the result cache
+}
+
+// auditFilter, selection of records returned by auditStore.List
+type auditFilter struct {
+	Tenant string
+	Since  time.Time // Zero for all records
+	Limit  int       // Newest records kept when more match
+}
+
+// auditStore, append-only storage of audit records
+type auditStore interface {
+	// Append stores the record
+	Append(ctx context.Context, record AuditRecord) error
+	// List returns the records of the tenant matching the filter, newest first
+	List(ctx context.Context, filter auditFilter) ([]AuditRecord, error)
+}
+
+// auditLog, audit store used by the handlers (nil: auditing disabled)
+var auditLog auditStore
+
+// auditFromEnv reads AUDIT_LOG, the JSON lines file records are appended to. When it is empty,
+// auditing is disabled.
+func auditFromEnv() (auditStore, error) {
+	path := os.Getenv("AUDIT_LOG")
+	if path == "" {
+		return nil, nil
+	}
+	return newFileAuditStore(path)
+}
+
+// fileAuditStore, audit store appending one JSON line per record to a file
+type fileAuditStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// newFileAuditStore creates the file and its directory if needed
+func newFileAuditStore(path string) (*fileAuditStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	file.Close()
+	return &fileAuditStore{path: path}, nil
+}
+
+// Append writes the record as a single line and syncs it to disk
+func (s *fileAuditStore) Append(_ context.Context, record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// List scans the file; lines that cannot be decoded are skipped
+func (s *fileAuditStore) List(_ context.Context, filter auditFilter) ([]AuditRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if record.Tenant != filter.Tenant || record.Time.Before(filter.Since) {
+			continue
+		}
+		records = append(records, record)
+		if filter.Limit > 0 && len(records) > filter.Limit {
+			records = records[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	return records, nil
+}
+
+// fingerprint returns the hex SHA-256 hash of the data
+func fingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// audit completes the record with the request details and appends it to the audit log. Audit
+// failures are logged.
+func audit(r *http.Request, record AuditRecord) {
+	if auditLog == nil {
+		return
+	}
+	record.RequestID = requestID(r)
+	record.Time = time.Now().UTC()
+	record.Tenant = requestTenant(r)
+	record.Client = r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		record.Client = host
+	}
+	record.Operation = r.URL.Path
+	if err := auditLog.Append(r.Context(), record); err != nil {
+		log.Print(i18n.T(language, "[%s] audit log write failed: %v", requestID(r), err))
+	}
+}
+
+// defaultAuditLimit and maxAuditLimit, records returned by GET /audit without and with limit
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// handleAudit, lists the audit records of the tenant, newest first. The since query parameter
+// (RFC 3339) skips older records and limit caps the number of records.
+func handleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, CodeMethodNotAllowed, "Only GET requests are supported", nil)
+		return
+	}
+	if auditLog == nil {
+		writeError(w, r, CodeAuditDisabled, "Audit log is not enabled", nil)
+		return
+	}
+
+	filter := auditFilter{Tenant: requestTenant(r), Limit: defaultAuditLimit}
+	if v := r.URL.Query().Get("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, r, CodeInvalidParameter, "since must be an RFC 3339 time", err)
+			return
+		}
+		filter.Since = since
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, r, CodeInvalidParameter, "limit must be a positive integer", err)
+			return
+		}
+		filter.Limit = min(n, maxAuditLimit)
+	}
+
+	records, err := auditLog.List(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, CodeInternal, "Audit log could not be read", err)
+		return
+	}
+	if records == nil {
+		records = []AuditRecord{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"records": records})
+}
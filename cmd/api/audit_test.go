@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// withAuditLog swaps the package audit store for a file store in a temporary directory
+func withAuditLog(t *testing.T) *fileAuditStore {
+	t.Helper()
+	store, err := newFileAuditStore(filepath.Join(t.TempDir(), "audit", "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := auditLog
+	auditLog = store
+	t.Cleanup(func() { auditLog = saved })
+	return store
+}
+
+func TestFileAuditStore(t *testing.T) {
+	store := withAuditLog(t)
+	ctx := context.Background()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, tenant := range []string{"", "crm", "", ""} {
+		record := AuditRecord{RequestID: string(rune('a' + i)), Tenant: tenant, Time: start.Add(time.Duration(i) * time.Hour)}
+		if err := store.Append(ctx, record); err != nil {
+			t.Fatalf("Append error: %v", err)
+		}
+	}
+
+	records, err := store.List(ctx, auditFilter{Limit: 10})
+	if err != nil || len(records) != 3 || records[0].RequestID != "d" || records[2].RequestID != "a" {
+		t.Fatalf("expected the 3 records of the default tenant newest first, got %+v, %v", records, err)
+	}
+	records, _ = store.List(ctx, auditFilter{Limit: 2})
+	if len(records) != 2 || records[0].RequestID != "d" || records[1].RequestID != "c" {
+		t.Errorf("expected the 2 newest records, got %+v", records)
+	}
+	records, _ = store.List(ctx, auditFilter{Since: start.Add(3 * time.Hour)})
+	if len(records) != 1 || records[0].RequestID != "d" {
+		t.Errorf("expected the records since the time, got %+v", records)
+	}
+	records, _ = store.List(ctx, auditFilter{Tenant: "crm"})
+	if len(records) != 1 || records[0].RequestID != "b" {
+		t.Errorf("expected only the records of the tenant, got %+v", records)
+	}
+}
+
+func TestHandleClean_Audit(t *testing.T) {
+	store := withAuditLog(t)
+
+	body := `{"data":[{"name":"  Ali  "},{"name":""}],"actions":["trim"]}`
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body))
+	req.Header.Set(requestIDHeader, "req-1")
+	w := httptest.NewRecorder()
+	withRequestID(http.HandlerFunc(handleClean)).ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	records, err := store.List(context.Background(), auditFilter{})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("expected 1 record, got %+v, %v", records, err)
+	}
+	record := records[0]
+	input, _ := json.Marshal([]map[string]interface{}{{"name": "  Ali  "}, {"name": ""}})
+	if record.RequestID != "req-1" || record.Operation != "/clean" || record.Actions[0] != "trim" || record.RowsIn != 2 || record.RowsOut != 2 {
+		t.Errorf("unexpected record: %+v", record)
+	}
+	if record.InputFingerprint != fingerprint(input) || record.OutputFingerprint != fingerprint(w.Body.Bytes()) {
+		t.Errorf("unexpected fingerprints: %+v", record)
+	}
+}
+
+func TestHandleCleanFile_Audit(t *testing.T) {
+	store := withAuditLog(t)
+
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "in.csv")
+	if err := os.WriteFile(input, []byte("name\n  Ali  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "out.csv")
+	body, _ := json.Marshal(FileCleanRequest{FilePath: input, Actions: []string{"trim"}, Output: output})
+	w := httptest.NewRecorder()
+	handleCleanFile(w, httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBuffer(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	records, _ := store.List(context.Background(), auditFilter{})
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %+v", records)
+	}
+	content, _ := os.ReadFile(output)
+	record := records[0]
+	if record.InputPath != input || record.InputFingerprint != fingerprint([]byte("name\n  Ali  \n")) {
+		t.Errorf("unexpected input: %+v", record)
+	}
+	if record.OutputPath != output || record.OutputFingerprint != fingerprint(content) || record.RowsOut != 1 {
+		t.Errorf("unexpected output: %+v", record)
+	}
+}
+
+func TestHandleAudit(t *testing.T) {
+	saved := auditLog
+	auditLog = nil
+	w := httptest.NewRecorder()
+	handleAudit(w, httptest.NewRequest(http.MethodGet, "/audit", nil))
+	auditLog = saved
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 when auditing is disabled, got %d", w.Code)
+	}
+
+	store := withAuditLog(t)
+	store.Append(context.Background(), AuditRecord{RequestID: "old", Time: time.Now().Add(-time.Hour)})
+	store.Append(context.Background(), AuditRecord{RequestID: "new", Time: time.Now()})
+
+	since := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	w = httptest.NewRecorder()
+	handleAudit(w, httptest.NewRequest(http.MethodGet, "/audit?since="+since, nil))
+	var resp struct {
+		Records []AuditRecord `json:"records"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusOK || len(resp.Records) != 1 || resp.Records[0].RequestID != "new" {
+		t.Errorf("expected the new record, got %d: %s", w.Code, w.Body.String())
+	}
+
+	for _, query := range []string{"since=yesterday", "limit=0"} {
+		w = httptest.NewRecorder()
+		handleAudit(w, httptest.NewRequest(http.MethodGet, "/audit?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 for %s, got %d", query, w.Code)
+		}
+	}
+}
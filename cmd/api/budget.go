@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExhausted is returned when workers cannot be reserved before the queue timeout
+var ErrBudgetExhausted = errors.New("worker budget exhausted")
+
+// workerBudget, server-wide pool of workers shared by all requests
+type workerBudget struct {
+	mu           sync.Mutex
+	size         int
+	perRequest   int // Workers of a parallel request without MaxWorkers
+	available    int
+	queueTimeout time.Duration
+	waiters      []*budgetWaiter
+}
+
+type budgetWaiter struct {
+	n     int
+	ready chan struct{}
+}
+
+// newWorkerBudget creates a budget of size workers. Requests wait up to queueTimeout for workers to
+// free up. A parallel request without MaxWorkers takes half of the budget, so two of them run at
+// the same time; see withPerRequest.
+func newWorkerBudget(size int, queueTimeout time.Duration) *workerBudget {
+	if size <= 0 {
+		size = runtime.NumCPU()
+	}
+	return &workerBudget{
+		size:         size,
+		perRequest:   max(1, size/2),
+		available:    size,
+		queueTimeout: queueTimeout,
+	}
+}
+
+// withPerRequest sets the workers of a parallel request without MaxWorkers, capped at the budget
+// size. Values below 1 keep the default.
+func (b *workerBudget) withPerRequest(n int) *workerBudget {
+	if n > 0 {
+		b.perRequest = min(n, b.size)
+	}
+	return b
+}
+
+// budgetFromEnv reads WORKER_BUDGET, WORKER_REQUEST_DEFAULT and WORKER_QUEUE_TIMEOUT
+func budgetFromEnv() *workerBudget {
+	size, _ := strconv.Atoi(os.Getenv("WORKER_BUDGET"))
+	timeout := 30 * time.Second
+	if v := os.Getenv("WORKER_QUEUE_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			timeout = d
+		}
+	}
+	perRequest, _ := strconv.Atoi(os.Getenv("WORKER_REQUEST_DEFAULT"))
+	return newWorkerBudget(size, timeout).withPerRequest(perRequest)
+}
+
+// budget, worker budget used by the handlers
+var budget = newWorkerBudget(0, 30*time.Second)
+
+// workersFor returns the number of workers a request is granted: serial requests take one
+// worker, parallel requests take MaxWorkers capped at the budget size, or the per-request default
+// when unset.
+func (b *workerBudget) workersFor(parallel bool, requested int) int {
+	if !parallel {
+		return 1
+	}
+	if requested <= 0 {
+		return b.perRequest
+	}
+	return min(requested, b.size)
+}
+
+// Acquire reserves n workers, queueing in FIFO order until they are available, the queue timeout
+// elapses or ctx is done. The returned function releases the workers.
+func (b *workerBudget) Acquire(ctx context.Context, n int) (func(), error) {
+	if n > b.size {
+		n = b.size
+	}
+
+	b.mu.Lock()
+	if len(b.waiters) == 0 && b.available >= n {
+		b.available -= n
+		b.mu.Unlock()
+		return b.releaser(n), nil
+	}
+	if b.queueTimeout <= 0 {
+		b.mu.Unlock()
+		return nil, ErrBudgetExhausted
+	}
+	waiter := &budgetWaiter{n: n, ready: make(chan struct{})}
+	b.waiters = append(b.waiters, waiter)
+	b.mu.Unlock()
+
+	timer := time.NewTimer(b.queueTimeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-waiter.ready:
+		return b.releaser(n), nil
+	case <-timer.C:
+		err = ErrBudgetExhausted
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	b.mu.Lock()
+	select {
+	case <-waiter.ready:
+		// Granted while timing out, hand the workers back
+		b.available += n
+	default:
+		for i, w := range b.waiters {
+			if w == waiter {
+				b.waiters = append(b.waiters[:i], b.waiters[i+1:]...)
+				break
+			}
+		}
+	}
+	b.notify()
+	b.mu.Unlock()
+	return nil, err
+}
+
+// releaser returns a function that gives n workers back to the budget exactly once
+func (b *workerBudget) releaser(n int) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			b.available += n
+			b.notify()
+			b.mu.Unlock()
+		})
+	}
+}
+
+// notify wakes queued waiters in order while workers are available. Caller must hold b.mu.
+func (b *workerBudget) notify() {
+	for len(b.waiters) > 0 {
+		next := b.waiters[0]
+		if b.available < next.n {
+			return
+		}
+		b.available -= next.n
+		b.waiters = b.waiters[1:]
+		close(next.ready)
+	}
+}
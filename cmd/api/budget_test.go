@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWorkerBudget_WorkersFor(t *testing.T) {
+	b := newWorkerBudget(8, time.Second)
+
+	tests := []struct {
+		parallel  bool
+		requested int
+		expect    int
+	}{
+		{false, 0, 1},
+		{false, 64, 1},
+		{true, 0, 4},
+		{true, 4, 4},
+		{true, 128, 8},
+	}
+
+	for _, tt := range tests {
+		if got := b.workersFor(tt.parallel, tt.requested); got != tt.expect {
+			t.Errorf("workersFor(%v, %d) = %d, want %d", tt.parallel, tt.requested, got, tt.expect)
+		}
+	}
+
+	if got := newWorkerBudget(1, time.Second).workersFor(true, 0); got != 1 {
+		t.Errorf("expected 1 worker of a budget of 1, got %d", got)
+	}
+	if got := newWorkerBudget(8, time.Second).withPerRequest(2).workersFor(true, 0); got != 2 {
+		t.Errorf("expected the configured default of 2 workers, got %d", got)
+	}
+	if got := newWorkerBudget(8, time.Second).withPerRequest(32).workersFor(true, 0); got != 8 {
+		t.Errorf("expected the default capped at the budget, got %d", got)
+	}
+}
+
+func TestWorkerBudget_ConcurrentDefaultParallelRequests(t *testing.T) {
+	b := newWorkerBudget(8, 0)
+
+	// Without a queue, the second request fails unless the first one left workers for it
+	first, err := b.Acquire(context.Background(), b.workersFor(true, 0))
+	if err != nil {
+		t.Fatalf("first request: unexpected error: %v", err)
+	}
+	defer first()
+	second, err := b.Acquire(context.Background(), b.workersFor(true, 0))
+	if err != nil {
+		t.Fatalf("second request: unexpected error: %v", err)
+	}
+	defer second()
+}
+
+func TestWorkerBudget_RejectWithoutQueue(t *testing.T) {
+	b := newWorkerBudget(2, 0)
+
+	release, err := b.Acquire(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := b.Acquire(context.Background(), 1); !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("expected ErrBudgetExhausted, got %v", err)
+	}
+
+	release()
+	release() // releasing twice must not inflate the budget
+
+	if b.available != 2 {
+		t.Errorf("expected 2 available workers, got %d", b.available)
+	}
+}
+
+func TestWorkerBudget_QueueUntilReleased(t *testing.T) {
+	b := newWorkerBudget(4, time.Second)
+
+	release, err := b.Acquire(context.Background(), 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		r, err := b.Acquire(context.Background(), 3)
+		if err == nil {
+			r()
+		}
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	release()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("queued acquire failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued acquire was not granted after release")
+	}
+}
+
+func TestWorkerBudget_QueueTimeout(t *testing.T) {
+	b := newWorkerBudget(1, 10*time.Millisecond)
+
+	release, _ := b.Acquire(context.Background(), 1)
+	defer release()
+
+	if _, err := b.Acquire(context.Background(), 1); !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("expected ErrBudgetExhausted, got %v", err)
+	}
+	if len(b.waiters) != 0 {
+		t.Errorf("timed out waiter should be removed from the queue")
+	}
+}
+
+func TestWorkerBudget_ContextCancelled(t *testing.T) {
+	b := newWorkerBudget(1, time.Second)
+
+	release, _ := b.Acquire(context.Background(), 1)
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := b.Acquire(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestHandleClean_BudgetExhausted(t *testing.T) {
+	saved := budget
+	budget = newWorkerBudget(1, 0)
+	defer func() { budget = saved }()
+
+	release, _ := budget.Acquire(context.Background(), 1)
+	defer release()
+
+	body := `{"data":[{"name":" Alice "}],"actions":["trim"],"parallel":true,"max_workers":128}`
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handleClean(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+// cacheVersion is part of every cache key, so results of older server versions are not reused
+const cacheVersion = "3"
+
+// cacheHeader reports whether a response was served from the result cache (HIT or MISS)
+const cacheHeader = "X-Cache"
+
+// cachedFile, cache entry of a cleaned file
+type cachedFile struct {
+	Statistics Statistics `json:"statistics"`
+	Content    []byte     `json:"content"`
+}
+
+// resultCache stores encoded results of cleaning requests by key
+type resultCache interface {
+	// Get returns the value stored under key and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value under key
+	Set(ctx context.Context, key string, value []byte) error
+}
+
+// results, cache of cleaning results used by the handlers (nil: caching disabled)
+var results resultCache
+
+// cacheFromEnv reads RESULT_CACHE and RESULT_CACHE_TTL. RESULT_CACHE is either a redis:// URL or a
+// directory for a local disk cache; when it is empty, caching is disabled.
+func cacheFromEnv() (resultCache, error) {
+	spec := os.Getenv("RESULT_CACHE")
+	if spec == "" {
+		return nil, nil
+	}
+
+	ttl := time.Hour
+	if v := os.Getenv("RESULT_CACHE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid RESULT_CACHE_TTL: %q", v)
+		}
+		ttl = d
+	}
+
+	if strings.HasPrefix(spec, "redis://") {
+		return newRedisCache(spec, ttl)
+	}
+	return newDiskCache(spec, ttl)
+}
+
+// cacheKey hashes the input fingerprint together with everything that affects the result
+// (resolved actions and output options)
+func cacheKey(kind string, fingerprint []byte, actions []string, options interface{}) string {
+	config, _ := json.Marshal(struct {
+		Version string      `json:"version"`
+		Kind    string      `json:"kind"`
+		Actions []string    `json:"actions"`
+		Options interface{} `json:"options"`
+	}{cacheVersion, kind, actions, options})
+
+	h := sha256.New()
+	h.Write(fingerprint)
+	h.Write([]byte{0})
+	h.Write(config)
+	return "cleango:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// fileFingerprint returns the SHA-256 hash of the file content
+func fileFingerprint(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// cacheGet looks the key up in the result cache. Cache errors are logged and treated as a miss.
+func cacheGet(r *http.Request, key string) ([]byte, bool) {
+	if results == nil {
+		return nil, false
+	}
+	value, ok, err := results.Get(r.Context(), key)
+	if err != nil {
+		log.Print(i18n.T(language, "[%s] result cache read failed: %v", requestID(r), err))
+		return nil, false
+	}
+	return value, ok
+}
+
+// cachePut stores the value in the result cache. Cache errors are logged.
+func cachePut(r *http.Request, key string, value []byte) {
+	if results == nil {
+		return
+	}
+	if err := results.Set(r.Context(), key, value); err != nil {
+		log.Print(i18n.T(language, "[%s] result cache write failed: %v", requestID(r), err))
+	}
+}
+
+// diskCache, result cache stored as files in a local directory
+type diskCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// newDiskCache creates the cache directory if needed
+func newDiskCache(dir string, ttl time.Duration) (*diskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create result cache directory: %w", err)
+	}
+	return &diskCache{dir: dir, ttl: ttl}, nil
+}
+
+// path returns the file of the key; keys are hex hashes with a prefix, so they are safe file names
+func (c *diskCache) path(key string) string {
+	return filepath.Join(c.dir, strings.ReplaceAll(key, ":", "-"))
+}
+
+// Get returns the cached value unless it is older than the TTL
+func (c *diskCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if time.Since(info.ModTime()) > c.ttl {
+		os.Remove(path)
+		return nil, false, nil
+	}
+
+	value, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set writes the value through a temporary file, so readers never see a partial entry
+func (c *diskCache) Set(_ context.Context, key string, value []byte) error {
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(value); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// redisCache, result cache stored in Redis with a TTL per entry
+type redisCache struct {
+	addr     string
+	username string
+	password string
+	db       int
+	ttl      time.Duration
+}
+
+// redisTimeout bounds every Redis command when the request context has no earlier deadline
+const redisTimeout = 5 * time.Second
+
+// newRedisCache parses a redis://[user:password@]host[:port][/db] URL
+func newRedisCache(rawURL string, ttl time.Duration) (*redisCache, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+
+	c := &redisCache{addr: u.Host, ttl: ttl}
+	if u.Port() == "" {
+		c.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		c.username = u.User.Username()
+		c.password, _ = u.User.Password()
+		if c.password == "" {
+			// redis://:password@host and redis://password@host are both common
+			c.username, c.password = "", c.username
+		}
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if c.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid Redis database: %q", db)
+		}
+	}
+	return c, nil
+}
+
+// Get runs GET key
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	return reply, true, nil
+}
+
+// Set runs SET key value PX ttl
+func (c *redisCache) Set(ctx context.Context, key string, value []byte) error {
+	_, err := c.do(ctx, "SET", key, string(value), "PX", strconv.FormatInt(c.ttl.Milliseconds(), 10))
+	return err
+}
+
+// do runs one command on a new connection, after authenticating and selecting the database
+func (c *redisCache) do(ctx context.Context, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	var commands [][]string
+	if c.password != "" {
+		if c.username != "" {
+			commands = append(commands, []string{"AUTH", c.username, c.password})
+		} else {
+			commands = append(commands, []string{"AUTH", c.password})
+		}
+	}
+	if c.db != 0 {
+		commands = append(commands, []string{"SELECT", strconv.Itoa(c.db)})
+	}
+	commands = append(commands, args)
+
+	// Commands are pipelined; only the reply of the last one is returned
+	w := bufio.NewWriter(conn)
+	for _, command := range commands {
+		writeRedisCommand(w, command)
+	}
+	if err := w.Flush(); err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+
+	r := bufio.NewReader(conn)
+	var reply []byte
+	for range commands {
+		if reply, err = readRedisReply(r); err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+	}
+	return reply, nil
+}
+
+// writeRedisCommand encodes the command as a RESP array of bulk strings
+func writeRedisCommand(w *bufio.Writer, args []string) {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+}
+
+// readRedisReply reads one RESP reply. A nil bulk string is returned as nil and error replies as errors.
+func readRedisReply(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, errors.New(line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk length: %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	default:
+		return nil, fmt.Errorf("unexpected reply: %q", line)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// withResultCache swaps the package result cache for the duration of a test
+func withResultCache(t *testing.T, cache resultCache) {
+	t.Helper()
+	saved := results
+	results = cache
+	t.Cleanup(func() { results = saved })
+}
+
+func TestDiskCache(t *testing.T) {
+	cache, err := newDiskCache(filepath.Join(t.TempDir(), "cache"), time.Hour)
+	if err != nil {
+		t.Fatalf("newDiskCache error: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, ok, err := cache.Get(ctx, "cleango:abc"); ok || err != nil {
+		t.Fatalf("expected a miss, got %v %v", ok, err)
+	}
+	if err := cache.Set(ctx, "cleango:abc", []byte("value")); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if value, ok, err := cache.Get(ctx, "cleango:abc"); !ok || err != nil || string(value) != "value" {
+		t.Errorf("expected a hit, got %q %v %v", value, ok, err)
+	}
+
+	// Expired entries are misses
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(cache.path("cleango:abc"), old, old)
+	if _, ok, _ := cache.Get(ctx, "cleango:abc"); ok {
+		t.Error("expected the expired entry to be a miss")
+	}
+}
+
+// fakeRedis is a minimal RESP server supporting AUTH, SELECT, GET and SET
+func fakeRedis(t *testing.T, password string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var mu sync.Mutex
+	store := map[string]string{}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				authenticated := password == ""
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+					args := make([]string, n)
+					for i := range args {
+						header, _ := r.ReadString('\n')
+						size, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+						arg := make([]byte, size+2)
+						io.ReadFull(r, arg)
+						args[i] = string(arg[:size])
+					}
+
+					mu.Lock()
+					switch {
+					case args[0] == "AUTH":
+						authenticated = args[len(args)-1] == password
+						conn.Write([]byte("+OK\r\n"))
+					case !authenticated:
+						conn.Write([]byte("-NOAUTH Authentication required.\r\n"))
+					case args[0] == "SELECT":
+						conn.Write([]byte("+OK\r\n"))
+					case args[0] == "SET":
+						store[args[1]] = args[2]
+						conn.Write([]byte("+OK\r\n"))
+					case args[0] == "GET":
+						if value, ok := store[args[1]]; ok {
+							conn.Write([]byte("$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n"))
+						} else {
+							conn.Write([]byte("$-1\r\n"))
+						}
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestRedisCache(t *testing.T) {
+	addr := fakeRedis(t, "secret")
+	ctx := context.Background()
+
+	cache, err := newRedisCache("redis://:secret@"+addr+"/2", time.Minute)
+	if err != nil {
+		t.Fatalf("newRedisCache error: %v", err)
+	}
+	if _, ok, err := cache.Get(ctx, "key"); ok || err != nil {
+		t.Fatalf("expected a miss, got %v %v", ok, err)
+	}
+	if err := cache.Set(ctx, "key", []byte("line\r\nvalue")); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if value, ok, err := cache.Get(ctx, "key"); !ok || err != nil || string(value) != "line\r\nvalue" {
+		t.Errorf("expected a hit, got %q %v %v", value, ok, err)
+	}
+
+	unauthorized, _ := newRedisCache("redis://"+addr, time.Minute)
+	if _, _, err := unauthorized.Get(ctx, "key"); err == nil || !strings.Contains(err.Error(), "NOAUTH") {
+		t.Errorf("expected an authentication error, got %v", err)
+	}
+
+	if _, err := newRedisCache("redis://localhost/db", time.Minute); err == nil {
+		t.Error("expected an invalid database error")
+	}
+}
+
+func TestHandleClean_ResultCache(t *testing.T) {
+	cache, _ := newDiskCache(t.TempDir(), time.Hour)
+	withResultCache(t, cache)
+
+	do := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		handleClean(w, req)
+		return w
+	}
+
+	body := `{"data":[{"name":"  Ali  "}],"actions":["trim"]}`
+	first := do(body)
+	second := do(body)
+	if first.Code != http.StatusOK || second.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d and %d", first.Code, second.Code)
+	}
+	if first.Header().Get(cacheHeader) != "MISS" || second.Header().Get(cacheHeader) != "HIT" {
+		t.Errorf("expected MISS then HIT, got %q and %q", first.Header().Get(cacheHeader), second.Header().Get(cacheHeader))
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Errorf("cached response differs: %s vs %s", first.Body.String(), second.Body.String())
+	}
+
+	if w := do(`{"data":[{"name":"  Ali  "}],"actions":["normalize_case:name=upper"]}`); w.Header().Get(cacheHeader) != "MISS" {
+		t.Errorf("expected a miss for different actions, got %q", w.Header().Get(cacheHeader))
+	}
+}
+
+func TestHandleCleanFile_ResultCache(t *testing.T) {
+	cache, _ := newDiskCache(t.TempDir(), time.Hour)
+	withResultCache(t, cache)
+
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "in.csv")
+	output := filepath.Join(dir, "out.csv")
+	if err := os.WriteFile(input, []byte("name\n  Ali  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	do := func() *httptest.ResponseRecorder {
+		body, _ := json.Marshal(FileCleanRequest{FilePath: input, Actions: []string{"trim"}, Output: output})
+		req := httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		handleCleanFile(w, req)
+		return w
+	}
+
+	if w := do(); w.Code != http.StatusOK || w.Header().Get(cacheHeader) != "MISS" {
+		t.Fatalf("expected a 200 miss, got %d %q: %s", w.Code, w.Header().Get(cacheHeader), w.Body.String())
+	}
+	os.Remove(output)
+
+	if w := do(); w.Code != http.StatusOK || w.Header().Get(cacheHeader) != "HIT" {
+		t.Fatalf("expected a 200 hit, got %d %q: %s", w.Code, w.Header().Get(cacheHeader), w.Body.String())
+	}
+	if content, _ := os.ReadFile(output); string(content) != "name\nAli\n" {
+		t.Errorf("unexpected restored output: %q", content)
+	}
+	// The restored output is written through a temporary file like a miss, which is not left behind
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("expected only the input and the output, got %d entries", len(entries))
+	}
+
+	// A changed input is a miss
+	if err := os.WriteFile(input, []byte("name\n  Ayşe  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if w := do(); w.Header().Get(cacheHeader) != "MISS" {
+		t.Errorf("expected a miss for a changed input, got %q", w.Header().Get(cacheHeader))
+	}
+}
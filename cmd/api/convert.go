@@ -0,0 +1,326 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+// ConvertRequest, structure for a file conversion request
+type ConvertRequest struct {
+	FilePath string `json:"file_path"`
+	Format   string `json:"format,omitempty"`
+	Output   string `json:"output,omitempty"`
+	// FormatOptions configures reading the input and writing the output (delimiter, sheet, compression, XML elements)
+	FormatOptions FormatOptions `json:"format_options,omitempty"`
+}
+
+// formatExtensions, file extension written for each format
+var formatExtensions = map[string]string{
+	"csv":     ".csv",
+	"json":    ".json",
+	"excel":   ".xlsx",
+	"parquet": ".parquet",
+	"xml":     ".xml",
+	"yaml":    ".yaml",
+}
+
+// maxUploadMemory, bytes of a multipart upload kept in memory; larger parts are spooled to disk
+const maxUploadMemory = 32 << 20
+
+// maxUploadSize, largest request body accepted by /upload, set by MAX_UPLOAD_SIZE
+var maxUploadSize int64 = 256 << 20
+
+// uploadLimitFromEnv reads MAX_UPLOAD_SIZE, the largest upload in bytes (default: 256 MiB)
+func uploadLimitFromEnv() (int64, error) {
+	v := os.Getenv("MAX_UPLOAD_SIZE")
+	if v == "" {
+		return maxUploadSize, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid MAX_UPLOAD_SIZE: %q", v)
+	}
+	return n, nil
+}
+
+// handleConvert, converts a file on the server to another format without cleaning it
+func handleConvert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, CodeMethodNotAllowed, "Only POST requests are supported", nil)
+		return
+	}
+
+	var req ConvertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, CodeInvalidJSON, "Request body is not valid JSON", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if req.FilePath == "" {
+		writeError(w, r, CodeMissingFilePath, "File path not specified", nil)
+		return
+	}
+	if !allowedFilePath(w, r, req.FilePath) {
+		return
+	}
+	inputFormat := getFileFormat(req.FilePath)
+	if inputFormat == "" {
+		writeError(w, r, CodeUnsupportedFormat, "Unsupported file format", nil)
+		return
+	}
+
+	// The output format is taken from format, then from the output extension
+	outputFormat := req.Format
+	if outputFormat == "" {
+		outputFormat = getFileFormat(req.Output)
+	}
+	if !isSupportedFormat(outputFormat) {
+		writeError(w, r, CodeUnsupportedFormat, "Unsupported output format", fmt.Errorf("format: %q", outputFormat))
+		return
+	}
+	outputFile := req.Output
+	if outputFile == "" {
+		outputFile = strings.TrimSuffix(req.FilePath, filepath.Ext(req.FilePath)) + formatExtensions[outputFormat]
+	}
+	if filepath.Clean(outputFile) == filepath.Clean(req.FilePath) {
+		writeError(w, r, CodeInvalidParameter, "Output must differ from the input file", nil)
+		return
+	}
+	// Without a workspace the output is written where requested, so it must stay in the working directory
+	if jobs == nil && !allowedFilePath(w, r, outputFile) {
+		return
+	}
+	if err := req.FormatOptions.Validate(); err != nil {
+		writeError(w, r, CodeInvalidFormatOptions, "Invalid format options", err)
+		return
+	}
+
+	var jobID string
+	jobDone := false
+	if jobs != nil {
+		id, dir, err := jobs.newJob(requestTenant(r))
+		if err != nil {
+			writeError(w, r, CodeWriteFailed, "File could not be written", err)
+			return
+		}
+		jobID = id
+		outputFile = filepath.Join(dir, filepath.Base(outputFile))
+		defer func() {
+			if !jobDone {
+				jobs.Delete(requestTenant(r), id)
+			}
+		}()
+	}
+
+	release, err := budget.Acquire(r.Context(), budget.workersFor(false, 0))
+	if err != nil {
+		writeError(w, r, classifyError(err, CodeServerBusy), "Server worker budget exhausted", err)
+		return
+	}
+	defer release()
+
+	span := startIOSpan(r.Context(), "read", inputFormat)
+	df, err := readDataFrame(req.FilePath, inputFormat, req.FormatOptions)
+	endIOSpan(span, df, err)
+	if err != nil {
+		writeError(w, r, classifyError(err, CodeReadFailed), "File could not be read", err)
+		return
+	}
+	metrics.rows(r, len(df.Data))
+
+	span = startIOSpan(r.Context(), "write", outputFormat)
+	err = writeDataFrame(df, outputFile, outputFormat, req.FormatOptions)
+	endIOSpan(span, df, err)
+	if err != nil {
+		writeError(w, r, CodeWriteFailed, "File could not be written", err)
+		return
+	}
+
+	resp := map[string]interface{}{
+		"message": i18n.T(requestLanguage(r), "File converted successfully"),
+		"output":  outputFile,
+		"rows":    len(df.Data),
+		"columns": len(df.Headers),
+	}
+	if jobID != "" {
+		if err := jobs.Enforce(requestTenant(r), jobID); err != nil {
+			if errors.Is(err, ErrQuotaExceeded) {
+				writeError(w, r, CodeQuotaExceeded, "Output is larger than the workspace quota", err)
+				return
+			}
+			writeError(w, r, CodeWriteFailed, "File could not be written", err)
+			return
+		}
+		resp["job_id"] = jobID
+	}
+	jobDone = true
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleUpload, cleans a file uploaded as multipart/form-data and returns the cleaned records like
+// /clean. The form holds the file part, the actions (repeated), pipeline, format, format_options
+// (as JSON), preserve_types, parallel and max_workers fields.
+func handleUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, CodeMethodNotAllowed, "Only POST requests are supported", nil)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, r, CodeUploadTooLarge, "Upload is larger than the allowed size", err)
+			return
+		}
+		writeError(w, r, CodeMissingFile, "Request has no uploaded file", err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, r, CodeMissingFile, "Request has no uploaded file", err)
+		return
+	}
+	defer file.Close()
+
+	// The input format is taken from format, then from the extension of the uploaded file name
+	format := r.FormValue("format")
+	if format == "" {
+		format = getFileFormat(header.Filename)
+	}
+	if !isSupportedFormat(format) {
+		writeError(w, r, CodeUnsupportedFormat, "Unsupported file format", fmt.Errorf("format: %q", format))
+		return
+	}
+	var opts FormatOptions
+	if value := r.FormValue("format_options"); value != "" {
+		if err := json.Unmarshal([]byte(value), &opts); err != nil {
+			writeError(w, r, CodeInvalidFormatOptions, "Invalid format options", err)
+			return
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		writeError(w, r, CodeInvalidFormatOptions, "Invalid format options", err)
+		return
+	}
+	preserveTypes := false
+	if value := r.FormValue("preserve_types"); value != "" {
+		if preserveTypes, err = strconv.ParseBool(value); err != nil {
+			writeError(w, r, CodeInvalidParameter, "Invalid preserve_types", err)
+			return
+		}
+	}
+	parallel := false
+	if value := r.FormValue("parallel"); value != "" {
+		if parallel, err = strconv.ParseBool(value); err != nil {
+			writeError(w, r, CodeInvalidParameter, "Invalid parallel", err)
+			return
+		}
+	}
+	maxWorkers := 0
+	if value := r.FormValue("max_workers"); value != "" {
+		if maxWorkers, err = strconv.Atoi(value); err != nil || maxWorkers < 0 {
+			writeError(w, r, CodeInvalidParameter, "Invalid max_workers", fmt.Errorf("max_workers: %q", value))
+			return
+		}
+	}
+
+	pipelineName := r.FormValue("pipeline")
+	actions, err := resolveActions(requestTenant(r), pipelineName, r.MultipartForm.Value["actions"])
+	if err != nil {
+		writePipelineError(w, r, err)
+		return
+	}
+
+	// The readers take a path, so the upload is copied to a temporary file first and hashed on the
+	// way for the result cache and the audit log
+	tmp, err := os.CreateTemp("", "cleango-upload-*"+formatExtensions[format])
+	if err != nil {
+		writeError(w, r, CodeInternal, "Uploaded file could not be stored", err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, hash), file)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		writeError(w, r, CodeInternal, "Uploaded file could not be stored", err)
+		return
+	}
+	input := hash.Sum(nil)
+	record := AuditRecord{Pipeline: pipelineName, Actions: actions, InputPath: header.Filename, InputFingerprint: hex.EncodeToString(input)}
+
+	// An identical upload cleaned with the same actions and options returns the cached response.
+	// With provenance the records hold the path of the temporary file, so they are not cached.
+	var key string
+	if results != nil && !opts.Provenance {
+		key = cacheKey("upload", input, actions, map[string]interface{}{
+			"format":         format,
+			"format_options": opts,
+			"preserve_types": preserveTypes,
+			"language":       requestLanguage(r),
+		})
+		if cached, ok := cacheGet(r, key); ok {
+			writeCacheHit(w, r, cached, record)
+			return
+		}
+	}
+
+	workers := budget.workersFor(parallel, maxWorkers)
+	release, err := budget.Acquire(r.Context(), workers)
+	if err != nil {
+		writeError(w, r, classifyError(err, CodeServerBusy), "Server worker budget exhausted", err)
+		return
+	}
+	defer release()
+
+	span := startIOSpan(r.Context(), "read", format)
+	df, err := readDataFrame(tmp.Name(), format, opts)
+	endIOSpan(span, df, err)
+	if err != nil {
+		writeError(w, r, classifyError(err, CodeReadFailed), "File could not be read", err)
+		return
+	}
+	metrics.rows(r, len(df.Data))
+
+	parallelOptions := []func(*cleaner.ParallelOptions){
+		cleaner.WithContext(r.Context()),
+		cleaner.WithMaxWorkers(workers),
+		cleaner.WithObserver(operationObserver),
+	}
+	df, err = applyActions(df, actions, parallel, false, parallelOptions)
+	if err != nil {
+		writeError(w, r, classifyError(err, CodeInvalidAction), "Action could not be applied", err)
+		return
+	}
+
+	if preserveTypes {
+		// Files carry no JSON types, so they are inferred from the cleaned values
+		df.InferTypes()
+	}
+	resp := CleanResponse{
+		Data:       dataFrameToMaps(df, preserveTypes),
+		Statistics: newStatistics(df),
+		Message:    i18n.T(requestLanguage(r), "Data cleaned successfully"),
+	}
+	body := writeCachedJSON(w, r, key, resp)
+	record.OutputFingerprint = fingerprint(body)
+	record.RowsIn, record.RowsOut = resp.Statistics.Rows+resp.Statistics.RowsDropped, resp.Statistics.Rows
+	audit(r, record)
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const peopleXML = `<?xml version="1.0" encoding="UTF-8"?>
+<people>
+  <person><name>  Alice  </name><city>istanbul</city></person>
+  <person><name>Bob</name><city>ankara</city></person>
+</people>`
+
+func TestHandleConvert_XMLToYAML(t *testing.T) {
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "people.xml")
+	if err := os.WriteFile(input, []byte(peopleXML), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	payload := ConvertRequest{
+		FilePath:      input,
+		Format:        "yaml",
+		FormatOptions: FormatOptions{RootElement: "people", ItemElement: "person"},
+	}
+	body, _ := json.Marshal(payload)
+	w := httptest.NewRecorder()
+	handleConvert(w, httptest.NewRequest(http.MethodPost, "/convert", bytes.NewBuffer(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Output string `json:"output"`
+		Rows   int    `json:"rows"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	// Without output, the file is written next to the input with the extension of the format
+	if resp.Output != filepath.Join(dir, "people.yaml") || resp.Rows != 2 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	content, err := os.ReadFile(resp.Output)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(content), "name: Alice") || !strings.Contains(string(content), "city: istanbul") {
+		t.Errorf("unexpected output:\n%s", content)
+	}
+}
+
+func TestHandleConvert_Workspace(t *testing.T) {
+	ws, _ := newWorkspace(t.TempDir(), time.Hour, 0)
+	withWorkspace(t, ws)
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "people.csv")
+	os.WriteFile(input, []byte("name\nAlice\n"), 0644)
+
+	body := `{"file_path":"` + input + `","output":"out/people.json"}`
+	w := httptest.NewRecorder()
+	handleConvert(w, httptest.NewRequest(http.MethodPost, "/convert", bytes.NewBufferString(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Output string `json:"output"`
+		JobID  string `json:"job_id"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.JobID == "" || resp.Output != filepath.Join(ws.dir, resp.JobID, "people.json") {
+		t.Fatalf("expected the output in a job directory, got %+v", resp)
+	}
+	if _, err := os.Stat(resp.Output); err != nil {
+		t.Errorf("output not written: %v", err)
+	}
+}
+
+func TestHandleConvert_Errors(t *testing.T) {
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "people.csv")
+	os.WriteFile(input, []byte("name\nAlice\n"), 0644)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   ErrorCode
+	}{
+		{"missing file path", `{"format":"json"}`, http.StatusBadRequest, CodeMissingFilePath},
+		{"outside work dir", `{"file_path":"/etc/passwd.csv","format":"json"}`, http.StatusForbidden, CodeForbiddenPath},
+		{"missing output format", `{"file_path":"` + input + `"}`, http.StatusBadRequest, CodeUnsupportedFormat},
+		{"same file", `{"file_path":"` + input + `","format":"csv"}`, http.StatusBadRequest, CodeInvalidParameter},
+		{"missing input", `{"file_path":"` + filepath.Join(dir, "missing.csv") + `","format":"json"}`, http.StatusNotFound, CodeFileNotFound},
+		{"output outside work dir", `{"file_path":"` + input + `","output":"/tmp/people.json"}`, http.StatusForbidden, CodeForbiddenPath},
+		{"output traversal", `{"file_path":"` + input + `","output":"../people.json"}`, http.StatusForbidden, CodeForbiddenPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handleConvert(w, httptest.NewRequest(http.MethodPost, "/convert", bytes.NewBufferString(tt.body)))
+			var resp ErrorResponse
+			json.NewDecoder(w.Body).Decode(&resp)
+			if w.Code != tt.status || resp.Code != tt.code {
+				t.Errorf("expected %d %s, got %d %s", tt.status, tt.code, w.Code, resp.Code)
+			}
+		})
+	}
+}
+
+// uploadRequest returns a multipart request of the file and form fields
+func uploadRequest(t *testing.T, filename, content string, fields map[string][]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if filename != "" {
+		part, err := mw.CreateFormFile("file", filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(content))
+	}
+	for name, values := range fields {
+		for _, value := range values {
+			mw.WriteField(name, value)
+		}
+	}
+	mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestHandleUpload_XML(t *testing.T) {
+	req := uploadRequest(t, "people.xml", peopleXML, map[string][]string{
+		"actions":        {"trim", "normalize_case:city=upper"},
+		"format_options": {`{"root_element":"people","item_element":"person"}`},
+	})
+	w := httptest.NewRecorder()
+	handleUpload(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp CleanResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	want := []map[string]interface{}{{"name": "Alice", "city": "ISTANBUL"}, {"name": "Bob", "city": "ANKARA"}}
+	if !reflect.DeepEqual(resp.Data, want) {
+		t.Errorf("expected %v, got %v", want, resp.Data)
+	}
+	if resp.Statistics.Rows != 2 {
+		t.Errorf("expected 2 rows, got %d", resp.Statistics.Rows)
+	}
+}
+
+func TestHandleUpload_YAMLWithFormatField(t *testing.T) {
+	// The format field wins over the extension of the file name
+	req := uploadRequest(t, "people.txt", "- age: 30\n- age: 41\n", map[string][]string{
+		"format":         {"yaml"},
+		"preserve_types": {"true"},
+	})
+	w := httptest.NewRecorder()
+	handleUpload(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp CleanResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Data) != 2 || resp.Data[0]["age"] != float64(30) {
+		t.Errorf("expected numeric ages, got %v", resp.Data)
+	}
+}
+
+func TestHandleUpload_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *http.Request
+		code ErrorCode
+	}{
+		{"not multipart", httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(`{}`)), CodeMissingFile},
+		{"missing file", uploadRequest(t, "", "", map[string][]string{"actions": {"trim"}}), CodeMissingFile},
+		{"unsupported format", uploadRequest(t, "people.txt", "name\n", nil), CodeUnsupportedFormat},
+		{"invalid format options", uploadRequest(t, "people.csv", "name\n", map[string][]string{"format_options": {`{"delimiter":";;"}`}}), CodeInvalidFormatOptions},
+		{"invalid action", uploadRequest(t, "people.csv", "name\nAlice\n", map[string][]string{"actions": {"clean_regex:missing=a=b"}}), CodeColumnNotFound},
+		{"invalid parallel", uploadRequest(t, "people.csv", "name\n", map[string][]string{"parallel": {"sometimes"}}), CodeInvalidParameter},
+		{"invalid max workers", uploadRequest(t, "people.csv", "name\n", map[string][]string{"max_workers": {"-1"}}), CodeInvalidParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handleUpload(w, tt.req)
+			var resp ErrorResponse
+			json.NewDecoder(w.Body).Decode(&resp)
+			if resp.Code != tt.code || w.Code != tt.code.Status() {
+				t.Errorf("expected %s, got %d %s: %s", tt.code, w.Code, resp.Code, resp.Details)
+			}
+		})
+	}
+}
+
+func TestHandleUpload_TooLarge(t *testing.T) {
+	saved := maxUploadSize
+	maxUploadSize = 1024
+	t.Cleanup(func() { maxUploadSize = saved })
+
+	req := uploadRequest(t, "people.csv", "name\n"+strings.Repeat("Alice\n", 1000), nil)
+	w := httptest.NewRecorder()
+	handleUpload(w, req)
+	var resp ErrorResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != http.StatusRequestEntityTooLarge || resp.Code != CodeUploadTooLarge {
+		t.Errorf("expected 413 %s, got %d %s", CodeUploadTooLarge, w.Code, resp.Code)
+	}
+}
+
+func TestHandleUpload_ParallelWorkers(t *testing.T) {
+	saved := budget
+	budget = newWorkerBudget(4, time.Second)
+	t.Cleanup(func() { budget = saved })
+
+	// max_workers above the budget is capped like on /clean, so the request still runs
+	req := uploadRequest(t, "people.csv", "name\n  Alice  \n", map[string][]string{
+		"actions":     {"trim"},
+		"parallel":    {"true"},
+		"max_workers": {"16"},
+	})
+	w := httptest.NewRecorder()
+	handleUpload(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp CleanResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Data) != 1 || resp.Data[0]["name"] != "Alice" {
+		t.Errorf("unexpected data %v", resp.Data)
+	}
+}
+
+func TestHandleUpload_CacheAndAudit(t *testing.T) {
+	cache, _ := newDiskCache(t.TempDir(), time.Hour)
+	withResultCache(t, cache)
+	store := withAuditLog(t)
+
+	content := "name\n  Alice  \n"
+	do := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handleUpload(w, uploadRequest(t, "people.csv", content, map[string][]string{"actions": {"trim"}}))
+		return w
+	}
+
+	first := do()
+	if first.Code != http.StatusOK || first.Header().Get(cacheHeader) != "MISS" {
+		t.Fatalf("expected a 200 miss, got %d %q: %s", first.Code, first.Header().Get(cacheHeader), first.Body.String())
+	}
+	second := do()
+	if second.Code != http.StatusOK || second.Header().Get(cacheHeader) != "HIT" {
+		t.Fatalf("expected a 200 hit, got %d %q: %s", second.Code, second.Header().Get(cacheHeader), second.Body.String())
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Errorf("cached response differs:\n%s\n%s", first.Body.String(), second.Body.String())
+	}
+
+	records, _ := store.List(context.Background(), auditFilter{})
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %+v", records)
+	}
+	for _, record := range records {
+		if record.InputPath != "people.csv" || record.InputFingerprint != fingerprint([]byte(content)) || record.RowsOut != 1 {
+			t.Errorf("unexpected record: %+v", record)
+		}
+		if record.OutputFingerprint != fingerprint(first.Body.Bytes()) {
+			t.Errorf("output fingerprint does not match the response: %+v", record)
+		}
+	}
+	// Records are listed newest first
+	if !records[0].Cached || records[1].Cached {
+		t.Errorf("expected only the second upload to be served from the cache: %+v", records)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+)
+
+// dryRunRowColumn, column added to the data of a dry run to find the input row of every output row
+const dryRunRowColumn = "_cleango_dry_run_row"
+
+// isDryRunRowColumn reports whether the header is the row column, possibly renamed by normalize_headers
+func isDryRunRowColumn(header string) bool {
+	letters := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, header)
+	return strings.EqualFold(letters, "cleangodryrunrow")
+}
+
+// dryRunSampleSize, changed rows returned by a dry run
+const dryRunSampleSize = 10
+
+// RowChange, a row changed by the actions: the values before and after, by column
+type RowChange struct {
+	Row    int               `json:"row"` // Index of the input row
+	Before map[string]string `json:"before"`
+	After  map[string]string `json:"after"`
+}
+
+// DryRunResponse, result of a request with dry_run: what the actions would do, without the data
+type DryRunResponse struct {
+	Statistics  Statistics  `json:"statistics"`
+	ChangedRows int         `json:"changed_rows"` // Kept rows with at least one changed value
+	Changes     []RowChange `json:"changes"`      // The first changed rows
+	Message     string      `json:"message"`
+}
+
+// dryRun applies the actions to a copy of df and reports the statistics and the first changed rows.
+// df is not modified. A row changes when one of its values or the column names change; dropped
+// rows only count in the statistics.
+func dryRun(df *cleaner.DataFrame, actions []string, parallel bool, parallelOptions []func(*cleaner.ParallelOptions)) (*DryRunResponse, error) {
+	data := make([][]string, len(df.Data))
+	for i, row := range df.Data {
+		data[i] = append(slices.Clone(row), strconv.Itoa(i))
+	}
+	indexed, err := cleaner.NewDataFrame(append(slices.Clone(df.Headers), dryRunRowColumn), data)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(indexed.Types, df.Types)
+
+	result, err := applyActions(indexed, actions, parallel, false, parallelOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := newStatistics(result)
+	rowColumn := slices.IndexFunc(result.Headers, isDryRunRowColumn)
+	if rowColumn >= 0 {
+		stats.Columns--
+		delete(stats.ColumnStats, result.Headers[rowColumn])
+		delete(stats.Types, result.Headers[rowColumn])
+	}
+
+	resp := &DryRunResponse{Statistics: stats, Changes: []RowChange{}}
+	for j, row := range result.Data {
+		// Without the row column (dropped by an action), rows are paired by position
+		i := j
+		if rowColumn >= 0 {
+			i, _ = strconv.Atoi(row[rowColumn])
+		}
+		if i >= len(df.Data) {
+			break
+		}
+
+		before := make(map[string]string, len(df.Headers))
+		for k, header := range df.Headers {
+			before[header] = df.Data[i][k]
+		}
+		after := make(map[string]string, len(result.Headers))
+		for k, header := range result.Headers {
+			if k != rowColumn {
+				after[header] = row[k]
+			}
+		}
+		if maps.Equal(before, after) {
+			continue
+		}
+		resp.ChangedRows++
+		if len(resp.Changes) < dryRunSampleSize {
+			resp.Changes = append(resp.Changes, RowChange{Row: i, Before: before, After: after})
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+)
+
+func TestDryRun(t *testing.T) {
+	df, _ := cleaner.NewDataFrame([]string{"name", "age"}, [][]string{
+		{"  Ali  ", "30"},
+		{"Veli", "31"},
+		{" Ayşe", "1000"},
+		{"Can", "32"},
+		{"Ece ", "33"},
+	})
+	df.Types["age"] = cleaner.TypeInt
+
+	resp, err := dryRun(df, []string{"trim", "filter_outliers:age=0=100"}, false, nil)
+	if err != nil {
+		t.Fatalf("dryRun error: %v", err)
+	}
+	if resp.Statistics.Columns != 2 || resp.Statistics.RowsDropped != 1 {
+		t.Errorf("unexpected statistics: %+v", resp.Statistics)
+	}
+	if _, ok := resp.Statistics.ColumnStats[dryRunRowColumn]; ok {
+		t.Error("expected no statistics for the row column")
+	}
+	// The dropped row is not a change; rows after it keep their input index
+	if resp.ChangedRows != 2 || len(resp.Changes) != 2 {
+		t.Fatalf("expected 2 changed rows, got %+v", resp)
+	}
+	change := resp.Changes[0]
+	if change.Row != 0 || change.Before["name"] != "  Ali  " || change.After["name"] != "Ali" {
+		t.Errorf("unexpected change: %+v", change)
+	}
+	if resp.Changes[1].Row != 4 || resp.Changes[1].After["name"] != "Ece" {
+		t.Errorf("unexpected change: %+v", resp.Changes[1])
+	}
+	if df.Data[0][0] != "  Ali  " || len(df.Headers) != 2 {
+		t.Error("expected the input not to be modified")
+	}
+}
+
+func TestDryRun_RenamedHeaders(t *testing.T) {
+	df, _ := cleaner.NewDataFrame([]string{"First Name"}, [][]string{{"Ali"}})
+	resp, err := dryRun(df, []string{"normalize_headers"}, false, nil)
+	if err != nil {
+		t.Fatalf("dryRun error: %v", err)
+	}
+	if resp.Statistics.Columns != 1 || len(resp.Changes) != 1 || len(resp.Changes[0].After) != 1 || resp.Changes[0].After["first_name"] != "Ali" {
+		t.Errorf("expected the renamed column without the row column, got %+v", resp)
+	}
+}
+
+func TestHandleClean_DryRun(t *testing.T) {
+	body := `{"data":[{"name":"  Ali  "},{"name":"Veli"}],"actions":["trim"],"dry_run":true}`
+	w := httptest.NewRecorder()
+	handleClean(w, httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp map[string]json.RawMessage
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if _, ok := resp["data"]; ok {
+		t.Error("expected no data in a dry run")
+	}
+	if string(resp["changed_rows"]) != "1" {
+		t.Errorf("expected 1 changed row, got %s", resp["changed_rows"])
+	}
+}
+
+func TestHandleCleanFile_DryRun(t *testing.T) {
+	ws, _ := newWorkspace(t.TempDir(), time.Hour, 0)
+	withWorkspace(t, ws)
+
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "in.csv")
+	if err := os.WriteFile(input, []byte("name\n  Ali  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(FileCleanRequest{FilePath: input, Actions: []string{"trim"}, Output: filepath.Join(dir, "out.csv"), DryRun: true})
+	w := httptest.NewRecorder()
+	handleCleanFile(w, httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBuffer(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp DryRunResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.ChangedRows != 1 || resp.Changes[0].After["name"] != "Ali" {
+		t.Errorf("unexpected dry run: %+v", resp)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out.csv")); !os.IsNotExist(err) {
+		t.Error("expected no output file")
+	}
+	if entries, _ := os.ReadDir(ws.dir); len(entries) != 0 {
+		t.Errorf("expected no job, found %d entries", len(entries))
+	}
+}
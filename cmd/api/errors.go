@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+// ErrorCode, machine-readable error identifier returned in error responses
+type ErrorCode string
+
+// Error code catalog. Every error response carries exactly one of these codes.
+const (
+	CodeMethodNotAllowed     ErrorCode = "method_not_allowed"     // HTTP method is not supported by the endpoint
+	CodeInvalidJSON          ErrorCode = "invalid_json"           // Request body is not valid JSON
+	CodeEmptyData            ErrorCode = "empty_data"             // Request contains no records
+	CodeInvalidData          ErrorCode = "invalid_data"           // Records cannot be converted into a DataFrame
+	CodeMissingFilePath      ErrorCode = "missing_file_path"      // file_path was not specified
+	CodeMissingFile          ErrorCode = "missing_file"           // Upload has no file part
+	CodeUploadTooLarge       ErrorCode = "upload_too_large"       // Upload is larger than MAX_UPLOAD_SIZE
+	CodeInvalidFilePath      ErrorCode = "invalid_file_path"      // file_path cannot be resolved
+	CodeForbiddenPath        ErrorCode = "forbidden_path"         // file_path or an output is outside the allowed directory
+	CodeUnsupportedFormat    ErrorCode = "unsupported_format"     // Input or output format is not supported
+	CodeInvalidFormatOptions ErrorCode = "invalid_format_options" // format_options contains an invalid value
+	CodeFileNotFound         ErrorCode = "file_not_found"         // Input file does not exist
+	CodeReadFailed           ErrorCode = "read_failed"            // Input file could not be read or parsed
+	CodeWriteFailed          ErrorCode = "write_failed"           // Output file could not be written
+	CodeInvalidAction        ErrorCode = "invalid_action"         // An action is malformed or has invalid parameters
+	CodeColumnNotFound       ErrorCode = "column_not_found"       // An action references an unknown column
+	CodeRequestCancelled     ErrorCode = "request_cancelled"      // Client cancelled the request
+	CodeTimeout              ErrorCode = "timeout"                // Processing exceeded the deadline
+	CodeServerBusy           ErrorCode = "server_busy"            // Worker budget is exhausted, retry later
+	CodeInvalidPipeline      ErrorCode = "invalid_pipeline"       // Pipeline definition is malformed
+	CodePipelineNotFound     ErrorCode = "pipeline_not_found"     // Referenced pipeline does not exist
+	CodePipelineExists       ErrorCode = "pipeline_exists"        // A pipeline with the same name already exists
+	CodeInvalidParameter     ErrorCode = "invalid_parameter"      // A parameter or request field has an invalid value
+	CodeJobNotFound          ErrorCode = "job_not_found"          // Job does not exist or was already purged
+	CodeQuotaExceeded        ErrorCode = "quota_exceeded"         // Output is larger than the workspace quota
+	CodeUnauthorized         ErrorCode = "unauthorized"           // API key is missing or unknown
+	CodeInvalidTenant        ErrorCode = "invalid_tenant"         // X-Tenant-ID is not a valid tenant name
+	CodeAuditDisabled        ErrorCode = "audit_disabled"         // Audit log is not enabled on the server
+	CodeInternal             ErrorCode = "internal_error"         // Unexpected server error
+)
+
+// errorStatus, HTTP status for each error code
+var errorStatus = map[ErrorCode]int{
+	CodeMethodNotAllowed:     http.StatusMethodNotAllowed,
+	CodeInvalidJSON:          http.StatusBadRequest,
+	CodeEmptyData:            http.StatusBadRequest,
+	CodeInvalidData:          http.StatusUnprocessableEntity,
+	CodeMissingFilePath:      http.StatusBadRequest,
+	CodeMissingFile:          http.StatusBadRequest,
+	CodeUploadTooLarge:       http.StatusRequestEntityTooLarge,
+	CodeInvalidFilePath:      http.StatusBadRequest,
+	CodeForbiddenPath:        http.StatusForbidden,
+	CodeUnsupportedFormat:    http.StatusBadRequest,
+	CodeInvalidFormatOptions: http.StatusBadRequest,
+	CodeFileNotFound:         http.StatusNotFound,
+	CodeReadFailed:           http.StatusUnprocessableEntity,
+	CodeWriteFailed:          http.StatusInternalServerError,
+	CodeInvalidAction:        http.StatusBadRequest,
+	CodeColumnNotFound:       http.StatusUnprocessableEntity,
+	CodeRequestCancelled:     499,
+	CodeTimeout:              http.StatusGatewayTimeout,
+	CodeServerBusy:           http.StatusServiceUnavailable,
+	CodeInvalidPipeline:      http.StatusBadRequest,
+	CodePipelineNotFound:     http.StatusNotFound,
+	CodePipelineExists:       http.StatusConflict,
+	CodeInvalidParameter:     http.StatusBadRequest,
+	CodeJobNotFound:          http.StatusNotFound,
+	CodeQuotaExceeded:        http.StatusInsufficientStorage,
+	CodeUnauthorized:         http.StatusUnauthorized,
+	CodeInvalidTenant:        http.StatusBadRequest,
+	CodeAuditDisabled:        http.StatusNotFound,
+	CodeInternal:             http.StatusInternalServerError,
+}
+
+// Status returns the HTTP status matching the error code
+func (c ErrorCode) Status() int {
+	if status, ok := errorStatus[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorResponse, JSON error envelope returned by every endpoint
+type ErrorResponse struct {
+	Code      ErrorCode `json:"code"`
+	Message   string    `json:"message"`
+	Details   string    `json:"details,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+}
+
+// requestIDHeader, header used to receive and echo request identifiers
+const requestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// withRequestID assigns a request ID to every request, reusing the client's X-Request-ID when present
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// requestID returns the ID assigned to the request
+func requestID(r *http.Request) string {
+	if id, ok := r.Context().Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	return r.Header.Get(requestIDHeader)
+}
+
+// newRequestID generates a random 16 byte hex identifier
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// language of the log output and of responses to requests without a supported Accept-Language
+var language = i18n.FromEnv()
+
+// requestLanguage returns the language of the response: the first supported language of the
+// Accept-Language header, or the server language
+func requestLanguage(r *http.Request) i18n.Language {
+	return i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"), language)
+}
+
+// withLanguage reports the language of every response in the Content-Language header
+func withLanguage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Language", string(requestLanguage(r)))
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeError writes the JSON error envelope with the status matching the code. The message and
+// details are translated to the request language; the code is not.
+func writeError(w http.ResponseWriter, r *http.Request, code ErrorCode, message string, err error) {
+	lang := requestLanguage(r)
+	resp := ErrorResponse{
+		Code:      code,
+		Message:   i18n.T(lang, message),
+		RequestID: requestID(r),
+	}
+	if err != nil {
+		resp.Details = i18n.Error(lang, err)
+	}
+	writeJSON(w, code.Status(), resp)
+}
+
+// classifyError maps errors returned by the cleaner and formats packages to error codes
+func classifyError(err error, fallback ErrorCode) ErrorCode {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	case errors.Is(err, context.Canceled):
+		return CodeRequestCancelled
+	case errors.Is(err, cleaner.ErrColumnNotFound):
+		return CodeColumnNotFound
+	case errors.Is(err, os.ErrNotExist):
+		return CodeFileNotFound
+	default:
+		return fallback
+	}
+}
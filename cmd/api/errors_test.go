@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+)
+
+func TestErrorEnvelope_InvalidJSON(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString("not json"))
+	req.Header.Set(requestIDHeader, "req-123")
+	w := httptest.NewRecorder()
+
+	withRequestID(http.HandlerFunc(handleClean)).ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json content type, got %q", ct)
+	}
+	if got := w.Header().Get(requestIDHeader); got != "req-123" {
+		t.Errorf("expected request ID header to be echoed, got %q", got)
+	}
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if resp.Code != CodeInvalidJSON {
+		t.Errorf("expected code %q, got %q", CodeInvalidJSON, resp.Code)
+	}
+	if resp.Message == "" || resp.Details == "" {
+		t.Errorf("expected message and details, got %+v", resp)
+	}
+	if resp.RequestID != "req-123" {
+		t.Errorf("expected request_id=req-123, got %q", resp.RequestID)
+	}
+}
+
+func TestErrorEnvelope_GeneratedRequestID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/clean", nil)
+	w := httptest.NewRecorder()
+
+	withRequestID(http.HandlerFunc(handleClean)).ServeHTTP(w, req)
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if resp.Code != CodeMethodNotAllowed {
+		t.Errorf("expected code %q, got %q", CodeMethodNotAllowed, resp.Code)
+	}
+	if resp.RequestID == "" || resp.RequestID != w.Header().Get(requestIDHeader) {
+		t.Errorf("expected generated request ID in body and header, got %q / %q", resp.RequestID, w.Header().Get(requestIDHeader))
+	}
+}
+
+func TestErrorEnvelope_ColumnNotFound(t *testing.T) {
+	body := `{"data":[{"name":"Alice"}],"actions":["clean_regex:missing=[0-9]="]}`
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handleClean(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if resp.Code != CodeColumnNotFound {
+		t.Errorf("expected code %q, got %q", CodeColumnNotFound, resp.Code)
+	}
+}
+
+func TestErrorEnvelope_Localized(t *testing.T) {
+	body := `{"data":[{"name":"Alice"}],"actions":["clean_regex:missing=[0-9]="]}`
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body))
+	req.Header.Set("Accept-Language", "tr-TR,tr;q=0.9,en;q=0.8")
+	w := httptest.NewRecorder()
+
+	withLanguage(http.HandlerFunc(handleClean)).ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Language"); got != "tr" {
+		t.Errorf("expected Content-Language tr, got %q", got)
+	}
+	var resp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if resp.Code != CodeColumnNotFound {
+		t.Errorf("expected the code to stay untranslated, got %q", resp.Code)
+	}
+	if resp.Message != "İşlem uygulanamadı" {
+		t.Errorf("expected a Turkish message, got %q", resp.Message)
+	}
+	if !strings.Contains(resp.Details, "sütun bulunamadı") {
+		t.Errorf("expected Turkish details, got %q", resp.Details)
+	}
+}
+
+func TestErrorCodeStatus(t *testing.T) {
+	for code, status := range errorStatus {
+		if code.Status() != status {
+			t.Errorf("%s.Status() = %d, want %d", code, code.Status(), status)
+		}
+	}
+	if ErrorCode("unknown").Status() != http.StatusInternalServerError {
+		t.Error("unknown codes should map to 500")
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err    error
+		expect ErrorCode
+	}{
+		{context.DeadlineExceeded, CodeTimeout},
+		{context.Canceled, CodeRequestCancelled},
+		{fmt.Errorf("%w: age", cleaner.ErrColumnNotFound), CodeColumnNotFound},
+		{fmt.Errorf("open: %w", os.ErrNotExist), CodeFileNotFound},
+		{fmt.Errorf("boom"), CodeInternal},
+	}
+
+	for _, tt := range tests {
+		if got := classifyError(tt.err, CodeInternal); got != tt.expect {
+			t.Errorf("classifyError(%v) = %q, want %q", tt.err, got, tt.expect)
+		}
+	}
+}
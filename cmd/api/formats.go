@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+	"github.com/mstgnz/cleango/pkg/formats"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+// FormatOptions, format specific reading and writing options
+type FormatOptions struct {
+	Delimiter   string `json:"delimiter,omitempty"`    // CSV delimiter character (default: ,)
+	SheetName   string `json:"sheet_name,omitempty"`   // Excel worksheet name (default: Sheet1)
+	Compression string `json:"compression,omitempty"`  // Parquet compression: snappy, gzip, lz4, zstd, uncompressed (default: snappy)
+	RootElement string `json:"root_element,omitempty"` // XML root element name (default: root)
+	ItemElement string `json:"item_element,omitempty"` // XML item element name (default: item)
+	Pretty      bool   `json:"pretty,omitempty"`       // Indent JSON, XML and YAML output
+	// StringColumns are kept as text by every writer, e.g. identifiers with leading zeros
+	StringColumns []string `json:"string_columns,omitempty"`
+	// Provenance adds the _source_file and _source_row columns when reading the input file
+	Provenance bool `json:"provenance,omitempty"`
+}
+
+// parquetCodecs, Parquet compression codecs by name
+var parquetCodecs = map[string]parquet.CompressionCodec{
+	"snappy":       parquet.CompressionCodec_SNAPPY,
+	"gzip":         parquet.CompressionCodec_GZIP,
+	"lz4":          parquet.CompressionCodec_LZ4,
+	"zstd":         parquet.CompressionCodec_ZSTD,
+	"uncompressed": parquet.CompressionCodec_UNCOMPRESSED,
+}
+
+// Validate checks the options before any file is touched
+func (o FormatOptions) Validate() error {
+	if o.Delimiter != "" && utf8.RuneCountInString(o.Delimiter) != 1 {
+		return fmt.Errorf("delimiter must be a single character: %q", o.Delimiter)
+	}
+	if o.Compression != "" {
+		if _, ok := parquetCodecs[strings.ToLower(o.Compression)]; !ok {
+			return fmt.Errorf("unsupported parquet compression: %s", o.Compression)
+		}
+	}
+	return nil
+}
+
+// csvOptions returns the CSV options for the request
+func (o FormatOptions) csvOptions() []formats.CSVOption {
+	var opts []formats.CSVOption
+	if o.Delimiter != "" {
+		r, _ := utf8.DecodeRuneInString(o.Delimiter)
+		opts = append(opts, formats.WithDelimiter(r))
+	}
+	if o.Provenance {
+		opts = append(opts, formats.WithProvenance(true))
+	}
+	return opts
+}
+
+// excelOptions returns the Excel options for the request
+func (o FormatOptions) excelOptions() []formats.ExcelOption {
+	var opts []formats.ExcelOption
+	if o.SheetName != "" {
+		opts = append(opts, formats.WithSheetName(o.SheetName))
+	}
+	if o.Provenance {
+		opts = append(opts, formats.WithExcelProvenance(true))
+	}
+	return opts
+}
+
+// parquetOptions returns the Parquet options for the request
+func (o FormatOptions) parquetOptions() []formats.ParquetOption {
+	var opts []formats.ParquetOption
+	if codec, ok := parquetCodecs[strings.ToLower(o.Compression)]; ok {
+		opts = append(opts, formats.WithCompression(codec))
+	}
+	return opts
+}
+
+// xmlOptions returns the XML options for the request
+func (o FormatOptions) xmlOptions() []formats.XMLOption {
+	var opts []formats.XMLOption
+	if o.RootElement != "" {
+		opts = append(opts, formats.WithXMLRootElement(o.RootElement))
+	}
+	if o.ItemElement != "" {
+		opts = append(opts, formats.WithXMLItemElement(o.ItemElement))
+	}
+	if o.Pretty {
+		opts = append(opts, formats.WithXMLPretty(true))
+	}
+	if o.Provenance {
+		opts = append(opts, formats.WithXMLProvenance(true))
+	}
+	return opts
+}
+
+// readDataFrame reads the file in the given format
+func readDataFrame(filePath, format string, opts FormatOptions) (*cleaner.DataFrame, error) {
+	df, err := readFormat(filePath, format, opts)
+	if err != nil {
+		return nil, err
+	}
+	return df.WithStringColumns(opts.StringColumns), nil
+}
+
+// readFormat reads the file in the given format
+func readFormat(filePath, format string, opts FormatOptions) (*cleaner.DataFrame, error) {
+	switch format {
+	case "csv":
+		return cleaner.ReadCSV(filePath, opts.csvOptions()...)
+	case "json":
+		return cleaner.ReadJSON(filePath, formats.WithJSONProvenance(opts.Provenance))
+	case "excel":
+		return cleaner.ReadExcel(filePath, opts.excelOptions()...)
+	case "parquet":
+		return cleaner.ReadParquet(filePath, formats.WithParquetProvenance(opts.Provenance))
+	case "xml":
+		return cleaner.ReadXML(filePath, opts.xmlOptions()...)
+	case "yaml":
+		return cleaner.ReadYAML(filePath, formats.WithYAMLProvenance(opts.Provenance))
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// writeDataFrame writes the DataFrame to the file in the given format
+func writeDataFrame(df *cleaner.DataFrame, filePath, format string, opts FormatOptions) error {
+	switch format {
+	case "csv":
+		return df.WriteCSV(filePath, opts.csvOptions()...)
+	case "json":
+		return df.WriteJSON(filePath, formats.WithPretty(opts.Pretty))
+	case "excel":
+		return df.WriteExcel(filePath, opts.excelOptions()...)
+	case "parquet":
+		return df.WriteParquet(filePath, opts.parquetOptions()...)
+	case "xml":
+		return df.WriteXML(filePath, opts.xmlOptions()...)
+	case "yaml":
+		return df.WriteYAML(filePath, formats.WithYAMLPretty(opts.Pretty))
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// writeFileContent writes the content of an output file, e.g. restored from the result cache,
+// atomically and with the overwrite and fsync defaults writeDataFrame writes with
+func writeFileContent(filePath string, content []byte) error {
+	return formats.WriteFile(filePath, content, false, true)
+}
+
+// getFileFormat returns the format name for the file extension, or "" when unsupported
+func getFileFormat(filePath string) string {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	switch ext {
+	case ".csv":
+		return "csv"
+	case ".json":
+		return "json"
+	case ".xlsx", ".xls":
+		return "excel"
+	case ".parquet":
+		return "parquet"
+	case ".xml":
+		return "xml"
+	case ".yaml", ".yml":
+		return "yaml"
+	default:
+		return ""
+	}
+}
+
+// isSupportedFormat reports whether the format name can be read and written by the API
+func isSupportedFormat(format string) bool {
+	switch format {
+	case "csv", "json", "excel", "parquet", "xml", "yaml":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/formats"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xuri/excelize/v2"
+)
+
+// tempWorkDir creates a temporary directory inside the working directory, where /clean-file may read
+func tempWorkDir(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp(".", "testdata-")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestHandleCleanFile_XMLToYAML(t *testing.T) {
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "people.xml")
+	output := filepath.Join(dir, "people.yaml")
+
+	xmlData := `<?xml version="1.0" encoding="UTF-8"?>
+<people>
+  <person><name>  Alice  </name><city>istanbul</city></person>
+  <person><name>Bob</name><city>ankara</city></person>
+</people>`
+	if err := os.WriteFile(input, []byte(xmlData), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	payload := FileCleanRequest{
+		FilePath:      input,
+		Actions:       []string{"trim", "normalize_case:city=upper"},
+		Output:        output,
+		FormatOptions: FormatOptions{RootElement: "people", ItemElement: "person"},
+	}
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	handleCleanFile(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(content), "ISTANBUL") || !strings.Contains(string(content), "name: Alice") {
+		t.Errorf("unexpected YAML output:\n%s", content)
+	}
+}
+
+func TestHandleCleanFile_YAMLToXML(t *testing.T) {
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "people.yml")
+	output := filepath.Join(dir, "people.xml")
+
+	if err := os.WriteFile(input, []byte("- name: Alice\n  city: istanbul\n"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	payload := FileCleanRequest{
+		FilePath:      input,
+		Output:        output,
+		Format:        "xml",
+		FormatOptions: FormatOptions{RootElement: "people", ItemElement: "person"},
+	}
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	handleCleanFile(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(content), "<people><person>") {
+		t.Errorf("expected custom root/item elements, got:\n%s", content)
+	}
+}
+
+func TestHandleCleanFile_UnsupportedOutputFormat(t *testing.T) {
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(input, []byte("name\nAlice\n"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	body := `{"file_path":"` + input + `","format":"toml"}`
+	req := httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handleCleanFile(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestFormatOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    FormatOptions
+		wantErr bool
+	}{
+		{"empty", FormatOptions{}, false},
+		{"semicolon", FormatOptions{Delimiter: ";"}, false},
+		{"tab", FormatOptions{Delimiter: "\t"}, false},
+		{"multi-char delimiter", FormatOptions{Delimiter: ";;"}, true},
+		{"gzip", FormatOptions{Compression: "GZIP"}, false},
+		{"unknown compression", FormatOptions{Compression: "brotli"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandleCleanFile_CSVDelimiter(t *testing.T) {
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "data.csv")
+	output := filepath.Join(dir, "out.csv")
+	if err := os.WriteFile(input, []byte("name;city\n Alice ;istanbul\n"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	body := `{"file_path":"` + input + `","output":"` + output + `","actions":["trim"],"format_options":{"delimiter":";"}}`
+	req := httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handleCleanFile(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	content, _ := os.ReadFile(output)
+	if string(content) != "name;city\nAlice;istanbul\n" {
+		t.Errorf("unexpected CSV output: %q", content)
+	}
+}
+
+func TestHandleCleanFile_ExcelSheetName(t *testing.T) {
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "data.csv")
+	output := filepath.Join(dir, "out.xlsx")
+	if err := os.WriteFile(input, []byte("name,age\nAlice,30\n"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	body := `{"file_path":"` + input + `","output":"` + output + `","format_options":{"sheet_name":"People"}}`
+	req := httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handleCleanFile(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	f, err := excelize.OpenFile(output)
+	if err != nil {
+		t.Fatalf("failed to open Excel output: %v", err)
+	}
+	defer f.Close()
+	if sheets := f.GetSheetList(); len(sheets) != 1 || sheets[0] != "People" {
+		t.Errorf("expected single sheet People, got %v", sheets)
+	}
+}
+
+func TestFormatOptions_ParquetCompression(t *testing.T) {
+	var applied formats.ParquetOptions
+	for _, opt := range (FormatOptions{Compression: "ZSTD"}).parquetOptions() {
+		opt(&applied)
+	}
+	if applied.Compression != parquet.CompressionCodec_ZSTD {
+		t.Errorf("expected ZSTD compression, got %v", applied.Compression)
+	}
+
+	if opts := (FormatOptions{}).parquetOptions(); len(opts) != 0 {
+		t.Errorf("expected writer default when compression is unset, got %d options", len(opts))
+	}
+}
+
+func TestHandleCleanFile_InvalidFormatOptions(t *testing.T) {
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(input, []byte("name\nAlice\n"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	body := `{"file_path":"` + input + `","format_options":{"compression":"brotli"}}`
+	req := httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handleCleanFile(w, req)
+
+	var resp ErrorResponse
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != http.StatusBadRequest || resp.Code != CodeInvalidFormatOptions {
+		t.Errorf("expected 400 invalid_format_options, got %d %q", w.Code, resp.Code)
+	}
+}
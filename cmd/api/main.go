@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -15,32 +18,93 @@ import (
 	"time"
 
 	"github.com/mstgnz/cleango/pkg/cleaner"
+	"github.com/mstgnz/cleango/pkg/i18n"
 )
 
 // CleanRequest, structure for cleanup request
 type CleanRequest struct {
 	Data       []map[string]interface{} `json:"data"`
 	Actions    []string                 `json:"actions"`
+	Pipeline   string                   `json:"pipeline,omitempty"`
 	Format     string                   `json:"format,omitempty"`
 	Parallel   bool                     `json:"parallel,omitempty"`
 	MaxWorkers int                      `json:"max_workers,omitempty"`
+	// PreserveTypes emits numbers, booleans and nulls natively instead of as strings
+	PreserveTypes bool `json:"preserve_types,omitempty"`
+	// CaptureRejects returns the rows dropped by filters or failing to parse in the response
+	CaptureRejects bool `json:"capture_rejects,omitempty"`
+	// DryRun returns the statistics and a sample of the changed rows instead of the data
+	DryRun bool `json:"dry_run,omitempty"`
+	// ResponseLimit caps the cleaned rows returned, starting at Offset (0: all rows)
+	ResponseLimit int `json:"response_limit,omitempty"`
+	Offset        int `json:"offset,omitempty"`
+	// StatisticsOnly returns the statistics without any data
+	StatisticsOnly bool `json:"statistics_only,omitempty"`
 }
 
 // CleanResponse, structure for cleanup response
 type CleanResponse struct {
 	Data       []map[string]interface{} `json:"data"`
-	Statistics map[string]int           `json:"statistics"`
-	Message    string                   `json:"message"`
+	Statistics Statistics               `json:"statistics"`
+	Rejects    []map[string]interface{} `json:"rejects,omitempty"`
+	// NextOffset is the offset of the next page when response_limit left rows out
+	NextOffset int    `json:"next_offset,omitempty"`
+	Message    string `json:"message"`
+}
+
+// Statistics, shape of the cleaned data and what the pipeline changed in every column
+type Statistics struct {
+	Rows        int                            `json:"rows"`
+	Columns     int                            `json:"columns"`
+	RowsDropped int                            `json:"rows_dropped"`
+	ColumnStats map[string]cleaner.ColumnStats `json:"column_stats"`
+	// Types is the type of every column of the result, e.g. {"age": "int"}. Columns without a
+	// declared type are inferred from their cleaned values.
+	Types map[string]cleaner.Type `json:"types"`
+	// MemoryUsage is the estimated memory of the cleaned data, to size the memory of workers
+	MemoryUsage cleaner.MemoryUsage `json:"memory_usage"`
+}
+
+// newStatistics returns the statistics of a cleaned DataFrame
+func newStatistics(df *cleaner.DataFrame) Statistics {
+	rows, columns := df.Shape()
+	stats := df.Stats()
+	return Statistics{
+		Rows:        rows,
+		Columns:     columns,
+		RowsDropped: stats.RowsDropped(),
+		ColumnStats: stats.Columns,
+		Types:       df.Copy().InferTypes().Types,
+		MemoryUsage: df.MemoryUsage(),
+	}
+}
+
+// ProfileResponse, structure for data quality response
+type ProfileResponse struct {
+	Rows    int                  `json:"rows"`
+	Columns int                  `json:"columns"`
+	Quality cleaner.QualityScore `json:"quality"`
+	// Summary holds count, nulls, distinct values, min, max and, for numeric columns, mean,
+	// median and standard deviation of every column, in column order
+	Summary []cleaner.ColumnSummary `json:"summary"`
+	Message string                  `json:"message"`
 }
 
 // FileCleanRequest, structure for file cleanup request
 type FileCleanRequest struct {
 	FilePath   string   `json:"file_path"`
 	Actions    []string `json:"actions"`
+	Pipeline   string   `json:"pipeline,omitempty"`
 	Format     string   `json:"format,omitempty"`
 	Output     string   `json:"output,omitempty"`
 	Parallel   bool     `json:"parallel,omitempty"`
 	MaxWorkers int      `json:"max_workers,omitempty"`
+	// FormatOptions configures reading the input and writing the output (delimiter, sheet, compression, XML elements)
+	FormatOptions FormatOptions `json:"format_options,omitempty"`
+	// RejectsOutput is the file for the rows dropped by filters or failing to parse, with a reject_reason column
+	RejectsOutput string `json:"rejects_output,omitempty"`
+	// DryRun returns the statistics and a sample of the changed rows without writing any file
+	DryRun bool `json:"dry_run,omitempty"`
 }
 
 func main() {
@@ -49,14 +113,86 @@ func main() {
 		port = "8080"
 	}
 
+	budget = budgetFromEnv()
+
+	uploadLimit, err := uploadLimitFromEnv()
+	if err != nil {
+		log.Fatal(i18n.T(language, "Upload limit error: %v", err))
+	}
+	maxUploadSize = uploadLimit
+
+	tenantConf, err := tenantsFromEnv()
+	if err != nil {
+		log.Fatal(i18n.T(language, "Tenant configuration error: %v", err))
+	}
+	tenants = tenantConf
+
+	if path := os.Getenv("COLUMN_PROFILES"); path != "" {
+		if err := cleaner.LoadProfiles(path); err != nil {
+			log.Fatal(i18n.T(language, "Column profiles error: %v", err))
+		}
+	}
+
+	store, err := newPipelineStore(os.Getenv("PIPELINE_STORE"))
+	if err != nil {
+		log.Fatal(i18n.T(language, "Pipeline store error: %v", err))
+	}
+	pipelines = store
+
+	baseline, err := defaultPipelineFromEnv()
+	if err != nil {
+		log.Fatal(i18n.T(language, "Default pipeline error: %v", err))
+	}
+	defaultActions = baseline
+
+	cache, err := cacheFromEnv()
+	if err != nil {
+		log.Fatal(i18n.T(language, "Result cache error: %v", err))
+	}
+	results = cache
+
+	auditor, err := auditFromEnv()
+	if err != nil {
+		log.Fatal(i18n.T(language, "Audit log error: %v", err))
+	}
+	auditLog = auditor
+
+	ws, err := workspaceFromEnv()
+	if err != nil {
+		log.Fatal(i18n.T(language, "Output workspace error: %v", err))
+	}
+	jobs = ws
+	collectCtx, stopCollect := context.WithCancel(context.Background())
+	defer stopCollect()
+	if jobs != nil {
+		go jobs.run(collectCtx)
+	}
+
+	provider, err := tracingFromEnv(context.Background())
+	if err != nil {
+		log.Fatal(i18n.T(language, "Tracing error: %v", err))
+	}
+	if provider != nil {
+		enableTracing(provider)
+	}
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/clean", handleClean)
+	mux.HandleFunc("/clean/arrow", handleCleanArrow)
 	mux.HandleFunc("/clean-file", handleCleanFile)
+	mux.HandleFunc("/convert", handleConvert)
+	mux.HandleFunc("/upload", handleUpload)
+	mux.HandleFunc("/profile", handleProfile)
+	mux.HandleFunc("/pipelines", handlePipelines)
+	mux.HandleFunc("/pipelines/{name}", handlePipeline)
+	mux.HandleFunc("/jobs/{id}", handleJob)
+	mux.HandleFunc("/metrics", handleMetrics)
+	mux.HandleFunc("/audit", handleAudit)
 	mux.HandleFunc("/health", handleHealth)
 
 	srv := &http.Server{
 		Addr:         ":" + port,
-		Handler:      mux,
+		Handler:      withRequestID(withLanguage(withTenant(withTracing(mux)))),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 60 * time.Second,
 		IdleTimeout:  120 * time.Second,
@@ -67,30 +203,56 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
-		log.Printf("CleanGo API starting on port %s\n", port)
+		log.Print(i18n.T(language, "CleanGo API starting on port %s", port))
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server error: %v", err)
+			log.Fatal(i18n.T(language, "Server error: %v", err))
 		}
 	}()
 
 	<-quit
-	log.Println("Shutting down server...")
+	log.Print(i18n.T(language, "Shutting down server..."))
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		log.Fatal(i18n.T(language, "Server forced to shutdown: %v", err))
+	}
+	if provider != nil {
+		// Export the spans still buffered
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Print(i18n.T(language, "Tracing shutdown error: %v", err))
+		}
 	}
-	log.Println("Server stopped")
+	log.Print(i18n.T(language, "Server stopped"))
 }
 
 func writeJSON(w http.ResponseWriter, status int, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(v); err != nil {
-		log.Printf("failed to encode JSON response: %v", err)
+		log.Print(i18n.T(language, "failed to encode JSON response: %v", err))
+	}
+}
+
+// writeCachedJSON writes an encoded JSON response and, unless key is empty, stores it in the
+// result cache on a miss. It returns the response body, nil if v could not be encoded.
+func writeCachedJSON(w http.ResponseWriter, r *http.Request, key string, v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeJSON(w, http.StatusOK, v)
+		return nil
+	}
+	data = append(data, '\n')
+	if key != "" {
+		cachePut(r, key, data)
+		w.Header().Set(cacheHeader, "MISS")
 	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+	return data
 }
 
 // handleHealth, health check handler
@@ -100,111 +262,270 @@ func handleHealth(w http.ResponseWriter, r *http.Request) {
 
 // handleClean, data cleaning handler
 func handleClean(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Only POST requests are supported", http.StatusMethodNotAllowed)
+	req, actions, ok := decodeCleanRequest(w, r)
+	if !ok {
 		return
 	}
-
-	var req CleanRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "JSON parse error: "+err.Error(), http.StatusBadRequest)
+	if req.DryRun {
+		var resp *DryRunResponse
+		ok := processRequestData(w, r, req, func(df *cleaner.DataFrame, parallelOptions []func(*cleaner.ParallelOptions)) (err error) {
+			resp, err = dryRun(df, actions, req.Parallel, parallelOptions)
+			return err
+		})
+		if ok {
+			resp.Message = i18n.T(requestLanguage(r), "Dry run completed, no data was returned")
+			writeJSON(w, http.StatusOK, resp)
+		}
 		return
 	}
-	defer r.Body.Close()
 
-	if len(req.Data) == 0 {
-		http.Error(w, "Data cannot be empty", http.StatusBadRequest)
+	input, _ := json.Marshal(req.Data)
+	record := AuditRecord{Pipeline: req.Pipeline, Actions: actions, InputFingerprint: fingerprint(input)}
+
+	// Identical data and actions return the cached response without running the pipeline
+	var key string
+	if results != nil {
+		key = cacheKey("clean", input, actions, map[string]interface{}{
+			"preserve_types":  req.PreserveTypes,
+			"capture_rejects": req.CaptureRejects,
+			"response_limit":  req.ResponseLimit,
+			"offset":          req.Offset,
+			"statistics_only": req.StatisticsOnly,
+			"language":        requestLanguage(r),
+		})
+		if cached, ok := cacheGet(r, key); ok {
+			writeCacheHit(w, r, cached, record)
+			return
+		}
+	}
+
+	df, ok := cleanRequestData(w, r, req, actions)
+	if !ok {
 		return
 	}
 
-	// Collect headers (preserve first-seen order)
-	headers := make([]string, 0)
-	headerMap := make(map[string]bool)
-	for _, record := range req.Data {
-		for key := range record {
-			if !headerMap[key] {
-				headerMap[key] = true
-				headers = append(headers, key)
-			}
+	resp := CleanResponse{
+		Data:       []map[string]interface{}{},
+		Statistics: newStatistics(df),
+		Message:    i18n.T(requestLanguage(r), "Data cleaned successfully"),
+	}
+	if !req.StatisticsOnly {
+		page, next := responsePage(df, req.Offset, req.ResponseLimit)
+		resp.Data = dataFrameToMaps(page, req.PreserveTypes)
+		resp.NextOffset = next
+	}
+	if req.CaptureRejects {
+		resp.Rejects = dataFrameToMaps(df.Rejects(), false)
+	}
+	body := writeCachedJSON(w, r, key, resp)
+	record.OutputFingerprint = fingerprint(body)
+	record.RowsIn, record.RowsOut = resp.Statistics.Rows+resp.Statistics.RowsDropped, resp.Statistics.Rows
+	audit(r, record)
+}
+
+// writeCacheHit writes a cached JSON response of the result cache and completes its audit record
+func writeCacheHit(w http.ResponseWriter, r *http.Request, cached []byte, record AuditRecord) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(cacheHeader, "HIT")
+	w.WriteHeader(http.StatusOK)
+	w.Write(cached)
+	if auditLog != nil {
+		var resp struct {
+			Statistics Statistics `json:"statistics"`
 		}
+		json.Unmarshal(cached, &resp)
+		record.OutputFingerprint = fingerprint(cached)
+		record.RowsIn, record.RowsOut = resp.Statistics.Rows+resp.Statistics.RowsDropped, resp.Statistics.Rows
+		record.Cached = true
+		audit(r, record)
 	}
+}
 
-	rows := make([][]string, len(req.Data))
-	for i, record := range req.Data {
-		row := make([]string, len(headers))
-		for j, header := range headers {
-			if val, ok := record[header]; ok {
-				row[j] = fmt.Sprintf("%v", val)
-			}
+// handleCleanArrow, data cleaning handler that returns the result as an Arrow IPC stream.
+// The batch_size query parameter sets the rows per record batch (default: 65536).
+func handleCleanArrow(w http.ResponseWriter, r *http.Request) {
+	batchSize := defaultArrowBatchSize
+	if v := r.URL.Query().Get("batch_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, r, CodeInvalidParameter, "batch_size must be a positive integer", err)
+			return
 		}
-		rows[i] = row
+		batchSize = n
 	}
 
-	df, err := cleaner.NewDataFrame(headers, rows)
-	if err != nil {
-		http.Error(w, "DataFrame creation error: "+err.Error(), http.StatusInternalServerError)
+	req, actions, ok := decodeCleanRequest(w, r)
+	if !ok {
+		return
+	}
+	df, ok := cleanRequestData(w, r, req, actions)
+	if !ok {
 		return
 	}
 
-	parallelOptions := []func(*cleaner.ParallelOptions){
-		cleaner.WithContext(r.Context()),
+	// Values are converted per column type; a value that no longer fits its type fails before anything is sent
+	var buf bytes.Buffer
+	span := startIOSpan(r.Context(), "write", "arrow")
+	err := df.WriteArrowStream(&buf, batchSize)
+	endIOSpan(span, df, err)
+	if err != nil {
+		writeError(w, r, CodeInvalidData, "Data cannot be converted to Arrow", err)
+		return
 	}
-	if req.MaxWorkers > 0 {
-		parallelOptions = append(parallelOptions, cleaner.WithMaxWorkers(req.MaxWorkers))
+	w.Header().Set("Content-Type", cleaner.ArrowStreamContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+
+	if auditLog != nil {
+		input, _ := json.Marshal(req.Data)
+		stats := newStatistics(df)
+		audit(r, AuditRecord{
+			Pipeline:          req.Pipeline,
+			Actions:           actions,
+			InputFingerprint:  fingerprint(input),
+			OutputFingerprint: fingerprint(buf.Bytes()),
+			RowsIn:            stats.Rows + stats.RowsDropped,
+			RowsOut:           stats.Rows,
+		})
 	}
+}
 
-	if err := applyActions(df, req.Actions, req.Parallel, parallelOptions); err != nil {
-		http.Error(w, "Action error: "+err.Error(), http.StatusBadRequest)
+// handleProfile, data quality handler. It accepts the same body as /clean and scores the data
+// after the actions, if any, are applied.
+func handleProfile(w http.ResponseWriter, r *http.Request) {
+	req, actions, ok := decodeCleanRequest(w, r)
+	if !ok {
 		return
 	}
+	df, ok := cleanRequestData(w, r, req, actions)
+	if !ok {
+		return
+	}
+
+	rows, columns := df.Shape()
+	writeJSON(w, http.StatusOK, ProfileResponse{
+		Rows:    rows,
+		Columns: columns,
+		Quality: df.QualityScore(),
+		Summary: df.Describe(),
+		Message: i18n.T(requestLanguage(r), "Data profiled successfully"),
+	})
+}
+
+// defaultArrowBatchSize, rows per Arrow record batch when batch_size is not set
+const defaultArrowBatchSize = 65536
+
+// decodeCleanRequest decodes and validates a cleaning request and resolves its actions.
+// On failure the error response is written and false is returned.
+func decodeCleanRequest(w http.ResponseWriter, r *http.Request) (*CleanRequest, []string, bool) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, CodeMethodNotAllowed, "Only POST requests are supported", nil)
+		return nil, nil, false
+	}
+
+	var req CleanRequest
+	// Numbers are kept as written, so integers beyond the precision of float64 are not rounded
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, r, CodeInvalidJSON, "Request body is not valid JSON", err)
+		return nil, nil, false
+	}
+	defer r.Body.Close()
+
+	if len(req.Data) == 0 {
+		writeError(w, r, CodeEmptyData, "Data cannot be empty", nil)
+		return nil, nil, false
+	}
+	if req.ResponseLimit < 0 || req.Offset < 0 {
+		writeError(w, r, CodeInvalidParameter, "response_limit and offset must not be negative", fmt.Errorf("response_limit: %d, offset: %d", req.ResponseLimit, req.Offset))
+		return nil, nil, false
+	}
 
-	result := dataFrameToMaps(df)
-	rowCount, colCount := df.Shape()
+	actions, err := resolveActions(requestTenant(r), req.Pipeline, req.Actions)
+	if err != nil {
+		writePipelineError(w, r, err)
+		return nil, nil, false
+	}
+	return &req, actions, true
+}
 
-	writeJSON(w, http.StatusOK, CleanResponse{
-		Data:       result,
-		Statistics: map[string]int{"rows": rowCount, "columns": colCount},
-		Message:    "Data cleaned successfully",
+// cleanRequestData converts the request records to a DataFrame and applies the actions within the
+// worker budget. On failure the error response is written and false is returned.
+func cleanRequestData(w http.ResponseWriter, r *http.Request, req *CleanRequest, actions []string) (*cleaner.DataFrame, bool) {
+	var result *cleaner.DataFrame
+	ok := processRequestData(w, r, req, func(df *cleaner.DataFrame, parallelOptions []func(*cleaner.ParallelOptions)) (err error) {
+		result, err = applyActions(df, actions, req.Parallel, req.CaptureRejects, parallelOptions)
+		return err
 	})
+	return result, ok
+}
+
+// processRequestData converts the request records to a DataFrame and runs process on it within the
+// worker budget. On failure the error response is written and false is returned.
+func processRequestData(w http.ResponseWriter, r *http.Request, req *CleanRequest, process func(*cleaner.DataFrame, []func(*cleaner.ParallelOptions)) error) bool {
+	span := startIOSpan(r.Context(), "read", "json")
+	df, err := recordsToDataFrame(req.Data)
+	endIOSpan(span, df, err)
+	if err != nil {
+		writeError(w, r, CodeInvalidData, "Data cannot be converted to a DataFrame", err)
+		return false
+	}
+
+	workers := budget.workersFor(req.Parallel, req.MaxWorkers)
+	release, err := budget.Acquire(r.Context(), workers)
+	if err != nil {
+		writeError(w, r, classifyError(err, CodeServerBusy), "Server worker budget exhausted", err)
+		return false
+	}
+	defer release()
+
+	parallelOptions := []func(*cleaner.ParallelOptions){
+		cleaner.WithContext(r.Context()),
+		cleaner.WithMaxWorkers(workers),
+		cleaner.WithObserver(operationObserver),
+	}
+
+	metrics.rows(r, len(df.Data))
+	if err := process(df, parallelOptions); err != nil {
+		writeError(w, r, classifyError(err, CodeInvalidAction), "Action could not be applied", err)
+		return false
+	}
+	return true
 }
 
 // handleCleanFile, file cleaning handler
 func handleCleanFile(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Only POST requests are supported", http.StatusMethodNotAllowed)
+		writeError(w, r, CodeMethodNotAllowed, "Only POST requests are supported", nil)
 		return
 	}
 
 	var req FileCleanRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "JSON parse error: "+err.Error(), http.StatusBadRequest)
+		writeError(w, r, CodeInvalidJSON, "Request body is not valid JSON", err)
 		return
 	}
 	defer r.Body.Close()
 
 	if req.FilePath == "" {
-		http.Error(w, "File path not specified", http.StatusBadRequest)
+		writeError(w, r, CodeMissingFilePath, "File path not specified", nil)
 		return
 	}
 
-	// Prevent path traversal
-	cleanPath := filepath.Clean(req.FilePath)
-	absPath, err := filepath.Abs(cleanPath)
-	if err != nil || strings.Contains(absPath, "..") {
-		http.Error(w, "Invalid file path", http.StatusBadRequest)
+	actions, err := resolveActions(requestTenant(r), req.Pipeline, req.Actions)
+	if err != nil {
+		writePipelineError(w, r, err)
 		return
 	}
 
-	// Restrict to current working directory or a dedicated data dir
-	workDir, _ := os.Getwd()
-	if !strings.HasPrefix(absPath, workDir) {
-		http.Error(w, "File path is outside the allowed directory", http.StatusForbidden)
+	if !allowedFilePath(w, r, req.FilePath) {
 		return
 	}
 
 	inputFormat := getFileFormat(req.FilePath)
 	if inputFormat == "" {
-		http.Error(w, "Unsupported file format", http.StatusBadRequest)
+		writeError(w, r, CodeUnsupportedFormat, "Unsupported file format", nil)
 		return
 	}
 
@@ -214,224 +535,389 @@ func handleCleanFile(w http.ResponseWriter, r *http.Request) {
 	if outputFile == "" {
 		outputFile = "cleaned_" + filepath.Base(req.FilePath)
 	}
+	rejectsOutput := req.RejectsOutput
+	if outputFormat == "" {
+		outputFormat = getFileFormat(outputFile)
+	}
 	if outputFormat == "" {
 		outputFormat = inputFormat
 	}
+	if !isSupportedFormat(outputFormat) {
+		writeError(w, r, CodeUnsupportedFormat, "Unsupported output format", fmt.Errorf("format: %s", outputFormat))
+		return
+	}
+	if err := req.FormatOptions.Validate(); err != nil {
+		writeError(w, r, CodeInvalidFormatOptions, "Invalid format options", err)
+		return
+	}
+
+	// Rejects are written in the format of their extension, or else in the output format
+	rejectsFormat := outputFormat
+	if req.RejectsOutput != "" {
+		if format := getFileFormat(req.RejectsOutput); format != "" {
+			rejectsFormat = format
+		}
+		if !isSupportedFormat(rejectsFormat) {
+			writeError(w, r, CodeUnsupportedFormat, "Unsupported output format", fmt.Errorf("format: %s", rejectsFormat))
+			return
+		}
+	}
+
+	// Without a workspace the outputs are written where requested, so they must stay in the working directory
+	if jobs == nil && !req.DryRun {
+		if !allowedFilePath(w, r, outputFile) {
+			return
+		}
+		if rejectsOutput != "" && !allowedFilePath(w, r, rejectsOutput) {
+			return
+		}
+	}
 
-	var parallelOptions []func(*cleaner.ParallelOptions)
-	if req.MaxWorkers > 0 {
-		parallelOptions = append(parallelOptions, cleaner.WithMaxWorkers(req.MaxWorkers))
+	// With an output workspace, every run writes into its own job directory, which is purged after
+	// OUTPUT_TTL or by DELETE /jobs/{id}. The directory of a failed run is removed right away.
+	var jobID string
+	jobDone := false
+	if jobs != nil && !req.DryRun {
+		id, dir, err := jobs.newJob(requestTenant(r))
+		if err != nil {
+			writeError(w, r, CodeWriteFailed, "File could not be written", err)
+			return
+		}
+		jobID = id
+		outputFile = filepath.Join(dir, filepath.Base(outputFile))
+		if rejectsOutput != "" {
+			rejectsOutput = filepath.Join(dir, filepath.Base(rejectsOutput))
+		}
+		defer func() {
+			if !jobDone {
+				jobs.Delete(requestTenant(r), id)
+			}
+		}()
 	}
 
-	var df *cleaner.DataFrame
-	switch inputFormat {
-	case "csv":
-		df, err = cleaner.ReadCSV(req.FilePath)
-	case "json":
-		df, err = cleaner.ReadJSON(req.FilePath)
-	case "excel":
-		df, err = cleaner.ReadExcel(req.FilePath)
-	case "parquet":
-		df, err = cleaner.ReadParquet(req.FilePath)
+	// An unchanged file cleaned with the same actions and options is restored from the cache.
+	// Runs that write rejects are not cached, as the entry holds the output file only.
+	var key string
+	var input []byte
+	if ((results != nil && req.RejectsOutput == "") || auditLog != nil) && !req.DryRun {
+		input, err = fileFingerprint(req.FilePath)
+		if err != nil {
+			writeError(w, r, classifyError(err, CodeReadFailed), "File could not be read", err)
+			return
+		}
+	}
+	record := AuditRecord{Pipeline: req.Pipeline, Actions: actions, InputPath: req.FilePath, InputFingerprint: hex.EncodeToString(input)}
+	if results != nil && req.RejectsOutput == "" && !req.DryRun {
+		options := map[string]interface{}{
+			"input_format":   inputFormat,
+			"output_format":  outputFormat,
+			"format_options": req.FormatOptions,
+			"language":       requestLanguage(r),
+		}
+		if req.FormatOptions.Provenance {
+			// The output holds the input path in the _source_file column
+			options["file_path"] = req.FilePath
+		}
+		key = cacheKey("clean-file", input, actions, options)
+		if cached, ok := cacheGet(r, key); ok {
+			var entry cachedFile
+			if err := json.Unmarshal(cached, &entry); err == nil {
+				if err := writeFileContent(outputFile, entry.Content); err != nil {
+					writeError(w, r, CodeWriteFailed, "File could not be written", err)
+					return
+				}
+				w.Header().Set(cacheHeader, "HIT")
+				jobDone = true
+				record.Cached = true
+				writeFileCleaned(w, r, outputFile, jobID, entry.Statistics, record)
+				return
+			}
+		}
 	}
+
+	workers := budget.workersFor(req.Parallel, req.MaxWorkers)
+	release, err := budget.Acquire(r.Context(), workers)
 	if err != nil {
-		http.Error(w, "File read error: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, classifyError(err, CodeServerBusy), "Server worker budget exhausted", err)
 		return
 	}
+	defer release()
+
+	parallelOptions := []func(*cleaner.ParallelOptions){
+		cleaner.WithContext(r.Context()),
+		cleaner.WithMaxWorkers(workers),
+		cleaner.WithObserver(operationObserver),
+	}
 
-	if err := applyActions(df, req.Actions, req.Parallel, parallelOptions); err != nil {
-		http.Error(w, "Action error: "+err.Error(), http.StatusBadRequest)
+	span := startIOSpan(r.Context(), "read", inputFormat)
+	df, err := readDataFrame(req.FilePath, inputFormat, req.FormatOptions)
+	endIOSpan(span, df, err)
+	if err != nil {
+		writeError(w, r, classifyError(err, CodeReadFailed), "File could not be read", err)
 		return
 	}
 
-	var writeErr error
-	switch outputFormat {
-	case "csv":
-		writeErr = df.WriteCSV(outputFile)
-	case "json":
-		writeErr = df.WriteJSON(outputFile)
-	case "excel":
-		writeErr = df.WriteExcel(outputFile)
-	case "parquet":
-		writeErr = df.WriteParquet(outputFile)
+	metrics.rows(r, len(df.Data))
+	if req.DryRun {
+		resp, err := dryRun(df, actions, req.Parallel, parallelOptions)
+		if err != nil {
+			writeError(w, r, classifyError(err, CodeInvalidAction), "Action could not be applied", err)
+			return
+		}
+		resp.Message = i18n.T(requestLanguage(r), "Dry run completed, no file was written")
+		writeJSON(w, http.StatusOK, resp)
+		return
 	}
-	if writeErr != nil {
-		http.Error(w, "File write error: "+writeErr.Error(), http.StatusInternalServerError)
+	df, err = applyActions(df, actions, req.Parallel, req.RejectsOutput != "", parallelOptions)
+	if err != nil {
+		writeError(w, r, classifyError(err, CodeInvalidAction), "Action could not be applied", err)
+		return
+	}
+
+	span = startIOSpan(r.Context(), "write", outputFormat)
+	err = writeDataFrame(df, outputFile, outputFormat, req.FormatOptions)
+	endIOSpan(span, df, err)
+	if err != nil {
+		writeError(w, r, CodeWriteFailed, "File could not be written", err)
 		return
 	}
 
-	rowCount, colCount := df.Shape()
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"message":    "File cleaned successfully",
+	if req.RejectsOutput != "" {
+		span = startIOSpan(r.Context(), "write", rejectsFormat)
+		err = writeDataFrame(df.Rejects(), rejectsOutput, rejectsFormat, req.FormatOptions)
+		endIOSpan(span, df.Rejects(), err)
+		if err != nil {
+			writeError(w, r, CodeWriteFailed, "File could not be written", err)
+			return
+		}
+	}
+
+	stats := newStatistics(df)
+	if key != "" {
+		if content, err := os.ReadFile(outputFile); err == nil {
+			entry, _ := json.Marshal(cachedFile{Statistics: stats, Content: content})
+			cachePut(r, key, entry)
+		}
+		w.Header().Set(cacheHeader, "MISS")
+	}
+	jobDone = true
+	writeFileCleaned(w, r, outputFile, jobID, stats, record)
+}
+
+// allowedFilePath reports whether the file path resolves inside the working directory. Otherwise
+// the error response is written.
+func allowedFilePath(w http.ResponseWriter, r *http.Request, filePath string) bool {
+	// Prevent path traversal
+	cleanPath := filepath.Clean(filePath)
+	absPath, err := filepath.Abs(cleanPath)
+	if err != nil || strings.Contains(absPath, "..") {
+		writeError(w, r, CodeInvalidFilePath, "Invalid file path", err)
+		return false
+	}
+
+	// Restrict to current working directory or a dedicated data dir
+	workDir, _ := os.Getwd()
+	if !strings.HasPrefix(absPath, workDir) {
+		writeError(w, r, CodeForbiddenPath, "File path is outside the allowed directory", nil)
+		return false
+	}
+	return true
+}
+
+// writeFileCleaned writes the response of a cleaned file and completes its audit record. The output
+// of a job is first fitted into the workspace quota.
+func writeFileCleaned(w http.ResponseWriter, r *http.Request, outputFile, jobID string, stats Statistics, record AuditRecord) {
+	resp := map[string]interface{}{
+		"message":    i18n.T(requestLanguage(r), "File cleaned successfully"),
 		"output":     outputFile,
-		"statistics": map[string]int{"rows": rowCount, "columns": colCount},
+		"statistics": stats,
+	}
+	if jobID != "" {
+		if err := jobs.Enforce(requestTenant(r), jobID); err != nil {
+			if errors.Is(err, ErrQuotaExceeded) {
+				writeError(w, r, CodeQuotaExceeded, "Output is larger than the workspace quota", err)
+				return
+			}
+			writeError(w, r, CodeWriteFailed, "File could not be written", err)
+			return
+		}
+		resp["job_id"] = jobID
+	}
+	writeJSON(w, http.StatusOK, resp)
+
+	if auditLog != nil {
+		output, err := fileFingerprint(outputFile)
+		if err != nil {
+			log.Print(i18n.T(language, "[%s] audit log write failed: %v", requestID(r), err))
+			return
+		}
+		record.OutputPath = outputFile
+		record.OutputFingerprint = hex.EncodeToString(output)
+		record.JobID = jobID
+		record.RowsIn, record.RowsOut = stats.Rows+stats.RowsDropped, stats.Rows
+		audit(r, record)
+	}
+}
+
+// applyActions applies the list of cleaning actions to the DataFrame as a single pipeline, so
+// consecutive row-wise actions run in one pass. Malformed and unknown actions are skipped; errors of
+// clean_regex fail the request while other action errors are logged and the action is skipped.
+// With captureRejects, rows that are dropped or fail an action are kept in df.Rejects() instead.
+func applyActions(df *cleaner.DataFrame, actions []string, parallel, captureRejects bool, parallelOptions []func(*cleaner.ParallelOptions)) (*cleaner.DataFrame, error) {
+	pipeline := buildPipeline(actions).OnError(func(err *cleaner.StepError) error {
+		if err.Step == "clean_regex" {
+			return fmt.Errorf("clean_regex error: %w", err.Err)
+		}
+		log.Print(i18n.T(language, "%s error: %s", err.Step, i18n.Error(language, err.Err)))
+		return nil
 	})
+	if captureRejects {
+		pipeline.CaptureRejects()
+	}
+
+	if !parallel {
+		parallelOptions = append(parallelOptions[:len(parallelOptions):len(parallelOptions)], cleaner.WithMaxWorkers(1))
+	}
+	return pipeline.Run(df, parallelOptions...)
 }
 
-// applyActions applies the list of cleaning actions to the DataFrame.
-func applyActions(df *cleaner.DataFrame, actions []string, parallel bool, parallelOptions []func(*cleaner.ParallelOptions)) error {
+// buildPipeline converts the list of cleaning actions into a pipeline. Invalid actions are logged and skipped.
+func buildPipeline(actions []string) *cleaner.Pipeline {
+	pipeline := cleaner.NewPipeline()
 	for _, action := range actions {
-		parts := strings.SplitN(action, ":", 2)
-		actionType := parts[0]
-
-		switch actionType {
-		case "trim":
-			if parallel {
-				if trimmed, err := df.TrimColumnsParallel(parallelOptions...); err != nil {
-					return err
-				} else {
-					df = trimmed
-				}
-			} else {
-				df.TrimColumns()
-			}
+		if err := pipeline.Action(action); err != nil {
+			log.Print(i18n.Error(language, err))
+		}
+	}
+	return pipeline
+}
 
-		case "normalize_dates":
-			if len(parts) < 2 {
-				continue
-			}
-			dateParts := strings.SplitN(parts[1], "=", 2)
-			if len(dateParts) != 2 {
-				continue
-			}
-			column, layout := dateParts[0], dateParts[1]
-			var err error
-			if parallel {
-				_, err = df.CleanDatesParallel(column, layout, parallelOptions...)
-			} else {
-				_, err = df.CleanDates(column, layout)
-			}
-			if err != nil {
-				log.Printf("normalize_dates error: %v", err)
+// recordsToDataFrame converts request records into a DataFrame. Headers keep their first-seen
+// order and each column's type is taken from the JSON values it holds.
+func recordsToDataFrame(records []map[string]interface{}) (*cleaner.DataFrame, error) {
+	headers := make([]string, 0)
+	headerMap := make(map[string]bool)
+	for _, record := range records {
+		for key := range record {
+			if !headerMap[key] {
+				headerMap[key] = true
+				headers = append(headers, key)
 			}
+		}
+	}
 
-		case "replace_nulls":
-			if len(parts) < 2 {
-				continue
-			}
-			nullParts := strings.SplitN(parts[1], "=", 2)
-			if len(nullParts) != 2 {
-				continue
-			}
-			column, value := nullParts[0], nullParts[1]
-			var err error
-			if parallel {
-				_, err = df.ReplaceNullsParallel(column, value, parallelOptions...)
-			} else {
-				_, err = df.ReplaceNulls(column, value)
-			}
-			if err != nil {
-				log.Printf("replace_nulls error: %v", err)
+	rows := make([][]string, len(records))
+	for i, record := range records {
+		row := make([]string, len(headers))
+		for j, header := range headers {
+			if val, ok := record[header]; ok {
+				row[j] = formatRequestValue(val)
 			}
+		}
+		rows[i] = row
+	}
 
-		case "normalize_case":
-			if len(parts) < 2 {
-				continue
-			}
-			caseParts := strings.SplitN(parts[1], "=", 2)
-			if len(caseParts) != 2 {
-				continue
-			}
-			column, caseType := caseParts[0], caseParts[1]
-			toUpper := strings.ToLower(caseType) == "upper"
-			var err error
-			if parallel {
-				_, err = df.NormalizeCaseParallel(column, toUpper, parallelOptions...)
-			} else {
-				_, err = df.NormalizeCase(column, toUpper)
-			}
-			if err != nil {
-				log.Printf("normalize_case error: %v", err)
-			}
+	df, err := cleaner.NewDataFrame(headers, rows)
+	if err != nil {
+		return nil, err
+	}
 
-		case "clean_regex":
-			if len(parts) < 2 {
-				continue
-			}
-			regexParts := strings.SplitN(parts[1], "=", 3)
-			if len(regexParts) != 3 {
-				continue
-			}
-			column, pattern, replacement := regexParts[0], regexParts[1], regexParts[2]
-			var err error
-			if parallel {
-				_, err = df.CleanWithRegexParallel(column, pattern, replacement, parallelOptions...)
-			} else {
-				_, err = df.CleanWithRegex(column, pattern, replacement)
-			}
-			if err != nil {
-				return fmt.Errorf("clean_regex error: %w", err)
-			}
+	for _, header := range headers {
+		df.Types[header] = jsonColumnType(records, header)
+	}
+	return df, nil
+}
 
-		case "split_column":
-			if len(parts) < 2 {
-				continue
-			}
-			splitParts := strings.SplitN(parts[1], "=", 3)
-			if len(splitParts) < 3 {
-				continue
+// jsonColumnType returns the type shared by all non-null JSON values of a column
+func jsonColumnType(records []map[string]interface{}, header string) cleaner.Type {
+	result, seen := cleaner.TypeString, false
+	for _, record := range records {
+		var t cleaner.Type
+		switch v := record[header].(type) {
+		case nil:
+			continue
+		case json.Number:
+			t = cleaner.TypeFloat
+			if _, err := v.Int64(); err == nil {
+				t = cleaner.TypeInt
 			}
-			column, separator := splitParts[0], splitParts[1]
-			newColumns := strings.Split(splitParts[2], ",")
-			if _, err := df.SplitColumn(column, separator, newColumns); err != nil {
-				log.Printf("split_column error: %v", err)
+		case float64:
+			t = cleaner.TypeFloat
+			if v == float64(int64(v)) {
+				t = cleaner.TypeInt
 			}
+		case bool:
+			t = cleaner.TypeBool
+		case map[string]interface{}, []interface{}:
+			t = cleaner.TypeJSON
+		default:
+			return cleaner.TypeString
+		}
 
-		case "filter_outliers":
-			if len(parts) < 2 {
-				continue
-			}
-			outlierParts := strings.SplitN(parts[1], "=", 3)
-			if len(outlierParts) != 3 {
-				continue
-			}
-			column := outlierParts[0]
-			min, err1 := strconv.ParseFloat(outlierParts[1], 64)
-			max, err2 := strconv.ParseFloat(outlierParts[2], 64)
-			if err1 != nil || err2 != nil {
-				log.Printf("filter_outliers: invalid number")
-				continue
-			}
-			var err error
-			if parallel {
-				_, err = df.FilterOutliersParallel(column, min, max, parallelOptions...)
-			} else {
-				_, err = df.FilterOutliers(column, min, max)
-			}
-			if err != nil {
-				log.Printf("filter_outliers error: %v", err)
-			}
+		switch {
+		case !seen || result == t:
+			result, seen = t, true
+		case (result == cleaner.TypeInt && t == cleaner.TypeFloat) || (result == cleaner.TypeFloat && t == cleaner.TypeInt):
+			result = cleaner.TypeFloat
+		default:
+			return cleaner.TypeString
+		}
+	}
+	return result
+}
+
+// formatRequestValue converts a JSON value to its cell representation
+func formatRequestValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case json.Number:
+		return v.String()
+	case float64:
+		// Without an exponent, so 1000000 stays 1000000 rather than 1e+06
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
 		}
+		return string(b)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// responsePage returns the rows of df from offset, at most limit of them unless limit is 0, and the
+// offset of the following rows, 0 when there are none
+func responsePage(df *cleaner.DataFrame, offset, limit int) (*cleaner.DataFrame, int) {
+	start := min(offset, len(df.Data))
+	end := len(df.Data)
+	if limit > 0 && start+limit < end {
+		end = start + limit
 	}
-	return nil
+	next := 0
+	if end < len(df.Data) {
+		next = end
+	}
+	return &cleaner.DataFrame{Headers: df.Headers, Data: df.Data[start:end], Types: df.Types}, next
 }
 
-func dataFrameToMaps(df *cleaner.DataFrame) []map[string]interface{} {
+// dataFrameToMaps converts the DataFrame to records. With preserveTypes, values are
+// emitted as native JSON numbers, booleans and nulls according to the column types.
+func dataFrameToMaps(df *cleaner.DataFrame, preserveTypes bool) []map[string]interface{} {
 	result := make([]map[string]interface{}, len(df.GetData()))
 	for i, row := range df.GetData() {
 		record := make(map[string]interface{})
 		for j, header := range df.GetHeaders() {
 			if j < len(row) {
-				record[header] = row[j]
+				if preserveTypes {
+					record[header] = cleaner.ParseValue(row[j], df.Types[header])
+				} else {
+					record[header] = row[j]
+				}
 			}
 		}
 		result[i] = record
 	}
 	return result
 }
-
-func getFileFormat(filePath string) string {
-	ext := strings.ToLower(filepath.Ext(filePath))
-	switch ext {
-	case ".csv":
-		return "csv"
-	case ".json":
-		return "json"
-	case ".xlsx", ".xls":
-		return "excel"
-	case ".parquet":
-		return "parquet"
-	default:
-		return ""
-	}
-}
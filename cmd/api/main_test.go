@@ -5,8 +5,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"testing"
 
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
 	"github.com/mstgnz/cleango/pkg/cleaner"
 )
 
@@ -224,11 +231,141 @@ func TestHandleClean_StatisticsReturned(t *testing.T) {
 		t.Fatalf("failed to decode response: %v", err)
 	}
 
-	if resp.Statistics["rows"] != 3 {
-		t.Errorf("expected rows=3, got %d", resp.Statistics["rows"])
+	if resp.Statistics.Rows != 3 {
+		t.Errorf("expected rows=3, got %d", resp.Statistics.Rows)
 	}
-	if resp.Statistics["columns"] != 2 {
-		t.Errorf("expected columns=2, got %d", resp.Statistics["columns"])
+	if resp.Statistics.Columns != 2 {
+		t.Errorf("expected columns=2, got %d", resp.Statistics.Columns)
+	}
+}
+
+func TestHandleClean_ColumnStatistics(t *testing.T) {
+	payload := CleanRequest{
+		Data: []map[string]interface{}{
+			{"name": " Ali ", "age": nil},
+			{"name": "Ayşe", "age": "200"},
+		},
+		Actions: []string{"trim", "replace_nulls:age=0", "filter_outliers:age=0=120"},
+	}
+	body, _ := json.Marshal(payload)
+
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	handleClean(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp CleanResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Statistics.Rows != 1 || resp.Statistics.RowsDropped != 1 {
+		t.Errorf("expected 1 row kept and 1 dropped, got %+v", resp.Statistics)
+	}
+	if got := resp.Statistics.ColumnStats["name"].Modified; got != 1 {
+		t.Errorf("expected 1 trimmed name, got %d", got)
+	}
+	age := resp.Statistics.ColumnStats["age"]
+	if age.NullsReplaced != 1 || age.RowsDropped != 1 {
+		t.Errorf("unexpected age statistics: %+v", age)
+	}
+}
+
+func TestHandleClean_CaptureRejects(t *testing.T) {
+	payload := CleanRequest{
+		Data: []map[string]interface{}{
+			{"name": "Ali", "age": "30"},
+			{"name": "Ayşe", "age": "200"},
+		},
+		Actions:        []string{"filter_outliers:age=0=120"},
+		CaptureRejects: true,
+	}
+	body, _ := json.Marshal(payload)
+
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	handleClean(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp CleanResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Data) != 1 || len(resp.Rejects) != 1 {
+		t.Fatalf("expected 1 row kept and 1 rejected, got %d and %d", len(resp.Data), len(resp.Rejects))
+	}
+	if resp.Rejects[0]["name"] != "Ayşe" || resp.Rejects[0][cleaner.RejectReasonColumn] != "filter_outliers(age): value outside [0, 120]" {
+		t.Errorf("unexpected reject: %v", resp.Rejects[0])
+	}
+}
+
+func TestHandleClean_Types(t *testing.T) {
+	payload := CleanRequest{
+		Data: []map[string]interface{}{
+			{"name": " Ali ", "age": 30, "joined": "2024-01-15", "active": true},
+			{"name": "Ayşe", "age": 41, "joined": "2024-02-20", "active": false},
+		},
+		// Dates are declared by normalize_dates, the other types are inferred
+		Actions: []string{"trim", "normalize_dates:joined=2006-01-02"},
+	}
+	body, _ := json.Marshal(payload)
+	w := httptest.NewRecorder()
+	handleClean(w, httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBuffer(body)))
+
+	var resp struct {
+		Statistics struct {
+			Types       map[string]string   `json:"types"`
+			MemoryUsage cleaner.MemoryUsage `json:"memory_usage"`
+		} `json:"statistics"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	want := map[string]string{"name": "string", "age": "int", "joined": "date", "active": "bool"}
+	if !reflect.DeepEqual(resp.Statistics.Types, want) {
+		t.Errorf("expected types %v, got %v", want, resp.Statistics.Types)
+	}
+	if usage := resp.Statistics.MemoryUsage; usage.Total == 0 || usage.Columns["name"] == 0 {
+		t.Errorf("expected the memory usage of the result, got %+v", usage)
+	}
+}
+
+func TestHandleClean_Pagination(t *testing.T) {
+	data := make([]map[string]interface{}, 5)
+	for i := range data {
+		data[i] = map[string]interface{}{"id": strconv.Itoa(i)}
+	}
+	clean := func(payload CleanRequest) (int, CleanResponse) {
+		payload.Data = data
+		body, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		handleClean(w, httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBuffer(body)))
+		var resp CleanResponse
+		json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, resp
+	}
+
+	_, resp := clean(CleanRequest{ResponseLimit: 2, Offset: 1})
+	if len(resp.Data) != 2 || resp.Data[0]["id"] != "1" || resp.NextOffset != 3 || resp.Statistics.Rows != 5 {
+		t.Errorf("unexpected first page: %+v", resp)
+	}
+	_, resp = clean(CleanRequest{ResponseLimit: 2, Offset: 3})
+	if len(resp.Data) != 2 || resp.Data[1]["id"] != "4" || resp.NextOffset != 0 {
+		t.Errorf("unexpected last page: %+v", resp)
+	}
+	_, resp = clean(CleanRequest{Offset: 10})
+	if len(resp.Data) != 0 || resp.NextOffset != 0 {
+		t.Errorf("expected no rows past the end, got %+v", resp)
+	}
+	_, resp = clean(CleanRequest{StatisticsOnly: true})
+	if len(resp.Data) != 0 || resp.Statistics.Rows != 5 {
+		t.Errorf("expected statistics only, got %+v", resp)
+	}
+	if code, _ := clean(CleanRequest{Offset: -1}); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a negative offset, got %d", code)
 	}
 }
 
@@ -270,6 +407,32 @@ func TestHandleCleanFile_PathTraversal(t *testing.T) {
 	}
 }
 
+func TestHandleCleanFile_OutputOutsideWorkDir(t *testing.T) {
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(input, []byte("name\nAlice\n"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	outside := filepath.Join(t.TempDir(), "out.csv")
+	for name, req := range map[string]FileCleanRequest{
+		"output":         {FilePath: input, Output: outside},
+		"rejects output": {FilePath: input, Output: filepath.Join(dir, "out.csv"), RejectsOutput: outside},
+	} {
+		t.Run(name, func(t *testing.T) {
+			body, _ := json.Marshal(req)
+			w := httptest.NewRecorder()
+			handleCleanFile(w, httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBuffer(body)))
+			if w.Code != http.StatusForbidden {
+				t.Errorf("expected 403, got %d: %s", w.Code, w.Body.String())
+			}
+			if _, err := os.Stat(outside); !os.IsNotExist(err) {
+				t.Errorf("expected nothing written outside the working directory, got %v", err)
+			}
+		})
+	}
+}
+
 func TestHandleCleanFile_UnsupportedFormat(t *testing.T) {
 	body := `{"file_path":"testdata/file.txt"}`
 	req := httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBufferString(body))
@@ -295,6 +458,9 @@ func TestGetFileFormat(t *testing.T) {
 		{"data.xlsx", "excel"},
 		{"data.xls", "excel"},
 		{"data.parquet", "parquet"},
+		{"data.xml", "xml"},
+		{"data.yaml", "yaml"},
+		{"data.yml", "yaml"},
 		{"data.txt", ""},
 		{"data", ""},
 	}
@@ -313,7 +479,7 @@ func TestApplyActions_UnknownAction(t *testing.T) {
 		t.Fatalf("failed to create DataFrame: %v", err)
 	}
 
-	if err := applyActions(df, []string{"unknown_action:foo=bar"}, false, nil); err != nil {
+	if _, err := applyActions(df, []string{"unknown_action:foo=bar"}, false, false, nil); err != nil {
 		t.Errorf("unknown action should be ignored, got error: %v", err)
 	}
 }
@@ -324,7 +490,7 @@ func TestApplyActions_ParallelTrim(t *testing.T) {
 		t.Fatalf("failed to create DataFrame: %v", err)
 	}
 
-	if err := applyActions(df, []string{"trim"}, true, nil); err != nil {
+	if _, err := applyActions(df, []string{"trim"}, true, false, nil); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
@@ -332,3 +498,379 @@ func TestApplyActions_ParallelTrim(t *testing.T) {
 		t.Errorf("expected Alice, got %q", df.GetData()[0][0])
 	}
 }
+
+func TestApplyActions_SanitizeControlChars(t *testing.T) {
+	df, err := cleaner.NewDataFrame([]string{"name", "note"}, [][]string{{"Alice\u200b", "a\u00a0b"}})
+	if err != nil {
+		t.Fatalf("failed to create DataFrame: %v", err)
+	}
+
+	if _, err := applyActions(df, []string{"sanitize_control_chars:name"}, false, false, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if data := df.GetData(); data[0][0] != "Alice" || data[0][1] != "a\u00a0b" {
+		t.Errorf("expected only the name column to be sanitized, got %q", data[0])
+	}
+}
+
+func TestApplyActions_NormalizeNumericFormats(t *testing.T) {
+	df, err := cleaner.NewDataFrame([]string{"price"}, [][]string{{"$1,234.50"}, {"(12)"}, {"45%"}})
+	if err != nil {
+		t.Fatalf("failed to create DataFrame: %v", err)
+	}
+
+	if _, err := applyActions(df, []string{"normalize_numeric_formats:price"}, false, false, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, want := range []string{"1234.5", "-12", "0.45"} {
+		if got := df.GetData()[i][0]; got != want {
+			t.Errorf("row %d: expected %q, got %q", i, want, got)
+		}
+	}
+}
+
+func TestApplyActions_ConvertUnits(t *testing.T) {
+	df, err := cleaner.NewDataFrame([]string{"weight"}, [][]string{{"10"}, {"2.5"}})
+	if err != nil {
+		t.Fatalf("failed to create DataFrame: %v", err)
+	}
+
+	if _, err := applyActions(df, []string{"convert_units:weight=lb=kg=2"}, false, false, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, want := range []string{"4.54", "1.13"} {
+		if got := df.GetData()[i][0]; got != want {
+			t.Errorf("row %d: expected %q, got %q", i, want, got)
+		}
+	}
+}
+
+func TestApplyActions_NormalizeDatesEpoch(t *testing.T) {
+	df, err := cleaner.NewDataFrame([]string{"ts"}, [][]string{{"1673741400000"}})
+	if err != nil {
+		t.Fatalf("failed to create DataFrame: %v", err)
+	}
+
+	if _, err := applyActions(df, []string{"normalize_dates:ts=2006-01-02 15:04=ms"}, false, false, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := df.GetData()[0][0]; got != "2023-01-15 00:10" {
+		t.Errorf("expected 2023-01-15 00:10, got %q", got)
+	}
+}
+
+func TestApplyActions_NormalizeHeaders(t *testing.T) {
+	df, err := cleaner.NewDataFrame([]string{"Müşteri No", "Ad Soyad"}, [][]string{{"1", "Ali"}})
+	if err != nil {
+		t.Fatalf("failed to create DataFrame: %v", err)
+	}
+
+	result, err := applyActions(df, []string{"normalize_headers:camel=transliterate"}, false, false, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if headers := result.GetHeaders(); headers[0] != "musteriNo" || headers[1] != "adSoyad" {
+		t.Errorf("expected musteriNo and adSoyad, got %v", headers)
+	}
+}
+
+func TestApplyActions_DropConstantColumns(t *testing.T) {
+	df, err := cleaner.NewDataFrame([]string{"id", "country"}, [][]string{{"1", "TR"}, {"2", "TR"}})
+	if err != nil {
+		t.Fatalf("failed to create DataFrame: %v", err)
+	}
+
+	result, err := applyActions(df, []string{"drop_constant_columns"}, false, false, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if headers := result.GetHeaders(); len(headers) != 1 || headers[0] != "id" {
+		t.Errorf("expected only id to be kept, got %v", headers)
+	}
+}
+
+func TestApplyActions_FakeColumn(t *testing.T) {
+	df, err := cleaner.NewDataFrame([]string{"email"}, [][]string{{"ali@corp.com"}, {"ali@corp.com"}})
+	if err != nil {
+		t.Fatalf("failed to create DataFrame: %v", err)
+	}
+
+	if _, err := applyActions(df, []string{"fake_column:email=email=42"}, false, false, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := df.GetData()
+	if data[0][0] == "ali@corp.com" || data[0][0] != data[1][0] {
+		t.Errorf("expected the same fake for both rows, got %v", data)
+	}
+}
+
+func TestApplyActions_AddRowNumber(t *testing.T) {
+	df, err := cleaner.NewDataFrame([]string{"name"}, [][]string{{"Ali"}, {"Ayşe"}})
+	if err != nil {
+		t.Fatalf("failed to create DataFrame: %v", err)
+	}
+
+	result, err := applyActions(df, []string{"add_row_number:row_id"}, false, false, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if headers := result.GetHeaders(); headers[0] != "row_id" || result.GetData()[1][0] != "2" {
+		t.Errorf("expected row_id numbering, got %v %v", headers, result.GetData())
+	}
+}
+
+func TestReadDataFrame_Provenance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "people.csv")
+	if err := os.WriteFile(path, []byte("name\nAli\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	df, err := readDataFrame(path, "csv", FormatOptions{Provenance: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data := df.GetData(); len(data) != 1 || data[0][1] != path || data[0][2] != "1" {
+		t.Errorf("expected provenance columns, got %v %v", df.GetHeaders(), data)
+	}
+}
+
+func TestApplyActions_CoalesceColumns(t *testing.T) {
+	df, err := cleaner.NewDataFrame([]string{"mobile", "home"}, [][]string{{"", "212"}, {"555", "312"}})
+	if err != nil {
+		t.Fatalf("failed to create DataFrame: %v", err)
+	}
+
+	result, err := applyActions(df, []string{"coalesce_columns:phone=mobile,home"}, false, false, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if headers := result.GetHeaders(); len(headers) != 1 || headers[0] != "phone" {
+		t.Errorf("expected only the phone column, got %v", headers)
+	}
+	if data := result.GetData(); data[0][0] != "212" || data[1][0] != "555" {
+		t.Errorf("expected the first non-empty phone of every row, got %v", data)
+	}
+}
+
+func TestHandleClean_PreserveTypes(t *testing.T) {
+	body := `{
+		"data": [
+			{"name": " Alice ", "age": 30, "score": 9.5, "active": true, "note": null, "tags": ["a"]},
+			{"name": "Bob", "age": 25, "score": 7, "active": false, "note": "x", "tags": ["b"]}
+		],
+		"actions": ["trim"],
+		"preserve_types": true
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handleClean(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp CleanResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	first := resp.Data[0]
+	if first["name"] != "Alice" {
+		t.Errorf("expected name=Alice, got %v", first["name"])
+	}
+	if first["age"] != float64(30) {
+		t.Errorf("expected numeric age, got %#v", first["age"])
+	}
+	if first["score"] != 9.5 {
+		t.Errorf("expected numeric score, got %#v", first["score"])
+	}
+	if first["active"] != true {
+		t.Errorf("expected boolean active, got %#v", first["active"])
+	}
+	if v, ok := first["note"]; !ok || v != nil {
+		t.Errorf("expected null note, got %#v", v)
+	}
+	if tags, ok := first["tags"].([]interface{}); !ok || len(tags) != 1 || tags[0] != "a" {
+		t.Errorf("expected tags array, got %#v", first["tags"])
+	}
+}
+
+func TestHandleClean_TypesNotPreservedByDefault(t *testing.T) {
+	body := `{"data":[{"age":30,"note":null}],"actions":[]}`
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handleClean(w, req)
+
+	var resp CleanResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Data[0]["age"] != "30" {
+		t.Errorf("expected age as string, got %#v", resp.Data[0]["age"])
+	}
+	if resp.Data[0]["note"] != "" {
+		t.Errorf("expected null to become an empty string, got %#v", resp.Data[0]["note"])
+	}
+}
+
+func TestHandleClean_LargeNumbers(t *testing.T) {
+	// 9007199254740993 is 2^53+1, which float64 rounds to 9007199254740992
+	body := `{"data":[{"id":9007199254740993,"amount":1000000,"price":1234567.5,"meta":{"id":9007199254740993}}],"actions":[]}`
+	w := httptest.NewRecorder()
+	handleClean(w, httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data       []map[string]string `json:"data"`
+		Statistics struct {
+			Types map[string]string `json:"types"`
+		} `json:"statistics"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	want := map[string]string{"id": "9007199254740993", "amount": "1000000", "price": "1234567.5", "meta": `{"id":9007199254740993}`}
+	if !reflect.DeepEqual(resp.Data[0], want) {
+		t.Errorf("expected %v, got %v", want, resp.Data[0])
+	}
+	if types := resp.Statistics.Types; types["id"] != "int" || types["amount"] != "int" || types["price"] != "float" {
+		t.Errorf("unexpected types %v", types)
+	}
+}
+
+func TestFormatRequestValue(t *testing.T) {
+	tests := []struct {
+		val  interface{}
+		want string
+	}{
+		{float64(1000000), "1000000"},
+		{float64(1 << 53), "9007199254740992"},
+		{0.25, "0.25"},
+		{json.Number("9007199254740993"), "9007199254740993"},
+		{true, "true"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := formatRequestValue(tt.val); got != tt.want {
+			t.Errorf("formatRequestValue(%#v) = %q, expected %q", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestApplyActions_ParallelFilterOutliers(t *testing.T) {
+	df, err := cleaner.NewDataFrame([]string{"age"}, [][]string{{"25"}, {"250"}, {"30"}})
+	if err != nil {
+		t.Fatalf("failed to create DataFrame: %v", err)
+	}
+
+	result, err := applyActions(df, []string{"filter_outliers:age=0=120"}, true, false, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rows, _ := result.Shape(); rows != 2 {
+		t.Errorf("expected 2 rows after filtering, got %d", rows)
+	}
+}
+
+func TestHandleCleanArrow(t *testing.T) {
+	body := `{"data":[{"name":"  Ali  ","age":30},{"name":"Ayşe","age":null}],"actions":["trim"]}`
+	req := httptest.NewRequest(http.MethodPost, "/clean/arrow?batch_size=1", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handleCleanArrow(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != cleaner.ArrowStreamContentType {
+		t.Errorf("expected Arrow content type, got %q", ct)
+	}
+
+	reader, err := ipc.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("ipc.NewReader error: %v", err)
+	}
+	defer reader.Release()
+
+	schema := reader.Schema()
+	if ages := schema.FieldIndices("age"); len(ages) != 1 || schema.Field(ages[0]).Type.ID() != arrow.INT64 {
+		t.Errorf("expected an int64 age column, got %s", schema)
+	}
+	var names []string
+	for reader.Next() {
+		record := reader.Record()
+		col := record.Column(schema.FieldIndices("name")[0]).(*array.String)
+		for i := 0; i < col.Len(); i++ {
+			names = append(names, col.Value(i))
+		}
+	}
+	if len(names) != 2 || names[0] != "Ali" || names[1] != "Ayşe" {
+		t.Errorf("unexpected names: %v", names)
+	}
+}
+
+func TestHandleCleanArrow_InvalidBatchSize(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/clean/arrow?batch_size=0", bytes.NewBufferString(`{"data":[{"a":"1"}]}`))
+	w := httptest.NewRecorder()
+
+	handleCleanArrow(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestHandleProfile(t *testing.T) {
+	payload := CleanRequest{
+		Data: []map[string]interface{}{
+			{"name": " Ali ", "age": "30"},
+			{"name": "Ayşe", "age": nil},
+		},
+		Actions: []string{"trim"},
+	}
+	body, _ := json.Marshal(payload)
+
+	req := httptest.NewRequest(http.MethodPost, "/profile", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	handleProfile(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ProfileResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Rows != 2 || resp.Columns != 2 {
+		t.Errorf("expected 2 rows and 2 columns, got %d and %d", resp.Rows, resp.Columns)
+	}
+	if got := resp.Quality.Columns["age"].Completeness; got != 0.5 {
+		t.Errorf("expected age completeness 0.5, got %v", got)
+	}
+	if got := resp.Quality.Columns["name"].Score; got != 1 {
+		t.Errorf("expected a perfect score for the trimmed names, got %v", got)
+	}
+	for _, s := range resp.Summary {
+		if s.Column == "age" && (!s.Numeric || s.Nulls != 1 || s.Mean == nil || *s.Mean != 30) {
+			t.Errorf("unexpected summary of age: %+v", s)
+		}
+	}
+	if len(resp.Summary) != 2 {
+		t.Errorf("expected a summary per column, got %d", len(resp.Summary))
+	}
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+)
+
+// PipelineDefinition, named list of actions stored on the server
+type PipelineDefinition struct {
+	Tenant      string    `json:"tenant,omitempty"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Actions     []string  `json:"actions"`
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+var (
+	// ErrPipelineNotFound is returned when a referenced pipeline does not exist
+	ErrPipelineNotFound = errors.New("pipeline not found")
+	// ErrPipelineExists is returned when creating a pipeline whose name is taken
+	ErrPipelineExists = errors.New("pipeline already exists")
+)
+
+// pipelineNamePattern, allowed pipeline names (e.g. crm-contacts-v3)
+var pipelineNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// validateActions checks that every action has a known type
+func validateActions(actions []string) error {
+	for i, action := range actions {
+		if err := cleaner.NewPipeline().Action(action); errors.Is(err, cleaner.ErrUnknownAction) {
+			return fmt.Errorf("action %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// defaultActions, baseline actions run before those of every cleaning request (nil: none)
+var defaultActions []string
+
+// defaultPipelineFromEnv reads DEFAULT_PIPELINE, a JSON file with a pipeline definition whose
+// actions run first on every cleaning request, e.g. {"name": "baseline", "actions": ["trim"]}.
+// When it is empty, no baseline is applied.
+func defaultPipelineFromEnv() ([]string, error) {
+	path := os.Getenv("DEFAULT_PIPELINE")
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read default pipeline: %w", err)
+	}
+	var p PipelineDefinition
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse default pipeline: %w", err)
+	}
+	if len(p.Actions) == 0 {
+		return nil, errors.New("default pipeline must contain at least one action")
+	}
+	if err := validateActions(p.Actions); err != nil {
+		return nil, fmt.Errorf("default pipeline: %w", err)
+	}
+	return p.Actions, nil
+}
+
+// pipelineStore, thread-safe pipeline storage, optionally persisted to a JSON file. Every tenant
+// has its own pipeline names.
+type pipelineStore struct {
+	mu    sync.RWMutex
+	path  string
+	items map[string]PipelineDefinition // By pipelineKey
+}
+
+// pipelineKey returns the key of the pipeline of the tenant; names and tenants cannot contain '/'
+func pipelineKey(tenant, name string) string {
+	return tenant + "/" + name
+}
+
+// newPipelineStore creates a store persisted at path. An empty path keeps pipelines in memory only.
+func newPipelineStore(path string) (*pipelineStore, error) {
+	s := &pipelineStore{path: path, items: make(map[string]PipelineDefinition)}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline store: %w", err)
+	}
+
+	var items []PipelineDefinition
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline store: %w", err)
+	}
+	for _, p := range items {
+		s.items[pipelineKey(p.Tenant, p.Name)] = p
+	}
+	return s, nil
+}
+
+// pipelines, pipeline store used by the handlers
+var pipelines, _ = newPipelineStore("")
+
+// List returns the pipelines of the tenant sorted by name
+func (s *pipelineStore) List(tenant string) []PipelineDefinition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]PipelineDefinition, 0, len(s.items))
+	for _, p := range s.items {
+		if p.Tenant == tenant {
+			result = append(result, p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Get returns the pipeline of the tenant with the given name
+func (s *pipelineStore) Get(tenant, name string) (PipelineDefinition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.items[pipelineKey(tenant, name)]
+	if !ok {
+		return PipelineDefinition{}, fmt.Errorf("%w: %s", ErrPipelineNotFound, name)
+	}
+	return p, nil
+}
+
+// Put stores the pipeline under its tenant. With create, an existing pipeline is an error;
+// otherwise it is replaced and its version incremented.
+func (s *pipelineStore) Put(p PipelineDefinition, create bool) (PipelineDefinition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	key := pipelineKey(p.Tenant, p.Name)
+	existing, ok := s.items[key]
+	if ok && create {
+		return PipelineDefinition{}, fmt.Errorf("%w: %s", ErrPipelineExists, p.Name)
+	}
+
+	p.Version = 1
+	p.CreatedAt = now
+	if ok {
+		p.Version = existing.Version + 1
+		p.CreatedAt = existing.CreatedAt
+	}
+	p.UpdatedAt = now
+
+	s.items[key] = p
+	if err := s.persist(); err != nil {
+		if ok {
+			s.items[key] = existing
+		} else {
+			delete(s.items, key)
+		}
+		return PipelineDefinition{}, err
+	}
+	return p, nil
+}
+
+// Delete removes the pipeline of the tenant with the given name
+func (s *pipelineStore) Delete(tenant, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := pipelineKey(tenant, name)
+	existing, ok := s.items[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrPipelineNotFound, name)
+	}
+
+	delete(s.items, key)
+	if err := s.persist(); err != nil {
+		s.items[key] = existing
+		return err
+	}
+	return nil
+}
+
+// persist writes all pipelines to the store file. Caller must hold s.mu.
+func (s *pipelineStore) persist() error {
+	if s.path == "" {
+		return nil
+	}
+
+	items := make([]PipelineDefinition, 0, len(s.items))
+	for _, p := range s.items {
+		items = append(items, p)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Tenant != items[j].Tenant {
+			return items[i].Tenant < items[j].Tenant
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode pipeline store: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write pipeline store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write pipeline store: %w", err)
+	}
+	return nil
+}
+
+// resolveActions prepends the default actions and the actions of the referenced pipeline of the
+// tenant to the request actions
+func resolveActions(tenant, pipeline string, actions []string) ([]string, error) {
+	if pipeline == "" && defaultActions == nil {
+		return actions, nil
+	}
+
+	var p PipelineDefinition
+	if pipeline != "" {
+		var err error
+		if p, err = pipelines.Get(tenant, pipeline); err != nil {
+			return nil, err
+		}
+	}
+
+	resolved := make([]string, 0, len(defaultActions)+len(p.Actions)+len(actions))
+	resolved = append(resolved, defaultActions...)
+	resolved = append(resolved, p.Actions...)
+	return append(resolved, actions...), nil
+}
+
+// handlePipelines, handler for listing (GET) and creating (POST) pipelines
+func handlePipelines(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"pipelines": pipelines.List(requestTenant(r))})
+	case http.MethodPost:
+		p, ok := decodePipeline(w, r, "")
+		if !ok {
+			return
+		}
+		saved, err := pipelines.Put(p, true)
+		if err != nil {
+			writePipelineError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, saved)
+	default:
+		writeError(w, r, CodeMethodNotAllowed, "Only GET and POST requests are supported", nil)
+	}
+}
+
+// handlePipeline, handler for reading (GET), replacing (PUT) and deleting (DELETE) a pipeline
+func handlePipeline(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	switch r.Method {
+	case http.MethodGet:
+		p, err := pipelines.Get(requestTenant(r), name)
+		if err != nil {
+			writePipelineError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, p)
+	case http.MethodPut:
+		p, ok := decodePipeline(w, r, name)
+		if !ok {
+			return
+		}
+		saved, err := pipelines.Put(p, false)
+		if err != nil {
+			writePipelineError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, saved)
+	case http.MethodDelete:
+		if err := pipelines.Delete(requestTenant(r), name); err != nil {
+			writePipelineError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, r, CodeMethodNotAllowed, "Only GET, PUT and DELETE requests are supported", nil)
+	}
+}
+
+// decodePipeline reads and validates a pipeline definition from the request body.
+// When name is set (from the URL) it overrides the name in the body.
+func decodePipeline(w http.ResponseWriter, r *http.Request, name string) (PipelineDefinition, bool) {
+	var p PipelineDefinition
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeError(w, r, CodeInvalidJSON, "Request body is not valid JSON", err)
+		return p, false
+	}
+	defer r.Body.Close()
+
+	if name != "" {
+		p.Name = name
+	}
+	// Pipelines always belong to the tenant of the request
+	p.Tenant = requestTenant(r)
+	if !pipelineNamePattern.MatchString(p.Name) {
+		writeError(w, r, CodeInvalidPipeline, "Pipeline name must contain only letters, digits, '.', '_' or '-'", fmt.Errorf("name: %q", p.Name))
+		return p, false
+	}
+	if len(p.Actions) == 0 {
+		writeError(w, r, CodeInvalidPipeline, "Pipeline must contain at least one action", nil)
+		return p, false
+	}
+	if err := validateActions(p.Actions); err != nil {
+		writeError(w, r, CodeInvalidPipeline, "Pipeline contains an invalid action", err)
+		return p, false
+	}
+	return p, true
+}
+
+// writePipelineError writes the error envelope for pipeline store errors
+func writePipelineError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrPipelineNotFound):
+		writeError(w, r, CodePipelineNotFound, "Pipeline not found", err)
+	case errors.Is(err, ErrPipelineExists):
+		writeError(w, r, CodePipelineExists, "Pipeline already exists", err)
+	default:
+		writeError(w, r, CodeInternal, "Pipeline could not be stored", err)
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+)
+
+// withPipelineStore swaps the package store for a fresh in-memory one for the duration of a test
+func withPipelineStore(t *testing.T) {
+	t.Helper()
+	saved := pipelines
+	pipelines, _ = newPipelineStore("")
+	t.Cleanup(func() { pipelines = saved })
+}
+
+func doPipelineRequest(method, name, body string) *httptest.ResponseRecorder {
+	target := "/pipelines"
+	if name != "" {
+		target += "/" + name
+	}
+	req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	if name == "" {
+		handlePipelines(w, req)
+	} else {
+		req.SetPathValue("name", name)
+		handlePipeline(w, req)
+	}
+	return w
+}
+
+func TestPipelines_CRUD(t *testing.T) {
+	withPipelineStore(t)
+
+	w := doPipelineRequest(http.MethodPost, "", `{"name":"crm-contacts-v3","actions":["trim","normalize_case:email=lower"]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doPipelineRequest(http.MethodPost, "", `{"name":"crm-contacts-v3","actions":["trim"]}`)
+	if w.Code != http.StatusConflict {
+		t.Errorf("duplicate create: expected 409, got %d", w.Code)
+	}
+
+	w = doPipelineRequest(http.MethodGet, "crm-contacts-v3", "")
+	var p PipelineDefinition
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("failed to decode pipeline: %v", err)
+	}
+	if p.Version != 1 || len(p.Actions) != 2 {
+		t.Errorf("unexpected pipeline: %+v", p)
+	}
+
+	w = doPipelineRequest(http.MethodPut, "crm-contacts-v3", `{"actions":["trim"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("update: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("failed to decode pipeline: %v", err)
+	}
+	if p.Version != 2 || p.Name != "crm-contacts-v3" {
+		t.Errorf("expected version 2 with name from URL, got %+v", p)
+	}
+
+	w = doPipelineRequest(http.MethodGet, "", "")
+	var list struct {
+		Pipelines []PipelineDefinition `json:"pipelines"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode list: %v", err)
+	}
+	if len(list.Pipelines) != 1 {
+		t.Errorf("expected 1 pipeline, got %d", len(list.Pipelines))
+	}
+
+	if w = doPipelineRequest(http.MethodDelete, "crm-contacts-v3", ""); w.Code != http.StatusNoContent {
+		t.Errorf("delete: expected 204, got %d", w.Code)
+	}
+	if w = doPipelineRequest(http.MethodGet, "crm-contacts-v3", ""); w.Code != http.StatusNotFound {
+		t.Errorf("get after delete: expected 404, got %d", w.Code)
+	}
+}
+
+func TestPipelines_Validation(t *testing.T) {
+	withPipelineStore(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid name", `{"name":"bad name!","actions":["trim"]}`},
+		{"no actions", `{"name":"empty","actions":[]}`},
+		{"unknown action", `{"name":"unknown","actions":["explode:all"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doPipelineRequest(http.MethodPost, "", tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", w.Code)
+			}
+			var resp ErrorResponse
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			if resp.Code != CodeInvalidPipeline {
+				t.Errorf("expected code %q, got %q", CodeInvalidPipeline, resp.Code)
+			}
+		})
+	}
+}
+
+func TestPipelineStore_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipelines.json")
+
+	store, err := newPipelineStore(path)
+	if err != nil {
+		t.Fatalf("newPipelineStore error: %v", err)
+	}
+	if _, err := store.Put(PipelineDefinition{Name: "daily", Actions: []string{"trim"}}, true); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+
+	reloaded, err := newPipelineStore(path)
+	if err != nil {
+		t.Fatalf("reload error: %v", err)
+	}
+	p, err := reloaded.Get("", "daily")
+	if err != nil {
+		t.Fatalf("Get after reload error: %v", err)
+	}
+	if len(p.Actions) != 1 || p.Actions[0] != "trim" {
+		t.Errorf("unexpected reloaded pipeline: %+v", p)
+	}
+}
+
+func TestHandleClean_WithPipeline(t *testing.T) {
+	withPipelineStore(t)
+	if _, err := pipelines.Put(PipelineDefinition{Name: "contacts", Actions: []string{"trim"}}, true); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+
+	body := `{"data":[{"name":"  alice  "}],"pipeline":"contacts","actions":["normalize_case:name=upper"]}`
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handleClean(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp CleanResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Data[0]["name"] != "ALICE" {
+		t.Errorf("expected pipeline and request actions applied, got %v", resp.Data[0]["name"])
+	}
+}
+
+func TestHandleClean_UnknownPipeline(t *testing.T) {
+	withPipelineStore(t)
+
+	body := `{"data":[{"name":"alice"}],"pipeline":"missing"}`
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	handleClean(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestDefaultPipelineFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "baseline.json")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Setenv("DEFAULT_PIPELINE", write(`{"name":"baseline","actions":["trim","normalize_headers"]}`))
+	actions, err := defaultPipelineFromEnv()
+	if err != nil || len(actions) != 2 || actions[0] != "trim" {
+		t.Fatalf("unexpected default actions: %v, %v", actions, err)
+	}
+
+	for _, content := range []string{`{"actions":[]}`, `{"actions":["unknown"]}`, `not json`} {
+		t.Setenv("DEFAULT_PIPELINE", write(content))
+		if _, err := defaultPipelineFromEnv(); err == nil {
+			t.Errorf("expected an error for %s", content)
+		}
+	}
+}
+
+func TestHandleClean_DefaultPipeline(t *testing.T) {
+	withPipelineStore(t)
+	saved := defaultActions
+	defaultActions = []string{"trim"}
+	t.Cleanup(func() { defaultActions = saved })
+	if _, err := pipelines.Put(PipelineDefinition{Name: "upper", Actions: []string{"normalize_case:name=upper"}}, true); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+
+	actions, err := resolveActions("", "upper", []string{"add_row_number"})
+	if err != nil || len(actions) != 3 || actions[0] != "trim" || actions[1] != "normalize_case:name=upper" {
+		t.Fatalf("expected default, pipeline and request actions in order, got %v, %v", actions, err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(`{"data":[{"name":"  alice  "}]}`))
+	w := httptest.NewRecorder()
+	handleClean(w, req)
+	var resp CleanResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != http.StatusOK || resp.Data[0]["name"] != "alice" {
+		t.Errorf("expected the default pipeline applied, got %d: %v", w.Code, resp.Data)
+	}
+}
+
+func TestHandleClean_RegisteredOp(t *testing.T) {
+	withPipelineStore(t)
+	err := cleaner.RegisterOp("test_reverse_rows", func(string) (func(*cleaner.DataFrame) (*cleaner.DataFrame, error), error) {
+		return func(df *cleaner.DataFrame) (*cleaner.DataFrame, error) {
+			slices.Reverse(df.Data)
+			return df, nil
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterOp error: %v", err)
+	}
+
+	if w := doPipelineRequest(http.MethodPost, "", `{"name":"reversed","actions":["trim","test_reverse_rows"]}`); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	body := `{"data":[{"id":1},{"id":2}],"pipeline":"reversed"}`
+	w := httptest.NewRecorder()
+	handleClean(w, httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp CleanResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Data) != 2 || fmt.Sprint(resp.Data[0]["id"]) != "2" {
+		t.Errorf("expected the registered operation to run, got %v", resp.Data)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Headers carrying the API key and, without API keys, the tenant of a request
+const (
+	apiKeyHeader = "X-API-Key"
+	tenantHeader = "X-Tenant-ID"
+)
+
+// tenantConfig, how the tenant of a request is determined. With API keys every request must carry
+// a known key, whose tenant is used; otherwise the X-Tenant-ID header names the tenant and requests
+// without it belong to the default tenant "".
+type tenantConfig struct {
+	keys map[string]string // API key -> tenant
+}
+
+// tenants, tenant configuration used by the middleware
+var tenants = &tenantConfig{}
+
+// tenantsFromEnv reads API_KEYS, a comma-separated list of tenant=key pairs
+// (e.g. crm=9f2c...,billing=41ab...). A tenant may have several keys.
+func tenantsFromEnv() (*tenantConfig, error) {
+	c := &tenantConfig{}
+	spec := os.Getenv("API_KEYS")
+	if spec == "" {
+		return c, nil
+	}
+
+	c.keys = make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		tenant, key, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" || !pipelineNamePattern.MatchString(tenant) {
+			return nil, fmt.Errorf("invalid API_KEYS entry: %q", pair)
+		}
+		c.keys[key] = tenant
+	}
+	return c, nil
+}
+
+type tenantKey struct{}
+
+// withTenant resolves the tenant of every request except health checks and counts the request in
+// the metrics of the tenant
+func withTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var tenant string
+		if tenants.keys != nil {
+			key := r.Header.Get(apiKeyHeader)
+			if key == "" {
+				key, _ = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			}
+			var ok bool
+			if tenant, ok = tenants.keys[key]; !ok || key == "" {
+				writeError(w, r, CodeUnauthorized, "Missing or invalid API key", nil)
+				return
+			}
+		} else if tenant = r.Header.Get(tenantHeader); tenant != "" && !pipelineNamePattern.MatchString(tenant) {
+			writeError(w, r, CodeInvalidTenant, "Tenant ID must contain only letters, digits, '.', '_' or '-'", fmt.Errorf("tenant: %q", tenant))
+			return
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), tenantKey{}, tenant)))
+		metrics.request(tenant, recorder.status)
+	})
+}
+
+// requestTenant returns the tenant of the request, "" for the default tenant
+func requestTenant(r *http.Request) string {
+	tenant, _ := r.Context().Value(tenantKey{}).(string)
+	return tenant
+}
+
+// tenantMetrics, counters of one tenant
+type tenantMetrics struct {
+	requests map[int]int64 // By status class, e.g. 2 for 2xx
+	rows     int64
+}
+
+// metricsRegistry, thread-safe counters of all tenants
+type metricsRegistry struct {
+	mu      sync.Mutex
+	tenants map[string]*tenantMetrics
+}
+
+// metrics, counters updated by the handlers
+var metrics = &metricsRegistry{tenants: make(map[string]*tenantMetrics)}
+
+// get returns the counters of the tenant. Caller must hold m.mu.
+func (m *metricsRegistry) get(tenant string) *tenantMetrics {
+	t, ok := m.tenants[tenant]
+	if !ok {
+		t = &tenantMetrics{requests: make(map[int]int64)}
+		m.tenants[tenant] = t
+	}
+	return t
+}
+
+// request counts a request of the tenant answered with the status
+func (m *metricsRegistry) request(tenant string, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.get(tenant).requests[status/100]++
+}
+
+// rows counts rows cleaned for the tenant of the request
+func (m *metricsRegistry) rows(r *http.Request, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.get(requestTenant(r)).rows += int64(n)
+}
+
+// handleMetrics, per-tenant metrics in the Prometheus text format. With API keys, a tenant only
+// sees its own metrics.
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, CodeMethodNotAllowed, "Only GET requests are supported", nil)
+		return
+	}
+
+	metrics.mu.Lock()
+	names := make([]string, 0, len(metrics.tenants))
+	for tenant := range metrics.tenants {
+		if tenants.keys == nil || tenant == requestTenant(r) {
+			names = append(names, tenant)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# HELP cleango_requests_total Requests served, by tenant and status class.\n")
+	b.WriteString("# TYPE cleango_requests_total counter\n")
+	for _, tenant := range names {
+		classes := make([]int, 0, len(metrics.tenants[tenant].requests))
+		for class := range metrics.tenants[tenant].requests {
+			classes = append(classes, class)
+		}
+		sort.Ints(classes)
+		for _, class := range classes {
+			fmt.Fprintf(&b, "cleango_requests_total{tenant=%q,status=\"%dxx\"} %d\n", tenant, class, metrics.tenants[tenant].requests[class])
+		}
+	}
+	b.WriteString("# HELP cleango_rows_processed_total Rows cleaned, by tenant.\n")
+	b.WriteString("# TYPE cleango_rows_processed_total counter\n")
+	for _, tenant := range names {
+		fmt.Fprintf(&b, "cleango_rows_processed_total{tenant=%q} %d\n", tenant, metrics.tenants[tenant].rows)
+	}
+	metrics.mu.Unlock()
+
+	if jobs != nil {
+		b.WriteString("# HELP cleango_output_bytes Bytes of the job outputs in the workspace, by tenant.\n")
+		b.WriteString("# TYPE cleango_output_bytes gauge\n")
+		for _, tenant := range names {
+			size, err := jobs.usage(tenant)
+			if err == nil {
+				fmt.Fprintf(&b, "cleango_output_bytes{tenant=%q} %d\n", tenant, size)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(b.String()))
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withTenants swaps the tenant configuration and metrics for the duration of a test
+func withTenants(t *testing.T, keys map[string]string) {
+	t.Helper()
+	savedTenants, savedMetrics := tenants, metrics
+	tenants = &tenantConfig{keys: keys}
+	metrics = &metricsRegistry{tenants: make(map[string]*tenantMetrics)}
+	t.Cleanup(func() { tenants, metrics = savedTenants, savedMetrics })
+}
+
+// tenantHandler routes requests like the server does
+func tenantHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/clean-file", handleCleanFile)
+	mux.HandleFunc("/pipelines", handlePipelines)
+	mux.HandleFunc("/pipelines/{name}", handlePipeline)
+	mux.HandleFunc("/jobs/{id}", handleJob)
+	mux.HandleFunc("/metrics", handleMetrics)
+	mux.HandleFunc("/health", handleHealth)
+	return withTenant(mux)
+}
+
+func doTenantRequest(method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	tenantHandler().ServeHTTP(w, req)
+	return w
+}
+
+func TestTenantsFromEnv(t *testing.T) {
+	t.Setenv("API_KEYS", "crm=key1, billing=key2,crm=key3")
+	c, err := tenantsFromEnv()
+	if err != nil {
+		t.Fatalf("tenantsFromEnv error: %v", err)
+	}
+	if c.keys["key1"] != "crm" || c.keys["key2"] != "billing" || c.keys["key3"] != "crm" {
+		t.Errorf("unexpected keys: %v", c.keys)
+	}
+
+	for _, spec := range []string{"crm", "crm=", "a/b=key"} {
+		t.Setenv("API_KEYS", spec)
+		if _, err := tenantsFromEnv(); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+func TestWithTenant_APIKeys(t *testing.T) {
+	withTenants(t, map[string]string{"secret": "crm"})
+	withPipelineStore(t)
+
+	if w := doTenantRequest(http.MethodGet, "/pipelines", "", nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a key, got %d", w.Code)
+	}
+	if w := doTenantRequest(http.MethodGet, "/pipelines", "", map[string]string{apiKeyHeader: "wrong"}); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for an unknown key, got %d", w.Code)
+	}
+	// The tenant header cannot override the tenant of the key
+	w := doTenantRequest(http.MethodPost, "/pipelines", `{"name":"p","actions":["trim"]}`,
+		map[string]string{"Authorization": "Bearer secret", tenantHeader: "billing"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201 with a bearer key, got %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := pipelines.Get("crm", "p"); err != nil {
+		t.Errorf("expected the pipeline to belong to the tenant of the key: %v", err)
+	}
+	if w := doTenantRequest(http.MethodGet, "/health", "", nil); w.Code != http.StatusOK {
+		t.Errorf("expected health checks without a key, got %d", w.Code)
+	}
+}
+
+func TestWithTenant_InvalidHeader(t *testing.T) {
+	withTenants(t, nil)
+	w := doTenantRequest(http.MethodGet, "/pipelines", "", map[string]string{tenantHeader: "../crm"})
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), string(CodeInvalidTenant)) {
+		t.Errorf("expected invalid_tenant, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTenant_PipelinesIsolated(t *testing.T) {
+	withTenants(t, nil)
+	withPipelineStore(t)
+	crm := map[string]string{tenantHeader: "crm"}
+	billing := map[string]string{tenantHeader: "billing"}
+
+	if w := doTenantRequest(http.MethodPut, "/pipelines/daily", `{"actions":["trim"]}`, crm); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	// The same name is free for another tenant
+	if w := doTenantRequest(http.MethodPut, "/pipelines/daily", `{"actions":["sanitize_control_chars"]}`, billing); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := doTenantRequest(http.MethodGet, "/pipelines/daily", "", nil); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for the default tenant, got %d", w.Code)
+	}
+
+	w := doTenantRequest(http.MethodGet, "/pipelines", "", crm)
+	var resp struct {
+		Pipelines []PipelineDefinition `json:"pipelines"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Pipelines) != 1 || resp.Pipelines[0].Actions[0] != "trim" {
+		t.Errorf("expected only the pipeline of the tenant, got %+v", resp.Pipelines)
+	}
+
+	if _, err := resolveActions("billing", "daily", nil); err != nil {
+		t.Errorf("expected the pipeline of the tenant to resolve: %v", err)
+	}
+	if _, err := resolveActions("", "daily", nil); err == nil {
+		t.Error("expected the pipeline of another tenant not to resolve")
+	}
+}
+
+func TestTenant_Jobs(t *testing.T) {
+	withTenants(t, nil)
+	ws, _ := newWorkspace(t.TempDir(), time.Hour, 0)
+	ws.quotas = map[string]int64{"crm": 5}
+	withWorkspace(t, ws)
+
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "in.csv")
+	if err := os.WriteFile(input, []byte("name\n  Ali  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(FileCleanRequest{FilePath: input, Actions: []string{"trim"}})
+
+	w := doTenantRequest(http.MethodPost, "/clean-file", string(body), map[string]string{tenantHeader: "billing"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Output string `json:"output"`
+		JobID  string `json:"job_id"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if want := filepath.Join(ws.dir, "tenants", "billing", resp.JobID); filepath.Dir(resp.Output) != want {
+		t.Fatalf("expected the output under %s, got %s", want, resp.Output)
+	}
+
+	// Another tenant cannot delete the job
+	if w := doTenantRequest(http.MethodDelete, "/jobs/"+resp.JobID, "", map[string]string{tenantHeader: "crm"}); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a job of another tenant, got %d", w.Code)
+	}
+	if w := doTenantRequest(http.MethodDelete, "/jobs/"+resp.JobID, "", map[string]string{tenantHeader: "billing"}); w.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", w.Code)
+	}
+
+	// The output of 9 bytes exceeds the quota of crm but not the unlimited default
+	w = doTenantRequest(http.MethodPost, "/clean-file", string(body), map[string]string{tenantHeader: "crm"})
+	if w.Code != http.StatusInsufficientStorage {
+		t.Errorf("expected 507 over the quota of the tenant, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleMetrics(t *testing.T) {
+	withTenants(t, map[string]string{"k1": "crm", "k2": "billing"})
+	withPipelineStore(t)
+
+	doTenantRequest(http.MethodGet, "/pipelines", "", map[string]string{apiKeyHeader: "k1"})
+	doTenantRequest(http.MethodGet, "/pipelines/missing", "", map[string]string{apiKeyHeader: "k1"})
+	doTenantRequest(http.MethodGet, "/pipelines", "", map[string]string{apiKeyHeader: "k2"})
+
+	w := doTenantRequest(http.MethodGet, "/metrics", "", map[string]string{apiKeyHeader: "k1"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`cleango_requests_total{tenant="crm",status="2xx"} 1`,
+		`cleango_requests_total{tenant="crm",status="4xx"} 1`,
+		`cleango_rows_processed_total{tenant="crm"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "billing") {
+		t.Errorf("expected only the metrics of the tenant, got:\n%s", body)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName identifies the spans started by the API server
+const tracerName = "github.com/mstgnz/cleango/cmd/api"
+
+// tracer starts the spans of requests and file reads and writes; it is a no-op unless tracing is configured
+var tracer trace.Tracer = noop.NewTracerProvider().Tracer(tracerName)
+
+// operationObserver reports cleaning operations as spans (nil: tracing disabled)
+var operationObserver cleaner.Observer
+
+// propagator reads the W3C trace context of incoming requests
+var propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// tracingFromEnv reads OTEL_TRACES_EXPORTER: otlp exports spans over OTLP/HTTP (configured with the
+// standard OTEL_EXPORTER_OTLP_* variables), console writes them to stdout and none or an empty value
+// disables tracing, in which case a nil provider is returned.
+func tracingFromEnv(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch name := os.Getenv("OTEL_TRACES_EXPORTER"); name {
+	case "", "none":
+		return nil, nil
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx)
+	case "console":
+		exporter, err = stdouttrace.New()
+	default:
+		return nil, fmt.Errorf("unsupported OTEL_TRACES_EXPORTER: %q", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the default service name
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", "cleango-api")),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+	return sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res)), nil
+}
+
+// enableTracing makes the handlers report spans to provider
+func enableTracing(provider trace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	tracer = provider.Tracer(tracerName)
+	operationObserver = cleaner.NewTracingObserver(tracer)
+}
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// withTracing starts a server span for every request, continuing the trace of the caller if the
+// request carries a traceparent header. The span is named after the matched route.
+func withTracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("cleango.request_id", requestID(r)),
+				attribute.String("cleango.tenant", requestTenant(r)),
+			),
+		)
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		req := r.WithContext(ctx)
+		next.ServeHTTP(recorder, req)
+
+		if req.Pattern != "" {
+			span.SetName(req.Pattern)
+			span.SetAttributes(attribute.String("http.route", req.Pattern))
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", recorder.status))
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+	})
+}
+
+// startIOSpan starts the span of reading or writing data in the given format
+func startIOSpan(ctx context.Context, operation, format string) trace.Span {
+	_, span := tracer.Start(ctx, "cleango."+operation, trace.WithAttributes(
+		attribute.String("cleango.operation", operation),
+		attribute.String("cleango.format", format),
+	))
+	return span
+}
+
+// endIOSpan records the shape of the data read or written, or the error, and ends the span
+func endIOSpan(span trace.Span, df *cleaner.DataFrame, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if df != nil {
+		rows, columns := df.Shape()
+		span.SetAttributes(attribute.Int("cleango.rows", rows), attribute.Int("cleango.columns", columns))
+	}
+	span.End()
+}
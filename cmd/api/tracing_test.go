@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// useTestTracing records the spans of the test and restores the disabled tracing afterwards
+func useTestTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousTracer, previousObserver := tracer, operationObserver
+	enableTracing(provider)
+	t.Cleanup(func() {
+		tracer, operationObserver = previousTracer, previousObserver
+	})
+	return recorder
+}
+
+func TestWithTracing(t *testing.T) {
+	recorder := useTestTracing(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/clean", handleClean)
+
+	body := `{"data":[{"name":" Ali "}],"actions":["trim"]}`
+	req := httptest.NewRequest(http.MethodPost, "/clean", bytes.NewBufferString(body))
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	withRequestID(withTracing(mux)).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	spans := recorder.Ended()
+	var server sdktrace.ReadOnlySpan
+	names := make(map[string]bool)
+	for _, span := range spans {
+		names[span.Name()] = true
+		if span.SpanKind() == trace.SpanKindServer {
+			server = span
+		}
+	}
+	if server == nil {
+		t.Fatalf("expected a server span, got %v", names)
+	}
+	if server.Name() != "/clean" {
+		t.Errorf("expected the span to be named after the route, got %s", server.Name())
+	}
+	if server.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected the caller's trace to be continued, got %s", server.SpanContext().TraceID())
+	}
+	for _, name := range []string{"cleango.read", "cleango.pipeline"} {
+		if !names[name] {
+			t.Errorf("expected a %s span, got %v", name, names)
+		}
+	}
+	for _, span := range spans {
+		if span.SpanContext().TraceID() != server.SpanContext().TraceID() {
+			t.Errorf("span %s is not part of the request trace", span.Name())
+		}
+	}
+}
+
+func TestWithTracing_ServerError(t *testing.T) {
+	recorder := useTestTracing(t)
+
+	handler := withTracing(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error {
+		t.Errorf("expected one failed span, got %v", spans)
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+var (
+	// ErrJobNotFound is returned for a job that does not exist or was already purged
+	ErrJobNotFound = errors.New("job not found")
+	// ErrQuotaExceeded is returned when the output of a job alone exceeds the workspace quota
+	ErrQuotaExceeded = errors.New("output quota exceeded")
+)
+
+// jobIDPattern, IDs generated by newJob; anything else is rejected before touching the file system
+var jobIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// workspace, directory holding the outputs of /clean-file, one subdirectory per job. Jobs of the
+// default tenant are stored at the top and jobs of other tenants under tenants/<tenant>. Jobs
+// older than the TTL are removed by collect, and the oldest jobs of a tenant are evicted when the
+// tenant exceeds its quota.
+type workspace struct {
+	mu     sync.Mutex // Serializes creating, evicting and collecting jobs
+	dir    string
+	ttl    time.Duration
+	quota  int64            // Total bytes of the jobs of a tenant, 0 for no limit
+	quotas map[string]int64 // Quotas of tenants that differ from quota
+}
+
+// jobs, output workspace used by the handlers (nil: outputs are written where requested)
+var jobs *workspace
+
+// workspaceFromEnv reads OUTPUT_DIR, OUTPUT_TTL, OUTPUT_QUOTA and TENANT_QUOTAS, a comma-separated
+// list of tenant=bytes pairs. When OUTPUT_DIR is empty, the workspace is disabled.
+func workspaceFromEnv() (*workspace, error) {
+	dir := os.Getenv("OUTPUT_DIR")
+	if dir == "" {
+		return nil, nil
+	}
+
+	ttl := 24 * time.Hour
+	if v := os.Getenv("OUTPUT_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid OUTPUT_TTL: %q", v)
+		}
+		ttl = d
+	}
+
+	var quota int64
+	if v := os.Getenv("OUTPUT_QUOTA"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid OUTPUT_QUOTA: %q", v)
+		}
+		quota = n
+	}
+
+	ws, err := newWorkspace(dir, ttl, quota)
+	if err != nil {
+		return nil, err
+	}
+	if v := os.Getenv("TENANT_QUOTAS"); v != "" {
+		ws.quotas = make(map[string]int64)
+		for _, pair := range strings.Split(v, ",") {
+			tenant, size, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			n, err := strconv.ParseInt(size, 10, 64)
+			if err != nil || n < 0 || !pipelineNamePattern.MatchString(tenant) {
+				return nil, fmt.Errorf("invalid TENANT_QUOTAS entry: %q", pair)
+			}
+			ws.quotas[tenant] = n
+		}
+	}
+	return ws, nil
+}
+
+// newWorkspace creates the workspace directory if needed
+func newWorkspace(dir string, ttl time.Duration, quota int64) (*workspace, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	return &workspace{dir: dir, ttl: ttl, quota: quota}, nil
+}
+
+// tenantDir returns the directory of the jobs of the tenant
+func (ws *workspace) tenantDir(tenant string) string {
+	if tenant == "" {
+		return ws.dir
+	}
+	return filepath.Join(ws.dir, "tenants", tenant)
+}
+
+// quotaFor returns the quota of the tenant
+func (ws *workspace) quotaFor(tenant string) int64 {
+	if quota, ok := ws.quotas[tenant]; ok {
+		return quota
+	}
+	return ws.quota
+}
+
+// newJob creates the directory of a new job of the tenant and returns its ID and path
+func (ws *workspace) newJob(tenant string) (string, string, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	id := newRequestID()
+	if id == "" {
+		return "", "", errors.New("failed to generate a job ID")
+	}
+	dir := filepath.Join(ws.tenantDir(tenant), id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", err
+	}
+	return id, dir, nil
+}
+
+// Delete removes the job of the tenant and all its files. Jobs of other tenants are not found.
+func (ws *workspace) Delete(tenant, id string) error {
+	if !jobIDPattern.MatchString(id) {
+		return ErrJobNotFound
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	dir := filepath.Join(ws.tenantDir(tenant), id)
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return ErrJobNotFound
+	}
+	return os.RemoveAll(dir)
+}
+
+// jobInfo, size and age of a job directory
+type jobInfo struct {
+	id       string
+	size     int64
+	modified time.Time
+}
+
+// list returns the jobs of the tenant, oldest first. Caller must hold ws.mu.
+func (ws *workspace) list(tenant string) ([]jobInfo, error) {
+	root := ws.tenantDir(tenant)
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []jobInfo
+	for _, entry := range entries {
+		if !entry.IsDir() || !jobIDPattern.MatchString(entry.Name()) {
+			continue
+		}
+		job := jobInfo{id: entry.Name()}
+		err := filepath.WalkDir(filepath.Join(root, job.id), func(_ string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				job.size += info.Size()
+			}
+			if info.ModTime().After(job.modified) {
+				job.modified = info.ModTime()
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		list = append(list, job)
+	}
+	sort.Slice(list, func(a, b int) bool { return list[a].modified.Before(list[b].modified) })
+	return list, nil
+}
+
+// usage returns the total bytes of the jobs of the tenant
+func (ws *workspace) usage(tenant string) (int64, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	list, err := ws.list(tenant)
+	var total int64
+	for _, job := range list {
+		total += job.size
+	}
+	return total, err
+}
+
+// Enforce evicts the oldest other jobs of the tenant until its jobs fit its quota. If the job alone
+// exceeds the quota, it is removed as well and ErrQuotaExceeded is returned.
+func (ws *workspace) Enforce(tenant, id string) error {
+	quota := ws.quotaFor(tenant)
+	if quota <= 0 {
+		return nil
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	root := ws.tenantDir(tenant)
+	list, err := ws.list(tenant)
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, job := range list {
+		total += job.size
+	}
+	for _, job := range list {
+		if total <= quota {
+			return nil
+		}
+		if job.id == id || job.size == 0 {
+			// Empty jobs are still running and freeing them gains nothing
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(root, job.id)); err != nil {
+			return err
+		}
+		total -= job.size
+	}
+	if total <= quota {
+		return nil
+	}
+	os.RemoveAll(filepath.Join(root, id))
+	return fmt.Errorf("%w: %d bytes, quota %d bytes", ErrQuotaExceeded, total, quota)
+}
+
+// collect removes the jobs of all tenants not modified within the TTL and returns how many were removed
+func (ws *workspace) collect() (int, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	names := []string{""}
+	entries, err := os.ReadDir(filepath.Join(ws.dir, "tenants"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+
+	removed := 0
+	for _, tenant := range names {
+		list, err := ws.list(tenant)
+		if err != nil {
+			return removed, err
+		}
+		for _, job := range list {
+			if time.Since(job.modified) <= ws.ttl {
+				break
+			}
+			if err := os.RemoveAll(filepath.Join(ws.tenantDir(tenant), job.id)); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// run collects expired jobs every tenth of the TTL, but at least every minute, until ctx is done
+func (ws *workspace) run(ctx context.Context) {
+	ticker := time.NewTicker(max(min(ws.ttl/10, time.Minute), time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := ws.collect(); err != nil {
+				log.Print(i18n.T(language, "Output cleanup failed: %v", err))
+			}
+		}
+	}
+}
+
+// handleJob, purges the output files of a job
+func handleJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, r, CodeMethodNotAllowed, "Only DELETE requests are supported", nil)
+		return
+	}
+	if jobs == nil {
+		writeError(w, r, CodeJobNotFound, "Job not found", nil)
+		return
+	}
+	if err := jobs.Delete(requestTenant(r), r.PathValue("id")); err != nil {
+		if errors.Is(err, ErrJobNotFound) {
+			writeError(w, r, CodeJobNotFound, "Job not found", nil)
+			return
+		}
+		writeError(w, r, CodeInternal, "Job could not be deleted", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withWorkspace swaps the package workspace for the duration of a test
+func withWorkspace(t *testing.T, ws *workspace) {
+	t.Helper()
+	saved := jobs
+	jobs = ws
+	t.Cleanup(func() { jobs = saved })
+}
+
+// writeJob creates a job with a file of size bytes, modified at the given time
+func writeJob(t *testing.T, ws *workspace, size int, modified time.Time) string {
+	t.Helper()
+	id, dir, err := ws.newJob("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "out.csv")
+	if err := os.WriteFile(path, bytes.Repeat([]byte("x"), size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(path, modified, modified)
+	os.Chtimes(dir, modified, modified)
+	return id
+}
+
+func jobExists(ws *workspace, id string) bool {
+	_, err := os.Stat(filepath.Join(ws.dir, id))
+	return err == nil
+}
+
+func TestWorkspace_Collect(t *testing.T) {
+	ws, _ := newWorkspace(t.TempDir(), time.Hour, 0)
+	old := writeJob(t, ws, 10, time.Now().Add(-2*time.Hour))
+	recent := writeJob(t, ws, 10, time.Now())
+
+	removed, err := ws.collect()
+	if err != nil || removed != 1 {
+		t.Fatalf("expected 1 job removed, got %d, %v", removed, err)
+	}
+	if jobExists(ws, old) || !jobExists(ws, recent) {
+		t.Error("expected only the expired job to be removed")
+	}
+}
+
+func TestWorkspace_Enforce(t *testing.T) {
+	ws, _ := newWorkspace(t.TempDir(), time.Hour, 100)
+	oldest := writeJob(t, ws, 40, time.Now().Add(-3*time.Minute))
+	older := writeJob(t, ws, 40, time.Now().Add(-2*time.Minute))
+	current := writeJob(t, ws, 40, time.Now())
+
+	if err := ws.Enforce("", current); err != nil {
+		t.Fatalf("Enforce error: %v", err)
+	}
+	if jobExists(ws, oldest) || !jobExists(ws, older) || !jobExists(ws, current) {
+		t.Error("expected only the oldest job to be evicted")
+	}
+
+	huge := writeJob(t, ws, 200, time.Now())
+	if err := ws.Enforce("", huge); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if jobExists(ws, huge) {
+		t.Error("expected the job over the quota to be removed")
+	}
+}
+
+func TestHandleCleanFile_Workspace(t *testing.T) {
+	ws, _ := newWorkspace(t.TempDir(), time.Hour, 0)
+	withWorkspace(t, ws)
+
+	dir := tempWorkDir(t)
+	input := filepath.Join(dir, "in.csv")
+	if err := os.WriteFile(input, []byte("name\n  Ali  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(FileCleanRequest{FilePath: input, Actions: []string{"trim"}, Output: "../../escape.csv"})
+	w := httptest.NewRecorder()
+	handleCleanFile(w, httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBuffer(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Output string `json:"output"`
+		JobID  string `json:"job_id"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if want := filepath.Join(ws.dir, resp.JobID, "escape.csv"); resp.JobID == "" || resp.Output != want {
+		t.Fatalf("expected the output in the job directory, got %+v", resp)
+	}
+	if content, _ := os.ReadFile(resp.Output); string(content) != "name\nAli\n" {
+		t.Errorf("unexpected output: %q", content)
+	}
+
+	del := func(id string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/jobs/"+id, nil)
+		req.SetPathValue("id", id)
+		w := httptest.NewRecorder()
+		handleJob(w, req)
+		return w.Code
+	}
+	if code := del(resp.JobID); code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", code)
+	}
+	if jobExists(ws, resp.JobID) {
+		t.Error("expected the job to be purged")
+	}
+	if code := del(resp.JobID); code != http.StatusNotFound {
+		t.Errorf("expected 404 for a purged job, got %d", code)
+	}
+	if code := del(strings.Repeat(".", 32)); code != http.StatusNotFound {
+		t.Errorf("expected 404 for an invalid job ID, got %d", code)
+	}
+}
+
+func TestHandleCleanFile_WorkspaceFailedJobRemoved(t *testing.T) {
+	ws, _ := newWorkspace(t.TempDir(), time.Hour, 0)
+	withWorkspace(t, ws)
+
+	dir := tempWorkDir(t)
+	body, _ := json.Marshal(FileCleanRequest{FilePath: filepath.Join(dir, "missing.csv"), Actions: []string{"trim"}})
+	w := httptest.NewRecorder()
+	handleCleanFile(w, httptest.NewRequest(http.MethodPost, "/clean-file", bytes.NewBuffer(body)))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+	if entries, _ := os.ReadDir(ws.dir); len(entries) != 0 {
+		t.Errorf("expected the failed job to be removed, found %d entries", len(entries))
+	}
+}
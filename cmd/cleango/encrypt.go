@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/mstgnz/cleango/pkg/formats"
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+// parseEncryptFor parses the --encrypt-for flag: comma-separated age recipients, or env:NAME for
+// an age passphrase in the environment variable NAME. The passphrase is read from the environment
+// so it does not show up in the shell history or the process list.
+func parseEncryptFor(value string) (formats.Encryptor, error) {
+	if name, ok := strings.CutPrefix(value, "env:"); ok {
+		passphrase := os.Getenv(name)
+		if passphrase == "" {
+			return nil, errors.New(i18n.T(language, "environment variable %s of the encryption passphrase is not set", name))
+		}
+		return formats.AgePassphrase(passphrase)
+	}
+
+	var recipients []string
+	for _, recipient := range strings.Split(value, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return formats.AgeRecipients(recipients...)
+}
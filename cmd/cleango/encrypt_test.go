@@ -0,0 +1,92 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/formats"
+)
+
+// decryptFile returns the decrypted content of a file encrypted for the identity
+func decryptFile(t *testing.T, path, identity string) string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+	r, err := formats.DecryptAge(file, identity)
+	if err != nil {
+		t.Fatalf("DecryptAge failed: %v", err)
+	}
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to decrypt %s: %v", path, err)
+	}
+	return string(content)
+}
+
+func TestRunClean_EncryptFor(t *testing.T) {
+	identity, recipient, err := formats.GenerateAgeIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, stream := range []bool{false, true} {
+		dir := t.TempDir()
+		input := filepath.Join(dir, "people.csv")
+		os.WriteFile(input, []byte("name,age\n Alice ,30\nBob,250\n"), 0644)
+		output := filepath.Join(dir, "clean.csv")
+		args := []string{"-trim", "-encrypt-for", recipient, "-output", output}
+		if stream {
+			args = append(args, "-stream")
+		} else {
+			args = append(args, "-outlier", "age:0:120", "-rejects", filepath.Join(dir, "rejects.csv"))
+		}
+
+		if err := runClean(append(args, input)); err != nil {
+			t.Fatalf("runClean error: %v", err)
+		}
+		raw, _ := os.ReadFile(output)
+		if strings.Contains(string(raw), "Alice") {
+			t.Errorf("stream %v: output is not encrypted", stream)
+		}
+		if content := decryptFile(t, output, identity); !strings.HasPrefix(content, "name,age\nAlice,30\n") {
+			t.Errorf("stream %v: unexpected output %q", stream, content)
+		}
+		if !stream {
+			if content := decryptFile(t, filepath.Join(dir, "rejects.csv"), identity); !strings.Contains(content, "Bob") {
+				t.Errorf("expected Bob in the rejects file, got %q", content)
+			}
+		}
+	}
+}
+
+func TestParseEncryptFor(t *testing.T) {
+	t.Setenv("CLEANGO_TEST_PASSPHRASE", "correct horse battery staple")
+
+	encryptor, err := parseEncryptFor("env:CLEANGO_TEST_PASSPHRASE")
+	if err != nil {
+		t.Fatalf("parseEncryptFor failed: %v", err)
+	}
+	var buf strings.Builder
+	w, _ := encryptor.Encrypt(&buf)
+	w.Write([]byte("secret"))
+	w.Close()
+	r, err := formats.DecryptAgePassphrase(strings.NewReader(buf.String()), "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("DecryptAgePassphrase failed: %v", err)
+	}
+	if content, _ := io.ReadAll(r); string(content) != "secret" {
+		t.Errorf("decrypted = %q", content)
+	}
+
+	for _, value := range []string{"env:CLEANGO_TEST_MISSING", "age1invalid", ","} {
+		if _, err := parseEncryptFor(value); err == nil {
+			t.Errorf("expected an error for %q", value)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+// errPipelineInvalid is returned by lint when the pipeline spec has problems
+var errPipelineInvalid = errors.New("pipeline is invalid")
+
+// runLint parses flags and args, then checks a pipeline spec file without reading or writing any
+// data to clean. With --sample or --schema, the columns referenced by the actions are checked too.
+func runLint(args []string) error {
+	lintCmd := flag.NewFlagSet("lint", flag.ContinueOnError)
+
+	sampleFlag := lintCmd.String("sample", "", i18n.T(language, "Data file whose columns the actions are checked against"))
+	schemaFlag := lintCmd.String("schema", "", i18n.T(language, "JSON schema file written by cleango schema --json whose columns the actions are checked against"))
+	profilesFlag := lintCmd.String("profiles", "", i18n.T(language, "YAML or JSON file with column profiles for the apply_profile action"))
+	jsonFlag := lintCmd.Bool("json", false, i18n.T(language, "Print the problems as JSON"))
+	langFlag := lintCmd.String("lang", string(i18n.FromEnv()), i18n.T(language, "Language of the messages (en, tr)"))
+
+	if err := lintCmd.Parse(args); err != nil {
+		return err
+	}
+	language = i18n.Parse(*langFlag)
+
+	positional := lintCmd.Args()
+	if len(positional) < 1 {
+		return errors.New(i18n.T(language, "pipeline file not specified — usage: cleango lint [--sample <file>|--schema <file>] [flags] <pipeline>"))
+	}
+	pipelineFile := positional[0]
+
+	if *profilesFlag != "" {
+		if err := cleaner.LoadProfiles(*profilesFlag); err != nil {
+			return err
+		}
+	}
+	spec, err := cleaner.LoadPipelineSpec(pipelineFile)
+	if err != nil {
+		return err
+	}
+
+	var columns []string
+	switch {
+	case *sampleFlag != "":
+		inputFormat := getFileFormat(*sampleFlag)
+		if inputFormat == "" {
+			return errors.New(i18n.T(language, "unsupported file format — supported: .csv, .json, .xlsx, .parquet"))
+		}
+		df, err := readFile(*sampleFlag, inputFormat, nil, nil, nil, nil)
+		if err != nil {
+			return fmt.Errorf(i18n.T(language, "read error: %w"), err)
+		}
+		columns = df.Headers
+	case *schemaFlag != "":
+		schema, err := readSchemaFile(*schemaFlag)
+		if err != nil {
+			return fmt.Errorf(i18n.T(language, "read error: %w"), err)
+		}
+		columns = schema.Columns
+	}
+
+	issues := spec.Lint(columns)
+	if *jsonFlag {
+		if issues == nil {
+			issues = []cleaner.LintIssue{}
+		}
+		if err := printJSON(issues); err != nil {
+			return err
+		}
+	} else {
+		printLintIssues(os.Stdout, pipelineFile, len(spec.Actions), issues)
+	}
+	if len(issues) > 0 {
+		return errPipelineInvalid
+	}
+	return nil
+}
+
+// readSchemaFile reads the first schema of a file written by schema --json, which holds a list of
+// schemas, or a single schema
+func readSchemaFile(path string) (*cleaner.FileSchema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var schemas []cleaner.FileSchema
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		var schema cleaner.FileSchema
+		if err := json.Unmarshal(data, &schema); err != nil {
+			return nil, fmt.Errorf("invalid schema file: %w", err)
+		}
+		return &schema, nil
+	}
+	if len(schemas) == 0 {
+		return nil, errors.New("invalid schema file: no schema")
+	}
+	return &schemas[0], nil
+}
+
+// printLintIssues prints the problems of a pipeline spec, in action order
+func printLintIssues(w io.Writer, path string, actions int, issues []cleaner.LintIssue) {
+	if len(issues) == 0 {
+		fmt.Fprintln(w, i18n.T(language, "%s: %d actions, no problems found", path, actions))
+		return
+	}
+	fmt.Fprintln(w, i18n.T(language, "%s: %d problems found", path, len(issues)))
+	for _, issue := range issues {
+		if issue.Action < 0 {
+			fmt.Fprintf(w, "  %s\n", issue.Message)
+			continue
+		}
+		fmt.Fprintln(w, i18n.T(language, "  action %d (%s): %s", issue.Action, issue.Text, issue.Message))
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+)
+
+func TestRunLint_NoPipelineFile(t *testing.T) {
+	if err := runLint(nil); err == nil || !strings.Contains(err.Error(), "pipeline file not specified") {
+		t.Errorf("expected usage error, got %v", err)
+	}
+}
+
+func TestRunLint(t *testing.T) {
+	dir := t.TempDir()
+	pipeline := filepath.Join(dir, "pipeline.yaml")
+	sample := filepath.Join(dir, "sample.csv")
+	schema := filepath.Join(dir, "schema.json")
+	os.WriteFile(pipeline, []byte("actions:\n  - trim\n  - normalize_case:city=upper\n"), 0644)
+	os.WriteFile(sample, []byte("id,city\n1,izmir\n"), 0644)
+	os.WriteFile(schema, []byte(`[{"path":"sample.csv","rows":1,"columns":["id","town"]}]`), 0644)
+
+	if err := runLint([]string{pipeline}); err != nil {
+		t.Fatalf("expected a valid pipeline, got %v", err)
+	}
+	if err := runLint([]string{"-sample", sample, pipeline}); err != nil {
+		t.Fatalf("expected a valid pipeline for the sample, got %v", err)
+	}
+	if err := runLint([]string{"-schema", schema, "-json", pipeline}); !errors.Is(err, errPipelineInvalid) {
+		t.Errorf("expected errPipelineInvalid for a missing column, got %v", err)
+	}
+
+	os.WriteFile(pipeline, []byte("actions:\n  - trim\n  - fill_nulls:age=average\n"), 0644)
+	if err := runLint([]string{pipeline}); !errors.Is(err, errPipelineInvalid) {
+		t.Errorf("expected errPipelineInvalid for an invalid action, got %v", err)
+	}
+}
+
+func TestPrintLintIssues(t *testing.T) {
+	var buf bytes.Buffer
+	printLintIssues(&buf, "pipeline.yaml", 3, nil)
+	if want := "pipeline.yaml: 3 actions, no problems found\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+
+	buf.Reset()
+	printLintIssues(&buf, "pipeline.yaml", 3, []cleaner.LintIssue{
+		{Action: 2, Text: "normalize_case:city=upper", Message: "column not found: city"},
+	})
+	want := "pipeline.yaml: 1 problems found\n  action 2 (normalize_case:city=upper): column not found: city\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
@@ -4,32 +4,56 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/mstgnz/cleango/pkg/cleaner"
 	"github.com/mstgnz/cleango/pkg/formats"
+	"github.com/mstgnz/cleango/pkg/i18n"
 	"github.com/xitongsys/parquet-go/parquet"
 )
 
+// language of the console output, set from CLEANGO_LANG and the --lang flag
+var language = i18n.FromEnv()
+
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: cleango <command> [arguments]")
-		fmt.Println("Commands:")
-		fmt.Println("  clean    Performs data cleaning operation")
+		fmt.Println(i18n.T(language, "Usage: cleango <command> [arguments]"))
+		fmt.Println(i18n.T(language, "Commands:"))
+		fmt.Println(i18n.T(language, "  clean    Performs data cleaning operation"))
+		fmt.Println(i18n.T(language, "  profile  Scores the data quality of every column"))
+		fmt.Println(i18n.T(language, "  schema   Prints or compares the columns and types of files"))
+		fmt.Println(i18n.T(language, "  lint     Checks a pipeline file without touching data"))
 		os.Exit(1)
 	}
 
 	switch os.Args[1] {
 	case "clean":
 		if err := runClean(os.Args[2:]); err != nil {
-			fmt.Println("Error:", err)
+			fmt.Println(i18n.T(language, "Error: %s", i18n.Error(language, err)))
+			os.Exit(1)
+		}
+	case "profile":
+		if err := runProfile(os.Args[2:]); err != nil {
+			fmt.Println(i18n.T(language, "Error: %s", i18n.Error(language, err)))
+			os.Exit(1)
+		}
+	case "schema":
+		if err := runSchema(os.Args[2:]); err != nil {
+			fmt.Println(i18n.T(language, "Error: %s", i18n.Error(language, err)))
+			os.Exit(1)
+		}
+	case "lint":
+		if err := runLint(os.Args[2:]); err != nil {
+			fmt.Println(i18n.T(language, "Error: %s", i18n.Error(language, err)))
 			os.Exit(1)
 		}
 	default:
-		fmt.Printf("Unknown command %q.\n", os.Args[1])
+		fmt.Println(i18n.T(language, "Unknown command %q.", os.Args[1]))
 		os.Exit(1)
 	}
 }
@@ -39,45 +63,75 @@ func main() {
 func runClean(args []string) error {
 	cleanCmd := flag.NewFlagSet("clean", flag.ContinueOnError)
 
-	trimFlag := cleanCmd.Bool("trim", false, "Clean whitespace at the beginning and end of all cells")
-	dateFormatFlag := cleanCmd.String("date-format", "", "Date format (e.g.: created_at:2006-01-02)")
-	nullReplaceFlag := cleanCmd.String("null-replace", "", "Replace empty values (e.g.: age:0,name:Unknown)")
-	caseFlag := cleanCmd.String("case", "", "Upper/lower case conversion (e.g.: name:upper,description:lower)")
-	outputFlag := cleanCmd.String("output", "", "Output file (default: cleaned_[input])")
-	delimiterFlag := cleanCmd.String("delimiter", ",", "CSV delimiter character")
-	formatFlag := cleanCmd.String("format", "", "Output format (csv, json, excel, parquet)")
-	regexFlag := cleanCmd.String("regex", "", "Cleaning with regex (e.g.: name:[0-9]+:,description:\\s+: )")
-	splitFlag := cleanCmd.String("split", "", "Column splitting (e.g.: full_name: :first_name,last_name)")
-	outlierFlag := cleanCmd.String("outlier", "", "Outlier value filtering (e.g.: age:18:65)")
-	sheetNameFlag := cleanCmd.String("sheet-name", "Sheet1", "Excel worksheet name")
-	compressionFlag := cleanCmd.String("compression", "snappy", "Parquet compression algorithm (snappy, gzip, lz4, zstd, uncompressed)")
-	parallelFlag := cleanCmd.Bool("parallel", false, "Use parallel processing")
-	workersFlag := cleanCmd.Int("workers", 0, "Number of workers for parallel processing (0: as many as CPU cores)")
+	trimFlag := cleanCmd.Bool("trim", false, i18n.T(language, "Clean whitespace at the beginning and end of all cells"))
+	sanitizeFlag := cleanCmd.Bool("sanitize", false, i18n.T(language, "Remove control characters and zero-width spaces and replace exotic spaces such as NBSP in all cells"))
+	dateFormatFlag := cleanCmd.String("date-format", "", i18n.T(language, "Date format (e.g.: created_at:2006-01-02)"))
+	nullReplaceFlag := cleanCmd.String("null-replace", "", i18n.T(language, "Replace empty values (e.g.: age:0,name:Unknown)"))
+	numericFlag := cleanCmd.String("numeric-formats", "", i18n.T(language, "Convert scientific notation, percents, accounting negatives and currency amounts to plain numbers (e.g.: price,discount)"))
+	unitsFlag := cleanCmd.String("convert-units", "", i18n.T(language, "Unit conversion with optional decimals (e.g.: weight:lb:kg:2,temp:F:C)"))
+	fakeFlag := cleanCmd.String("fake", "", i18n.T(language, "Replace values with deterministic fakes (e.g.: customer:name,mail:email,tel:phone,addr:address)"))
+	fakeSeedFlag := cleanCmd.Int64("fake-seed", 0, i18n.T(language, "Seed of the fakes; the same value gets the same fake for the same seed"))
+	caseFlag := cleanCmd.String("case", "", i18n.T(language, "Upper/lower case conversion (e.g.: name:upper,description:lower)"))
+	outputFlag := cleanCmd.String("output", "", i18n.T(language, "Output file (default: cleaned_[input] next to the input or in --output-dir)"))
+	outputDirFlag := cleanCmd.String("output-dir", "", i18n.T(language, "Directory of the output file, created if missing"))
+	forceFlag := cleanCmd.Bool("force", false, i18n.T(language, "Overwrite existing output and rejects files"))
+	delimiterFlag := cleanCmd.String("delimiter", ",", i18n.T(language, "CSV delimiter character"))
+	formatFlag := cleanCmd.String("format", "", i18n.T(language, "Output format (csv, json, excel, parquet)"))
+	regexFlag := cleanCmd.String("regex", "", i18n.T(language, "Cleaning with regex; write ':' and ',' in a pattern as \\: and \\, (e.g.: name:[0-9]+:,description:\\s+: ,time:([0-9]{2})\\:([0-9]{2}):$1h$2)"))
+	regexJSONFlag := cleanCmd.String("regex-json", "", i18n.T(language, "Cleaning with regex as a JSON array of {\"column\", \"pattern\", \"replacement\"} objects, applied after --regex"))
+	splitFlag := cleanCmd.String("split", "", i18n.T(language, "Column splitting (e.g.: full_name: :first_name,last_name)"))
+	outlierFlag := cleanCmd.String("outlier", "", i18n.T(language, "Outlier value filtering (e.g.: age:18:65)"))
+	profilesFlag := cleanCmd.String("profiles", "", i18n.T(language, "YAML or JSON file with column profiles for the apply_profile action"))
+	pipelineFlag := cleanCmd.String("pipeline", "", i18n.T(language, "Pipeline spec file (YAML or JSON) whose actions run before the other cleaning flags"))
+	headersFlag := cleanCmd.String("normalize-headers", "", i18n.T(language, "Rename the columns to database-safe ASCII names after cleaning (snake, camel)"))
+	sheetNameFlag := cleanCmd.String("sheet-name", "Sheet1", i18n.T(language, "Excel worksheet name"))
+	compressionFlag := cleanCmd.String("compression", "snappy", i18n.T(language, "Parquet compression algorithm (snappy, gzip, lz4, zstd, uncompressed)"))
+	streamFlag := cleanCmd.Bool("stream", false, i18n.T(language, "Clean CSV and JSON inputs chunk by chunk instead of loading them into memory"))
+	streamThresholdFlag := cleanCmd.String("stream-threshold", defaultStreamThreshold, i18n.T(language, "Input size above which the input is streamed automatically, e.g. 512MB or 2GiB (0: never)"))
+	parallelFlag := cleanCmd.Bool("parallel", false, i18n.T(language, "Use parallel processing"))
+	decimalFlag := cleanCmd.Bool("decimal", false, i18n.T(language, "Compare and aggregate numbers as exact decimals instead of floating point, e.g. for monetary values"))
+	workersFlag := cleanCmd.Int("workers", 0, i18n.T(language, "Number of workers for parallel processing (0: as many as CPU cores)"))
+	timingsFlag := cleanCmd.Bool("timings", false, i18n.T(language, "Print wall time, rows/sec and peak memory of every operation"))
+	stringColumnsFlag := cleanCmd.String("string-columns", "", i18n.T(language, "Columns written as text even if they look like numbers, e.g. identifiers with leading zeros (e.g.: id,zip)"))
+	provenanceFlag := cleanCmd.Bool("provenance", false, i18n.T(language, "Add the _source_file and _source_row columns to trace rows back to the input file"))
+	rowNumberFlag := cleanCmd.String("row-number", "", i18n.T(language, "Name of a column added with the 1-based number of every input row"))
+	encryptForFlag := cleanCmd.String("encrypt-for", "", i18n.T(language, "Encrypt the output and rejects files with age for comma-separated recipients, or with the passphrase in an environment variable (e.g.: age1...,age1... or env:CLEANGO_PASSPHRASE)"))
+	manifestFlag := cleanCmd.String("manifest", "", i18n.T(language, "JSON manifest file listing the written files with their row counts, SHA-256 checksums, the pipeline hash and timestamps"))
+	rejectsFlag := cleanCmd.String("rejects", "", i18n.T(language, "File for the rows dropped by filters or failing to parse, with a reject_reason column"))
+	langFlag := cleanCmd.String("lang", string(i18n.FromEnv()), i18n.T(language, "Language of the messages (en, tr)"))
 
 	if err := cleanCmd.Parse(args); err != nil {
 		return err
 	}
+	language = i18n.Parse(*langFlag)
 
 	positional := cleanCmd.Args()
 	if len(positional) < 1 {
-		return errors.New("input file not specified — usage: cleango clean [flags] <file>")
+		return errors.New(i18n.T(language, "input file not specified — usage: cleango clean [flags] <file>"))
 	}
 	inputFile := positional[0]
 
 	inputFormat := getFileFormat(inputFile)
 	if inputFormat == "" {
-		return errors.New("unsupported file format — supported: .csv, .json, .xlsx, .parquet")
+		return errors.New(i18n.T(language, "unsupported file format — supported: .csv, .json, .xlsx, .parquet"))
 	}
 
-	outputFile := *outputFlag
 	outputFormat := *formatFlag
-
-	if outputFile == "" {
-		outputFile = "cleaned_" + inputFile
-	}
 	if outputFormat == "" {
 		outputFormat = inputFormat
 	}
+	outputFile, err := outputPath(inputFile, *outputFlag, *outputDirFlag, outputFormat)
+	if err != nil {
+		return fmt.Errorf(i18n.T(language, "write error: %w"), err)
+	}
+	// Refuse before cleaning; the writers check again when they replace the file
+	if !*forceFlag {
+		for _, path := range []string{outputFile, *rejectsFlag, *manifestFlag} {
+			if _, err := os.Stat(path); path != "" && err == nil {
+				return errors.New(i18n.T(language, "output file %s already exists, use --force to overwrite it", path))
+			}
+		}
+	}
 
 	var csvOptions []formats.CSVOption
 	if *delimiterFlag != "" && len(*delimiterFlag) == 1 {
@@ -105,252 +159,482 @@ func runClean(args []string) error {
 		parquetOptions = append(parquetOptions, formats.WithCompression(parquet.CompressionCodec_SNAPPY))
 	}
 
+	var jsonOptions []formats.JSONOption
+	if *provenanceFlag {
+		csvOptions = append(csvOptions, formats.WithProvenance(true))
+		jsonOptions = append(jsonOptions, formats.WithJSONProvenance(true))
+		excelOptions = append(excelOptions, formats.WithExcelProvenance(true))
+		parquetOptions = append(parquetOptions, formats.WithParquetProvenance(true))
+	}
+
+	csvOptions = append(csvOptions, formats.WithOverwrite(*forceFlag))
+	jsonOptions = append(jsonOptions, formats.WithJSONOverwrite(*forceFlag))
+	excelOptions = append(excelOptions, formats.WithExcelOverwrite(*forceFlag))
+	parquetOptions = append(parquetOptions, formats.WithParquetOverwrite(*forceFlag))
+
+	if *encryptForFlag != "" {
+		encryptor, err := parseEncryptFor(*encryptForFlag)
+		if err != nil {
+			return fmt.Errorf(i18n.T(language, "invalid --encrypt-for: %w"), err)
+		}
+		csvOptions = append(csvOptions, formats.WithEncryption(encryptor))
+		jsonOptions = append(jsonOptions, formats.WithJSONEncryption(encryptor))
+		excelOptions = append(excelOptions, formats.WithExcelEncryption(encryptor))
+		parquetOptions = append(parquetOptions, formats.WithParquetEncryption(encryptor))
+	}
+
 	var parallelOptions []func(*cleaner.ParallelOptions)
 	if *workersFlag > 0 {
 		parallelOptions = append(parallelOptions, cleaner.WithMaxWorkers(*workersFlag))
 	}
 
-	var df *cleaner.DataFrame
-	var err error
+	if *decimalFlag {
+		previous := cleaner.GetDefaults()
+		decimal := previous
+		decimal.Decimal = true
+		if err := cleaner.SetDefaults(decimal); err != nil {
+			return err
+		}
+		defer cleaner.SetDefaults(previous)
+	}
 
-	switch inputFormat {
-	case "csv":
-		df, err = cleaner.ReadCSV(inputFile, csvOptions...)
-	case "json":
-		df, err = cleaner.ReadJSON(inputFile)
-	case "excel":
-		df, err = cleaner.ReadExcel(inputFile, excelOptions...)
-	case "parquet":
-		df, err = cleaner.ReadParquet(inputFile, parquetOptions...)
+	var timings *timingRecorder
+	if *timingsFlag {
+		timings = newTimingRecorder()
+		defer timings.Stop()
+	}
+
+	regexRules, err := parseRegexRules(*regexFlag, *regexJSONFlag)
+	if err != nil {
+		return err
+	}
+	if *profilesFlag != "" {
+		if err := cleaner.LoadProfiles(*profilesFlag); err != nil {
+			return err
+		}
+	}
+	var specActions []string
+	if *pipelineFlag != "" {
+		spec, err := cleaner.LoadPipelineSpec(*pipelineFlag)
+		if err != nil {
+			return err
+		}
+		specActions = spec.Actions
+	}
+	var manifest *cleaner.Manifest
+	if *manifestFlag != "" {
+		manifest = cleaner.NewManifest(cleaningHash(cleanCmd, specActions))
+	}
+
+	cli, err := buildPipeline(pipelineFlags{
+		specActions:    specActions,
+		sanitize:       *sanitizeFlag,
+		trim:           *trimFlag,
+		dateFormat:     *dateFormatFlag,
+		nullReplace:    *nullReplaceFlag,
+		numeric:        *numericFlag,
+		units:          *unitsFlag,
+		fake:           *fakeFlag,
+		fakeSeed:       *fakeSeedFlag,
+		caseConversion: *caseFlag,
+		regexRules:     regexRules,
+		split:          *splitFlag,
+		outlier:        *outlierFlag,
+		headers:        *headersFlag,
+		parallel:       *parallelFlag,
+		captureRejects: *rejectsFlag != "",
+	})
+	if err != nil {
+		return err
 	}
+
+	threshold, err := parseByteSize(*streamThresholdFlag)
 	if err != nil {
-		return fmt.Errorf("read error: %w", err)
+		return errors.New(i18n.T(language, "invalid --stream-threshold: %s", *streamThresholdFlag))
+	}
+	var wholeInputFlags []string
+	if *rejectsFlag != "" {
+		wholeInputFlags = append(wholeInputFlags, "--rejects")
+	}
+	if *rowNumberFlag != "" {
+		wholeInputFlags = append(wholeInputFlags, "--row-number")
+	}
+	if *provenanceFlag {
+		wholeInputFlags = append(wholeInputFlags, "--provenance")
+	}
+	if *stringColumnsFlag != "" {
+		wholeInputFlags = append(wholeInputFlags, "--string-columns")
+	}
+	blocker := streamBlocker(inputFormat, outputFormat, cli.pipeline, wholeInputFlags)
+	streaming, err := chooseStreaming(inputFile, *streamFlag, threshold, blocker)
+	if err != nil {
+		return err
+	}
+	if streaming {
+		if err := runStreamClean(cli, inputFile, inputFormat, outputFile, outputFormat, csvOptions, jsonOptions, timings, manifest); err != nil {
+			return err
+		}
+		return writeManifest(manifest, *manifestFlag)
 	}
 
-	if *parallelFlag {
-		if err := applyParallel(df, trimFlag, dateFormatFlag, nullReplaceFlag, caseFlag, regexFlag, outlierFlag, parallelOptions); err != nil {
+	var df *cleaner.DataFrame
+	read := func() error {
+		var err error
+		df, err = readFile(inputFile, inputFormat, csvOptions, jsonOptions, excelOptions, parquetOptions)
+		return err
+	}
+	if err := timings.measure("read", read, func() int { return rowsOf(df) }); err != nil {
+		return fmt.Errorf(i18n.T(language, "read error: %w"), err)
+	}
+	if *stringColumnsFlag != "" {
+		df.WithStringColumns(strings.Split(*stringColumnsFlag, ","))
+	}
+	if *rowNumberFlag != "" {
+		if _, err := df.AddRowNumber(*rowNumberFlag); err != nil {
 			return err
 		}
-	} else {
-		if err := applySerial(df, trimFlag, dateFormatFlag, nullReplaceFlag, caseFlag, regexFlag, splitFlag, outlierFlag); err != nil {
+	}
+
+	df, err = cli.run(df, *parallelFlag, parallelOptions, timings)
+	if err != nil {
+		return err
+	}
+
+	writeAs := func(df *cleaner.DataFrame, path, format string) error {
+		switch format {
+		case "csv":
+			return df.WriteCSV(path, csvOptions...)
+		case "json":
+			return df.WriteJSON(path, jsonOptions...)
+		case "excel":
+			return df.WriteExcel(path, excelOptions...)
+		case "parquet":
+			return df.WriteParquet(path, parquetOptions...)
+		}
+		return nil
+	}
+	write := func() error { return writeAs(df, outputFile, outputFormat) }
+	if err := timings.measure("write", write, func() int { return rowsOf(df) }); err != nil {
+		return fmt.Errorf(i18n.T(language, "write error: %w"), err)
+	}
+
+	if *rejectsFlag != "" {
+		rejectsFormat := getFileFormat(*rejectsFlag)
+		if rejectsFormat == "" {
+			rejectsFormat = outputFormat
+		}
+		if err := writeAs(df.Rejects(), *rejectsFlag, rejectsFormat); err != nil {
+			return fmt.Errorf(i18n.T(language, "rejects write error: %w"), err)
+		}
+		fmt.Println(i18n.T(language, "%d rejected rows written to %s", len(df.Rejects().Data), *rejectsFlag))
+	}
+
+	if manifest != nil {
+		if err := manifest.AddOutput(outputFile, len(df.Data)); err != nil {
+			return fmt.Errorf(i18n.T(language, "manifest write error: %w"), err)
+		}
+		if *rejectsFlag != "" {
+			if err := manifest.AddOutput(*rejectsFlag, len(df.Rejects().Data)); err != nil {
+				return fmt.Errorf(i18n.T(language, "manifest write error: %w"), err)
+			}
+		}
+		if err := writeManifest(manifest, *manifestFlag); err != nil {
 			return err
 		}
 	}
 
-	switch outputFormat {
+	fmt.Println(i18n.T(language, "Cleaned data written to %s", outputFile))
+	rowCount, colCount := df.Shape()
+	fmt.Println(i18n.T(language, "Statistics: %d rows, %d columns", rowCount, colCount))
+	fmt.Println(i18n.T(language, "  Estimated memory: %s", formatBytes(uint64(df.MemoryUsage().Total))))
+	printColumnStats(df)
+	printTimings(timings)
+	return nil
+}
+
+// printTimings prints the timings of the operations, if recorded
+func printTimings(timings *timingRecorder) {
+	if timings != nil {
+		timings.Stop()
+		fmt.Println(i18n.T(language, "Timings:"))
+		timings.Print(os.Stdout)
+	}
+}
+
+// readFile reads a file in the given format
+func readFile(path, format string, csvOptions []formats.CSVOption, jsonOptions []formats.JSONOption, excelOptions []formats.ExcelOption, parquetOptions []formats.ParquetOption) (*cleaner.DataFrame, error) {
+	switch format {
 	case "csv":
-		err = df.WriteCSV(outputFile, csvOptions...)
+		return cleaner.ReadCSV(path, csvOptions...)
 	case "json":
-		err = df.WriteJSON(outputFile)
+		return cleaner.ReadJSON(path, jsonOptions...)
 	case "excel":
-		err = df.WriteExcel(outputFile, excelOptions...)
+		return cleaner.ReadExcel(path, excelOptions...)
 	case "parquet":
-		err = df.WriteParquet(outputFile, parquetOptions...)
+		return cleaner.ReadParquet(path, parquetOptions...)
 	}
-	if err != nil {
-		return fmt.Errorf("write error: %w", err)
+	return nil, nil
+}
+
+// printColumnStats prints the rows dropped and the per-column counts of the pipeline run, in column order
+func printColumnStats(df *cleaner.DataFrame) {
+	stats := df.Stats()
+	if dropped := stats.RowsDropped(); dropped > 0 {
+		fmt.Println(i18n.T(language, "  Rows dropped: %d", dropped))
 	}
 
-	fmt.Printf("Cleaned data written to %s\n", outputFile)
-	rowCount, colCount := df.Shape()
-	fmt.Printf("Statistics: %d rows, %d columns\n", rowCount, colCount)
-	return nil
+	// Columns that no longer exist, e.g. renamed ones, follow in name order
+	columns := slices.Clone(df.Headers)
+	for _, column := range slices.Sorted(maps.Keys(stats.Columns)) {
+		if !slices.Contains(df.Headers, column) {
+			columns = append(columns, column)
+		}
+	}
+	for _, column := range columns {
+		c, ok := stats.Columns[column]
+		if !ok {
+			continue
+		}
+		fmt.Println(i18n.T(language, "  %s: %d modified, %d nulls replaced, %d rows dropped, %d parse failures",
+			column, c.Modified, c.NullsReplaced, c.RowsDropped, c.ParseFailures))
+	}
 }
 
-func applyParallel(df *cleaner.DataFrame, trimFlag *bool, dateFormatFlag, nullReplaceFlag, caseFlag, regexFlag, outlierFlag *string, opts []func(*cleaner.ParallelOptions)) error {
-	if *trimFlag {
-		trimmed, err := df.TrimColumnsParallel(opts...)
-		if err != nil {
-			fmt.Printf("Trim error: %v\n", err)
-		} else {
-			*df = *trimmed
-			fmt.Println("Trim operation applied in parallel")
+// rowsOf returns the number of rows of df, or 0 if it is nil
+func rowsOf(df *cleaner.DataFrame) int {
+	if df == nil {
+		return 0
+	}
+	return len(df.Data)
+}
+
+// cliStep describes a pipeline step for the console output
+type cliStep struct {
+	label   string // Prefix of the error message
+	message string // Message printed when the step succeeds
+}
+
+// cliPipeline is the pipeline of the cleaning flags with the console output of its steps
+type cliPipeline struct {
+	pipeline *cleaner.Pipeline
+	steps    []cliStep
+	failed   []bool
+}
+
+// pipelineFlags are the values of the cleaning flags that buildPipeline turns into steps
+type pipelineFlags struct {
+	specActions    []string    // Actions of the --pipeline spec, run first
+	sanitize       bool        // --sanitize
+	trim           bool        // --trim
+	dateFormat     string      // --date-format
+	nullReplace    string      // --null-replace
+	numeric        string      // --numeric-formats
+	units          string      // --convert-units
+	fake           string      // --fake
+	fakeSeed       int64       // --fake-seed
+	caseConversion string      // --case
+	regexRules     []regexRule // --regex and --regex-json
+	split          string      // --split
+	outlier        string      // --outlier
+	headers        string      // --normalize-headers
+	parallel       bool        // --parallel, reported in the step messages
+	captureRejects bool        // Set with --rejects
+}
+
+// buildPipeline builds one pipeline from the cleaning flags, so consecutive row-wise operations
+// are applied in a single pass. The actions of a pipeline spec run first. A failing operation is
+// reported once and skipped.
+func buildPipeline(flags pipelineFlags) (*cliPipeline, error) {
+	pipeline := cleaner.NewPipeline()
+	if flags.captureRejects {
+		pipeline.CaptureRejects()
+	}
+	var steps []cliStep
+
+	suffix := ""
+	if flags.parallel {
+		suffix = i18n.T(language, " in parallel")
+	}
+
+	for _, action := range flags.specActions {
+		if err := pipeline.Action(action); err != nil {
+			return nil, err
 		}
+		actionType, _, _ := strings.Cut(action, ":")
+		steps = append(steps, cliStep{actionType, i18n.T(language, "Action %s applied%s", action, suffix)})
 	}
 
-	if *dateFormatFlag != "" {
-		parts := strings.SplitN(*dateFormatFlag, ":", 2)
+	if flags.sanitize {
+		pipeline.SanitizeControlChars()
+		steps = append(steps, cliStep{i18n.T(language, "Sanitization"), i18n.T(language, "Control characters removed%s", suffix)})
+	}
+
+	if flags.trim {
+		pipeline.Trim()
+		steps = append(steps, cliStep{i18n.T(language, "Trim"), i18n.T(language, "Trim operation applied%s", suffix)})
+	}
+
+	if flags.dateFormat != "" {
+		parts := strings.SplitN(flags.dateFormat, ":", 2)
 		if len(parts) == 2 {
 			column, layout := parts[0], parts[1]
-			if _, err := df.CleanDatesParallel(column, layout, opts...); err != nil {
-				fmt.Printf("Date cleaning error: %v\n", err)
-			} else {
-				fmt.Printf("Date format cleaning applied in parallel for column %s\n", column)
-			}
+			pipeline.CleanDates(column, layout)
+			steps = append(steps, cliStep{i18n.T(language, "Date cleaning"), i18n.T(language, "Date format cleaning applied%s for column %s", suffix, column)})
 		}
 	}
 
-	if *nullReplaceFlag != "" {
-		for _, replacement := range strings.Split(*nullReplaceFlag, ",") {
+	if flags.nullReplace != "" {
+		for _, replacement := range strings.Split(flags.nullReplace, ",") {
 			parts := strings.SplitN(replacement, ":", 2)
 			if len(parts) == 2 {
 				column, value := parts[0], parts[1]
-				if _, err := df.ReplaceNullsParallel(column, value, opts...); err != nil {
-					fmt.Printf("Null replacement error: %v\n", err)
-				} else {
-					fmt.Printf("Null values in column %s replaced with %s in parallel\n", column, value)
-				}
+				pipeline.ReplaceNulls(column, value)
+				steps = append(steps, cliStep{i18n.T(language, "Null replacement"), i18n.T(language, "Null values in column %s replaced with %s%s", column, value, suffix)})
 			}
 		}
 	}
 
-	if *caseFlag != "" {
-		for _, c := range strings.Split(*caseFlag, ",") {
-			parts := strings.SplitN(c, ":", 2)
-			if len(parts) == 2 {
-				column, caseType := parts[0], parts[1]
-				toUpper := strings.ToLower(caseType) == "upper"
-				if _, err := df.NormalizeCaseParallel(column, toUpper, opts...); err != nil {
-					fmt.Printf("Case conversion error: %v\n", err)
-				} else {
-					caseStr := "lower"
-					if toUpper {
-						caseStr = "upper"
-					}
-					fmt.Printf("%s case conversion applied in parallel for column %s\n", caseStr, column)
-				}
-			}
+	if flags.numeric != "" {
+		for _, column := range strings.Split(flags.numeric, ",") {
+			pipeline.NormalizeNumericFormats(column)
+			steps = append(steps, cliStep{i18n.T(language, "Numeric format normalization"), i18n.T(language, "Numeric formats normalized%s for column %s", suffix, column)})
 		}
 	}
 
-	if *regexFlag != "" {
-		for _, r := range strings.Split(*regexFlag, ",") {
-			parts := strings.SplitN(r, ":", 3)
-			if len(parts) == 3 {
-				column, pattern, replacement := parts[0], parts[1], parts[2]
-				if _, err := df.CleanWithRegexParallel(column, pattern, replacement, opts...); err != nil {
-					fmt.Printf("Regex cleaning error: %v\n", err)
-				} else {
-					fmt.Printf("Regex cleaning applied in parallel for column %s\n", column)
-				}
+	if flags.units != "" {
+		for _, u := range strings.Split(flags.units, ",") {
+			parts := strings.Split(u, ":")
+			if len(parts) != 3 && len(parts) != 4 {
+				continue
 			}
-		}
-	}
-
-	if *outlierFlag != "" {
-		for _, o := range strings.Split(*outlierFlag, ",") {
-			parts := strings.SplitN(o, ":", 3)
-			if len(parts) == 3 {
-				column := parts[0]
-				min, err1 := strconv.ParseFloat(parts[1], 64)
-				max, err2 := strconv.ParseFloat(parts[2], 64)
-				if err1 != nil || err2 != nil {
-					fmt.Println("Outlier filtering error: invalid number")
+			var unitOptions []cleaner.UnitOption
+			if len(parts) == 4 {
+				precision, err := strconv.Atoi(parts[3])
+				if err != nil {
+					fmt.Println(i18n.T(language, "Unit conversion error: invalid precision"))
 					continue
 				}
-				if _, err := df.FilterOutliersParallel(column, min, max, opts...); err != nil {
-					fmt.Printf("Outlier filtering error: %v\n", err)
-				} else {
-					fmt.Printf("Outliers filtered in column %s (min: %g, max: %g) in parallel\n", column, min, max)
-				}
+				unitOptions = append(unitOptions, cleaner.WithUnitPrecision(precision))
 			}
+			pipeline.ConvertUnits(parts[0], parts[1], parts[2], unitOptions...)
+			steps = append(steps, cliStep{i18n.T(language, "Unit conversion"), i18n.T(language, "Column %s converted from %s to %s%s", parts[0], parts[1], parts[2], suffix)})
 		}
 	}
 
-	return nil
-}
-
-func applySerial(df *cleaner.DataFrame, trimFlag *bool, dateFormatFlag, nullReplaceFlag, caseFlag, regexFlag, splitFlag, outlierFlag *string) error {
-	if *trimFlag {
-		df.TrimColumns()
-		fmt.Println("Trim operation applied")
-	}
-
-	if *dateFormatFlag != "" {
-		parts := strings.SplitN(*dateFormatFlag, ":", 2)
-		if len(parts) == 2 {
-			column, layout := parts[0], parts[1]
-			if _, err := df.CleanDates(column, layout); err != nil {
-				fmt.Printf("Date cleaning error: %v\n", err)
-			} else {
-				fmt.Printf("Date format cleaning applied for column %s\n", column)
+	if flags.fake != "" {
+		for _, f := range strings.Split(flags.fake, ",") {
+			parts := strings.SplitN(f, ":", 2)
+			if len(parts) != 2 {
+				continue
 			}
-		}
-	}
-
-	if *nullReplaceFlag != "" {
-		for _, replacement := range strings.Split(*nullReplaceFlag, ",") {
-			parts := strings.SplitN(replacement, ":", 2)
-			if len(parts) == 2 {
-				column, value := parts[0], parts[1]
-				if _, err := df.ReplaceNulls(column, value); err != nil {
-					fmt.Printf("Null replacement error: %v\n", err)
-				} else {
-					fmt.Printf("Null values in column %s replaced with %s\n", column, value)
-				}
+			kind, err := cleaner.ParseFakeKind(parts[1])
+			if err != nil {
+				return nil, err
 			}
+			pipeline.FakeColumn(parts[0], kind, flags.fakeSeed)
+			steps = append(steps, cliStep{i18n.T(language, "Anonymization"), i18n.T(language, "Column %s replaced with fake %s values%s", parts[0], strings.ToLower(parts[1]), suffix)})
 		}
 	}
 
-	if *caseFlag != "" {
-		for _, c := range strings.Split(*caseFlag, ",") {
+	if flags.caseConversion != "" {
+		for _, c := range strings.Split(flags.caseConversion, ",") {
 			parts := strings.SplitN(c, ":", 2)
 			if len(parts) == 2 {
 				column, caseType := parts[0], parts[1]
 				toUpper := strings.ToLower(caseType) == "upper"
-				if _, err := df.NormalizeCase(column, toUpper); err != nil {
-					fmt.Printf("Case conversion error: %v\n", err)
-				} else {
-					caseStr := "lower"
-					if toUpper {
-						caseStr = "upper"
-					}
-					fmt.Printf("%s case conversion applied for column %s\n", caseStr, column)
+				pipeline.NormalizeCase(column, toUpper)
+				message := "lower case conversion applied%s for column %s"
+				if toUpper {
+					message = "upper case conversion applied%s for column %s"
 				}
+				steps = append(steps, cliStep{i18n.T(language, "Case conversion"), i18n.T(language, message, suffix, column)})
 			}
 		}
 	}
 
-	if *regexFlag != "" {
-		for _, r := range strings.Split(*regexFlag, ",") {
-			parts := strings.SplitN(r, ":", 3)
-			if len(parts) == 3 {
-				column, pattern, replacement := parts[0], parts[1], parts[2]
-				if _, err := df.CleanWithRegex(column, pattern, replacement); err != nil {
-					fmt.Printf("Regex cleaning error: %v\n", err)
-				} else {
-					fmt.Printf("Regex cleaning applied for column %s\n", column)
-				}
-			}
-		}
+	for _, rule := range flags.regexRules {
+		pipeline.CleanWithRegex(rule.Column, rule.Pattern, rule.Replacement)
+		steps = append(steps, cliStep{i18n.T(language, "Regex cleaning"), i18n.T(language, "Regex cleaning applied%s for column %s", suffix, rule.Column)})
 	}
 
-	if *splitFlag != "" {
-		for _, s := range strings.Split(*splitFlag, ",") {
+	if flags.split != "" {
+		for _, s := range strings.Split(flags.split, ",") {
 			parts := strings.SplitN(s, ":", 3)
 			if len(parts) >= 3 {
 				column, separator := parts[0], parts[1]
 				newColumns := strings.Split(parts[2], ",")
-				if _, err := df.SplitColumn(column, separator, newColumns); err != nil {
-					fmt.Printf("Column splitting error: %v\n", err)
-				} else {
-					fmt.Printf("Column %s split with %s\n", column, strings.Join(newColumns, ", "))
-				}
+				pipeline.SplitColumn(column, separator, newColumns)
+				steps = append(steps, cliStep{i18n.T(language, "Column splitting"), i18n.T(language, "Column %s split with %s", column, strings.Join(newColumns, ", "))})
 			}
 		}
 	}
 
-	if *outlierFlag != "" {
-		for _, o := range strings.Split(*outlierFlag, ",") {
+	if flags.outlier != "" {
+		for _, o := range strings.Split(flags.outlier, ",") {
 			parts := strings.SplitN(o, ":", 3)
 			if len(parts) == 3 {
 				column := parts[0]
 				min, err1 := strconv.ParseFloat(parts[1], 64)
 				max, err2 := strconv.ParseFloat(parts[2], 64)
 				if err1 != nil || err2 != nil {
-					fmt.Println("Outlier filtering error: invalid number")
+					fmt.Println(i18n.T(language, "Outlier filtering error: invalid number"))
 					continue
 				}
-				if _, err := df.FilterOutliers(column, min, max); err != nil {
-					fmt.Printf("Outlier filtering error: %v\n", err)
-				} else {
-					fmt.Printf("Outliers filtered in column %s (min: %g, max: %g)\n", column, min, max)
-				}
+				pipeline.FilterOutliers(column, min, max)
+				steps = append(steps, cliStep{i18n.T(language, "Outlier filtering"), i18n.T(language, "Outliers filtered in column %s (min: %g, max: %g)%s", column, min, max, suffix)})
 			}
 		}
 	}
 
-	return nil
+	// Headers are normalized last, so the other flags name the input columns
+	if flags.headers != "" {
+		style := cleaner.SnakeCase
+		if strings.ToLower(flags.headers) == "camel" {
+			style = cleaner.CamelCase
+		}
+		pipeline.NormalizeHeaders(style, true)
+		steps = append(steps, cliStep{i18n.T(language, "Header normalization"), i18n.T(language, "Column names normalized to %s case", strings.ToLower(flags.headers))})
+	}
+
+	c := &cliPipeline{pipeline: pipeline, steps: steps, failed: make([]bool, len(steps))}
+	pipeline.OnError(func(err *cleaner.StepError) error {
+		// A streamed pipeline runs once per chunk
+		if !c.failed[err.Index] {
+			c.failed[err.Index] = true
+			fmt.Println(i18n.T(language, "%s error: %s", steps[err.Index].label, i18n.Error(language, err.Err)))
+		}
+		return nil
+	})
+	return c, nil
+}
+
+// run runs the pipeline on df and prints the steps that were applied
+func (c *cliPipeline) run(df *cleaner.DataFrame, parallel bool, opts []func(*cleaner.ParallelOptions), timings *timingRecorder) (*cleaner.DataFrame, error) {
+	pipeline := c.pipeline
+	opts = opts[:len(opts):len(opts)]
+	if !parallel {
+		opts = append(opts, cleaner.WithMaxWorkers(1))
+	}
+	if timings != nil {
+		// Steps run in separate passes so each one is timed on its own
+		pipeline.Unfused()
+		opts = append(opts, cleaner.WithObserver(timings))
+	}
+	result, err := pipeline.Run(df, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.printApplied()
+	return result, nil
+}
+
+// printApplied prints the messages of the steps that did not fail
+func (c *cliPipeline) printApplied() {
+	for i, step := range c.steps {
+		if !c.failed[i] {
+			fmt.Println(step.message)
+		}
+	}
 }
 
 func getFileFormat(filePath string) string {
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+	"github.com/mstgnz/cleango/pkg/i18n"
 )
 
 func TestGetFileFormat(t *testing.T) {
@@ -92,6 +96,20 @@ func TestRunClean_UnsupportedFormat(t *testing.T) {
 	}
 }
 
+func TestRunClean_Language(t *testing.T) {
+	t.Cleanup(func() { language = i18n.DefaultLanguage })
+
+	err := runClean([]string{"--lang", "tr"})
+	if err == nil || !strings.Contains(err.Error(), "girdi dosyası belirtilmedi") {
+		t.Errorf("expected a Turkish error, got: %v", err)
+	}
+
+	err = runClean([]string{})
+	if err == nil || !strings.Contains(err.Error(), "input file") {
+		t.Errorf("expected the language to be reset to English, got: %v", err)
+	}
+}
+
 func TestRunClean_FileNotFound(t *testing.T) {
 	err := runClean([]string{"nonexistent_file.csv"})
 	if err == nil {
@@ -211,6 +229,35 @@ func TestRunClean_FilterOutliers(t *testing.T) {
 	}
 }
 
+func TestRunClean_Decimal(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "payments.csv")
+	if err := os.WriteFile(input, []byte("id,amount\n1,0.30000000000000001\n2,0.1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		expected string
+	}{
+		{nil, "id,amount\n1,0.30000000000000001\n2,0.1\n"},
+		{[]string{"-decimal"}, "id,amount\n2,0.1\n"},
+	} {
+		output := filepath.Join(dir, "cleaned.csv")
+		args := append(tc.args, "-outlier", "amount:0:0.3", "-force", "-output", output, input)
+		if err := runClean(args); err != nil {
+			t.Fatalf("%v: runClean error: %v", tc.args, err)
+		}
+		data, _ := os.ReadFile(output)
+		if string(data) != tc.expected {
+			t.Errorf("%v: unexpected output:\n%s", tc.args, data)
+		}
+	}
+	if cleaner.GetDefaults().Decimal {
+		t.Error("expected the defaults to be restored")
+	}
+}
+
 func TestRunClean_ParallelTrim(t *testing.T) {
 	tmp, err := os.CreateTemp("", "test*.csv")
 	if err != nil {
@@ -239,3 +286,125 @@ func TestRunClean_ParallelTrim(t *testing.T) {
 		t.Error("output file was not created")
 	}
 }
+
+func TestRunClean_Timings(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.csv")
+	if err := os.WriteFile(input, []byte("name,age\n  Alice  ,\n  Bob  ,25\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outputFile := filepath.Join(dir, "output.csv")
+
+	err := runClean([]string{
+		"-trim",
+		"-null-replace", "age:0",
+		"-timings",
+		"-output", outputFile,
+		input,
+	})
+	if err != nil {
+		t.Fatalf("runClean error: %v", err)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if string(content) != "name,age\nAlice,0\nBob,25\n" {
+		t.Errorf("unexpected output: %q", content)
+	}
+}
+
+func TestRunClean_ParallelMatchesSerial(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.csv")
+
+	var content strings.Builder
+	content.WriteString("name,age,created_at\n")
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&content, "  Name %d ,%s,2024/%02d/%02d\n", i, []string{"", "30", "250", "abc"}[i%4], i%12+1, i%28+1)
+	}
+	if err := os.WriteFile(input, []byte(content.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var outputs []string
+	for _, extra := range [][]string{nil, {"-parallel", "-workers", "3"}, {"-parallel", "-workers", "8"}} {
+		output := filepath.Join(dir, fmt.Sprintf("output%d.csv", len(outputs)))
+		args := append([]string{
+			"-trim",
+			"-null-replace", "age:0",
+			"-date-format", "created_at:2006-01-02",
+			"-case", "name:upper",
+			"-outlier", "age:0:120",
+			"-output", output,
+		}, extra...)
+		if err := runClean(append(args, input)); err != nil {
+			t.Fatalf("runClean error: %v", err)
+		}
+
+		b, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs = append(outputs, string(b))
+	}
+
+	for i := 1; i < len(outputs); i++ {
+		if outputs[i] != outputs[0] {
+			t.Errorf("parallel output %d differs from the serial output", i)
+		}
+	}
+}
+
+func TestRunClean_Rejects(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "people.csv")
+	os.WriteFile(input, []byte("name,age\nAlice,30\nBob,250\n"), 0644)
+	outputFile := filepath.Join(dir, "clean.csv")
+	rejectsFile := filepath.Join(dir, "rejects.json")
+
+	err := runClean([]string{
+		"-outlier", "age:0:120",
+		"-rejects", rejectsFile,
+		"-output", outputFile,
+		input,
+	})
+	if err != nil {
+		t.Fatalf("runClean error: %v", err)
+	}
+
+	content, err := os.ReadFile(rejectsFile)
+	if err != nil {
+		t.Fatalf("rejects file was not created: %v", err)
+	}
+	if !strings.Contains(string(content), "Bob") || !strings.Contains(string(content), "reject_reason") {
+		t.Errorf("expected Bob with a reject reason in the rejects file, got %s", content)
+	}
+	output, _ := os.ReadFile(outputFile)
+	if strings.Contains(string(output), "Bob") {
+		t.Errorf("expected Bob to be removed from the output, got %s", output)
+	}
+}
+
+func TestRunClean_Pipeline(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.csv")
+	output := filepath.Join(dir, "out.csv")
+	spec := filepath.Join(dir, "pipeline.yaml")
+	os.WriteFile(input, []byte("name,age\n ali ,\n"), 0644)
+	os.WriteFile(spec, []byte("actions:\n  - trim\n  - replace_nulls:age=0\n"), 0644)
+
+	if err := runClean([]string{"-pipeline", spec, "-case", "name:upper", "-output", output, input}); err != nil {
+		t.Fatalf("runClean error: %v", err)
+	}
+	data, _ := os.ReadFile(output)
+	if string(data) != "name,age\nALI,0\n" {
+		t.Errorf("unexpected output: %q", data)
+	}
+
+	os.WriteFile(spec, []byte("actions:\n  - unknown\n"), 0644)
+	if err := runClean([]string{"-pipeline", spec, "-output", output, input}); err == nil {
+		t.Error("expected an error for an unknown action")
+	}
+}
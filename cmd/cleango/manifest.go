@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+// manifestIgnoredFlags are the clean flags that do not change the cleaned data, so they are left
+// out of the pipeline hash of the manifest
+var manifestIgnoredFlags = map[string]bool{
+	"output":           true,
+	"output-dir":       true,
+	"force":            true,
+	"manifest":         true,
+	"rejects":          true,
+	"encrypt-for":      true,
+	"pipeline":         true, // Its actions are hashed instead of its path
+	"stream":           true,
+	"stream-threshold": true,
+	"parallel":         true,
+	"workers":          true,
+	"timings":          true,
+	"lang":             true,
+}
+
+// cleaningHash returns the pipeline hash of the manifest: the hash of the actions of the pipeline
+// spec followed by the cleaning flags that were set, in name order
+func cleaningHash(flags *flag.FlagSet, specActions []string) string {
+	actions := append([]string{}, specActions...)
+	flags.Visit(func(f *flag.Flag) {
+		if !manifestIgnoredFlags[f.Name] {
+			actions = append(actions, "--"+f.Name+"="+f.Value.String())
+		}
+	})
+	return cleaner.PipelineHash(actions)
+}
+
+// writeManifest writes the manifest to path, if one is requested
+func writeManifest(manifest *cleaner.Manifest, path string) error {
+	if manifest == nil {
+		return nil
+	}
+	if err := manifest.Write(path); err != nil {
+		return fmt.Errorf(i18n.T(language, "manifest write error: %w"), err)
+	}
+	fmt.Println(i18n.T(language, "Manifest written to %s", path))
+	return nil
+}
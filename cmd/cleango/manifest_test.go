@@ -0,0 +1,67 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+)
+
+// readManifest decodes the manifest file
+func readManifest(t *testing.T, path string) cleaner.Manifest {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("manifest was not written: %v", err)
+	}
+	var manifest cleaner.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	return manifest
+}
+
+func TestRunClean_Manifest(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "people.csv")
+	os.WriteFile(input, []byte("name,age\n Alice ,30\nBob,250\n"), 0644)
+	output := filepath.Join(dir, "clean.csv")
+	rejects := filepath.Join(dir, "rejects.csv")
+	manifestFile := filepath.Join(dir, "manifest.json")
+
+	if err := runClean([]string{"-trim", "-outlier", "age:0:120", "-rejects", rejects, "-output", output, "-manifest", manifestFile, input}); err != nil {
+		t.Fatalf("runClean error: %v", err)
+	}
+	manifest := readManifest(t, manifestFile)
+	if len(manifest.Outputs) != 2 || manifest.Outputs[0].Path != output || manifest.Outputs[0].Rows != 1 || manifest.Outputs[1].Path != rejects || manifest.Outputs[1].Rows != 1 {
+		t.Fatalf("unexpected outputs %+v", manifest.Outputs)
+	}
+	content, _ := os.ReadFile(output)
+	sum := sha256.Sum256(content)
+	if manifest.Outputs[0].SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("expected the checksum of the output")
+	}
+
+	// Streaming with the same cleaning flags gives the same pipeline hash
+	streamed := filepath.Join(dir, "streamed.csv")
+	streamedManifest := filepath.Join(dir, "streamed.json")
+	if err := runClean([]string{"-trim", "-outlier", "age:0:120", "-stream", "-output", streamed, "-manifest", streamedManifest, input}); err != nil {
+		t.Fatalf("runClean error: %v", err)
+	}
+	other := readManifest(t, streamedManifest)
+	if other.PipelineHash != manifest.PipelineHash {
+		t.Errorf("expected the same pipeline hash, got %s and %s", manifest.PipelineHash, other.PipelineHash)
+	}
+	if len(other.Outputs) != 1 || other.Outputs[0].Rows != 1 {
+		t.Errorf("unexpected streamed outputs %+v", other.Outputs)
+	}
+
+	// An existing manifest is not replaced without --force
+	if err := runClean([]string{"-trim", "-output", filepath.Join(dir, "third.csv"), "-manifest", manifestFile, input}); err == nil {
+		t.Error("expected an error for an existing manifest")
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// formatExtensions are the file extensions of the output formats
+var formatExtensions = map[string]string{
+	"csv":     ".csv",
+	"json":    ".json",
+	"excel":   ".xlsx",
+	"parquet": ".parquet",
+}
+
+// reservedFileNames are the device names Windows does not allow as file names, with any extension
+var reservedFileNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// outputPath returns the file to write the cleaned data to and creates its directory. Without
+// output, the file is "cleaned_" followed by the input file name, with the extension of the output
+// format and characters illegal in file names replaced, in outputDir or else in the directory of
+// the input. A relative output is placed in outputDir when it is set.
+func outputPath(inputFile, output, outputDir, format string) (string, error) {
+	path := output
+	if path == "" {
+		base := filepath.Base(inputFile)
+		if ext := formatExtensions[format]; getFileFormat(base) != format && ext != "" {
+			base = strings.TrimSuffix(base, filepath.Ext(base)) + ext
+		}
+		dir := outputDir
+		if dir == "" {
+			dir = filepath.Dir(inputFile)
+		}
+		path = filepath.Join(dir, sanitizeFileName("cleaned_"+base))
+	} else if outputDir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(outputDir, path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// sanitizeFileName makes name safe on Windows and Unix: characters that are not allowed in file
+// names become '_', trailing dots and spaces are removed and reserved device names get a '_' prefix
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 32 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimRight(name, ". ")
+
+	stem, _, _ := strings.Cut(name, ".")
+	if reservedFileNames[strings.ToUpper(strings.TrimSpace(stem))] {
+		name = "_" + name
+	}
+	if name == "" {
+		name = "_"
+	}
+	return name
+}
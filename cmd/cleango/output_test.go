@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOutputPath(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "data", "raw.csv")
+	outDir := filepath.Join(dir, "out", "daily")
+
+	tests := []struct {
+		name      string
+		output    string
+		outputDir string
+		format    string
+		expect    string
+	}{
+		{"next to the input", "", "", "csv", filepath.Join(dir, "data", "cleaned_raw.csv")},
+		{"output dir", "", outDir, "csv", filepath.Join(outDir, "cleaned_raw.csv")},
+		{"format extension", "", "", "parquet", filepath.Join(dir, "data", "cleaned_raw.parquet")},
+		{"explicit output", filepath.Join(dir, "x.csv"), outDir, "csv", filepath.Join(dir, "x.csv")},
+		{"relative output in output dir", "x.json", outDir, "json", filepath.Join(outDir, "x.json")},
+	}
+	for _, tt := range tests {
+		got, err := outputPath(input, tt.output, tt.outputDir, tt.format)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got != tt.expect {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.expect)
+		}
+		if info, err := os.Stat(filepath.Dir(got)); err != nil || !info.IsDir() {
+			t.Errorf("%s: expected the directory to be created", tt.name)
+		}
+	}
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := map[string]string{
+		"cleaned_raw.csv":       "cleaned_raw.csv",
+		`cleaned_a:b*c?.csv`:    "cleaned_a_b_c_.csv",
+		"cleaned_report. ":      "cleaned_report",
+		"CON.csv":               "_CON.csv",
+		"com1":                  "_com1",
+		"console.csv":           "console.csv",
+		"tab\there|pipe<>.json": "tab_here_pipe__.json",
+		"...":                   "_",
+	}
+	for name, expect := range tests {
+		if got := sanitizeFileName(name); got != expect {
+			t.Errorf("sanitizeFileName(%q) = %q, want %q", name, got, expect)
+		}
+	}
+}
+
+func TestRunClean_DefaultOutputNextToInput(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "exports")
+	os.MkdirAll(dir, 0755)
+	input := filepath.Join(dir, "raw.csv")
+	os.WriteFile(input, []byte("name\n ali \n"), 0644)
+
+	if err := runClean([]string{"-trim", input}); err != nil {
+		t.Fatalf("runClean error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "cleaned_raw.csv"))
+	if err != nil || string(data) != "name\nali\n" {
+		t.Errorf("unexpected output: %q %v", data, err)
+	}
+}
+
+func TestRunClean_Force(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "raw.csv")
+	output := filepath.Join(dir, "cleaned_raw.csv")
+	os.WriteFile(input, []byte("name\n ali \n"), 0644)
+	os.WriteFile(output, []byte("previous"), 0644)
+
+	if err := runClean([]string{"-trim", input}); err == nil {
+		t.Fatal("expected an error for an existing output file")
+	}
+	if data, _ := os.ReadFile(output); string(data) != "previous" {
+		t.Errorf("expected the existing output to be kept, got %q", data)
+	}
+
+	if err := runClean([]string{"-trim", "-force", input}); err != nil {
+		t.Fatalf("runClean error: %v", err)
+	}
+	if data, _ := os.ReadFile(output); string(data) != "name\nali\n" {
+		t.Errorf("expected the output to be replaced, got %q", data)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+	"github.com/mstgnz/cleango/pkg/formats"
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+// runProfile parses flags and args, then prints the quality score of every column of the file
+func runProfile(args []string) error {
+	profileCmd := flag.NewFlagSet("profile", flag.ContinueOnError)
+
+	delimiterFlag := profileCmd.String("delimiter", ",", i18n.T(language, "CSV delimiter character"))
+	sheetNameFlag := profileCmd.String("sheet-name", "Sheet1", i18n.T(language, "Excel worksheet name"))
+	jsonFlag := profileCmd.Bool("json", false, i18n.T(language, "Print the scores as JSON"))
+	constantFlag := profileCmd.Float64("constant", 0, i18n.T(language, "Report the columns whose most common value fills at least this share of the rows (e.g.: 0.99, 1 for single-valued columns)"))
+	langFlag := profileCmd.String("lang", string(i18n.FromEnv()), i18n.T(language, "Language of the messages (en, tr)"))
+
+	if err := profileCmd.Parse(args); err != nil {
+		return err
+	}
+	language = i18n.Parse(*langFlag)
+
+	positional := profileCmd.Args()
+	if len(positional) < 1 {
+		return errors.New(i18n.T(language, "input file not specified — usage: cleango profile [flags] <file>"))
+	}
+	inputFile := positional[0]
+
+	inputFormat := getFileFormat(inputFile)
+	if inputFormat == "" {
+		return errors.New(i18n.T(language, "unsupported file format — supported: .csv, .json, .xlsx, .parquet"))
+	}
+
+	var csvOptions []formats.CSVOption
+	if len(*delimiterFlag) == 1 {
+		csvOptions = append(csvOptions, formats.WithDelimiter(rune((*delimiterFlag)[0])))
+	}
+	var excelOptions []formats.ExcelOption
+	if *sheetNameFlag != "" {
+		excelOptions = append(excelOptions, formats.WithSheetName(*sheetNameFlag))
+	}
+
+	df, err := readFile(inputFile, inputFormat, csvOptions, nil, excelOptions, nil)
+	if err != nil {
+		return fmt.Errorf(i18n.T(language, "read error: %w"), err)
+	}
+
+	var constant []string
+	if *constantFlag != 0 {
+		if constant, err = df.ConstantColumns(*constantFlag); err != nil {
+			return err
+		}
+	}
+
+	if *jsonFlag {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			cleaner.QualityScore
+			Summary         []cleaner.ColumnSummary `json:"summary"`
+			MemoryUsage     cleaner.MemoryUsage     `json:"memory_usage"`
+			ConstantColumns []string                `json:"constant_columns,omitempty"`
+		}{df.QualityScore(), df.Describe(), df.MemoryUsage(), constant})
+	}
+	printQuality(os.Stdout, df)
+	printSummary(os.Stdout, df)
+	if *constantFlag != 0 {
+		printConstantColumns(os.Stdout, constant, *constantFlag)
+	}
+	return nil
+}
+
+// printConstantColumns prints the constant columns found with the threshold
+func printConstantColumns(w io.Writer, columns []string, threshold float64) {
+	if len(columns) == 0 {
+		fmt.Fprintln(w, i18n.T(language, "No constant columns (threshold %g)", threshold))
+		return
+	}
+	fmt.Fprintln(w, i18n.T(language, "Constant columns (threshold %g): %s", threshold, strings.Join(columns, ", ")))
+}
+
+// printQuality prints the overall score, the estimated memory and a table of the column scores, in
+// column order
+func printQuality(w io.Writer, df *cleaner.DataFrame) {
+	score := df.QualityScore()
+	usage := df.MemoryUsage()
+	rows, columns := df.Shape()
+	fmt.Fprintln(w, i18n.T(language, "Quality score: %.2f (%d rows, %d columns)", score.Score, rows, columns))
+	fmt.Fprintln(w, i18n.T(language, "Estimated memory: %s", formatBytes(uint64(usage.Total))))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T(language, "  Column\tType\tCompleteness\tValidity\tUniqueness\tConsistency\tScore\tMemory"))
+	for _, column := range df.Headers {
+		c := score.Columns[column]
+		fmt.Fprintf(tw, "  %s\t%s\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%s\n",
+			column, c.Type, c.Completeness, c.Validity, c.Uniqueness, c.Consistency, c.Score, formatBytes(uint64(usage.Columns[column])))
+	}
+	tw.Flush()
+}
+
+// printSummary prints a table of the summary statistics of the columns, in column order. Mean,
+// median and standard deviation are only printed for numeric columns.
+func printSummary(w io.Writer, df *cleaner.DataFrame) {
+	fmt.Fprintln(w, i18n.T(language, "Summary:"))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T(language, "  Column\tCount\tNulls\tDistinct\tMin\tMax\tMean\tMedian\tStdDev"))
+	for _, s := range df.Describe() {
+		fmt.Fprintf(tw, "  %s\t%d\t%d\t%d\t%s\t%s\t%s\t%s\t%s\n", s.Column, s.Count, s.Nulls, s.Distinct,
+			summaryValue(s.Min), summaryValue(s.Max), summaryNumber(s.Mean), summaryNumber(s.Median), summaryNumber(s.StdDev))
+	}
+	tw.Flush()
+}
+
+// summaryValue returns the value shortened to fit a table cell, - if empty
+func summaryValue(value string) string {
+	const maxWidth = 24
+	if value == "" {
+		return "-"
+	}
+	if runes := []rune(value); len(runes) > maxWidth {
+		return string(runes[:maxWidth-1]) + "…"
+	}
+	return value
+}
+
+// summaryNumber formats a statistic with two decimals, - if not set
+func summaryNumber(n *float64) string {
+	if n == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*n, 'f', 2, 64)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+)
+
+func TestRunProfile_NoInputFile(t *testing.T) {
+	err := runProfile([]string{})
+	if err == nil {
+		t.Fatal("expected error when no input file is given, got nil")
+	}
+	if !strings.Contains(err.Error(), "input file not specified") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestRunProfile_JSON(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "people.csv")
+	os.WriteFile(input, []byte("name,age\nAlice,30\nBob,\n"), 0644)
+
+	if err := runProfile([]string{"-json", input}); err != nil {
+		t.Fatalf("runProfile error: %v", err)
+	}
+}
+
+func TestPrintQuality(t *testing.T) {
+	df, _ := cleaner.NewDataFrame([]string{"name", "age"}, [][]string{{"Alice", "30"}, {"Bob", ""}})
+
+	var buf bytes.Buffer
+	printQuality(&buf, df)
+
+	out := buf.String()
+	for _, want := range []string{"Quality score: 0.94 (2 rows, 2 columns)", "Estimated memory: ", "Completeness", "Memory", "age", "0.50"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintSummary(t *testing.T) {
+	df, _ := cleaner.NewDataFrame([]string{"name", "age"}, [][]string{{"Alice", "30"}, {"Bob", ""}, {"Carol", "40"}})
+
+	var buf bytes.Buffer
+	printSummary(&buf, df)
+
+	out := buf.String()
+	for _, want := range []string{"Summary:", "StdDev", "35.00", "7.07", "Alice"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintConstantColumns(t *testing.T) {
+	var buf bytes.Buffer
+	printConstantColumns(&buf, []string{"country", "source"}, 0.99)
+
+	if want := "Constant columns (threshold 0.99): country, source\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+// regexRule is a regex cleaning given with --regex or --regex-json
+type regexRule struct {
+	Column      string `json:"column"`
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+}
+
+// parseRegexRules returns the rules of --regex followed by the rules of --regex-json.
+// In --regex, rules are separated by ',' and the column, pattern and replacement by ':';
+// "\:" and "\," stand for a literal ':' and ','. Malformed --regex rules are skipped.
+func parseRegexRules(regexFlag, regexJSONFlag string) ([]regexRule, error) {
+	var rules []regexRule
+	if regexFlag != "" {
+		for _, r := range splitEscaped(regexFlag, ',', -1) {
+			parts := splitEscaped(r, ':', 3)
+			if len(parts) == 3 {
+				rules = append(rules, regexRule{unescapeSeparators(parts[0]), unescapeSeparators(parts[1]), unescapeSeparators(parts[2])})
+			}
+		}
+	}
+
+	if regexJSONFlag != "" {
+		var jsonRules []regexRule
+		if err := json.Unmarshal([]byte(regexJSONFlag), &jsonRules); err != nil {
+			return nil, fmt.Errorf(i18n.T(language, "invalid --regex-json: %w"), err)
+		}
+		for i, rule := range jsonRules {
+			if rule.Column == "" || rule.Pattern == "" {
+				return nil, fmt.Errorf(i18n.T(language, "invalid --regex-json: rule %d needs a column and a pattern"), i+1)
+			}
+		}
+		rules = append(rules, jsonRules...)
+	}
+	return rules, nil
+}
+
+// splitEscaped splits s at the separators not preceded by a backslash, into at most n parts
+// (all parts if n < 0). Escape sequences are kept as they are.
+func splitEscaped(s string, sep byte, n int) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if n >= 0 && len(parts) == n-1 {
+			break
+		}
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescapeSeparators replaces "\:" and "\," with ':' and ','; other escapes, e.g. "\s" in a regex, are kept
+func unescapeSeparators(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			if s[i+1] != ':' && s[i+1] != ',' {
+				b.WriteByte('\\')
+			}
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
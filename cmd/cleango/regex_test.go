@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRegexRules(t *testing.T) {
+	rules, err := parseRegexRules(`phone:[^0-9]:,time:([0-9]{2})\:([0-9]{2}):$1h$2,amount:[0-9]{1\,3}:N,notes:\s+: `, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []regexRule{
+		{"phone", "[^0-9]", ""},
+		{"time", "([0-9]{2}):([0-9]{2})", "$1h$2"},
+		{"amount", "[0-9]{1,3}", "N"},
+		{"notes", `\s+`, " "},
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("unexpected rules:\n%q\nwant\n%q", rules, expected)
+	}
+
+	rules, err = parseRegexRules("phone:[^0-9]:", `[{"column":"url","pattern":"https?://[^,]+","replacement":"<url>"}]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 2 || rules[1] != (regexRule{"url", "https?://[^,]+", "<url>"}) {
+		t.Errorf("unexpected rules: %q", rules)
+	}
+
+	if _, err := parseRegexRules("", `{"column":"a"}`); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+	if _, err := parseRegexRules("", `[{"pattern":"a"}]`); err == nil {
+		t.Error("expected an error for a rule without a column")
+	}
+}
+
+func TestRunClean_RegexJSON(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.csv")
+	output := filepath.Join(dir, "out.csv")
+	os.WriteFile(input, []byte("time,site\n09:30,see https://example.com:8080/a\n"), 0644)
+
+	err := runClean([]string{
+		"-regex", `time:([0-9]{2})\:([0-9]{2}):$1.$2`,
+		"-regex-json", `[{"column":"site","pattern":"https?://[^ ]+","replacement":"<url>"}]`,
+		"-output", output,
+		input,
+	})
+	if err != nil {
+		t.Fatalf("runClean error: %v", err)
+	}
+	data, _ := os.ReadFile(output)
+	if string(data) != "time,site\n09.30,see <url>\n" {
+		t.Errorf("unexpected output: %q", data)
+	}
+}
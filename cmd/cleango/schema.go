@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+// errSchemasDiffer is returned by schema --compare when the files do not have the same schema
+var errSchemasDiffer = errors.New("schemas differ")
+
+// runSchema parses flags and args, then prints the columns and types of the files or, with
+// --compare, how their schemas differ from the first file
+func runSchema(args []string) error {
+	schemaCmd := flag.NewFlagSet("schema", flag.ContinueOnError)
+
+	compareFlag := schemaCmd.Bool("compare", false, i18n.T(language, "Report the columns that are missing, extra or of another type compared with the first file"))
+	jsonFlag := schemaCmd.Bool("json", false, i18n.T(language, "Print the schemas as JSON"))
+	langFlag := schemaCmd.String("lang", string(i18n.FromEnv()), i18n.T(language, "Language of the messages (en, tr)"))
+
+	if err := schemaCmd.Parse(args); err != nil {
+		return err
+	}
+	language = i18n.Parse(*langFlag)
+
+	files := schemaCmd.Args()
+	if len(files) < 1 || *compareFlag && len(files) < 2 {
+		return errors.New(i18n.T(language, "input files not specified — usage: cleango schema [--compare] [flags] <file>..."))
+	}
+
+	if *compareFlag {
+		report, err := cleaner.CompareSchemas(files...)
+		if err != nil {
+			return err
+		}
+		if *jsonFlag {
+			if err := printJSON(report); err != nil {
+				return err
+			}
+		} else {
+			printSchemaReport(os.Stdout, report)
+		}
+		if !report.Consistent() {
+			return errSchemasDiffer
+		}
+		return nil
+	}
+
+	schemas := make([]cleaner.FileSchema, 0, len(files))
+	for _, file := range files {
+		inputFormat := getFileFormat(file)
+		if inputFormat == "" {
+			return errors.New(i18n.T(language, "unsupported file format — supported: .csv, .json, .xlsx, .parquet"))
+		}
+		df, err := readFile(file, inputFormat, nil, nil, nil, nil)
+		if err != nil {
+			return fmt.Errorf(i18n.T(language, "read error: %w"), err)
+		}
+		df.InferTypes()
+		schemas = append(schemas, cleaner.FileSchema{Path: file, Rows: len(df.Data), Columns: df.Headers, Types: maps.Clone(df.Types)})
+	}
+	if *jsonFlag {
+		return printJSON(schemas)
+	}
+	for _, schema := range schemas {
+		printFileSchema(os.Stdout, schema)
+	}
+	return nil
+}
+
+// printJSON prints the value as indented JSON
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printFileSchema prints the columns of a file and their types, in column order
+func printFileSchema(w io.Writer, schema cleaner.FileSchema) {
+	fmt.Fprintln(w, i18n.T(language, "%s (%d rows, %d columns)", schema.Path, schema.Rows, len(schema.Columns)))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, column := range schema.Columns {
+		fmt.Fprintf(tw, "  %s\t%s\n", column, schema.Types[column])
+	}
+	tw.Flush()
+}
+
+// printSchemaReport prints the differences of every file from the first file
+func printSchemaReport(w io.Writer, report *cleaner.SchemaReport) {
+	if report.Consistent() {
+		fmt.Fprintln(w, i18n.T(language, "All %d files have the same columns and types as %s", len(report.Files), report.Files[0].Path))
+		return
+	}
+	fmt.Fprintln(w, i18n.T(language, "Compared with %s:", report.Files[0].Path))
+	for _, diff := range report.Differences {
+		fmt.Fprintf(w, "  %s\n", diff.Path)
+		if len(diff.Missing) > 0 {
+			fmt.Fprintln(w, i18n.T(language, "    missing: %s", strings.Join(diff.Missing, ", ")))
+		}
+		if len(diff.Extra) > 0 {
+			fmt.Fprintln(w, i18n.T(language, "    extra: %s", strings.Join(diff.Extra, ", ")))
+		}
+		for _, m := range diff.Mismatched {
+			fmt.Fprintln(w, i18n.T(language, "    type of %s: %s instead of %s", m.Column, m.Actual, m.Expected))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+)
+
+func TestRunSchema_NoInputFiles(t *testing.T) {
+	if err := runSchema([]string{"-compare", "only.csv"}); err == nil || !strings.Contains(err.Error(), "input files not specified") {
+		t.Errorf("expected usage error, got %v", err)
+	}
+}
+
+func TestRunSchema_Compare(t *testing.T) {
+	dir := t.TempDir()
+	jan := filepath.Join(dir, "jan.csv")
+	feb := filepath.Join(dir, "feb.csv")
+	os.WriteFile(jan, []byte("id,amount\n1,10\n"), 0644)
+	os.WriteFile(feb, []byte("amount,id\n20,2\n"), 0644)
+
+	if err := runSchema([]string{"-compare", jan, feb}); err != nil {
+		t.Fatalf("expected consistent schemas, got %v", err)
+	}
+	if err := runSchema([]string{jan}); err != nil {
+		t.Fatalf("runSchema error: %v", err)
+	}
+
+	os.WriteFile(feb, []byte("id,amount,note\nx2,20,late\n"), 0644)
+	if err := runSchema([]string{"-compare", "-json", jan, feb}); !errors.Is(err, errSchemasDiffer) {
+		t.Errorf("expected errSchemasDiffer, got %v", err)
+	}
+}
+
+func TestPrintSchemaReport(t *testing.T) {
+	report := &cleaner.SchemaReport{
+		Files: []cleaner.FileSchema{{Path: "jan.csv"}, {Path: "feb.csv"}},
+		Differences: []cleaner.SchemaDifference{{
+			Path:       "feb.csv",
+			Missing:    []string{"name"},
+			Extra:      []string{"note"},
+			Mismatched: []cleaner.TypeMismatch{{Column: "id", Expected: cleaner.TypeInt, Actual: cleaner.TypeString}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	printSchemaReport(&buf, report)
+
+	want := "Compared with jan.csv:\n  feb.csv\n    missing: name\n    extra: note\n    type of id: string instead of int\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+	"github.com/mstgnz/cleango/pkg/formats"
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+// defaultStreamThreshold is the input size above which the clean command streams by default.
+// Loaded into a DataFrame, a CSV file takes several times its size in memory.
+const defaultStreamThreshold = "1GB"
+
+// byteSizeUnits, the multipliers of the size units; KB, MB, ... are decimal and KiB, MiB, ... binary
+var byteSizeUnits = map[string]int64{
+	"":    1,
+	"B":   1,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+}
+
+// parseByteSize parses a size such as 512MB, 2GiB or 1000000 (bytes)
+func parseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	end := len(s)
+	for end > 0 && (s[end-1] < '0' || s[end-1] > '9') {
+		end--
+	}
+	unit, ok := byteSizeUnits[strings.ToUpper(strings.TrimSpace(s[end:]))]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit: %s", s[end:])
+	}
+	n, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size: %s", s)
+	}
+	return int64(n * float64(unit)), nil
+}
+
+// streamBlocker returns why the clean command cannot stream, or "" if it can. wholeInputFlags are
+// the given flags that only work on the loaded input.
+func streamBlocker(inputFormat, outputFormat string, pipeline *cleaner.Pipeline, wholeInputFlags []string) string {
+	switch {
+	case inputFormat != "csv" && inputFormat != "json":
+		return i18n.T(language, "%s input cannot be streamed", inputFormat)
+	case outputFormat != "csv" && outputFormat != "json":
+		return i18n.T(language, "%s output cannot be streamed", outputFormat)
+	case len(wholeInputFlags) > 0:
+		return i18n.T(language, "%s needs the whole input", wholeInputFlags[0])
+	}
+	if steps := pipeline.WholeDatasetSteps(); len(steps) > 0 {
+		return i18n.T(language, "the %s action needs the whole input", steps[0])
+	}
+	return ""
+}
+
+// chooseStreaming reports whether the input is streamed: with --stream, or automatically when it is
+// larger than threshold. A large input that cannot be streamed is loaded with a warning.
+func chooseStreaming(inputFile string, stream bool, threshold int64, blocker string) (bool, error) {
+	if stream {
+		if blocker != "" {
+			return false, errors.New(i18n.T(language, "--stream is not possible: %s", blocker))
+		}
+		return true, nil
+	}
+
+	info, err := os.Stat(inputFile)
+	if err != nil || threshold <= 0 || info.Size() <= threshold {
+		// A missing input is reported by the reader
+		return false, nil
+	}
+	if blocker != "" {
+		fmt.Println(i18n.T(language, "Warning: %s is %s, larger than --stream-threshold, but is loaded into memory because %s", inputFile, formatBytes(uint64(info.Size())), blocker))
+		return false, nil
+	}
+	fmt.Println(i18n.T(language, "%s is %s, larger than --stream-threshold: streaming", inputFile, formatBytes(uint64(info.Size()))))
+	return true, nil
+}
+
+// runStreamClean cleans the input chunk by chunk with the pipeline and writes the output row by row,
+// so inputs larger than memory can be cleaned
+func runStreamClean(cli *cliPipeline, inputFile, inputFormat, outputFile, outputFormat string, csvOptions []formats.CSVOption, jsonOptions []formats.JSONOption, timings *timingRecorder, manifest *cleaner.Manifest) error {
+	var source formats.RowReader
+	var err error
+	if inputFormat == "csv" {
+		source, err = formats.NewCSVRowReader(inputFile, csvOptions...)
+	} else {
+		source, err = formats.NewJSONRowReader(inputFile, jsonOptions...)
+	}
+	if err != nil {
+		return fmt.Errorf(i18n.T(language, "read error: %w"), err)
+	}
+
+	var sink formats.RowWriter
+	if outputFormat == "csv" {
+		sink, err = formats.NewCSVRowWriter(outputFile, csvOptions...)
+	} else {
+		sink, err = formats.NewJSONRowWriter(outputFile, jsonOptions...)
+	}
+	if err != nil {
+		source.Close()
+		return fmt.Errorf(i18n.T(language, "write error: %w"), err)
+	}
+
+	var stats *cleaner.StreamStats
+	run := func() error {
+		var err error
+		stats, err = cleaner.NewStream(source, cleaner.WithColumnStats()).Then(cli.pipeline).Run(sink)
+		return err
+	}
+	rows := func() int {
+		if stats == nil {
+			return 0
+		}
+		return stats.RowsWritten
+	}
+	if err := timings.measure("stream", run, rows); err != nil {
+		return err
+	}
+	if manifest != nil {
+		if err := manifest.AddOutput(outputFile, stats.RowsWritten); err != nil {
+			return fmt.Errorf(i18n.T(language, "manifest write error: %w"), err)
+		}
+	}
+
+	cli.printApplied()
+	fmt.Println(i18n.T(language, "Cleaned data written to %s", outputFile))
+	fmt.Println(i18n.T(language, "Statistics: %d rows read, %d rows written in %d chunks", stats.RowsRead, stats.RowsWritten, stats.Chunks))
+	printStreamColumnStats(os.Stdout, stats.Columns)
+	printTimings(timings)
+	return nil
+}
+
+// printStreamColumnStats prints a table of the statistics of the output columns gathered while
+// streaming, in column order
+func printStreamColumnStats(w io.Writer, columns []cleaner.StreamColumnStats) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T(language, "  Column\tCount\tNulls\t~Distinct\tMin\tMax"))
+	for _, c := range columns {
+		fmt.Fprintf(tw, "  %s\t%d\t%d\t%d\t%s\t%s\n", c.Column, c.Count, c.Nulls, c.Distinct, summaryValue(c.Min), summaryValue(c.Max))
+	}
+	tw.Flush()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]int64{
+		"0":       0,
+		"1000":    1000,
+		"512MB":   512e6,
+		"2GiB":    2 << 30,
+		"1.5 kb":  1500,
+		" 1TB ":   1e12,
+		"100 B":   100,
+		"4kib":    4096,
+		"0.5 GiB": 1 << 29,
+	}
+	for input, expect := range tests {
+		got, err := parseByteSize(input)
+		if err != nil || got != expect {
+			t.Errorf("parseByteSize(%q) = %d, %v, want %d", input, got, err, expect)
+		}
+	}
+	for _, input := range []string{"", "MB", "12XB", "-1GB", "1.2.3MB"} {
+		if _, err := parseByteSize(input); err == nil {
+			t.Errorf("parseByteSize(%q): expected an error", input)
+		}
+	}
+}
+
+func TestRunClean_Stream(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "raw.csv")
+	var sb strings.Builder
+	sb.WriteString("name,age\n")
+	for range 100 {
+		sb.WriteString(" ali ,\n  veli ,30\n")
+	}
+	os.WriteFile(input, []byte(sb.String()), 0644)
+
+	inMemory := filepath.Join(dir, "memory.csv")
+	if err := runClean([]string{"-trim", "-null-replace=age:0", "-output=" + inMemory, input}); err != nil {
+		t.Fatalf("runClean error: %v", err)
+	}
+	expected, _ := os.ReadFile(inMemory)
+
+	for name, args := range map[string][]string{
+		"explicit":  {"-stream"},
+		"threshold": {"-stream-threshold=1KB"},
+	} {
+		output := filepath.Join(dir, name+".csv")
+		args = append(args, "-trim", "-null-replace=age:0", "-output="+output, input)
+		if err := runClean(args); err != nil {
+			t.Fatalf("%s: runClean error: %v", name, err)
+		}
+		data, err := os.ReadFile(output)
+		if err != nil || string(data) != string(expected) {
+			t.Errorf("%s: expected the in-memory output, got %q %v", name, data, err)
+		}
+	}
+}
+
+func TestRunClean_StreamBlocked(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "raw.csv")
+	os.WriteFile(input, []byte("name\nali\nveli\nali\n"), 0644)
+
+	pipeline := filepath.Join(dir, "pipeline.yaml")
+	os.WriteFile(pipeline, []byte("actions:\n  - drop_duplicates\n"), 0644)
+	if err := runClean([]string{"-stream", "-pipeline=" + pipeline, input}); err == nil || !strings.Contains(err.Error(), "drop_duplicates") {
+		t.Errorf("expected an error naming the drop_duplicates action, got %v", err)
+	}
+	if err := runClean([]string{"-stream", "-format=excel", input}); err == nil {
+		t.Error("expected an error for Excel output")
+	}
+
+	// Above the threshold a blocked input is loaded with a warning
+	output := filepath.Join(dir, "deduplicated.csv")
+	if err := runClean([]string{"-stream-threshold=1", "-pipeline=" + pipeline, "-output=" + output, input}); err != nil {
+		t.Fatalf("runClean error: %v", err)
+	}
+	if data, _ := os.ReadFile(output); string(data) != "name\nali\nveli\n" {
+		t.Errorf("unexpected output: %q", data)
+	}
+}
+
+func TestPrintStreamColumnStats(t *testing.T) {
+	var buf bytes.Buffer
+	printStreamColumnStats(&buf, []cleaner.StreamColumnStats{
+		{Column: "name", Count: 2, Nulls: 1, Distinct: 2, Min: "ali", Max: "veli"},
+		{Column: "age", Nulls: 3},
+	})
+	want := "  Column  Count  Nulls  ~Distinct  Min  Max\n" +
+		"  name    2      1      2          ali  veli\n" +
+		"  age     0      3      0          -    -\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
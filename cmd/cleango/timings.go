@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime/metrics"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+	"github.com/mstgnz/cleango/pkg/i18n"
+)
+
+// heapMetric is the runtime metric sampled for peak memory
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// memorySampleInterval is how often the heap size is sampled while timing
+const memorySampleInterval = time.Millisecond
+
+// timing is the measurement of one operation
+type timing struct {
+	name     string
+	rows     int
+	duration time.Duration
+	peak     uint64
+}
+
+// timingRecorder measures the wall time, throughput and peak heap memory of every operation.
+// It implements cleaner.Observer for the pipeline steps; reading and writing are measured with measure.
+type timingRecorder struct {
+	mu      sync.Mutex
+	timings []timing
+	active  map[*timing]struct{}
+	start   time.Time
+	stop    chan struct{}
+	done    chan struct{}
+
+	stopOnce sync.Once
+}
+
+// newTimingRecorder starts sampling the heap size until Stop is called
+func newTimingRecorder() *timingRecorder {
+	r := &timingRecorder{
+		active: make(map[*timing]struct{}),
+		start:  time.Now(),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.sample()
+	return r
+}
+
+// sample records the heap size into the peak of every running operation
+func (r *timingRecorder) sample() {
+	defer close(r.done)
+	ticker := time.NewTicker(memorySampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.update(heapInUse())
+		}
+	}
+}
+
+// update raises the peak of the running operations to heap
+func (r *timingRecorder) update(heap uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for t := range r.active {
+		t.peak = max(t.peak, heap)
+	}
+}
+
+// begin starts measuring an operation
+func (r *timingRecorder) begin(name string, rows int) *timing {
+	t := &timing{name: name, rows: rows, peak: heapInUse()}
+	r.mu.Lock()
+	r.active[t] = struct{}{}
+	r.mu.Unlock()
+	return t
+}
+
+// end finishes measuring an operation
+func (r *timingRecorder) end(t *timing, duration time.Duration) {
+	heap := heapInUse()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.active, t)
+	t.duration = duration
+	t.peak = max(t.peak, heap)
+	r.timings = append(r.timings, *t)
+}
+
+// measure runs fn as a measured operation; rows returns the number of rows it processed.
+// On a nil recorder fn is only run.
+func (r *timingRecorder) measure(name string, fn func() error, rows func() int) error {
+	if r == nil {
+		return fn()
+	}
+	t := r.begin(name, 0)
+	start := time.Now()
+	err := fn()
+	t.rows = rows()
+	r.end(t, time.Since(start))
+	return err
+}
+
+type timingKey struct{}
+
+// OperationStarted starts measuring a pipeline step. The pipeline itself is not measured since the
+// total is printed separately.
+func (r *timingRecorder) OperationStarted(ctx context.Context, event cleaner.OperationEvent) context.Context {
+	if event.Operation == "pipeline" {
+		return ctx
+	}
+	name := event.Operation
+	if event.Column != "" {
+		name += " (" + event.Column + ")"
+	}
+	return context.WithValue(ctx, timingKey{}, r.begin(name, event.RowsIn))
+}
+
+// OperationFinished finishes measuring a pipeline step
+func (r *timingRecorder) OperationFinished(ctx context.Context, event cleaner.OperationEvent) {
+	if t, ok := ctx.Value(timingKey{}).(*timing); ok {
+		r.end(t, event.Duration)
+	}
+}
+
+// Stop stops sampling memory. It may be called more than once.
+func (r *timingRecorder) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+	})
+}
+
+// Print writes the timings as a table followed by the total
+func (r *timingRecorder) Print(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T(language, "  Operation\tTime\tRows/sec\tPeak memory"))
+	var peak uint64
+	for _, t := range r.timings {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", t.name, formatDuration(t.duration), formatRate(t.rows, t.duration), formatBytes(t.peak))
+		peak = max(peak, t.peak)
+	}
+	fmt.Fprintln(tw, i18n.T(language, "  total\t%s\t\t%s", formatDuration(time.Since(r.start)), formatBytes(peak)))
+	tw.Flush()
+}
+
+// heapInUse returns the bytes of live and not yet swept heap objects
+func heapInUse() uint64 {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
+
+func formatRate(rows int, d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f", float64(rows)/d.Seconds())
+}
+
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/cleaner"
+)
+
+func TestTimingRecorder(t *testing.T) {
+	df, err := cleaner.NewDataFrame([]string{"name", "age"}, [][]string{{" a ", ""}, {"b", "3"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timings := newTimingRecorder()
+	_, err = cleaner.NewPipeline().Trim().ReplaceNulls("age", "0").Unfused().
+		Run(df, cleaner.WithObserver(timings))
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if err := timings.measure("write", func() error { return nil }, func() int { return 2 }); err != nil {
+		t.Fatal(err)
+	}
+	timings.Stop()
+	timings.Stop()
+
+	var names []string
+	for _, timing := range timings.timings {
+		names = append(names, timing.name)
+		if timing.peak == 0 {
+			t.Errorf("%s: expected a peak memory sample", timing.name)
+		}
+	}
+	if got := strings.Join(names, ","); got != "trim,replace_nulls (age),write" {
+		t.Errorf("unexpected operations: %s", got)
+	}
+
+	var out bytes.Buffer
+	timings.Print(&out)
+	for _, want := range []string{"Operation", "Rows/sec", "replace_nulls (age)", "total"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output is missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[uint64]string{
+		512:             "512 B",
+		2048:            "2.0 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+	}
+	for in, want := range tests {
+		if got := formatBytes(in); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", in, got, want)
+		}
+	}
+
+	var recorder *timingRecorder
+	called := false
+	if err := recorder.measure("read", func() error { called = true; return nil }, nil); err != nil || !called {
+		t.Error("a nil recorder should only run the operation")
+	}
+}
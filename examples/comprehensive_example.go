@@ -5,6 +5,8 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/mstgnz/cleango/pkg/cleaner"
@@ -356,7 +358,7 @@ func parallelProcessingExample(examplesDir string) {
 		fmt.Println("Converted 'name' column to lowercase in parallel")
 	}
 
-	// Batch processing with multiple operations
+	// Batch processing: apply the operations one after another
 	processors := []func(*cleaner.DataFrame) (*cleaner.DataFrame, error){
 		func(df *cleaner.DataFrame) (*cleaner.DataFrame, error) {
 			return df.CleanWithRegexParallel("email", "@example\\.com", "@cleango.org", workers)
@@ -366,7 +368,7 @@ func parallelProcessingExample(examplesDir string) {
 		},
 	}
 
-	df, err = df.BatchProcessParallel(processors, workers)
+	df, err = df.BatchPipeline(processors, workers)
 	if err != nil {
 		log.Printf("Warning: %v", err)
 	} else {
@@ -461,54 +463,35 @@ func customDataExample() {
 	rows, cols := df.Shape()
 	fmt.Printf("Custom DataFrame created: %d rows, %d columns\n", rows, cols)
 
-	// Note: The following methods might not be implemented in the current version
-	// Uncomment if they are available in your version of CleanGo
-
-	/*
-		// Add a new column with calculated values
-		newColumn := make([]string, len(data))
-		for i, row := range df.GetData() {
-			// Find price column index
-			priceIdx := -1
-			for j, header := range df.GetHeaders() {
-				if header == "price" {
-					priceIdx = j
-					break
-				}
-			}
-
-			if priceIdx >= 0 {
-				// Parse price and add tax
-				price := 0.0
-				fmt.Sscanf(row[priceIdx], "%f", &price)
-				taxPrice := price * 1.08 // 8% tax
-				newColumn[i] = fmt.Sprintf("%.2f", taxPrice)
-			}
-		}
-
-		df, err = df.AddColumn("price_with_tax", newColumn)
-		if err != nil {
-			log.Printf("Warning: %v", err)
-		} else {
-			fmt.Println("Added 'price_with_tax' column with calculated values")
-		}
+	// Add a new column with calculated values
+	priceIdx := slices.Index(df.GetHeaders(), "price")
+	df, err = df.AddColumnFunc("price_with_tax", func(row []string) string {
+		price, _ := strconv.ParseFloat(row[priceIdx], 64)
+		return strconv.FormatFloat(price*1.08, 'f', 2, 64) // 8% tax
+	})
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	} else {
+		fmt.Println("Added 'price_with_tax' column with calculated values")
+	}
 
-		// Filter rows by category
-		df, err = df.FilterRows("category", "Electronics")
-		if err != nil {
-			log.Printf("Warning: %v", err)
-		} else {
-			fmt.Println("Filtered rows to keep only 'Electronics' category")
-		}
+	// Filter rows by category
+	df, err = df.FilterRows(func(row map[string]string) bool {
+		return row["category"] == "Electronics"
+	})
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	} else {
+		fmt.Println("Filtered rows to keep only 'Electronics' category")
+	}
 
-		// Sort by price
-		df, err = df.SortByColumn("price", true) // descending order
-		if err != nil {
-			log.Printf("Warning: %v", err)
-		} else {
-			fmt.Println("Sorted rows by 'price' in descending order")
-		}
-	*/
+	// Sort by price
+	df, err = df.SortBy([]cleaner.SortKey{{Column: "price", Descending: true, Numeric: true}})
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	} else {
+		fmt.Println("Sorted rows by 'price' in descending order")
+	}
 
 	// Save result
 	outputPath := "custom_data_example.csv"
@@ -558,9 +541,6 @@ func errorHandlingExample() {
 	}
 
 	// Try to parse invalid date
-	// Note: AddColumn might not be implemented in the current version
-	// We'll use a different approach for demonstration
-
 	// Create a DataFrame with invalid dates
 	headersWithDate := []string{"id", "name", "invalid_date"}
 	dataWithDate := [][]string{
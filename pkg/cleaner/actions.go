@@ -0,0 +1,593 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownAction is returned for an action whose type is not known
+var ErrUnknownAction = errors.New("unknown action type")
+
+// PipelineSpec is a pipeline in the format of pipeline files and of the pipelines saved in the
+// REST API: a list of actions such as "trim" or "replace_nulls:age=0"
+type PipelineSpec struct {
+	Name        string   `json:"name,omitempty" yaml:"name,omitempty"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Actions     []string `json:"actions" yaml:"actions"`
+}
+
+// actionUsages, the argument format of every action type
+var actionUsages = map[string]string{
+	"trim":                      "trim",
+	"sanitize_control_chars":    "sanitize_control_chars[:col1,col2]",
+	"normalize_dates":           "normalize_dates:column=layout[=epoch_unit]",
+	"replace_nulls":             "replace_nulls:column=value",
+	"fill_nulls":                "fill_nulls:column=mean|median|mode",
+	"fill_forward":              "fill_forward:column",
+	"fill_backward":             "fill_backward:column",
+	"normalize_numeric":         "normalize_numeric:column=minmax|zscore[=target]",
+	"bin":                       "bin:column=edge,edge,...[=label,label,...[=target]]",
+	"bin_equal_width":           "bin_equal_width:column=bins[=target]",
+	"bin_equal_frequency":       "bin_equal_frequency:column=bins[=target]",
+	"normalize_numeric_formats": "normalize_numeric_formats:column",
+	"convert_units":             "convert_units:column=from=to[=decimals]",
+	"fake_column":               "fake_column:column=kind[=seed]",
+	"normalize_case":            "normalize_case:column=upper|lower",
+	"strip_prefix":              "strip_prefix:column=prefix",
+	"strip_suffix":              "strip_suffix:column=suffix",
+	"pad_left":                  "pad_left:column=width[=char]",
+	"map_values":                "map_values:column=from:to,from:to[=keep|empty]",
+	"clean_regex":               "clean_regex:column=pattern=replace",
+	"split_column":              "split_column:column=sep=col1,col2",
+	"filter_outliers":           "filter_outliers:column=min=max",
+	"normalize_headers":         "normalize_headers[:snake|camel[=transliterate]]",
+	"drop_constant_columns":     "drop_constant_columns[:threshold]",
+	"add_row_number":            "add_row_number:column",
+	"coalesce_columns":          "coalesce_columns:new=col1,col2",
+	"copy_column":               "copy_column:src=dst",
+	"swap_columns":              "swap_columns:a=b",
+	"apply_profile":             "apply_profile:column=profile",
+	"drop_duplicates":           "drop_duplicates[:col1,col2][=first|last]",
+	"dedupe_fuzzy":              "dedupe_fuzzy:column=threshold[=levenshtein|jaro_winkler]",
+	"collation":                 "collation:locale",
+	"pivot":                     "pivot:index=key=value",
+	"melt":                      "melt:id1,id2=[col1,col2][=var_name=value_name]",
+	"allowed_values":            "allowed_values:column=value1,value2[=drop|replace|report]",
+	"denied_values":             "denied_values:column=value1,value2[=drop|replace|report]",
+	"sample":                    "sample:n[=seed]",
+	"sample_fraction":           "sample_fraction:fraction[=seed]",
+	"sample_stratified":         "sample_stratified:column=fraction[=seed]",
+	"assert_sorted":             "assert_sorted:column[=asc|desc]",
+	"assert_unique":             "assert_unique[:col1,col2]",
+	"assert_non_null":           "assert_non_null[:col1,col2]",
+}
+
+// ParsePipeline parses a pipeline spec in YAML or JSON, e.g.
+//
+//	name: customers
+//	actions:
+//	  - trim
+//	  - replace_nulls:age=0
+//
+// into a pipeline with the steps of its actions
+func ParsePipeline(data []byte) (*Pipeline, error) {
+	spec, err := ParsePipelineSpec(data)
+	if err != nil {
+		return nil, err
+	}
+	pipeline := NewPipeline()
+	if err := pipeline.Actions(spec.Actions...); err != nil {
+		return nil, err
+	}
+	return pipeline, nil
+}
+
+// LoadPipeline reads a pipeline spec file in YAML or JSON, see ParsePipeline
+func LoadPipeline(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline spec read error: %w", err)
+	}
+	return ParsePipeline(data)
+}
+
+// ParsePipelineSpec parses a pipeline spec in YAML or JSON without checking its actions
+func ParsePipelineSpec(data []byte) (*PipelineSpec, error) {
+	// YAML is a superset of JSON, so one decoder reads both
+	var spec PipelineSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("invalid pipeline spec: %w", err)
+	}
+	return &spec, nil
+}
+
+// LoadPipelineSpec reads a pipeline spec file in YAML or JSON without checking its actions
+func LoadPipelineSpec(path string) (*PipelineSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pipeline spec read error: %w", err)
+	}
+	return ParsePipelineSpec(data)
+}
+
+// Actions appends the steps of the actions, stopping at the first invalid one
+func (p *Pipeline) Actions(actions ...string) error {
+	for i, action := range actions {
+		if err := p.Action(action); err != nil {
+			return fmt.Errorf("action %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Action appends the step of an action such as "trim" or "normalize_dates:created_at=2006-01-02",
+// or of a custom operation registered with RegisterOp. Nothing is appended when the action is
+// unknown (ErrUnknownAction) or its arguments are invalid.
+func (p *Pipeline) Action(action string) error {
+	actionType, arg, hasArg := strings.Cut(action, ":")
+	usage, ok := actionUsages[actionType]
+	if !ok {
+		factory, ok := registeredOp(actionType)
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownAction, actionType)
+		}
+		op, err := factory(arg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", actionType, err)
+		}
+		if op == nil {
+			return fmt.Errorf("%s: operation factory returned no operation", actionType)
+		}
+		p.Then(actionType, op)
+		return nil
+	}
+	invalid := fmt.Errorf("%s: invalid arguments, expected %s", actionType, usage)
+
+	switch actionType {
+	case "trim":
+		p.Trim()
+
+	case "sanitize_control_chars":
+		var columns []string
+		if arg != "" {
+			columns = strings.Split(arg, ",")
+		}
+		p.SanitizeControlChars(columns...)
+
+	case "normalize_dates":
+		dateParts := strings.SplitN(arg, "=", 3)
+		if len(dateParts) < 2 {
+			return invalid
+		}
+		var dateOptions []DateOption
+		if len(dateParts) == 3 {
+			unit, err := ParseEpochUnit(dateParts[2])
+			if err != nil {
+				return errors.New("normalize_dates: invalid epoch unit")
+			}
+			dateOptions = append(dateOptions, WithEpoch(unit))
+		}
+		p.CleanDates(dateParts[0], dateParts[1], dateOptions...)
+
+	case "replace_nulls":
+		column, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return invalid
+		}
+		p.ReplaceNulls(column, value)
+
+	case "fill_nulls":
+		column, name, ok := strings.Cut(arg, "=")
+		if !ok || column == "" {
+			return invalid
+		}
+		strategy, err := ParseFillStrategy(name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", actionType, err)
+		}
+		p.FillNulls(column, strategy)
+
+	case "fill_forward", "fill_backward":
+		if arg == "" {
+			return invalid
+		}
+		if actionType == "fill_forward" {
+			p.FillForward(arg)
+		} else {
+			p.FillBackward(arg)
+		}
+
+	case "normalize_numeric":
+		normalizeParts := strings.SplitN(arg, "=", 3)
+		if len(normalizeParts) < 2 || normalizeParts[0] == "" {
+			return invalid
+		}
+		method, err := ParseNormalizeMethod(normalizeParts[1])
+		if err != nil {
+			return fmt.Errorf("%s: %w", actionType, err)
+		}
+		var normalizeOptions []NormalizeOption
+		if len(normalizeParts) == 3 {
+			normalizeOptions = append(normalizeOptions, WithNormalizeTarget(normalizeParts[2]))
+		}
+		p.NormalizeNumeric(normalizeParts[0], method, normalizeOptions...)
+
+	case "bin":
+		binParts := strings.SplitN(arg, "=", 4)
+		if len(binParts) < 2 || binParts[0] == "" {
+			return invalid
+		}
+		var edges []float64
+		for _, text := range strings.Split(binParts[1], ",") {
+			edge, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+			if err != nil {
+				return errors.New("bin: invalid edges")
+			}
+			edges = append(edges, edge)
+		}
+		var labels []string
+		if len(binParts) > 2 && binParts[2] != "" {
+			labels = strings.Split(binParts[2], ",")
+		}
+		var binOptions []BinOption
+		if len(binParts) == 4 {
+			binOptions = append(binOptions, WithBinTarget(binParts[3]))
+		}
+		p.Bin(binParts[0], edges, labels, binOptions...)
+
+	case "bin_equal_width", "bin_equal_frequency":
+		binParts := strings.SplitN(arg, "=", 3)
+		if len(binParts) < 2 || binParts[0] == "" {
+			return invalid
+		}
+		bins, err := strconv.Atoi(binParts[1])
+		if err != nil || bins < 1 {
+			return fmt.Errorf("%s: invalid number of bins", actionType)
+		}
+		var binOptions []BinOption
+		if len(binParts) == 3 {
+			binOptions = append(binOptions, WithBinTarget(binParts[2]))
+		}
+		if actionType == "bin_equal_width" {
+			p.BinEqualWidth(binParts[0], bins, nil, binOptions...)
+		} else {
+			p.BinEqualFrequency(binParts[0], bins, nil, binOptions...)
+		}
+
+	case "normalize_numeric_formats":
+		if arg == "" {
+			return invalid
+		}
+		p.NormalizeNumericFormats(arg)
+
+	case "convert_units":
+		unitParts := strings.SplitN(arg, "=", 4)
+		if len(unitParts) < 3 {
+			return invalid
+		}
+		var unitOptions []UnitOption
+		if len(unitParts) == 4 {
+			precision, err := strconv.Atoi(unitParts[3])
+			if err != nil {
+				return errors.New("convert_units: invalid precision")
+			}
+			unitOptions = append(unitOptions, WithUnitPrecision(precision))
+		}
+		p.ConvertUnits(unitParts[0], unitParts[1], unitParts[2], unitOptions...)
+
+	case "fake_column":
+		fakeParts := strings.SplitN(arg, "=", 3)
+		if len(fakeParts) < 2 {
+			return invalid
+		}
+		kind, err := ParseFakeKind(fakeParts[1])
+		if err != nil {
+			return errors.New("fake_column: invalid kind or seed")
+		}
+		var seed int64
+		if len(fakeParts) == 3 {
+			if seed, err = strconv.ParseInt(fakeParts[2], 10, 64); err != nil {
+				return errors.New("fake_column: invalid kind or seed")
+			}
+		}
+		p.FakeColumn(fakeParts[0], kind, seed)
+
+	case "normalize_case":
+		column, caseType, ok := strings.Cut(arg, "=")
+		if !ok {
+			return invalid
+		}
+		p.NormalizeCase(column, strings.ToLower(caseType) == "upper")
+
+	case "clean_regex":
+		regexParts := strings.SplitN(arg, "=", 3)
+		if len(regexParts) != 3 {
+			return invalid
+		}
+		p.CleanWithRegex(regexParts[0], regexParts[1], regexParts[2])
+
+	case "split_column":
+		splitParts := strings.SplitN(arg, "=", 3)
+		if len(splitParts) < 3 {
+			return invalid
+		}
+		p.SplitColumn(splitParts[0], splitParts[1], strings.Split(splitParts[2], ","))
+
+	case "filter_outliers":
+		outlierParts := strings.SplitN(arg, "=", 3)
+		if len(outlierParts) != 3 {
+			return invalid
+		}
+		min, err1 := strconv.ParseFloat(outlierParts[1], 64)
+		max, err2 := strconv.ParseFloat(outlierParts[2], 64)
+		if err1 != nil || err2 != nil {
+			return errors.New("filter_outliers: invalid number")
+		}
+		p.FilterOutliers(outlierParts[0], min, max)
+
+	case "normalize_headers":
+		style := SnakeCase
+		transliterate := false
+		if hasArg {
+			name, option, _ := strings.Cut(arg, "=")
+			switch strings.ToLower(name) {
+			case "", "snake":
+			case "camel":
+				style = CamelCase
+			default:
+				return invalid
+			}
+			transliterate = option == "transliterate"
+		}
+		p.NormalizeHeaders(style, transliterate)
+
+	case "drop_constant_columns":
+		threshold := 1.0
+		if arg != "" {
+			var err error
+			if threshold, err = strconv.ParseFloat(arg, 64); err != nil {
+				return errors.New("drop_constant_columns: invalid threshold")
+			}
+		}
+		p.DropConstantColumns(threshold)
+
+	case "add_row_number":
+		if arg == "" {
+			return invalid
+		}
+		p.AddRowNumber(arg)
+
+	case "apply_profile":
+		column, profile, ok := strings.Cut(arg, "=")
+		if !ok {
+			return invalid
+		}
+		if _, ok := Profile(profile); !ok {
+			return fmt.Errorf("apply_profile: unknown profile: %s", profile)
+		}
+		p.ApplyProfile(column, profile)
+
+	case "drop_duplicates":
+		columnList, keepName, _ := strings.Cut(arg, "=")
+		keep := KeepFirst
+		switch keepName {
+		case "", "first":
+		case "last":
+			keep = KeepLast
+		default:
+			return invalid
+		}
+		var columns []string
+		if columnList != "" {
+			columns = strings.Split(columnList, ",")
+		}
+		p.DropDuplicatesKeep(keep, columns...)
+
+	case "dedupe_fuzzy":
+		fuzzyParts := strings.SplitN(arg, "=", 3)
+		if len(fuzzyParts) < 2 {
+			return invalid
+		}
+		threshold, err := strconv.ParseFloat(fuzzyParts[1], 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			return errors.New("dedupe_fuzzy: invalid threshold")
+		}
+		algorithm := Levenshtein
+		if len(fuzzyParts) == 3 {
+			if algorithm, err = ParseStringDistance(fuzzyParts[2]); err != nil {
+				return fmt.Errorf("dedupe_fuzzy: %w", err)
+			}
+		}
+		p.DedupeFuzzy(fuzzyParts[0], threshold, algorithm)
+
+	case "collation":
+		if arg == "" {
+			return invalid
+		}
+		if _, err := parseCollation(arg); err != nil {
+			return err
+		}
+		p.WithCollation(arg)
+
+	case "coalesce_columns":
+		column, columns, ok := strings.Cut(arg, "=")
+		if !ok {
+			return invalid
+		}
+		p.CoalesceColumns(column, strings.Split(columns, ",")...)
+
+	case "strip_prefix", "strip_suffix":
+		column, affix, ok := strings.Cut(arg, "=")
+		if !ok || column == "" || affix == "" {
+			return invalid
+		}
+		if actionType == "strip_prefix" {
+			p.StripPrefix(column, affix)
+		} else {
+			p.StripSuffix(column, affix)
+		}
+
+	case "pad_left":
+		padParts := strings.SplitN(arg, "=", 3)
+		if len(padParts) < 2 {
+			return invalid
+		}
+		width, err := strconv.Atoi(padParts[1])
+		if err != nil || width < 0 {
+			return invalid
+		}
+		char := '0'
+		if len(padParts) == 3 {
+			if utf8.RuneCountInString(padParts[2]) != 1 {
+				return invalid
+			}
+			char, _ = utf8.DecodeRuneInString(padParts[2])
+		}
+		p.PadLeft(padParts[0], width, char)
+
+	case "map_values":
+		mapParts := strings.Split(arg, "=")
+		if len(mapParts) < 2 || len(mapParts) > 3 || mapParts[0] == "" || mapParts[1] == "" {
+			return invalid
+		}
+		mapping := make(map[string]string)
+		for _, pair := range strings.Split(mapParts[1], ",") {
+			from, to, ok := strings.Cut(pair, ":")
+			if !ok {
+				return invalid
+			}
+			mapping[from] = to
+		}
+		keepUnmapped := true
+		if len(mapParts) == 3 {
+			switch mapParts[2] {
+			case "keep":
+			case "empty":
+				keepUnmapped = false
+			default:
+				return invalid
+			}
+		}
+		p.MapValues(mapParts[0], mapping, keepUnmapped)
+
+	case "copy_column", "swap_columns":
+		first, second, ok := strings.Cut(arg, "=")
+		if !ok || first == "" || second == "" {
+			return invalid
+		}
+		if actionType == "copy_column" {
+			p.CopyColumn(first, second)
+		} else {
+			p.SwapColumns(first, second)
+		}
+
+	case "pivot":
+		pivotParts := strings.Split(arg, "=")
+		if len(pivotParts) != 3 {
+			return invalid
+		}
+		p.Pivot(pivotParts[0], pivotParts[1], pivotParts[2])
+
+	case "melt":
+		meltParts := strings.Split(arg, "=")
+		if len(meltParts) != 2 && len(meltParts) != 4 {
+			return invalid
+		}
+		var idColumns, valueColumns []string
+		if meltParts[0] != "" {
+			idColumns = strings.Split(meltParts[0], ",")
+		}
+		if meltParts[1] != "" {
+			valueColumns = strings.Split(meltParts[1], ",")
+		}
+		varName, valueName := "", ""
+		if len(meltParts) == 4 {
+			varName, valueName = meltParts[2], meltParts[3]
+		}
+		p.Melt(idColumns, valueColumns, varName, valueName)
+
+	case "allowed_values", "denied_values":
+		domainParts := strings.Split(arg, "=")
+		if len(domainParts) < 2 || len(domainParts) > 3 || domainParts[0] == "" {
+			return invalid
+		}
+		onViolation := ViolationDrop
+		if len(domainParts) == 3 {
+			var err error
+			if onViolation, err = ParseViolation(domainParts[2]); err != nil {
+				return fmt.Errorf("%s: %w", actionType, err)
+			}
+		}
+		values := strings.Split(domainParts[1], ",")
+		if actionType == "allowed_values" {
+			p.EnforceAllowedValues(domainParts[0], values, onViolation)
+		} else {
+			p.EnforceDeniedValues(domainParts[0], values, onViolation)
+		}
+
+	case "sample", "sample_fraction":
+		size, seedText, _ := strings.Cut(arg, "=")
+		var seed int64
+		if seedText != "" {
+			var err error
+			if seed, err = strconv.ParseInt(seedText, 10, 64); err != nil {
+				return invalid
+			}
+		}
+		if actionType == "sample" {
+			n, err := strconv.Atoi(size)
+			if err != nil || n < 0 {
+				return invalid
+			}
+			p.Sample(n, seed)
+		} else {
+			fraction, err := strconv.ParseFloat(size, 64)
+			if err != nil || fraction < 0 || fraction > 1 {
+				return invalid
+			}
+			p.SampleFraction(fraction, seed)
+		}
+
+	case "sample_stratified":
+		sampleParts := strings.Split(arg, "=")
+		if len(sampleParts) < 2 || len(sampleParts) > 3 {
+			return invalid
+		}
+		fraction, err := strconv.ParseFloat(sampleParts[1], 64)
+		if err != nil || fraction < 0 || fraction > 1 {
+			return invalid
+		}
+		var seed int64
+		if len(sampleParts) == 3 {
+			if seed, err = strconv.ParseInt(sampleParts[2], 10, 64); err != nil {
+				return invalid
+			}
+		}
+		p.SampleStratified(sampleParts[0], fraction, seed)
+
+	case "assert_sorted":
+		column, order, _ := strings.Cut(arg, "=")
+		if column == "" || (order != "" && order != "asc" && order != "desc") {
+			return invalid
+		}
+		p.AssertSorted(column, order != "desc")
+
+	case "assert_unique", "assert_non_null":
+		var columns []string
+		if arg != "" {
+			columns = strings.Split(arg, ",")
+		}
+		if actionType == "assert_unique" {
+			p.AssertUnique(columns...)
+		} else {
+			p.AssertNonNull(columns...)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package cleaner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePipeline(t *testing.T) {
+	specs := map[string]string{
+		"yaml": "name: customers\nactions:\n  - trim\n  - replace_nulls:age=0\n  - normalize_case:name=upper\n",
+		"json": `{"name": "customers", "actions": ["trim", "replace_nulls:age=0", "normalize_case:name=upper"]}`,
+	}
+	for format, spec := range specs {
+		pipeline, err := ParsePipeline([]byte(spec))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		df, _ := NewDataFrame([]string{"name", "age"}, [][]string{{" ali ", ""}})
+		df, err = pipeline.Run(df)
+		if err != nil {
+			t.Fatalf("%s: unexpected run error: %v", format, err)
+		}
+		if !reflect.DeepEqual(df.Data, [][]string{{"ALI", "0"}}) {
+			t.Errorf("%s: unexpected data: %v", format, df.Data)
+		}
+	}
+
+	if _, err := ParsePipeline([]byte(`{"actions": ["trim", "lowercase"]}`)); !errors.Is(err, ErrUnknownAction) {
+		t.Errorf("expected ErrUnknownAction, got %v", err)
+	}
+	if _, err := ParsePipeline([]byte(`{"actions": ["replace_nulls:age"]}`)); err == nil || !strings.Contains(err.Error(), "replace_nulls:column=value") {
+		t.Errorf("expected an invalid arguments error, got %v", err)
+	}
+	if _, err := ParsePipeline([]byte("actions: [trim")); err == nil {
+		t.Error("expected an error for an invalid spec")
+	}
+}
+
+func TestLoadPipeline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+	os.WriteFile(path, []byte("actions:\n  - clean_regex:phone=@non_digits=\n"), 0644)
+
+	pipeline, err := LoadPipeline(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	df, _ := NewDataFrame([]string{"phone"}, [][]string{{"(555) 123"}})
+	if df, err = pipeline.Run(df); err != nil || df.Data[0][0] != "555123" {
+		t.Errorf("unexpected result: %v %v", df, err)
+	}
+
+	spec, err := LoadPipelineSpec(path)
+	if err != nil || !reflect.DeepEqual(spec.Actions, []string{"clean_regex:phone=@non_digits="}) {
+		t.Errorf("unexpected spec: %v %v", spec, err)
+	}
+	if _, err := LoadPipeline(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,51 @@
+package cleaner
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// StripPrefix removes prefix from the start of the values of the column that begin with it, e.g.
+// "CUST-" from "CUST-1042". Other values are left unchanged.
+func (df *DataFrame) StripPrefix(column, prefix string) (*DataFrame, error) {
+	return df.mapColumn(column, func(value string) string { return strings.TrimPrefix(value, prefix) })
+}
+
+// StripSuffix removes suffix from the end of the values of the column that end with it, e.g.
+// " Ltd." from company names. Other values are left unchanged.
+func (df *DataFrame) StripSuffix(column, suffix string) (*DataFrame, error) {
+	return df.mapColumn(column, func(value string) string { return strings.TrimSuffix(value, suffix) })
+}
+
+// PadLeft pads the values of the column on the left with char up to width characters, e.g. "42"
+// to "00000042" with width 8 and char '0'. Empty values and values of at least width characters
+// are left unchanged. Padded identifiers are only kept as text in columns set with
+// WithStringColumns; otherwise they may be written as numbers without their leading zeros.
+func (df *DataFrame) PadLeft(column string, width int, char rune) (*DataFrame, error) {
+	if width < 0 {
+		return nil, fmt.Errorf("pad width must not be negative: %d", width)
+	}
+	return df.mapColumn(column, func(value string) string { return padLeft(value, width, char) })
+}
+
+// mapColumn replaces every value of the column with fn(value)
+func (df *DataFrame) mapColumn(column string, fn func(value string) string) (*DataFrame, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	for i := range df.Data {
+		df.setCell(i, colIndex, fn(df.Data[i][colIndex]))
+	}
+	return df, nil
+}
+
+// padLeft pads a non-empty value on the left with char up to width characters
+func padLeft(value string, width int, char rune) string {
+	n := utf8.RuneCountInString(value)
+	if value == "" || n >= width {
+		return value
+	}
+	return strings.Repeat(string(char), width-n) + value
+}
@@ -0,0 +1,70 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStripPrefixAndSuffix(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "company"}, [][]string{
+		{"CUST-1042", "Acme Ltd."},
+		{"1043", "Globex"},
+		{"CUST-", "Ltd."},
+	})
+
+	if _, err := df.StripPrefix("id", "CUST-"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := df.StripSuffix("company", " Ltd."); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{{"1042", "Acme"}, {"1043", "Globex"}, {"", "Ltd."}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected rows: %v", df.Data)
+	}
+	if _, err := df.StripPrefix("missing", "x"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestPadLeft(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id"}, [][]string{{"42"}, {""}, {"123456789"}, {"ğü"}})
+
+	if _, err := df.PadLeft("id", 8, '0'); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{{"00000042"}, {""}, {"123456789"}, {"000000ğü"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected rows: %v", df.Data)
+	}
+	if _, err := df.PadLeft("id", -1, '0'); err == nil {
+		t.Error("expected an error for a negative width")
+	}
+}
+
+func TestPipelineAffixes(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id"}, [][]string{{" CUST-42-TR "}, {"CUST-7-TR"}})
+	pipeline := NewPipeline()
+	if err := pipeline.Actions("trim", "strip_prefix:id=CUST-", "strip_suffix:id=-TR", "pad_left:id=4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := pipeline.Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Data, [][]string{{"0042"}, {"0007"}}) {
+		t.Errorf("unexpected rows: %v", result.Data)
+	}
+
+	result, _ = NewPipeline().PadLeft("id", 6, '*').Run(result)
+	if result.Data[0][0] != "**0042" {
+		t.Errorf("expected padding with *, got %s", result.Data[0][0])
+	}
+
+	for _, action := range []string{"pad_left:id", "pad_left:id=x", "pad_left:id=4=ab", "strip_prefix:id"} {
+		if err := NewPipeline().Action(action); err == nil {
+			t.Errorf("%s: expected a usage error", action)
+		}
+	}
+}
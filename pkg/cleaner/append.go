@@ -0,0 +1,192 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/mstgnz/cleango/pkg/formats"
+)
+
+// ErrInputTruncated is returned by AppendClean when an input is smaller than when it was last processed
+var ErrInputTruncated = errors.New("input is smaller than when it was last processed")
+
+// ledgerSuffix is appended to the output path to name its ledger file
+const ledgerSuffix = ".ledger.json"
+
+// appendLedger records how far every input has been processed into an output
+type appendLedger struct {
+	Inputs map[string]ledgerEntry `json:"inputs"`
+}
+
+// ledgerEntry is the processed offset of one input
+type ledgerEntry struct {
+	Rows int   `json:"rows"` // Data rows already processed
+	Size int64 `json:"size"` // Input size in bytes when it was processed
+}
+
+// WithCSVOptions sets the CSV options AppendClean uses to read the input and write the output
+func WithCSVOptions(options ...formats.CSVOption) StreamOption {
+	return func(o *StreamOptions) {
+		o.CSVOptions = append(o.CSVOptions, options...)
+	}
+}
+
+// AppendClean cleans the rows of the CSV file newInput that have not been processed yet and appends
+// them to the CSV file existingOutput, so an input that grows (or a new hourly file) does not trigger
+// full reprocessing. The pipeline may be a *Pipeline or any other StreamStep; nil copies the rows.
+//
+// The processed row offset of every input is kept in a ledger next to the output
+// (existingOutput + ".ledger.json"). The output is created with a header line if it does not exist;
+// otherwise the cleaned headers must match its header line. Stateful steps such as DedupStep only see
+// the rows of the current run. The ledger is updated after the rows are written, so a run that fails
+// in between processes its rows again on the next call.
+func AppendClean(existingOutput, newInput string, pipeline StreamStep, options ...StreamOption) (*StreamStats, error) {
+	opts := defaultStreamOptions()
+	for _, option := range options {
+		option(opts)
+	}
+
+	ledgerPath := existingOutput + ledgerSuffix
+	ledger, err := readLedger(ledgerPath)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := filepath.Abs(newInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve input path: %w", err)
+	}
+	info, err := os.Stat(newInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	entry := ledger.Inputs[key]
+	if info.Size() < entry.Size {
+		return nil, fmt.Errorf("%w: %s", ErrInputTruncated, newInput)
+	}
+
+	existingHeaders, err := outputHeaders(existingOutput, opts.CSVOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := formats.NewCSVRowReader(newInput, opts.CSVOptions...)
+	if err != nil {
+		return nil, err
+	}
+	sink, err := formats.NewCSVRowWriter(existingOutput, append(slices.Clone(opts.CSVOptions), formats.WithAppend(true))...)
+	if err != nil {
+		source.Close()
+		return nil, err
+	}
+
+	stream := NewStream(&offsetReader{RowReader: source, skip: entry.Rows}, options...)
+	if pipeline != nil {
+		stream.Then(pipeline)
+	}
+	stats, err := stream.Run(&appendSink{RowWriter: sink, existing: existingHeaders})
+	if err != nil {
+		return nil, err
+	}
+
+	ledger.Inputs[key] = ledgerEntry{Rows: entry.Rows + stats.RowsRead, Size: info.Size()}
+	if err := writeLedger(ledgerPath, ledger); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// readLedger reads the ledger file, or returns an empty ledger if it does not exist
+func readLedger(path string) (*appendLedger, error) {
+	ledger := &appendLedger{Inputs: map[string]ledgerEntry{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ledger, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+	if err := json.Unmarshal(data, ledger); err != nil {
+		return nil, fmt.Errorf("failed to decode ledger: %w", err)
+	}
+	if ledger.Inputs == nil {
+		ledger.Inputs = map[string]ledgerEntry{}
+	}
+	return ledger, nil
+}
+
+// writeLedger replaces the ledger file through a temporary file
+func writeLedger(path string, ledger *appendLedger) error {
+	data, err := json.MarshalIndent(ledger, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode ledger: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	return nil
+}
+
+// outputHeaders returns the header line of an existing output, or nil if it does not exist or is empty
+func outputHeaders(path string, options []formats.CSVOption) ([]string, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	if info.Size() == 0 {
+		return nil, nil
+	}
+
+	reader, err := formats.NewCSVRowReader(path, options...)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return reader.Headers(), nil
+}
+
+// offsetReader skips the rows of its RowReader that were already processed
+type offsetReader struct {
+	formats.RowReader
+	skip int
+}
+
+func (r *offsetReader) Read(n int) ([][]string, error) {
+	for r.skip > 0 {
+		rows, err := r.RowReader.Read(min(r.skip, max(n, 1)))
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				r.skip = 0
+			}
+			return nil, err
+		}
+		r.skip -= len(rows)
+	}
+	return r.RowReader.Read(n)
+}
+
+// appendSink checks the cleaned headers against the header line of the existing output
+type appendSink struct {
+	formats.RowWriter
+	existing []string
+}
+
+func (s *appendSink) WriteHeaders(headers []string) error {
+	if s.existing != nil && !slices.Equal(headers, s.existing) {
+		return fmt.Errorf("cleaned headers %v do not match the existing output headers %v", headers, s.existing)
+	}
+	return s.RowWriter.WriteHeaders(headers)
+}
@@ -0,0 +1,100 @@
+package cleaner
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/formats"
+)
+
+func TestAppendClean(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "events.csv")
+	output := filepath.Join(dir, "clean.csv")
+	pipeline := NewPipeline().Trim().NormalizeCase("name", true)
+
+	if err := os.WriteFile(input, []byte("id,name\n1, ali \n2,ayşe\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := AppendClean(output, input, pipeline, WithChunkSize(1))
+	if err != nil {
+		t.Fatalf("AppendClean error: %v", err)
+	}
+	if stats.RowsRead != 2 || stats.RowsWritten != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// Only the rows added since the last run are processed
+	if err := os.WriteFile(input, []byte("id,name\n1, ali \n2,ayşe\n3, veli\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stats, err = AppendClean(output, input, pipeline)
+	if err != nil {
+		t.Fatalf("AppendClean error: %v", err)
+	}
+	if stats.RowsRead != 1 {
+		t.Errorf("expected 1 new row, got %d", stats.RowsRead)
+	}
+
+	// Nothing new: the output is left as it is
+	if _, err := AppendClean(output, input, pipeline); err != nil {
+		t.Fatalf("AppendClean error: %v", err)
+	}
+
+	content, _ := os.ReadFile(output)
+	if string(content) != "id,name\n1,ALI\n2,AYŞE\n3,VELI\n" {
+		t.Errorf("unexpected output: %q", content)
+	}
+}
+
+func TestAppendClean_NewInputFile(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "clean.csv")
+	for i, content := range []string{"id;name\n1;a\n", "id;name\n2;b\n"} {
+		input := filepath.Join(dir, []string{"00.csv", "01.csv"}[i])
+		if err := os.WriteFile(input, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := AppendClean(output, input, nil, WithCSVOptions(formats.WithDelimiter(';'))); err != nil {
+			t.Fatalf("AppendClean error: %v", err)
+		}
+	}
+
+	content, _ := os.ReadFile(output)
+	if string(content) != "id;name\n1;a\n2;b\n" {
+		t.Errorf("unexpected output: %q", content)
+	}
+}
+
+func TestAppendClean_Errors(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.csv")
+	output := filepath.Join(dir, "clean.csv")
+	if err := os.WriteFile(input, []byte("id,name\n1,a\n2,b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AppendClean(output, input, nil); err != nil {
+		t.Fatalf("AppendClean error: %v", err)
+	}
+
+	if err := os.WriteFile(input, []byte("id,name\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AppendClean(output, input, nil); !errors.Is(err, ErrInputTruncated) {
+		t.Errorf("expected ErrInputTruncated, got %v", err)
+	}
+
+	other := filepath.Join(dir, "other.csv")
+	if err := os.WriteFile(other, []byte("id,email\n3,c\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AppendClean(output, other, nil); err == nil {
+		t.Error("expected a header mismatch error")
+	}
+	content, _ := os.ReadFile(output)
+	if string(content) != "id,name\n1,a\n2,b\n" {
+		t.Errorf("output changed after a failed run: %q", content)
+	}
+}
@@ -0,0 +1,131 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errNilTransform is returned when a column transform has no function
+var errNilTransform = errors.New("transform function cannot be nil")
+
+// ApplyColumn replaces every value of the column with fn(value), to plug a transformation the
+// package does not provide into the cleaning flow, e.g.
+//
+//	df, err := df.ApplyColumn("price_cents", func(value string) (string, error) {
+//		cents, err := strconv.Atoi(value)
+//		return fmt.Sprintf("%d.%02d", cents/100, cents%100), err
+//	})
+//
+// It stops at the first error of fn and returns it with the row and the column; the values of the
+// earlier rows are already replaced.
+func (df *DataFrame) ApplyColumn(column string, fn func(value string) (string, error)) (*DataFrame, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	if fn == nil {
+		return nil, errNilTransform
+	}
+
+	for i := range df.Data {
+		value, err := fn(df.Data[i][colIndex])
+		if err != nil {
+			return nil, applyError(i, column, err)
+		}
+		df.setCell(i, colIndex, value)
+	}
+	return df, nil
+}
+
+// ApplyColumnParallel replaces every value of the column with fn(value) like ApplyColumn, with the
+// rows split across workers. fn must be safe for concurrent use. On an error, the values before the
+// first failing row are replaced and its error is returned, as in a serial run.
+func (df *DataFrame) ApplyColumnParallel(column string, fn func(value string) (string, error), options ...func(*ParallelOptions)) (*DataFrame, error) {
+	return df.runObserved("apply_column", column, options, func(opts *ParallelOptions) (*DataFrame, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		if fn == nil {
+			return nil, errNilTransform
+		}
+
+		err = df.parallelizeColumnStrict(opts, colIndex, func(i int, value string) (string, error) {
+			value, err := fn(value)
+			if err != nil {
+				return value, applyError(i, column, err)
+			}
+			return value, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return df, nil
+	})
+}
+
+// ApplyRow replaces the values of every row with the values fn returns by column name, for
+// transformations that need several fields at once, e.g. recomputing the total of an order:
+//
+//	df, err := df.ApplyRow(func(row map[string]string) (map[string]string, error) {
+//		qty, err := strconv.Atoi(row["qty"])
+//		if err != nil {
+//			return nil, err
+//		}
+//		price, err := strconv.ParseFloat(row["price"], 64)
+//		row["total"] = strconv.FormatFloat(float64(qty)*price, 'f', 2, 64)
+//		return row, err
+//	})
+//
+// The row maps the column names to the values. fn may change and return it, or return a new map;
+// columns missing from the returned map keep their values, and a column that is not in the
+// DataFrame, such as a total not added with AddColumn yet, is an error. The row is reused between
+// rows, so fn must not retain it. It stops at the first error like ApplyColumn.
+func (df *DataFrame) ApplyRow(fn func(row map[string]string) (map[string]string, error)) (*DataFrame, error) {
+	if fn == nil {
+		return nil, errNilTransform
+	}
+
+	index := df.headerIndex()
+	row := make(map[string]string, len(df.Headers))
+	for i := range df.Data {
+		if err := df.applyRow(i, row, index, fn); err != nil {
+			return nil, err
+		}
+	}
+	return df, nil
+}
+
+// headerIndex returns the index of every column by name
+func (df *DataFrame) headerIndex() map[string]int {
+	index := make(map[string]int, len(df.Headers))
+	for j, header := range df.Headers {
+		index[header] = j
+	}
+	return index
+}
+
+// applyRow calls fn with the values of row i in row and writes the values it returns. The columns
+// are checked before any value is written, so a failing row is left unchanged.
+func (df *DataFrame) applyRow(i int, row map[string]string, index map[string]int, fn func(row map[string]string) (map[string]string, error)) error {
+	values, err := fn(df.rowMap(i, row))
+	if err != nil {
+		return fmt.Errorf("row %d: %w", i, err)
+	}
+	for column := range values {
+		if _, ok := index[column]; !ok {
+			return fmt.Errorf("row %d: %w: %s", i, ErrColumnNotFound, column)
+		}
+	}
+	for column, value := range values {
+		if j := index[column]; df.Data[i][j] != value {
+			df.setCell(i, j, value)
+		}
+	}
+	return nil
+}
+
+// applyError adds the row and the column to an error of a column transform
+func applyError(i int, column string, err error) error {
+	return fmt.Errorf("row %d, column %s: %w", i, column, err)
+}
@@ -0,0 +1,166 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// centsToAmount formats an integer number of cents as an amount
+func centsToAmount(value string) (string, error) {
+	cents, err := strconv.Atoi(value)
+	if err != nil {
+		return value, err
+	}
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100), nil
+}
+
+func applyTestFrame(prices ...string) *DataFrame {
+	rows := make([][]string, len(prices))
+	for i, price := range prices {
+		rows[i] = []string{strconv.Itoa(i + 1), price}
+	}
+	df, _ := NewDataFrame([]string{"id", "price"}, rows)
+	return df
+}
+
+func TestApplyColumn(t *testing.T) {
+	df, err := applyTestFrame("1999", "5", "120000").ApplyColumn("price", centsToAmount)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{{"1", "19.99"}, {"2", "0.05"}, {"3", "1200.00"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+
+	df = applyTestFrame("1999", "n/a", "5")
+	_, err = df.ApplyColumn("price", centsToAmount)
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatalf("expected the error of the function, got %v", err)
+	}
+	if err.Error() != `row 1, column price: strconv.Atoi: parsing "n/a": invalid syntax` {
+		t.Errorf("unexpected error message: %v", err)
+	}
+	if df.Data[0][1] != "19.99" || df.Data[2][1] != "5" {
+		t.Errorf("expected only the rows before the error to change, got %v", df.Data)
+	}
+
+	if _, err := df.ApplyColumn("missing", centsToAmount); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := df.ApplyColumn("price", nil); err == nil {
+		t.Error("expected an error for a nil function")
+	}
+}
+
+func TestApplyColumnParallel(t *testing.T) {
+	prices := make([]string, 100)
+	for i := range prices {
+		prices[i] = strconv.Itoa(i * 37)
+	}
+	serial, _ := applyTestFrame(prices...).ApplyColumn("price", centsToAmount)
+	parallel, err := applyTestFrame(prices...).ApplyColumnParallel("price", centsToAmount, WithMaxWorkers(4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(parallel.Data, serial.Data) {
+		t.Errorf("expected the serial result, got %v", parallel.Data)
+	}
+
+	prices[60], prices[30] = "x", "y"
+	df := applyTestFrame(prices...)
+	_, serialErr := applyTestFrame(prices...).ApplyColumn("price", centsToAmount)
+	_, err = df.ApplyColumnParallel("price", centsToAmount, WithMaxWorkers(4))
+	if err == nil || err.Error() != serialErr.Error() {
+		t.Errorf("expected the serial error %v, got %v", serialErr, err)
+	}
+	if df.Data[29][1] != "10.73" || df.Data[31][1] != prices[31] {
+		t.Errorf("expected only the rows before the first error to change, got %v and %v", df.Data[29], df.Data[31])
+	}
+}
+
+func TestPipelineApplyColumn(t *testing.T) {
+	df, err := NewPipeline().Trim().ApplyColumn("price", centsToAmount).Run(applyTestFrame(" 1999 ", "5"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Data, [][]string{{"1", "19.99"}, {"2", "0.05"}}) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+
+	if _, err := NewPipeline().ApplyColumn("price", centsToAmount).Run(applyTestFrame("x")); err == nil {
+		t.Error("expected the error of the function")
+	}
+}
+
+// orderTotal recomputes the total of an order from its quantity and price
+func orderTotal(row map[string]string) (map[string]string, error) {
+	qty, err := strconv.Atoi(row["qty"])
+	if err != nil {
+		return nil, err
+	}
+	price, err := strconv.ParseFloat(row["price"], 64)
+	row["total"] = strconv.FormatFloat(float64(qty)*price, 'f', 2, 64)
+	return row, err
+}
+
+func orderTestFrame() *DataFrame {
+	df, _ := NewDataFrame([]string{"qty", "price", "total"}, [][]string{
+		{"2", "9.99", ""},
+		{"3", "0.5", "99"},
+	})
+	return df
+}
+
+func TestApplyRow(t *testing.T) {
+	df, err := orderTestFrame().ApplyRow(orderTotal)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{{"2", "9.99", "19.98"}, {"3", "0.5", "1.50"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+
+	// A new map with some of the columns leaves the others unchanged
+	df, err = orderTestFrame().ApplyRow(func(row map[string]string) (map[string]string, error) {
+		return map[string]string{"total": row["qty"]}, nil
+	})
+	if err != nil || df.Data[1][1] != "0.5" || df.Data[1][2] != "3" {
+		t.Errorf("unexpected result: %v, %v", df.Data, err)
+	}
+
+	df = orderTestFrame()
+	df.Data[1][0] = "x"
+	if _, err := df.ApplyRow(orderTotal); err == nil || err.Error() != `row 1: strconv.Atoi: parsing "x": invalid syntax` {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if df.Data[0][2] != "19.98" {
+		t.Errorf("expected the rows before the error to change, got %v", df.Data[0])
+	}
+
+	df = orderTestFrame()
+	_, err = df.ApplyRow(func(row map[string]string) (map[string]string, error) {
+		row["qty"], row["discount"] = "1", "0"
+		return row, nil
+	})
+	if !errors.Is(err, ErrColumnNotFound) || df.Data[0][0] != "2" {
+		t.Errorf("expected ErrColumnNotFound and an unchanged row, got %v, %v", err, df.Data[0])
+	}
+	if _, err := df.ApplyRow(nil); err == nil {
+		t.Error("expected an error for a nil function")
+	}
+}
+
+func TestPipelineApplyRow(t *testing.T) {
+	df, err := NewPipeline().Trim().ApplyRow(orderTotal).Run(orderTestFrame(), WithMaxWorkers(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if df.Data[0][2] != "19.98" || df.Data[1][2] != "1.50" {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+}
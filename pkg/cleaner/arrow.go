@@ -0,0 +1,193 @@
+package cleaner
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// ArrowStreamContentType is the media type of the Arrow IPC stream format
+const ArrowStreamContentType = "application/vnd.apache.arrow.stream"
+
+// arrowTypes maps column types to Arrow types; other types are written as strings
+var arrowTypes = map[Type]arrow.DataType{
+	TypeInt:   arrow.PrimitiveTypes.Int64,
+	TypeFloat: arrow.PrimitiveTypes.Float64,
+	TypeBool:  arrow.FixedWidthTypes.Boolean,
+}
+
+// ArrowSchema returns the Arrow schema of DataFrame. Int, float and bool columns become Int64, Float64
+// and Boolean fields; all other columns are strings. All fields are nullable.
+func (df *DataFrame) ArrowSchema() *arrow.Schema {
+	fields := make([]arrow.Field, len(df.Headers))
+	for i, header := range df.Headers {
+		dataType, ok := arrowTypes[df.Types[header]]
+		if !ok {
+			dataType = arrow.BinaryTypes.String
+		}
+		fields[i] = arrow.Field{Name: header, Type: dataType, Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// WriteArrowStream writes DataFrame to w in the Arrow IPC stream format, one record batch per
+// batchSize rows (all rows when batchSize <= 0). Empty values of typed columns are written as nulls.
+func (df *DataFrame) WriteArrowStream(w io.Writer, batchSize int) error {
+	schema := df.ArrowSchema()
+	mem := memory.NewGoAllocator()
+	writer := ipc.NewWriter(w, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+
+	if batchSize <= 0 {
+		batchSize = max(len(df.Data), 1)
+	}
+	for start := 0; start < len(df.Data); start += batchSize {
+		record, err := df.arrowRecord(mem, schema, start, min(start+batchSize, len(df.Data)))
+		if err != nil {
+			writer.Close()
+			return err
+		}
+		err = writer.Write(record)
+		record.Release()
+		if err != nil {
+			writer.Close()
+			return fmt.Errorf("failed to write Arrow record batch: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close Arrow stream: %w", err)
+	}
+	return nil
+}
+
+// arrowRecord builds a record of the rows [start, end). The caller releases the record.
+func (df *DataFrame) arrowRecord(mem memory.Allocator, schema *arrow.Schema, start, end int) (arrow.Record, error) {
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+	builder.Reserve(end - start)
+
+	for j, field := range builder.Fields() {
+		for i := start; i < end; i++ {
+			value := df.Data[i][j]
+			if value == "" && schema.Field(j).Type.ID() != arrow.STRING {
+				field.AppendNull()
+				continue
+			}
+			if err := appendArrowValue(field, value); err != nil {
+				return nil, fmt.Errorf("row %d, column %s: %w", i, df.Headers[j], err)
+			}
+		}
+	}
+	return builder.NewRecord(), nil
+}
+
+// appendArrowValue parses the value for the builder type and appends it
+func appendArrowValue(builder array.Builder, value string) error {
+	switch b := builder.(type) {
+	case *array.Int64Builder:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("conversion error: %w", err)
+		}
+		b.Append(v)
+	case *array.Float64Builder:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("conversion error: %w", err)
+		}
+		b.Append(v)
+	case *array.BooleanBuilder:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("conversion error: %w", err)
+		}
+		b.Append(v)
+	case *array.StringBuilder:
+		b.Append(value)
+	default:
+		return fmt.Errorf("unsupported Arrow type: %s", builder.Type())
+	}
+	return nil
+}
+
+// ToArrow converts DataFrame to an Arrow record with the schema of ArrowSchema.
+// The caller must call Release on the record.
+func (df *DataFrame) ToArrow() (arrow.Record, error) {
+	return df.arrowRecord(memory.NewGoAllocator(), df.ArrowSchema(), 0, len(df.Data))
+}
+
+// FromArrowRecord converts an Arrow record to DataFrame. Integer, floating point, boolean, date and
+// timestamp columns keep their type; nulls become empty values. Dates are formatted as 2006-01-02
+// and timestamps in RFC 3339 format.
+func FromArrowRecord(record arrow.Record) (*DataFrame, error) {
+	schema := record.Schema()
+	headers := make([]string, schema.NumFields())
+	types := make(map[string]Type, len(headers))
+	rows := make([][]string, record.NumRows())
+	for i := range rows {
+		rows[i] = make([]string, len(headers))
+	}
+
+	for j, field := range schema.Fields() {
+		headers[j] = field.Name
+		column := record.Column(j)
+		types[field.Name] = arrowColumnType(field.Type)
+
+		format := arrowFormatter(column)
+		for i := range rows {
+			if !column.IsNull(i) {
+				rows[i][j] = format(i)
+			}
+		}
+	}
+
+	df, err := NewDataFrame(headers, rows)
+	if err != nil {
+		return nil, err
+	}
+	for header, t := range types {
+		df.Types[header] = t
+	}
+	return df, nil
+}
+
+// arrowColumnType returns the column type of an Arrow type
+func arrowColumnType(dataType arrow.DataType) Type {
+	switch dataType.ID() {
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64, arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+		return TypeInt
+	case arrow.FLOAT16, arrow.FLOAT32, arrow.FLOAT64, arrow.DECIMAL128, arrow.DECIMAL256:
+		return TypeFloat
+	case arrow.BOOL:
+		return TypeBool
+	case arrow.DATE32, arrow.DATE64, arrow.TIMESTAMP:
+		return TypeDate
+	default:
+		return TypeString
+	}
+}
+
+// arrowFormatter returns a function that formats the non-null value at index i of the column
+func arrowFormatter(column arrow.Array) func(i int) string {
+	switch c := column.(type) {
+	case *array.String:
+		return c.Value
+	case *array.LargeString:
+		return c.Value
+	case *array.Date32:
+		return func(i int) string { return c.Value(i).ToTime().Format(time.DateOnly) }
+	case *array.Date64:
+		return func(i int) string { return c.Value(i).ToTime().UTC().Format(time.DateOnly) }
+	case *array.Timestamp:
+		unit := c.DataType().(*arrow.TimestampType).Unit
+		return func(i int) string { return c.Value(i).ToTime(unit).UTC().Format(time.RFC3339Nano) }
+	default:
+		return column.ValueStr
+	}
+}
@@ -0,0 +1,142 @@
+package cleaner
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestWriteArrowStream(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "price", "active", "name"}, [][]string{
+		{"1", "9.5", "true", "Ali"},
+		{"2", "", "false", ""},
+		{"3", "12", "", "Ayşe"},
+	})
+	df.InferTypes()
+
+	var buf bytes.Buffer
+	if err := df.WriteArrowStream(&buf, 2); err != nil {
+		t.Fatalf("WriteArrowStream error: %v", err)
+	}
+
+	reader, err := ipc.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("ipc.NewReader error: %v", err)
+	}
+	defer reader.Release()
+
+	expectedTypes := []arrow.Type{arrow.INT64, arrow.FLOAT64, arrow.BOOL, arrow.STRING}
+	for i, field := range reader.Schema().Fields() {
+		if field.Type.ID() != expectedTypes[i] {
+			t.Errorf("field %s: expected %s, got %s", field.Name, expectedTypes[i], field.Type)
+		}
+	}
+
+	var batches, rows int
+	var prices []string
+	for reader.Next() {
+		record := reader.Record()
+		batches++
+		rows += int(record.NumRows())
+		col := record.Column(1).(*array.Float64)
+		for i := 0; i < col.Len(); i++ {
+			prices = append(prices, col.ValueStr(i))
+		}
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("reader error: %v", err)
+	}
+	if batches != 2 || rows != 3 {
+		t.Errorf("expected 2 batches with 3 rows, got %d batches with %d rows", batches, rows)
+	}
+	if expected := []string{"9.5", "(null)", "12"}; len(prices) != 3 || prices[0] != expected[0] || prices[1] != expected[1] || prices[2] != expected[2] {
+		t.Errorf("expected prices %v, got %v", expected, prices)
+	}
+}
+
+func TestWriteArrowStream_ConversionError(t *testing.T) {
+	df, _ := NewDataFrame([]string{"age"}, [][]string{{"30"}, {"thirty"}})
+	df.Types["age"] = TypeInt
+
+	if err := df.WriteArrowStream(&bytes.Buffer{}, 0); err == nil {
+		t.Error("expected a conversion error")
+	}
+}
+
+func TestToArrowAndFromArrowRecord(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "price", "active", "name"}, [][]string{
+		{"1", "9.5", "true", "Ali"},
+		{"2", "", "false", ""},
+	})
+	df.InferTypes()
+
+	record, err := df.ToArrow()
+	if err != nil {
+		t.Fatalf("ToArrow error: %v", err)
+	}
+	defer record.Release()
+	if record.NumRows() != 2 || record.NumCols() != 4 {
+		t.Fatalf("expected 2x4 record, got %dx%d", record.NumRows(), record.NumCols())
+	}
+	if !record.Column(1).IsNull(1) {
+		t.Errorf("expected empty price to be null")
+	}
+
+	back, err := FromArrowRecord(record)
+	if err != nil {
+		t.Fatalf("FromArrowRecord error: %v", err)
+	}
+	for i := range df.Data {
+		for j := range df.Headers {
+			if back.Data[i][j] != df.Data[i][j] {
+				t.Errorf("cell (%d,%d): expected %q, got %q", i, j, df.Data[i][j], back.Data[i][j])
+			}
+		}
+	}
+	expectedTypes := map[string]Type{"id": TypeInt, "price": TypeFloat, "active": TypeBool, "name": TypeString}
+	for header, expected := range expectedTypes {
+		if back.Types[header] != expected {
+			t.Errorf("column %s: expected type %s, got %s", header, expected, back.Types[header])
+		}
+	}
+}
+
+func TestFromArrowRecord_Dates(t *testing.T) {
+	mem := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "day", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "at", Type: &arrow.TimestampType{Unit: arrow.Millisecond}, Nullable: true},
+	}, nil)
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+
+	builder.Field(0).(*array.Date32Builder).Append(arrow.Date32(19723)) // 2024-01-01
+	builder.Field(0).(*array.Date32Builder).AppendNull()
+	builder.Field(1).(*array.TimestampBuilder).Append(arrow.Timestamp(1704110400500))
+	builder.Field(1).(*array.TimestampBuilder).Append(arrow.Timestamp(0))
+	record := builder.NewRecord()
+	defer record.Release()
+
+	df, err := FromArrowRecord(record)
+	if err != nil {
+		t.Fatalf("FromArrowRecord error: %v", err)
+	}
+	expected := [][]string{
+		{"2024-01-01", "2024-01-01T12:00:00.5Z"},
+		{"", "1970-01-01T00:00:00Z"},
+	}
+	for i, row := range expected {
+		for j, value := range row {
+			if df.Data[i][j] != value {
+				t.Errorf("cell (%d,%d): expected %q, got %q", i, j, value, df.Data[i][j])
+			}
+		}
+	}
+	if df.Types["day"] != TypeDate || df.Types["at"] != TypeDate {
+		t.Errorf("expected date types, got %v", df.Types)
+	}
+}
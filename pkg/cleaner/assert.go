@@ -0,0 +1,91 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrAssertionFailed is returned when the data does not meet an assertion, see AssertSorted,
+// AssertUnique and AssertNonNull
+var ErrAssertionFailed = errors.New("assertion failed")
+
+// IsSorted reports whether the rows are sorted by the column, ascending or descending; equal
+// values may follow each other. Int and float columns are compared as numbers, the others
+// lexicographically, like SortByColumn.
+func (df *DataFrame) IsSorted(column string, ascending bool) (bool, error) {
+	row, err := df.unsortedRow(column, ascending)
+	return row == -1, err
+}
+
+// AssertSorted returns an ErrAssertionFailed error naming the first row out of order if the rows
+// are not sorted by the column, see IsSorted
+func (df *DataFrame) AssertSorted(column string, ascending bool) error {
+	row, err := df.unsortedRow(column, ascending)
+	if err != nil || row == -1 {
+		return err
+	}
+	order := "ascending"
+	if !ascending {
+		order = "descending"
+	}
+	colIndex := df.getColumnIndex(column)
+	return fmt.Errorf("%w: column %s is not sorted %s: row %d (%s) follows %s", ErrAssertionFailed,
+		column, order, row, df.Data[row][colIndex], df.Data[row-1][colIndex])
+}
+
+// AssertUnique returns an ErrAssertionFailed error naming the first repeated row if the values of
+// the columns are not unique, e.g. for a primary key. Without columns, whole rows are compared.
+func (df *DataFrame) AssertUnique(columns ...string) error {
+	indices, err := df.columnIndices(columns)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]int, len(df.Data))
+	for i, row := range df.Data {
+		key := rowKey(row, indices)
+		if first, ok := seen[key]; ok {
+			values := make([]string, len(indices))
+			for k, j := range indices {
+				values[k] = row[j]
+			}
+			return fmt.Errorf("%w: row %d repeats row %d: %s", ErrAssertionFailed, i, first, strings.Join(values, ", "))
+		}
+		seen[key] = i
+	}
+	return nil
+}
+
+// AssertNonNull returns an ErrAssertionFailed error naming the first empty value if a column has
+// empty values. Without columns, all columns are checked.
+func (df *DataFrame) AssertNonNull(columns ...string) error {
+	indices, err := df.columnIndices(columns)
+	if err != nil {
+		return err
+	}
+	for i, row := range df.Data {
+		for _, j := range indices {
+			if isNull(strings.TrimSpace(row[j])) {
+				return fmt.Errorf("%w: row %d, column %s is empty", ErrAssertionFailed, i, df.Headers[j])
+			}
+		}
+	}
+	return nil
+}
+
+// unsortedRow returns the first row that is out of order, -1 if the rows are sorted
+func (df *DataFrame) unsortedRow(column string, ascending bool) (int, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return -1, err
+	}
+	numeric := df.Types[column] == TypeInt || df.Types[column] == TypeFloat
+	compare := df.stringComparator()
+	for i := 1; i < len(df.Data); i++ {
+		c := compareValuesWith(df.Data[i-1][colIndex], df.Data[i][colIndex], numeric, compare)
+		if (ascending && c > 0) || (!ascending && c < 0) {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
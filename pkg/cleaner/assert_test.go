@@ -0,0 +1,88 @@
+package cleaner
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIsSorted(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "name"}, [][]string{{"2", "c"}, {"10", "b"}, {"10", "a"}})
+
+	// Strings compare lexicographically, "10" before "2"
+	if sorted, _ := df.IsSorted("id", true); sorted {
+		t.Error("expected the string column not to be sorted")
+	}
+	df.Types["id"] = TypeInt
+	if sorted, _ := df.IsSorted("id", true); !sorted {
+		t.Error("expected the int column to be sorted, with equal values")
+	}
+	if sorted, _ := df.IsSorted("name", false); !sorted {
+		t.Error("expected the names to be sorted descending")
+	}
+	if _, err := df.IsSorted("missing", true); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+
+	err := df.AssertSorted("name", true)
+	if !errors.Is(err, ErrAssertionFailed) || !strings.Contains(err.Error(), "row 1 (b) follows c") {
+		t.Errorf("expected the first row out of order, got %v", err)
+	}
+	if err := df.AssertSorted("id", true); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAssertUniqueAndNonNull(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "email"}, [][]string{{"1", "a@x.com"}, {"2", " "}, {"1", "c@x.com"}})
+
+	err := df.AssertUnique("id")
+	if !errors.Is(err, ErrAssertionFailed) || !strings.Contains(err.Error(), "row 2 repeats row 0: 1") {
+		t.Errorf("expected the repeated id, got %v", err)
+	}
+	if err := df.AssertUnique(); err != nil {
+		t.Errorf("expected whole rows to be unique, got %v", err)
+	}
+
+	err = df.AssertNonNull()
+	if !errors.Is(err, ErrAssertionFailed) || !strings.Contains(err.Error(), "row 1, column email is empty") {
+		t.Errorf("expected the empty email, got %v", err)
+	}
+	if err := df.AssertNonNull("id"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := df.AssertNonNull("missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestPipelineAssertions(t *testing.T) {
+	newFrame := func() *DataFrame {
+		df, _ := NewDataFrame([]string{"id", "email"}, [][]string{{"1", " a@x.com "}, {"2", "b@x.com"}})
+		return df
+	}
+
+	pipeline := NewPipeline()
+	if err := pipeline.Actions("trim", "assert_sorted:id", "assert_unique:email", "assert_non_null"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := pipeline.Run(newFrame()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// A failed assertion stops the run even with an error handler
+	skipped := 0
+	pipeline = NewPipeline().AssertSorted("id", false).OnError(func(err *StepError) error {
+		skipped++
+		return nil
+	})
+	_, err := pipeline.Run(newFrame())
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "assert_sorted" || !errors.Is(err, ErrAssertionFailed) || skipped != 0 {
+		t.Errorf("expected a failed assertion, got %v", err)
+	}
+
+	if err := NewPipeline().Action("assert_sorted:id=down"); err == nil {
+		t.Error("expected a usage error")
+	}
+}
@@ -0,0 +1,65 @@
+package cleaner
+
+import (
+	"time"
+
+	"github.com/mstgnz/cleango/pkg/formats"
+)
+
+// bigQueryTypes maps column types to BigQuery types. Dates are mapped by their values, see bigQueryDateType.
+var bigQueryTypes = map[Type]string{
+	TypeString: "STRING",
+	TypeInt:    "INTEGER",
+	TypeFloat:  "FLOAT",
+	TypeBool:   "BOOLEAN",
+	TypeJSON:   "JSON",
+}
+
+// WriteBigQuery loads DataFrame into a BigQuery table with a load job. The table schema is derived
+// from the column types (see InferTypes) and the table is created if it does not exist. An authorized
+// HTTP client must be passed with formats.WithBigQueryClient.
+func (df *DataFrame) WriteBigQuery(projectID, dataset, table string, options ...formats.BigQueryOption) error {
+	return formats.WriteBigQueryFromRaw(df.Headers, df.Data, df.BigQuerySchema(), projectID, dataset, table, options...)
+}
+
+// BigQuerySchema returns the BigQuery schema of DataFrame. Date columns become DATE when all values are
+// formatted as 2006-01-02, TIMESTAMP when they all include a time and STRING otherwise.
+func (df *DataFrame) BigQuerySchema() []formats.BigQueryField {
+	schema := make([]formats.BigQueryField, len(df.Headers))
+	for i, header := range df.Headers {
+		fieldType, ok := bigQueryTypes[df.Types[header]]
+		if df.Types[header] == TypeDate {
+			fieldType = df.bigQueryDateType(i)
+		} else if !ok {
+			fieldType = "STRING"
+		}
+		schema[i] = formats.BigQueryField{Name: header, Type: fieldType, Mode: "NULLABLE"}
+	}
+	return schema
+}
+
+// bigQueryDateType returns the BigQuery type that all values of a date column can be loaded as
+func (df *DataFrame) bigQueryDateType(colIndex int) string {
+	isDate, isTimestamp := true, true
+	for _, row := range df.Data {
+		value := row[colIndex]
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			isDate = false
+		}
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			if _, err := time.Parse(time.DateTime, value); err != nil {
+				isTimestamp = false
+			}
+		}
+		if !isDate && !isTimestamp {
+			return "STRING"
+		}
+	}
+	if isDate {
+		return "DATE"
+	}
+	return "TIMESTAMP"
+}
@@ -0,0 +1,38 @@
+package cleaner
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/formats"
+)
+
+func TestBigQuerySchema(t *testing.T) {
+	df, _ := NewDataFrame(
+		[]string{"id", "price", "active", "day", "at", "mixed", "name"},
+		[][]string{
+			{"1", "9.5", "true", "2024-03-01", "2024-03-01 10:00:00", "2024-03-01", "Ali"},
+			{"2", "", "false", "", "2024-03-01T10:00:00Z", "01/03/2024", "Ayşe"},
+		},
+	)
+	for _, column := range []string{"day", "at", "mixed"} {
+		df.Types[column] = TypeDate
+	}
+	df.InferTypes()
+
+	types := make([]string, 0, len(df.Headers))
+	for _, field := range df.BigQuerySchema() {
+		types = append(types, field.Type)
+	}
+	expected := []string{"INTEGER", "FLOAT", "BOOLEAN", "DATE", "TIMESTAMP", "STRING", "STRING"}
+	if !reflect.DeepEqual(types, expected) {
+		t.Errorf("expected %v, got %v", expected, types)
+	}
+}
+
+func TestWriteBigQuery_RequiresClient(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id"}, [][]string{{"1"}})
+	if err := df.WriteBigQuery("p", "d", "t", formats.WithBigQueryEndpoint("http://127.0.0.1:0")); err == nil {
+		t.Error("expected an error without an HTTP client")
+	}
+}
@@ -0,0 +1,172 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// BinOption configures Bin, BinEqualWidth and BinEqualFrequency
+type BinOption func(*binOptions)
+
+type binOptions struct {
+	target string
+}
+
+// WithBinTarget sets the name of the column the bucket labels are written to, column_bin by default
+func WithBinTarget(column string) BinOption {
+	return func(o *binOptions) {
+		o.target = column
+	}
+}
+
+// Bin writes the bucket of every numeric value of the column to a new column, e.g. age groups for a
+// report or a model:
+//
+//	df, err = df.Bin("age", []float64{0, 18, 65, 120}, []string{"child", "adult", "senior"})
+//
+// The edges must be increasing; n edges make n-1 buckets that include their lower edge and exclude
+// their upper edge, except for the last one that includes both. Without labels, buckets are named
+// after their edges, e.g. "[18, 65)". Empty values and values outside the edges get an empty label
+// and a value that is not a number is an error. The new column is named column_bin unless
+// WithBinTarget is given.
+func (df *DataFrame) Bin(column string, edges []float64, labels []string, options ...BinOption) (*DataFrame, error) {
+	if len(edges) < 2 {
+		return nil, errors.New("at least two bin edges are required")
+	}
+	for i := 1; i < len(edges); i++ {
+		if !(edges[i] > edges[i-1]) {
+			return nil, fmt.Errorf("bin edges must be increasing: %v", edges)
+		}
+	}
+	return df.bin(column, edges, labels, options)
+}
+
+// bin writes the buckets of the column between the edges, which may also be two equal edges for a
+// column whose values are all equal
+func (df *DataFrame) bin(column string, edges []float64, labels []string, options []BinOption) (*DataFrame, error) {
+	if labels == nil {
+		labels = binLabels(edges)
+	} else if len(labels) != len(edges)-1 {
+		return nil, fmt.Errorf("%d bin edges need %d labels, got %d", len(edges), len(edges)-1, len(labels))
+	}
+	opts := binOptions{target: column + "_bin"}
+	for _, option := range options {
+		option(&opts)
+	}
+	if err := df.checkNewColumn(opts.target); err != nil {
+		return nil, err
+	}
+	c, err := df.Float64Column(column)
+	if err != nil {
+		return nil, err
+	}
+
+	df.addColumn(opts.target, func(i int, _ []string) string {
+		v := c.Values[i]
+		if !c.Valid[i] || v < edges[0] || v > edges[len(edges)-1] {
+			return ""
+		}
+		// The index of the first edge above v, less one, is the bucket; the last edge closes the last bucket
+		bucket := sort.Search(len(edges), func(k int) bool { return edges[k] > v }) - 1
+		return labels[min(bucket, len(labels)-1)]
+	})
+	return df, nil
+}
+
+// BinEqualWidth bins the column like Bin into buckets of the same width between its smallest and
+// largest value. labels may be nil or hold one label per bucket. A column whose values are all
+// equal has a single bucket.
+func (df *DataFrame) BinEqualWidth(column string, bins int, labels []string, options ...BinOption) (*DataFrame, error) {
+	if bins < 1 {
+		return nil, fmt.Errorf("number of bins must be positive: %d", bins)
+	}
+	c, err := df.Float64Column(column)
+	if err != nil {
+		return nil, err
+	}
+	lo, hi, ok := c.MinMax()
+	if !ok {
+		return nil, fmt.Errorf("column %s has no values to bin", column)
+	}
+	if lo == hi {
+		return df.bin(column, []float64{lo, hi}, labels, options)
+	}
+	edges := make([]float64, bins+1)
+	for k := range edges {
+		edges[k] = roundEdge(lo + (hi-lo)*float64(k)/float64(bins))
+	}
+	edges[0], edges[bins] = lo, hi
+	return df.bin(column, edges, labels, options)
+}
+
+// BinEqualFrequency bins the column like Bin into buckets that hold about the same number of
+// values, with edges at its quantiles. Repeated values are never split between buckets, so a
+// column with few distinct values can have fewer buckets than asked for; that is an error when
+// labels are given.
+func (df *DataFrame) BinEqualFrequency(column string, bins int, labels []string, options ...BinOption) (*DataFrame, error) {
+	if bins < 1 {
+		return nil, fmt.Errorf("number of bins must be positive: %d", bins)
+	}
+	c, err := df.Float64Column(column)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, 0, c.Len())
+	for i, v := range c.Values {
+		if c.Valid[i] {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("column %s has no values to bin", column)
+	}
+	slices.Sort(values)
+
+	edges := make([]float64, 0, bins+1)
+	for k := 0; k <= bins; k++ {
+		// Linear interpolation between the closest ranks, like the median of Describe
+		position := float64(k) * float64(len(values)-1) / float64(bins)
+		lower := int(position)
+		edge := values[lower]
+		if fraction := position - float64(lower); fraction > 0 {
+			edge = roundEdge(edge + fraction*(values[lower+1]-edge))
+		}
+		if len(edges) == 0 || edge > edges[len(edges)-1] {
+			edges = append(edges, edge)
+		}
+	}
+	if len(edges) == 1 {
+		edges = append(edges, edges[0])
+	}
+	if labels != nil && len(labels) != len(edges)-1 {
+		return nil, fmt.Errorf("column %s has too few distinct values for %d bins", column, bins)
+	}
+	return df.bin(column, edges, labels, options)
+}
+
+// binLabels returns the labels of the buckets between the edges
+func binLabels(edges []float64) []string {
+	labels := make([]string, len(edges)-1)
+	for k := range labels {
+		closing := ")"
+		if k == len(labels)-1 {
+			closing = "]"
+		}
+		labels[k] = "[" + formatEdge(edges[k]) + ", " + formatEdge(edges[k+1]) + closing
+	}
+	return labels
+}
+
+// roundEdge drops the floating point noise of a computed edge, e.g. 0.30000000000000004
+func roundEdge(edge float64) float64 {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(edge, 'g', 12, 64), 64)
+	return rounded
+}
+
+// formatEdge returns the shortest text of an edge
+func formatEdge(edge float64) string {
+	return strconv.FormatFloat(edge, 'f', -1, 64)
+}
@@ -0,0 +1,131 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func binTestDataFrame() *DataFrame {
+	df, _ := NewDataFrame([]string{"age"}, [][]string{{"5"}, {"18"}, {""}, {"40"}, {"65"}, {"120"}, {"130"}})
+	return df
+}
+
+func TestBin(t *testing.T) {
+	df, err := binTestDataFrame().Bin("age", []float64{0, 18, 65, 120}, []string{"child", "adult", "senior"})
+	if err != nil {
+		t.Fatalf("Bin error: %v", err)
+	}
+	if df.Headers[1] != "age_bin" {
+		t.Errorf("expected the age_bin column, got %v", df.Headers)
+	}
+	expected := []string{"child", "adult", "", "adult", "senior", "senior", ""}
+	if got := columnValues(df, 1); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	df, err = binTestDataFrame().Bin("age", []float64{0, 18, 65}, nil, WithBinTarget("group"))
+	if err != nil {
+		t.Fatalf("Bin error: %v", err)
+	}
+	expected = []string{"[0, 18)", "[18, 65]", "", "[18, 65]", "[18, 65]", "", ""}
+	if df.Headers[1] != "group" || !reflect.DeepEqual(columnValues(df, 1), expected) {
+		t.Errorf("expected %v in group, got %v %v", expected, df.Headers, columnValues(df, 1))
+	}
+}
+
+func TestBin_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		edges  []float64
+		labels []string
+		option BinOption
+	}{
+		{"one edge", []float64{1}, nil, WithBinTarget("bin")},
+		{"decreasing edges", []float64{0, 10, 5}, nil, WithBinTarget("bin")},
+		{"label count", []float64{0, 10, 20}, []string{"low"}, WithBinTarget("bin")},
+		{"existing target", []float64{0, 10}, nil, WithBinTarget("age")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := binTestDataFrame().Bin("age", tt.edges, tt.labels, tt.option); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+	if _, err := binTestDataFrame().Bin("missing", []float64{0, 1}, nil); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestBinEqualWidth(t *testing.T) {
+	df, _ := NewDataFrame([]string{"score"}, [][]string{{"0"}, {"0.3"}, {"0.5"}, {""}, {"0.9"}})
+	if _, err := df.BinEqualWidth("score", 3, nil); err != nil {
+		t.Fatalf("BinEqualWidth error: %v", err)
+	}
+	expected := []string{"[0, 0.3)", "[0.3, 0.6)", "[0.3, 0.6)", "", "[0.6, 0.9]"}
+	if got := columnValues(df, 1); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	constant, _ := NewDataFrame([]string{"score"}, [][]string{{"7"}, {"7"}})
+	if _, err := constant.BinEqualWidth("score", 4, []string{"all"}); err != nil {
+		t.Fatalf("BinEqualWidth error: %v", err)
+	}
+	if got := columnValues(constant, 1); !reflect.DeepEqual(got, []string{"all", "all"}) {
+		t.Errorf("expected a single bucket, got %v", got)
+	}
+
+	if _, err := df.BinEqualWidth("score", 0, nil, WithBinTarget("other")); err == nil {
+		t.Error("expected an error for zero bins")
+	}
+}
+
+func TestBinEqualFrequency(t *testing.T) {
+	df, _ := NewDataFrame([]string{"amount"}, [][]string{{"1"}, {"2"}, {"3"}, {"4"}, {"100"}, {"200"}})
+	if _, err := df.BinEqualFrequency("amount", 2, []string{"low", "high"}); err != nil {
+		t.Fatalf("BinEqualFrequency error: %v", err)
+	}
+	expected := []string{"low", "low", "low", "high", "high", "high"}
+	if got := columnValues(df, 1); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// Repeated values are not split, so three buckets collapse into two
+	repeated, _ := NewDataFrame([]string{"amount"}, [][]string{{"1"}, {"1"}, {"1"}, {"1"}, {"2"}})
+	if _, err := repeated.BinEqualFrequency("amount", 3, []string{"a", "b", "c"}); err == nil {
+		t.Error("expected an error for too few distinct values")
+	}
+	if _, err := repeated.BinEqualFrequency("amount", 3, nil); err != nil {
+		t.Fatalf("BinEqualFrequency error: %v", err)
+	}
+	if got := columnValues(repeated, 1); !reflect.DeepEqual(got, []string{"[1, 2]", "[1, 2]", "[1, 2]", "[1, 2]", "[1, 2]"}) {
+		t.Errorf("unexpected buckets: %v", got)
+	}
+}
+
+func TestPipelineBin(t *testing.T) {
+	df, _ := NewDataFrame([]string{"age", "score"}, [][]string{{"10", "1"}, {"30", "2"}, {"70", "3"}})
+	pipeline := NewPipeline()
+	for _, action := range []string{"bin:age=0,18,65,120=child,adult,senior=age_group", "bin_equal_width:score=2"} {
+		if err := pipeline.Action(action); err != nil {
+			t.Fatalf("%s: unexpected error: %v", action, err)
+		}
+	}
+	result, err := pipeline.Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := columnValues(result, 2); !reflect.DeepEqual(got, []string{"child", "adult", "senior"}) {
+		t.Errorf("unexpected age groups: %v", got)
+	}
+	if got := columnValues(result, 3); !reflect.DeepEqual(got, []string{"[1, 2)", "[2, 3]", "[2, 3]"}) {
+		t.Errorf("unexpected score buckets: %v", got)
+	}
+
+	for _, action := range []string{"bin:age", "bin:age=0,ten", "bin_equal_width:age", "bin_equal_frequency:age=0", "bin_equal_frequency:=3"} {
+		if err := NewPipeline().Action(action); err == nil {
+			t.Errorf("%s: expected an error", action)
+		}
+	}
+}
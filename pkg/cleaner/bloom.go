@@ -0,0 +1,48 @@
+package cleaner
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// bloomFilter is a fixed-size set of strings that may report false positives, but never false negatives
+type bloomFilter struct {
+	bits   []uint64
+	m      uint64 // Number of bits
+	hashes uint64 // Number of bit positions per key
+}
+
+// newBloomFilter sizes a filter for n keys with the false positive rate p
+func newBloomFilter(n int, p float64) *bloomFilter {
+	n = max(n, 1)
+	if p <= 0 || p >= 1 {
+		p = 0.01
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	m = max(m, 64)
+	hashes := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	return &bloomFilter{bits: make([]uint64, (m+63)/64), m: m, hashes: max(hashes, 1)}
+}
+
+// positions returns the two hashes the bit positions of key are derived from
+func (f *bloomFilter) positions(key string) (uint64, uint64) {
+	h1, h2 := fnv.New64a(), fnv.New64()
+	h1.Write([]byte(key))
+	h2.Write([]byte(key))
+	return h1.Sum64(), h2.Sum64() | 1
+}
+
+// Add adds key and reports whether it may have been added before
+func (f *bloomFilter) Add(key string) bool {
+	h1, h2 := f.positions(key)
+	present := true
+	for i := uint64(0); i < f.hashes; i++ {
+		bit := (h1 + i*h2) % f.m
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if f.bits[word]&mask == 0 {
+			present = false
+			f.bits[word] |= mask
+		}
+	}
+	return present
+}
@@ -0,0 +1,95 @@
+package cleaner
+
+import (
+	"container/list"
+	"regexp"
+	"sync"
+)
+
+// Cache sizes of the package-level caches
+const (
+	regexCacheSize      = 256
+	dateLayoutCacheSize = 64
+)
+
+var (
+	// regexCache holds compiled regexes by pattern, shared by all operations
+	regexCache = newLRUCache[string, *regexp.Regexp](regexCacheSize)
+	// dateLayoutCache holds the candidate input layouts by output layout
+	dateLayoutCache = newLRUCache[string, []string](dateLayoutCacheSize)
+)
+
+// lruCache is a concurrency-safe cache that evicts the least recently used entry when full
+type lruCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[K]*list.Element
+}
+
+// lruEntry is a key and value stored in the cache list
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func newLRUCache[K comparable, V any](size int) *lruCache[K, V] {
+	return &lruCache[K, V]{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[K]*list.Element, size),
+	}
+}
+
+// Get returns the cached value and marks it as recently used
+func (c *lruCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		return elem.Value.(*lruEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores the value, evicting the least recently used entry if the cache is full
+func (c *lruCache[K, V]) Put(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+// Len returns the number of cached entries
+func (c *lruCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Purge removes all entries
+func (c *lruCache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[K]*list.Element, c.size)
+}
+
+// ClearCaches empties the package-level regex and date layout caches
+func ClearCaches() {
+	regexCache.Purge()
+	dateLayoutCache.Purge()
+}
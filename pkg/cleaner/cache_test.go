@@ -0,0 +1,107 @@
+package cleaner
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := newLRUCache[string, int](2)
+	c.Put("a", 1)
+	c.Put("b", 2)
+
+	// Touch "a" so "b" becomes the least recently used entry
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %v, %v", v, ok)
+	}
+	c.Put("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if v, ok := c.Get("c"); !ok || v != 3 {
+		t.Errorf("Get(c) = %v, %v", v, ok)
+	}
+
+	c.Put("a", 10)
+	if v, _ := c.Get("a"); v != 10 {
+		t.Errorf("expected updated value, got %v", v)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, expected = 2", c.Len())
+	}
+
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("Len() after Purge = %d", c.Len())
+	}
+}
+
+func TestLRUCacheConcurrent(t *testing.T) {
+	c := newLRUCache[string, int](8)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := fmt.Sprint(i % 16)
+				c.Put(key, i)
+				c.Get(key)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if c.Len() > 8 {
+		t.Errorf("cache grew beyond its size: %d", c.Len())
+	}
+}
+
+func TestCompileRegexCached(t *testing.T) {
+	ClearCaches()
+
+	first, err := compileRegex(`[0-9]+`)
+	if err != nil {
+		t.Fatalf("compileRegex error: %v", err)
+	}
+	second, _ := compileRegex(`[0-9]+`)
+	if first != second {
+		t.Error("expected the cached regex to be reused")
+	}
+
+	if _, err := compileRegex(`[`); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+	if regexCache.Len() != 1 {
+		t.Errorf("invalid patterns must not be cached, cache size = %d", regexCache.Len())
+	}
+}
+
+func TestDateLayoutsCached(t *testing.T) {
+	ClearCaches()
+
+	layouts := dateLayouts("2006-01-02")
+	if layouts[0] != "2006-01-02" {
+		t.Errorf("user layout must be tried first, got %s", layouts[0])
+	}
+	if len(layouts) != len(commonDateLayouts) {
+		t.Errorf("duplicate layout was not removed: %d layouts", len(layouts))
+	}
+	if again := dateLayouts("2006-01-02"); &again[0] != &layouts[0] {
+		t.Error("expected the cached layouts to be reused")
+	}
+
+	custom := dateLayouts("02.01.2006")
+	if custom[0] != "02.01.2006" || len(custom) != len(commonDateLayouts)+1 {
+		t.Errorf("unexpected layouts for custom format: %v", custom[:2])
+	}
+}
+
+func BenchmarkCleanWithRegex(b *testing.B) {
+	benchmarkOp(b, func(df *DataFrame) error {
+		_, err := df.CleanWithRegex("col1", `Value (\d+)`, "V$1")
+		return err
+	})
+}
@@ -0,0 +1,120 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCanaryFailed is returned by Run when a step fails on too many rows of the canary sample
+var ErrCanaryFailed = errors.New("canary failed")
+
+// CanaryStep is the effect of one pipeline step on the canary sample
+type CanaryStep struct {
+	Index         int     `json:"index"`
+	Step          string  `json:"step"`
+	Column        string  `json:"column,omitempty"`
+	RowsIn        int     `json:"rows_in"`        // Sample rows the step was given
+	CellsChanged  int     `json:"cells_changed"`  // Only counted for row-wise steps
+	RowsDropped   int     `json:"rows_dropped"`   // Rows removed by a filter
+	ParseFailures int     `json:"parse_failures"` // Rows with a value the step could not convert
+	FailureRate   float64 `json:"failure_rate"`   // ParseFailures / RowsIn
+}
+
+// CanaryReport is the anticipated effect of a pipeline, measured on a sample of the rows
+type CanaryReport struct {
+	SampleRows int          `json:"sample_rows"`
+	TotalRows  int          `json:"total_rows"`
+	Steps      []CanaryStep `json:"steps"`
+}
+
+// CanaryError is returned by Run when a step of the canary run fails on more rows than allowed
+type CanaryError struct {
+	Report         *CanaryReport
+	Step           CanaryStep // The first step above the threshold
+	MaxFailureRate float64
+}
+
+func (e *CanaryError) Error() string {
+	return fmt.Sprintf("%v: step %d (%s) failed on %.1f%% of %d sample rows, above %.1f%%", ErrCanaryFailed,
+		e.Step.Index, e.Step.Step, e.Step.FailureRate*100, e.Step.RowsIn, e.MaxFailureRate*100)
+}
+
+func (e *CanaryError) Unwrap() error {
+	return ErrCanaryFailed
+}
+
+// Canary makes Run first run every step on a sample of sampleRows evenly spaced rows and stop with
+// a CanaryError, before touching the data, if a step fails to convert more than maxFailureRate of
+// the sample rows, e.g. 0.05 for 5%. Otherwise the full run goes ahead and the report of the
+// sample is available from CanaryReport of the result.
+func (p *Pipeline) Canary(sampleRows int, maxFailureRate float64) *Pipeline {
+	p.canaryRows = sampleRows
+	p.maxFailureRate = maxFailureRate
+	return p
+}
+
+// Preview runs every step on its own on a copy of sampleRows evenly spaced rows of df (0 for all
+// rows) and reports what it changed, dropped and failed to convert, without modifying df. Each step
+// sees the sample as the previous steps left it; rows a step fails on are removed from the sample.
+// Errors that would stop the full run, such as a missing column, are returned.
+func (p *Pipeline) Preview(df *DataFrame, sampleRows int) (*CanaryReport, error) {
+	// Only the sampled rows are copied; custom steps may write to Data directly, so the sample
+	// must not share rows with df, and df is not marked as shared
+	rows := df.Data
+	if sampleRows > 0 && len(df.Data) > sampleRows {
+		rows = make([][]string, sampleRows)
+		for i := range rows {
+			rows[i] = df.Data[i*len(df.Data)/sampleRows]
+		}
+	}
+	sample := df.deepCopyRows(rows)
+
+	report := &CanaryReport{SampleRows: len(sample.Data), TotalRows: len(df.Data), Steps: make([]CanaryStep, 0, len(p.steps))}
+	for i, step := range p.steps {
+		single := &Pipeline{steps: []pipelineStep{step}, rejects: true, onError: func(err *StepError) error {
+			err.Index = i
+			if p.onError == nil {
+				return err
+			}
+			return p.onError(err)
+		}}
+		rowsIn := len(sample.Data)
+		result, err := single.Run(sample, WithMaxWorkers(1))
+		if err != nil {
+			return nil, err
+		}
+		sample = result
+
+		stats := sample.Stats()
+		effect := CanaryStep{Index: i, Step: step.name, Column: step.column, RowsIn: rowsIn}
+		for _, c := range stats.Columns {
+			effect.CellsChanged += c.Modified
+			effect.ParseFailures += c.ParseFailures
+		}
+		effect.RowsDropped = stats.RowsDropped() - effect.ParseFailures
+		if rowsIn > 0 {
+			effect.FailureRate = float64(effect.ParseFailures) / float64(rowsIn)
+		}
+		report.Steps = append(report.Steps, effect)
+	}
+	return report, nil
+}
+
+// runCanary previews the pipeline and returns a CanaryError for the first step above the threshold
+func (p *Pipeline) runCanary(df *DataFrame) (*CanaryReport, error) {
+	report, err := p.Preview(df, p.canaryRows)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range report.Steps {
+		if step.FailureRate > p.maxFailureRate {
+			return nil, &CanaryError{Report: report, Step: step, MaxFailureRate: p.maxFailureRate}
+		}
+	}
+	return report, nil
+}
+
+// CanaryReport returns the report of the canary run of the pipeline run that produced df, or nil
+func (df *DataFrame) CanaryReport() *CanaryReport {
+	return df.canary
+}
@@ -0,0 +1,96 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// canaryFrame returns rows with a date column that fails to parse on every failEvery-th row
+func canaryFrame(rows, failEvery int) *DataFrame {
+	data := make([][]string, rows)
+	for i := range data {
+		date := "2024-01-02"
+		if i%failEvery == 0 {
+			date = "not a date"
+		}
+		data[i] = []string{fmt.Sprint(i), " name ", date}
+	}
+	df, _ := NewDataFrame([]string{"id", "name", "created"}, data)
+	return df
+}
+
+func TestPipeline_Preview(t *testing.T) {
+	df := canaryFrame(1000, 70)
+	original := df.DeepCopy()
+
+	report, err := NewPipeline().
+		Trim().
+		CleanDates("created", "02.01.2006").
+		FilterOutliers("id", 0, 499).
+		Preview(df, 100)
+	if err != nil {
+		t.Fatalf("Preview error: %v", err)
+	}
+
+	// The sample is every 10th row, so every 7th sample row has an invalid date
+	if report.SampleRows != 100 || report.TotalRows != 1000 {
+		t.Errorf("unexpected sample: %+v", report)
+	}
+	expected := []CanaryStep{
+		{Index: 0, Step: "trim", RowsIn: 100, CellsChanged: 100},
+		{Index: 1, Step: "clean_dates", Column: "created", RowsIn: 100, CellsChanged: 85, ParseFailures: 15, FailureRate: 0.15},
+		{Index: 2, Step: "filter_outliers", Column: "id", RowsIn: 85, RowsDropped: 43},
+	}
+	if !reflect.DeepEqual(report.Steps, expected) {
+		t.Errorf("steps = %+v, expected = %+v", report.Steps, expected)
+	}
+	if !reflect.DeepEqual(df.Data, original.Data) {
+		t.Error("expected Preview to leave the DataFrame unchanged")
+	}
+	// The rows of df are not marked as shared, so the full run does not copy every row it writes
+	if df.cow != nil {
+		t.Error("expected Preview to leave the rows of the DataFrame unshared")
+	}
+}
+
+func TestPipeline_Canary(t *testing.T) {
+	df := canaryFrame(1000, 70)
+	original := df.DeepCopy()
+
+	_, err := NewPipeline().Trim().CleanDates("created", "02.01.2006").Canary(100, 0.05).Run(df)
+	var canaryErr *CanaryError
+	if !errors.As(err, &canaryErr) || !errors.Is(err, ErrCanaryFailed) {
+		t.Fatalf("expected a CanaryError, got %v", err)
+	}
+	if canaryErr.Step.Step != "clean_dates" || canaryErr.Step.Index != 1 {
+		t.Errorf("unexpected failing step: %+v", canaryErr.Step)
+	}
+	if !reflect.DeepEqual(df.Data, original.Data) {
+		t.Error("expected the failed canary to leave the DataFrame unchanged")
+	}
+
+	// Below the threshold the full run goes ahead
+	result, err := NewPipeline().Trim().CleanDates("created", "02.01.2006").
+		OnError(func(*StepError) error { return nil }).
+		Canary(100, 0.2).
+		Run(df)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if report := result.CanaryReport(); report == nil || report.Steps[1].FailureRate != 0.15 {
+		t.Errorf("unexpected canary report: %+v", report)
+	}
+	if result.Data[1][1] != "name" || result.Data[1][2] != "02.01.2024" {
+		t.Errorf("expected the full run to clean every row, got %v", result.Data[1])
+	}
+}
+
+func TestPipeline_CanaryMissingColumn(t *testing.T) {
+	_, err := NewPipeline().Trim().ReplaceNulls("age", "0").Canary(10, 0.5).Run(canaryFrame(20, 2))
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Index != 1 || !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected step 1 to fail with ErrColumnNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package cleaner
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// WithCollation compares the values of the DataFrame by the rules of a language instead of by byte
+// value, e.g. "tr" for Turkish, where "ç", "ğ", "ı", "ö", "ş" and "ü" follow "c", "g", "h", "o",
+// "s" and "u" instead of "z":
+//
+//   - SortBy, SortByColumn, IsSorted and AssertSorted order strings by the collation
+//   - DropDuplicates, DropDuplicatesKeep and DropDuplicatesParallel treat canonically equivalent
+//     values as equal, such as a precomposed "ç" and a "c" followed by a combining cedilla
+//   - DedupeFuzzy lower-cases values with the case mapping of the language, e.g. "I" to "ı" in
+//     Turkish and Azerbaijani
+//
+// The collation is kept by copies and by the results of parallel operations. An empty locale
+// restores byte order; a malformed locale is an error.
+func (df *DataFrame) WithCollation(locale string) (*DataFrame, error) {
+	tag, err := parseCollation(locale)
+	if err != nil {
+		return nil, err
+	}
+	df.collation = tag
+	return df, nil
+}
+
+// parseCollation parses the locale of a collation, Und for an empty locale
+func parseCollation(locale string) (language.Tag, error) {
+	if locale == "" {
+		return language.Und, nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return language.Und, fmt.Errorf("invalid collation: %s", locale)
+	}
+	return tag, nil
+}
+
+// Collation returns the locale set with WithCollation, empty for byte order
+func (df *DataFrame) Collation() string {
+	if df.collation == language.Und {
+		return ""
+	}
+	return df.collation.String()
+}
+
+// stringComparator returns the comparison of strings of the collation. Collators are not safe for
+// concurrent use, so every sort gets its own.
+func (df *DataFrame) stringComparator() func(a, b string) int {
+	if df.collation == language.Und {
+		return strings.Compare
+	}
+	return collate.New(df.collation).CompareString
+}
+
+// rowKeyFunc returns the function building the duplicate keys of rows: with a collation, values
+// are normalized to NFC first so canonically equivalent values get the same key
+func (df *DataFrame) rowKeyFunc() func(row []string, indices []int) string {
+	if df.collation == language.Und {
+		return rowKey
+	}
+	return func(row []string, indices []int) string {
+		var sb strings.Builder
+		for i, idx := range indices {
+			if i > 0 {
+				sb.WriteByte(0)
+			}
+			sb.WriteString(norm.NFC.String(row[idx]))
+		}
+		return sb.String()
+	}
+}
+
+// fuzzyKeyFunc returns the function building the keys DedupeFuzzy compares: with a collation,
+// values are normalized to NFC and lower-cased with the case mapping of the language
+func (df *DataFrame) fuzzyKeyFunc() func(value string) string {
+	if df.collation == language.Und {
+		return func(value string) string { return fuzzyKey(value, unicode.ToLower) }
+	}
+	toLower := unicode.ToLower
+	switch base, _ := df.collation.Base(); base.String() {
+	case "tr":
+		toLower = unicode.TurkishCase.ToLower
+	case "az":
+		toLower = unicode.AzeriCase.ToLower
+	}
+	return func(value string) string { return fuzzyKey(norm.NFC.String(value), toLower) }
+}
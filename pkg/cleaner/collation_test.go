@@ -0,0 +1,121 @@
+package cleaner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func collationTestFrame() *DataFrame {
+	df, _ := NewDataFrame([]string{"word"}, [][]string{
+		{"zeytin"}, {"çay"}, {"inek"}, {"ılık"}, {"cuma"}, {"hasta"}, {"dağ"},
+	})
+	return df
+}
+
+func words(df *DataFrame) []string {
+	values := make([]string, len(df.Data))
+	for i, row := range df.Data {
+		values[i] = row[0]
+	}
+	return values
+}
+
+func TestWithCollationSort(t *testing.T) {
+	df, _ := collationTestFrame().SortByColumn("word", false)
+	if !reflect.DeepEqual(words(df), []string{"cuma", "dağ", "hasta", "inek", "zeytin", "çay", "ılık"}) {
+		t.Errorf("expected byte order, got %v", words(df))
+	}
+
+	df, err := collationTestFrame().WithCollation("tr")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sorted, _ := df.IsSorted("word", true); sorted {
+		t.Error("expected the rows not to be sorted")
+	}
+	df, _ = df.SortByColumn("word", false)
+	expected := []string{"cuma", "çay", "dağ", "hasta", "ılık", "inek", "zeytin"}
+	if !reflect.DeepEqual(words(df), expected) {
+		t.Errorf("expected Turkish order, got %v", words(df))
+	}
+	if err := df.AssertSorted("word", true); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if df.Copy().Collation() != "tr" {
+		t.Error("expected copies to keep the collation")
+	}
+
+	if _, err := df.WithCollation("!!"); err == nil {
+		t.Error("expected an error for a malformed locale")
+	}
+	df, _ = df.WithCollation("")
+	if df.Collation() != "" {
+		t.Errorf("expected byte order, got %s", df.Collation())
+	}
+}
+
+func TestWithCollationDuplicates(t *testing.T) {
+	frame := func() *DataFrame {
+		// A precomposed ç and a c followed by a combining cedilla
+		df, _ := NewDataFrame([]string{"word"}, [][]string{{"çay"}, {"c\u0327ay"}, {"cay"}})
+		return df
+	}
+
+	df, _ := frame().DropDuplicates()
+	if len(df.Data) != 3 {
+		t.Errorf("expected byte comparison to keep 3 rows, got %v", df.Data)
+	}
+
+	collated, _ := frame().WithCollation("tr")
+	df, _ = collated.DropDuplicates()
+	if !reflect.DeepEqual(words(df), []string{"çay", "cay"}) {
+		t.Errorf("expected canonically equivalent values to be duplicates, got %q", words(df))
+	}
+
+	collated, _ = frame().WithCollation("tr")
+	df, err := collated.DropDuplicatesParallel(nil, KeepLast, WithMaxWorkers(2))
+	if err != nil || !reflect.DeepEqual(words(df), []string{"c\u0327ay", "cay"}) {
+		t.Errorf("unexpected parallel result: %q, %v", words(df), err)
+	}
+	if df.Collation() != "tr" {
+		t.Error("expected the result to keep the collation")
+	}
+}
+
+func TestWithCollationDedupeFuzzy(t *testing.T) {
+	frame := func() *DataFrame {
+		df, _ := NewDataFrame([]string{"word"}, [][]string{{"ILIK"}, {"ılık"}, {"İNEK"}, {"inek"}})
+		return df
+	}
+
+	df, _ := frame().DedupeFuzzy("word", 1, Levenshtein)
+	if len(df.Data) != 3 {
+		t.Errorf("expected the Unicode case mapping to keep 3 rows, got %v", df.Data)
+	}
+
+	collated, _ := frame().WithCollation("tr")
+	df, _ = collated.DedupeFuzzy("word", 1, Levenshtein)
+	if !reflect.DeepEqual(words(df), []string{"ILIK", "İNEK"}) {
+		t.Errorf("expected the Turkish case mapping, got %v", words(df))
+	}
+}
+
+func TestPipelineCollation(t *testing.T) {
+	pipeline := NewPipeline()
+	if err := pipeline.Actions("collation:tr", "assert_unique:word"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	df, err := pipeline.SortBy([]SortKey{{Column: "word"}}).Run(collationTestFrame())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if words(df)[1] != "çay" || words(df)[4] != "ılık" {
+		t.Errorf("expected Turkish order, got %v", words(df))
+	}
+
+	for _, action := range []string{"collation", "collation:!!"} {
+		if err := NewPipeline().Action(action); err == nil {
+			t.Errorf("%s: expected an error", action)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// AddColumn appends a column with one value per row, e.g. a constant or values computed outside
+// the DataFrame. The column is a TypeString column until the types are inferred again.
+func (df *DataFrame) AddColumn(name string, values []string) (*DataFrame, error) {
+	if err := df.checkNewColumn(name); err != nil {
+		return nil, err
+	}
+	if len(values) != len(df.Data) {
+		return nil, fmt.Errorf("column %s has %d values, expected %d", name, len(values), len(df.Data))
+	}
+	return df.addColumn(name, func(i int, _ []string) string { return values[i] }), nil
+}
+
+// AddColumnFunc appends a column computed from every row, e.g. price_with_tax from price. fn
+// receives the row without the new column and must not modify it.
+func (df *DataFrame) AddColumnFunc(name string, fn func(row []string) string) (*DataFrame, error) {
+	if err := df.checkNewColumn(name); err != nil {
+		return nil, err
+	}
+	if fn == nil {
+		return nil, errors.New("column function cannot be nil")
+	}
+	return df.addColumn(name, func(_ int, row []string) string { return fn(row) }), nil
+}
+
+// checkNewColumn returns an error if name cannot be added as a column
+func (df *DataFrame) checkNewColumn(name string) error {
+	if name == "" {
+		return errors.New("column name cannot be empty")
+	}
+	if df.getColumnIndex(name) != -1 {
+		return fmt.Errorf("column already exists: %s", name)
+	}
+	return nil
+}
+
+// addColumn appends the column with the value of every row
+func (df *DataFrame) addColumn(name string, value func(i int, row []string) string) *DataFrame {
+	newData := make([][]string, len(df.Data))
+	for i, row := range df.Data {
+		newRow := make([]string, len(row), len(row)+1)
+		copy(newRow, row)
+		newData[i] = append(newRow, value(i, row))
+	}
+
+	df.Headers = append(df.Headers[:len(df.Headers):len(df.Headers)], name)
+	df.Data = newData
+	df.Types[name] = TypeString
+	df.cow = nil
+	return df
+}
+
+// DropColumn removes the column
+func (df *DataFrame) DropColumn(name string) (*DataFrame, error) {
+	return df.DropColumns(name)
+}
+
+// DropColumns removes the columns. If one of them does not exist, nothing is removed.
+func (df *DataFrame) DropColumns(names ...string) (*DataFrame, error) {
+	for _, name := range names {
+		if _, err := df.requireColumn(name); err != nil {
+			return nil, err
+		}
+	}
+	return df.dropColumns(names), nil
+}
+
+// SelectColumns returns a new DataFrame with only the columns, in the order of the arguments, e.g.
+// to narrow the output schema. df is not modified. An unknown or repeated column is an error.
+func (df *DataFrame) SelectColumns(names ...string) (*DataFrame, error) {
+	indexes := make([]int, len(names))
+	for k, name := range names {
+		j, err := df.requireColumn(name)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(names[:k], name) {
+			return nil, fmt.Errorf("column selected more than once: %s", name)
+		}
+		indexes[k] = j
+	}
+
+	data := make([][]string, len(df.Data))
+	for i, row := range df.Data {
+		newRow := make([]string, len(indexes))
+		for k, j := range indexes {
+			newRow[k] = row[j]
+		}
+		data[i] = newRow
+	}
+
+	types := make(map[string]Type, len(names))
+	for _, name := range names {
+		if t, ok := df.Types[name]; ok {
+			types[name] = t
+		}
+	}
+
+	return &DataFrame{
+		Headers: slices.Clone(names),
+		Data:    data,
+		Types:   types,
+
+		stringColumns: slices.DeleteFunc(slices.Clone(df.stringColumns), func(column string) bool {
+			return !slices.Contains(names, column)
+		}),
+		collation: df.collation,
+	}, nil
+}
+
+// CopyColumn copies the values and the type of column src into column dst, e.g. to keep a column
+// under both its old and its new name while migrating between header conventions. A new dst is
+// appended as the last column; the values of an existing dst are overwritten.
+func (df *DataFrame) CopyColumn(src, dst string) (*DataFrame, error) {
+	srcIndex, err := df.requireColumn(src)
+	if err != nil {
+		return nil, err
+	}
+	if dst == "" {
+		return nil, errors.New("column name cannot be empty")
+	}
+
+	dstIndex := df.getColumnIndex(dst)
+	if dstIndex == -1 {
+		df.addColumn(dst, func(_ int, row []string) string { return row[srcIndex] })
+	} else if dstIndex != srcIndex {
+		for i, row := range df.Data {
+			df.setCell(i, dstIndex, row[srcIndex])
+		}
+	}
+	df.Types[dst] = df.Types[src]
+	if slices.Contains(df.stringColumns, src) && !slices.Contains(df.stringColumns, dst) {
+		df.stringColumns = append(df.stringColumns, dst)
+	}
+	return df, nil
+}
+
+// SwapColumns swaps the positions of two columns; values and types move with their headers
+func (df *DataFrame) SwapColumns(a, b string) (*DataFrame, error) {
+	aIndex, err := df.requireColumn(a)
+	if err != nil {
+		return nil, err
+	}
+	bIndex, err := df.requireColumn(b)
+	if err != nil {
+		return nil, err
+	}
+	if aIndex == bIndex {
+		return df, nil
+	}
+
+	df.Headers = slices.Clone(df.Headers)
+	df.Headers[aIndex], df.Headers[bIndex] = df.Headers[bIndex], df.Headers[aIndex]
+	for i := range df.Data {
+		row := df.writableRow(i)
+		row[aIndex], row[bIndex] = row[bIndex], row[aIndex]
+	}
+	return df, nil
+}
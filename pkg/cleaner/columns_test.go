@@ -0,0 +1,198 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestAddColumn(t *testing.T) {
+	data := [][]string{{"Ali"}, {"Ayşe"}}
+	df, _ := NewDataFrame([]string{"name"}, data)
+	copied := df.Copy()
+
+	if _, err := df.AddColumn("city", []string{"Ankara", "İzmir"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Headers, []string{"name", "city"}) {
+		t.Errorf("unexpected headers: %v", df.Headers)
+	}
+	if !reflect.DeepEqual(df.Data, [][]string{{"Ali", "Ankara"}, {"Ayşe", "İzmir"}}) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+	if df.Types["city"] != TypeString {
+		t.Errorf("expected string type, got %v", df.Types["city"])
+	}
+	if len(copied.Headers) != 1 || len(copied.Data[0]) != 1 || len(data[0]) != 1 {
+		t.Error("expected copies and the input rows not to change")
+	}
+
+	if _, err := df.AddColumn("name", []string{"a", "b"}); err == nil {
+		t.Error("expected an error for an existing column")
+	}
+	if _, err := df.AddColumn("country", []string{"TR"}); err == nil {
+		t.Error("expected an error for a wrong number of values")
+	}
+	if _, err := df.AddColumn("", []string{"a", "b"}); err == nil {
+		t.Error("expected an error for an empty name")
+	}
+}
+
+func TestAddColumnFunc(t *testing.T) {
+	df, _ := NewDataFrame([]string{"price"}, [][]string{{"100"}, {"12.5"}})
+	df, err := NewPipeline().
+		Trim().
+		AddColumnFunc("price_with_tax", func(row []string) string {
+			price, _ := strconv.ParseFloat(row[0], 64)
+			return strconv.FormatFloat(price*1.2, 'f', 2, 64)
+		}).
+		Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Data, [][]string{{"100", "120.00"}, {"12.5", "15.00"}}) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+
+	if _, err := df.AddColumnFunc("other", nil); err == nil {
+		t.Error("expected an error for a nil function")
+	}
+}
+
+func TestDropColumns(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "name", "email"}, [][]string{{"1", "Ali", "a@x.com"}, {"2", "Ayşe", "b@x.com"}})
+	copied := df.Copy()
+
+	if _, err := df.DropColumns("id", "email"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Headers, []string{"name"}) || !reflect.DeepEqual(df.Data, [][]string{{"Ali"}, {"Ayşe"}}) {
+		t.Errorf("unexpected result: %v %v", df.Headers, df.Data)
+	}
+	if _, ok := df.Types["email"]; ok {
+		t.Error("expected the type of a dropped column to be removed")
+	}
+	if len(copied.Data[0]) != 3 {
+		t.Error("expected copies not to change")
+	}
+
+	if _, err := df.DropColumns("name", "missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if len(df.Headers) != 1 {
+		t.Error("expected nothing to be removed when a column is missing")
+	}
+	if _, err := df.DropColumn("name"); err != nil || len(df.Headers) != 0 || len(df.Data[0]) != 0 {
+		t.Errorf("unexpected DropColumn result: %v %v", df.Headers, err)
+	}
+}
+
+func TestSelectColumns(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "name", "email", "created_at"}, [][]string{{"1", "Ali", "a@x.com", "2024-01-02"}})
+	df.WithStringColumns([]string{"id", "name"})
+
+	selected, err := df.SelectColumns("created_at", "id", "email")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(selected.Headers, []string{"created_at", "id", "email"}) || !reflect.DeepEqual(selected.Data, [][]string{{"2024-01-02", "1", "a@x.com"}}) {
+		t.Errorf("unexpected result: %v %v", selected.Headers, selected.Data)
+	}
+	if len(selected.Types) != 3 || !reflect.DeepEqual(selected.stringColumns, []string{"id"}) {
+		t.Errorf("unexpected types or string columns: %v %v", selected.Types, selected.stringColumns)
+	}
+	if len(df.Headers) != 4 || len(df.Data[0]) != 4 {
+		t.Error("expected the input not to change")
+	}
+
+	if _, err := df.SelectColumns("id", "missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := df.SelectColumns("id", "id"); err == nil {
+		t.Error("expected an error for a repeated column")
+	}
+
+	result, err := NewPipeline().Trim().SelectColumns("email").Run(df)
+	if err != nil || !reflect.DeepEqual(result.Headers, []string{"email"}) || result.Stats().RowsIn != 1 {
+		t.Errorf("unexpected pipeline result: %v %v", result, err)
+	}
+}
+
+func TestCopyColumn(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "mail", "email"}, [][]string{{"1", "a@x.com", ""}, {"2", "b@x.com", "old"}})
+	df.Types["mail"] = TypeJSON
+	df.WithStringColumns([]string{"id"})
+	original := df.Copy()
+
+	if _, err := df.CopyColumn("id", "customer_id"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Headers, []string{"id", "mail", "email", "customer_id"}) || df.Data[1][3] != "2" {
+		t.Errorf("expected customer_id appended with the ids, got %v %v", df.Headers, df.Data)
+	}
+	if !slices.Contains(df.stringColumns, "customer_id") {
+		t.Error("expected the string setting to be copied")
+	}
+
+	if _, err := df.CopyColumn("mail", "email"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if df.Data[0][2] != "a@x.com" || df.Data[1][2] != "b@x.com" || len(df.Headers) != 4 {
+		t.Errorf("expected email to be overwritten, got %v", df.Data)
+	}
+	if df.Types["email"] != TypeJSON {
+		t.Errorf("expected the type to be copied, got %v", df.Types["email"])
+	}
+	if original.Data[1][2] != "old" {
+		t.Error("expected a copy of the DataFrame not to change")
+	}
+
+	if _, err := df.CopyColumn("missing", "x"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := df.CopyColumn("id", ""); err == nil {
+		t.Error("expected an error for an empty name")
+	}
+}
+
+func TestSwapColumns(t *testing.T) {
+	df, _ := NewDataFrame([]string{"first", "last", "age"}, [][]string{{"Ali", "Yılmaz", "30"}})
+	df.Types["age"] = TypeInt
+	original := df.Copy()
+
+	if _, err := df.SwapColumns("first", "age"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Headers, []string{"age", "last", "first"}) || !reflect.DeepEqual(df.Data[0], []string{"30", "Yılmaz", "Ali"}) {
+		t.Errorf("unexpected result: %v %v", df.Headers, df.Data)
+	}
+	if df.Types["age"] != TypeInt {
+		t.Error("expected the type to move with the column")
+	}
+	if !reflect.DeepEqual(original.Headers, []string{"first", "last", "age"}) || original.Data[0][0] != "Ali" {
+		t.Errorf("expected a copy of the DataFrame not to change, got %v %v", original.Headers, original.Data)
+	}
+	if _, err := df.SwapColumns("age", "missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestPipelineCopyAndSwapColumns(t *testing.T) {
+	df, _ := NewDataFrame([]string{"a", "b"}, [][]string{{" 1 ", "2"}})
+	pipeline := NewPipeline()
+	if err := pipeline.Actions("trim", "copy_column:a=c", "swap_columns:a=b"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := pipeline.Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Headers, []string{"b", "a", "c"}) || !reflect.DeepEqual(result.Data[0], []string{"2", "1", "1"}) {
+		t.Errorf("unexpected result: %v %v", result.Headers, result.Data)
+	}
+	if err := NewPipeline().Action("swap_columns:a"); err == nil {
+		t.Error("expected a usage error")
+	}
+}
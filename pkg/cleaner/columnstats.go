@@ -0,0 +1,106 @@
+package cleaner
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// StreamColumnStats holds the statistics of an output column gathered while a stream runs, see
+// WithColumnStats. Min and Max are compared as numbers if every non-empty value is a number and
+// lexicographically otherwise, like in ColumnSummary.
+type StreamColumnStats struct {
+	Column   string `json:"column"`
+	Numeric  bool   `json:"numeric"`  // Every non-empty value is a number
+	Count    int    `json:"count"`    // Non-empty values
+	Nulls    int    `json:"nulls"`    // Empty or whitespace-only values
+	Distinct int    `json:"distinct"` // Approximate distinct non-empty values, within about 1%
+	Min      string `json:"min"`      // Empty if the column has no values
+	Max      string `json:"max"`      // Empty if the column has no values
+}
+
+// columnStatsCollector gathers the statistics of the columns in one pass over the rows, in memory
+// independent of the number of rows
+type columnStatsCollector struct {
+	columns []onlineColumnStats
+}
+
+// onlineColumnStats holds the running statistics of a column
+type onlineColumnStats struct {
+	count, nulls     int
+	distinct         *hyperLogLog
+	minText, maxText string // Lexicographic min and max
+	numeric          bool   // Every value so far is a number
+	minNumber        float64
+	maxNumber        float64
+	minNumberText    string
+	maxNumberText    string
+}
+
+// newColumnStatsCollector returns a collector for rows of len(headers) columns
+func newColumnStatsCollector(headers []string) *columnStatsCollector {
+	c := &columnStatsCollector{columns: make([]onlineColumnStats, len(headers))}
+	for j := range c.columns {
+		c.columns[j] = onlineColumnStats{distinct: newHyperLogLog(), numeric: true}
+	}
+	return c
+}
+
+// add adds the values of the rows to the statistics
+func (c *columnStatsCollector) add(rows [][]string) {
+	for _, row := range rows {
+		for j := range c.columns {
+			if j < len(row) {
+				c.columns[j].add(strings.TrimSpace(row[j]))
+			}
+		}
+	}
+}
+
+// add adds a trimmed value to the statistics of the column
+func (s *onlineColumnStats) add(value string) {
+	if isNull(value) {
+		s.nulls++
+		return
+	}
+	s.distinct.add(value)
+	first := s.count == 0
+	s.count++
+	if first || value < s.minText {
+		s.minText = value
+	}
+	if first || value > s.maxText {
+		s.maxText = value
+	}
+	if !s.numeric {
+		return
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		s.numeric = false
+		return
+	}
+	if first || n < s.minNumber {
+		s.minNumber, s.minNumberText = n, value
+	}
+	if first || n > s.maxNumber {
+		s.maxNumber, s.maxNumberText = n, value
+	}
+}
+
+// result returns the statistics of the columns, in column order
+func (c *columnStatsCollector) result(headers []string) []StreamColumnStats {
+	stats := make([]StreamColumnStats, len(c.columns))
+	for j, s := range c.columns {
+		stats[j] = StreamColumnStats{Column: headers[j], Count: s.count, Nulls: s.nulls, Min: s.minText, Max: s.maxText}
+		if s.count == 0 {
+			continue
+		}
+		stats[j].Distinct = min(s.distinct.count(), s.count)
+		if s.numeric {
+			stats[j].Numeric = true
+			stats[j].Min, stats[j].Max = s.minNumberText, s.maxNumberText
+		}
+	}
+	return stats
+}
@@ -0,0 +1,90 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Concat stacks the rows of the DataFrames into a new one, e.g. to combine daily export files:
+//
+//	combined, err := cleaner.Concat(monday, tuesday, wednesday)
+//
+// The columns are the union of all headers in the order they first appear; a row gets an empty
+// value for the columns its DataFrame does not have. A column keeps its type if it has the same
+// type in every DataFrame with the column, otherwise it becomes TypeString. The inputs are not
+// modified.
+func Concat(dfs ...*DataFrame) (*DataFrame, error) {
+	if len(dfs) == 0 {
+		return nil, errors.New("concat requires at least one DataFrame")
+	}
+
+	var headers []string
+	types := make(map[string]Type)
+	var stringColumns []string
+	for k, df := range dfs {
+		if df == nil {
+			return nil, fmt.Errorf("DataFrame %d is nil", k)
+		}
+		for _, header := range df.Headers {
+			typ, seen := types[header]
+			if !seen {
+				headers = append(headers, header)
+				types[header] = df.Types[header]
+			} else if typ != df.Types[header] {
+				types[header] = TypeString
+			}
+			if slices.Contains(df.stringColumns, header) && !slices.Contains(stringColumns, header) {
+				stringColumns = append(stringColumns, header)
+			}
+		}
+	}
+
+	rows := 0
+	for _, df := range dfs {
+		rows += len(df.Data)
+	}
+	data := make([][]string, 0, rows)
+	for _, df := range dfs {
+		// Position of every column of df in the combined row
+		positions := make([]int, len(df.Headers))
+		for j, header := range df.Headers {
+			positions[j] = slices.Index(headers, header)
+		}
+		for _, row := range df.Data {
+			newRow := make([]string, len(headers))
+			for j, value := range row {
+				newRow[positions[j]] = value
+			}
+			data = append(data, newRow)
+		}
+	}
+
+	return &DataFrame{
+		Headers: headers,
+		Data:    data,
+		Types:   types,
+
+		stringColumns: stringColumns,
+		collation:     dfs[0].collation,
+	}, nil
+}
+
+// AppendRows appends rows to the DataFrame. Every row must have one value per column, in the order
+// of the headers; if one does not, nothing is appended. The rows are copied, so the caller may
+// reuse them.
+func (df *DataFrame) AppendRows(rows [][]string) (*DataFrame, error) {
+	for i, row := range rows {
+		if len(row) != len(df.Headers) {
+			return nil, fmt.Errorf("row %d has an incompatible number of columns: %d (expected: %d)", i, len(row), len(df.Headers))
+		}
+	}
+
+	for _, row := range rows {
+		df.Data = append(df.Data, slices.Clone(row))
+		if df.cow != nil {
+			df.cow.owned = append(df.cow.owned, true)
+		}
+	}
+	return df, nil
+}
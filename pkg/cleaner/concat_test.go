@@ -0,0 +1,69 @@
+package cleaner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	monday, _ := NewDataFrame([]string{"id", "amount"}, [][]string{{"1", "10"}, {"2", "20"}})
+	monday.Types["amount"] = TypeInt
+	monday.WithStringColumns([]string{"id"})
+	tuesday, _ := NewDataFrame([]string{"amount", "id", "city"}, [][]string{{"12.5", "3", "Ankara"}})
+	tuesday.Types["amount"] = TypeFloat
+
+	combined, err := Concat(monday, tuesday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(combined.Headers, []string{"id", "amount", "city"}) {
+		t.Errorf("unexpected headers: %v", combined.Headers)
+	}
+	expected := [][]string{{"1", "10", ""}, {"2", "20", ""}, {"3", "12.5", "Ankara"}}
+	if !reflect.DeepEqual(combined.Data, expected) {
+		t.Errorf("unexpected data: %v", combined.Data)
+	}
+	if combined.Types["amount"] != TypeString || combined.Types["city"] != TypeString {
+		t.Errorf("expected differing types to become string: %v", combined.Types)
+	}
+	if !reflect.DeepEqual(combined.stringColumns, []string{"id"}) {
+		t.Errorf("unexpected string columns: %v", combined.stringColumns)
+	}
+
+	combined.Data[0][0] = "changed"
+	if monday.Data[0][0] != "1" {
+		t.Error("expected the inputs not to change")
+	}
+
+	if _, err := Concat(); err == nil {
+		t.Error("expected an error without DataFrames")
+	}
+	if _, err := Concat(monday, nil); err == nil {
+		t.Error("expected an error for a nil DataFrame")
+	}
+}
+
+func TestAppendRows(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "name"}, [][]string{{"1", "Ali"}})
+	copied := df.Copy()
+
+	rows := [][]string{{"2", "Ayşe"}, {"3", "Mehmet"}}
+	if _, err := df.AppendRows(rows); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows[0][1] = "changed"
+	expected := [][]string{{"1", "Ali"}, {"2", "Ayşe"}, {"3", "Mehmet"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+	if len(copied.Data) != 1 {
+		t.Error("expected copies not to change")
+	}
+
+	if _, err := df.AppendRows([][]string{{"4", "Zeynep"}, {"5"}}); err == nil {
+		t.Error("expected an error for a short row")
+	}
+	if len(df.Data) != 3 {
+		t.Error("expected nothing to be appended when a row is invalid")
+	}
+}
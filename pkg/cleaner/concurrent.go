@@ -0,0 +1,103 @@
+package cleaner
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ConcurrentDataFrame is a DataFrame guarded by a read-write lock, for sharing one DataFrame, such
+// as a reference dataset, between goroutines. Any number of goroutines may read at the same time;
+// writes are serialized and wait for running reads.
+//
+// A plain DataFrame is not safe for concurrent use: its operations modify it in place, and even
+// Copy updates the copy-on-write state of the source.
+type ConcurrentDataFrame struct {
+	mu sync.RWMutex
+	df *DataFrame
+}
+
+// Concurrent returns a concurrency-safe view of the DataFrame. After this call the DataFrame must
+// only be accessed through the view.
+func (df *DataFrame) Concurrent() *ConcurrentDataFrame {
+	return &ConcurrentDataFrame{df: df}
+}
+
+// Read calls fn with the DataFrame under the read lock. fn must not modify the DataFrame or call
+// methods that do, including Copy; use Snapshot to get a private copy.
+func (c *ConcurrentDataFrame) Read(fn func(df *DataFrame) error) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return fn(c.df)
+}
+
+// Write calls fn with the DataFrame under the write lock and stores the DataFrame fn returns.
+// On error the stored DataFrame is unchanged, unless fn modified it in place before failing.
+func (c *ConcurrentDataFrame) Write(fn func(df *DataFrame) (*DataFrame, error)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, err := fn(c.df)
+	if err != nil {
+		return err
+	}
+	if result != nil {
+		c.df = result
+	}
+	return nil
+}
+
+// Snapshot returns a copy of the DataFrame that the caller owns. Rows are shared copy-on-write, so
+// a snapshot is cheap and later writes to either side are not visible to the other.
+func (c *ConcurrentDataFrame) Snapshot() *DataFrame {
+	// Copy marks the rows of the source as shared, so it needs the write lock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.df.Copy()
+}
+
+// Shape returns the number of rows and columns
+func (c *ConcurrentDataFrame) Shape() (int, int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.df.Shape()
+}
+
+// Headers returns a copy of the headers
+func (c *ConcurrentDataFrame) Headers() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.df.Headers...)
+}
+
+// Row returns a copy of row i
+func (c *ConcurrentDataFrame) Row(i int) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if i < 0 || i >= len(c.df.Data) {
+		return nil, fmt.Errorf("row index out of range: %d", i)
+	}
+	return append([]string(nil), c.df.Data[i]...), nil
+}
+
+// Value returns the value of a cell
+func (c *ConcurrentDataFrame) Value(row int, column string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	colIndex, err := c.df.requireColumn(column)
+	if err != nil {
+		return "", err
+	}
+	if row < 0 || row >= len(c.df.Data) {
+		return "", fmt.Errorf("row index out of range: %d", row)
+	}
+	return c.df.Data[row][colIndex], nil
+}
+
+// SetValue sets a single cell under the write lock
+func (c *ConcurrentDataFrame) SetValue(row int, column string, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.df.SetValue(row, column, value)
+}
@@ -0,0 +1,95 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentReadsAndWrites(t *testing.T) {
+	df, _ := NewDataFrame([]string{"code", "name"}, [][]string{{"tr", "turkey"}, {"de", "germany"}})
+	shared := df.Concurrent()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if w == 0 {
+					err := shared.Write(func(df *DataFrame) (*DataFrame, error) {
+						return df.NormalizeCase("name", i%2 == 0)
+					})
+					if err != nil {
+						t.Errorf("Write error: %v", err)
+					}
+					continue
+				}
+				if _, err := shared.Value(1, "name"); err != nil {
+					t.Errorf("Value error: %v", err)
+				}
+				if rows, _ := shared.Shape(); rows != 2 {
+					t.Errorf("expected 2 rows, got %d", rows)
+				}
+				snapshot := shared.Snapshot()
+				if _, err := snapshot.ReplaceNulls("code", "xx"); err != nil {
+					t.Errorf("ReplaceNulls error: %v", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if name, _ := shared.Value(0, "name"); name != "turkey" {
+		t.Errorf("expected the last write to lower-case the names, got %q", name)
+	}
+}
+
+func TestConcurrentSnapshotIsIsolated(t *testing.T) {
+	df, _ := NewDataFrame([]string{"name"}, [][]string{{"ali"}})
+	shared := df.Concurrent()
+
+	snapshot := shared.Snapshot()
+	if err := shared.SetValue(0, "name", "veli"); err != nil {
+		t.Fatalf("SetValue error: %v", err)
+	}
+	if snapshot.Data[0][0] != "ali" {
+		t.Errorf("snapshot changed after write: %q", snapshot.Data[0][0])
+	}
+
+	if err := snapshot.SetValue(0, "name", "ayşe"); err != nil {
+		t.Fatalf("SetValue error: %v", err)
+	}
+	if v, _ := shared.Value(0, "name"); v != "veli" {
+		t.Errorf("shared DataFrame changed after snapshot write: %q", v)
+	}
+}
+
+func TestConcurrentWriteError(t *testing.T) {
+	df, _ := NewDataFrame([]string{"name"}, [][]string{{"ali"}})
+	shared := df.Concurrent()
+
+	err := shared.Write(func(df *DataFrame) (*DataFrame, error) {
+		return df.SplitColumn("missing", " ", []string{"a"})
+	})
+	if !errors.Is(err, ErrColumnNotFound) {
+		t.Fatalf("expected ErrColumnNotFound, got %v", err)
+	}
+	if headers := shared.Headers(); fmt.Sprint(headers) != "[name]" {
+		t.Errorf("DataFrame should be unchanged, got headers %v", headers)
+	}
+	if _, err := shared.Row(5); err == nil {
+		t.Error("expected an out of range error")
+	}
+
+	err = shared.Read(func(df *DataFrame) error {
+		if df.Data[0][0] != "ali" {
+			return fmt.Errorf("unexpected value %q", df.Data[0][0])
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
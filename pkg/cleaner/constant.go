@@ -0,0 +1,81 @@
+package cleaner
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ConstantColumns returns the columns, in column order, whose most common value fills at least
+// threshold of the rows, e.g. 1 for columns with a single value or 0.99 for columns that are 99%
+// identical. Empty cells count as a value, so empty columns are constant. A DataFrame without rows
+// has no constant columns.
+func (df *DataFrame) ConstantColumns(threshold float64) ([]string, error) {
+	if threshold <= 0 || threshold > 1 {
+		return nil, fmt.Errorf("threshold must be in (0, 1]: %g", threshold)
+	}
+
+	var columns []string
+	if len(df.Data) == 0 {
+		return columns, nil
+	}
+	counts := make(map[string]int)
+	for colIndex, header := range df.Headers {
+		clear(counts)
+		common := 0
+		for _, row := range df.Data {
+			counts[row[colIndex]]++
+			common = max(common, counts[row[colIndex]])
+		}
+		if float64(common) >= threshold*float64(len(df.Data)) {
+			columns = append(columns, header)
+		}
+	}
+	return columns, nil
+}
+
+// DropConstantColumns drops the columns ConstantColumns returns for the threshold. They carry no
+// information, e.g. a country column that is "TR" on every row.
+func (df *DataFrame) DropConstantColumns(threshold float64) (*DataFrame, error) {
+	columns, err := df.ConstantColumns(threshold)
+	if err != nil {
+		return nil, err
+	}
+	return df.dropColumns(columns), nil
+}
+
+// dropColumns removes the existing columns
+func (df *DataFrame) dropColumns(columns []string) *DataFrame {
+	if len(columns) == 0 {
+		return df
+	}
+	keep := make([]int, 0, len(df.Headers))
+	newHeaders := make([]string, 0, len(df.Headers))
+	for j, header := range df.Headers {
+		if !slices.Contains(columns, header) {
+			keep = append(keep, j)
+			newHeaders = append(newHeaders, header)
+		}
+	}
+
+	newData := make([][]string, len(df.Data))
+	for i, row := range df.Data {
+		newRow := make([]string, len(keep))
+		for k, j := range keep {
+			newRow[k] = row[j]
+		}
+		newData[i] = newRow
+	}
+
+	newTypes := make(map[string]Type, len(newHeaders))
+	for _, header := range newHeaders {
+		if t, ok := df.Types[header]; ok {
+			newTypes[header] = t
+		}
+	}
+
+	df.Headers = newHeaders
+	df.Data = newData
+	df.Types = newTypes
+	df.cow = nil
+	return df
+}
@@ -0,0 +1,52 @@
+package cleaner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConstantColumns(t *testing.T) {
+	data := make([][]string, 100)
+	for i := range data {
+		data[i] = []string{"TR", "", "a", "1"}
+	}
+	data[0][2] = "b"
+	data[1][3] = "2"
+	data[2][3] = "3"
+	df, _ := NewDataFrame([]string{"country", "notes", "flag", "id"}, data)
+
+	columns, err := df.ConstantColumns(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(columns, []string{"country", "notes"}) {
+		t.Errorf("expected country and notes, got %v", columns)
+	}
+
+	columns, _ = df.ConstantColumns(0.99)
+	if !reflect.DeepEqual(columns, []string{"country", "notes", "flag"}) {
+		t.Errorf("expected country, notes and flag, got %v", columns)
+	}
+
+	if _, err := df.ConstantColumns(1.5); err == nil {
+		t.Error("expected an error for a threshold above 1")
+	}
+}
+
+func TestDropConstantColumns(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "country", "name"}, [][]string{{"1", "TR", "Ali"}, {"2", "TR", "Ayşe"}})
+	df.Types["id"] = TypeInt
+
+	if _, err := df.DropConstantColumns(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Headers, []string{"id", "name"}) {
+		t.Errorf("unexpected headers: %v", df.Headers)
+	}
+	if !reflect.DeepEqual(df.Data, [][]string{{"1", "Ali"}, {"2", "Ayşe"}}) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+	if _, ok := df.Types["country"]; ok || df.Types["id"] != TypeInt {
+		t.Errorf("unexpected types: %v", df.Types)
+	}
+}
@@ -0,0 +1,136 @@
+package cleaner
+
+import (
+	"fmt"
+	"slices"
+)
+
+// cowState tracks which rows a DataFrame owns. Rows that are not owned are shared with
+// another DataFrame and are copied before the first write.
+type cowState struct {
+	owned []bool
+}
+
+// Copy returns a copy of the DataFrame. Rows are shared copy-on-write: a row is only duplicated
+// when one of the DataFrames modifies it through its methods, so copying a large DataFrame costs
+// one pointer per row. Headers and types are copied immediately.
+//
+// Writing to Data directly bypasses copy-on-write and is visible in every copy; use SetValue for
+// single-cell writes, or DeepCopy for fully independent data.
+func (df *DataFrame) Copy() *DataFrame {
+	df.shareRows()
+
+	newTypes := make(map[string]Type, len(df.Types))
+	for k, v := range df.Types {
+		newTypes[k] = v
+	}
+
+	return &DataFrame{
+		Headers: append([]string{}, df.Headers...),
+		Data:    append([][]string(nil), df.Data...),
+		Types:   newTypes,
+		cow:     &cowState{owned: make([]bool, len(df.Data))},
+
+		stringColumns: slices.Clone(df.stringColumns),
+		collation:     df.collation,
+	}
+}
+
+// DeepCopy returns a copy of the DataFrame that shares no memory with the original
+func (df *DataFrame) DeepCopy() *DataFrame {
+	return df.deepCopyRows(df.Data)
+}
+
+// deepCopyRows returns a DataFrame with the columns of df and copies of the rows, which need not
+// be rows of df, sharing no memory with the original
+func (df *DataFrame) deepCopyRows(rows [][]string) *DataFrame {
+	newData := make([][]string, len(rows))
+	for i, row := range rows {
+		newData[i] = append([]string(nil), row...)
+	}
+
+	newTypes := make(map[string]Type, len(df.Types))
+	for k, v := range df.Types {
+		newTypes[k] = v
+	}
+
+	return &DataFrame{
+		Headers: append([]string{}, df.Headers...),
+		Data:    newData,
+		Types:   newTypes,
+
+		stringColumns: slices.Clone(df.stringColumns),
+		collation:     df.collation,
+	}
+}
+
+// SetValue sets a single cell, copying the row first if it is shared with a copy
+func (df *DataFrame) SetValue(row int, column string, value string) error {
+	colIndex := df.getColumnIndex(column)
+	if colIndex == -1 {
+		return fmt.Errorf("%w: %s", ErrColumnNotFound, column)
+	}
+	if row < 0 || row >= len(df.Data) {
+		return fmt.Errorf("row index out of range: %d", row)
+	}
+	df.writableRow(row)[colIndex] = value
+	return nil
+}
+
+// shareRows marks all rows as shared with another DataFrame
+func (df *DataFrame) shareRows() {
+	df.cow = &cowState{owned: make([]bool, len(df.Data))}
+}
+
+// writableRow returns row i, copying it first if it is shared. Workers may call it
+// concurrently for different rows.
+func (df *DataFrame) writableRow(i int) []string {
+	if df.cow != nil && i < len(df.cow.owned) && !df.cow.owned[i] {
+		df.Data[i] = append([]string(nil), df.Data[i]...)
+		df.cow.owned[i] = true
+	}
+	return df.Data[i]
+}
+
+// setCell writes a value only when it differs, so unchanged shared rows are not copied
+func (df *DataFrame) setCell(i, j int, value string) {
+	if df.Data[i][j] != value {
+		df.writableRow(i)[j] = value
+	}
+}
+
+// internCell replaces a cell with an equal string, e.g. a canonical copy shared by several cells.
+// Shared rows are left alone: copying them would cost more memory than interning releases, and
+// the other DataFrame still references the original string.
+func (df *DataFrame) internCell(i, j int, value string) {
+	if df.cow != nil && i < len(df.cow.owned) && !df.cow.owned[i] {
+		return
+	}
+	if df.Data[i][j] == value {
+		df.Data[i][j] = value
+	}
+}
+
+// retainRows keeps the rows for which keep is true, in order, together with their ownership
+func (df *DataFrame) retainRows(keep []bool) {
+	kept := make([][]string, 0, len(df.Data))
+	var owned []bool
+	if df.cow != nil {
+		owned = make([]bool, 0, len(df.Data))
+	}
+
+	for i, row := range df.Data {
+		if !keep[i] {
+			continue
+		}
+		kept = append(kept, row)
+		if df.cow != nil {
+			owned = append(owned, i < len(df.cow.owned) && df.cow.owned[i])
+		}
+	}
+
+	df.Data = kept
+	if df.cow != nil {
+		df.cow = &cowState{owned: owned}
+	}
+}
@@ -0,0 +1,146 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func cowTestDataFrame() *DataFrame {
+	df, _ := NewDataFrame([]string{"name", "age"}, [][]string{
+		{"ali", "30"},
+		{"AYŞE", ""},
+		{"mehmet", "150"},
+	})
+	return df
+}
+
+// sameRow reports whether two rows share their backing array
+func sameRow(a, b []string) bool {
+	return len(a) > 0 && len(b) > 0 && &a[0] == &b[0]
+}
+
+func TestCopySharesRowsUntilWrite(t *testing.T) {
+	df := cowTestDataFrame()
+	cp := df.Copy()
+
+	for i := range df.Data {
+		if !sameRow(df.Data[i], cp.Data[i]) {
+			t.Fatalf("row %d should be shared after Copy", i)
+		}
+	}
+
+	if _, err := cp.NormalizeCase("name", true); err != nil {
+		t.Fatalf("NormalizeCase error: %v", err)
+	}
+
+	if fmt.Sprint(df.Data) != fmt.Sprint([][]string{{"ali", "30"}, {"AYŞE", ""}, {"mehmet", "150"}}) {
+		t.Errorf("original was modified: %v", df.Data)
+	}
+	if fmt.Sprint(cp.Data) != fmt.Sprint([][]string{{"ALI", "30"}, {"AYŞE", ""}, {"MEHMET", "150"}}) {
+		t.Errorf("unexpected copy: %v", cp.Data)
+	}
+
+	// Only modified rows are duplicated
+	if !sameRow(df.Data[1], cp.Data[1]) {
+		t.Error("unchanged row should still be shared")
+	}
+	if sameRow(df.Data[0], cp.Data[0]) {
+		t.Error("modified row should have been copied")
+	}
+}
+
+func TestCopyWriteToOriginal(t *testing.T) {
+	df := cowTestDataFrame()
+	cp := df.Copy()
+
+	if _, err := df.ReplaceNullsParallel("age", "0", WithMaxWorkers(2)); err != nil {
+		t.Fatalf("ReplaceNullsParallel error: %v", err)
+	}
+	if cp.Data[1][1] != "" {
+		t.Errorf("copy was modified by a write to the original: %v", cp.Data[1])
+	}
+	if df.Data[1][1] != "0" {
+		t.Errorf("original was not modified: %v", df.Data[1])
+	}
+}
+
+func TestCopyFilterOutliers(t *testing.T) {
+	df := cowTestDataFrame()
+	cp := df.Copy()
+
+	if _, err := cp.FilterOutliers("age", 0, 100); err != nil {
+		t.Fatalf("FilterOutliers error: %v", err)
+	}
+	// Row ownership must follow the kept rows
+	if _, err := cp.NormalizeCase("name", true); err != nil {
+		t.Fatalf("NormalizeCase error: %v", err)
+	}
+
+	if len(df.Data) != 3 || df.Data[0][0] != "ali" {
+		t.Errorf("original was modified: %v", df.Data)
+	}
+	if fmt.Sprint(cp.Data) != fmt.Sprint([][]string{{"ALI", "30"}, {"AYŞE", ""}}) {
+		t.Errorf("unexpected copy: %v", cp.Data)
+	}
+}
+
+func TestFilterOutliersParallelResultIsIndependent(t *testing.T) {
+	df := cowTestDataFrame()
+	filtered, err := df.FilterOutliersParallel("age", 0, 100)
+	if err != nil {
+		t.Fatalf("FilterOutliersParallel error: %v", err)
+	}
+
+	if err := filtered.SetValue(0, "name", "veli"); err != nil {
+		t.Fatalf("SetValue error: %v", err)
+	}
+	if df.Data[0][0] != "ali" {
+		t.Errorf("original was modified through the filtered result: %v", df.Data[0])
+	}
+}
+
+func TestSetValue(t *testing.T) {
+	df := cowTestDataFrame()
+	cp := df.Copy()
+
+	if err := cp.SetValue(2, "age", "45"); err != nil {
+		t.Fatalf("SetValue error: %v", err)
+	}
+	if df.Data[2][1] != "150" || cp.Data[2][1] != "45" {
+		t.Errorf("unexpected values: original=%v copy=%v", df.Data[2], cp.Data[2])
+	}
+
+	if err := cp.SetValue(0, "missing", "x"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if err := cp.SetValue(10, "age", "x"); err == nil {
+		t.Error("expected error for out of range row")
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	df := cowTestDataFrame()
+	cp := df.DeepCopy()
+
+	cp.Data[0][0] = "veli"
+	if df.Data[0][0] != "ali" {
+		t.Errorf("DeepCopy shares data with the original")
+	}
+}
+
+func BenchmarkCopy(b *testing.B) {
+	source := benchmarkDataFrame(100000, 8)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		source.Copy()
+	}
+}
+
+func BenchmarkDeepCopy(b *testing.B) {
+	source := benchmarkDataFrame(100000, 8)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		source.DeepCopy()
+	}
+}
@@ -3,16 +3,29 @@ package cleaner
 import (
 	"errors"
 	"fmt"
-	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+
+	"golang.org/x/text/language"
 )
 
-// DataFrame is the basic data structure for data cleaning operations
+// DataFrame is the basic data structure for data cleaning operations.
+//
+// A DataFrame is not safe for concurrent use. *Parallel operations use several goroutines
+// internally but must not run concurrently with other operations on the same DataFrame.
+// Use Concurrent to share one DataFrame between goroutines.
 type DataFrame struct {
 	Headers []string        // Column headers
 	Data    [][]string      // Data consisting of rows and columns
 	Types   map[string]Type // Data type of each column
+
+	cow           *cowState     // Rows shared with copies; nil when all rows are owned
+	stats         *Stats        // Counts of the pipeline run that produced the DataFrame
+	rejects       *DataFrame    // Rows dropped by the pipeline run that produced the DataFrame, if captured
+	canary        *CanaryReport // Canary run of the pipeline run that produced the DataFrame, if any
+	stringColumns []string      // Columns that are never converted to numbers, see WithStringColumns
+	collation     language.Tag  // Language of string comparisons, see WithCollation; Und for byte order
 }
 
 // GetHeaders returns the headers of the DataFrame
@@ -67,22 +80,37 @@ func NewDataFrame(headers []string, data [][]string) (*DataFrame, error) {
 func (df *DataFrame) TrimColumns() *DataFrame {
 	for i := range df.Data {
 		for j := range df.Data[i] {
-			df.Data[i][j] = strings.TrimSpace(df.Data[i][j])
+			df.setCell(i, j, strings.TrimSpace(df.Data[i][j]))
 		}
 	}
 	return df
 }
 
+// SanitizeControlChars, remove non-printable characters and zero-width spaces and replace tabs, line
+// breaks and exotic spaces such as NBSP with a regular space in the specified columns (default: all columns)
+func (df *DataFrame) SanitizeControlChars(columns ...string) (*DataFrame, error) {
+	indexes, err := df.columnIndices(columns)
+	if err != nil {
+		return nil, err
+	}
+	for i := range df.Data {
+		for _, j := range indexes {
+			df.setCell(i, j, sanitizeControlChars(df.Data[i][j]))
+		}
+	}
+	return df, nil
+}
+
 // ReplaceNulls, replace empty values with the specified default value
 func (df *DataFrame) ReplaceNulls(column string, defaultValue string) (*DataFrame, error) {
 	colIndex := df.getColumnIndex(column)
 	if colIndex == -1 {
-		return nil, fmt.Errorf("column not found: %s", column)
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
 	}
 
 	for i := range df.Data {
-		if df.Data[i][colIndex] == "" {
-			df.Data[i][colIndex] = defaultValue
+		if isNull(df.Data[i][colIndex]) {
+			df.setCell(i, colIndex, defaultValue)
 		}
 	}
 	return df, nil
@@ -90,23 +118,25 @@ func (df *DataFrame) ReplaceNulls(column string, defaultValue string) (*DataFram
 
 // CleanDates converts date values in the specified column to the specified output layout.
 // The user-provided layout is tried first as input format, then common formats are attempted.
-func (df *DataFrame) CleanDates(column string, layout string) (*DataFrame, error) {
+// Unix timestamps such as 1673741400 or 1673741400000 are converted as UTC times; see WithEpoch.
+func (df *DataFrame) CleanDates(column string, layout string, options ...DateOption) (*DataFrame, error) {
 	colIndex := df.getColumnIndex(column)
 	if colIndex == -1 {
-		return nil, fmt.Errorf("column not found: %s", column)
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
 	}
+	opts := newDateOptions(options)
 
 	for i := range df.Data {
 		if df.Data[i][colIndex] == "" {
 			continue
 		}
 
-		t, err := parseDate(df.Data[i][colIndex], layout)
+		t, err := parseDateEpoch(df.Data[i][colIndex], layout, opts.epoch)
 		if err != nil {
 			return nil, fmt.Errorf("row %d, column %s: date format not found: %s", i, column, df.Data[i][colIndex])
 		}
 
-		df.Data[i][colIndex] = t.Format(layout)
+		df.setCell(i, colIndex, t.Format(layout))
 	}
 
 	df.Types[column] = TypeDate
@@ -117,14 +147,14 @@ func (df *DataFrame) CleanDates(column string, layout string) (*DataFrame, error
 func (df *DataFrame) NormalizeCase(column string, toUpper bool) (*DataFrame, error) {
 	colIndex := df.getColumnIndex(column)
 	if colIndex == -1 {
-		return nil, fmt.Errorf("column not found: %s", column)
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
 	}
 
 	for i := range df.Data {
 		if toUpper {
-			df.Data[i][colIndex] = strings.ToUpper(df.Data[i][colIndex])
+			df.setCell(i, colIndex, toUpperCase(df.Data[i][colIndex]))
 		} else {
-			df.Data[i][colIndex] = strings.ToLower(df.Data[i][colIndex])
+			df.setCell(i, colIndex, toLowerCase(df.Data[i][colIndex]))
 		}
 	}
 	return df, nil
@@ -134,7 +164,7 @@ func (df *DataFrame) NormalizeCase(column string, toUpper bool) (*DataFrame, err
 func (df *DataFrame) RenameColumn(oldName, newName string) (*DataFrame, error) {
 	colIndex := df.getColumnIndex(oldName)
 	if colIndex == -1 {
-		return nil, fmt.Errorf("column not found: %s", oldName)
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, oldName)
 	}
 
 	// If the new name already exists, return an error
@@ -190,18 +220,18 @@ func (df *DataFrame) Shape() (int, int) {
 func (df *DataFrame) CleanWithRegex(column string, pattern string, replacement string) (*DataFrame, error) {
 	colIndex := df.getColumnIndex(column)
 	if colIndex == -1 {
-		return nil, fmt.Errorf("column not found: %s", column)
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
 	}
 
-	// Compile regex
-	re, err := regexp.Compile(pattern)
+	// Compile regex (cached across calls)
+	re, err := compileRegex(pattern)
 	if err != nil {
-		return nil, fmt.Errorf("invalid regex: %w", err)
+		return nil, err
 	}
 
 	// Clean the values
 	for i := range df.Data {
-		df.Data[i][colIndex] = re.ReplaceAllString(df.Data[i][colIndex], replacement)
+		df.setCell(i, colIndex, re.ReplaceAllString(df.Data[i][colIndex], replacement))
 	}
 
 	return df, nil
@@ -211,15 +241,15 @@ func (df *DataFrame) CleanWithRegex(column string, pattern string, replacement s
 func (df *DataFrame) SplitColumn(column string, separator string, newColumns []string) (*DataFrame, error) {
 	colIndex := df.getColumnIndex(column)
 	if colIndex == -1 {
-		return nil, fmt.Errorf("column not found: %s", column)
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
 	}
 
-	// Yeni sütun sayısı kontrol et
+	// Check the number of new columns
 	if len(newColumns) == 0 {
 		return nil, errors.New("at least one new column name must be specified")
 	}
 
-	// Yeni sütun adlarının benzersiz olduğunu kontrol et
+	// Check that the new column names are unique
 	for _, newCol := range newColumns {
 		if df.getColumnIndex(newCol) != -1 {
 			return nil, fmt.Errorf("column already exists: %s", newCol)
@@ -278,6 +308,109 @@ func (df *DataFrame) SplitColumn(column string, separator string, newColumns []s
 	df.Headers = newHeaders
 	df.Data = newData
 	df.Types = newTypes
+	df.cow = nil
+
+	return df, nil
+}
+
+// CoalesceColumns, merge several columns into newName, taking the first non-empty value of every row.
+// The new column takes the place of the first source column and the source columns are dropped.
+// newName may be one of the source columns, e.g. to fill phone from phone_home.
+func (df *DataFrame) CoalesceColumns(newName string, columns ...string) (*DataFrame, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("at least one column to coalesce must be specified")
+	}
+
+	sources := make([]int, len(columns))
+	for k, column := range columns {
+		sources[k] = df.getColumnIndex(column)
+		if sources[k] == -1 {
+			return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
+		}
+	}
+	if i := df.getColumnIndex(newName); i != -1 && !slices.Contains(sources, i) {
+		return nil, fmt.Errorf("column already exists: %s", newName)
+	}
+
+	// Update the headers
+	first := slices.Min(sources)
+	newHeaders := make([]string, 0, len(df.Headers))
+	for i, header := range df.Headers {
+		switch {
+		case i == first:
+			newHeaders = append(newHeaders, newName)
+		case !slices.Contains(sources, i):
+			newHeaders = append(newHeaders, header)
+		}
+	}
+
+	// Update the data
+	newData := make([][]string, len(df.Data))
+	for i, row := range df.Data {
+		value := ""
+		for _, j := range sources {
+			if row[j] != "" {
+				value = row[j]
+				break
+			}
+		}
+		newRow := make([]string, 0, len(newHeaders))
+		for j, cell := range row {
+			switch {
+			case j == first:
+				newRow = append(newRow, value)
+			case !slices.Contains(sources, j):
+				newRow = append(newRow, cell)
+			}
+		}
+		newData[i] = newRow
+	}
+
+	// The new column keeps the type the source columns share, or else becomes a string column
+	newType := df.Types[columns[0]]
+	newTypes := make(map[string]Type, len(newHeaders))
+	for header, typ := range df.Types {
+		if slices.Contains(columns, header) {
+			if typ != newType {
+				newType = TypeString
+			}
+			continue
+		}
+		newTypes[header] = typ
+	}
+	newTypes[newName] = newType
+
+	df.Headers = newHeaders
+	df.Data = newData
+	df.Types = newTypes
+	df.cow = nil
+
+	return df, nil
+}
+
+// AddRowNumber, add an integer column with the 1-based number of every row as the first column.
+// Added before cleaning, it lets rows dropped or changed later be traced back to their input row.
+func (df *DataFrame) AddRowNumber(name string) (*DataFrame, error) {
+	return df.addRowNumber(name, 1)
+}
+
+// addRowNumber adds the row number column, numbering the rows from first
+func (df *DataFrame) addRowNumber(name string, first int) (*DataFrame, error) {
+	if df.getColumnIndex(name) != -1 {
+		return nil, fmt.Errorf("column already exists: %s", name)
+	}
+
+	newData := make([][]string, len(df.Data))
+	for i, row := range df.Data {
+		newRow := make([]string, 0, len(row)+1)
+		newRow = append(newRow, strconv.Itoa(first+i))
+		newData[i] = append(newRow, row...)
+	}
+
+	df.Headers = append([]string{name}, df.Headers...)
+	df.Data = newData
+	df.Types[name] = TypeInt
+	df.cow = nil
 
 	return df, nil
 }
@@ -286,32 +419,29 @@ func (df *DataFrame) SplitColumn(column string, separator string, newColumns []s
 func (df *DataFrame) FilterOutliers(column string, min, max float64) (*DataFrame, error) {
 	colIndex := df.getColumnIndex(column)
 	if colIndex == -1 {
-		return nil, fmt.Errorf("column not found: %s", column)
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
 	}
 
-	// Collect filtered data
-	var filteredData [][]string
-	for _, row := range df.Data {
+	// Collect the rows to keep
+	bounds := newNumberRange(min, max)
+	keep := make([]bool, len(df.Data))
+	for i, row := range df.Data {
 		// Skip empty values
 		if row[colIndex] == "" {
-			filteredData = append(filteredData, row)
+			keep[i] = true
 			continue
 		}
 
-		// Convert to number
-		val, err := strconv.ParseFloat(row[colIndex], 64)
+		// Check if it is within the range
+		inRange, err := bounds.contains(row[colIndex])
 		if err != nil {
 			return nil, fmt.Errorf("conversion error: %w", err)
 		}
-
-		// Check if it is within the range
-		if val >= min && val <= max {
-			filteredData = append(filteredData, row)
-		}
+		keep[i] = inRange
 	}
 
 	// Update the DataFrame
-	df.Data = filteredData
+	df.retainRows(keep)
 
 	return df, nil
 }
@@ -2,292 +2,238 @@ package cleaner
 
 import (
 	"fmt"
-	"sync"
+	"strings"
 )
 
 // TrimColumnsParallel cleans whitespace at the beginning and end of all values in all columns in parallel
 func (df *DataFrame) TrimColumnsParallel(options ...func(*ParallelOptions)) (*DataFrame, error) {
-	return df.parallelizeRows(func(row []string) []string {
-		for i := range row {
-			row[i] = trimSpace(row[i])
+	return df.runObserved("trim", "", options, func(opts *ParallelOptions) (*DataFrame, error) {
+		columnIndices := make([]int, len(df.Headers))
+		for i := range columnIndices {
+			columnIndices[i] = i
 		}
-		return row
-	}, options...)
+		return df.parallelizeColumns(opts, columnIndices, trimSpace)
+	})
 }
 
 // ReplaceNullsParallel replaces empty values with the specified default value in parallel
 func (df *DataFrame) ReplaceNullsParallel(column string, defaultValue string, options ...func(*ParallelOptions)) (*DataFrame, error) {
-	colIndex := df.getColumnIndex(column)
-	if colIndex == -1 {
-		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
-	}
-
-	columnIndices := []int{colIndex}
-	return df.parallelizeColumns(columnIndices, func(row []string, colIdx int) []string {
-		if row[colIdx] == "" {
-			row[colIdx] = defaultValue
+	return df.runObserved("replace_nulls", column, options, func(opts *ParallelOptions) (*DataFrame, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
 		}
-		return row
-	}, options...)
+
+		return df.parallelizeColumns(opts, []int{colIndex}, func(value string) string {
+			if isNull(value) {
+				return defaultValue
+			}
+			return value
+		})
+	})
 }
 
-// CleanDatesParallel converts date values in the specified column to the specified format in parallel
+// CleanDatesParallel converts date values in the specified column to the specified format in parallel.
+// Values that cannot be parsed are left unchanged, unless WithDeterministic is set, in which case the
+// operation fails like CleanDates and sets the column type to TypeDate on success.
 func (df *DataFrame) CleanDatesParallel(column string, layout string, options ...func(*ParallelOptions)) (*DataFrame, error) {
-	colIndex := df.getColumnIndex(column)
-	if colIndex == -1 {
-		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
-	}
-
-	columnIndices := []int{colIndex}
-	return df.parallelizeColumns(columnIndices, func(row []string, colIdx int) []string {
-		if row[colIdx] == "" {
-			return row
+	return df.runObserved("clean_dates", column, options, func(opts *ParallelOptions) (*DataFrame, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
 		}
 
-		t, err := parseDate(row[colIdx], layout)
-		if err != nil {
-			return row
+		if opts.Deterministic {
+			err := df.parallelizeColumnStrict(opts, colIndex, func(i int, value string) (string, error) {
+				if value == "" {
+					return value, nil
+				}
+				t, err := parseDate(value, layout)
+				if err != nil {
+					return value, fmt.Errorf("row %d, column %s: date format not found: %s", i, column, value)
+				}
+				return t.Format(layout), nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			df.Types[column] = TypeDate
+			return df, nil
 		}
 
-		row[colIdx] = t.Format(layout)
-		return row
-	}, options...)
+		return df.parallelizeColumns(opts, []int{colIndex}, func(value string) string {
+			if value == "" {
+				return value
+			}
+
+			t, err := parseDate(value, layout)
+			if err != nil {
+				return value
+			}
+
+			return t.Format(layout)
+		})
+	})
 }
 
 // NormalizeCaseParallel converts text in the specified column to upper/lower case in parallel
 func (df *DataFrame) NormalizeCaseParallel(column string, toUpper bool, options ...func(*ParallelOptions)) (*DataFrame, error) {
-	colIndex := df.getColumnIndex(column)
-	if colIndex == -1 {
-		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
-	}
-
-	columnIndices := []int{colIndex}
-	return df.parallelizeColumns(columnIndices, func(row []string, colIdx int) []string {
-		if toUpper {
-			row[colIdx] = toUpperCase(row[colIdx])
-		} else {
-			row[colIdx] = toLowerCase(row[colIdx])
+	return df.runObserved("normalize_case", column, options, func(opts *ParallelOptions) (*DataFrame, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
 		}
-		return row
-	}, options...)
+
+		return df.parallelizeColumns(opts, []int{colIndex}, func(value string) string {
+			if toUpper {
+				return toUpperCase(value)
+			}
+			return toLowerCase(value)
+		})
+	})
 }
 
 // CleanWithRegexParallel cleans values in the specified column with regex in parallel
 func (df *DataFrame) CleanWithRegexParallel(column string, pattern string, replacement string, options ...func(*ParallelOptions)) (*DataFrame, error) {
-	colIndex := df.getColumnIndex(column)
-	if colIndex == -1 {
-		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
-	}
+	return df.runObserved("clean_regex", column, options, func(opts *ParallelOptions) (*DataFrame, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
 
-	re, err := compileRegex(pattern)
-	if err != nil {
-		return nil, err
-	}
+		re, err := compileRegex(pattern)
+		if err != nil {
+			return nil, err
+		}
 
-	columnIndices := []int{colIndex}
-	return df.parallelizeColumns(columnIndices, func(row []string, colIdx int) []string {
-		row[colIdx] = re.ReplaceAllString(row[colIdx], replacement)
-		return row
-	}, options...)
+		return df.parallelizeColumns(opts, []int{colIndex}, func(value string) string {
+			return re.ReplaceAllString(value, replacement)
+		})
+	})
 }
 
-// FilterOutliersParallel filters outlier values in the specified column in parallel
+// FilterOutliersParallel filters outlier values in the specified column in parallel.
+// Kept rows stay in their original order. Values that are not numbers are kept, unless
+// WithDeterministic is set, in which case the operation fails like FilterOutliers.
 func (df *DataFrame) FilterOutliersParallel(column string, min, max float64, options ...func(*ParallelOptions)) (*DataFrame, error) {
-	colIndex := df.getColumnIndex(column)
-	if colIndex == -1 {
-		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
-	}
-
-	opts := defaultParallelOptions()
-	for _, option := range options {
-		option(opts)
-	}
-
-	numWorkers := opts.MaxWorkers
-	if numWorkers > len(df.Data) {
-		numWorkers = len(df.Data)
-	}
-
-	jobs := make(chan int, len(df.Data))
-	results := make(chan struct {
-		index int
-		keep  bool
-	}, len(df.Data))
-
-	var wg sync.WaitGroup
-	for w := 0; w < numWorkers; w++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for rowIndex := range jobs {
-				select {
-				case <-opts.Context.Done():
-					return
-				default:
-				}
+	return df.runObserved("filter_outliers", column, options, func(opts *ParallelOptions) (*DataFrame, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
 
-				value := df.Data[rowIndex][colIndex]
+		// Workers only write the flags of their own rows; the result is assembled by row index
+		bounds := newNumberRange(min, max)
+		keep := make([]bool, len(df.Data))
+		errs := make([]error, len(df.Data))
+		err = processChunks(opts, len(df.Data), func(start, end int) {
+			for i := start; i < end; i++ {
+				value := df.Data[i][colIndex]
 				if value == "" {
-					results <- struct {
-						index int
-						keep  bool
-					}{rowIndex, true}
+					keep[i] = true
 					continue
 				}
-
-				num, err := parseFloat(value)
-				if err != nil {
-					results <- struct {
-						index int
-						keep  bool
-					}{rowIndex, true}
+				if opts.Deterministic {
+					inRange, err := bounds.contains(value)
+					errs[i] = err
+					keep[i] = err == nil && inRange
 					continue
 				}
-
-				results <- struct {
-					index int
-					keep  bool
-				}{rowIndex, num >= min && num <= max}
+				inRange, err := bounds.contains(strings.TrimSpace(value))
+				keep[i] = err != nil || inRange
 			}
-		}()
-	}
-
-	for i := range df.Data {
-		jobs <- i
-	}
-	close(jobs)
-
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+		})
+		if err != nil {
+			return nil, err
+		}
 
-	keepMap := make(map[int]bool, len(df.Data))
-	for result := range results {
-		keepMap[result.index] = result.keep
-	}
+		// The serial operation fails at the first value that is not a number
+		for _, err := range errs {
+			if err != nil {
+				return nil, fmt.Errorf("conversion error: %w", err)
+			}
+		}
 
-	if err := opts.Context.Err(); err != nil {
-		return nil, err
-	}
+		// The result shares the kept rows with df copy-on-write
+		filtered := &DataFrame{
+			Headers: df.Headers,
+			Data:    df.Data,
+			Types:   df.Types,
 
-	var filteredData [][]string
-	for i, row := range df.Data {
-		if keepMap[i] {
-			filteredData = append(filteredData, row)
+			stringColumns: df.stringColumns,
+			collation:     df.collation,
 		}
-	}
+		df.shareRows()
+		filtered.shareRows()
+		filtered.retainRows(keep)
+		return filtered, nil
+	})
+}
 
-	return &DataFrame{
-		Headers: df.Headers,
-		Data:    filteredData,
-		Types:   df.Types,
-	}, nil
+// BatchPipeline applies the processors one after another, each to the result of the previous one.
+// The context is checked before every processor.
+func (df *DataFrame) BatchPipeline(processors []func(*DataFrame) (*DataFrame, error), options ...func(*ParallelOptions)) (*DataFrame, error) {
+	return df.runObserved("batch_pipeline", "", options, func(opts *ParallelOptions) (*DataFrame, error) {
+		result := df
+		for i, processor := range processors {
+			if err := opts.Context.Err(); err != nil {
+				return nil, err
+			}
+			var err error
+			if result, err = processor(result); err != nil {
+				return nil, fmt.Errorf("processor %d: %w", i, err)
+			}
+		}
+		return result, nil
+	})
 }
 
-// BatchProcessParallel applies multiple processors in parallel, then combines results in order
-func (df *DataFrame) BatchProcessParallel(processors []func(*DataFrame) (*DataFrame, error), options ...func(*ParallelOptions)) (*DataFrame, error) {
+// BatchBranchesParallel applies every processor to its own copy of the DataFrame in parallel and
+// returns the independent results in processor order. The original DataFrame is not modified.
+func (df *DataFrame) BatchBranchesParallel(processors []func(*DataFrame) (*DataFrame, error), options ...func(*ParallelOptions)) ([]*DataFrame, error) {
 	if len(processors) == 0 {
-		return df, nil
+		return nil, nil
 	}
 
-	opts := defaultParallelOptions()
-	for _, option := range options {
-		option(opts)
-	}
-
-	numWorkers := opts.MaxWorkers
-	if numWorkers > len(processors) {
-		numWorkers = len(processors)
-	}
-
-	type jobItem struct {
-		index     int
-		processor func(*DataFrame) (*DataFrame, error)
-	}
-	jobs := make(chan jobItem, len(processors))
-	results := make(chan struct {
-		index int
-		df    *DataFrame
-		err   error
-	}, len(processors))
+	var results []*DataFrame
+	_, err := df.runObserved("batch_branches", "", options, func(opts *ParallelOptions) (*DataFrame, error) {
+		// Copies share rows copy-on-write; they are taken up front because Copy updates df
+		copies := make([]*DataFrame, len(processors))
+		for i := range copies {
+			copies[i] = df.Copy()
+		}
 
-	var wg sync.WaitGroup
-	for w := 0; w < numWorkers; w++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for j := range jobs {
-				select {
-				case <-opts.Context.Done():
+		results = make([]*DataFrame, len(processors))
+		errs := make([]error, len(processors))
+		err := processChunks(opts, len(processors), func(start, end int) {
+			for i := start; i < end; i++ {
+				if opts.Context.Err() != nil {
 					return
-				default:
 				}
-				resultDF, err := j.processor(df.Copy())
-				results <- struct {
-					index int
-					df    *DataFrame
-					err   error
-				}{j.index, resultDF, err}
+				results[i], errs[i] = processors[i](copies[i])
 			}
-		}()
-	}
-
-	for i, processor := range processors {
-		jobs <- jobItem{i, processor}
-	}
-	close(jobs)
-
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	type result struct {
-		df  *DataFrame
-		err error
-	}
-	resultMap := make(map[int]result)
-	for r := range results {
-		if r.err != nil {
-			return nil, r.err
+		})
+		if err != nil {
+			return nil, err
 		}
-		resultMap[r.index] = result{r.df, r.err}
-	}
-
-	if err := opts.Context.Err(); err != nil {
-		return nil, err
-	}
 
-	resultDF := df.Copy()
-	for i := range processors {
-		r, ok := resultMap[i]
-		if !ok || r.err != nil {
-			return nil, fmt.Errorf("missing or failed processor at index %d", i)
+		for i, err := range errs {
+			if err != nil {
+				return nil, fmt.Errorf("processor %d: %w", i, err)
+			}
 		}
-		resultDF = r.df
+		return df, nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	return resultDF, nil
+	return results, nil
 }
 
-// Copy creates a deep copy of the DataFrame
-func (df *DataFrame) Copy() *DataFrame {
-	newData := make([][]string, len(df.Data))
-	for i, row := range df.Data {
-		newRow := make([]string, len(row))
-		copy(newRow, row)
-		newData[i] = newRow
-	}
-
-	newTypes := make(map[string]Type, len(df.Types))
-	for k, v := range df.Types {
-		newTypes[k] = v
-	}
-
-	return &DataFrame{
-		Headers: append([]string{}, df.Headers...),
-		Data:    newData,
-		Types:   newTypes,
-	}
+// BatchProcessParallel applies the processors as a sequential pipeline.
+//
+// Deprecated: Earlier versions ran every processor on a copy and returned only the last result.
+// Use BatchPipeline for sequential composition or BatchBranchesParallel for independent results.
+func (df *DataFrame) BatchProcessParallel(processors []func(*DataFrame) (*DataFrame, error), options ...func(*ParallelOptions)) (*DataFrame, error) {
+	return df.BatchPipeline(processors, options...)
 }
@@ -1,6 +1,8 @@
 package cleaner
 
 import (
+	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -584,3 +586,104 @@ func TestCleanDates(t *testing.T) {
 		t.Errorf("CleanDates('Non-Column', ...) expected error, but no error occurred")
 	}
 }
+
+func TestCoalesceColumns(t *testing.T) {
+	df, _ := NewDataFrame([]string{"name", "phone_mobile", "city", "phone_home"}, [][]string{
+		{"Ali", "555-1", "Istanbul", "212-1"},
+		{"Ayşe", "", "Ankara", "312-2"},
+		{"Can", "", "Izmir", ""},
+	})
+
+	result, err := df.CoalesceColumns("phone", "phone_mobile", "phone_home")
+	if err != nil {
+		t.Fatalf("CoalesceColumns error: %v", err)
+	}
+
+	expectedHeaders := []string{"name", "phone", "city"}
+	if !reflect.DeepEqual(result.Headers, expectedHeaders) {
+		t.Errorf("expected headers %v, got %v", expectedHeaders, result.Headers)
+	}
+	expectedData := [][]string{{"Ali", "555-1", "Istanbul"}, {"Ayşe", "312-2", "Ankara"}, {"Can", "", "Izmir"}}
+	if !reflect.DeepEqual(result.Data, expectedData) {
+		t.Errorf("expected data %v, got %v", expectedData, result.Data)
+	}
+	if _, ok := result.Types["phone_home"]; ok {
+		t.Error("expected the type of a source column to be removed")
+	}
+	if result.Types["phone"] != TypeString {
+		t.Errorf("expected phone to be a string column, got %v", result.Types["phone"])
+	}
+}
+
+func TestCoalesceColumns_IntoSourceColumn(t *testing.T) {
+	df, _ := NewDataFrame([]string{"a", "b"}, [][]string{{"", "2"}, {"1", "3"}})
+	df.InferTypes()
+
+	result, err := df.CoalesceColumns("b", "a", "b")
+	if err != nil {
+		t.Fatalf("CoalesceColumns error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Headers, []string{"b"}) || !reflect.DeepEqual(result.Data, [][]string{{"2"}, {"1"}}) {
+		t.Errorf("unexpected result: %v %v", result.Headers, result.Data)
+	}
+	if result.Types["b"] != TypeInt {
+		t.Errorf("expected the shared int type to be kept, got %v", result.Types["b"])
+	}
+}
+
+func TestCoalesceColumns_Errors(t *testing.T) {
+	df, _ := NewDataFrame([]string{"a", "b", "c"}, [][]string{{"1", "2", "3"}})
+
+	if _, err := df.CoalesceColumns("x"); err == nil {
+		t.Error("expected an error without source columns")
+	}
+	if _, err := df.CoalesceColumns("x", "a", "missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := df.CoalesceColumns("c", "a", "b"); err == nil {
+		t.Error("expected an error when the new column already exists")
+	}
+}
+
+func TestDataFrameSanitizeControlChars(t *testing.T) {
+	df, _ := NewDataFrame([]string{"name", "note"}, [][]string{{"Ali\u200b", "a\u00a0b"}})
+
+	if _, err := df.SanitizeControlChars("name"); err != nil {
+		t.Fatalf("SanitizeControlChars error: %v", err)
+	}
+	if df.Data[0][0] != "Ali" || df.Data[0][1] != "a\u00a0b" {
+		t.Errorf("expected only the name column to be sanitized, got %q", df.Data[0])
+	}
+
+	if _, err := df.SanitizeControlChars(); err != nil {
+		t.Fatalf("SanitizeControlChars error: %v", err)
+	}
+	if df.Data[0][1] != "a b" {
+		t.Errorf("expected all columns to be sanitized, got %q", df.Data[0])
+	}
+
+	if _, err := df.SanitizeControlChars("missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestAddRowNumber(t *testing.T) {
+	df, _ := NewDataFrame([]string{"name"}, [][]string{{"Ali"}, {"Ayşe"}})
+
+	if _, err := df.AddRowNumber("row"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Headers, []string{"row", "name"}) {
+		t.Errorf("unexpected headers: %v", df.Headers)
+	}
+	if !reflect.DeepEqual(df.Data, [][]string{{"1", "Ali"}, {"2", "Ayşe"}}) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+	if df.Types["row"] != TypeInt {
+		t.Errorf("expected int type, got %v", df.Types["row"])
+	}
+
+	if _, err := df.AddRowNumber("name"); err == nil {
+		t.Error("expected an error for an existing column")
+	}
+}
@@ -0,0 +1,96 @@
+package cleaner
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// decimalPlaces is the number of decimal places of results without a terminating decimal
+// expansion in decimal mode, e.g. the mean of 1, 1 and 2
+const decimalPlaces = 20
+
+// parseDecimal parses a number such as "-1234.5678" or "1.2E+05" exactly, without the rounding of
+// float64
+func parseDecimal(value string) (*big.Rat, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+	if !plainNumberPattern.MatchString(s) {
+		return nil, fmt.Errorf("not a decimal number: %s", value)
+	}
+	r, _ := new(big.Rat).SetString(value)
+	return r, nil
+}
+
+// formatDecimal formats a decimal exactly, without trailing zeros. Results without a terminating
+// decimal expansion are rounded to decimalPlaces places.
+func formatDecimal(r *big.Rat) string {
+	d := new(big.Int).Set(r.Denom())
+	mod := new(big.Int)
+	for _, p := range []*big.Int{big.NewInt(2), big.NewInt(5)} {
+		for {
+			q, m := new(big.Int).QuoRem(d, p, mod)
+			if m.Sign() != 0 {
+				break
+			}
+			d = q
+		}
+	}
+	if d.IsInt64() && d.Int64() == 1 {
+		return decimalString(r)
+	}
+	return strings.TrimRight(r.FloatString(decimalPlaces), "0")
+}
+
+// numberRange is a [min, max] range of numbers. In decimal mode, values are compared exactly
+// with the shortest decimal representations of the bounds, e.g. 0.1 rather than the float64
+// closest to it.
+type numberRange struct {
+	min, max   float64
+	dmin, dmax *big.Rat // nil for an infinite bound
+	decimal    bool
+}
+
+// newNumberRange returns the range [min, max] in the current numeric mode
+func newNumberRange(min, max float64) numberRange {
+	r := numberRange{min: min, max: max, decimal: defaults.Load().Decimal}
+	// NaN bounds match no value, as with float64 comparisons
+	if math.IsNaN(min) || math.IsNaN(max) {
+		r.decimal = false
+	}
+	if r.decimal {
+		r.dmin, r.dmax = floatDecimal(min), floatDecimal(max)
+	}
+	return r
+}
+
+// floatDecimal returns the shortest decimal representation of f, nil if f is infinite
+func floatDecimal(f float64) *big.Rat {
+	if math.IsInf(f, 0) {
+		return nil
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return r
+}
+
+// contains reports whether the number value is in the range. Values that are not numbers are an
+// error.
+func (r numberRange) contains(value string) (bool, error) {
+	if !r.decimal {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false, err
+		}
+		return v >= r.min && v <= r.max, nil
+	}
+
+	v, err := parseDecimal(value)
+	if err != nil {
+		return false, err
+	}
+	if (r.dmin == nil && r.min > 0) || (r.dmax == nil && r.max < 0) {
+		return false, nil
+	}
+	return (r.dmin == nil || v.Cmp(r.dmin) >= 0) && (r.dmax == nil || v.Cmp(r.dmax) <= 0), nil
+}
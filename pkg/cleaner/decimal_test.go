@@ -0,0 +1,114 @@
+package cleaner
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestDecimalFilterOutliers(t *testing.T) {
+	frame := func() *DataFrame {
+		df, _ := NewDataFrame([]string{"amount"}, [][]string{{"0.30000000000000001"}, {"0.3"}, {"-1e-30"}, {""}})
+		return df
+	}
+
+	// float64 cannot tell 0.30000000000000001 from 0.3 or -1e-30 from 0
+	df, _ := frame().FilterOutliers("amount", 0, 0.3)
+	if len(df.Data) != 3 {
+		t.Fatalf("expected the float64 comparison to keep 3 rows, got %v", df.Data)
+	}
+
+	setTestDefaults(t, Defaults{Decimal: true})
+	expected := [][]string{{"0.3"}, {""}}
+	df, err := frame().FilterOutliers("amount", 0, 0.3)
+	if err != nil || !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected result: %v, %v", df.Data, err)
+	}
+	df, err = frame().FilterOutliersParallel("amount", 0, 0.3, WithMaxWorkers(2), WithDeterministic(true))
+	if err != nil || !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected parallel result: %v, %v", df.Data, err)
+	}
+	df, err = NewPipeline().FilterOutliers("amount", 0, 0.3).Run(frame())
+	if err != nil || !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected pipeline result: %v, %v", df.Data, err)
+	}
+
+	df, _ = frame().FilterOutliers("amount", math.Inf(-1), 0)
+	if !reflect.DeepEqual(df.Data, [][]string{{"-1e-30"}, {""}}) {
+		t.Errorf("unexpected result with an infinite bound: %v", df.Data)
+	}
+
+	bad, _ := NewDataFrame([]string{"amount"}, [][]string{{"NaN"}})
+	if _, err := bad.FilterOutliers("amount", 0, 1); err == nil {
+		t.Error("expected an error for a value that is not a decimal")
+	}
+}
+
+func TestDecimalAggregations(t *testing.T) {
+	df, _ := NewDataFrame([]string{"account", "amount"}, [][]string{
+		{"A", "0.1"},
+		{"A", "0.2"},
+		{"B", "12345678901234.56"},
+		{"B", "0.01"},
+		{"B", "1"},
+		{"C", ""},
+	})
+	aggs := []Aggregation{Sum("amount"), Mean("amount"), Min("amount"), Max("amount")}
+
+	float, _ := df.GroupBy("account").Agg(aggs...)
+	if float.Data[0][1] != "0.30000000000000004" {
+		t.Fatalf("expected the float64 rounding error, got %s", float.Data[0][1])
+	}
+
+	setTestDefaults(t, Defaults{Decimal: true})
+	summary, err := df.GroupBy("account").Agg(aggs...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{
+		{"A", "0.3", "0.15", "0.1", "0.2"},
+		{"B", "12345678901235.57", "4115226300411.85666666666666666667", "0.01", "12345678901234.56"},
+		{"C", "0", "", "", ""},
+	}
+	if !reflect.DeepEqual(summary.Data, expected) {
+		t.Errorf("unexpected data: %v", summary.Data)
+	}
+}
+
+func TestDecimalGroupByStep_Spill(t *testing.T) {
+	setTestDefaults(t, Defaults{Decimal: true})
+	var rows [][]string
+	for i := 0; i < 300; i++ {
+		rows = append(rows, []string{fmt.Sprintf("g%d", i%100), "0.1"})
+	}
+	step := GroupByStep([]string{"key"}, Sum("value"))
+	sink := runStream(t, []string{"key", "value"}, rows,
+		[]StreamOption{WithChunkSize(30), WithMemoryLimit(3000), WithTempDir(t.TempDir())}, step)
+
+	if step.(*groupByStep).partitions == nil {
+		t.Fatal("expected group by to spill")
+	}
+	for _, row := range sink.rows {
+		if row[1] != "0.3" {
+			t.Errorf("unexpected sum for %s: %s", row[0], row[1])
+		}
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	for value, expected := range map[string]string{
+		"1.50":   "1.5",
+		"-2":     "-2",
+		"1/3":    "0.33333333333333333333",
+		"2/3":    "0.66666666666666666667",
+		"1/8":    "0.125",
+		"100/10": "10",
+	} {
+		r, _ := new(big.Rat).SetString(value)
+		if got := formatDecimal(r); got != expected {
+			t.Errorf("formatDecimal(%s) = %s, expected %s", value, got, expected)
+		}
+	}
+}
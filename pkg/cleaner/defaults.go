@@ -0,0 +1,98 @@
+package cleaner
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// Defaults holds the package-level defaults of all operations, so an embedding service configures
+// them once with SetDefaults instead of passing options to every call
+type Defaults struct {
+	// NullTokens are values treated as empty, besides the empty string, e.g. "NULL" or "N/A".
+	// They are compared exactly with the values, trimmed of surrounding whitespace, by
+	// ReplaceNulls, AssertNonNull, Describe and QualityScore.
+	NullTokens []string
+	// DateFormats are input layouts CleanDates tries after the output layout and before the
+	// common layouts, e.g. "02.01.2006" for Turkish dates
+	DateFormats []string
+	// Workers is the number of workers of parallel operations without WithMaxWorkers;
+	// 0 means as many as CPU cores
+	Workers int
+	// Locale selects the case mapping of NormalizeCase: "" for the Unicode mapping, or "tr" and
+	// "az" for the dotted and dotless i, e.g. "istanbul" to "İSTANBUL". With "tr" and "az",
+	// NormalizeNumericFormats also reads '.' as the thousands and ',' as the decimal separator,
+	// e.g. "₺1.234,56" to "1234.56".
+	Locale string
+	// Decimal parses numbers as exact decimals instead of float64 in FilterOutliers and the Sum,
+	// Mean, Min and Max aggregations, so monetary values such as 12345678901234.56 survive
+	// cleaning bit-exact
+	Decimal bool
+}
+
+// defaults holds the current Defaults; it is replaced as a whole, so readers never see a partial
+// update
+var defaults atomic.Pointer[Defaults]
+
+func init() {
+	defaults.Store(&Defaults{})
+}
+
+// SetDefaults replaces the package-level defaults. It is safe for concurrent use; operations
+// already running may see either the old or the new defaults.
+func SetDefaults(d Defaults) error {
+	if d.Workers < 0 {
+		return fmt.Errorf("workers must not be negative: %d", d.Workers)
+	}
+	switch strings.ToLower(d.Locale) {
+	case "", "tr", "az":
+	default:
+		return fmt.Errorf("unknown locale: %s", d.Locale)
+	}
+
+	d.NullTokens = slices.Clone(d.NullTokens)
+	d.DateFormats = slices.Clone(d.DateFormats)
+	d.Locale = strings.ToLower(d.Locale)
+	defaults.Store(&d)
+	return nil
+}
+
+// GetDefaults returns a copy of the package-level defaults
+func GetDefaults() Defaults {
+	d := *defaults.Load()
+	d.NullTokens = slices.Clone(d.NullTokens)
+	d.DateFormats = slices.Clone(d.DateFormats)
+	return d
+}
+
+// isNull reports whether a value is empty or one of the null tokens
+func isNull(value string) bool {
+	if value == "" {
+		return true
+	}
+	tokens := defaults.Load().NullTokens
+	return len(tokens) > 0 && slices.Contains(tokens, strings.TrimSpace(value))
+}
+
+// caseMapping returns the special case mapping of the locale, nil for the Unicode mapping
+func caseMapping() unicode.SpecialCase {
+	switch defaults.Load().Locale {
+	case "tr":
+		return unicode.TurkishCase
+	case "az":
+		return unicode.AzeriCase
+	}
+	return nil
+}
+
+// localeDecimalComma reports whether numbers of the locale use a decimal comma and dot thousands
+// separators, e.g. 1.234,56
+func localeDecimalComma() bool {
+	switch defaults.Load().Locale {
+	case "tr", "az":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package cleaner
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// setTestDefaults sets the defaults for the test and restores them when it ends
+func setTestDefaults(t *testing.T, d Defaults) {
+	t.Helper()
+	if err := SetDefaults(d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { SetDefaults(Defaults{}) })
+}
+
+func TestSetDefaultsNullTokens(t *testing.T) {
+	setTestDefaults(t, Defaults{NullTokens: []string{"NULL", "N/A"}})
+
+	df, _ := NewDataFrame([]string{"city"}, [][]string{{"Izmir"}, {"NULL"}, {" N/A "}, {""}, {"null"}})
+	if err := df.AssertNonNull("city"); err == nil {
+		t.Error("expected the null tokens to fail the assertion")
+	}
+	if s := df.Describe()[0]; s.Nulls != 3 || s.Count != 2 {
+		t.Errorf("expected 3 nulls and 2 values, got %+v", s)
+	}
+
+	if _, err := df.ReplaceNulls("city", "Unknown"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{{"Izmir"}, {"Unknown"}, {"Unknown"}, {"Unknown"}, {"null"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+}
+
+func TestSetDefaultsDateFormatsAndLocale(t *testing.T) {
+	df, _ := NewDataFrame([]string{"date", "city"}, [][]string{{"15.01.2024", "istanbul"}})
+	if _, err := df.Copy().CleanDates("date", "2006-01-02"); err == nil {
+		t.Fatal("expected an error without the date format")
+	}
+
+	setTestDefaults(t, Defaults{DateFormats: []string{"02.01.2006"}, Locale: "TR"})
+	df, err := NewPipeline().CleanDates("date", "2006-01-02").NormalizeCase("city", true).Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Data, [][]string{{"2024-01-15", "İSTANBUL"}}) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+	if got := GetDefaults(); got.Locale != "tr" || !reflect.DeepEqual(got.DateFormats, []string{"02.01.2006"}) {
+		t.Errorf("unexpected defaults: %+v", got)
+	}
+}
+
+func TestSetDefaultsWorkers(t *testing.T) {
+	setTestDefaults(t, Defaults{Workers: 3})
+	if workers := defaultParallelOptions().MaxWorkers; workers != 3 {
+		t.Errorf("expected 3 workers, got %d", workers)
+	}
+	opts := defaultParallelOptions()
+	WithMaxWorkers(5)(opts)
+	if opts.MaxWorkers != 5 {
+		t.Errorf("expected WithMaxWorkers to win, got %d", opts.MaxWorkers)
+	}
+
+	if err := SetDefaults(Defaults{Workers: -1}); err == nil {
+		t.Error("expected an error for negative workers")
+	}
+	if err := SetDefaults(Defaults{Locale: "xx"}); err == nil {
+		t.Error("expected an error for an unknown locale")
+	}
+	if GetDefaults().Workers != 3 {
+		t.Error("expected invalid defaults to be ignored")
+	}
+}
+
+func TestSetDefaultsConcurrent(t *testing.T) {
+	t.Cleanup(func() { SetDefaults(Defaults{}) })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetDefaults(Defaults{NullTokens: []string{"NULL"}, DateFormats: []string{"02.01.2006"}, Workers: i + 1})
+		}()
+		go func() {
+			defer wg.Done()
+			df, _ := NewDataFrame([]string{"date", "city"}, [][]string{{"2024-01-15", "NULL"}})
+			if _, err := df.CleanDatesParallel("date", "2006-01-02"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if _, err := df.ReplaceNullsParallel("city", "Unknown"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,109 @@
+package cleaner
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ColumnSummary holds the summary statistics of a column. Min and Max are the smallest and
+// largest values, compared as numbers in numeric columns and lexicographically in the others.
+// Mean, Median and StdDev are only set for numeric columns.
+type ColumnSummary struct {
+	Column   string   `json:"column"`
+	Numeric  bool     `json:"numeric"`          // Every non-empty value is a number
+	Count    int      `json:"count"`            // Non-empty values
+	Nulls    int      `json:"nulls"`            // Empty or whitespace-only values
+	Distinct int      `json:"distinct"`         // Distinct non-empty values
+	Min      string   `json:"min"`              // Empty if the column has no values
+	Max      string   `json:"max"`              // Empty if the column has no values
+	Mean     *float64 `json:"mean,omitempty"`   // Arithmetic mean
+	Median   *float64 `json:"median,omitempty"` // Middle value, the mean of the two middle values for an even count
+	StdDev   *float64 `json:"stddev,omitempty"` // Sample standard deviation, 0 for a single value
+}
+
+// Describe returns the summary statistics of every column, in column order:
+//
+//	for _, s := range df.Describe() {
+//		fmt.Println(s.Column, s.Count, s.Nulls, s.Distinct, s.Min, s.Max)
+//	}
+//
+// A column is numeric if it has at least one value and every non-empty value is a number, except
+// for the columns set with WithStringColumns. Values are trimmed before they are compared.
+func (df *DataFrame) Describe() []ColumnSummary {
+	summaries := make([]ColumnSummary, len(df.Headers))
+	values := make([]string, 0, len(df.Data))
+	for j, header := range df.Headers {
+		values = values[:0]
+		for _, row := range df.Data {
+			if value := strings.TrimSpace(row[j]); !isNull(value) {
+				values = append(values, value)
+			}
+		}
+		summaries[j] = describeColumn(header, values, len(df.Data), slices.Contains(df.stringColumns, header))
+	}
+	return summaries
+}
+
+// describeColumn summarizes the non-empty values of a column of rows rows
+func describeColumn(column string, values []string, rows int, stringColumn bool) ColumnSummary {
+	summary := ColumnSummary{Column: column, Count: len(values), Nulls: rows - len(values)}
+	distinct := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		distinct[value] = struct{}{}
+	}
+	summary.Distinct = len(distinct)
+	if len(values) == 0 {
+		return summary
+	}
+
+	var numbers []float64
+	if !stringColumn {
+		numbers = make([]float64, 0, len(values))
+		for _, value := range values {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+				numbers = nil
+				break
+			}
+			numbers = append(numbers, n)
+		}
+	}
+	if numbers == nil {
+		summary.Min, summary.Max = slices.Min(values), slices.Max(values)
+		return summary
+	}
+
+	// Min and Max keep the original text of the values, e.g. "1.50"
+	summary.Numeric = true
+	minIndex, maxIndex := 0, 0
+	var sum float64
+	for i, n := range numbers {
+		if n < numbers[minIndex] {
+			minIndex = i
+		}
+		if n > numbers[maxIndex] {
+			maxIndex = i
+		}
+		sum += n
+	}
+	summary.Min, summary.Max = values[minIndex], values[maxIndex]
+
+	mean := sum / float64(len(numbers))
+	var squares float64
+	for _, n := range numbers {
+		squares += (n - mean) * (n - mean)
+	}
+	stdDev := 0.0
+	if len(numbers) > 1 {
+		stdDev = math.Sqrt(squares / float64(len(numbers)-1))
+	}
+	slices.Sort(numbers)
+	median := numbers[len(numbers)/2]
+	if len(numbers)%2 == 0 {
+		median = (numbers[len(numbers)/2-1] + median) / 2
+	}
+	summary.Mean, summary.Median, summary.StdDev = &mean, &median, &stdDev
+	return summary
+}
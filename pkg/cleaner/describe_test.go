@@ -0,0 +1,64 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "price", "city", "notes"}, [][]string{
+		{"007", "10", "Izmir", ""},
+		{"008", "2.50", "Ankara", " "},
+		{"009", "", "Izmir", ""},
+		{"010", "30", "ankara", ""},
+	})
+	df.WithStringColumns([]string{"id"})
+
+	summaries := df.Describe()
+	if len(summaries) != 4 {
+		t.Fatalf("expected one summary per column, got %d", len(summaries))
+	}
+
+	id := summaries[0]
+	if id.Numeric || id.Min != "007" || id.Max != "010" || id.Mean != nil {
+		t.Errorf("expected string statistics for a string column, got %+v", id)
+	}
+
+	price := summaries[1]
+	if !price.Numeric || price.Count != 3 || price.Nulls != 1 || price.Distinct != 3 {
+		t.Errorf("unexpected counts: %+v", price)
+	}
+	if price.Min != "2.50" || price.Max != "30" {
+		t.Errorf("expected numeric min and max with their text, got %s and %s", price.Min, price.Max)
+	}
+	if *price.Mean != 42.5/3 || *price.Median != 10 {
+		t.Errorf("unexpected mean or median: %v %v", *price.Mean, *price.Median)
+	}
+	if math.Abs(*price.StdDev-14.2156) > 1e-4 {
+		t.Errorf("unexpected standard deviation: %v", *price.StdDev)
+	}
+
+	city := summaries[2]
+	if city.Numeric || city.Distinct != 3 || city.Min != "Ankara" || city.Max != "ankara" {
+		t.Errorf("unexpected string statistics: %+v", city)
+	}
+
+	notes := summaries[3]
+	if notes.Count != 0 || notes.Nulls != 4 || notes.Min != "" || notes.Numeric {
+		t.Errorf("unexpected statistics of an empty column: %+v", notes)
+	}
+
+	data, _ := json.Marshal(summaries[2])
+	if strings.Contains(string(data), "mean") {
+		t.Errorf("expected no numeric fields for a string column, got %s", data)
+	}
+}
+
+func TestDescribe_EvenMedian(t *testing.T) {
+	df, _ := NewDataFrame([]string{"n"}, [][]string{{"4"}, {"1"}, {"3"}, {"2"}})
+	if s := df.Describe()[0]; *s.Median != 2.5 || *s.StdDev == 0 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+}
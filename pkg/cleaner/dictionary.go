@@ -0,0 +1,96 @@
+package cleaner
+
+import "fmt"
+
+// Dictionary is the dictionary encoding of a column: its distinct values and one code per row.
+// It is a snapshot; later changes to the DataFrame are not reflected.
+type Dictionary struct {
+	Values []string // Distinct values in order of first appearance
+	Codes  []uint32 // Index into Values for every row
+
+	index map[string]uint32
+}
+
+// Len returns the number of distinct values
+func (d *Dictionary) Len() int {
+	return len(d.Values)
+}
+
+// Lookup returns the code of the value
+func (d *Dictionary) Lookup(value string) (uint32, bool) {
+	code, ok := d.index[value]
+	return code, ok
+}
+
+// Value returns the value of row i
+func (d *Dictionary) Value(i int) string {
+	return d.Values[d.Codes[i]]
+}
+
+// Counts returns the number of rows for every code
+func (d *Dictionary) Counts() []int {
+	counts := make([]int, len(d.Values))
+	for _, code := range d.Codes {
+		counts[code]++
+	}
+	return counts
+}
+
+// DictionaryEncode encodes the column as a dictionary. Repeated values in rows the DataFrame owns
+// are replaced by one shared string, so the memory of duplicates is released; rows shared with a
+// copy are left as they are. The returned codes are a snapshot for callers: other operations such
+// as DropDuplicates or GroupBy still compare the string values.
+func (df *DataFrame) DictionaryEncode(column string) (*Dictionary, error) {
+	colIndex := df.getColumnIndex(column)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
+	}
+	return df.encodeColumn(colIndex), nil
+}
+
+// CompactCategorical dictionary-encodes every column with at most maxDistinct distinct values,
+// such as country or status columns, to release the memory of their duplicates, and returns the
+// names of the encoded columns.
+func (df *DataFrame) CompactCategorical(maxDistinct int) []string {
+	var encoded []string
+	for j, header := range df.Headers {
+		if df.distinctAtMost(j, maxDistinct) {
+			df.encodeColumn(j)
+			encoded = append(encoded, header)
+		}
+	}
+	return encoded
+}
+
+// distinctAtMost reports whether the column has at most limit distinct values
+func (df *DataFrame) distinctAtMost(colIndex, limit int) bool {
+	seen := make(map[string]struct{}, limit+1)
+	for _, row := range df.Data {
+		seen[row[colIndex]] = struct{}{}
+		if len(seen) > limit {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeColumn builds the dictionary of the column and interns its values
+func (df *DataFrame) encodeColumn(colIndex int) *Dictionary {
+	d := &Dictionary{
+		Codes: make([]uint32, len(df.Data)),
+		index: make(map[string]uint32),
+	}
+
+	for i, row := range df.Data {
+		value := row[colIndex]
+		code, ok := d.index[value]
+		if !ok {
+			code = uint32(len(d.Values))
+			d.index[value] = code
+			d.Values = append(d.Values, value)
+		}
+		d.Codes[i] = code
+		df.internCell(i, colIndex, d.Values[code])
+	}
+	return d
+}
@@ -0,0 +1,97 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func dictionaryTestDataFrame() *DataFrame {
+	// Build values at runtime so equal values do not share memory
+	country := func(s string) string { return string([]byte(s)) }
+	df, _ := NewDataFrame([]string{"id", "country"}, [][]string{
+		{"1", country("TR")},
+		{"2", country("DE")},
+		{"3", country("TR")},
+		{"4", country("TR")},
+		{"5", country("US")},
+	})
+	return df
+}
+
+func TestDictionaryEncode(t *testing.T) {
+	df := dictionaryTestDataFrame()
+
+	d, err := df.DictionaryEncode("country")
+	if err != nil {
+		t.Fatalf("DictionaryEncode error: %v", err)
+	}
+
+	if !reflect.DeepEqual(d.Values, []string{"TR", "DE", "US"}) {
+		t.Errorf("Values = %v", d.Values)
+	}
+	if !reflect.DeepEqual(d.Codes, []uint32{0, 1, 0, 0, 2}) {
+		t.Errorf("Codes = %v", d.Codes)
+	}
+	if !reflect.DeepEqual(d.Counts(), []int{3, 1, 1}) {
+		t.Errorf("Counts = %v", d.Counts())
+	}
+	if code, ok := d.Lookup("US"); !ok || code != 2 {
+		t.Errorf("Lookup(US) = %v, %v", code, ok)
+	}
+	if _, ok := d.Lookup("FR"); ok {
+		t.Error("Lookup(FR) should not be found")
+	}
+	if d.Value(3) != "TR" || d.Len() != 3 {
+		t.Errorf("unexpected Value/Len: %s %d", d.Value(3), d.Len())
+	}
+
+	// Equal values now share their bytes
+	if unsafe.StringData(df.Data[0][1]) != unsafe.StringData(df.Data[3][1]) {
+		t.Error("repeated values were not interned")
+	}
+
+	if _, err := df.DictionaryEncode("missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestCompactCategorical(t *testing.T) {
+	df := dictionaryTestDataFrame()
+
+	encoded := df.CompactCategorical(3)
+	if !reflect.DeepEqual(encoded, []string{"country"}) {
+		t.Errorf("CompactCategorical(3) = %v, expected = [country]", encoded)
+	}
+
+	// Values are unchanged
+	expected := [][]string{{"1", "TR"}, {"2", "DE"}, {"3", "TR"}, {"4", "TR"}, {"5", "US"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("data changed: %v", df.Data)
+	}
+}
+
+func TestDictionaryEncode_SharedRows(t *testing.T) {
+	df := dictionaryTestDataFrame()
+	cp := df.Copy()
+	cp.SetValue(2, "id", "30")
+
+	if _, err := cp.DictionaryEncode("country"); err != nil {
+		t.Fatalf("DictionaryEncode error: %v", err)
+	}
+
+	// Shared rows are neither copied nor written, so the original keeps its strings
+	for _, i := range []int{0, 1, 3, 4} {
+		if &cp.Data[i][0] != &df.Data[i][0] {
+			t.Errorf("row %d was copied", i)
+		}
+	}
+	if unsafe.StringData(df.Data[0][1]) == unsafe.StringData(df.Data[3][1]) {
+		t.Error("shared rows of the original were interned")
+	}
+	// The owned row is interned
+	if unsafe.StringData(cp.Data[2][1]) != unsafe.StringData(cp.Data[0][1]) {
+		t.Error("owned row was not interned")
+	}
+}
@@ -0,0 +1,114 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrValueNotAllowed is the error of a value outside the domain of its column, see
+// EnforceAllowedValues
+var ErrValueNotAllowed = errors.New("value not allowed")
+
+// Violation selects what EnforceAllowedValues and EnforceDeniedValues do with a value outside the
+// domain of the column
+type Violation int
+
+const (
+	ViolationDrop    Violation = iota // Drop the row; captured rejects keep it
+	ViolationReplace                  // Empty the value, e.g. to fill it with ReplaceNulls later
+	ViolationReport                   // Keep the value and report it as a value error of the step
+)
+
+// ParseViolation parses the name of a violation action: drop, replace or report
+func ParseViolation(name string) (Violation, error) {
+	switch strings.ToLower(name) {
+	case "drop":
+		return ViolationDrop, nil
+	case "replace":
+		return ViolationReplace, nil
+	case "report":
+		return ViolationReport, nil
+	}
+	return 0, fmt.Errorf("unknown violation action: %s", name)
+}
+
+// valueDomain is the set of values a column may hold, or may not hold if denied
+type valueDomain struct {
+	values map[string]struct{}
+	denied bool
+}
+
+// newValueDomain returns the domain of the allowed or the denied values
+func newValueDomain(values []string, denied bool) valueDomain {
+	domain := valueDomain{values: make(map[string]struct{}, len(values)), denied: denied}
+	for _, value := range values {
+		domain.values[value] = struct{}{}
+	}
+	return domain
+}
+
+// violates reports whether a non-empty value is outside the domain
+func (d valueDomain) violates(value string) bool {
+	if value == "" {
+		return false
+	}
+	_, listed := d.values[value]
+	return listed == d.denied
+}
+
+// reason describes the values outside the domain for captured rejects
+func (d valueDomain) reason() string {
+	if d.denied {
+		return "value in denied values"
+	}
+	return "value not in allowed values"
+}
+
+// EnforceAllowedValues constrains a categorical column to a known domain, e.g. the status codes
+// of an order. Non-empty values that are not allowed are handled by onViolation: the row is
+// dropped, the value is emptied, or, with ViolationReport, the DataFrame is left unchanged and the
+// first violation is returned as an ErrValueNotAllowed error. Values are compared exactly, so
+// normalize their case first.
+func (df *DataFrame) EnforceAllowedValues(column string, allowed []string, onViolation Violation) (*DataFrame, error) {
+	return df.enforceDomain(column, newValueDomain(allowed, false), onViolation)
+}
+
+// EnforceDeniedValues handles the values of the column that are in denied like
+// EnforceAllowedValues handles the values that are not allowed, e.g. to remove placeholder values
+// such as "test" or "N/A"
+func (df *DataFrame) EnforceDeniedValues(column string, denied []string, onViolation Violation) (*DataFrame, error) {
+	return df.enforceDomain(column, newValueDomain(denied, true), onViolation)
+}
+
+// enforceDomain handles the values of the column outside the domain
+func (df *DataFrame) enforceDomain(column string, domain valueDomain, onViolation Violation) (*DataFrame, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, err
+	}
+
+	switch onViolation {
+	case ViolationDrop:
+		kept := make([]bool, len(df.Data))
+		for i, row := range df.Data {
+			kept[i] = !domain.violates(row[colIndex])
+		}
+		df.retainRows(kept)
+	case ViolationReplace:
+		for i, row := range df.Data {
+			if domain.violates(row[colIndex]) {
+				df.setCell(i, colIndex, "")
+			}
+		}
+	case ViolationReport:
+		for i, row := range df.Data {
+			if domain.violates(row[colIndex]) {
+				return nil, fmt.Errorf("row %d: %w: %s", i, ErrValueNotAllowed, row[colIndex])
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown violation action: %d", onViolation)
+	}
+	return df, nil
+}
@@ -0,0 +1,89 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func domainTestFrame() *DataFrame {
+	df, _ := NewDataFrame([]string{"id", "status"}, [][]string{
+		{"1", "paid"},
+		{"2", "PAID"},
+		{"3", ""},
+		{"4", "test"},
+		{"5", "shipped"},
+	})
+	return df
+}
+
+func TestEnforceAllowedValues(t *testing.T) {
+	allowed := []string{"paid", "shipped"}
+
+	df, err := domainTestFrame().EnforceAllowedValues("status", allowed, ViolationDrop)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{{"1", "paid"}, {"3", ""}, {"5", "shipped"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("expected the rows with allowed or empty values, got %v", df.Data)
+	}
+
+	df, _ = domainTestFrame().EnforceAllowedValues("status", allowed, ViolationReplace)
+	if df.Data[1][1] != "" || df.Data[3][1] != "" || df.Data[4][1] != "shipped" {
+		t.Errorf("expected the values that are not allowed to be emptied, got %v", df.Data)
+	}
+
+	df = domainTestFrame()
+	if _, err := df.EnforceAllowedValues("status", allowed, ViolationReport); !errors.Is(err, ErrValueNotAllowed) {
+		t.Errorf("expected ErrValueNotAllowed, got %v", err)
+	}
+	if df.Data[1][1] != "PAID" {
+		t.Error("expected the DataFrame not to change when reporting")
+	}
+
+	if _, err := domainTestFrame().EnforceAllowedValues("missing", allowed, ViolationDrop); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := domainTestFrame().EnforceAllowedValues("status", allowed, Violation(9)); err == nil {
+		t.Error("expected an error for an unknown violation action")
+	}
+}
+
+func TestEnforceDeniedValues(t *testing.T) {
+	df, err := domainTestFrame().EnforceDeniedValues("status", []string{"test"}, ViolationDrop)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(df.Data) != 4 || df.Data[3][0] != "5" {
+		t.Errorf("expected only the denied row to be dropped, got %v", df.Data)
+	}
+}
+
+func TestPipelineEnforceAllowedValues(t *testing.T) {
+	pipeline := NewPipeline().CaptureRejects()
+	if err := pipeline.Actions("allowed_values:status=paid,shipped,PAID", "denied_values:status=PAID=replace"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	df, err := pipeline.Run(domainTestFrame())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{{"1", "paid"}, {"2", ""}, {"3", ""}, {"5", "shipped"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected rows: %v", df.Data)
+	}
+	rejects := df.Rejects()
+	if len(rejects.Data) != 1 || rejects.Data[0][2] != "allowed_values(status): value not in allowed values" {
+		t.Errorf("unexpected rejects: %v", rejects.Data)
+	}
+
+	// Reported values are value errors of the step
+	pipeline = NewPipeline().EnforceAllowedValues("status", []string{"paid", "shipped"}, ViolationReport)
+	if _, err := pipeline.Run(domainTestFrame()); !errors.Is(err, ErrValueNotAllowed) {
+		t.Errorf("expected ErrValueNotAllowed, got %v", err)
+	}
+	if err := NewPipeline().Action("allowed_values:status=paid=ignore"); err == nil {
+		t.Error("expected an error for an unknown violation action")
+	}
+}
@@ -0,0 +1,123 @@
+package cleaner
+
+import (
+	"fmt"
+	"hash/maphash"
+)
+
+// DuplicateKeep selects which row of a group of duplicates DropDuplicates keeps
+type DuplicateKeep int
+
+const (
+	KeepFirst DuplicateKeep = iota // The first occurrence
+	KeepLast                       // The last occurrence, e.g. the latest update of a record
+)
+
+// DropDuplicates removes the rows whose values of the columns equal those of an earlier row,
+// keeping the first occurrence. Without columns, whole rows are compared. The kept rows keep their
+// order.
+func (df *DataFrame) DropDuplicates(columns ...string) (*DataFrame, error) {
+	return df.DropDuplicatesKeep(KeepFirst, columns...)
+}
+
+// DropDuplicatesKeep removes duplicate rows like DropDuplicates, keeping the first or the last
+// occurrence of every key:
+//
+//	df, err := df.DropDuplicatesKeep(cleaner.KeepLast, "customer_id")
+func (df *DataFrame) DropDuplicatesKeep(keep DuplicateKeep, columns ...string) (*DataFrame, error) {
+	indices, err := df.duplicateKeyColumns(keep, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	keyOf := df.rowKeyFunc()
+	kept := make([]bool, len(df.Data))
+	seen := make(map[string]struct{}, len(df.Data))
+	for k := range df.Data {
+		i := k
+		if keep == KeepLast {
+			i = len(df.Data) - 1 - k
+		}
+		key := keyOf(df.Data[i], indices)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			kept[i] = true
+		}
+	}
+	df.retainRows(kept)
+	return df, nil
+}
+
+// DropDuplicatesParallel removes duplicate rows like DropDuplicatesKeep and returns a new DataFrame
+// that shares the kept rows with df copy-on-write. The keys are built across workers and
+// partitioned by hash, so every worker deduplicates its own share of the keys; the result does not
+// depend on the number of workers.
+func (df *DataFrame) DropDuplicatesParallel(columns []string, keep DuplicateKeep, options ...func(*ParallelOptions)) (*DataFrame, error) {
+	return df.runObserved("drop_duplicates", "", options, func(opts *ParallelOptions) (*DataFrame, error) {
+		indices, err := df.duplicateKeyColumns(keep, columns)
+		if err != nil {
+			return nil, err
+		}
+
+		keyOf := df.rowKeyFunc()
+		shards := max(1, min(opts.MaxWorkers, len(df.Data)/minRowsPerWorker))
+		seed := maphash.MakeSeed()
+		keys := make([]string, len(df.Data))
+		shardOf := make([]int, len(df.Data))
+		err = processChunks(opts, len(df.Data), func(start, end int) {
+			for i := start; i < end; i++ {
+				keys[i] = keyOf(df.Data[i], indices)
+				shardOf[i] = int(maphash.String(seed, keys[i]) % uint64(shards))
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Every key belongs to one shard, so workers only write the flags of their own rows
+		kept := make([]bool, len(df.Data))
+		err = processChunks(opts, shards, func(shard, _ int) {
+			seen := make(map[string]struct{})
+			for k := range df.Data {
+				if k%ctxCheckInterval == 0 && opts.Context.Err() != nil {
+					return
+				}
+				i := k
+				if keep == KeepLast {
+					i = len(df.Data) - 1 - k
+				}
+				if shardOf[i] != shard {
+					continue
+				}
+				if _, ok := seen[keys[i]]; !ok {
+					seen[keys[i]] = struct{}{}
+					kept[i] = true
+				}
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		deduplicated := &DataFrame{
+			Headers: df.Headers,
+			Data:    df.Data,
+			Types:   df.Types,
+
+			stringColumns: df.stringColumns,
+			collation:     df.collation,
+		}
+		df.shareRows()
+		deduplicated.shareRows()
+		deduplicated.retainRows(kept)
+		return deduplicated, nil
+	})
+}
+
+// duplicateKeyColumns returns the indices of the key columns, all columns when none are given
+func (df *DataFrame) duplicateKeyColumns(keep DuplicateKeep, columns []string) ([]int, error) {
+	if keep != KeepFirst && keep != KeepLast {
+		return nil, fmt.Errorf("unknown duplicate keep mode: %d", keep)
+	}
+	return df.columnIndices(columns)
+}
@@ -0,0 +1,90 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func duplicatesTestFrame() *DataFrame {
+	df, _ := NewDataFrame([]string{"id", "email", "updated"}, [][]string{
+		{"1", "a@x.com", "2024-01-01"},
+		{"2", "b@x.com", "2024-01-01"},
+		{"1", "a@x.com", "2024-01-01"},
+		{"1", "a@x.com", "2024-02-01"},
+		{"3", "b@x.com", "2024-03-01"},
+	})
+	return df
+}
+
+func TestDropDuplicates(t *testing.T) {
+	df, err := duplicatesTestFrame().DropDuplicates()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(df.Data) != 4 || !reflect.DeepEqual(df.Data[2], []string{"1", "a@x.com", "2024-02-01"}) {
+		t.Errorf("expected only the identical row to be dropped, got %v", df.Data)
+	}
+
+	df, _ = duplicatesTestFrame().DropDuplicates("email")
+	expected := [][]string{{"1", "a@x.com", "2024-01-01"}, {"2", "b@x.com", "2024-01-01"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected first occurrences: %v", df.Data)
+	}
+
+	df, _ = duplicatesTestFrame().DropDuplicatesKeep(KeepLast, "id")
+	expected = [][]string{{"2", "b@x.com", "2024-01-01"}, {"1", "a@x.com", "2024-02-01"}, {"3", "b@x.com", "2024-03-01"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected last occurrences: %v", df.Data)
+	}
+
+	if _, err := duplicatesTestFrame().DropDuplicates("missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := duplicatesTestFrame().DropDuplicatesKeep(DuplicateKeep(5)); err == nil {
+		t.Error("expected an error for an unknown keep mode")
+	}
+}
+
+func TestDropDuplicatesParallel(t *testing.T) {
+	// Enough rows for several shards
+	data := make([][]string, 10000)
+	for i := range data {
+		data[i] = []string{fmt.Sprint(i % 997), fmt.Sprint(i)}
+	}
+	df, _ := NewDataFrame([]string{"key", "seq"}, data)
+
+	for _, keep := range []DuplicateKeep{KeepFirst, KeepLast} {
+		serial, _ := df.Copy().DropDuplicatesKeep(keep, "key")
+		parallel, err := df.DropDuplicatesParallel([]string{"key"}, keep, WithMaxWorkers(4))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(parallel.Data, serial.Data) {
+			t.Errorf("keep %d: expected the serial result", keep)
+		}
+	}
+	if len(df.Data) != 10000 {
+		t.Error("expected the input not to change")
+	}
+}
+
+func TestPipelineDropDuplicates(t *testing.T) {
+	pipeline := NewPipeline()
+	if err := pipeline.Actions("trim", "drop_duplicates:email=last"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	df, err := pipeline.Run(duplicatesTestFrame())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{{"1", "a@x.com", "2024-02-01"}, {"3", "b@x.com", "2024-03-01"}}
+	if !reflect.DeepEqual(df.Data, expected) || df.Stats().RowsOut != 2 {
+		t.Errorf("unexpected result: %v", df.Data)
+	}
+
+	if err := NewPipeline().Action("drop_duplicates:id=newest"); err == nil {
+		t.Error("expected an error for an unknown keep mode")
+	}
+}
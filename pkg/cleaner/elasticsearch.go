@@ -0,0 +1,11 @@
+package cleaner
+
+import (
+	"github.com/mstgnz/cleango/pkg/formats"
+)
+
+// WriteElasticsearch indexes every row of DataFrame as a document into an Elasticsearch index
+// through the bulk API, e.g. with formats.NewElasticsearchClient("http://localhost:9200")
+func (df *DataFrame) WriteElasticsearch(client formats.BulkIndexer, index string, options ...formats.ElasticsearchOption) error {
+	return formats.WriteElasticsearch(df, client, index, options...)
+}
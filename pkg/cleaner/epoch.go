@@ -0,0 +1,103 @@
+package cleaner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EpochUnit is the unit of Unix timestamps in a date column
+type EpochUnit int
+
+const (
+	// EpochAuto detects the unit of integers with at least 9 digits from their length; other
+	// integers are not timestamps
+	EpochAuto    EpochUnit = iota
+	EpochSeconds           // Seconds since 1970-01-01 UTC
+	EpochMillis            // Milliseconds since 1970-01-01 UTC
+	EpochMicros            // Microseconds since 1970-01-01 UTC
+	EpochNanos             // Nanoseconds since 1970-01-01 UTC
+)
+
+// epochUnitNames are the names ParseEpochUnit accepts
+var epochUnitNames = map[string]EpochUnit{
+	"auto": EpochAuto, "s": EpochSeconds, "seconds": EpochSeconds, "ms": EpochMillis, "millis": EpochMillis,
+	"us": EpochMicros, "micros": EpochMicros, "ns": EpochNanos, "nanos": EpochNanos,
+}
+
+// ParseEpochUnit returns the unit with the given name: auto, seconds (s), millis (ms), micros (us)
+// or nanos (ns)
+func ParseEpochUnit(name string) (EpochUnit, error) {
+	unit, ok := epochUnitNames[strings.ToLower(name)]
+	if !ok {
+		return EpochAuto, fmt.Errorf("unknown epoch unit: %s", name)
+	}
+	return unit, nil
+}
+
+// minEpochDigits is the number of digits from which EpochAuto takes an integer for a timestamp,
+// which is 1973 in seconds. Shorter integers are more likely years or compact dates.
+const minEpochDigits = 9
+
+// DateOption configures CleanDates
+type DateOption func(*dateOptions)
+
+type dateOptions struct {
+	epoch EpochUnit
+}
+
+// WithEpoch reads integers as Unix timestamps in the given unit before trying the date layouts.
+// Without it, integers that no layout matches are read as timestamps in the unit detected from
+// their length: up to 11 digits are seconds, up to 14 milliseconds, up to 17 microseconds and
+// longer ones nanoseconds.
+func WithEpoch(unit EpochUnit) DateOption {
+	return func(o *dateOptions) {
+		o.epoch = unit
+	}
+}
+
+// newDateOptions applies the options to the defaults
+func newDateOptions(options []DateOption) dateOptions {
+	opts := dateOptions{epoch: EpochAuto}
+	for _, option := range options {
+		option(&opts)
+	}
+	return opts
+}
+
+// parseEpoch converts an integer Unix timestamp in the given unit to a UTC time. With EpochAuto the
+// unit is detected from the number of digits.
+func parseEpoch(s string, unit EpochUnit) (time.Time, bool) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if unit == EpochAuto {
+		digits := len(strings.TrimLeft(s, "+-"))
+		switch {
+		case digits < minEpochDigits:
+			return time.Time{}, false
+		case digits <= 11:
+			unit = EpochSeconds
+		case digits <= 14:
+			unit = EpochMillis
+		case digits <= 17:
+			unit = EpochMicros
+		default:
+			unit = EpochNanos
+		}
+	}
+
+	switch unit {
+	case EpochSeconds:
+		return time.Unix(n, 0).UTC(), true
+	case EpochMillis:
+		return time.UnixMilli(n).UTC(), true
+	case EpochMicros:
+		return time.UnixMicro(n).UTC(), true
+	case EpochNanos:
+		return time.Unix(0, n).UTC(), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,64 @@
+package cleaner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCleanDates_Epoch(t *testing.T) {
+	df, _ := NewDataFrame([]string{"ts"}, [][]string{
+		{"1673741400"},
+		{"1673741400000"},
+		{"1673741400000000"},
+		{"2023-01-15"},
+		{""},
+	})
+
+	if _, err := df.CleanDates("ts", time.DateTime); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"2023-01-15 00:10:00", "2023-01-15 00:10:00", "2023-01-15 00:10:00", "2023-01-15 00:00:00", ""}
+	for i, want := range expected {
+		if got := df.Data[i][0]; got != want {
+			t.Errorf("row %d: expected %q, got %q", i, want, got)
+		}
+	}
+}
+
+func TestCleanDates_WithEpoch(t *testing.T) {
+	// Integers with less than 9 digits are not detected as timestamps
+	df, _ := NewDataFrame([]string{"ts"}, [][]string{{"1673741"}, {"86400000"}})
+
+	if _, err := df.Copy().CleanDates("ts", time.DateOnly); err == nil {
+		t.Error("expected short integers not to be read as timestamps")
+	}
+
+	if _, err := df.CleanDates("ts", time.DateOnly, WithEpoch(EpochMillis)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if df.Data[0][0] != "1970-01-01" || df.Data[1][0] != "1970-01-02" {
+		t.Errorf("expected 1970-01-01 and 1970-01-02, got %v", df.Data)
+	}
+}
+
+func TestPipelineCleanDates_WithEpoch(t *testing.T) {
+	df, _ := NewDataFrame([]string{"ts"}, [][]string{{"1673741400"}})
+
+	result, err := NewPipeline().CleanDates("ts", time.RFC3339, WithEpoch(EpochSeconds)).Run(df)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if result.Data[0][0] != "2023-01-15T00:10:00Z" {
+		t.Errorf("expected 2023-01-15T00:10:00Z, got %q", result.Data[0][0])
+	}
+}
+
+func TestParseEpochUnit(t *testing.T) {
+	if unit, err := ParseEpochUnit("MS"); err != nil || unit != EpochMillis {
+		t.Errorf("expected EpochMillis, got %v, %v", unit, err)
+	}
+	if _, err := ParseEpochUnit("minutes"); err == nil {
+		t.Error("expected an error for an unknown unit")
+	}
+}
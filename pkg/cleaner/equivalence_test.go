@@ -0,0 +1,298 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// equivalenceDataFrame creates rows with padded, mixed-case, empty, numeric and date values. When
+// invalid is set, some dates and numbers cannot be parsed.
+func equivalenceDataFrame(rows int, invalid bool) *DataFrame {
+	names := []string{"  Ali ", "ayşe", "İSMAİL", "straße", "", " Ömer\t"}
+	scores := []string{"10", "250", "-5", "", "99.5", "1e2"}
+	dates := []string{"2024-01-15", "15/01/2024", "2024/01/15", "", "2024-01-15 10:30:00", "2024-01-15T10:30:00Z"}
+
+	data := make([][]string, rows)
+	for i := range data {
+		score := scores[i%len(scores)]
+		date := dates[i%len(dates)]
+		if invalid && i%97 == 50 {
+			score = " 12 "
+		}
+		if invalid && i%89 == 40 {
+			date = "not a date"
+		}
+		data[i] = []string{fmt.Sprint(i), names[i%len(names)], score, date, fmt.Sprintf("tel: +90 (555) %03d", i%1000)}
+	}
+	df, _ := NewDataFrame([]string{"id", "name", "score", "date", "phone"}, data)
+	return df
+}
+
+// equivalenceCase runs the serial and the parallel version of an operation
+type equivalenceCase struct {
+	name     string
+	serial   func(df *DataFrame) (*DataFrame, error)
+	parallel func(df *DataFrame, options ...func(*ParallelOptions)) (*DataFrame, error)
+}
+
+var equivalenceCases = []equivalenceCase{
+	{
+		name:   "TrimColumns",
+		serial: func(df *DataFrame) (*DataFrame, error) { return df.TrimColumns(), nil },
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.TrimColumnsParallel(o...)
+		},
+	},
+	{
+		name:   "ReplaceNulls",
+		serial: func(df *DataFrame) (*DataFrame, error) { return df.ReplaceNulls("score", "0") },
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.ReplaceNullsParallel("score", "0", o...)
+		},
+	},
+	{
+		name:   "CleanDates",
+		serial: func(df *DataFrame) (*DataFrame, error) { return df.CleanDates("date", "2006-01-02") },
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.CleanDatesParallel("date", "2006-01-02", o...)
+		},
+	},
+	{
+		name:   "NormalizeCaseUpper",
+		serial: func(df *DataFrame) (*DataFrame, error) { return df.NormalizeCase("name", true) },
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.NormalizeCaseParallel("name", true, o...)
+		},
+	},
+	{
+		name:   "NormalizeCaseLower",
+		serial: func(df *DataFrame) (*DataFrame, error) { return df.NormalizeCase("name", false) },
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.NormalizeCaseParallel("name", false, o...)
+		},
+	},
+	{
+		name:   "CleanWithRegex",
+		serial: func(df *DataFrame) (*DataFrame, error) { return df.CleanWithRegex("phone", `[^0-9+]`, "") },
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.CleanWithRegexParallel("phone", `[^0-9+]`, "", o...)
+		},
+	},
+	{
+		name:   "FilterOutliers",
+		serial: func(df *DataFrame) (*DataFrame, error) { return df.FilterOutliers("score", 0, 100) },
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.FilterOutliersParallel("score", 0, 100, o...)
+		},
+	},
+	{
+		name: "ApplyColumn",
+		serial: func(df *DataFrame) (*DataFrame, error) {
+			return df.ApplyColumn("date", equivalenceTransform)
+		},
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.ApplyColumnParallel("date", equivalenceTransform, o...)
+		},
+	},
+	{
+		name: "MapValues",
+		serial: func(df *DataFrame) (*DataFrame, error) {
+			return df.MapValues("name", equivalenceMapping, false)
+		},
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.MapValuesParallel("name", equivalenceMapping, false, o...)
+		},
+	},
+	{
+		name: "FilterRows",
+		serial: func(df *DataFrame) (*DataFrame, error) {
+			return df.FilterRows(equivalencePredicate)
+		},
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.FilterRowsParallel(equivalencePredicate, o...)
+		},
+	},
+	{
+		name:   "DropDuplicatesFirst",
+		serial: func(df *DataFrame) (*DataFrame, error) { return df.DropDuplicatesKeep(KeepFirst, "name", "score") },
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.DropDuplicatesParallel([]string{"name", "score"}, KeepFirst, o...)
+		},
+	},
+	{
+		name:   "DropDuplicatesLast",
+		serial: func(df *DataFrame) (*DataFrame, error) { return df.DropDuplicatesKeep(KeepLast, "name") },
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.DropDuplicatesParallel([]string{"name"}, KeepLast, o...)
+		},
+	},
+	{
+		name: "BatchProcess",
+		serial: func(df *DataFrame) (*DataFrame, error) {
+			for _, process := range equivalenceProcessors {
+				var err error
+				if df, err = process(df); err != nil {
+					return nil, err
+				}
+			}
+			return df, nil
+		},
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.BatchProcessParallel(equivalenceProcessors, o...)
+		},
+	},
+	{
+		// The branches are compared as one DataFrame of their rows in processor order
+		name: "BatchBranches",
+		serial: func(df *DataFrame) (*DataFrame, error) {
+			branches := make([]*DataFrame, len(equivalenceProcessors))
+			for i, process := range equivalenceProcessors {
+				var err error
+				if branches[i], err = process(df.DeepCopy()); err != nil {
+					return nil, fmt.Errorf("processor %d: %w", i, err)
+				}
+			}
+			return Concat(branches...)
+		},
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			branches, err := df.BatchBranchesParallel(equivalenceProcessors, o...)
+			if err != nil {
+				return nil, err
+			}
+			return Concat(branches...)
+		},
+	},
+	{
+		name:   "MissingColumn",
+		serial: func(df *DataFrame) (*DataFrame, error) { return df.NormalizeCase("missing", true) },
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			return df.NormalizeCaseParallel("missing", true, o...)
+		},
+	},
+	{
+		name: "Pipeline",
+		serial: func(df *DataFrame) (*DataFrame, error) {
+			df.TrimColumns()
+			if _, err := df.FilterOutliers("score", 0, 100); err != nil {
+				return nil, err
+			}
+			if _, err := df.CleanDates("date", "02.01.2006"); err != nil {
+				return nil, err
+			}
+			return df.NormalizeCase("name", true)
+		},
+		parallel: func(df *DataFrame, o ...func(*ParallelOptions)) (*DataFrame, error) {
+			result, err := NewPipeline().
+				Trim().
+				FilterOutliers("score", 0, 100).
+				CleanDates("date", "02.01.2006").
+				NormalizeCase("name", true).
+				Run(df, o...)
+			if stepErr, ok := err.(*StepError); ok {
+				return nil, stepErr.Err
+			}
+			return result, err
+		},
+	},
+}
+
+// equivalenceTransform upper-cases a value and fails on an invalid date
+func equivalenceTransform(value string) (string, error) {
+	if value == "not a date" {
+		return "", errors.New("invalid date")
+	}
+	return strings.ToUpper(value), nil
+}
+
+// equivalenceMapping maps some of the names; the others are emptied
+var equivalenceMapping = map[string]string{"ayşe": "Ayşe", "straße": "Strasse", "": "unknown"}
+
+// equivalencePredicate keeps the rows with a score and an even id
+func equivalencePredicate(row map[string]string) bool {
+	id, _ := strconv.Atoi(row["id"])
+	return row["score"] != "" && id%2 == 0
+}
+
+// equivalenceProcessors are the processors of the batch cases
+var equivalenceProcessors = []func(*DataFrame) (*DataFrame, error){
+	func(df *DataFrame) (*DataFrame, error) { return df.TrimColumns(), nil },
+	func(df *DataFrame) (*DataFrame, error) { return df.FilterOutliers("score", 0, 100) },
+	func(df *DataFrame) (*DataFrame, error) { return df.NormalizeCase("name", true) },
+}
+
+// sameResult compares two results of an operation, including the error
+func sameResult(t *testing.T, label string, want *DataFrame, wantErr error, got *DataFrame, gotErr error) {
+	t.Helper()
+
+	if fmt.Sprint(wantErr) != fmt.Sprint(gotErr) {
+		t.Errorf("%s: errors differ:\nwant %v\ngot  %v", label, wantErr, gotErr)
+		return
+	}
+	if want == nil || got == nil {
+		if want != got {
+			t.Errorf("%s: one of the results is nil", label)
+		}
+		return
+	}
+	if !reflect.DeepEqual(want.Headers, got.Headers) || !reflect.DeepEqual(want.Types, got.Types) {
+		t.Errorf("%s: headers or types differ", label)
+	}
+	if len(want.Data) != len(got.Data) {
+		t.Errorf("%s: row counts differ: %d != %d", label, len(want.Data), len(got.Data))
+		return
+	}
+	for i := range want.Data {
+		if !reflect.DeepEqual(want.Data[i], got.Data[i]) {
+			t.Errorf("%s: row %d differs: %q != %q", label, i, want.Data[i], got.Data[i])
+			return
+		}
+	}
+}
+
+func TestDeterministicParallelMatchesSerial(t *testing.T) {
+	for _, tc := range equivalenceCases {
+		for _, rows := range []int{0, 1, 7, 1000, 5000} {
+			for _, invalid := range []bool{false, true} {
+				want, wantErr := tc.serial(equivalenceDataFrame(rows, invalid))
+
+				for _, workers := range []int{1, 2, 3, 8, 64} {
+					label := fmt.Sprintf("%s rows=%d invalid=%v workers=%d", tc.name, rows, invalid, workers)
+					got, err := tc.parallel(equivalenceDataFrame(rows, invalid), WithMaxWorkers(workers), WithDeterministic(true))
+					sameResult(t, label, want, wantErr, got, err)
+				}
+			}
+		}
+	}
+}
+
+func TestParallelResultIndependentOfWorkers(t *testing.T) {
+	for _, tc := range equivalenceCases {
+		want, wantErr := tc.parallel(equivalenceDataFrame(5000, true), WithMaxWorkers(1))
+
+		for _, workers := range []int{2, 5, 16} {
+			got, err := tc.parallel(equivalenceDataFrame(5000, true), WithMaxWorkers(workers))
+			sameResult(t, fmt.Sprintf("%s workers=%d", tc.name, workers), want, wantErr, got, err)
+		}
+	}
+}
+
+func TestEquivalenceCasesCoverParallelMethods(t *testing.T) {
+	dfType := reflect.TypeOf(&DataFrame{})
+	for i := 0; i < dfType.NumMethod(); i++ {
+		name, found := strings.CutSuffix(dfType.Method(i).Name, "Parallel")
+		if !found {
+			continue
+		}
+		covered := false
+		for _, tc := range equivalenceCases {
+			covered = covered || strings.HasPrefix(tc.name, name)
+		}
+		if !covered {
+			t.Errorf("%sParallel has no equivalence case", name)
+		}
+	}
+}
@@ -14,7 +14,10 @@ func ReadExcel(filePath string, options ...formats.ExcelOption) (*DataFrame, err
 	return NewDataFrame(headers, data)
 }
 
-// WriteExcel, DataFrame is written to Excel file
+// WriteExcel, DataFrame is written to Excel file. The columns of WithStringColumns are written as text.
 func (df *DataFrame) WriteExcel(filePath string, options ...formats.ExcelOption) error {
+	if len(df.stringColumns) > 0 {
+		options = append([]formats.ExcelOption{formats.WithExcelStringColumns(df.stringColumns)}, options...)
+	}
 	return formats.WriteExcel(df, filePath, options...)
 }
@@ -2,6 +2,7 @@ package cleaner
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/mstgnz/cleango/pkg/formats"
@@ -80,3 +81,19 @@ func TestWriteExcelWithOptions(t *testing.T) {
 		t.Errorf("Excel file not created")
 	}
 }
+
+func TestWriteExcel_StringColumns(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "age"}, [][]string{{"007", "30"}})
+	tempFile := filepath.Join(t.TempDir(), "ids.xlsx")
+
+	if err := df.WithStringColumns([]string{"id"}).WriteExcel(tempFile); err != nil {
+		t.Fatalf("WriteExcel error: %v", err)
+	}
+	result, err := ReadExcel(tempFile)
+	if err != nil {
+		t.Fatalf("ReadExcel error: %v", err)
+	}
+	if result.Data[0][0] != "007" {
+		t.Errorf("expected 007, got %q", result.Data[0][0])
+	}
+}
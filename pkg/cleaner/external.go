@@ -0,0 +1,780 @@
+package cleaner
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SortKey describes one key of a multi-column sort
+type SortKey struct {
+	Column     string
+	Descending bool
+	Numeric    bool // Compare as numbers; non-numeric values sort after numbers
+}
+
+// compareValues compares two cell values lexicographically or numerically
+func compareValues(a, b string, numeric bool) int {
+	return compareValuesWith(a, b, numeric, strings.Compare)
+}
+
+// compareValuesWith compares two cell values like compareValues, comparing strings with compare
+func compareValuesWith(a, b string, numeric bool, compare func(a, b string) int) int {
+	if !numeric {
+		return compare(a, b)
+	}
+	x, errX := strconv.ParseFloat(a, 64)
+	y, errY := strconv.ParseFloat(b, 64)
+	switch {
+	case errX == nil && errY == nil:
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+		return 0
+	case errX == nil:
+		return -1
+	case errY == nil:
+		return 1
+	default:
+		return compare(a, b)
+	}
+}
+
+// rowComparator returns a function comparing rows by the keys at the given column indices,
+// comparing strings with compare
+func rowComparator(keys []SortKey, indices []int, compare func(a, b string) int) func(a, b []string) int {
+	return func(a, b []string) int {
+		for i, key := range keys {
+			c := compareValuesWith(a[indices[i]], b[indices[i]], key.Numeric, compare)
+			if c != 0 {
+				if key.Descending {
+					return -c
+				}
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+// sortStep holds all rows of the stream and emits them sorted after the last chunk
+type sortStep struct {
+	keys    []SortKey
+	opts    *StreamOptions
+	headers []string
+	compare func(a, b []string) int
+	rows    [][]string
+	size    int64
+	dir     spillDir
+	runs    []string
+}
+
+// SortStep sorts the whole stream by the keys. The sort is stable.
+// With WithMemoryLimit, sorted runs are spilled to disk and merged after the last chunk.
+func SortStep(keys ...SortKey) StreamStep {
+	return &sortStep{keys: keys, opts: defaultStreamOptions()}
+}
+
+func (s *sortStep) configure(opts *StreamOptions) {
+	s.opts = opts
+	s.dir.parent = opts.TempDir
+}
+
+// ProcessChunk holds the rows of the chunk back and returns it empty
+func (s *sortStep) ProcessChunk(chunk *DataFrame) (*DataFrame, error) {
+	if s.compare == nil {
+		if len(s.keys) == 0 {
+			return nil, errors.New("sort requires at least one key")
+		}
+		columns := make([]string, len(s.keys))
+		for i, key := range s.keys {
+			columns[i] = key.Column
+		}
+		indices, err := chunk.columnIndices(columns)
+		if err != nil {
+			return nil, err
+		}
+		s.compare = rowComparator(s.keys, indices, strings.Compare)
+		s.headers = append([]string(nil), chunk.Headers...)
+	}
+
+	for _, row := range chunk.Data {
+		s.rows = append(s.rows, row)
+		s.size += rowSize(row)
+		if s.opts.MemoryLimit > 0 && s.size >= s.opts.MemoryLimit {
+			if err := s.spill(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	chunk.Data = nil
+	chunk.cow = nil
+	return chunk, nil
+}
+
+// spill writes the held rows to disk as a sorted run
+func (s *sortStep) spill() error {
+	slices.SortStableFunc(s.rows, s.compare)
+
+	file, err := s.dir.create("sort")
+	if err != nil {
+		return err
+	}
+	w := newRunWriter(file)
+	for _, row := range s.rows {
+		if err := w.Write(row); err != nil {
+			w.Close()
+			return fmt.Errorf("failed to write spill file: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	s.runs = append(s.runs, file.Name())
+	s.rows = nil
+	s.size = 0
+	return nil
+}
+
+// Flush emits the sorted rows, merging spilled runs if there are any
+func (s *sortStep) Flush(emit func(*DataFrame) error) error {
+	if s.compare == nil {
+		return nil
+	}
+	batch := newRowBatcher(s.headers, s.opts.ChunkSize, emit)
+
+	if len(s.runs) == 0 {
+		slices.SortStableFunc(s.rows, s.compare)
+		for _, row := range s.rows {
+			if err := batch.Add(row); err != nil {
+				return err
+			}
+		}
+		s.rows = nil
+		return batch.Flush()
+	}
+
+	if len(s.rows) > 0 {
+		if err := s.spill(); err != nil {
+			return err
+		}
+	}
+	if err := s.merge(batch); err != nil {
+		return err
+	}
+	return batch.Flush()
+}
+
+// merge performs a k-way merge of the sorted runs
+func (s *sortStep) merge(batch *rowBatcher) error {
+	readers := make([]*runReader, 0, len(s.runs))
+	defer func() {
+		for _, r := range readers {
+			r.Close()
+		}
+	}()
+
+	h := &mergeHeap{compare: s.compare}
+	for i, path := range s.runs {
+		r, err := openRun(path)
+		if err != nil {
+			return err
+		}
+		readers = append(readers, r)
+		row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read spill file: %w", err)
+		}
+		h.items = append(h.items, mergeItem{row: row, run: i})
+	}
+	heap.Init(h)
+
+	for h.Len() > 0 {
+		item := h.items[0]
+		if err := batch.Add(item.row); err != nil {
+			return err
+		}
+		row, err := readers[item.run].Next()
+		if errors.Is(err, io.EOF) {
+			heap.Pop(h)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read spill file: %w", err)
+		}
+		h.items[0].row = row
+		heap.Fix(h, 0)
+	}
+	return nil
+}
+
+// Close removes the spill files
+func (s *sortStep) Close() error {
+	return s.dir.Close()
+}
+
+// mergeItem is the current row of a sorted run
+type mergeItem struct {
+	row []string
+	run int
+}
+
+// mergeHeap orders runs by their current row. Ties go to the earlier run, which keeps the sort stable.
+type mergeHeap struct {
+	items   []mergeItem
+	compare func(a, b []string) int
+}
+
+func (h *mergeHeap) Len() int { return len(h.items) }
+
+func (h *mergeHeap) Less(i, j int) bool {
+	if c := h.compare(h.items[i].row, h.items[j].row); c != 0 {
+		return c < 0
+	}
+	return h.items[i].run < h.items[j].run
+}
+
+func (h *mergeHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *mergeHeap) Push(x any) { h.items = append(h.items, x.(mergeItem)) }
+
+func (h *mergeHeap) Pop() any {
+	item := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return item
+}
+
+// dedupStep drops rows whose key was already seen in this or an earlier chunk
+type dedupStep struct {
+	columns    []string
+	opts       *StreamOptions
+	headers    []string
+	seen       map[string]struct{}
+	size       int64
+	dir        spillDir
+	partitions *spillPartitions
+
+	// With WithDedupWindow, the keys of the last rows in a ring and how often each occurs in it
+	window    []string
+	windowPos int
+	counts    map[string]int
+	// With WithApproxDedup, the Bloom filter of the seen keys
+	bloom *bloomFilter
+}
+
+// DedupStep removes duplicate rows across the whole stream, keeping the first occurrence.
+// The key is built from the given columns, or from all columns when none are given.
+// Memory grows with the number of distinct keys, not with the number of rows.
+//
+// With WithMemoryLimit, once the seen keys reach the limit, rows with new keys are hash-partitioned
+// to disk and deduplicated partition by partition after the last chunk. Those rows are emitted at
+// the end of the stream, grouped by partition.
+//
+// For unbounded streams, WithDedupWindow bounds the memory by only looking back a number of rows,
+// and WithApproxDedup by remembering the keys in a Bloom filter. Both emit every row with its chunk.
+func DedupStep(columns ...string) StreamStep {
+	return &dedupStep{columns: columns, opts: defaultStreamOptions(), seen: make(map[string]struct{})}
+}
+
+func (d *dedupStep) configure(opts *StreamOptions) {
+	d.opts = opts
+	d.dir.parent = opts.TempDir
+}
+
+// ProcessChunk keeps rows with unseen keys
+func (d *dedupStep) ProcessChunk(chunk *DataFrame) (*DataFrame, error) {
+	indices, err := chunk.columnIndices(d.columns)
+	if err != nil {
+		return nil, err
+	}
+	if d.headers == nil {
+		d.headers = append([]string(nil), chunk.Headers...)
+	}
+
+	keep := make([]bool, len(chunk.Data))
+	switch {
+	case d.opts.DedupWindow > 0:
+		d.processWindow(chunk, indices, keep)
+		chunk.retainRows(keep)
+		return chunk, nil
+	case d.opts.ApproxDedupKeys > 0:
+		if d.bloom == nil {
+			d.bloom = newBloomFilter(d.opts.ApproxDedupKeys, d.opts.ApproxDedupRate)
+		}
+		for i, row := range chunk.Data {
+			keep[i] = !d.bloom.Add(rowKey(row, indices))
+		}
+		chunk.retainRows(keep)
+		return chunk, nil
+	}
+
+	for i, row := range chunk.Data {
+		key := rowKey(row, indices)
+		if _, ok := d.seen[key]; ok {
+			continue
+		}
+		if d.partitions != nil {
+			if err := d.partitions.Write(key, row); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		d.seen[key] = struct{}{}
+		keep[i] = true
+		d.size += int64(len(key)) + 48
+		if d.opts.MemoryLimit > 0 && d.size >= d.opts.MemoryLimit {
+			d.partitions = newSpillPartitions(&d.dir, "dedup")
+		}
+	}
+	chunk.retainRows(keep)
+	return chunk, nil
+}
+
+// processWindow keeps the rows whose key is not among the keys of the previous DedupWindow rows
+func (d *dedupStep) processWindow(chunk *DataFrame, indices []int, keep []bool) {
+	if d.window == nil {
+		d.window = make([]string, 0, d.opts.DedupWindow)
+		d.counts = make(map[string]int)
+	}
+	for i, row := range chunk.Data {
+		key := rowKey(row, indices)
+		keep[i] = d.counts[key] == 0
+
+		// Slide the window: the key of this row replaces the oldest one
+		if len(d.window) < d.opts.DedupWindow {
+			d.window = append(d.window, key)
+		} else {
+			oldest := d.window[d.windowPos]
+			if d.counts[oldest]--; d.counts[oldest] == 0 {
+				delete(d.counts, oldest)
+			}
+			d.window[d.windowPos] = key
+			d.windowPos = (d.windowPos + 1) % d.opts.DedupWindow
+		}
+		d.counts[key]++
+	}
+}
+
+// Flush deduplicates and emits the spilled partitions
+func (d *dedupStep) Flush(emit func(*DataFrame) error) error {
+	if d.partitions == nil {
+		return nil
+	}
+	paths, err := d.partitions.Finish()
+	if err != nil {
+		return err
+	}
+
+	indices, err := (&DataFrame{Headers: d.headers}).columnIndices(d.columns)
+	if err != nil {
+		return err
+	}
+	batch := newRowBatcher(d.headers, d.opts.ChunkSize, emit)
+	for _, path := range paths {
+		seen := make(map[string]struct{})
+		err := readRun(path, func(row []string) error {
+			key := rowKey(row, indices)
+			if _, ok := seen[key]; ok {
+				return nil
+			}
+			seen[key] = struct{}{}
+			return batch.Add(row)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return batch.Flush()
+}
+
+// Close removes the spill files
+func (d *dedupStep) Close() error {
+	return d.dir.Close()
+}
+
+// readRun calls fn for every row of a spill file
+func readRun(path string, fn func(row []string) error) error {
+	r, err := openRun(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for {
+		row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read spill file: %w", err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+}
+
+// aggregationKind is the reduction applied by an Aggregation
+type aggregationKind int
+
+const (
+	aggCount aggregationKind = iota
+	aggSum
+	aggMean
+	aggMin
+	aggMax
+)
+
+// Aggregation reduces the values of a group to one output column
+type Aggregation struct {
+	Name   string // Output column name
+	Column string // Input column (empty for Count)
+	kind   aggregationKind
+}
+
+// Count counts the rows of each group
+func Count() Aggregation {
+	return Aggregation{Name: "count", kind: aggCount}
+}
+
+// Sum sums the numeric values of the column
+func Sum(column string) Aggregation {
+	return Aggregation{Name: column + "_sum", Column: column, kind: aggSum}
+}
+
+// Mean averages the numeric values of the column
+func Mean(column string) Aggregation {
+	return Aggregation{Name: column + "_mean", Column: column, kind: aggMean}
+}
+
+// Min returns the smallest numeric value of the column
+func Min(column string) Aggregation {
+	return Aggregation{Name: column + "_min", Column: column, kind: aggMin}
+}
+
+// Max returns the largest numeric value of the column
+func Max(column string) Aggregation {
+	return Aggregation{Name: column + "_max", Column: column, kind: aggMax}
+}
+
+// As returns the aggregation with a different output column name
+func (a Aggregation) As(name string) Aggregation {
+	a.Name = name
+	return a
+}
+
+// aggregationState accumulates one aggregation of one group. Empty values are ignored.
+type aggregationState struct {
+	count    int
+	sum      float64
+	min, max float64
+
+	// Exact values in decimal mode
+	dsum, dmin, dmax *big.Rat
+}
+
+// add adds a row value to the state
+func (st *aggregationState) add(a Aggregation, value string, decimal bool) error {
+	if a.kind == aggCount {
+		st.count++
+		return nil
+	}
+	if value == "" {
+		return nil
+	}
+	if decimal {
+		return st.addDecimal(a, value)
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("column %s: value is not numeric: %s", a.Column, value)
+	}
+	if st.count == 0 || v < st.min {
+		st.min = v
+	}
+	if st.count == 0 || v > st.max {
+		st.max = v
+	}
+	st.count++
+	st.sum += v
+	return nil
+}
+
+// addDecimal adds a row value to the state as an exact decimal
+func (st *aggregationState) addDecimal(a Aggregation, value string) error {
+	v, err := parseDecimal(value)
+	if err != nil {
+		return fmt.Errorf("column %s: value is not numeric: %s", a.Column, value)
+	}
+	if st.count == 0 {
+		st.dsum, st.dmin, st.dmax = new(big.Rat), v, v
+	}
+	if v.Cmp(st.dmin) < 0 {
+		st.dmin = v
+	}
+	if v.Cmp(st.dmax) > 0 {
+		st.dmax = v
+	}
+	st.count++
+	st.dsum.Add(st.dsum, v)
+	return nil
+}
+
+// result formats the aggregated value
+func (st *aggregationState) result(a Aggregation, decimal bool) string {
+	if a.kind == aggCount {
+		return strconv.Itoa(st.count)
+	}
+	if st.count == 0 && a.kind != aggSum {
+		return ""
+	}
+	if decimal {
+		return st.decimalResult(a)
+	}
+	var v float64
+	switch a.kind {
+	case aggSum:
+		v = st.sum
+	case aggMean:
+		v = st.sum / float64(st.count)
+	case aggMin:
+		v = st.min
+	case aggMax:
+		v = st.max
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// decimalResult formats the aggregated value of a state with at least one value or of a sum
+func (st *aggregationState) decimalResult(a Aggregation) string {
+	if st.count == 0 {
+		return "0"
+	}
+	switch a.kind {
+	case aggMean:
+		return formatDecimal(new(big.Rat).Quo(st.dsum, big.NewRat(int64(st.count), 1)))
+	case aggMin:
+		return formatDecimal(st.dmin)
+	case aggMax:
+		return formatDecimal(st.dmax)
+	}
+	return formatDecimal(st.dsum)
+}
+
+// group is the key and aggregation states of one group
+type group struct {
+	key    []string
+	states []aggregationState
+}
+
+// groupTable aggregates rows into groups, remembering the order in which groups first appear
+type groupTable struct {
+	aggs       []Aggregation
+	keyIndices []int
+	aggIndices []int
+	groups     map[string]*group
+	order      []*group
+	decimal    bool // Aggregate exact decimals, see Defaults.Decimal
+}
+
+func newGroupTable(aggs []Aggregation, keyIndices, aggIndices []int, decimal bool) *groupTable {
+	return &groupTable{aggs: aggs, keyIndices: keyIndices, aggIndices: aggIndices, groups: make(map[string]*group), decimal: decimal}
+}
+
+// newGroupTable resolves the key and aggregation columns of the DataFrame and returns an empty
+// table with the output headers: the keys followed by the aggregation names
+func (df *DataFrame) newGroupTable(keys []string, aggs []Aggregation) (*groupTable, []string, error) {
+	if len(keys) == 0 {
+		return nil, nil, errors.New("group by requires at least one key column")
+	}
+	keyIndices, err := df.columnIndices(keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	aggIndices := make([]int, len(aggs))
+	headers := append([]string(nil), keys...)
+	for i, a := range aggs {
+		aggIndices[i] = -1
+		if a.kind != aggCount {
+			if aggIndices[i] = df.getColumnIndex(a.Column); aggIndices[i] == -1 {
+				return nil, nil, fmt.Errorf("%w: %s", ErrColumnNotFound, a.Column)
+			}
+		}
+		headers = append(headers, a.Name)
+	}
+	return newGroupTable(aggs, keyIndices, aggIndices, defaults.Load().Decimal), headers, nil
+}
+
+// add aggregates the row into the group, creating the group if create is true.
+// It reports whether the row was aggregated.
+func (t *groupTable) add(key string, row []string, create bool) (bool, error) {
+	g, ok := t.groups[key]
+	if !ok {
+		if !create {
+			return false, nil
+		}
+		g = &group{key: make([]string, len(t.keyIndices)), states: make([]aggregationState, len(t.aggs))}
+		for i, idx := range t.keyIndices {
+			g.key[i] = row[idx]
+		}
+		t.groups[key] = g
+		t.order = append(t.order, g)
+	}
+
+	for i, a := range t.aggs {
+		value := ""
+		if t.aggIndices[i] >= 0 {
+			value = row[t.aggIndices[i]]
+		}
+		if err := g.states[i].add(a, value, t.decimal); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// emit passes one row per group to the batcher
+func (t *groupTable) emit(batch *rowBatcher) error {
+	for _, g := range t.order {
+		if err := batch.Add(t.row(g)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// row returns the output row of the group: its key followed by the aggregated values
+func (t *groupTable) row(g *group) []string {
+	row := make([]string, 0, len(g.key)+len(t.aggs))
+	row = append(row, g.key...)
+	for i, a := range t.aggs {
+		row = append(row, g.states[i].result(a, t.decimal))
+	}
+	return row
+}
+
+// groupByStep aggregates the stream by key columns and emits one row per group after the last chunk
+type groupByStep struct {
+	keys       []string
+	aggs       []Aggregation
+	opts       *StreamOptions
+	headers    []string
+	table      *groupTable
+	size       int64
+	dir        spillDir
+	partitions *spillPartitions
+}
+
+// GroupByStep groups the whole stream by the key columns. After the last chunk it emits one row per
+// group with the key columns followed by one column per aggregation, in order of first appearance.
+//
+// With WithMemoryLimit, once the groups reach the limit, rows of new groups are hash-partitioned to disk
+// and aggregated partition by partition; those groups are emitted last.
+func GroupByStep(keys []string, aggs ...Aggregation) StreamStep {
+	return &groupByStep{keys: keys, aggs: aggs, opts: defaultStreamOptions()}
+}
+
+func (g *groupByStep) configure(opts *StreamOptions) {
+	g.opts = opts
+	g.dir.parent = opts.TempDir
+}
+
+// ProcessChunk aggregates the rows of the chunk and returns an empty chunk with the output headers
+func (g *groupByStep) ProcessChunk(chunk *DataFrame) (*DataFrame, error) {
+	if g.table == nil {
+		if err := g.init(chunk); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, row := range chunk.Data {
+		key := rowKey(row, g.table.keyIndices)
+		groups := len(g.table.order)
+		added, err := g.table.add(key, row, g.partitions == nil)
+		if err != nil {
+			return nil, err
+		}
+		if !added {
+			if err := g.partitions.Write(key, row); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(g.table.order) > groups {
+			g.size += int64(2*len(key)) + g.groupSize()
+			if g.opts.MemoryLimit > 0 && g.size >= g.opts.MemoryLimit {
+				g.partitions = newSpillPartitions(&g.dir, "group")
+			}
+		}
+	}
+
+	return NewDataFrame(append([]string(nil), g.headers...), nil)
+}
+
+// init resolves the columns and output headers from the first chunk
+func (g *groupByStep) init(chunk *DataFrame) error {
+	table, headers, err := chunk.newGroupTable(g.keys, g.aggs)
+	if err != nil {
+		return err
+	}
+	g.headers = headers
+	g.table = table
+	return nil
+}
+
+// groupSize estimates the memory held by one group besides its key bytes
+func (g *groupByStep) groupSize() int64 {
+	return int64(24+16*len(g.keys)) + int64(len(g.aggs))*32 + 64
+}
+
+// Flush emits the groups held in memory, then aggregates and emits the spilled partitions
+func (g *groupByStep) Flush(emit func(*DataFrame) error) error {
+	if g.table == nil {
+		return nil
+	}
+	batch := newRowBatcher(g.headers, g.opts.ChunkSize, emit)
+	if err := g.table.emit(batch); err != nil {
+		return err
+	}
+
+	if g.partitions != nil {
+		paths, err := g.partitions.Finish()
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			table := newGroupTable(g.aggs, g.table.keyIndices, g.table.aggIndices, g.table.decimal)
+			err := readRun(path, func(row []string) error {
+				_, err := table.add(rowKey(row, table.keyIndices), row, true)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if err := table.emit(batch); err != nil {
+				return err
+			}
+		}
+	}
+	return batch.Flush()
+}
+
+// Close removes the spill files
+func (g *groupByStep) Close() error {
+	return g.dir.Close()
+}
@@ -0,0 +1,251 @@
+package cleaner
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/formats"
+)
+
+// runStream runs the steps over the rows and returns the collected output
+func runStream(t *testing.T, headers []string, rows [][]string, options []StreamOption, steps ...StreamStep) *collectWriter {
+	t.Helper()
+	sink := &collectWriter{}
+	if _, err := NewStream(formats.NewRawRowReader(headers, rows), options...).Then(steps...).Run(sink); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	return sink
+}
+
+// assertNoSpillFiles fails if spill directories were left behind in dir
+func assertNoSpillFiles(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read temp dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("spill files were not removed: %v", entries)
+	}
+}
+
+func TestSortStep(t *testing.T) {
+	headers := []string{"name", "age"}
+	rows := [][]string{{"c", "10"}, {"a", "9"}, {"b", "10"}, {"d", "x"}, {"e", "2"}}
+
+	sink := runStream(t, headers, rows, []StreamOption{WithChunkSize(2)},
+		SortStep(SortKey{Column: "age", Numeric: true, Descending: true}, SortKey{Column: "name"}))
+
+	expected := [][]string{{"d", "x"}, {"b", "10"}, {"c", "10"}, {"a", "9"}, {"e", "2"}}
+	if !reflect.DeepEqual(sink.rows, expected) {
+		t.Errorf("rows = %v, expected = %v", sink.rows, expected)
+	}
+	if !reflect.DeepEqual(sink.headers, headers) {
+		t.Errorf("headers = %v, expected = %v", sink.headers, headers)
+	}
+}
+
+func TestSortStep_Spill(t *testing.T) {
+	var rows [][]string
+	for i := 0; i < 500; i++ {
+		rows = append(rows, []string{fmt.Sprintf("%03d", (i*37)%500), fmt.Sprint(i % 3)})
+	}
+	tempDir := t.TempDir()
+
+	step := SortStep(SortKey{Column: "group"}, SortKey{Column: "id", Numeric: true})
+	sink := runStream(t, []string{"id", "group"}, rows,
+		[]StreamOption{WithChunkSize(64), WithMemoryLimit(2000), WithTempDir(tempDir)}, step)
+
+	if runs := len(step.(*sortStep).runs); runs < 2 {
+		t.Fatalf("expected several spilled runs, got %d", runs)
+	}
+	if len(sink.rows) != 500 {
+		t.Fatalf("expected 500 rows, got %d", len(sink.rows))
+	}
+	sorted := slices.IsSortedFunc(sink.rows, func(a, b []string) int {
+		if c := compareValues(a[1], b[1], false); c != 0 {
+			return c
+		}
+		return compareValues(a[0], b[0], true)
+	})
+	if !sorted {
+		t.Error("output is not sorted")
+	}
+	assertNoSpillFiles(t, tempDir)
+}
+
+func TestSortStep_MissingColumn(t *testing.T) {
+	sink := &collectWriter{}
+	source := formats.NewRawRowReader([]string{"name"}, [][]string{{"a"}})
+	if _, err := NewStream(source).Then(SortStep(SortKey{Column: "age"})).Run(sink); err == nil {
+		t.Error("expected error for missing sort column")
+	}
+}
+
+func TestDedupStep_Spill(t *testing.T) {
+	var rows [][]string
+	for i := 0; i < 300; i++ {
+		rows = append(rows, []string{fmt.Sprintf("user%d@example.com", i%120), fmt.Sprint(i)})
+	}
+	tempDir := t.TempDir()
+
+	step := DedupStep("email")
+	sink := runStream(t, []string{"email", "seq"}, rows,
+		[]StreamOption{WithChunkSize(50), WithMemoryLimit(1500), WithTempDir(tempDir)}, step)
+
+	if step.(*dedupStep).partitions == nil {
+		t.Fatal("expected dedup to spill")
+	}
+	if len(sink.rows) != 120 {
+		t.Fatalf("expected 120 distinct rows, got %d", len(sink.rows))
+	}
+	seen := make(map[string]bool)
+	for _, row := range sink.rows {
+		if seen[row[0]] {
+			t.Errorf("duplicate key in output: %s", row[0])
+		}
+		seen[row[0]] = true
+		// The first occurrence of user<n> is row n
+		if fmt.Sprintf("user%s@example.com", row[1]) != row[0] {
+			t.Errorf("expected first occurrence of %s, got seq %s", row[0], row[1])
+		}
+	}
+	assertNoSpillFiles(t, tempDir)
+}
+
+func TestDedupStep_Window(t *testing.T) {
+	rows := [][]string{{"a", "1"}, {"b", "2"}, {"a", "3"}, {"c", "4"}, {"d", "5"}, {"a", "6"}, {"d", "7"}}
+
+	step := DedupStep("key")
+	sink := runStream(t, []string{"key", "seq"}, rows, []StreamOption{WithChunkSize(3), WithDedupWindow(2)}, step)
+
+	// a at 3 is within 2 rows of a at 1, a at 6 is not; d at 7 follows d at 5
+	expected := [][]string{{"a", "1"}, {"b", "2"}, {"c", "4"}, {"d", "5"}, {"a", "6"}}
+	if !reflect.DeepEqual(sink.rows, expected) {
+		t.Errorf("rows = %v, expected = %v", sink.rows, expected)
+	}
+	if d := step.(*dedupStep); len(d.window) != 2 || len(d.counts) > 2 || len(d.seen) != 0 {
+		t.Errorf("expected at most 2 keys in memory, got window %v and counts %v", d.window, d.counts)
+	}
+}
+
+func TestDedupStep_Approx(t *testing.T) {
+	var rows [][]string
+	for i := 0; i < 3000; i++ {
+		rows = append(rows, []string{fmt.Sprintf("user%d@example.com", i%1000), fmt.Sprint(i)})
+	}
+
+	step := DedupStep("email")
+	sink := runStream(t, []string{"email", "seq"}, rows,
+		[]StreamOption{WithChunkSize(256), WithApproxDedup(1000, 0.001)}, step)
+
+	// A false positive drops a new key, so a few keys may be missing but none is repeated
+	if len(sink.rows) > 1000 || len(sink.rows) < 990 {
+		t.Fatalf("expected about 1000 distinct rows, got %d", len(sink.rows))
+	}
+	seen := make(map[string]bool)
+	for _, row := range sink.rows {
+		if seen[row[0]] {
+			t.Errorf("duplicate key in output: %s", row[0])
+		}
+		seen[row[0]] = true
+	}
+	if d := step.(*dedupStep); d.bloom == nil || len(d.seen) != 0 {
+		t.Error("expected keys in the Bloom filter only")
+	}
+}
+
+func TestBloomFilter(t *testing.T) {
+	f := newBloomFilter(100, 0.01)
+	for i := 0; i < 100; i++ {
+		if f.Add(fmt.Sprint("in", i)) && i == 0 {
+			t.Error("expected the first key to be new")
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if !f.Add(fmt.Sprint("in", i)) {
+			t.Errorf("expected key %d to be present", i)
+		}
+	}
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		// Probe a copy, so the filter does not fill up with the probed keys
+		probe := *f
+		probe.bits = slices.Clone(f.bits)
+		if probe.Add(fmt.Sprint("out", i)) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 300 {
+		t.Errorf("too many false positives: %d", falsePositives)
+	}
+}
+
+func TestGroupByStep(t *testing.T) {
+	headers := []string{"country", "amount", "age"}
+	rows := [][]string{
+		{"TR", "10", "30"},
+		{"DE", "5", ""},
+		{"TR", "2.5", "40"},
+		{"DE", "", "20"},
+		{"US", "1", "50"},
+	}
+
+	sink := runStream(t, headers, rows, []StreamOption{WithChunkSize(2)},
+		GroupByStep([]string{"country"}, Sum("amount"), Count(), Mean("age").As("avg_age"), Min("amount"), Max("age")))
+
+	expectedHeaders := []string{"country", "amount_sum", "count", "avg_age", "amount_min", "age_max"}
+	if !reflect.DeepEqual(sink.headers, expectedHeaders) {
+		t.Errorf("headers = %v, expected = %v", sink.headers, expectedHeaders)
+	}
+	expected := [][]string{
+		{"TR", "12.5", "2", "35", "2.5", "40"},
+		{"DE", "5", "2", "20", "5", "20"},
+		{"US", "1", "1", "50", "1", "50"},
+	}
+	if !reflect.DeepEqual(sink.rows, expected) {
+		t.Errorf("rows = %v, expected = %v", sink.rows, expected)
+	}
+}
+
+func TestGroupByStep_Spill(t *testing.T) {
+	var rows [][]string
+	for i := 0; i < 400; i++ {
+		rows = append(rows, []string{fmt.Sprintf("g%d", i%100), "1"})
+	}
+	tempDir := t.TempDir()
+
+	step := GroupByStep([]string{"key"}, Count(), Sum("value"))
+	sink := runStream(t, []string{"key", "value"}, rows,
+		[]StreamOption{WithChunkSize(30), WithMemoryLimit(3000), WithTempDir(tempDir)}, step)
+
+	if step.(*groupByStep).partitions == nil {
+		t.Fatal("expected group by to spill")
+	}
+	if len(sink.rows) != 100 {
+		t.Fatalf("expected 100 groups, got %d", len(sink.rows))
+	}
+	for _, row := range sink.rows {
+		if row[1] != "4" || row[2] != "4" {
+			t.Errorf("unexpected aggregates for %s: %v", row[0], row[1:])
+		}
+	}
+	assertNoSpillFiles(t, tempDir)
+}
+
+func TestGroupByStep_NonNumeric(t *testing.T) {
+	source := formats.NewRawRowReader([]string{"k", "v"}, [][]string{{"a", "x"}})
+	if _, err := NewStream(source).Then(GroupByStep([]string{"k"}, Sum("v"))).Run(&collectWriter{}); err == nil {
+		t.Error("expected error for non-numeric sum")
+	}
+}
+
+func TestGroupByStep_EmptySource(t *testing.T) {
+	sink := runStream(t, []string{"k", "v"}, nil, nil, GroupByStep([]string{"k"}, Count()))
+	if !reflect.DeepEqual(sink.headers, []string{"k", "count"}) || len(sink.rows) != 0 {
+		t.Errorf("unexpected output: %v %v", sink.headers, sink.rows)
+	}
+}
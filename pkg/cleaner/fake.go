@@ -0,0 +1,101 @@
+package cleaner
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// FakeKind is the kind of value FakeColumn generates
+type FakeKind int
+
+const (
+	FakeName    FakeKind = iota // "Ayşe Demir"
+	FakeEmail                   // "ayse.demir17@example.com"
+	FakePhone                   // "+1-555-318-2046"
+	FakeAddress                 // "42 Oak Street, Springfield"
+)
+
+// fakeKindNames are the names ParseFakeKind accepts
+var fakeKindNames = map[string]FakeKind{
+	"name": FakeName, "email": FakeEmail, "phone": FakePhone, "address": FakeAddress,
+}
+
+// ParseFakeKind returns the kind with the given name: name, email, phone or address
+func ParseFakeKind(name string) (FakeKind, error) {
+	kind, ok := fakeKindNames[strings.ToLower(name)]
+	if !ok {
+		return FakeName, fmt.Errorf("unknown fake kind: %s", name)
+	}
+	return kind, nil
+}
+
+var (
+	fakeFirstNames = []string{
+		"Ali", "Ayşe", "Mehmet", "Fatma", "Mustafa", "Zeynep", "Emre", "Elif", "Can", "Selin",
+		"John", "Mary", "James", "Linda", "Robert", "Susan", "David", "Emma", "Daniel", "Olivia",
+	}
+	fakeLastNames = []string{
+		"Yılmaz", "Kaya", "Demir", "Şahin", "Çelik", "Yıldız", "Öztürk", "Aydın", "Arslan", "Doğan",
+		"Smith", "Johnson", "Brown", "Taylor", "Miller", "Wilson", "Moore", "Clark", "Walker", "Young",
+	}
+	fakeStreets      = []string{"Oak", "Maple", "Cedar", "Pine", "Elm", "Lake", "Hill", "Park", "River", "Meadow"}
+	fakeStreetTypes  = []string{"Street", "Avenue", "Road", "Lane", "Boulevard"}
+	fakeCities       = []string{"Springfield", "Riverside", "Fairview", "Greenville", "Madison", "Franklin", "Clinton", "Salem"}
+	fakeEmailDomains = []string{"example.com", "example.org", "example.net"}
+)
+
+// fakeValue returns the fake of the kind for value. The fake only depends on the value, the kind
+// and the seed. Emails and phones use reserved domains and the fictional 555 exchange.
+func fakeValue(value string, kind FakeKind, seed int64) string {
+	h := fnv.New64a()
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(seed))
+	h.Write(b[:])
+	h.Write([]byte{byte(kind)})
+	h.Write([]byte(value))
+	n := h.Sum64()
+
+	// pick takes the next choice out of the hash
+	pick := func(choices int) int {
+		i := int(n % uint64(choices))
+		n /= uint64(choices)
+		return i
+	}
+
+	switch kind {
+	case FakeEmail:
+		first := fakeFirstNames[pick(len(fakeFirstNames))]
+		last := fakeLastNames[pick(len(fakeLastNames))]
+		local := normalizeHeader(first, SnakeCase, true) + "." + normalizeHeader(last, SnakeCase, true)
+		return fmt.Sprintf("%s%d@%s", local, pick(100), fakeEmailDomains[pick(len(fakeEmailDomains))])
+	case FakePhone:
+		return fmt.Sprintf("+1-555-%03d-%04d", pick(1000), pick(10000))
+	case FakeAddress:
+		return fmt.Sprintf("%d %s %s, %s", 1+pick(999), fakeStreets[pick(len(fakeStreets))],
+			fakeStreetTypes[pick(len(fakeStreetTypes))], fakeCities[pick(len(fakeCities))])
+	default:
+		return fakeFirstNames[pick(len(fakeFirstNames))] + " " + fakeLastNames[pick(len(fakeLastNames))]
+	}
+}
+
+// FakeColumn replaces the values of the column with realistic fakes of the kind, to share data
+// without personal information. Replacement is deterministic: the same value gets the same fake
+// for the same seed, in every column and every run, so joins and duplicates survive. Different
+// values may get the same fake. Empty values are left unchanged.
+func (df *DataFrame) FakeColumn(column string, kind FakeKind, seed int64) (*DataFrame, error) {
+	colIndex := df.getColumnIndex(column)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
+	}
+
+	for i := range df.Data {
+		if value := df.Data[i][colIndex]; value != "" {
+			df.setCell(i, colIndex, fakeValue(value, kind, seed))
+		}
+	}
+
+	df.Types[column] = TypeString
+	return df, nil
+}
@@ -0,0 +1,58 @@
+package cleaner
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestFakeColumn(t *testing.T) {
+	patterns := map[FakeKind]*regexp.Regexp{
+		FakeName:    regexp.MustCompile(`^\pL+ \pL+$`),
+		FakeEmail:   regexp.MustCompile(`^[a-z]+\.[a-z]+\d{1,2}@example\.(com|org|net)$`),
+		FakePhone:   regexp.MustCompile(`^\+1-555-\d{3}-\d{4}$`),
+		FakeAddress: regexp.MustCompile(`^\d+ \pL+ \pL+, \pL+$`),
+	}
+	for kind, pattern := range patterns {
+		df, _ := NewDataFrame([]string{"v"}, [][]string{{"ayse@corp.com"}, {"mehmet@corp.com"}, {"ayse@corp.com"}, {""}})
+		if _, err := df.FakeColumn("v", kind, 7); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			if !pattern.MatchString(df.Data[i][0]) {
+				t.Errorf("kind %d: unexpected fake %q", kind, df.Data[i][0])
+			}
+		}
+		if df.Data[0][0] != df.Data[2][0] {
+			t.Errorf("kind %d: expected the same fake for the same value, got %q and %q", kind, df.Data[0][0], df.Data[2][0])
+		}
+		if df.Data[3][0] != "" {
+			t.Errorf("kind %d: expected the empty value to be left unchanged, got %q", kind, df.Data[3][0])
+		}
+	}
+}
+
+func TestFakeValue_Seed(t *testing.T) {
+	if fakeValue("Ali Veli", FakeName, 1) != fakeValue("Ali Veli", FakeName, 1) {
+		t.Error("expected the same fake for the same seed")
+	}
+
+	// Some of the values get other fakes with another seed
+	changed := false
+	for _, value := range []string{"a", "b", "c", "d", "e"} {
+		if fakeValue(value, FakePhone, 1) != fakeValue(value, FakePhone, 2) {
+			changed = true
+		}
+	}
+	if !changed {
+		t.Error("expected the seed to change the fakes")
+	}
+}
+
+func TestParseFakeKind(t *testing.T) {
+	if kind, err := ParseFakeKind("Email"); err != nil || kind != FakeEmail {
+		t.Errorf("expected FakeEmail, got %v, %v", kind, err)
+	}
+	if _, err := ParseFakeKind("ssn"); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
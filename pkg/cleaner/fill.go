@@ -0,0 +1,136 @@
+package cleaner
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FillStrategy selects how FillNulls computes the value that replaces the empty values of a column
+type FillStrategy int
+
+const (
+	StrategyMean   FillStrategy = iota // The arithmetic mean of a numeric column
+	StrategyMedian                     // The middle value of a numeric column, robust to outliers
+	StrategyMode                       // The most frequent value, for numeric and categorical columns
+)
+
+// ParseFillStrategy parses the name of a fill strategy: mean, median or mode
+func ParseFillStrategy(name string) (FillStrategy, error) {
+	switch strings.ToLower(name) {
+	case "mean":
+		return StrategyMean, nil
+	case "median":
+		return StrategyMedian, nil
+	case "mode":
+		return StrategyMode, nil
+	}
+	return 0, fmt.Errorf("unknown fill strategy: %s", name)
+}
+
+// FillNulls replaces the empty values of the column with a value computed from its other values,
+// e.g. to impute missing features of ML training data:
+//
+//	df, err = df.FillNulls("age", cleaner.StrategyMedian)
+//	df, err = df.FillNulls("city", cleaner.StrategyMode)
+//
+// StrategyMean and StrategyMedian need a numeric column, like the Mean and Median of Describe, and
+// write the shortest text of the number, e.g. "42.5". StrategyMode writes the most frequent value
+// as it appears, the smallest one in sorted order if several are as frequent. Values are trimmed
+// before they are compared. A column without values is left unchanged.
+func (df *DataFrame) FillNulls(column string, strategy FillStrategy) (*DataFrame, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	if strategy < StrategyMean || strategy > StrategyMode {
+		return nil, fmt.Errorf("unknown fill strategy: %d", strategy)
+	}
+
+	values := make([]string, 0, len(df.Data))
+	for _, row := range df.Data {
+		if value := strings.TrimSpace(row[colIndex]); !isNull(value) {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return df, nil
+	}
+
+	var fill string
+	if strategy == StrategyMode {
+		fill = modeValue(values)
+	} else {
+		summary := describeColumn(column, values, len(df.Data), slices.Contains(df.stringColumns, column))
+		if !summary.Numeric {
+			return nil, fmt.Errorf("column %s is not numeric", column)
+		}
+		n := *summary.Mean
+		if strategy == StrategyMedian {
+			n = *summary.Median
+		}
+		fill = strconv.FormatFloat(n, 'f', -1, 64)
+	}
+
+	for i := range df.Data {
+		if isNull(strings.TrimSpace(df.Data[i][colIndex])) {
+			df.setCell(i, colIndex, fill)
+		}
+	}
+	return df, nil
+}
+
+// FillForward replaces the empty values of the column with the last non-empty value above them,
+// e.g. the readings of a sensor that only reports changes. Empty values before the first
+// non-empty one are left unchanged. Rows are taken in their current order, so sort them first.
+func (df *DataFrame) FillForward(column string) (*DataFrame, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	last := ""
+	for i := range df.Data {
+		if value := df.Data[i][colIndex]; !isNull(strings.TrimSpace(value)) {
+			last = value
+		} else if last != "" {
+			df.setCell(i, colIndex, last)
+		}
+	}
+	return df, nil
+}
+
+// FillBackward replaces the empty values of the column with the next non-empty value below them,
+// like FillForward in the other direction. Empty values after the last non-empty one are left
+// unchanged.
+func (df *DataFrame) FillBackward(column string) (*DataFrame, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	next := ""
+	for i := len(df.Data) - 1; i >= 0; i-- {
+		if value := df.Data[i][colIndex]; !isNull(strings.TrimSpace(value)) {
+			next = value
+		} else if next != "" {
+			df.setCell(i, colIndex, next)
+		}
+	}
+	return df, nil
+}
+
+// modeValue returns the most frequent of the values, the smallest one if several are as frequent
+func modeValue(values []string) string {
+	counts := make(map[string]int, len(values))
+	for _, value := range values {
+		counts[value]++
+	}
+	var mode string
+	best := 0
+	for value, count := range counts {
+		if count > best || (count == best && value < mode) {
+			mode, best = value, count
+		}
+	}
+	return mode
+}
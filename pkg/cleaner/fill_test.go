@@ -0,0 +1,122 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFillNulls(t *testing.T) {
+	newFrame := func() *DataFrame {
+		df, _ := NewDataFrame([]string{"age", "city"}, [][]string{
+			{"30", "Izmir"}, {"", "Ankara"}, {"20", ""}, {" ", "Ankara"}, {"70", "Izmir"}, {"40", ""},
+		})
+		return df
+	}
+
+	tests := []struct {
+		column   string
+		strategy FillStrategy
+		expected []string
+	}{
+		{"age", StrategyMean, []string{"30", "40", "20", "40", "70", "40"}},
+		{"age", StrategyMedian, []string{"30", "35", "20", "35", "70", "40"}},
+		{"age", StrategyMode, []string{"30", "20", "20", "20", "70", "40"}},
+		{"city", StrategyMode, []string{"Izmir", "Ankara", "Ankara", "Ankara", "Izmir", "Ankara"}},
+	}
+	for _, tt := range tests {
+		df, err := newFrame().FillNulls(tt.column, tt.strategy)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.column, err)
+		}
+		colIndex := df.getColumnIndex(tt.column)
+		var values []string
+		for _, row := range df.Data {
+			values = append(values, row[colIndex])
+		}
+		if !reflect.DeepEqual(values, tt.expected) {
+			t.Errorf("%s, strategy %d: expected %q, got %q", tt.column, tt.strategy, tt.expected, values)
+		}
+	}
+
+	if _, err := newFrame().FillNulls("city", StrategyMean); err == nil {
+		t.Error("expected an error for the mean of a non-numeric column")
+	}
+	if _, err := newFrame().FillNulls("missing", StrategyMode); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+
+	empty, _ := NewDataFrame([]string{"age"}, [][]string{{""}, {""}})
+	if df, err := empty.FillNulls("age", StrategyMedian); err != nil || df.Data[0][0] != "" {
+		t.Errorf("expected an empty column to be left unchanged, got %v, %v", df.Data, err)
+	}
+}
+
+func TestPipelineFillNulls(t *testing.T) {
+	df, _ := NewDataFrame([]string{"score"}, [][]string{{"1.5"}, {""}, {"2"}})
+	pipeline := NewPipeline()
+	if err := pipeline.Action("fill_nulls:score=mean"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := pipeline.Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Data[1][0] != "1.75" {
+		t.Errorf("expected the mean 1.75, got %s", result.Data[1][0])
+	}
+
+	for _, action := range []string{"fill_nulls:score", "fill_nulls:score=average", "fill_nulls:=mean"} {
+		if err := NewPipeline().Action(action); err == nil {
+			t.Errorf("%s: expected an error", action)
+		}
+	}
+}
+
+func TestFillForwardAndBackward(t *testing.T) {
+	newFrame := func() *DataFrame {
+		df, _ := NewDataFrame([]string{"temperature"}, [][]string{{""}, {"21.5"}, {""}, {" "}, {"22"}, {""}})
+		return df
+	}
+
+	df, err := newFrame().FillForward("temperature")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Data, [][]string{{""}, {"21.5"}, {"21.5"}, {"21.5"}, {"22"}, {"22"}}) {
+		t.Errorf("unexpected forward fill: %v", df.Data)
+	}
+
+	df, err = newFrame().FillBackward("temperature")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Data, [][]string{{"21.5"}, {"21.5"}, {"22"}, {"22"}, {"22"}, {""}}) {
+		t.Errorf("unexpected backward fill: %v", df.Data)
+	}
+
+	if _, err := newFrame().FillForward("missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := newFrame().FillBackward("missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestPipelineFillForwardAndBackward(t *testing.T) {
+	df, _ := NewDataFrame([]string{"reading"}, [][]string{{""}, {"1"}, {""}})
+	pipeline := NewPipeline()
+	if err := pipeline.Actions("fill_forward:reading", "fill_backward:reading"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := pipeline.Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Data, [][]string{{"1"}, {"1"}, {"1"}}) {
+		t.Errorf("unexpected rows: %v", result.Data)
+	}
+	if err := NewPipeline().Action("fill_forward"); err == nil {
+		t.Error("expected a usage error without a column")
+	}
+}
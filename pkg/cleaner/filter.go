@@ -0,0 +1,73 @@
+package cleaner
+
+import "errors"
+
+// errNilPredicate is returned when a row filter has no predicate
+var errNilPredicate = errors.New("filter predicate cannot be nil")
+
+// FilterRows keeps the rows for which keep returns true, e.g.
+//
+//	df, err := df.FilterRows(func(row map[string]string) bool {
+//		age, _ := strconv.Atoi(row["age"])
+//		return row["country"] == "TR" && age > 18
+//	})
+//
+// The row maps the column names to the values. The map is reused between rows, so keep must not
+// retain it.
+func (df *DataFrame) FilterRows(keep func(row map[string]string) bool) (*DataFrame, error) {
+	if keep == nil {
+		return nil, errNilPredicate
+	}
+
+	kept := make([]bool, len(df.Data))
+	row := make(map[string]string, len(df.Headers))
+	for i := range df.Data {
+		kept[i] = keep(df.rowMap(i, row))
+	}
+	df.retainRows(kept)
+	return df, nil
+}
+
+// FilterRowsParallel keeps the rows for which keep returns true, like FilterRows, with the rows
+// split across workers. keep must be safe for concurrent use.
+func (df *DataFrame) FilterRowsParallel(keep func(row map[string]string) bool, options ...func(*ParallelOptions)) (*DataFrame, error) {
+	return df.runObserved("filter_rows", "", options, func(opts *ParallelOptions) (*DataFrame, error) {
+		if keep == nil {
+			return nil, errNilPredicate
+		}
+
+		// Workers only write the flags of their own rows
+		kept := make([]bool, len(df.Data))
+		err := processChunks(opts, len(df.Data), func(start, end int) {
+			row := make(map[string]string, len(df.Headers))
+			for i := start; i < end; i++ {
+				kept[i] = keep(df.rowMap(i, row))
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// The result shares the kept rows with df copy-on-write
+		filtered := &DataFrame{
+			Headers: df.Headers,
+			Data:    df.Data,
+			Types:   df.Types,
+
+			stringColumns: df.stringColumns,
+			collation:     df.collation,
+		}
+		df.shareRows()
+		filtered.shareRows()
+		filtered.retainRows(kept)
+		return filtered, nil
+	})
+}
+
+// rowMap fills row with the values of row i by column name and returns it
+func (df *DataFrame) rowMap(i int, row map[string]string) map[string]string {
+	for j, header := range df.Headers {
+		row[header] = df.Data[i][j]
+	}
+	return row
+}
@@ -0,0 +1,65 @@
+package cleaner
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func adultsInTurkey(row map[string]string) bool {
+	age, _ := strconv.Atoi(row["age"])
+	return row["country"] == "TR" && age > 18
+}
+
+func filterTestFrame() *DataFrame {
+	df, _ := NewDataFrame([]string{"name", "country", "age"}, [][]string{
+		{"Ali", "TR", "30"},
+		{"John", "US", "40"},
+		{"Ayşe", "TR", "17"},
+		{"Can", "TR", "19"},
+	})
+	return df
+}
+
+func TestFilterRows(t *testing.T) {
+	df, err := filterTestFrame().FilterRows(adultsInTurkey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{{"Ali", "TR", "30"}, {"Can", "TR", "19"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+
+	if _, err := df.FilterRows(nil); err == nil {
+		t.Error("expected an error for a nil predicate")
+	}
+}
+
+func TestFilterRowsParallel(t *testing.T) {
+	df := filterTestFrame()
+	filtered, err := df.FilterRowsParallel(adultsInTurkey, WithMaxWorkers(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	serial, _ := filterTestFrame().FilterRows(adultsInTurkey)
+	if !reflect.DeepEqual(filtered.Data, serial.Data) {
+		t.Errorf("expected the serial result, got %v", filtered.Data)
+	}
+	if len(df.Data) != 4 {
+		t.Error("expected the input not to change")
+	}
+}
+
+func TestPipelineFilterRows(t *testing.T) {
+	df, err := NewPipeline().Trim().FilterRows(adultsInTurkey).CaptureRejects().Run(filterTestFrame())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(df.Data) != 2 || df.Stats().RowsOut != 2 {
+		t.Errorf("unexpected result: %v", df.Data)
+	}
+	if rejects := df.Rejects(); rejects == nil || len(rejects.Data) != 2 {
+		t.Errorf("expected 2 rejected rows, got %v", rejects)
+	}
+}
@@ -0,0 +1,179 @@
+package cleaner
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// StringDistance selects how DedupeFuzzy compares values
+type StringDistance int
+
+const (
+	Levenshtein StringDistance = iota // Edits needed to turn one value into the other, relative to the longer one
+	JaroWinkler                       // Matching characters with a bonus for a common prefix, suited to short names
+)
+
+// ParseStringDistance parses the name of a string distance: levenshtein or jaro_winkler
+func ParseStringDistance(name string) (StringDistance, error) {
+	switch strings.ToLower(name) {
+	case "levenshtein":
+		return Levenshtein, nil
+	case "jaro_winkler", "jarowinkler":
+		return JaroWinkler, nil
+	}
+	return 0, fmt.Errorf("unknown string distance: %s", name)
+}
+
+// DedupeFuzzy removes the rows whose value of the column is nearly identical to the value of an
+// earlier row, e.g. "Acme Corp." after "AcmeCorp", keeping the first row of every cluster as the
+// canonical record:
+//
+//	df, err := df.DedupeFuzzy("company", 0.9, cleaner.JaroWinkler)
+//
+// Values are compared ignoring case, whitespace and punctuation, and two values belong to the same
+// cluster if their similarity, between 0 and 1, is at least threshold. A value joins the cluster of
+// the first canonical value it is similar to. Empty values are never duplicates. Every value is
+// compared with the canonical values, so the cost grows with the number of rows times the number of
+// clusters.
+func (df *DataFrame) DedupeFuzzy(column string, threshold float64, algorithm StringDistance) (*DataFrame, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	if threshold <= 0 || threshold > 1 {
+		return nil, fmt.Errorf("similarity threshold must be in (0, 1]: %g", threshold)
+	}
+	var similarity func(a, b []rune) float64
+	switch algorithm {
+	case Levenshtein:
+		similarity = levenshteinSimilarity
+	case JaroWinkler:
+		similarity = jaroWinklerSimilarity
+	default:
+		return nil, fmt.Errorf("unknown string distance: %d", algorithm)
+	}
+
+	keyOf := df.fuzzyKeyFunc()
+	kept := make([]bool, len(df.Data))
+	known := make(map[string]bool) // Compared values, true for canonical ones
+	var canonical [][]rune
+	for i, row := range df.Data {
+		key := keyOf(row[colIndex])
+		if key == "" {
+			kept[i] = true
+			continue
+		}
+		if _, ok := known[key]; ok {
+			continue
+		}
+
+		runes := []rune(key)
+		duplicate := false
+		for _, c := range canonical {
+			if similarity(runes, c) >= threshold {
+				duplicate = true
+				break
+			}
+		}
+		known[key] = !duplicate
+		if !duplicate {
+			canonical = append(canonical, runes)
+			kept[i] = true
+		}
+	}
+	df.retainRows(kept)
+	return df, nil
+}
+
+// fuzzyKey returns the letters and digits of the value, lower-cased with toLower
+func fuzzyKey(value string, toLower func(r rune) rune) string {
+	var sb strings.Builder
+	for _, r := range value {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(toLower(r))
+		}
+	}
+	return sb.String()
+}
+
+// levenshteinSimilarity returns 1 minus the edit distance of a and b divided by the longer length
+func levenshteinSimilarity(a, b []rune) float64 {
+	longer := max(len(a), len(b))
+	if longer == 0 {
+		return 1
+	}
+
+	// Two rows of the distance matrix
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return 1 - float64(prev[len(b)])/float64(longer)
+}
+
+// jaroWinklerSimilarity returns the Jaro similarity of a and b raised for a common prefix of up
+// to 4 characters, with the usual scaling factor of 0.1
+func jaroWinklerSimilarity(a, b []rune) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	// Characters match if they are equal and not farther apart than the window
+	window := max(0, max(len(a), len(b))/2-1)
+	matchedA := make([]bool, len(a))
+	matchedB := make([]bool, len(b))
+	matches := 0
+	for i := range a {
+		for j := max(0, i-window); j < min(len(b), i+window+1); j++ {
+			if !matchedB[j] && a[i] == b[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// Half the matched characters that are out of order
+	transpositions := 0
+	j := 0
+	for i := range a {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if a[i] != b[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(a)) + m/float64(len(b)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(a), len(b)) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
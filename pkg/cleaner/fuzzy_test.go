@@ -0,0 +1,88 @@
+package cleaner
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDedupeFuzzy(t *testing.T) {
+	newFrame := func() *DataFrame {
+		df, _ := NewDataFrame([]string{"id", "company"}, [][]string{
+			{"1", "AcmeCorp"},
+			{"2", "Acme Corp."},
+			{"3", "Globex"},
+			{"4", "ACME CORP"},
+			{"5", ""},
+			{"6", "Acme Corpp"},
+			{"7", ""},
+			{"8", "Globex Inc"},
+		})
+		return df
+	}
+
+	df, err := newFrame().DedupeFuzzy("company", 0.85, Levenshtein)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids := func(df *DataFrame) []string {
+		var ids []string
+		for _, row := range df.Data {
+			ids = append(ids, row[0])
+		}
+		return ids
+	}
+	if got := ids(df); !reflect.DeepEqual(got, []string{"1", "3", "5", "7", "8"}) {
+		t.Errorf("unexpected rows: %v", got)
+	}
+
+	// Jaro-Winkler rewards the common prefix of "Globex" and "Globex Inc"
+	df, _ = newFrame().DedupeFuzzy("company", 0.9, JaroWinkler)
+	if got := ids(df); !reflect.DeepEqual(got, []string{"1", "3", "5", "7"}) {
+		t.Errorf("unexpected rows: %v", got)
+	}
+
+	if _, err := newFrame().DedupeFuzzy("missing", 0.9, Levenshtein); err == nil {
+		t.Error("expected an error for a missing column")
+	}
+	if _, err := newFrame().DedupeFuzzy("company", 1.5, Levenshtein); err == nil {
+		t.Error("expected an error for an invalid threshold")
+	}
+	if _, err := newFrame().DedupeFuzzy("company", 0.9, StringDistance(9)); err == nil {
+		t.Error("expected an error for an unknown algorithm")
+	}
+
+	pipeline := NewPipeline()
+	if err := pipeline.Action("dedupe_fuzzy:company=0.9=jaro_winkler"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if df, err := pipeline.Run(newFrame()); err != nil || len(df.Data) != 4 {
+		t.Errorf("unexpected pipeline result: %v %v", df, err)
+	}
+	if err := NewPipeline().Action("dedupe_fuzzy:company=2"); err == nil {
+		t.Error("expected an error for an invalid threshold")
+	}
+}
+
+func TestStringSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b        string
+		levenshtein float64
+		jaroWinkler float64
+	}{
+		{"", "", 1, 1},
+		{"abc", "", 0, 0},
+		{"kitten", "sitting", 1 - 3.0/7, 0.746},
+		{"martha", "marhta", 1 - 2.0/6, 0.961},
+		{"dixon", "dicksonx", 1 - 4.0/8, 0.813},
+	}
+	for _, tt := range tests {
+		a, b := []rune(tt.a), []rune(tt.b)
+		if got := levenshteinSimilarity(a, b); math.Abs(got-tt.levenshtein) > 0.001 {
+			t.Errorf("levenshtein(%q, %q) = %.3f, expected %.3f", tt.a, tt.b, got, tt.levenshtein)
+		}
+		if got := jaroWinklerSimilarity(a, b); math.Abs(got-tt.jaroWinkler) > 0.001 {
+			t.Errorf("jaroWinkler(%q, %q) = %.3f, expected %.3f", tt.a, tt.b, got, tt.jaroWinkler)
+		}
+	}
+}
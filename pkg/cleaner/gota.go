@@ -0,0 +1,119 @@
+package cleaner
+
+import (
+	"strconv"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+)
+
+// gotaTypes maps column types to gota series types; other types are converted to strings
+var gotaTypes = map[Type]series.Type{
+	TypeInt:   series.Int,
+	TypeFloat: series.Float,
+	TypeBool:  series.Bool,
+}
+
+// FromGota converts a gota DataFrame to DataFrame. Int, float and bool series keep their type;
+// NA elements become empty values.
+func FromGota(gdf dataframe.DataFrame) (*DataFrame, error) {
+	if gdf.Err != nil {
+		return nil, gdf.Err
+	}
+
+	headers := gdf.Names()
+	rows := make([][]string, gdf.Nrow())
+	for i := range rows {
+		rows[i] = make([]string, len(headers))
+	}
+
+	types := make(map[string]Type, len(headers))
+	for j, header := range headers {
+		column := gdf.Col(header)
+		switch column.Type() {
+		case series.Int:
+			types[header] = TypeInt
+		case series.Float:
+			types[header] = TypeFloat
+		case series.Bool:
+			types[header] = TypeBool
+		default:
+			types[header] = TypeString
+		}
+
+		for i := range rows {
+			element := column.Elem(i)
+			if element.IsNA() {
+				continue
+			}
+			if column.Type() == series.Float {
+				// Element.String formats floats with %f, which loses precision
+				rows[i][j] = strconv.FormatFloat(element.Float(), 'f', -1, 64)
+			} else {
+				rows[i][j] = element.String()
+			}
+		}
+	}
+
+	df, err := NewDataFrame(headers, rows)
+	if err != nil {
+		return nil, err
+	}
+	for header, t := range types {
+		df.Types[header] = t
+	}
+	return df, nil
+}
+
+// ToGota converts DataFrame to a gota DataFrame. Int, float and bool columns become series of the
+// same type with empty values as NA; a column with a value that does not parse as its type, and
+// all other columns, become string series.
+func (df *DataFrame) ToGota() dataframe.DataFrame {
+	columns := make([]series.Series, len(df.Headers))
+	for j, header := range df.Headers {
+		values := make([]string, len(df.Data))
+		for i, row := range df.Data {
+			values[i] = row[j]
+		}
+
+		seriesType, ok := gotaTypes[df.Types[header]]
+		if !ok || !parsesAs(values, df.Types[header]) {
+			columns[j] = series.New(values, series.String, header)
+			continue
+		}
+
+		typed := make([]string, len(values))
+		for i, value := range values {
+			if value == "" {
+				// gota reads "NaN" as NA for every type
+				typed[i] = "NaN"
+			} else {
+				typed[i] = value
+			}
+		}
+		columns[j] = series.New(typed, seriesType, header)
+	}
+	return dataframe.New(columns...)
+}
+
+// parsesAs reports whether every non-empty value parses as t
+func parsesAs(values []string, t Type) bool {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		var err error
+		switch t {
+		case TypeInt:
+			_, err = strconv.Atoi(value)
+		case TypeFloat:
+			_, err = strconv.ParseFloat(value, 64)
+		case TypeBool:
+			_, err = strconv.ParseBool(value)
+		}
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
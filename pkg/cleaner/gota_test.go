@@ -0,0 +1,65 @@
+package cleaner
+
+import (
+	"testing"
+
+	"github.com/go-gota/gota/dataframe"
+	"github.com/go-gota/gota/series"
+)
+
+func TestToGotaAndFromGota(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "price", "active", "name"}, [][]string{
+		{"1", "9.25", "true", "Ali"},
+		{"2", "", "false", ""},
+	})
+	df.InferTypes()
+
+	gdf := df.ToGota()
+	if gdf.Err != nil {
+		t.Fatalf("ToGota error: %v", gdf.Err)
+	}
+	expectedTypes := []series.Type{series.Int, series.Float, series.Bool, series.String}
+	for i, name := range gdf.Names() {
+		if gdf.Col(name).Type() != expectedTypes[i] {
+			t.Errorf("column %s: expected %s, got %s", name, expectedTypes[i], gdf.Col(name).Type())
+		}
+	}
+	if !gdf.Col("price").Elem(1).IsNA() {
+		t.Errorf("expected empty price to be NA")
+	}
+
+	back, err := FromGota(gdf)
+	if err != nil {
+		t.Fatalf("FromGota error: %v", err)
+	}
+	for i := range df.Data {
+		for j := range df.Headers {
+			if back.Data[i][j] != df.Data[i][j] {
+				t.Errorf("cell (%d,%d): expected %q, got %q", i, j, df.Data[i][j], back.Data[i][j])
+			}
+		}
+	}
+	if back.Types["id"] != TypeInt || back.Types["price"] != TypeFloat || back.Types["name"] != TypeString {
+		t.Errorf("unexpected types: %v", back.Types)
+	}
+}
+
+func TestToGota_MismatchedValuesKeepStrings(t *testing.T) {
+	df, _ := NewDataFrame([]string{"code"}, [][]string{{"1"}, {"A2"}})
+	df.Types["code"] = TypeInt
+
+	gdf := df.ToGota()
+	if gdf.Col("code").Type() != series.String {
+		t.Fatalf("expected string series, got %s", gdf.Col("code").Type())
+	}
+	if gdf.Col("code").Elem(1).String() != "A2" {
+		t.Errorf("expected A2 to be kept, got %s", gdf.Col("code").Elem(1).String())
+	}
+}
+
+func TestFromGota_Error(t *testing.T) {
+	gdf := dataframe.New(series.New([]int{1}, series.Int, "a"), series.New([]int{1, 2}, series.Int, "b"))
+	if _, err := FromGota(gdf); err == nil {
+		t.Errorf("expected error for invalid gota DataFrame")
+	}
+}
@@ -0,0 +1,69 @@
+package cleaner
+
+import (
+	"fmt"
+	"slices"
+)
+
+// GroupedDataFrame is a DataFrame grouped by key columns, see DataFrame.GroupBy
+type GroupedDataFrame struct {
+	df   *DataFrame
+	keys []string
+}
+
+// GroupBy groups the rows by the values of the key columns, e.g.
+//
+//	summary, err := df.GroupBy("country").Agg(cleaner.Sum("amount"), cleaner.Count(), cleaner.Mean("age"))
+//
+// Nothing is computed until Agg.
+func (df *DataFrame) GroupBy(keys ...string) *GroupedDataFrame {
+	return &GroupedDataFrame{df: df, keys: slices.Clone(keys)}
+}
+
+// Agg returns a new DataFrame with one row per group, in order of first appearance: the key
+// columns followed by one column per aggregation, named like the aggregation (e.g. "amount_sum",
+// see Aggregation.As). Empty values are ignored; a value of Sum, Mean, Min or Max that is not a
+// number is an error. The grouped DataFrame is not modified.
+func (g *GroupedDataFrame) Agg(aggs ...Aggregation) (*DataFrame, error) {
+	table, headers, err := g.df.newGroupTable(g.keys, aggs)
+	if err != nil {
+		return nil, err
+	}
+	for i, header := range headers {
+		if slices.Contains(headers[:i], header) {
+			return nil, fmt.Errorf("duplicate output column: %s", header)
+		}
+	}
+
+	for _, row := range g.df.Data {
+		if _, err := table.add(rowKey(row, table.keyIndices), row, true); err != nil {
+			return nil, err
+		}
+	}
+
+	data := make([][]string, len(table.order))
+	for i, group := range table.order {
+		data[i] = table.row(group)
+	}
+	result, err := NewDataFrame(headers, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys keep their types, aggregated columns are typed from their values
+	for _, key := range g.keys {
+		result.Types[key] = g.df.Types[key]
+	}
+	values := make([]string, len(data))
+	for k, a := range aggs {
+		for i, row := range data {
+			values[i] = row[len(g.keys)+k]
+		}
+		result.Types[a.Name] = InferType(values)
+	}
+	result.stringColumns = slices.DeleteFunc(slices.Clone(g.df.stringColumns), func(column string) bool {
+		return !slices.Contains(g.keys, column)
+	})
+	result.collation = g.df.collation
+	return result, nil
+}
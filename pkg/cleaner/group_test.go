@@ -0,0 +1,67 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGroupByAgg(t *testing.T) {
+	df, _ := NewDataFrame([]string{"country", "amount", "age"}, [][]string{
+		{"TR", "100", "30"},
+		{"US", "50.5", "40"},
+		{"TR", "20", ""},
+		{"DE", "", "25"},
+		{"US", "10", "20"},
+	})
+
+	summary, err := df.GroupBy("country").Agg(Sum("amount"), Count(), Mean("age").As("avg_age"), Max("amount"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(summary.Headers, []string{"country", "amount_sum", "count", "avg_age", "amount_max"}) {
+		t.Errorf("unexpected headers: %v", summary.Headers)
+	}
+	expected := [][]string{
+		{"TR", "120", "2", "30", "100"},
+		{"US", "60.5", "2", "30", "50.5"},
+		{"DE", "0", "1", "25", ""},
+	}
+	if !reflect.DeepEqual(summary.Data, expected) {
+		t.Errorf("unexpected data: %v", summary.Data)
+	}
+	if summary.Types["count"] != TypeInt || summary.Types["amount_sum"] != TypeFloat || summary.Types["country"] != TypeString {
+		t.Errorf("unexpected types: %v", summary.Types)
+	}
+	if len(df.Data) != 5 {
+		t.Error("expected the input not to change")
+	}
+}
+
+func TestGroupByAgg_Errors(t *testing.T) {
+	df, _ := NewDataFrame([]string{"country", "name"}, [][]string{{"TR", "Ali"}})
+
+	if _, err := df.GroupBy("missing").Agg(Count()); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := df.GroupBy().Agg(Count()); err == nil {
+		t.Error("expected an error without keys")
+	}
+	if _, err := df.GroupBy("country").Agg(Sum("name")); err == nil {
+		t.Error("expected an error for a non-numeric value")
+	}
+	if _, err := df.GroupBy("country").Agg(Count(), Count()); err == nil {
+		t.Error("expected an error for duplicate output columns")
+	}
+}
+
+func TestPipelineGroupBy(t *testing.T) {
+	df, _ := NewDataFrame([]string{"city", "district"}, [][]string{{" Ankara", "a"}, {"Ankara ", "b"}, {"İzmir", "c"}})
+	df, err := NewPipeline().Trim().GroupBy([]string{"city"}, Count()).Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Data, [][]string{{"Ankara", "2"}, {"İzmir", "1"}}) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+}
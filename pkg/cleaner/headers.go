@@ -0,0 +1,152 @@
+package cleaner
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// HeaderStyle is the naming style NormalizeHeaders converts column names to
+type HeaderStyle int
+
+const (
+	SnakeCase HeaderStyle = iota // customer_id
+	CamelCase                    // customerId
+)
+
+// transliterations are the ASCII replacements of Turkish and other common accented letters
+var transliterations = map[rune]string{
+	'ç': "c", 'Ç': "C", 'ğ': "g", 'Ğ': "G", 'ı': "i", 'İ': "I", 'ö': "o", 'Ö': "O", 'ş': "s", 'Ş': "S", 'ü': "u", 'Ü': "U",
+	'â': "a", 'Â': "A", 'î': "i", 'Î': "I", 'û': "u", 'Û': "U",
+	'á': "a", 'à': "a", 'ä': "a", 'ã': "a", 'å': "a", 'é': "e", 'è': "e", 'ê': "e", 'ë': "e",
+	'í': "i", 'ì': "i", 'ï': "i", 'ó': "o", 'ò': "o", 'ô': "o", 'õ': "o", 'ú': "u", 'ù': "u",
+	'ñ': "n", 'Ñ': "N", 'ß': "ss", 'æ': "ae", 'ø': "o",
+}
+
+// NormalizeHeaders renames the columns to database-safe names in the given style: names are trimmed
+// and split into lower case words at spaces, punctuation and camel case boundaries, so "Customer ID",
+// "customer-id" and "CustomerID" all become customer_id or customerId. With transliterate, Turkish
+// and other accented letters are replaced with ASCII letters ("Şehir Adı" becomes sehir_adi). Names
+// starting with a digit get a leading underscore and names without any letter or digit become
+// column_N for the N-th column. Names that collide after normalization are an error.
+func (df *DataFrame) NormalizeHeaders(style HeaderStyle, transliterate bool) (*DataFrame, error) {
+	newHeaders := make([]string, len(df.Headers))
+	seen := make(map[string]string, len(df.Headers))
+	for i, header := range df.Headers {
+		name := normalizeHeader(header, style, transliterate)
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		if other, ok := seen[name]; ok {
+			return nil, fmt.Errorf("columns %q and %q both normalize to %s", other, header, name)
+		}
+		seen[name] = header
+		newHeaders[i] = name
+	}
+
+	renamed := make(map[string]string, len(df.Headers))
+	newTypes := make(map[string]Type, len(df.Types))
+	for i, header := range df.Headers {
+		renamed[header] = newHeaders[i]
+		if t, ok := df.Types[header]; ok {
+			newTypes[newHeaders[i]] = t
+		}
+	}
+	stringColumns := make([]string, len(df.stringColumns))
+	for i, column := range df.stringColumns {
+		stringColumns[i] = column
+		if name, ok := renamed[column]; ok {
+			stringColumns[i] = name
+		}
+	}
+
+	df.Headers = newHeaders
+	df.Types = newTypes
+	if len(stringColumns) > 0 {
+		df.stringColumns = stringColumns
+	}
+	return df, nil
+}
+
+// normalizeHeader converts a column name to the style, or returns "" if it has no letter or digit
+func normalizeHeader(header string, style HeaderStyle, transliterate bool) string {
+	if transliterate {
+		var b strings.Builder
+		for _, r := range header {
+			if s, ok := transliterations[r]; ok {
+				b.WriteString(s)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		header = b.String()
+	}
+
+	words := headerWords(header)
+	if len(words) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i, word := range words {
+		switch {
+		case style == SnakeCase && i > 0:
+			b.WriteByte('_')
+			b.WriteString(word)
+		case style == CamelCase && i > 0:
+			runes := []rune(word)
+			b.WriteRune(unicode.ToUpper(runes[0]))
+			b.WriteString(string(runes[1:]))
+		default:
+			b.WriteString(word)
+		}
+	}
+	name := b.String()
+	if unicode.IsDigit([]rune(name)[0]) {
+		name = "_" + name
+	}
+	return name
+}
+
+// headerWords splits a column name into lower case words at every rune that is not a letter or
+// digit and at camel case boundaries: "HTTPServer ID" is split into http, server and id
+func headerWords(header string) []string {
+	runes := []rune(strings.TrimSpace(header))
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, lowerHeaderWord(word))
+			word = word[:0]
+		}
+	}
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := word[len(word)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
+
+// lowerHeaderWord lowers a word, mapping the Turkish İ to i instead of i with a combining dot
+func lowerHeaderWord(word []rune) string {
+	var b strings.Builder
+	for _, r := range word {
+		if r == 'İ' {
+			b.WriteRune('i')
+		} else {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
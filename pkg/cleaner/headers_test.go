@@ -0,0 +1,57 @@
+package cleaner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeHeader(t *testing.T) {
+	cases := []struct {
+		header        string
+		style         HeaderStyle
+		transliterate bool
+		want          string
+	}{
+		{"  Customer ID ", SnakeCase, false, "customer_id"},
+		{"customer-id", SnakeCase, false, "customer_id"},
+		{"CustomerID", SnakeCase, false, "customer_id"},
+		{"HTTPServer Port", SnakeCase, false, "http_server_port"},
+		{"Total (₺)", SnakeCase, false, "total"},
+		{"Şehir Adı", SnakeCase, true, "sehir_adi"},
+		{"Şehir Adı", SnakeCase, false, "şehir_adı"},
+		{"İL", SnakeCase, false, "il"},
+		{"2024 Sales", SnakeCase, false, "_2024_sales"},
+		{"first name", CamelCase, false, "firstName"},
+		{"Doğum Tarihi", CamelCase, true, "dogumTarihi"},
+		{"%%", SnakeCase, false, ""},
+	}
+	for _, c := range cases {
+		if got := normalizeHeader(c.header, c.style, c.transliterate); got != c.want {
+			t.Errorf("normalizeHeader(%q) = %q, want %q", c.header, got, c.want)
+		}
+	}
+}
+
+func TestNormalizeHeaders(t *testing.T) {
+	df, _ := NewDataFrame([]string{"Müşteri No", "Ad Soyad", "--"}, [][]string{{"007", "Ali", "x"}})
+	df.Types["Ad Soyad"] = TypeString
+	df.WithStringColumns([]string{"Müşteri No"})
+
+	if _, err := df.NormalizeHeaders(SnakeCase, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Headers, []string{"musteri_no", "ad_soyad", "column_3"}) {
+		t.Errorf("unexpected headers: %v", df.Headers)
+	}
+	if _, ok := df.Types["ad_soyad"]; !ok {
+		t.Errorf("expected the types to be renamed, got %v", df.Types)
+	}
+	if !reflect.DeepEqual(df.StringColumns(), []string{"musteri_no"}) {
+		t.Errorf("expected the string columns to be renamed, got %v", df.StringColumns())
+	}
+
+	df, _ = NewDataFrame([]string{"Customer ID", "customer_id"}, nil)
+	if _, err := df.NormalizeHeaders(SnakeCase, false); err == nil {
+		t.Error("expected an error for colliding names")
+	}
+}
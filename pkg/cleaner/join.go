@@ -0,0 +1,141 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// JoinType selects which rows a join keeps
+type JoinType int
+
+const (
+	InnerJoin JoinType = iota // Rows with a match on both sides
+	LeftJoin                  // All rows of the left DataFrame
+	RightJoin                 // All rows of the right DataFrame
+	FullJoin                  // All rows of both DataFrames
+)
+
+// joinSuffix is appended to the right columns whose names are taken by a left column
+const joinSuffix = "_right"
+
+// Join combines the rows of df and other whose values of the key columns are equal, e.g. to enrich
+// cleaned orders with a reference file:
+//
+//	enriched, err := orders.Join(customers, []string{"customer_id"}, cleaner.LeftJoin)
+//
+// The result is a new DataFrame with the columns of df followed by the other columns of other; a
+// column of other whose name df already has gets the "_right" suffix. A row matching several rows
+// appears once per match; like SQL nulls, an empty key value matches nothing. Rows come in the
+// order of df, followed by the rows of other without a match for right and full joins. Missing
+// values of unmatched rows are empty.
+func (df *DataFrame) Join(other *DataFrame, on []string, how JoinType) (*DataFrame, error) {
+	if len(on) == 0 {
+		return nil, errors.New("join requires at least one key column")
+	}
+	if how < InnerJoin || how > FullJoin {
+		return nil, fmt.Errorf("unknown join type: %d", how)
+	}
+	leftKeys, err := df.columnIndices(on)
+	if err != nil {
+		return nil, err
+	}
+	rightKeys, err := other.columnIndices(on)
+	if err != nil {
+		return nil, err
+	}
+
+	// Output columns: all of df, then the non-key columns of other
+	headers := slices.Clone(df.Headers)
+	types := make(map[string]Type, len(df.Headers)+len(other.Headers))
+	for _, header := range df.Headers {
+		types[header] = df.Types[header]
+	}
+	stringColumns := slices.Clone(df.stringColumns)
+	var rightColumns []int
+	for j, header := range other.Headers {
+		if slices.Contains(rightKeys, j) {
+			continue
+		}
+		name := header
+		if slices.Contains(headers, name) {
+			name += joinSuffix
+			if slices.Contains(headers, name) {
+				return nil, fmt.Errorf("duplicate column in join result: %s", name)
+			}
+		}
+		headers = append(headers, name)
+		types[name] = other.Types[header]
+		if slices.Contains(other.stringColumns, header) {
+			stringColumns = append(stringColumns, name)
+		}
+		rightColumns = append(rightColumns, j)
+	}
+
+	matches := make(map[string][]int, len(other.Data))
+	for i, row := range other.Data {
+		if hasEmptyKey(row, rightKeys) {
+			continue
+		}
+		key := rowKey(row, rightKeys)
+		matches[key] = append(matches[key], i)
+	}
+
+	data := make([][]string, 0, len(df.Data))
+	matched := make([]bool, len(other.Data))
+	for _, row := range df.Data {
+		var rows []int
+		if !hasEmptyKey(row, leftKeys) {
+			rows = matches[rowKey(row, leftKeys)]
+		}
+		for _, i := range rows {
+			matched[i] = true
+			newRow := make([]string, 0, len(headers))
+			newRow = append(newRow, row...)
+			for _, j := range rightColumns {
+				newRow = append(newRow, other.Data[i][j])
+			}
+			data = append(data, newRow)
+		}
+		if len(rows) == 0 && (how == LeftJoin || how == FullJoin) {
+			newRow := make([]string, len(headers))
+			copy(newRow, row)
+			data = append(data, newRow)
+		}
+	}
+
+	if how == RightJoin || how == FullJoin {
+		for i, row := range other.Data {
+			if matched[i] {
+				continue
+			}
+			newRow := make([]string, len(headers))
+			for k, j := range leftKeys {
+				newRow[j] = row[rightKeys[k]]
+			}
+			for k, j := range rightColumns {
+				newRow[len(df.Headers)+k] = row[j]
+			}
+			data = append(data, newRow)
+		}
+	}
+
+	return &DataFrame{
+		Headers: headers,
+		Data:    data,
+		Types:   types,
+
+		stringColumns: stringColumns,
+		collation:     df.collation,
+	}, nil
+}
+
+// hasEmptyKey reports whether one of the key values of the row is empty
+func hasEmptyKey(row []string, keys []int) bool {
+	for _, j := range keys {
+		if row[j] == "" {
+			return true
+		}
+	}
+	return false
+}
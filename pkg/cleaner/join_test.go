@@ -0,0 +1,101 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func joinTestFrames() (*DataFrame, *DataFrame) {
+	orders, _ := NewDataFrame([]string{"order_id", "customer_id", "name"}, [][]string{
+		{"1", "10", "book"},
+		{"2", "20", "pen"},
+		{"3", "30", "cup"},
+		{"4", "", "box"},
+	})
+	customers, _ := NewDataFrame([]string{"customer_id", "name", "city"}, [][]string{
+		{"20", "Ali", "Ankara"},
+		{"10", "Ayşe", "İzmir"},
+		{"40", "Can", "Bursa"},
+		{"10", "Ayşe K.", "Muğla"},
+		{"", "Nobody", "Nowhere"},
+	})
+	return orders, customers
+}
+
+func TestJoin(t *testing.T) {
+	tests := []struct {
+		how      JoinType
+		expected [][]string
+	}{
+		{InnerJoin, [][]string{
+			{"1", "10", "book", "Ayşe", "İzmir"},
+			{"1", "10", "book", "Ayşe K.", "Muğla"},
+			{"2", "20", "pen", "Ali", "Ankara"},
+		}},
+		{LeftJoin, [][]string{
+			{"1", "10", "book", "Ayşe", "İzmir"},
+			{"1", "10", "book", "Ayşe K.", "Muğla"},
+			{"2", "20", "pen", "Ali", "Ankara"},
+			{"3", "30", "cup", "", ""},
+			{"4", "", "box", "", ""},
+		}},
+		{RightJoin, [][]string{
+			{"1", "10", "book", "Ayşe", "İzmir"},
+			{"1", "10", "book", "Ayşe K.", "Muğla"},
+			{"2", "20", "pen", "Ali", "Ankara"},
+			{"", "40", "", "Can", "Bursa"},
+			{"", "", "", "Nobody", "Nowhere"},
+		}},
+		{FullJoin, [][]string{
+			{"1", "10", "book", "Ayşe", "İzmir"},
+			{"1", "10", "book", "Ayşe K.", "Muğla"},
+			{"2", "20", "pen", "Ali", "Ankara"},
+			{"3", "30", "cup", "", ""},
+			{"4", "", "box", "", ""},
+			{"", "40", "", "Can", "Bursa"},
+			{"", "", "", "Nobody", "Nowhere"},
+		}},
+	}
+	for _, tt := range tests {
+		orders, customers := joinTestFrames()
+		result, err := orders.Join(customers, []string{"customer_id"}, tt.how)
+		if err != nil {
+			t.Fatalf("join %d: unexpected error: %v", tt.how, err)
+		}
+		if !reflect.DeepEqual(result.Headers, []string{"order_id", "customer_id", "name", "name_right", "city"}) {
+			t.Errorf("join %d: unexpected headers: %v", tt.how, result.Headers)
+		}
+		if !reflect.DeepEqual(result.Data, tt.expected) {
+			t.Errorf("join %d: unexpected data:\n%v\nwant\n%v", tt.how, result.Data, tt.expected)
+		}
+		if len(orders.Headers) != 3 {
+			t.Error("expected the input not to change")
+		}
+	}
+}
+
+func TestJoin_MultipleKeys(t *testing.T) {
+	sales, _ := NewDataFrame([]string{"year", "region", "amount"}, [][]string{{"2024", "EU", "10"}, {"2024", "US", "20"}})
+	targets, _ := NewDataFrame([]string{"region", "year", "target"}, [][]string{{"US", "2024", "25"}, {"EU", "2023", "5"}})
+	result, err := sales.Join(targets, []string{"year", "region"}, InnerJoin)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Data, [][]string{{"2024", "US", "20", "25"}}) {
+		t.Errorf("unexpected data: %v", result.Data)
+	}
+}
+
+func TestJoin_Errors(t *testing.T) {
+	orders, customers := joinTestFrames()
+	if _, err := orders.Join(customers, []string{"order_id"}, InnerJoin); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := orders.Join(customers, nil, InnerJoin); err == nil {
+		t.Error("expected an error without keys")
+	}
+	if _, err := orders.Join(customers, []string{"customer_id"}, JoinType(9)); err == nil {
+		t.Error("expected an error for an unknown join type")
+	}
+}
@@ -0,0 +1,57 @@
+package cleaner
+
+import (
+	"errors"
+	"slices"
+)
+
+// LintIssue is a problem of an action of a pipeline spec, see PipelineSpec.Lint
+type LintIssue struct {
+	Action  int    `json:"action"`  // Position of the action in the spec, -1 if the run failed as a whole
+	Text    string `json:"text"`    // The action, e.g. "copy_column:mail=email"
+	Message string `json:"message"` // What is wrong with it
+}
+
+// Lint checks the actions of the spec without touching any data, e.g. in CI before a pipeline file
+// is deployed. Every action must be known and have valid arguments. With columns, the columns of
+// the input, every action must also find the columns it references when it runs, taking into
+// account the columns added, renamed or removed by the actions before it; the steps run on an
+// empty DataFrame with these columns for this. All problems are returned in action order, none
+// if the spec is valid.
+func (spec *PipelineSpec) Lint(columns []string) []LintIssue {
+	var issues []LintIssue
+	p := NewPipeline()
+	var actionOf []int // The action of every step
+	for i, action := range spec.Actions {
+		if err := p.Action(action); err != nil {
+			issues = append(issues, LintIssue{Action: i, Text: action, Message: err.Error()})
+			continue
+		}
+		for len(actionOf) < len(p.steps) {
+			actionOf = append(actionOf, i)
+		}
+	}
+	if len(columns) == 0 || len(p.steps) == 0 {
+		return issues
+	}
+
+	df, err := NewDataFrame(slices.Clone(columns), nil)
+	if err != nil {
+		return issues
+	}
+	report := func(err *StepError) error {
+		action := actionOf[err.Index]
+		issues = append(issues, LintIssue{Action: action, Text: spec.Actions[action], Message: err.Err.Error()})
+		return nil
+	}
+	// Failed assertions stop the run even with a handler
+	if _, err := p.OnError(report).Run(df); err != nil {
+		var stepErr *StepError
+		if !errors.As(err, &stepErr) {
+			return append(issues, LintIssue{Action: -1, Message: err.Error()})
+		}
+		report(stepErr)
+	}
+	slices.SortStableFunc(issues, func(a, b LintIssue) int { return a.Action - b.Action })
+	return issues
+}
@@ -0,0 +1,46 @@
+package cleaner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPipelineSpecLint(t *testing.T) {
+	spec := &PipelineSpec{Actions: []string{
+		"trim",
+		"copy_column:mail=email",
+		"normalize_case:email=lower",
+		"unknown_action",
+		"split_column:full_name= =first,last",
+		"normalize_case:first=upper",
+		"replace_nulls:phone=0",
+		"pad_left:id=x",
+	}}
+
+	issues := spec.Lint(nil)
+	if len(issues) != 2 || issues[0].Action != 3 || issues[1].Action != 7 {
+		t.Fatalf("expected the unknown and the invalid action, got %+v", issues)
+	}
+	if !strings.Contains(issues[1].Message, "invalid arguments") || issues[1].Text != "pad_left:id=x" {
+		t.Errorf("unexpected issue: %+v", issues[1])
+	}
+
+	// The copied and split columns exist for the later actions; phone is missing
+	issues = spec.Lint([]string{"id", "mail", "full_name"})
+	var actions []int
+	for _, issue := range issues {
+		actions = append(actions, issue.Action)
+	}
+	if !reflect.DeepEqual(actions, []int{3, 6, 7}) {
+		t.Fatalf("expected issues of actions 3, 6 and 7, got %+v", issues)
+	}
+	if !strings.Contains(issues[1].Message, "column not found: phone") {
+		t.Errorf("expected a missing column, got %q", issues[1].Message)
+	}
+
+	valid := &PipelineSpec{Actions: []string{"trim", "fill_nulls:age=median", "assert_non_null:age"}}
+	if issues := valid.Lint([]string{"age"}); len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+}
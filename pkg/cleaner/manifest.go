@@ -0,0 +1,106 @@
+package cleaner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Manifest lists the files written by a cleaning run, so downstream loaders can verify that a file
+// is complete and unmodified and recognize a run they already loaded:
+//
+//	manifest := cleaner.NewManifest(spec.Hash())
+//	err = pipeline.Actions(spec.Actions...)
+//	df, err = pipeline.Run(df)
+//	err = df.WriteCSV("out/customers.csv")
+//	err = manifest.AddOutput("out/customers.csv", len(df.Data))
+//	err = manifest.Write("out/manifest.json")
+type Manifest struct {
+	PipelineHash string           `json:"pipeline_hash,omitempty"` // See PipelineHash
+	StartedAt    time.Time        `json:"started_at"`
+	FinishedAt   time.Time        `json:"finished_at"`
+	Outputs      []ManifestOutput `json:"outputs"`
+}
+
+// ManifestOutput describes a written file in a Manifest
+type ManifestOutput struct {
+	Path      string    `json:"path"`
+	Rows      int       `json:"rows"`
+	Bytes     int64     `json:"bytes"`
+	SHA256    string    `json:"sha256"`     // Hex SHA-256 of the file content as written, encrypted or not
+	WrittenAt time.Time `json:"written_at"` // Modification time of the file
+}
+
+// PipelineHash returns the hex SHA-256 of the actions, which identifies the cleaning that produced
+// an output: the same input cleaned with the same actions gives the same output.
+func PipelineHash(actions []string) string {
+	if actions == nil {
+		actions = []string{}
+	}
+	// The JSON array keeps the action boundaries, so ["a", "b"] and ["a,b"] differ
+	data, _ := json.Marshal(actions)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Hash returns the PipelineHash of the actions of the spec
+func (spec *PipelineSpec) Hash() string {
+	return PipelineHash(spec.Actions)
+}
+
+// NewManifest returns an empty manifest of a run of the pipeline with the given hash, started now
+func NewManifest(pipelineHash string) *Manifest {
+	return &Manifest{PipelineHash: pipelineHash, StartedAt: time.Now().UTC(), Outputs: []ManifestOutput{}}
+}
+
+// AddOutput adds the written file with the number of rows written to it. The file is read to
+// compute its checksum, so add it once it is complete.
+func (m *Manifest) AddOutput(path string, rows int) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read output: %w", err)
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, file)
+	if err != nil {
+		return fmt.Errorf("failed to read output: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read output: %w", err)
+	}
+	m.Outputs = append(m.Outputs, ManifestOutput{
+		Path:      path,
+		Rows:      rows,
+		Bytes:     size,
+		SHA256:    hex.EncodeToString(h.Sum(nil)),
+		WrittenAt: info.ModTime().UTC(),
+	})
+	return nil
+}
+
+// Write sets the finish time of the run to now and writes the manifest as JSON to path. The file is
+// replaced through a temporary file, so a loader waiting for the manifest never reads a partial one.
+func (m *Manifest) Write(path string) error {
+	m.FinishedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
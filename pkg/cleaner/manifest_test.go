@@ -0,0 +1,71 @@
+package cleaner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPipelineHash(t *testing.T) {
+	hash := PipelineHash([]string{"trim", "replace_nulls:age=0"})
+	if len(hash) != 64 {
+		t.Errorf("expected a hex SHA-256, got %q", hash)
+	}
+	if hash != PipelineHash([]string{"trim", "replace_nulls:age=0"}) {
+		t.Error("expected the same hash for the same actions")
+	}
+	for _, other := range [][]string{{"replace_nulls:age=0", "trim"}, {"trim,replace_nulls:age=0"}, nil} {
+		if PipelineHash(other) == hash {
+			t.Errorf("expected a different hash for %v", other)
+		}
+	}
+	spec := &PipelineSpec{Actions: []string{"trim", "replace_nulls:age=0"}}
+	if spec.Hash() != hash {
+		t.Error("expected the spec hash to be the hash of its actions")
+	}
+}
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "out.csv")
+	content := []byte("name\nAli\nVeli\n")
+	os.WriteFile(output, content, 0644)
+
+	manifest := NewManifest(PipelineHash([]string{"trim"}))
+	if err := manifest.AddOutput(output, 2); err != nil {
+		t.Fatalf("AddOutput error: %v", err)
+	}
+	if err := manifest.AddOutput(filepath.Join(dir, "missing.csv"), 0); err == nil {
+		t.Error("expected an error for a missing output")
+	}
+	path := filepath.Join(dir, "manifest.json")
+	if err := manifest.Write(path); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read Manifest
+	if err := json.Unmarshal(data, &read); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	sum := sha256.Sum256(content)
+	if len(read.Outputs) != 1 {
+		t.Fatalf("expected one output, got %+v", read.Outputs)
+	}
+	out := read.Outputs[0]
+	if out.Path != output || out.Rows != 2 || out.Bytes != int64(len(content)) || out.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("unexpected output %+v", out)
+	}
+	if read.PipelineHash != PipelineHash([]string{"trim"}) || read.StartedAt.IsZero() || read.FinishedAt.Before(read.StartedAt) || out.WrittenAt.IsZero() {
+		t.Errorf("unexpected manifest %+v", read)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("expected the temporary file to be removed")
+	}
+}
@@ -0,0 +1,43 @@
+package cleaner
+
+import "unsafe"
+
+// Sizes of the headers of a string and of a row slice
+const (
+	stringHeaderSize = int64(unsafe.Sizeof(""))
+	rowHeaderSize    = int64(unsafe.Sizeof([]string(nil)))
+)
+
+// MemoryUsage is the estimated memory held by the values of a DataFrame, in bytes
+type MemoryUsage struct {
+	Total   int64            `json:"total"`   // All columns, the row slices and the headers
+	Columns map[string]int64 `json:"columns"` // The values of every column with their string headers
+}
+
+// MemoryUsage estimates how many bytes the DataFrame holds, per column and in total, to size the
+// memory of the processes that load datasets like it:
+//
+//	usage := df.MemoryUsage()
+//	fmt.Printf("%d MB\n", usage.Total>>20)
+//
+// Every cell counts its string header and its bytes. Values shared by several cells, e.g. after
+// DictionaryEncode or between copy-on-write copies, are counted for every cell, so the estimate is
+// an upper bound for such frames. Allocator overhead and spare slice capacity are not counted.
+func (df *DataFrame) MemoryUsage() MemoryUsage {
+	columns := make([]int64, len(df.Headers))
+	for _, row := range df.Data {
+		for j, value := range row {
+			columns[j] += stringHeaderSize + int64(len(value))
+		}
+	}
+
+	usage := MemoryUsage{
+		Total:   int64(len(df.Data)) * rowHeaderSize,
+		Columns: make(map[string]int64, len(df.Headers)),
+	}
+	for j, header := range df.Headers {
+		usage.Columns[header] = columns[j]
+		usage.Total += columns[j] + stringHeaderSize + int64(len(header))
+	}
+	return usage
+}
@@ -0,0 +1,24 @@
+package cleaner
+
+import "testing"
+
+func TestMemoryUsage(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "name"}, [][]string{{"1", "Ali"}, {"22", ""}})
+	usage := df.MemoryUsage()
+
+	if usage.Columns["id"] != 2*stringHeaderSize+3 {
+		t.Errorf("id = %d, expected two headers and 3 bytes", usage.Columns["id"])
+	}
+	if usage.Columns["name"] != 2*stringHeaderSize+3 {
+		t.Errorf("name = %d, expected two headers and 3 bytes", usage.Columns["name"])
+	}
+	expected := usage.Columns["id"] + usage.Columns["name"] + 2*rowHeaderSize + 2*stringHeaderSize + int64(len("idname"))
+	if usage.Total != expected {
+		t.Errorf("total = %d, expected %d", usage.Total, expected)
+	}
+
+	empty, _ := NewDataFrame([]string{"id"}, nil)
+	if usage := empty.MemoryUsage(); usage.Columns["id"] != 0 || usage.Total != stringHeaderSize+2 {
+		t.Errorf("unexpected usage of an empty frame: %+v", usage)
+	}
+}
@@ -0,0 +1,21 @@
+package cleaner
+
+import (
+	"github.com/mstgnz/cleango/pkg/formats"
+)
+
+// ReadMongo reads the documents of a MongoDB collection that match filter and converts them to DataFrame.
+// Documents are flattened with the same rules as JSON files; a nil filter matches all documents.
+func ReadMongo(uri, database, collection string, filter interface{}, options ...formats.MongoOption) (*DataFrame, error) {
+	headers, data, err := formats.ReadMongoToRaw(uri, database, collection, filter, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDataFrame(headers, data)
+}
+
+// WriteMongo inserts every row of DataFrame as a document into a MongoDB collection
+func (df *DataFrame) WriteMongo(uri, database, collection string, options ...formats.MongoOption) error {
+	return formats.WriteMongo(df, uri, database, collection, options...)
+}
@@ -0,0 +1,392 @@
+package cleaner
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Float64Column is a numeric column parsed once into a float64 slice. Operations on it work on the
+// slice directly instead of parsing every cell again in every pass; write it back with
+// SetFloat64Column. Empty values are marked invalid and are skipped by all operations.
+type Float64Column struct {
+	Values []float64 // Parsed values; 0 where Valid is false
+	Valid  []bool    // false for empty values
+}
+
+// Float64Column parses the column into a Float64Column. Values that are not numbers are an error.
+func (df *DataFrame) Float64Column(column string) (*Float64Column, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Float64Column{
+		Values: make([]float64, len(df.Data)),
+		Valid:  make([]bool, len(df.Data)),
+	}
+	for i, row := range df.Data {
+		if row[colIndex] == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(row[colIndex], 64)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: conversion error: %w", i, err)
+		}
+		c.Values[i] = v
+		c.Valid[i] = true
+	}
+	return c, nil
+}
+
+// SetFloat64Column writes the values back to the column in their shortest representation and sets
+// the column type to TypeFloat. Invalid values are left unchanged.
+func (df *DataFrame) SetFloat64Column(column string, c *Float64Column) error {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return err
+	}
+	if c.Len() != len(df.Data) {
+		return fmt.Errorf("column has %d values, DataFrame has %d rows", c.Len(), len(df.Data))
+	}
+
+	for i, v := range c.Values {
+		if c.Valid[i] {
+			df.setCell(i, colIndex, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	}
+	df.Types[column] = TypeFloat
+	return nil
+}
+
+// Len returns the number of values
+func (c *Float64Column) Len() int {
+	return len(c.Values)
+}
+
+// MinMax returns the smallest and the largest valid value; ok is false if there are none
+func (c *Float64Column) MinMax() (min, max float64, ok bool) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for i, v := range c.Values {
+		if c.Valid[i] {
+			min = math.Min(min, v)
+			max = math.Max(max, v)
+			ok = true
+		}
+	}
+	if !ok {
+		return 0, 0, false
+	}
+	return min, max, true
+}
+
+// Clip limits the values to [min, max]
+func (c *Float64Column) Clip(min, max float64) {
+	for i, v := range c.Values {
+		c.Values[i] = math.Min(math.Max(v, min), max)
+	}
+}
+
+// Normalize scales the values to [0, 1] with min-max scaling. If all values are equal they become 0.
+func (c *Float64Column) Normalize() {
+	min, max, ok := c.MinMax()
+	if !ok {
+		return
+	}
+	span := max - min
+	for i, v := range c.Values {
+		if !c.Valid[i] {
+			continue
+		}
+		if span == 0 {
+			c.Values[i] = 0
+			continue
+		}
+		c.Values[i] = (v - min) / span
+	}
+}
+
+// Standardize replaces the values with their z-scores, the number of standard deviations from the
+// mean, using the sample standard deviation like Describe. If all values are equal they become 0.
+func (c *Float64Column) Standardize() {
+	count, sum := 0, 0.0
+	for i, v := range c.Values {
+		if c.Valid[i] {
+			count++
+			sum += v
+		}
+	}
+	if count == 0 {
+		return
+	}
+	mean := sum / float64(count)
+	squares := 0.0
+	for i, v := range c.Values {
+		if c.Valid[i] {
+			squares += (v - mean) * (v - mean)
+		}
+	}
+	stdDev := 0.0
+	if count > 1 {
+		stdDev = math.Sqrt(squares / float64(count-1))
+	}
+	for i, v := range c.Values {
+		if !c.Valid[i] {
+			continue
+		}
+		if stdDev == 0 {
+			c.Values[i] = 0
+			continue
+		}
+		c.Values[i] = (v - mean) / stdDev
+	}
+}
+
+// InRange returns a mask that is true for values in [min, max] and for invalid values, matching
+// the rows FilterOutliers keeps
+func (c *Float64Column) InRange(min, max float64) []bool {
+	keep := make([]bool, len(c.Values))
+	for i, v := range c.Values {
+		keep[i] = !c.Valid[i] || (v >= min && v <= max)
+	}
+	return keep
+}
+
+// RetainRows keeps the rows for which keep is true, in order
+func (df *DataFrame) RetainRows(keep []bool) (*DataFrame, error) {
+	if len(keep) != len(df.Data) {
+		return nil, fmt.Errorf("mask has %d values, DataFrame has %d rows", len(keep), len(df.Data))
+	}
+	df.retainRows(keep)
+	return df, nil
+}
+
+// ClipColumn limits the numeric values of the column to [min, max]
+func (df *DataFrame) ClipColumn(column string, min, max float64) (*DataFrame, error) {
+	c, err := df.Float64Column(column)
+	if err != nil {
+		return nil, err
+	}
+	c.Clip(min, max)
+	if err := df.SetFloat64Column(column, c); err != nil {
+		return nil, err
+	}
+	return df, nil
+}
+
+// NormalizeColumn scales the numeric values of the column to [0, 1] with min-max scaling
+func (df *DataFrame) NormalizeColumn(column string) (*DataFrame, error) {
+	c, err := df.Float64Column(column)
+	if err != nil {
+		return nil, err
+	}
+	c.Normalize()
+	if err := df.SetFloat64Column(column, c); err != nil {
+		return nil, err
+	}
+	return df, nil
+}
+
+// NormalizeMethod selects how NormalizeNumeric rescales the values of a column
+type NormalizeMethod int
+
+const (
+	NormalizeMinMax NormalizeMethod = iota // Scale to [0, 1], see Float64Column.Normalize
+	NormalizeZScore                        // Center on the mean in standard deviations, see Float64Column.Standardize
+)
+
+// ParseNormalizeMethod parses the name of a normalization method: minmax or zscore
+func ParseNormalizeMethod(name string) (NormalizeMethod, error) {
+	switch strings.ToLower(name) {
+	case "minmax", "min-max", "min_max":
+		return NormalizeMinMax, nil
+	case "zscore", "z-score", "z_score":
+		return NormalizeZScore, nil
+	}
+	return 0, fmt.Errorf("unknown normalization method: %s", name)
+}
+
+// NormalizeOption configures NormalizeNumeric
+type NormalizeOption func(*normalizeOptions)
+
+type normalizeOptions struct {
+	target string
+}
+
+// WithNormalizeTarget writes the rescaled values to a new column instead of replacing the values
+// of the column, e.g. to keep the original amounts next to the features derived from them
+func WithNormalizeTarget(column string) NormalizeOption {
+	return func(o *normalizeOptions) {
+		o.target = column
+	}
+}
+
+// NormalizeNumeric rescales the numeric values of the column, e.g. to prepare features for ML
+// training:
+//
+//	df, err = df.NormalizeNumeric("income", cleaner.NormalizeZScore)
+//	df, err = df.NormalizeNumeric("age", cleaner.NormalizeMinMax, cleaner.WithNormalizeTarget("age_scaled"))
+//
+// NormalizeMinMax scales the values to [0, 1] and NormalizeZScore to their z-scores; a column whose
+// values are all equal becomes 0. Empty values are left empty and a value that is not a number is
+// an error. The rescaled column is a TypeFloat column.
+func (df *DataFrame) NormalizeNumeric(column string, method NormalizeMethod, options ...NormalizeOption) (*DataFrame, error) {
+	if method < NormalizeMinMax || method > NormalizeZScore {
+		return nil, fmt.Errorf("unknown normalization method: %d", method)
+	}
+	var opts normalizeOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	if opts.target != "" {
+		if err := df.checkNewColumn(opts.target); err != nil {
+			return nil, err
+		}
+	}
+
+	c, err := df.Float64Column(column)
+	if err != nil {
+		return nil, err
+	}
+	if method == NormalizeMinMax {
+		c.Normalize()
+	} else {
+		c.Standardize()
+	}
+
+	if opts.target == "" {
+		if err := df.SetFloat64Column(column, c); err != nil {
+			return nil, err
+		}
+		return df, nil
+	}
+	df.addColumn(opts.target, func(i int, _ []string) string {
+		if !c.Valid[i] {
+			return ""
+		}
+		return strconv.FormatFloat(c.Values[i], 'f', -1, 64)
+	})
+	df.Types[opts.target] = TypeFloat
+	return df, nil
+}
+
+// NormalizeNumericFormats converts the formatted numbers of the column into plain decimal strings, so
+// numeric operations can parse them: "1.2E+05" becomes "120000", "45%" becomes "0.45", the accounting
+// negative "(123)" becomes "-123" and currency symbols and thousands separators are removed
+// ("$1,234.50" becomes "1234.5"). With the "tr" or "az" Locale of SetDefaults, '.' separates
+// thousands and ',' decimals instead ("₺1.234,56" becomes "1234.56") and a dot outside a thousands
+// group is an error. The conversion is exact. Empty values are left unchanged and a value that is
+// not a number is an error.
+func (df *DataFrame) NormalizeNumericFormats(column string) (*DataFrame, error) {
+	colIndex := df.getColumnIndex(column)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
+	}
+
+	decimalComma := localeDecimalComma()
+	for i := range df.Data {
+		value := df.Data[i][colIndex]
+		if value == "" {
+			continue
+		}
+		normalized, err := normalizeNumber(value, decimalComma)
+		if err != nil {
+			return nil, fmt.Errorf("row %d, column %s: %w", i, column, err)
+		}
+		df.setCell(i, colIndex, normalized)
+	}
+
+	df.Types[column] = TypeFloat
+	return df, nil
+}
+
+// plainNumberPattern matches unsigned decimals with an optional exponent, e.g. 12, 1.5, .5 and 1.2E+05
+var plainNumberPattern = regexp.MustCompile(`^(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// thousandsPattern matches numbers with comma thousands separators, e.g. 1,234,567.89
+var thousandsPattern = regexp.MustCompile(`^\d{1,3}(,\d{3})+(\.\d*)?$`)
+
+// dotThousandsPattern matches numbers with dot thousands separators and a decimal comma, e.g. 1.234.567,89
+var dotThousandsPattern = regexp.MustCompile(`^\d{1,3}(\.\d{3})+(,\d*)?$`)
+
+// normalizeNumber converts a formatted number into a plain decimal string. With decimalComma, as in
+// Turkish, '.' separates thousands and ',' decimals, and a dot outside a thousands group is an
+// error rather than a guess, so "1.5" is not read as 15.
+func normalizeNumber(value string, decimalComma bool) (string, error) {
+	s := strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+
+	// Currency symbols and spaces may appear on either side of the sign, e.g. "-$5" and "$-5"
+	s = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Sc, r) || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	if strings.HasPrefix(s, "-") {
+		negative = !negative
+		s = s[1:]
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+
+	percent := strings.HasSuffix(s, "%")
+	s = strings.TrimSuffix(s, "%")
+	if decimalComma {
+		if dotThousandsPattern.MatchString(s) {
+			s = strings.ReplaceAll(s, ".", "")
+		}
+		if strings.Contains(s, ".") {
+			return "", fmt.Errorf("not a number: %s", value)
+		}
+		s = strings.Replace(s, ",", ".", 1)
+	} else if thousandsPattern.MatchString(s) {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+
+	if !plainNumberPattern.MatchString(s) {
+		return "", fmt.Errorf("not a number: %s", value)
+	}
+	r, _ := new(big.Rat).SetString(s)
+	if percent {
+		r.Quo(r, big.NewRat(100, 1))
+	}
+	if negative {
+		r.Neg(r)
+	}
+	return decimalString(r), nil
+}
+
+// decimalString formats a rational with a terminating decimal expansion exactly, without trailing zeros
+func decimalString(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+
+	// The denominator is 2^a * 5^b, which needs max(a, b) decimal places
+	d := new(big.Int).Set(r.Denom())
+	places := 0
+	for _, p := range []int64{2, 5} {
+		n := 0
+		prime := big.NewInt(p)
+		mod := new(big.Int)
+		for {
+			q, m := new(big.Int).QuoRem(d, prime, mod)
+			if m.Sign() != 0 {
+				break
+			}
+			d = q
+			n++
+		}
+		places = max(places, n)
+	}
+	return strings.TrimRight(r.FloatString(places), "0")
+}
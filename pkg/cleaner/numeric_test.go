@@ -0,0 +1,374 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func numericTestDataFrame() *DataFrame {
+	df, _ := NewDataFrame([]string{"name", "price"}, [][]string{
+		{"a", "10"},
+		{"b", ""},
+		{"c", "250.5"},
+		{"d", "-4"},
+		{"e", "1e2"},
+	})
+	return df
+}
+
+func TestFloat64Column(t *testing.T) {
+	df := numericTestDataFrame()
+
+	c, err := df.Float64Column("price")
+	if err != nil {
+		t.Fatalf("Float64Column error: %v", err)
+	}
+	if !reflect.DeepEqual(c.Values, []float64{10, 0, 250.5, -4, 100}) || !reflect.DeepEqual(c.Valid, []bool{true, false, true, true, true}) {
+		t.Errorf("unexpected column: %+v", c)
+	}
+	if min, max, ok := c.MinMax(); !ok || min != -4 || max != 250.5 {
+		t.Errorf("unexpected MinMax: %v %v %v", min, max, ok)
+	}
+
+	df.Data[0][1] = "ten"
+	if _, err := df.Float64Column("price"); err == nil {
+		t.Error("expected a conversion error")
+	}
+	if _, err := df.Float64Column("missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestClipAndNormalizeColumn(t *testing.T) {
+	df := numericTestDataFrame()
+	if _, err := df.ClipColumn("price", 0, 100); err != nil {
+		t.Fatalf("ClipColumn error: %v", err)
+	}
+	if got := columnValues(df, 1); !reflect.DeepEqual(got, []string{"10", "", "100", "0", "100"}) {
+		t.Errorf("unexpected clipped values: %v", got)
+	}
+	if df.Types["price"] != TypeFloat {
+		t.Errorf("expected TypeFloat, got %v", df.Types["price"])
+	}
+
+	if _, err := df.NormalizeColumn("price"); err != nil {
+		t.Fatalf("NormalizeColumn error: %v", err)
+	}
+	if got := columnValues(df, 1); !reflect.DeepEqual(got, []string{"0.1", "", "1", "0", "1"}) {
+		t.Errorf("unexpected normalized values: %v", got)
+	}
+}
+
+func TestNormalizeNumeric(t *testing.T) {
+	newDataFrame := func() *DataFrame {
+		df, _ := NewDataFrame([]string{"score"}, [][]string{{"2"}, {""}, {"4"}, {"6"}})
+		return df
+	}
+
+	df, err := newDataFrame().NormalizeNumeric("score", NormalizeMinMax)
+	if err != nil {
+		t.Fatalf("NormalizeNumeric error: %v", err)
+	}
+	if got := columnValues(df, 0); !reflect.DeepEqual(got, []string{"0", "", "0.5", "1"}) {
+		t.Errorf("unexpected min-max values: %v", got)
+	}
+
+	// Mean 4, sample standard deviation 2
+	df, err = newDataFrame().NormalizeNumeric("score", NormalizeZScore, WithNormalizeTarget("score_z"))
+	if err != nil {
+		t.Fatalf("NormalizeNumeric error: %v", err)
+	}
+	if got := columnValues(df, 0); !reflect.DeepEqual(got, []string{"2", "", "4", "6"}) {
+		t.Errorf("expected the original values to be kept, got %v", got)
+	}
+	if got := columnValues(df, 1); !reflect.DeepEqual(got, []string{"-1", "", "0", "1"}) {
+		t.Errorf("unexpected z-scores: %v", got)
+	}
+	if df.Types["score_z"] != TypeFloat {
+		t.Errorf("expected TypeFloat, got %v", df.Types["score_z"])
+	}
+
+	constant, _ := NewDataFrame([]string{"score"}, [][]string{{"3"}, {"3"}})
+	if _, err := constant.NormalizeNumeric("score", NormalizeZScore); err != nil {
+		t.Fatalf("NormalizeNumeric error: %v", err)
+	}
+	if got := columnValues(constant, 0); !reflect.DeepEqual(got, []string{"0", "0"}) {
+		t.Errorf("expected 0 for equal values, got %v", got)
+	}
+
+	if _, err := newDataFrame().NormalizeNumeric("score", NormalizeMinMax, WithNormalizeTarget("score")); err == nil {
+		t.Error("expected an error for an existing target column")
+	}
+	if _, err := newDataFrame().NormalizeNumeric("missing", NormalizeMinMax); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := ParseNormalizeMethod("log"); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+}
+
+func TestInRangeMatchesFilterOutliers(t *testing.T) {
+	expected := numericTestDataFrame()
+	if _, err := expected.FilterOutliers("price", 0, 200); err != nil {
+		t.Fatal(err)
+	}
+
+	df := numericTestDataFrame()
+	c, err := df.Float64Column("price")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := df.RetainRows(c.InRange(0, 200)); err != nil {
+		t.Fatalf("RetainRows error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Data, expected.Data) {
+		t.Errorf("expected %v, got %v", expected.Data, df.Data)
+	}
+
+	if _, err := df.RetainRows([]bool{true}); err == nil {
+		t.Error("expected a length error")
+	}
+}
+
+func columnValues(df *DataFrame, colIndex int) []string {
+	values := make([]string, len(df.Data))
+	for i, row := range df.Data {
+		values[i] = row[colIndex]
+	}
+	return values
+}
+
+func numericBenchmarkDataFrame(rows int) *DataFrame {
+	data := make([][]string, rows)
+	for i := range data {
+		data[i] = []string{fmt.Sprint(i), fmt.Sprintf("%d.%02d", i%5000-1000, i%100)}
+	}
+	df, _ := NewDataFrame([]string{"id", "value"}, data)
+	return df
+}
+
+// BenchmarkNumericPerCell clips, normalizes and filters a column with one parse of every cell per operation
+func BenchmarkNumericPerCell(b *testing.B) {
+	source := numericBenchmarkDataFrame(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		df := source.DeepCopy()
+		b.StartTimer()
+		if _, err := df.ClipColumn("value", -500, 3000); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := df.NormalizeColumn("value"); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := df.FilterOutliers("value", 0.1, 0.9); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkNumericVectorized runs the same operations on a column parsed once
+func BenchmarkNumericVectorized(b *testing.B) {
+	source := numericBenchmarkDataFrame(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		df := source.DeepCopy()
+		b.StartTimer()
+		c, err := df.Float64Column("value")
+		if err != nil {
+			b.Fatal(err)
+		}
+		c.Clip(-500, 3000)
+		c.Normalize()
+		keep := c.InRange(0.1, 0.9)
+		if err := df.SetFloat64Column("value", c); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := df.RetainRows(keep); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFilterOutliersPerCell(b *testing.B) {
+	source := numericBenchmarkDataFrame(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		df := source.Copy()
+		b.StartTimer()
+		if _, err := df.FilterOutliers("value", 0, 1000); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFilterOutliersVectorized(b *testing.B) {
+	source := numericBenchmarkDataFrame(100000)
+	c, err := source.Float64Column("value")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		df := source.Copy()
+		b.StartTimer()
+		if _, err := df.RetainRows(c.InRange(0, 1000)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestNormalizeNumber(t *testing.T) {
+	cases := map[string]string{
+		"1.2E+05":              "120000",
+		"1.5e-7":               "0.00000015",
+		"45%":                  "0.45",
+		"12.5 %":               "0.125",
+		"(123)":                "-123",
+		"($1,234.50)":          "-1234.5",
+		"$1,234.50":            "1234.5",
+		"€ 99":                 "99",
+		"-₺5":                  "-5",
+		"+7":                   "7",
+		"0.10":                 "0.1",
+		"1,5":                  "",
+		"abc":                  "",
+		"1/2":                  "",
+		"12345678901234567890": "12345678901234567890",
+	}
+	for input, want := range cases {
+		got, err := normalizeNumber(input, false)
+		if want == "" {
+			if err == nil {
+				t.Errorf("normalizeNumber(%q) = %q, expected an error", input, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("normalizeNumber(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+}
+
+func TestNormalizeNumber_DecimalComma(t *testing.T) {
+	cases := map[string]string{
+		"₺1.000":     "1000",
+		"1.234,56 €": "1234.56",
+		"1.000.000":  "1000000",
+		"12,5":       "12.5",
+		"(₺1.234,5)": "-1234.5",
+		"%12,5":      "",
+		"12,5%":      "0.125",
+		"1.5":        "",
+		"1,234.50":   "",
+		"1.23.456":   "",
+		"12,5,0":     "",
+		"1.2E+05":    "",
+		"42":         "42",
+		"-₺0,10":     "-0.1",
+	}
+	for input, want := range cases {
+		got, err := normalizeNumber(input, true)
+		if want == "" {
+			if err == nil {
+				t.Errorf("normalizeNumber(%q) = %q, expected an error", input, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("normalizeNumber(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+
+	// Without the decimal comma, dot-grouped values are not numbers rather than silently rounded
+	for _, input := range []string{"1.000.000", "1.234,56 €"} {
+		if got, err := normalizeNumber(input, false); err == nil {
+			t.Errorf("normalizeNumber(%q) = %q, expected an error", input, got)
+		}
+	}
+}
+
+func TestNormalizeNumericFormats_Locale(t *testing.T) {
+	setTestDefaults(t, Defaults{Locale: "tr"})
+
+	df, _ := NewDataFrame([]string{"price"}, [][]string{{"₺1.000"}, {"1.234,56 €"}, {"1.000.000"}})
+	if _, err := df.NormalizeNumericFormats("price"); err != nil {
+		t.Fatalf("NormalizeNumericFormats error: %v", err)
+	}
+	if want := [][]string{{"1000"}, {"1234.56"}, {"1000000"}}; !reflect.DeepEqual(df.Data, want) {
+		t.Errorf("expected %v, got %v", want, df.Data)
+	}
+
+	df, _ = NewDataFrame([]string{"price"}, [][]string{{"₺1.000"}, {"2,5"}})
+	result, err := NewPipeline().NormalizeNumericFormats("price").Run(df, WithMaxWorkers(1))
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if want := [][]string{{"1000"}, {"2.5"}}; !reflect.DeepEqual(result.Data, want) {
+		t.Errorf("expected %v, got %v", want, result.Data)
+	}
+}
+
+func TestNormalizeNumericFormats(t *testing.T) {
+	df, _ := NewDataFrame([]string{"price"}, [][]string{{"$1,200"}, {""}, {"(15)"}})
+
+	if _, err := df.NormalizeNumericFormats("price"); err != nil {
+		t.Fatalf("NormalizeNumericFormats error: %v", err)
+	}
+	if df.Data[0][0] != "1200" || df.Data[1][0] != "" || df.Data[2][0] != "-15" {
+		t.Errorf("unexpected values: %v", df.Data)
+	}
+	if df.Types["price"] != TypeFloat {
+		t.Errorf("expected a float column, got %v", df.Types["price"])
+	}
+
+	df.Data[1][0] = "n/a"
+	if _, err := df.NormalizeNumericFormats("price"); err == nil {
+		t.Error("expected an error for a value that is not a number")
+	}
+}
+
+func TestPipelineNormalizeNumericFormats(t *testing.T) {
+	df, _ := NewDataFrame([]string{"price"}, [][]string{{"1.2E+05"}, {"n/a"}, {"€ 3,500"}})
+
+	result, err := NewPipeline().NormalizeNumericFormats("price").CaptureRejects().Run(df, WithMaxWorkers(1))
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	if len(result.Data) != 2 || result.Data[0][0] != "120000" || result.Data[1][0] != "3500" {
+		t.Errorf("expected 120000 and 3500, got %v", result.Data)
+	}
+	if result.Types["price"] != TypeFloat {
+		t.Errorf("expected float type, got %v", result.Types["price"])
+	}
+	if rejects := result.Rejects(); rejects == nil || len(rejects.Data) != 1 || rejects.Data[0][0] != "n/a" {
+		t.Errorf("expected n/a to be rejected, got %v", rejects)
+	}
+}
+
+func TestPipelineNormalizeNumeric(t *testing.T) {
+	df, _ := NewDataFrame([]string{"age"}, [][]string{{"20"}, {"30"}, {"40"}})
+	pipeline := NewPipeline()
+	if err := pipeline.Action("normalize_numeric:age=minmax=age_scaled"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := pipeline.Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := columnValues(result, 1); !reflect.DeepEqual(got, []string{"0", "0.5", "1"}) {
+		t.Errorf("unexpected scaled values: %v", got)
+	}
+
+	for _, action := range []string{"normalize_numeric:age", "normalize_numeric:age=log", "normalize_numeric:=zscore"} {
+		if err := NewPipeline().Action(action); err == nil {
+			t.Errorf("%s: expected an error", action)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cleaner
+
+import (
+	"context"
+	"time"
+)
+
+// Observer receives an event when an operation starts and when it finishes, e.g. to emit
+// OpenTelemetry spans or metrics. It is set with WithObserver and applies to *Parallel operations,
+// batches and pipelines; run serial operations through a Pipeline to observe them.
+//
+// OperationStarted returns the context passed to OperationFinished and to nested operations, so a
+// span started for a pipeline becomes the parent of the spans of its steps. Observers may be called
+// from several goroutines at once.
+type Observer interface {
+	OperationStarted(ctx context.Context, event OperationEvent) context.Context
+	OperationFinished(ctx context.Context, event OperationEvent)
+}
+
+// OperationEvent describes an operation. RowsOut, Duration and Err are only set when it finishes.
+type OperationEvent struct {
+	Operation string        // Operation name, e.g. "trim" or "clean_dates"
+	Column    string        // Column the operation works on, if any
+	RowsIn    int           // Number of rows before the operation
+	RowsOut   int           // Number of rows after the operation
+	Start     time.Time     // Start time
+	Duration  time.Duration // Time the operation took
+	Err       error         // Error the operation failed with
+}
+
+// WithObserver sets an observer that receives start and finish events of every operation
+func WithObserver(observer Observer) func(*ParallelOptions) {
+	return func(o *ParallelOptions) {
+		o.Observer = observer
+	}
+}
+
+// observe reports the start of an operation and returns the options to run it with, whose context
+// is the one returned by the observer, and a function that reports its end
+func (o *ParallelOptions) observe(operation, column string, rows int) (*ParallelOptions, func(rowsOut int, err error)) {
+	if o.Observer == nil {
+		return o, func(int, error) {}
+	}
+
+	event := OperationEvent{Operation: operation, Column: column, RowsIn: rows, Start: time.Now()}
+	ctx := o.Observer.OperationStarted(o.Context, event)
+	if ctx == nil {
+		ctx = o.Context
+	}
+	nested := *o
+	nested.Context = ctx
+
+	return &nested, func(rowsOut int, err error) {
+		event.RowsOut = rowsOut
+		event.Duration = time.Since(event.Start)
+		event.Err = err
+		o.Observer.OperationFinished(ctx, event)
+	}
+}
+
+// runObserved parses the options and runs an operation on df, reporting it to the observer
+func (df *DataFrame) runObserved(operation, column string, options []func(*ParallelOptions), fn func(opts *ParallelOptions) (*DataFrame, error)) (*DataFrame, error) {
+	opts := defaultParallelOptions()
+	for _, option := range options {
+		option(opts)
+	}
+
+	opts, finish := opts.observe(operation, column, len(df.Data))
+	result, err := fn(opts)
+	rowsOut := 0
+	if result != nil {
+		rowsOut = len(result.Data)
+	}
+	finish(rowsOut, err)
+	return result, err
+}
@@ -0,0 +1,106 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type parentKey struct{}
+
+// recordingObserver records events and the operation each started operation was nested in
+type recordingObserver struct {
+	mu       sync.Mutex
+	started  []string
+	parents  []string
+	finished []OperationEvent
+}
+
+func (o *recordingObserver) OperationStarted(ctx context.Context, event OperationEvent) context.Context {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.started = append(o.started, event.Operation)
+	parent, _ := ctx.Value(parentKey{}).(string)
+	o.parents = append(o.parents, parent)
+	return context.WithValue(ctx, parentKey{}, event.Operation)
+}
+
+func (o *recordingObserver) OperationFinished(ctx context.Context, event OperationEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.finished = append(o.finished, event)
+}
+
+func TestObserverParallelOperation(t *testing.T) {
+	df := cowTestDataFrame()
+	observer := &recordingObserver{}
+
+	result, err := df.FilterOutliersParallel("age", 0, 100, WithObserver(observer))
+	if err != nil {
+		t.Fatalf("FilterOutliersParallel error: %v", err)
+	}
+
+	if len(observer.finished) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(observer.finished))
+	}
+	event := observer.finished[0]
+	if event.Operation != "filter_outliers" || event.Column != "age" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if event.RowsIn != 3 || event.RowsOut != len(result.Data) || event.RowsOut != 2 {
+		t.Errorf("unexpected row counts: in %d, out %d", event.RowsIn, event.RowsOut)
+	}
+	if event.Duration <= 0 || event.Start.IsZero() {
+		t.Errorf("expected start time and duration, got %+v", event)
+	}
+}
+
+func TestObserverReportsErrors(t *testing.T) {
+	df := cowTestDataFrame()
+	observer := &recordingObserver{}
+
+	_, err := df.NormalizeCaseParallel("missing", true, WithObserver(observer))
+	if !errors.Is(err, ErrColumnNotFound) {
+		t.Fatalf("expected ErrColumnNotFound, got %v", err)
+	}
+	if len(observer.finished) != 1 || !errors.Is(observer.finished[0].Err, ErrColumnNotFound) {
+		t.Errorf("expected the error in the finish event, got %+v", observer.finished)
+	}
+}
+
+func TestObserverPipeline(t *testing.T) {
+	df := cowTestDataFrame()
+	observer := &recordingObserver{}
+
+	_, err := NewPipeline().
+		Trim().
+		NormalizeCase("name", true).
+		RenameColumn("age", "years").
+		FilterOutliers("years", 0, 100).
+		Run(df, WithObserver(observer))
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	if want := []string{"pipeline", "trim+normalize_case", "rename_column", "filter_outliers"}; !reflect.DeepEqual(observer.started, want) {
+		t.Errorf("expected started %v, got %v", want, observer.started)
+	}
+
+	var finished []string
+	for _, event := range observer.finished {
+		finished = append(finished, event.Operation)
+	}
+	if want := []string{"trim+normalize_case", "rename_column", "filter_outliers", "pipeline"}; !reflect.DeepEqual(finished, want) {
+		t.Errorf("expected finished %v, got %v", want, finished)
+	}
+
+	// Steps are started in the context returned for the pipeline
+	if want := []string{"", "pipeline", "pipeline", "pipeline"}; !reflect.DeepEqual(observer.parents, want) {
+		t.Errorf("expected parents %v, got %v", want, observer.parents)
+	}
+	if last := observer.finished[3]; last.RowsIn != 3 || last.RowsOut != 2 {
+		t.Errorf("unexpected pipeline row counts: %+v", last)
+	}
+}
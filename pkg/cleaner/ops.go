@@ -0,0 +1,56 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// OpFactory creates a custom operation from the arguments of its action, the text after "name:"
+// (empty without arguments). An error makes the action invalid.
+type OpFactory func(args string) (func(df *DataFrame) (*DataFrame, error), error)
+
+// opNamePattern, the names a custom operation can be registered under
+var opNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+var (
+	opsMu sync.RWMutex
+	// ops, custom operations registered with RegisterOp
+	ops = map[string]OpFactory{}
+)
+
+// RegisterOp registers a custom operation, or replaces the one with the same name, so pipeline
+// specs and API actions can reference it as "name" or "name:args", e.g.
+//
+//	cleaner.RegisterOp("my_company_normalize_sku", func(args string) (func(*cleaner.DataFrame) (*cleaner.DataFrame, error), error) {
+//		return func(df *cleaner.DataFrame) (*cleaner.DataFrame, error) {
+//			return df.CleanWithRegex(args, `[^A-Z0-9]`, "")
+//		}, nil
+//	})
+//
+// Names contain only letters, digits and '_' and cannot be those of built-in actions.
+func RegisterOp(name string, factory OpFactory) error {
+	if !opNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid operation name: %q", name)
+	}
+	if _, ok := actionUsages[name]; ok {
+		return fmt.Errorf("operation name is a built-in action: %s", name)
+	}
+	if factory == nil {
+		return errors.New("operation factory cannot be nil")
+	}
+
+	opsMu.Lock()
+	defer opsMu.Unlock()
+	ops[name] = factory
+	return nil
+}
+
+// registeredOp returns the factory of a custom operation
+func registeredOp(name string) (OpFactory, bool) {
+	opsMu.RLock()
+	defer opsMu.RUnlock()
+	factory, ok := ops[name]
+	return factory, ok
+}
@@ -0,0 +1,49 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegisterOp(t *testing.T) {
+	err := RegisterOp("test_normalize_sku", func(args string) (func(*DataFrame) (*DataFrame, error), error) {
+		if args == "" {
+			return nil, errors.New("column required")
+		}
+		return func(df *DataFrame) (*DataFrame, error) {
+			df, err := df.CleanWithRegex(args, `[^A-Za-z0-9]`, "")
+			if err != nil {
+				return nil, err
+			}
+			return df.NormalizeCase(args, true)
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pipeline, err := ParsePipeline([]byte("actions:\n  - trim\n  - test_normalize_sku:sku\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	df, _ := NewDataFrame([]string{"sku"}, [][]string{{" ab-12/x "}})
+	df, err = pipeline.Run(df)
+	if err != nil || !reflect.DeepEqual(df.Data, [][]string{{"AB12X"}}) {
+		t.Errorf("unexpected result: %v %v", df, err)
+	}
+
+	if err := NewPipeline().Action("test_normalize_sku"); err == nil || !strings.Contains(err.Error(), "column required") {
+		t.Errorf("expected the factory error, got %v", err)
+	}
+	if err := RegisterOp("trim", func(string) (func(*DataFrame) (*DataFrame, error), error) { return nil, nil }); err == nil {
+		t.Error("expected an error for a built-in action name")
+	}
+	if err := RegisterOp("bad:name", func(string) (func(*DataFrame) (*DataFrame, error), error) { return nil, nil }); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+	if err := RegisterOp("test_nil", nil); err == nil {
+		t.Error("expected an error for a nil factory")
+	}
+}
@@ -3,19 +3,34 @@ package cleaner
 import (
 	"context"
 	"runtime"
+	"slices"
 	"sync"
 )
 
-// ParallelOptions contains parallel processing options
+// ParallelOptions contains parallel processing options.
+//
+// All *Parallel operations are deterministic: rows are partitioned into contiguous chunks that depend
+// only on the row count and the number of workers, and results are merged by row index, so rows keep
+// their original order and the output does not depend on scheduling.
+//
+// By default the parallel versions of CleanDates and FilterOutliers are lenient: values that cannot be
+// parsed are left unchanged or kept. With WithDeterministic they behave exactly like the serial
+// operations, so serial and parallel runs produce bit-identical output for any number of workers.
 type ParallelOptions struct {
-	MaxWorkers int
-	Context    context.Context
+	MaxWorkers    int
+	Context       context.Context
+	Observer      Observer // Receives operation events; nil disables them
+	Deterministic bool     // Match the serial operations exactly, including errors and column types
 }
 
 // defaultParallelOptions returns default parallel processing options
 func defaultParallelOptions() *ParallelOptions {
+	workers := defaults.Load().Workers
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
 	return &ParallelOptions{
-		MaxWorkers: runtime.NumCPU(),
+		MaxWorkers: workers,
 		Context:    context.Background(),
 	}
 }
@@ -38,83 +53,70 @@ func WithContext(ctx context.Context) func(*ParallelOptions) {
 	}
 }
 
-// parallelizeRows performs parallel operations on rows
-func (df *DataFrame) parallelizeRows(processor func(row []string) []string, options ...func(*ParallelOptions)) (*DataFrame, error) {
-	opts := defaultParallelOptions()
-	for _, option := range options {
-		option(opts)
+// WithDeterministic makes parallel operations and pipelines behave exactly like their serial
+// counterparts: the same output, the same column types, and the same error for the same input.
+// Pipelines then run every step in its own pass, so errors are reported in step order.
+func WithDeterministic(deterministic bool) func(*ParallelOptions) {
+	return func(o *ParallelOptions) {
+		o.Deterministic = deterministic
 	}
+}
 
-	ctx := opts.Context
+// ctxCheckInterval is the number of rows a worker processes between context checks
+const ctxCheckInterval = 1024
 
-	if len(df.Data) == 0 {
-		return df, nil
+// rowChunks splits n rows into at most workers contiguous [start, end) ranges of near-equal size
+func rowChunks(n, workers int) [][2]int {
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
 	}
 
-	numWorkers := opts.MaxWorkers
-	if numWorkers > len(df.Data) {
-		numWorkers = len(df.Data)
+	chunks := make([][2]int, 0, workers)
+	size, rest := n/workers, n%workers
+	start := 0
+	for w := 0; w < workers; w++ {
+		end := start + size
+		if w < rest {
+			end++
+		}
+		if end > start {
+			chunks = append(chunks, [2]int{start, end})
+		}
+		start = end
 	}
+	return chunks
+}
 
-	jobs := make(chan int, len(df.Data))
-	results := make(chan struct {
-		index int
-		row   []string
-	}, len(df.Data))
+// processChunks partitions n rows into one contiguous chunk per worker and calls fn with [start, end)
+// ranges of at most ctxCheckInterval rows. Workers stop early when the context is done, in which case
+// its error is returned.
+func processChunks(opts *ParallelOptions, n int, fn func(start, end int)) error {
+	ctx := opts.Context
 
 	var wg sync.WaitGroup
-	for w := 0; w < numWorkers; w++ {
+	for _, chunk := range rowChunks(n, opts.MaxWorkers) {
 		wg.Add(1)
-		go func() {
+		go func(start, end int) {
 			defer wg.Done()
-			for j := range jobs {
-				select {
-				case <-ctx.Done():
+			for i := start; i < end; i += ctxCheckInterval {
+				if ctx.Err() != nil {
 					return
-				default:
 				}
-				row := processor(df.Data[j])
-				results <- struct {
-					index int
-					row   []string
-				}{index: j, row: row}
+				fn(i, min(i+ctxCheckInterval, end))
 			}
-		}()
+		}(chunk[0], chunk[1])
 	}
+	wg.Wait()
 
-	for j := range df.Data {
-		jobs <- j
-	}
-	close(jobs)
-
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	newData := make([][]string, len(df.Data))
-	copy(newData, df.Data)
-	for r := range results {
-		newData[r.index] = r.row
-	}
-
-	if err := ctx.Err(); err != nil {
-		return nil, err
-	}
-
-	df.Data = newData
-	return df, nil
+	return ctx.Err()
 }
 
-// parallelizeColumns performs parallel operations on columns
-func (df *DataFrame) parallelizeColumns(columnIndices []int, processor func(row []string, colIdx int) []string, options ...func(*ParallelOptions)) (*DataFrame, error) {
-	opts := defaultParallelOptions()
-	for _, option := range options {
-		option(opts)
-	}
-
-	ctx := opts.Context
-
+// parallelizeColumns applies transform to the given columns in parallel. Every worker processes a
+// contiguous chunk of rows; only changed values are written, so shared rows stay shared when possible.
+func (df *DataFrame) parallelizeColumns(opts *ParallelOptions, columnIndices []int, transform func(value string) string) (*DataFrame, error) {
 	if len(df.Data) == 0 {
 		return df, nil
 	}
@@ -130,66 +132,52 @@ func (df *DataFrame) parallelizeColumns(columnIndices []int, processor func(row
 		return df, nil
 	}
 
-	totalJobs := len(df.Data) * len(indices)
-
-	numWorkers := opts.MaxWorkers
-	if numWorkers > totalJobs {
-		numWorkers = totalJobs
-	}
-
-	type job struct {
-		rowIdx  int
-		colIdx  int
-		rowData []string
-	}
-	jobs := make(chan job, totalJobs)
-	results := make(chan struct {
-		rowIdx int
-		row    []string
-	}, totalJobs)
-
-	var wg sync.WaitGroup
-	for w := 0; w < numWorkers; w++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for j := range jobs {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-				}
-				row := processor(j.rowData, j.colIdx)
-				results <- struct {
-					rowIdx int
-					row    []string
-				}{rowIdx: j.rowIdx, row: row}
+	err := processChunks(opts, len(df.Data), func(start, end int) {
+		for i := start; i < end; i++ {
+			for _, colIdx := range indices {
+				df.setCell(i, colIdx, transform(df.Data[i][colIdx]))
 			}
-		}()
+		}
+	})
+	if err != nil {
+		return nil, err
 	}
+	return df, nil
+}
 
-	for i, row := range df.Data {
-		for _, colIdx := range indices {
-			jobs <- job{rowIdx: i, colIdx: colIdx, rowData: row}
+// parallelizeColumnStrict applies transform to a column in parallel with the semantics of a serial
+// loop that stops at the first error: values are computed first, then only rows before the lowest
+// failing row are written, and the error of that row is returned.
+func (df *DataFrame) parallelizeColumnStrict(opts *ParallelOptions, colIndex int, transform func(i int, value string) (string, error)) error {
+	n := len(df.Data)
+	values := make([]string, n)
+	errs := make([]error, n)
+
+	err := processChunks(opts, n, func(start, end int) {
+		for i := start; i < end; i++ {
+			values[i], errs[i] = transform(i, df.Data[i][colIndex])
 		}
+	})
+	if err != nil {
+		return err
 	}
-	close(jobs)
-
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
 
-	newData := make([][]string, len(df.Data))
-	copy(newData, df.Data)
-	for r := range results {
-		newData[r.rowIdx] = r.row
+	failed := slices.IndexFunc(errs, func(err error) bool { return err != nil })
+	limit := n
+	if failed != -1 {
+		limit = failed
 	}
 
-	if err := ctx.Err(); err != nil {
-		return nil, err
+	err = processChunks(opts, limit, func(start, end int) {
+		for i := start; i < end; i++ {
+			df.setCell(i, colIndex, values[i])
+		}
+	})
+	if err != nil {
+		return err
 	}
-
-	df.Data = newData
-	return df, nil
+	if failed != -1 {
+		return errs[failed]
+	}
+	return nil
 }
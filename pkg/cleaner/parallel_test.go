@@ -1,6 +1,9 @@
 package cleaner
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -198,6 +201,31 @@ func TestFilterOutliersParallel(t *testing.T) {
 	}
 }
 
+func TestFilterOutliersParallelPreservesOrder(t *testing.T) {
+	headers := []string{"id", "value"}
+	data := make([][]string, 5000)
+	for i := range data {
+		data[i] = []string{fmt.Sprint(i), fmt.Sprint(i % 100)}
+	}
+
+	serial, _ := NewDataFrame(headers, data)
+	expected, err := serial.Copy().FilterOutliers("value", 10, 60)
+	if err != nil {
+		t.Fatalf("FilterOutliers error: %v", err)
+	}
+
+	for _, workers := range []int{1, 3, 16} {
+		df, _ := NewDataFrame(headers, data)
+		filtered, err := df.FilterOutliersParallel("value", 10, 60, WithMaxWorkers(workers))
+		if err != nil {
+			t.Fatalf("FilterOutliersParallel error: %v", err)
+		}
+		if fmt.Sprint(filtered.Data) != fmt.Sprint(expected.Data) {
+			t.Errorf("workers=%d: parallel result differs from serial result", workers)
+		}
+	}
+}
+
 func TestCleanDatesParallel(t *testing.T) {
 	headers := []string{"Name", "Birth Date"}
 	data := [][]string{
@@ -307,3 +335,198 @@ func TestWithMaxWorkers(t *testing.T) {
 			zeroWorkers)
 	}
 }
+
+func TestRowChunks(t *testing.T) {
+	tests := []struct {
+		n, workers int
+		expected   [][2]int
+	}{
+		{10, 3, [][2]int{{0, 4}, {4, 7}, {7, 10}}},
+		{2, 8, [][2]int{{0, 1}, {1, 2}}},
+		{5, 0, [][2]int{{0, 5}}},
+	}
+
+	for _, tt := range tests {
+		chunks := rowChunks(tt.n, tt.workers)
+		if fmt.Sprint(chunks) != fmt.Sprint(tt.expected) {
+			t.Errorf("rowChunks(%d, %d) = %v, expected = %v", tt.n, tt.workers, chunks, tt.expected)
+		}
+	}
+}
+
+func TestParallelizeColumnsCancelled(t *testing.T) {
+	df := benchmarkDataFrame(100, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := df.NormalizeCaseParallel("col0", true, WithContext(ctx))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// benchmarkDataFrame creates a DataFrame with mixed-case, padded values
+func benchmarkDataFrame(rows, cols int) *DataFrame {
+	headers := make([]string, cols)
+	for j := range headers {
+		headers[j] = fmt.Sprintf("col%d", j)
+	}
+	data := make([][]string, rows)
+	for i := range data {
+		data[i] = make([]string, cols)
+		for j := range data[i] {
+			if i%10 == 0 {
+				continue
+			}
+			data[i][j] = fmt.Sprintf("  Value %d-%d  ", i, j)
+		}
+	}
+	df, _ := NewDataFrame(headers, data)
+	return df
+}
+
+func benchmarkOp(b *testing.B, op func(df *DataFrame) error) {
+	source := benchmarkDataFrame(100000, 8)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		df := source.Copy()
+		b.StartTimer()
+		if err := op(df); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNormalizeCase(b *testing.B) {
+	benchmarkOp(b, func(df *DataFrame) error {
+		_, err := df.NormalizeCase("col3", true)
+		return err
+	})
+}
+
+func BenchmarkNormalizeCaseParallel(b *testing.B) {
+	benchmarkOp(b, func(df *DataFrame) error {
+		_, err := df.NormalizeCaseParallel("col3", true)
+		return err
+	})
+}
+
+func BenchmarkReplaceNulls(b *testing.B) {
+	benchmarkOp(b, func(df *DataFrame) error {
+		_, err := df.ReplaceNulls("col3", "N/A")
+		return err
+	})
+}
+
+func BenchmarkReplaceNullsParallel(b *testing.B) {
+	benchmarkOp(b, func(df *DataFrame) error {
+		_, err := df.ReplaceNullsParallel("col3", "N/A")
+		return err
+	})
+}
+
+func BenchmarkTrimColumns(b *testing.B) {
+	benchmarkOp(b, func(df *DataFrame) error {
+		df.TrimColumns()
+		return nil
+	})
+}
+
+func BenchmarkTrimColumnsParallel(b *testing.B) {
+	benchmarkOp(b, func(df *DataFrame) error {
+		_, err := df.TrimColumnsParallel()
+		return err
+	})
+}
+
+func batchTestProcessors() []func(*DataFrame) (*DataFrame, error) {
+	return []func(*DataFrame) (*DataFrame, error){
+		func(df *DataFrame) (*DataFrame, error) {
+			return df.NormalizeCase("name", true)
+		},
+		func(df *DataFrame) (*DataFrame, error) {
+			return df.ReplaceNulls("age", "0")
+		},
+	}
+}
+
+func TestBatchPipeline(t *testing.T) {
+	df, _ := NewDataFrame([]string{"name", "age"}, [][]string{{"ali", ""}, {"ayşe", "25"}})
+
+	result, err := df.BatchPipeline(batchTestProcessors())
+	if err != nil {
+		t.Fatalf("BatchPipeline error: %v", err)
+	}
+
+	// Both transformations must be applied
+	expected := [][]string{{"ALI", "0"}, {"AYŞE", "25"}}
+	if fmt.Sprint(result.Data) != fmt.Sprint(expected) {
+		t.Errorf("BatchPipeline() = %v, expected = %v", result.Data, expected)
+	}
+
+	// The deprecated alias behaves the same
+	df, _ = NewDataFrame([]string{"name", "age"}, [][]string{{"ali", ""}, {"ayşe", "25"}})
+	result, err = df.BatchProcessParallel(batchTestProcessors())
+	if err != nil || fmt.Sprint(result.Data) != fmt.Sprint(expected) {
+		t.Errorf("BatchProcessParallel() = %v, %v, expected = %v", result, err, expected)
+	}
+}
+
+func TestBatchPipelineError(t *testing.T) {
+	df, _ := NewDataFrame([]string{"name"}, [][]string{{"ali"}})
+	processors := []func(*DataFrame) (*DataFrame, error){
+		func(df *DataFrame) (*DataFrame, error) {
+			return df.ReplaceNulls("missing", "x")
+		},
+	}
+
+	if _, err := df.BatchPipeline(processors); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := df.BatchPipeline(batchTestProcessors(), WithContext(ctx)); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestBatchBranchesParallel(t *testing.T) {
+	df, _ := NewDataFrame([]string{"name", "age"}, [][]string{{"ali", ""}, {"ayşe", "25"}})
+
+	results, err := df.BatchBranchesParallel(batchTestProcessors(), WithMaxWorkers(2))
+	if err != nil {
+		t.Fatalf("BatchBranchesParallel error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	// Every branch only sees its own transformation
+	expected := []string{
+		fmt.Sprint([][]string{{"ALI", ""}, {"AYŞE", "25"}}),
+		fmt.Sprint([][]string{{"ali", "0"}, {"ayşe", "25"}}),
+	}
+	for i, result := range results {
+		if fmt.Sprint(result.Data) != expected[i] {
+			t.Errorf("branch %d = %v, expected = %v", i, result.Data, expected[i])
+		}
+	}
+
+	// The original is untouched
+	if fmt.Sprint(df.Data) != fmt.Sprint([][]string{{"ali", ""}, {"ayşe", "25"}}) {
+		t.Errorf("original DataFrame was modified: %v", df.Data)
+	}
+}
+
+func TestBatchBranchesParallelError(t *testing.T) {
+	df, _ := NewDataFrame([]string{"name"}, [][]string{{"ali"}})
+	processors := append(batchTestProcessors()[:1], func(df *DataFrame) (*DataFrame, error) {
+		return df.ReplaceNulls("missing", "x")
+	})
+
+	if _, err := df.BatchBranchesParallel(processors); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
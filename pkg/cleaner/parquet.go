@@ -15,8 +15,11 @@ func ReadParquet(filePath string, options ...formats.ParquetOption) (*DataFrame,
 	return NewDataFrame(headers, data)
 }
 
-// WriteParquet, DataFrame is written to Parquet file
+// WriteParquet, DataFrame is written to Parquet file. The columns of WithStringColumns are written as strings.
 func (df *DataFrame) WriteParquet(filePath string, options ...formats.ParquetOption) error {
+	if len(df.stringColumns) > 0 {
+		options = append([]formats.ParquetOption{formats.WithParquetStringColumns(df.stringColumns)}, options...)
+	}
 	return formats.WriteParquet(df, filePath, options...)
 }
 
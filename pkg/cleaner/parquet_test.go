@@ -48,3 +48,19 @@ func TestWriteParquet(t *testing.T) {
 		t.Log("Parquet file was not created, but this is expected in some environments")
 	}
 }
+
+func TestWriteParquet_StringColumns(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "age"}, [][]string{{"007", "30"}, {"042", "25"}})
+	tempFile := filepath.Join(t.TempDir(), "ids.parquet")
+
+	if err := df.WithStringColumns([]string{"id"}).WriteParquet(tempFile); err != nil {
+		t.Fatalf("WriteParquet error: %v", err)
+	}
+	result, err := ReadParquet(tempFile)
+	if err != nil {
+		t.Fatalf("ReadParquet error: %v", err)
+	}
+	if result.Data[0][0] != "007" || result.Data[1][0] != "042" || result.Data[0][1] != "30" {
+		t.Errorf("expected the identifiers to keep their leading zeros, got %v", result.Data)
+	}
+}
@@ -0,0 +1,78 @@
+package cleaner
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// patternRefPattern, a reference to a named pattern in place of a regex, e.g. "@email". A regex
+// that is meant to match a literal "@word" can escape it as `\@word`.
+var patternRefPattern = regexp.MustCompile(`^@[A-Za-z0-9_]+$`)
+
+var (
+	patternsMu sync.RWMutex
+	// namedPatterns, regexes usable as "@name" wherever a regex is accepted
+	namedPatterns = map[string]string{
+		"email":            `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+		"url":              `https?://[^\s<>"']+`,
+		"uuid":             `\b[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}\b`,
+		"tc_kimlik":        `\b[1-9][0-9]{10}\b`,
+		"iban_tr":          `\bTR[0-9]{2}(?: ?[0-9]{4}){5} ?[0-9]{2}\b`,
+		"non_digits":       `[^0-9]+`,
+		"non_alphanumeric": `[^\p{L}\p{N}]+`,
+		"whitespace":       `\s+`,
+	}
+)
+
+// RegisterPattern adds a named pattern, or replaces the one with the same name, so that "@name"
+// can be used instead of the regex in CleanWithRegex, pipelines, the CLI and the API. Names
+// contain only letters, digits and '_'.
+func RegisterPattern(name, pattern string) error {
+	if !patternRefPattern.MatchString("@" + name) {
+		return fmt.Errorf("invalid pattern name: %q", name)
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return fmt.Errorf("invalid regex pattern: %w", err)
+	}
+
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+	namedPatterns[name] = pattern
+	return nil
+}
+
+// NamedPattern returns the regex of the named pattern
+func NamedPattern(name string) (string, bool) {
+	patternsMu.RLock()
+	defer patternsMu.RUnlock()
+	pattern, ok := namedPatterns[name]
+	return pattern, ok
+}
+
+// NamedPatterns returns the names of all named patterns, sorted
+func NamedPatterns() []string {
+	patternsMu.RLock()
+	defer patternsMu.RUnlock()
+	names := make([]string, 0, len(namedPatterns))
+	for name := range namedPatterns {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// resolvePattern returns the regex of a named pattern reference, or the pattern itself
+func resolvePattern(pattern string) (string, error) {
+	if !patternRefPattern.MatchString(pattern) {
+		return pattern, nil
+	}
+	name := strings.TrimPrefix(pattern, "@")
+	resolved, ok := NamedPattern(name)
+	if !ok {
+		return "", fmt.Errorf("unknown named pattern: %s (known: %s)", pattern, strings.Join(NamedPatterns(), ", "))
+	}
+	return resolved, nil
+}
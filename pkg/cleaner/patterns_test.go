@@ -0,0 +1,86 @@
+package cleaner
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNamedPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		matches  []string
+		rejected []string
+	}{
+		{"email", []string{"ali@example.com", "a.b+c@mail.example.com.tr"}, []string{"ali@", "example.com", "ali@example"}},
+		{"url", []string{"https://example.com/a?b=1", "http://localhost:8080"}, []string{"ftp://example.com", "example.com"}},
+		{"uuid", []string{"123e4567-e89b-12d3-a456-426614174000"}, []string{"123e4567-e89b-12d3-a456-42661417400", "not-a-uuid"}},
+		{"tc_kimlik", []string{"10000000146", "TC: 12345678950"}, []string{"01234567890", "1234567895", "123456789501"}},
+		{"iban_tr", []string{"TR330006100519786457841326", "TR33 0006 1005 1978 6457 8413 26"}, []string{"DE89370400440532013000", "TR3300061005197864578413"}},
+		{"non_digits", []string{"+90 (555)"}, []string{"5551234567"}},
+		{"non_alphanumeric", []string{"a-b", "İ.ş"}, []string{"Ayşe42"}},
+		{"whitespace", []string{"a b", "a\tb"}, []string{"ab"}},
+	}
+	for _, tt := range tests {
+		pattern, ok := NamedPattern(tt.name)
+		if !ok {
+			t.Fatalf("pattern %s not registered", tt.name)
+		}
+		re := regexp.MustCompile(pattern)
+		for _, s := range tt.matches {
+			if !re.MatchString(s) {
+				t.Errorf("%s: expected %q to match", tt.name, s)
+			}
+		}
+		for _, s := range tt.rejected {
+			if re.MatchString(s) {
+				t.Errorf("%s: expected %q not to match", tt.name, s)
+			}
+		}
+	}
+}
+
+func TestCleanWithRegex_NamedPattern(t *testing.T) {
+	df, _ := NewDataFrame([]string{"phone", "notes"}, [][]string{{"+90 (555) 123-4567", "ali@x.com"}})
+	df, err := df.CleanWithRegex("phone", "@non_digits", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if df.Data[0][0] != "905551234567" {
+		t.Errorf("unexpected value: %s", df.Data[0][0])
+	}
+
+	// A pipeline resolves the name too, and an escaped @ is a literal
+	df, err = NewPipeline().CleanWithRegex("notes", "@email", "<email>").CleanWithRegex("notes", `\@`, "").Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if df.Data[0][1] != "<email>" {
+		t.Errorf("unexpected value: %s", df.Data[0][1])
+	}
+
+	if _, err := df.CleanWithRegex("phone", "@missing", ""); err == nil || !strings.Contains(err.Error(), "unknown named pattern") {
+		t.Errorf("expected an unknown pattern error, got %v", err)
+	}
+}
+
+func TestRegisterPattern(t *testing.T) {
+	if err := RegisterPattern("test_order_id", `ORD-[0-9]{6}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	df, _ := NewDataFrame([]string{"notes"}, [][]string{{"see ORD-123456"}})
+	df, err := df.CleanWithRegex("notes", "@test_order_id", "<order>")
+	if err != nil || df.Data[0][0] != "see <order>" {
+		t.Errorf("unexpected result: %v %v", df, err)
+	}
+
+	if err := RegisterPattern("bad name", `a`); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+	if err := RegisterPattern("bad_regex", `(`); err == nil {
+		t.Error("expected an error for an invalid regex")
+	}
+	if _, ok := NamedPattern("bad_regex"); ok {
+		t.Error("expected an invalid regex not to be registered")
+	}
+}
@@ -0,0 +1,1025 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// minRowsPerWorker is the smallest number of rows worth handing to a separate worker
+const minRowsPerWorker = 1024
+
+// rowFunc applies a row-wise step to row i. It returns false to drop the row.
+type rowFunc func(df *DataFrame, i int) (bool, error)
+
+// pipelineStep is one step of a Pipeline. Row-wise steps are bound to the current columns
+// and fused with their neighbours into a single pass; frame steps transform the whole DataFrame.
+type pipelineStep struct {
+	name   string
+	column string
+	reason string // Why the step drops a row, reported with captured rejects
+	bind   func(df *DataFrame) (rowFunc, error)
+	done   func(df *DataFrame)
+	apply  func(df *DataFrame) (*DataFrame, error)
+	// wholeDataset is set for frame steps that need all rows at once, e.g. to compute a mean or to
+	// find duplicates, and give a different result on chunks
+	wholeDataset bool
+}
+
+// Pipeline is a lazily built sequence of cleaning steps, e.g.
+//
+//	df, err := cleaner.NewPipeline().
+//		Trim().
+//		CleanDates("created_at", "2006-01-02").
+//		ReplaceNulls("age", "0").
+//		Run(df)
+//
+// Nothing runs until Run. Consecutive row-wise steps are fused into one pass over the data, which
+// is split into contiguous row chunks across workers. The result is the same as running the serial
+// operations one after another.
+type Pipeline struct {
+	steps   []pipelineStep
+	onError func(err *StepError) error
+	unfused bool
+	rejects bool
+
+	canaryRows     int     // Sample rows of the canary run, 0 without one
+	maxFailureRate float64 // Failure rate of a step above which the canary run stops the run
+}
+
+// StepError is an error raised by a pipeline step
+type StepError struct {
+	Index int    // Position of the step in the pipeline
+	Step  string // Name of the step, e.g. "clean_dates"
+	Err   error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("step %d (%s): %v", e.Index, e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// NewPipeline creates an empty pipeline
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// OnError sets a handler for step errors. Returning nil skips the failing step (for a missing
+// column or invalid pattern) or leaves the failing value unchanged (for a value that cannot be
+// converted) and continues; returning an error stops the run. Value errors are reported once per
+// step after the pass, with the first failing row. Without a handler the run stops with the error
+// of the first failing row. Failed assertions always stop the run.
+func (p *Pipeline) OnError(handler func(err *StepError) error) *Pipeline {
+	p.onError = handler
+	return p
+}
+
+// Unfused runs every row-wise step in its own pass instead of fusing them, so an observer can time
+// steps individually. The result is the same.
+func (p *Pipeline) Unfused() *Pipeline {
+	p.unfused = true
+	return p
+}
+
+// CaptureRejects keeps the rows that row-wise steps drop or fail on instead of discarding them.
+// A row with a value that cannot be converted is removed from the result rather than left
+// unchanged, without calling the error handler. The rows are available from Rejects of the result.
+func (p *Pipeline) CaptureRejects() *Pipeline {
+	p.rejects = true
+	return p
+}
+
+// Steps returns the names of the steps in order
+func (p *Pipeline) Steps() []string {
+	names := make([]string, len(p.steps))
+	for i, step := range p.steps {
+		names[i] = step.name
+	}
+	return names
+}
+
+// WholeDatasetSteps returns the names of the steps that need all rows at once, in order. Run on
+// chunks with ProcessChunk, e.g. while streaming, they would only see one chunk at a time. Row-wise
+// steps and the custom steps of Then work on chunks.
+func (p *Pipeline) WholeDatasetSteps() []string {
+	var names []string
+	for _, step := range p.steps {
+		if step.wholeDataset {
+			names = append(names, step.name)
+		}
+	}
+	return names
+}
+
+// rowStep appends a row-wise step
+func (p *Pipeline) rowStep(name, column string, bind func(df *DataFrame) (rowFunc, error)) *Pipeline {
+	p.steps = append(p.steps, pipelineStep{name: name, column: column, bind: bind})
+	return p
+}
+
+// Then appends a custom step that transforms the whole DataFrame
+func (p *Pipeline) Then(name string, fn func(df *DataFrame) (*DataFrame, error)) *Pipeline {
+	p.steps = append(p.steps, pipelineStep{name: name, apply: fn})
+	return p
+}
+
+// wholeDatasetStep appends a frame step that needs all rows at once, see WholeDatasetSteps
+func (p *Pipeline) wholeDatasetStep(name, column string, fn func(df *DataFrame) (*DataFrame, error)) *Pipeline {
+	p.steps = append(p.steps, pipelineStep{name: name, column: column, apply: fn, wholeDataset: true})
+	return p
+}
+
+// Trim trims all cells
+func (p *Pipeline) Trim() *Pipeline {
+	return p.rowStep("trim", "", func(df *DataFrame) (rowFunc, error) {
+		return func(df *DataFrame, i int) (bool, error) {
+			for j, value := range df.Data[i] {
+				df.setCell(i, j, strings.TrimSpace(value))
+			}
+			return true, nil
+		}, nil
+	})
+}
+
+// SanitizeControlChars removes non-printable characters and normalizes exotic spaces in the
+// columns (default: all columns)
+func (p *Pipeline) SanitizeControlChars(columns ...string) *Pipeline {
+	column := ""
+	if len(columns) == 1 {
+		column = columns[0]
+	}
+	return p.rowStep("sanitize_control_chars", column, func(df *DataFrame) (rowFunc, error) {
+		indexes, err := df.columnIndices(columns)
+		if err != nil {
+			return nil, err
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			for _, j := range indexes {
+				df.setCell(i, j, sanitizeControlChars(df.Data[i][j]))
+			}
+			return true, nil
+		}, nil
+	})
+}
+
+// ReplaceNulls replaces empty values of the column with defaultValue
+func (p *Pipeline) ReplaceNulls(column, defaultValue string) *Pipeline {
+	return p.rowStep("replace_nulls", column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			if isNull(df.Data[i][colIndex]) {
+				df.setCell(i, colIndex, defaultValue)
+			}
+			return true, nil
+		}, nil
+	})
+}
+
+// FillNulls replaces the empty values of the column with its mean, median or mode, computed from
+// all rows, see DataFrame.FillNulls
+func (p *Pipeline) FillNulls(column string, strategy FillStrategy) *Pipeline {
+	return p.wholeDatasetStep("fill_nulls", column, func(df *DataFrame) (*DataFrame, error) {
+		return df.FillNulls(column, strategy)
+	})
+}
+
+// FillForward replaces the empty values of the column with the last non-empty value above them,
+// see DataFrame.FillForward
+func (p *Pipeline) FillForward(column string) *Pipeline {
+	return p.wholeDatasetStep("fill_forward", column, func(df *DataFrame) (*DataFrame, error) {
+		return df.FillForward(column)
+	})
+}
+
+// FillBackward replaces the empty values of the column with the next non-empty value below them,
+// see DataFrame.FillBackward
+func (p *Pipeline) FillBackward(column string) *Pipeline {
+	return p.wholeDatasetStep("fill_backward", column, func(df *DataFrame) (*DataFrame, error) {
+		return df.FillBackward(column)
+	})
+}
+
+// NormalizeNumeric rescales the numeric values of the column with min-max scaling or z-scores
+// computed from all rows, see DataFrame.NormalizeNumeric
+func (p *Pipeline) NormalizeNumeric(column string, method NormalizeMethod, options ...NormalizeOption) *Pipeline {
+	return p.wholeDatasetStep("normalize_numeric", column, func(df *DataFrame) (*DataFrame, error) {
+		return df.NormalizeNumeric(column, method, options...)
+	})
+}
+
+// Bin writes the bucket of every value of the column between the edges to a new column, see
+// DataFrame.Bin
+func (p *Pipeline) Bin(column string, edges []float64, labels []string, options ...BinOption) *Pipeline {
+	p.Then("bin", func(df *DataFrame) (*DataFrame, error) {
+		return df.Bin(column, edges, labels, options...)
+	})
+	p.steps[len(p.steps)-1].column = column
+	return p
+}
+
+// BinEqualWidth bins the column into buckets of the same width computed from all rows, see
+// DataFrame.BinEqualWidth
+func (p *Pipeline) BinEqualWidth(column string, bins int, labels []string, options ...BinOption) *Pipeline {
+	return p.wholeDatasetStep("bin_equal_width", column, func(df *DataFrame) (*DataFrame, error) {
+		return df.BinEqualWidth(column, bins, labels, options...)
+	})
+}
+
+// BinEqualFrequency bins the column into buckets of about the same number of values computed from
+// all rows, see DataFrame.BinEqualFrequency
+func (p *Pipeline) BinEqualFrequency(column string, bins int, labels []string, options ...BinOption) *Pipeline {
+	return p.wholeDatasetStep("bin_equal_frequency", column, func(df *DataFrame) (*DataFrame, error) {
+		return df.BinEqualFrequency(column, bins, labels, options...)
+	})
+}
+
+// CleanDates converts the dates of the column to layout, see DataFrame.CleanDates
+func (p *Pipeline) CleanDates(column, layout string, options ...DateOption) *Pipeline {
+	opts := newDateOptions(options)
+	p.rowStep("clean_dates", column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			value := df.Data[i][colIndex]
+			if value == "" {
+				return true, nil
+			}
+			t, err := parseDateEpoch(value, layout, opts.epoch)
+			if err != nil {
+				return true, fmt.Errorf("row %d, column %s: date format not found: %s", i, column, value)
+			}
+			df.setCell(i, colIndex, t.Format(layout))
+			return true, nil
+		}, nil
+	})
+	p.steps[len(p.steps)-1].done = func(df *DataFrame) {
+		df.Types[column] = TypeDate
+	}
+	return p
+}
+
+// NormalizeNumericFormats converts the formatted numbers of the column, e.g. "1.2E+05", "45%", "(123)"
+// and "$1,234.50", into plain decimal strings, with the separators of the Locale of SetDefaults
+func (p *Pipeline) NormalizeNumericFormats(column string) *Pipeline {
+	p.rowStep("normalize_numeric_formats", column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		decimalComma := localeDecimalComma()
+		return func(df *DataFrame, i int) (bool, error) {
+			value := df.Data[i][colIndex]
+			if value == "" {
+				return true, nil
+			}
+			normalized, err := normalizeNumber(value, decimalComma)
+			if err != nil {
+				return true, fmt.Errorf("row %d, column %s: %w", i, column, err)
+			}
+			df.setCell(i, colIndex, normalized)
+			return true, nil
+		}, nil
+	})
+	p.steps[len(p.steps)-1].done = func(df *DataFrame) {
+		df.Types[column] = TypeFloat
+	}
+	return p
+}
+
+// ConvertUnits converts the numbers of the column from one unit to another, see DataFrame.ConvertUnits
+func (p *Pipeline) ConvertUnits(column, from, to string, options ...UnitOption) *Pipeline {
+	p.rowStep("convert_units", column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		convert, err := unitConverter(from, to, options)
+		if err != nil {
+			return nil, err
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			value := df.Data[i][colIndex]
+			if value == "" {
+				return true, nil
+			}
+			converted, err := convert(value)
+			if err != nil {
+				return true, fmt.Errorf("row %d, column %s: %w", i, column, err)
+			}
+			df.setCell(i, colIndex, converted)
+			return true, nil
+		}, nil
+	})
+	p.steps[len(p.steps)-1].done = func(df *DataFrame) {
+		df.Types[column] = TypeFloat
+	}
+	return p
+}
+
+// FakeColumn replaces the values of the column with deterministic fakes, see DataFrame.FakeColumn
+func (p *Pipeline) FakeColumn(column string, kind FakeKind, seed int64) *Pipeline {
+	p.rowStep("fake_column", column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			if value := df.Data[i][colIndex]; value != "" {
+				df.setCell(i, colIndex, fakeValue(value, kind, seed))
+			}
+			return true, nil
+		}, nil
+	})
+	p.steps[len(p.steps)-1].done = func(df *DataFrame) {
+		df.Types[column] = TypeString
+	}
+	return p
+}
+
+// NormalizeCase converts the column to upper or lower case
+func (p *Pipeline) NormalizeCase(column string, toUpper bool) *Pipeline {
+	return p.rowStep("normalize_case", column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		convert := toLowerCase
+		if toUpper {
+			convert = toUpperCase
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			df.setCell(i, colIndex, convert(df.Data[i][colIndex]))
+			return true, nil
+		}, nil
+	})
+}
+
+// StripPrefix removes prefix from the values of the column that begin with it
+func (p *Pipeline) StripPrefix(column, prefix string) *Pipeline {
+	return p.mapColumn("strip_prefix", column, func(value string) string { return strings.TrimPrefix(value, prefix) })
+}
+
+// StripSuffix removes suffix from the values of the column that end with it
+func (p *Pipeline) StripSuffix(column, suffix string) *Pipeline {
+	return p.mapColumn("strip_suffix", column, func(value string) string { return strings.TrimSuffix(value, suffix) })
+}
+
+// PadLeft pads the non-empty values of the column on the left with char up to width characters,
+// see DataFrame.PadLeft
+func (p *Pipeline) PadLeft(column string, width int, char rune) *Pipeline {
+	return p.mapColumn("pad_left", column, func(value string) string { return padLeft(value, width, char) })
+}
+
+// MapValues replaces the values of the column found in mapping, keeping or emptying the others,
+// see DataFrame.MapValues
+func (p *Pipeline) MapValues(column string, mapping map[string]string, keepUnmapped bool) *Pipeline {
+	return p.mapColumn("map_values", column, valueMapper(mapping, keepUnmapped))
+}
+
+// mapColumn appends a row-wise step replacing every value of the column with fn(value)
+func (p *Pipeline) mapColumn(name, column string, fn func(value string) string) *Pipeline {
+	return p.rowStep(name, column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			df.setCell(i, colIndex, fn(df.Data[i][colIndex]))
+			return true, nil
+		}, nil
+	})
+}
+
+// CleanWithRegex replaces matches of pattern in the column with replacement
+func (p *Pipeline) CleanWithRegex(column, pattern, replacement string) *Pipeline {
+	return p.rowStep("clean_regex", column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		re, err := compileRegex(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			df.setCell(i, colIndex, re.ReplaceAllString(df.Data[i][colIndex], replacement))
+			return true, nil
+		}, nil
+	})
+}
+
+// FilterOutliers drops rows whose value in the column is outside [min, max]. Empty values are kept.
+func (p *Pipeline) FilterOutliers(column string, min, max float64) *Pipeline {
+	p.rowStep("filter_outliers", column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		bounds := newNumberRange(min, max)
+		return func(df *DataFrame, i int) (bool, error) {
+			value := df.Data[i][colIndex]
+			if value == "" {
+				return true, nil
+			}
+			inRange, err := bounds.contains(value)
+			if err != nil {
+				return true, fmt.Errorf("row %d: conversion error: %w", i, err)
+			}
+			return inRange, nil
+		}, nil
+	})
+	p.steps[len(p.steps)-1].reason = fmt.Sprintf("value outside [%g, %g]", min, max)
+	return p
+}
+
+// FilterRows keeps the rows for which keep returns true, see DataFrame.FilterRows. Rows are split
+// across workers, so keep must be safe for concurrent use.
+func (p *Pipeline) FilterRows(keep func(row map[string]string) bool) *Pipeline {
+	p.rowStep("filter_rows", "", func(df *DataFrame) (rowFunc, error) {
+		if keep == nil {
+			return nil, errNilPredicate
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			return keep(df.rowMap(i, make(map[string]string, len(df.Headers)))), nil
+		}, nil
+	})
+	p.steps[len(p.steps)-1].reason = "rejected by the row filter"
+	return p
+}
+
+// ApplyColumn replaces every value of the column with fn(value), see DataFrame.ApplyColumn.
+// Parallel runs split the rows across workers, so fn must be safe for concurrent use.
+func (p *Pipeline) ApplyColumn(column string, fn func(value string) (string, error)) *Pipeline {
+	return p.rowStep("apply_column", column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		if fn == nil {
+			return nil, errNilTransform
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			value, err := fn(df.Data[i][colIndex])
+			if err != nil {
+				return true, applyError(i, column, err)
+			}
+			df.setCell(i, colIndex, value)
+			return true, nil
+		}, nil
+	})
+}
+
+// ApplyRow replaces the values of every row with the values fn returns, see DataFrame.ApplyRow.
+// Parallel runs split the rows across workers, so fn must be safe for concurrent use.
+func (p *Pipeline) ApplyRow(fn func(row map[string]string) (map[string]string, error)) *Pipeline {
+	return p.rowStep("apply_row", "", func(df *DataFrame) (rowFunc, error) {
+		if fn == nil {
+			return nil, errNilTransform
+		}
+		index := df.headerIndex()
+		return func(df *DataFrame, i int) (bool, error) {
+			return true, df.applyRow(i, make(map[string]string, len(df.Headers)), index, fn)
+		}, nil
+	})
+}
+
+// RequireReferences drops the rows whose non-empty value of the column is missing from refColumn of
+// ref, like a foreign key. Captured rejects keep the orphaned rows.
+func (p *Pipeline) RequireReferences(column string, ref *DataFrame, refColumn string) *Pipeline {
+	p.rowStep("require_references", column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		keys, err := ref.referenceKeys(refColumn)
+		if err != nil {
+			return nil, err
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			value := df.Data[i][colIndex]
+			if value == "" {
+				return true, nil
+			}
+			_, ok := keys[value]
+			return ok, nil
+		}, nil
+	})
+	p.steps[len(p.steps)-1].reason = "value not in reference column " + refColumn
+	return p
+}
+
+// EnforceAllowedValues handles the non-empty values of the column that are not in allowed, see
+// DataFrame.EnforceAllowedValues. Captured rejects keep the dropped rows and, with ViolationReport,
+// the rows with a value that is not allowed.
+func (p *Pipeline) EnforceAllowedValues(column string, allowed []string, onViolation Violation) *Pipeline {
+	return p.enforceDomain("allowed_values", column, newValueDomain(allowed, false), onViolation)
+}
+
+// EnforceDeniedValues handles the values of the column that are in denied, see
+// EnforceAllowedValues
+func (p *Pipeline) EnforceDeniedValues(column string, denied []string, onViolation Violation) *Pipeline {
+	return p.enforceDomain("denied_values", column, newValueDomain(denied, true), onViolation)
+}
+
+// enforceDomain appends a step handling the values of the column outside the domain
+func (p *Pipeline) enforceDomain(name, column string, domain valueDomain, onViolation Violation) *Pipeline {
+	p.rowStep(name, column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		if onViolation != ViolationDrop && onViolation != ViolationReplace && onViolation != ViolationReport {
+			return nil, fmt.Errorf("unknown violation action: %d", onViolation)
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			value := df.Data[i][colIndex]
+			if !domain.violates(value) {
+				return true, nil
+			}
+			switch onViolation {
+			case ViolationDrop:
+				return false, nil
+			case ViolationReplace:
+				df.setCell(i, colIndex, "")
+				return true, nil
+			}
+			return true, fmt.Errorf("row %d: %w: %s", i, ErrValueNotAllowed, value)
+		}, nil
+	})
+	p.steps[len(p.steps)-1].reason = domain.reason()
+	return p
+}
+
+// SplitColumn splits the column by separator into new columns
+func (p *Pipeline) SplitColumn(column, separator string, newColumns []string) *Pipeline {
+	p.Then("split_column", func(df *DataFrame) (*DataFrame, error) {
+		return df.SplitColumn(column, separator, newColumns)
+	})
+	p.steps[len(p.steps)-1].column = column
+	return p
+}
+
+// CoalesceColumns merges the columns into newName, taking the first non-empty value of every row
+func (p *Pipeline) CoalesceColumns(newName string, columns ...string) *Pipeline {
+	p.Then("coalesce_columns", func(df *DataFrame) (*DataFrame, error) {
+		return df.CoalesceColumns(newName, columns...)
+	})
+	p.steps[len(p.steps)-1].column = newName
+	return p
+}
+
+// AddColumnFunc appends a column computed from every row, see DataFrame.AddColumnFunc
+func (p *Pipeline) AddColumnFunc(name string, fn func(row []string) string) *Pipeline {
+	p.Then("add_column", func(df *DataFrame) (*DataFrame, error) {
+		return df.AddColumnFunc(name, fn)
+	})
+	p.steps[len(p.steps)-1].column = name
+	return p
+}
+
+// DropColumns removes the columns, see DataFrame.DropColumns
+func (p *Pipeline) DropColumns(names ...string) *Pipeline {
+	p.Then("drop_columns", func(df *DataFrame) (*DataFrame, error) {
+		return df.DropColumns(names...)
+	})
+	return p
+}
+
+// SelectColumns keeps only the columns, in the order of the arguments, see DataFrame.SelectColumns
+func (p *Pipeline) SelectColumns(names ...string) *Pipeline {
+	p.Then("select_columns", func(df *DataFrame) (*DataFrame, error) {
+		return df.SelectColumns(names...)
+	})
+	return p
+}
+
+// CopyColumn copies the values and the type of column src into column dst, see DataFrame.CopyColumn
+func (p *Pipeline) CopyColumn(src, dst string) *Pipeline {
+	p.Then("copy_column", func(df *DataFrame) (*DataFrame, error) {
+		return df.CopyColumn(src, dst)
+	})
+	p.steps[len(p.steps)-1].column = src
+	return p
+}
+
+// SwapColumns swaps the positions of two columns
+func (p *Pipeline) SwapColumns(a, b string) *Pipeline {
+	p.Then("swap_columns", func(df *DataFrame) (*DataFrame, error) {
+		return df.SwapColumns(a, b)
+	})
+	return p
+}
+
+// SortBy sorts the rows by the keys, see DataFrame.SortBy
+func (p *Pipeline) SortBy(keys []SortKey) *Pipeline {
+	return p.wholeDatasetStep("sort", "", func(df *DataFrame) (*DataFrame, error) {
+		return df.SortBy(keys)
+	})
+}
+
+// WithCollation sets the collation of the following steps, e.g. "tr" to sort Turkish text, see
+// DataFrame.WithCollation
+func (p *Pipeline) WithCollation(locale string) *Pipeline {
+	p.Then("collation", func(df *DataFrame) (*DataFrame, error) {
+		return df.WithCollation(locale)
+	})
+	return p
+}
+
+// DropDuplicates removes duplicate rows, keeping the first occurrence, see DataFrame.DropDuplicates
+func (p *Pipeline) DropDuplicates(columns ...string) *Pipeline {
+	return p.DropDuplicatesKeep(KeepFirst, columns...)
+}
+
+// DropDuplicatesKeep removes duplicate rows, keeping the first or the last occurrence
+func (p *Pipeline) DropDuplicatesKeep(keep DuplicateKeep, columns ...string) *Pipeline {
+	return p.wholeDatasetStep("drop_duplicates", "", func(df *DataFrame) (*DataFrame, error) {
+		return df.DropDuplicatesKeep(keep, columns...)
+	})
+}
+
+// DedupeFuzzy removes the rows whose value of the column is nearly identical to an earlier one,
+// see DataFrame.DedupeFuzzy
+func (p *Pipeline) DedupeFuzzy(column string, threshold float64, algorithm StringDistance) *Pipeline {
+	return p.wholeDatasetStep("dedupe_fuzzy", column, func(df *DataFrame) (*DataFrame, error) {
+		return df.DedupeFuzzy(column, threshold, algorithm)
+	})
+}
+
+// GroupBy replaces the rows with one row per group of the key columns, see GroupedDataFrame.Agg
+func (p *Pipeline) GroupBy(keys []string, aggs ...Aggregation) *Pipeline {
+	return p.wholeDatasetStep("group_by", "", func(df *DataFrame) (*DataFrame, error) {
+		return df.GroupBy(keys...).Agg(aggs...)
+	})
+}
+
+// Pivot reshapes the rows from long to wide format, see DataFrame.Pivot
+func (p *Pipeline) Pivot(indexCol, keyCol, valueCol string) *Pipeline {
+	return p.wholeDatasetStep("pivot", "", func(df *DataFrame) (*DataFrame, error) {
+		return df.Pivot(indexCol, keyCol, valueCol)
+	})
+}
+
+// Melt reshapes the rows from wide to long format, see DataFrame.Melt
+func (p *Pipeline) Melt(idColumns, valueColumns []string, varName, valueName string) *Pipeline {
+	p.Then("melt", func(df *DataFrame) (*DataFrame, error) {
+		return df.Melt(idColumns, valueColumns, varName, valueName)
+	})
+	return p
+}
+
+// Sample replaces the rows with n rows chosen at random, see DataFrame.Sample
+func (p *Pipeline) Sample(n int, seed int64) *Pipeline {
+	return p.wholeDatasetStep("sample", "", func(df *DataFrame) (*DataFrame, error) {
+		return df.Sample(n, seed)
+	})
+}
+
+// SampleFraction replaces the rows with a share of them chosen at random, see
+// DataFrame.SampleFraction
+func (p *Pipeline) SampleFraction(fraction float64, seed int64) *Pipeline {
+	return p.wholeDatasetStep("sample_fraction", "", func(df *DataFrame) (*DataFrame, error) {
+		return df.SampleFraction(fraction, seed)
+	})
+}
+
+// SampleStratified replaces the rows with a share of the rows of every value of the column, see
+// DataFrame.SampleStratified
+func (p *Pipeline) SampleStratified(column string, fraction float64, seed int64) *Pipeline {
+	return p.wholeDatasetStep("sample_stratified", column, func(df *DataFrame) (*DataFrame, error) {
+		return df.SampleStratified(column, fraction, seed)
+	})
+}
+
+// AssertSorted stops the run with ErrAssertionFailed if the rows are not sorted by the column, see
+// DataFrame.AssertSorted
+func (p *Pipeline) AssertSorted(column string, ascending bool) *Pipeline {
+	return p.wholeDatasetStep("assert_sorted", column, func(df *DataFrame) (*DataFrame, error) {
+		return df, df.AssertSorted(column, ascending)
+	})
+}
+
+// AssertUnique stops the run with ErrAssertionFailed if the values of the columns repeat, see
+// DataFrame.AssertUnique
+func (p *Pipeline) AssertUnique(columns ...string) *Pipeline {
+	return p.wholeDatasetStep("assert_unique", "", func(df *DataFrame) (*DataFrame, error) {
+		return df, df.AssertUnique(columns...)
+	})
+}
+
+// AssertNonNull stops the run with ErrAssertionFailed if the columns have empty values, see
+// DataFrame.AssertNonNull
+func (p *Pipeline) AssertNonNull(columns ...string) *Pipeline {
+	p.Then("assert_non_null", func(df *DataFrame) (*DataFrame, error) {
+		return df, df.AssertNonNull(columns...)
+	})
+	return p
+}
+
+// AddRowNumber adds the 1-based row number as the first column. Rows dropped by earlier steps are
+// not numbered, so add it first to number the input rows.
+func (p *Pipeline) AddRowNumber(name string) *Pipeline {
+	return p.wholeDatasetStep("add_row_number", name, func(df *DataFrame) (*DataFrame, error) {
+		return df.AddRowNumber(name)
+	})
+}
+
+// NormalizeHeaders renames the columns to database-safe names, see DataFrame.NormalizeHeaders
+func (p *Pipeline) NormalizeHeaders(style HeaderStyle, transliterate bool) *Pipeline {
+	p.Then("normalize_headers", func(df *DataFrame) (*DataFrame, error) {
+		return df.NormalizeHeaders(style, transliterate)
+	})
+	return p
+}
+
+// DropConstantColumns drops the columns whose most common value fills at least threshold of the rows
+func (p *Pipeline) DropConstantColumns(threshold float64) *Pipeline {
+	return p.wholeDatasetStep("drop_constant_columns", "", func(df *DataFrame) (*DataFrame, error) {
+		return df.DropConstantColumns(threshold)
+	})
+}
+
+// RenameColumn renames a column
+func (p *Pipeline) RenameColumn(oldName, newName string) *Pipeline {
+	p.Then("rename_column", func(df *DataFrame) (*DataFrame, error) {
+		return df.RenameColumn(oldName, newName)
+	})
+	p.steps[len(p.steps)-1].column = oldName
+	return p
+}
+
+// requireColumn returns the index of the column or ErrColumnNotFound
+func (df *DataFrame) requireColumn(column string) (int, error) {
+	colIndex := df.getColumnIndex(column)
+	if colIndex == -1 {
+		return -1, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
+	}
+	return colIndex, nil
+}
+
+// Run executes the pipeline on df. The DataFrame is modified in place and returned.
+// Parallel options control the number of workers and cancellation; by default all CPU cores
+// are used once there are enough rows. An observer receives an event for the pipeline and, nested
+// in it, one for every frame step and every fused pass (named after its steps, e.g. "trim+clean_dates").
+// The counts of the run are available from Stats of the result.
+func (p *Pipeline) Run(df *DataFrame, options ...func(*ParallelOptions)) (*DataFrame, error) {
+	return df.runObserved("pipeline", "", options, func(opts *ParallelOptions) (*DataFrame, error) {
+		var canary *CanaryReport
+		if p.canaryRows > 0 {
+			_, finish := opts.observe("canary", "", min(len(df.Data), p.canaryRows))
+			report, err := p.runCanary(df)
+			finish(len(df.Data), err)
+			if err != nil {
+				return nil, err
+			}
+			canary = report
+		}
+
+		stats := &Stats{RowsIn: len(df.Data), Columns: make(map[string]ColumnStats)}
+		var rejects *rejectCollector
+		if p.rejects {
+			rejects = &rejectCollector{}
+		}
+		for start := 0; start < len(p.steps); {
+			if err := opts.Context.Err(); err != nil {
+				return nil, err
+			}
+
+			if p.steps[start].apply != nil {
+				_, finish := opts.observe(p.steps[start].name, p.steps[start].column, len(df.Data))
+				result, err := p.steps[start].apply(df)
+				if err == nil {
+					df = result
+				}
+				finish(len(df.Data), err)
+				if err != nil {
+					if err = p.handleError(start, err); err != nil {
+						return nil, err
+					}
+				}
+				start++
+				continue
+			}
+
+			// Fuse consecutive row-wise steps into one pass
+			end := start + 1
+			for !p.unfused && !opts.Deterministic && end < len(p.steps) && p.steps[end].apply == nil {
+				end++
+			}
+			column := ""
+			if end == start+1 {
+				column = p.steps[start].column
+			}
+			passOpts, finish := opts.observe(strings.Join(p.Steps()[start:end], "+"), column, len(df.Data))
+			err := p.runFused(df, start, end, passOpts, stats, rejects)
+			finish(len(df.Data), err)
+			if err != nil {
+				return nil, err
+			}
+			start = end
+		}
+		stats.RowsOut = len(df.Data)
+		df.stats = stats
+		df.canary = canary
+		df.rejects = nil
+		if rejects != nil {
+			df.rejects = rejects.dataFrame().WithStringColumns(df.stringColumns)
+		}
+		return df, nil
+	})
+}
+
+// ProcessChunk runs the pipeline on a stream chunk, so a Pipeline can be used as a StreamStep. The
+// steps of WholeDatasetSteps only see the chunk.
+func (p *Pipeline) ProcessChunk(chunk *DataFrame) (*DataFrame, error) {
+	return p.Run(chunk, WithMaxWorkers(1))
+}
+
+// handleError passes a step error to the error handler
+func (p *Pipeline) handleError(index int, err error) error {
+	stepErr := &StepError{Index: index, Step: p.steps[index].name, Err: err}
+	if p.onError == nil || errors.Is(err, ErrAssertionFailed) {
+		return stepErr
+	}
+	return p.onError(stepErr)
+}
+
+// boundStep is a row-wise step bound to the columns of a DataFrame
+type boundStep struct {
+	index  int
+	column int // Index of the column the step works on, -1 for steps on all columns
+	fn     rowFunc
+}
+
+// rowError is a value error raised by a step on a row
+type rowError struct {
+	row  int
+	step int
+	err  error
+}
+
+// runFused binds the row-wise steps [start, end) and applies them in a single pass over the rows,
+// adding the changes of every step to stats and, if rejects is not nil, the dropped rows to rejects
+func (p *Pipeline) runFused(df *DataFrame, start, end int, opts *ParallelOptions, stats *Stats, rejects *rejectCollector) error {
+	var bound []boundStep
+	for i := start; i < end; i++ {
+		fn, err := p.steps[i].bind(df)
+		if err != nil {
+			if err = p.handleError(i, err); err != nil {
+				return err
+			}
+			continue
+		}
+		column := -1
+		if p.steps[i].column != "" {
+			column = df.getColumnIndex(p.steps[i].column)
+		}
+		bound = append(bound, boundStep{index: i, column: column, fn: fn})
+	}
+	if len(bound) == 0 || len(df.Data) == 0 {
+		p.finish(df, bound)
+		return nil
+	}
+
+	strict := p.onError == nil && rejects == nil
+	keep := make([]bool, len(df.Data))
+	var rejected []rejectedRow
+	var (
+		mu        sync.Mutex
+		errs      []rowError
+		filtered  atomic.Bool
+		failedRow atomic.Int64
+	)
+	failedRow.Store(int64(len(df.Data)))
+
+	workerOpts := *opts
+	workerOpts.MaxWorkers = min(opts.MaxWorkers, (len(df.Data)+minRowsPerWorker-1)/minRowsPerWorker)
+
+	// Every chunk counts into its own slice, merged when the chunk is done
+	counts := make([]ColumnStats, len(df.Headers))
+	err := processChunks(&workerOpts, len(df.Data), func(from, to int) {
+		local := make([]ColumnStats, len(df.Headers))
+		var before []string
+		var localRejects []rejectedRow
+		defer func() {
+			mu.Lock()
+			for j := range counts {
+				counts[j].add(local[j])
+			}
+			rejected = append(rejected, localRejects...)
+			mu.Unlock()
+		}()
+
+		for i := from; i < to; i++ {
+			// In strict mode rows after the first failing row are skipped; rows before it
+			// are still processed so the reported error is the same as in a serial run.
+			if strict && int64(i) > failedRow.Load() {
+				return
+			}
+			keep[i] = true
+			for _, step := range bound {
+				if step.column == -1 {
+					before = append(before[:0], df.Data[i]...)
+				} else {
+					before = append(before[:0], df.Data[i][step.column])
+				}
+
+				ok, err := step.fn(df, i)
+				if err != nil {
+					if step.column != -1 {
+						local[step.column].ParseFailures++
+					}
+					if rejects != nil {
+						localRejects = append(localRejects, p.rejectedRow(df, i, step.index, err.Error()))
+						keep[i] = false
+						filtered.Store(true)
+						break
+					}
+					mu.Lock()
+					errs = append(errs, rowError{row: i, step: step.index, err: err})
+					mu.Unlock()
+					if strict {
+						for cur := failedRow.Load(); int64(i) < cur && !failedRow.CompareAndSwap(cur, int64(i)); cur = failedRow.Load() {
+						}
+						return
+					}
+					continue
+				}
+
+				if step.column == -1 {
+					for j, value := range before {
+						local[j].countChange(value, df.Data[i][j])
+					}
+				} else {
+					local[step.column].countChange(before[0], df.Data[i][step.column])
+				}
+				if !ok {
+					if step.column != -1 {
+						local[step.column].RowsDropped++
+					}
+					if rejects != nil {
+						localRejects = append(localRejects, p.rejectedRow(df, i, step.index, p.steps[step.index].reason))
+					}
+					keep[i] = false
+					filtered.Store(true)
+					break
+				}
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	stats.addColumns(df.Headers, counts)
+
+	if len(errs) > 0 {
+		// Report the first failing row of every step, in step order
+		slices.SortFunc(errs, func(a, b rowError) int {
+			if a.step != b.step {
+				return a.step - b.step
+			}
+			return a.row - b.row
+		})
+		if strict {
+			first := slices.MinFunc(errs, func(a, b rowError) int { return a.row - b.row })
+			return p.handleError(first.step, first.err)
+		}
+		for i, e := range errs {
+			if i > 0 && errs[i-1].step == e.step {
+				continue
+			}
+			if err := p.handleError(e.step, e.err); err != nil {
+				return err
+			}
+		}
+	}
+
+	if rejects != nil {
+		rejects.add(df.Headers, rejected)
+	}
+	if filtered.Load() {
+		df.retainRows(keep)
+	}
+	p.finish(df, bound)
+	return nil
+}
+
+// finish runs the completion hooks of the bound steps
+func (p *Pipeline) finish(df *DataFrame, bound []boundStep) {
+	for _, step := range bound {
+		if done := p.steps[step.index].done; done != nil {
+			done(df)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/formats"
+)
+
+func pipelineTestDataFrame(rows int) *DataFrame {
+	data := make([][]string, rows)
+	for i := range data {
+		date := fmt.Sprintf("2023-%02d-%02d", i%12+1, i%28+1)
+		age := fmt.Sprint(i % 150)
+		if i%7 == 0 {
+			age = ""
+		}
+		data[i] = []string{fmt.Sprintf("  Name %d ", i), date, age}
+	}
+	df, _ := NewDataFrame([]string{"name", "created_at", "age"}, data)
+	return df
+}
+
+func TestPipelineMatchesSerialOperations(t *testing.T) {
+	expected := pipelineTestDataFrame(5000)
+	expected.TrimColumns()
+	if _, err := expected.CleanDates("created_at", "02.01.2006"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expected.ReplaceNulls("age", "0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expected.NormalizeCase("name", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expected.FilterOutliers("age", 0, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, workers := range []int{1, 4} {
+		df, err := NewPipeline().
+			Trim().
+			CleanDates("created_at", "02.01.2006").
+			ReplaceNulls("age", "0").
+			NormalizeCase("name", true).
+			FilterOutliers("age", 0, 100).
+			Run(pipelineTestDataFrame(5000), WithMaxWorkers(workers))
+		if err != nil {
+			t.Fatalf("workers=%d: Run error: %v", workers, err)
+		}
+		if !reflect.DeepEqual(df.Data, expected.Data) {
+			t.Errorf("workers=%d: result differs from serial operations", workers)
+		}
+		if df.Types["created_at"] != TypeDate {
+			t.Errorf("workers=%d: created_at type = %v, want TypeDate", workers, df.Types["created_at"])
+		}
+	}
+}
+
+func TestPipelineFrameSteps(t *testing.T) {
+	df, _ := NewDataFrame([]string{"full", "age"}, [][]string{
+		{" Ada Lovelace ", "36"},
+		{"alan turing", ""},
+	})
+
+	result, err := NewPipeline().
+		Trim().
+		SplitColumn("full", " ", []string{"first", "last"}).
+		NormalizeCase("last", true).
+		RenameColumn("age", "years").
+		ReplaceNulls("years", "?").
+		Run(df)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	want := [][]string{{"Ada", "LOVELACE", "36"}, {"alan", "TURING", "?"}}
+	if !reflect.DeepEqual(result.Headers, []string{"first", "last", "years"}) {
+		t.Errorf("unexpected headers: %v", result.Headers)
+	}
+	if !reflect.DeepEqual(result.Data, want) {
+		t.Errorf("expected %v, got %v", want, result.Data)
+	}
+}
+
+func TestPipelineSteps(t *testing.T) {
+	p := NewPipeline().Trim().CleanWithRegex("a", `\d`, "").Then("custom", func(df *DataFrame) (*DataFrame, error) {
+		return df, nil
+	})
+	if got := p.Steps(); !reflect.DeepEqual(got, []string{"trim", "clean_regex", "custom"}) {
+		t.Errorf("unexpected steps: %v", got)
+	}
+}
+
+func TestPipelineWholeDatasetSteps(t *testing.T) {
+	// Every action with whether it needs all rows at once. A new action fails this test until it
+	// is added here, so streaming never runs a whole-dataset step on chunks by mistake.
+	actions := map[string]struct {
+		action string
+		whole  bool
+	}{
+		"trim":                      {"trim", false},
+		"sanitize_control_chars":    {"sanitize_control_chars", false},
+		"normalize_dates":           {"normalize_dates:created_at=2006-01-02", false},
+		"replace_nulls":             {"replace_nulls:age=0", false},
+		"fill_nulls":                {"fill_nulls:age=mean", true},
+		"fill_forward":              {"fill_forward:age", true},
+		"fill_backward":             {"fill_backward:age", true},
+		"normalize_numeric":         {"normalize_numeric:age=minmax", true},
+		"bin":                       {"bin:age=0,18,65", false},
+		"bin_equal_width":           {"bin_equal_width:age=3", true},
+		"bin_equal_frequency":       {"bin_equal_frequency:age=3", true},
+		"normalize_numeric_formats": {"normalize_numeric_formats:age", false},
+		"convert_units":             {"convert_units:age=kg=lb", false},
+		"fake_column":               {"fake_column:name=email=1", false},
+		"normalize_case":            {"normalize_case:name=upper", false},
+		"strip_prefix":              {"strip_prefix:name=Name", false},
+		"strip_suffix":              {"strip_suffix:name=x", false},
+		"pad_left":                  {"pad_left:age=3", false},
+		"map_values":                {"map_values:name=a:b", false},
+		"clean_regex":               {`clean_regex:name=\d=`, false},
+		"split_column":              {"split_column:created_at=-=year,rest", false},
+		"filter_outliers":           {"filter_outliers:age=0=120", false},
+		"normalize_headers":         {"normalize_headers", false},
+		"drop_constant_columns":     {"drop_constant_columns", true},
+		"add_row_number":            {"add_row_number:row", true},
+		"coalesce_columns":          {"coalesce_columns:any=age,name", false},
+		"copy_column":               {"copy_column:name=name_copy", false},
+		"swap_columns":              {"swap_columns:name=age", false},
+		"apply_profile":             {"apply_profile:name=email_standard", false},
+		"drop_duplicates":           {"drop_duplicates", true},
+		"dedupe_fuzzy":              {"dedupe_fuzzy:name=0.9", true},
+		"collation":                 {"collation:tr", false},
+		"pivot":                     {"pivot:name=created_at=age", true},
+		"melt":                      {"melt:name=age", false},
+		"allowed_values":            {"allowed_values:name=a,b", false},
+		"denied_values":             {"denied_values:name=a,b", false},
+		"sample":                    {"sample:10", true},
+		"sample_fraction":           {"sample_fraction:0.5", true},
+		"sample_stratified":         {"sample_stratified:age=0.5", true},
+		"assert_sorted":             {"assert_sorted:age", true},
+		"assert_unique":             {"assert_unique", true},
+		"assert_non_null":           {"assert_non_null", false},
+	}
+	for name := range actionUsages {
+		tt, ok := actions[name]
+		if !ok {
+			t.Errorf("action %s is not classified: add it to this test", name)
+			continue
+		}
+		p := NewPipeline()
+		if err := p.Action(tt.action); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.action, err)
+			continue
+		}
+		if whole := len(p.WholeDatasetSteps()) > 0; whole != tt.whole {
+			t.Errorf("%s: WholeDatasetSteps() = %v, expected whole dataset %v", tt.action, p.WholeDatasetSteps(), tt.whole)
+		}
+	}
+
+	p := NewPipeline().Trim().SortBy([]SortKey{{Column: "age"}}).ReplaceNulls("age", "0").GroupBy([]string{"age"})
+	if got := p.WholeDatasetSteps(); !reflect.DeepEqual(got, []string{"sort", "group_by"}) {
+		t.Errorf("unexpected whole dataset steps: %v", got)
+	}
+}
+
+func TestPipelineStrictError(t *testing.T) {
+	df := pipelineTestDataFrame(5000)
+	df.Data[3000][1] = "not a date"
+	df.Data[4000][1] = "also not a date"
+
+	_, err := NewPipeline().Trim().CleanDates("created_at", "2006-01-02").Run(df, WithMaxWorkers(4))
+
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) {
+		t.Fatalf("expected StepError, got %v", err)
+	}
+	if stepErr.Index != 1 || stepErr.Step != "clean_dates" {
+		t.Errorf("unexpected step: %d %s", stepErr.Index, stepErr.Step)
+	}
+	if !strings.Contains(err.Error(), "row 3000") {
+		t.Errorf("expected the first failing row to be reported, got %v", err)
+	}
+
+	_, err = NewPipeline().ReplaceNulls("missing", "x").Run(pipelineTestDataFrame(10))
+	if !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestPipelineOnError(t *testing.T) {
+	df := pipelineTestDataFrame(100)
+	df.Data[10][1] = "not a date"
+	df.Data[20][1] = "not a date"
+
+	var reported []string
+	result, err := NewPipeline().
+		ReplaceNulls("missing", "x").
+		CleanDates("created_at", "02.01.2006").
+		ReplaceNulls("age", "0").
+		OnError(func(err *StepError) error {
+			reported = append(reported, err.Step)
+			return nil
+		}).
+		Run(df)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	if !reflect.DeepEqual(reported, []string{"replace_nulls", "clean_dates"}) {
+		t.Errorf("unexpected reported steps: %v", reported)
+	}
+	if result.Data[10][1] != "not a date" {
+		t.Errorf("failing value should be unchanged, got %q", result.Data[10][1])
+	}
+	if result.Data[11][1] != "12.12.2023" {
+		t.Errorf("other values should be converted, got %q", result.Data[11][1])
+	}
+	if result.Data[0][2] != "0" {
+		t.Errorf("later steps should still run, got %q", result.Data[0][2])
+	}
+
+	stop := errors.New("stop")
+	_, err = NewPipeline().CleanDates("created_at", "2006-01-02").
+		OnError(func(*StepError) error { return stop }).
+		Run(pipelineTestDataFrame(100))
+	if err != nil {
+		t.Errorf("no error expected for valid dates, got %v", err)
+	}
+	_, err = NewPipeline().CleanDates("missing", "2006-01-02").
+		OnError(func(*StepError) error { return stop }).
+		Run(pipelineTestDataFrame(100))
+	if !errors.Is(err, stop) {
+		t.Errorf("expected handler error, got %v", err)
+	}
+}
+
+func TestPipelineCopyOnWrite(t *testing.T) {
+	original := pipelineTestDataFrame(10)
+	cp := original.Copy()
+
+	if _, err := NewPipeline().Trim().FilterOutliers("age", 0, 5).Run(cp); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if original.Data[1][0] != "  Name 1 " || len(original.Data) != 10 {
+		t.Error("original DataFrame was modified")
+	}
+}
+
+func TestPipelineAsStreamStep(t *testing.T) {
+	source := pipelineTestDataFrame(250)
+	expected, err := NewPipeline().Trim().ReplaceNulls("age", "0").Run(source.DeepCopy())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sink := &collectWriter{}
+	pipeline := NewPipeline().Trim().ReplaceNulls("age", "0")
+	reader := formats.NewRawRowReader(source.Headers, source.Data)
+	if _, err := NewStream(reader, WithChunkSize(100)).Then(pipeline).Run(sink); err != nil {
+		t.Fatalf("stream error: %v", err)
+	}
+	if !reflect.DeepEqual(sink.rows, expected.Data) {
+		t.Error("streamed result differs from Run")
+	}
+}
+
+func benchmarkPipelineSteps() *Pipeline {
+	return NewPipeline().
+		Trim().
+		ReplaceNulls("col1", "n/a").
+		NormalizeCase("col2", true).
+		NormalizeCase("col3", false).
+		ReplaceNulls("col4", "n/a")
+}
+
+func BenchmarkPipelineSequential(b *testing.B) {
+	benchmarkOp(b, func(df *DataFrame) error {
+		df.TrimColumns()
+		if _, err := df.ReplaceNulls("col1", "n/a"); err != nil {
+			return err
+		}
+		if _, err := df.NormalizeCase("col2", true); err != nil {
+			return err
+		}
+		if _, err := df.NormalizeCase("col3", false); err != nil {
+			return err
+		}
+		_, err := df.ReplaceNulls("col4", "n/a")
+		return err
+	})
+}
+
+func BenchmarkPipelineFused(b *testing.B) {
+	benchmarkOp(b, func(df *DataFrame) error {
+		_, err := benchmarkPipelineSteps().Run(df, WithMaxWorkers(1))
+		return err
+	})
+}
+
+func BenchmarkPipelineFusedParallel(b *testing.B) {
+	benchmarkOp(b, func(df *DataFrame) error {
+		_, err := benchmarkPipelineSteps().Run(df)
+		return err
+	})
+}
@@ -0,0 +1,172 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Pivot reshapes the DataFrame from long to wide format and returns a new DataFrame: one row per
+// distinct value of the index column and one column per distinct value of the key column, holding
+// the value column, e.g.
+//
+//	// date,metric,value  ->  date,temperature,humidity
+//	wide, err := df.Pivot("date", "metric", "value")
+//
+// Rows and columns are in order of first appearance. Cells without a value are empty and rows with
+// an empty key are skipped. Two rows with the same index and key are an error, as is a key that
+// equals the name of the index column. The key columns have the type of the value column; the
+// DataFrame is not modified.
+func (df *DataFrame) Pivot(indexCol, keyCol, valueCol string) (*DataFrame, error) {
+	indexIndex, err := df.requireColumn(indexCol)
+	if err != nil {
+		return nil, err
+	}
+	keyIndex, err := df.requireColumn(keyCol)
+	if err != nil {
+		return nil, err
+	}
+	valueIndex, err := df.requireColumn(valueCol)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := []string{indexCol}
+	keyColumns := make(map[string]int)
+	rowOf := make(map[string]int)
+	filled := make(map[[2]int]bool)
+	var data [][]string
+	for _, row := range df.Data {
+		key := row[keyIndex]
+		if key == "" {
+			continue
+		}
+		col, ok := keyColumns[key]
+		if !ok {
+			if key == indexCol {
+				return nil, fmt.Errorf("pivot: key %q equals the index column", key)
+			}
+			col = len(headers)
+			keyColumns[key] = col
+			headers = append(headers, key)
+		}
+
+		index := row[indexIndex]
+		i, ok := rowOf[index]
+		if !ok {
+			i = len(data)
+			rowOf[index] = i
+			data = append(data, []string{index})
+		}
+		if len(data[i]) <= col {
+			data[i] = append(data[i], make([]string, col+1-len(data[i]))...)
+		}
+		if filled[[2]int{i, col}] {
+			return nil, fmt.Errorf("pivot: duplicate value for %s=%s, %s=%s", indexCol, index, keyCol, key)
+		}
+		filled[[2]int{i, col}] = true
+		data[i][col] = row[valueIndex]
+	}
+	for i := range data {
+		data[i] = append(data[i], make([]string, len(headers)-len(data[i]))...)
+	}
+
+	result, err := NewDataFrame(headers, data)
+	if err != nil {
+		return nil, err
+	}
+	result.Types[indexCol] = df.Types[indexCol]
+	for key := range keyColumns {
+		result.Types[key] = df.Types[valueCol]
+	}
+	if slices.Contains(df.stringColumns, indexCol) {
+		result.stringColumns = append(result.stringColumns, indexCol)
+	}
+	if slices.Contains(df.stringColumns, valueCol) {
+		result.stringColumns = append(result.stringColumns, headers[1:]...)
+	}
+	return result, nil
+}
+
+// Melt reshapes the DataFrame from wide to long format and returns a new DataFrame, the reverse
+// of Pivot: every value column of every row becomes a row with the id columns, the name of the
+// value column in varName and its value in valueName, e.g.
+//
+//	// id,q1,q2  ->  id,quarter,revenue
+//	long, err := df.Melt([]string{"id"}, []string{"q1", "q2"}, "quarter", "revenue")
+//
+// Without value columns, all columns that are not id columns are melted. An empty varName or
+// valueName defaults to "variable" and "value". The rows of one input row stay together, in the
+// order of the value columns. The value column has the type shared by the melted columns, string
+// if they differ; the DataFrame is not modified.
+func (df *DataFrame) Melt(idColumns, valueColumns []string, varName, valueName string) (*DataFrame, error) {
+	if varName == "" {
+		varName = "variable"
+	}
+	if valueName == "" {
+		valueName = "value"
+	}
+
+	idIndices := make([]int, len(idColumns))
+	for i, column := range idColumns {
+		index, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		idIndices[i] = index
+	}
+	if len(valueColumns) == 0 {
+		for _, header := range df.Headers {
+			if !slices.Contains(idColumns, header) {
+				valueColumns = append(valueColumns, header)
+			}
+		}
+		if len(valueColumns) == 0 {
+			return nil, errors.New("melt: no columns to melt")
+		}
+	}
+	valueIndices, err := df.columnIndices(valueColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := append(slices.Clone(idColumns), varName, valueName)
+	for i, header := range headers {
+		if slices.Contains(headers[:i], header) {
+			return nil, fmt.Errorf("melt: duplicate output column: %s", header)
+		}
+	}
+
+	data := make([][]string, 0, len(df.Data)*len(valueIndices))
+	for _, row := range df.Data {
+		for k, valueIndex := range valueIndices {
+			melted := make([]string, 0, len(headers))
+			for _, idIndex := range idIndices {
+				melted = append(melted, row[idIndex])
+			}
+			data = append(data, append(melted, valueColumns[k], row[valueIndex]))
+		}
+	}
+
+	result, err := NewDataFrame(headers, data)
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range idColumns {
+		result.Types[column] = df.Types[column]
+		if slices.Contains(df.stringColumns, column) {
+			result.stringColumns = append(result.stringColumns, column)
+		}
+	}
+	for i, column := range valueColumns {
+		if i == 0 || df.Types[column] == result.Types[valueName] {
+			result.Types[valueName] = df.Types[column]
+		} else {
+			result.Types[valueName] = TypeString
+		}
+		if slices.Contains(df.stringColumns, column) && !slices.Contains(result.stringColumns, valueName) {
+			result.stringColumns = append(result.stringColumns, valueName)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,150 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPivot(t *testing.T) {
+	df, _ := NewDataFrame([]string{"date", "metric", "value"}, [][]string{
+		{"2024-01-01", "temperature", "21.5"},
+		{"2024-01-01", "humidity", "40"},
+		{"2024-01-02", "temperature", "19"},
+		{"2024-01-02", "", "ignored"},
+		{"2024-01-03", "humidity", ""},
+		{"2024-01-03", "wind", "12"},
+	})
+	df.Types["value"] = TypeFloat
+
+	wide, err := df.Pivot("date", "metric", "value")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(wide.Headers, []string{"date", "temperature", "humidity", "wind"}) {
+		t.Errorf("unexpected headers: %v", wide.Headers)
+	}
+	expected := [][]string{
+		{"2024-01-01", "21.5", "40", ""},
+		{"2024-01-02", "19", "", ""},
+		{"2024-01-03", "", "", "12"},
+	}
+	if !reflect.DeepEqual(wide.Data, expected) {
+		t.Errorf("unexpected rows: %v", wide.Data)
+	}
+	if wide.Types["humidity"] != TypeFloat {
+		t.Errorf("expected the key columns to have the value type, got %v", wide.Types["humidity"])
+	}
+	if len(df.Data) != 6 {
+		t.Error("expected the input not to change")
+	}
+
+	// An empty value still fills its cell
+	df.Data = append(df.Data, []string{"2024-01-03", "humidity", "55"})
+	if _, err := df.Pivot("date", "metric", "value"); err == nil {
+		t.Error("expected an error for a duplicate cell")
+	}
+	if _, err := df.Pivot("date", "metric", "missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	clash, _ := NewDataFrame([]string{"id", "key", "value"}, [][]string{{"1", "id", "x"}})
+	if _, err := clash.Pivot("id", "key", "value"); err == nil {
+		t.Error("expected an error for a key named like the index column")
+	}
+}
+
+func TestPipelinePivot(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "field", "value"}, [][]string{
+		{"1", "name", " Ali "}, {"1", "city", "Ankara"}, {"2", "name", "Ayşe"},
+	})
+	pipeline := NewPipeline()
+	if err := pipeline.Actions("trim", "pivot:id=field=value"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wide, err := pipeline.Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{{"1", "Ali", "Ankara"}, {"2", "Ayşe", ""}}
+	if !reflect.DeepEqual(wide.Headers, []string{"id", "name", "city"}) || !reflect.DeepEqual(wide.Data, expected) {
+		t.Errorf("unexpected result: %v %v", wide.Headers, wide.Data)
+	}
+
+	if err := NewPipeline().Action("pivot:id=field"); err == nil {
+		t.Error("expected a usage error")
+	}
+}
+
+func TestMelt(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "region", "q1", "q2"}, [][]string{
+		{"1", "north", "100", "120"},
+		{"2", "south", "90", ""},
+	})
+	df.Types["q1"], df.Types["q2"] = TypeInt, TypeInt
+
+	long, err := df.Melt([]string{"id", "region"}, nil, "quarter", "revenue")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(long.Headers, []string{"id", "region", "quarter", "revenue"}) {
+		t.Errorf("unexpected headers: %v", long.Headers)
+	}
+	expected := [][]string{
+		{"1", "north", "q1", "100"},
+		{"1", "north", "q2", "120"},
+		{"2", "south", "q1", "90"},
+		{"2", "south", "q2", ""},
+	}
+	if !reflect.DeepEqual(long.Data, expected) {
+		t.Errorf("unexpected rows: %v", long.Data)
+	}
+	if long.Types["revenue"] != TypeInt {
+		t.Errorf("expected the shared type of the melted columns, got %v", long.Types["revenue"])
+	}
+
+	df.Types["q2"] = TypeFloat
+	long, _ = df.Melt([]string{"id"}, []string{"q2", "region"}, "", "")
+	if !reflect.DeepEqual(long.Headers, []string{"id", "variable", "value"}) || len(long.Data) != 4 {
+		t.Errorf("unexpected result: %v %v", long.Headers, long.Data)
+	}
+	if long.Types["value"] != TypeString {
+		t.Errorf("expected a string column for mixed types, got %v", long.Types["value"])
+	}
+
+	// Melting a pivoted frame gives back the long rows
+	wide, _ := long.Pivot("id", "variable", "value")
+	back, _ := wide.Melt([]string{"id"}, nil, "", "")
+	if !reflect.DeepEqual(back.Data, long.Data) {
+		t.Errorf("expected the round trip to keep the rows, got %v", back.Data)
+	}
+
+	if _, err := df.Melt([]string{"id"}, []string{"missing"}, "", ""); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := df.Melt([]string{"id"}, nil, "id", ""); err == nil {
+		t.Error("expected an error for a variable column named like an id column")
+	}
+	if _, err := df.Melt(df.Headers, nil, "", ""); err == nil {
+		t.Error("expected an error without columns to melt")
+	}
+}
+
+func TestPipelineMelt(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "q1", "q2"}, [][]string{{"1", " 100 ", "120"}})
+	pipeline := NewPipeline()
+	if err := pipeline.Actions("trim", "melt:id=q1,q2=quarter=revenue"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	long, err := pipeline.Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{{"1", "q1", "100"}, {"1", "q2", "120"}}
+	if !reflect.DeepEqual(long.Headers, []string{"id", "quarter", "revenue"}) || !reflect.DeepEqual(long.Data, expected) {
+		t.Errorf("unexpected result: %v %v", long.Headers, long.Data)
+	}
+
+	if err := NewPipeline().Action("melt:id=q1=quarter"); err == nil {
+		t.Error("expected a usage error")
+	}
+}
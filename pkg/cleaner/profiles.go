@@ -0,0 +1,218 @@
+package cleaner
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	profilesMu sync.RWMutex
+	// columnProfiles, named lists of column steps applied with ApplyProfile
+	columnProfiles = map[string][]string{
+		"email_standard": {"trim", "lower", "validate=@email"},
+		"money_try":      {"trim", "numeric=tr", "round=2"},
+	}
+)
+
+// RegisterProfile adds a column profile, or replaces the one with the same name: a list of steps
+// applied to one column by ApplyProfile, in order. The steps are
+//
+//	trim                      remove leading and trailing whitespace
+//	sanitize                  remove control characters and replace exotic spaces
+//	lower, upper              convert the case
+//	null=value                replace empty values
+//	regex=pattern=replacement replace the matches of a regex or a named pattern such as @email
+//	validate=pattern          empty the values that do not fully match the regex or named pattern
+//	numeric[=tr]              normalize formatted numbers like NormalizeNumericFormats, with ','
+//	                          thousands and '.' decimals whatever the Locale; with tr, '.'
+//	                          separates thousands and ',' decimals, e.g. "₺1.234,5"
+//	round=decimals            round numbers to the decimals, halves away from zero
+//
+// Names contain only letters, digits and '_'.
+func RegisterProfile(name string, steps []string) error {
+	if !opNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name: %q", name)
+	}
+	if _, _, err := compileProfile(steps); err != nil {
+		return fmt.Errorf("profile %s: %w", name, err)
+	}
+
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+	columnProfiles[name] = slices.Clone(steps)
+	return nil
+}
+
+// LoadProfiles registers the column profiles of a YAML or JSON file that maps profile names to
+// their steps, e.g.
+//
+//	email_standard: [trim, lower, validate=@email]
+//	sku: [trim, upper, "regex=[^A-Z0-9]="]
+//
+// Nothing is registered if one of the profiles is invalid.
+func LoadProfiles(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("profiles read error: %w", err)
+	}
+	var profiles map[string][]string
+	if err := yaml.Unmarshal(data, &profiles); err != nil {
+		return fmt.Errorf("invalid profiles: %w", err)
+	}
+
+	for name, steps := range profiles {
+		if !opNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid profile name: %q", name)
+		}
+		if _, _, err := compileProfile(steps); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+	}
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+	for name, steps := range profiles {
+		columnProfiles[name] = steps
+	}
+	return nil
+}
+
+// Profile returns the steps of the column profile
+func Profile(name string) ([]string, bool) {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+	steps, ok := columnProfiles[name]
+	return slices.Clone(steps), ok
+}
+
+// ApplyProfile applies the steps of the column profile to the column, e.g.
+// df.ApplyProfile("email", "email_standard"). A value that a numeric step cannot parse is an error.
+func (df *DataFrame) ApplyProfile(column, name string) (*DataFrame, error) {
+	return NewPipeline().ApplyProfile(column, name).Run(df, WithMaxWorkers(1))
+}
+
+// ApplyProfile applies the steps of the column profile to the column, see RegisterProfile
+func (p *Pipeline) ApplyProfile(column, name string) *Pipeline {
+	numeric := false
+	p.rowStep("apply_profile", column, func(df *DataFrame) (rowFunc, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		steps, ok := Profile(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown profile: %s", name)
+		}
+		var fns []func(string) (string, error)
+		fns, numeric, err = compileProfile(steps)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", name, err)
+		}
+		return func(df *DataFrame, i int) (bool, error) {
+			value := df.Data[i][colIndex]
+			for _, fn := range fns {
+				var err error
+				if value, err = fn(value); err != nil {
+					return true, fmt.Errorf("row %d, column %s: %w", i, column, err)
+				}
+			}
+			df.setCell(i, colIndex, value)
+			return true, nil
+		}, nil
+	})
+	p.steps[len(p.steps)-1].done = func(df *DataFrame) {
+		if numeric {
+			df.Types[column] = TypeFloat
+		}
+	}
+	return p
+}
+
+// compileProfile returns the functions of the profile steps and whether they produce numbers
+func compileProfile(steps []string) ([]func(string) (string, error), bool, error) {
+	fns := make([]func(string) (string, error), len(steps))
+	numeric := false
+	for k, step := range steps {
+		name, arg, _ := strings.Cut(step, "=")
+		switch name {
+		case "trim":
+			fns[k] = func(s string) (string, error) { return strings.TrimSpace(s), nil }
+		case "sanitize":
+			fns[k] = func(s string) (string, error) { return sanitizeControlChars(s), nil }
+		case "lower":
+			fns[k] = func(s string) (string, error) { return toLowerCase(s), nil }
+		case "upper":
+			fns[k] = func(s string) (string, error) { return toUpperCase(s), nil }
+		case "null":
+			fns[k] = func(s string) (string, error) {
+				if s == "" {
+					return arg, nil
+				}
+				return s, nil
+			}
+		case "regex":
+			pattern, replacement, ok := strings.Cut(arg, "=")
+			if !ok {
+				return nil, false, fmt.Errorf("step %d: expected regex=pattern=replacement", k)
+			}
+			re, err := compileRegex(pattern)
+			if err != nil {
+				return nil, false, fmt.Errorf("step %d: %w", k, err)
+			}
+			fns[k] = func(s string) (string, error) { return re.ReplaceAllString(s, replacement), nil }
+		case "validate":
+			pattern, err := resolvePattern(arg)
+			if err != nil {
+				return nil, false, fmt.Errorf("step %d: %w", k, err)
+			}
+			re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+			if err != nil {
+				return nil, false, fmt.Errorf("step %d: invalid regex pattern: %w", k, err)
+			}
+			fns[k] = func(s string) (string, error) {
+				if s != "" && !re.MatchString(s) {
+					return "", nil
+				}
+				return s, nil
+			}
+		case "numeric":
+			if arg != "" && arg != "tr" {
+				return nil, false, fmt.Errorf("step %d: unknown number locale: %s", k, arg)
+			}
+			decimalComma := arg == "tr"
+			fns[k] = func(s string) (string, error) {
+				if s == "" {
+					return s, nil
+				}
+				return normalizeNumber(s, decimalComma)
+			}
+			numeric = true
+		case "round":
+			decimals, err := strconv.Atoi(arg)
+			if err != nil || decimals < 0 {
+				return nil, false, fmt.Errorf("step %d: invalid decimals: %s", k, arg)
+			}
+			fns[k] = func(s string) (string, error) {
+				if s == "" {
+					return s, nil
+				}
+				r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+				if !ok {
+					return "", fmt.Errorf("not a number: %s", s)
+				}
+				return r.FloatString(decimals), nil
+			}
+			numeric = true
+		default:
+			return nil, false, fmt.Errorf("step %d: unknown profile step: %s", k, name)
+		}
+	}
+	return fns, numeric, nil
+}
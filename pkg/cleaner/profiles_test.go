@@ -0,0 +1,89 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestApplyProfile(t *testing.T) {
+	df, _ := NewDataFrame([]string{"email", "price"}, [][]string{
+		{"  Ali@Example.COM ", "₺1.234,567"},
+		{"not an email", ""},
+		{"", "12,5"},
+	})
+
+	df, err := df.ApplyProfile("email", "email_standard")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	df, err = df.ApplyProfile("price", "money_try")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := [][]string{{"ali@example.com", "1234.57"}, {"", ""}, {"", "12.50"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected data: %v", df.Data)
+	}
+	if df.Types["price"] != TypeFloat {
+		t.Errorf("expected a float column, got %v", df.Types["price"])
+	}
+
+	if _, err := df.ApplyProfile("email", "missing"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+	bad, _ := NewDataFrame([]string{"price"}, [][]string{{"abc"}})
+	if _, err := bad.ApplyProfile("price", "money_try"); err == nil {
+		t.Error("expected an error for a value that is not a number")
+	}
+}
+
+func TestRegisterProfile(t *testing.T) {
+	if err := RegisterProfile("test_sku", []string{"trim", "upper", "regex=[^A-Z0-9]=", "null=UNKNOWN"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pipeline, err := ParsePipeline([]byte(`{"actions": ["apply_profile:sku=test_sku"]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	df, _ := NewDataFrame([]string{"sku"}, [][]string{{" ab-12 "}, {""}})
+	df, err = pipeline.Run(df)
+	if err != nil || !reflect.DeepEqual(df.Data, [][]string{{"AB12"}, {"UNKNOWN"}}) {
+		t.Errorf("unexpected result: %v %v", df, err)
+	}
+
+	if err := RegisterProfile("test_bad", []string{"explode"}); err == nil {
+		t.Error("expected an error for an unknown step")
+	}
+	if err := RegisterProfile("test_bad", []string{"round=x"}); err == nil {
+		t.Error("expected an error for invalid decimals")
+	}
+	if err := NewPipeline().Action("apply_profile:sku=missing"); err == nil {
+		t.Error("expected an error for an unknown profile in an action")
+	}
+}
+
+func TestLoadProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.yaml")
+	os.WriteFile(path, []byte("test_name: [trim, sanitize, upper]\ntest_code: [\"validate=[A-Z]{3}\"]\n"), 0644)
+	if err := LoadProfiles(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if steps, ok := Profile("test_code"); !ok || !reflect.DeepEqual(steps, []string{"validate=[A-Z]{3}"}) {
+		t.Errorf("unexpected profile: %v", steps)
+	}
+	df, _ := NewDataFrame([]string{"code"}, [][]string{{"ABC"}, {"ABCD"}})
+	df, _ = df.ApplyProfile("code", "test_code")
+	if !reflect.DeepEqual(df.Data, [][]string{{"ABC"}, {""}}) {
+		t.Errorf("expected full matches only, got %v", df.Data)
+	}
+
+	os.WriteFile(path, []byte("test_ok: [trim]\ntest_broken: [nope]\n"), 0644)
+	if err := LoadProfiles(path); err == nil {
+		t.Error("expected an error for an invalid profile")
+	}
+	if _, ok := Profile("test_ok"); ok {
+		t.Error("expected nothing to be registered")
+	}
+}
@@ -0,0 +1,151 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ColumnQuality scores a column on metrics between 0 (worst) and 1 (best). A column without
+// any non-empty value scores 0 on every metric.
+type ColumnQuality struct {
+	Type         Type    `json:"type"`         // Type the values are checked against
+	Completeness float64 `json:"completeness"` // Share of non-empty cells
+	Validity     float64 `json:"validity"`     // Share of non-empty values that conform to Type
+	Uniqueness   float64 `json:"uniqueness"`   // Distinct values per non-empty value
+	Consistency  float64 `json:"consistency"`  // Share of non-empty values with the most common shape
+	Score        float64 `json:"score"`        // Mean of the four metrics
+}
+
+// QualityScore is the data quality of a DataFrame
+type QualityScore struct {
+	Score   float64                  `json:"score"` // Mean of the column scores
+	Columns map[string]ColumnQuality `json:"columns"`
+}
+
+// QualityScore scores every column on completeness, validity, uniqueness and consistency, and
+// the DataFrame on the mean of its column scores.
+//
+// Values are checked against the column type. String columns whose values are mostly numbers or
+// booleans are checked against that type, so a stray "n/a" in a numeric column lowers its validity.
+// The shape of a value replaces upper case letters with A, other letters with a and digits with 9,
+// collapsing repeats: "2024-01-02" and "02/01/2024" have the shapes "9-9-9" and "9/9/9".
+func (df *DataFrame) QualityScore() QualityScore {
+	score := QualityScore{Columns: make(map[string]ColumnQuality, len(df.Headers))}
+	values := make([]string, 0, len(df.Data))
+	for colIndex, header := range df.Headers {
+		values = values[:0]
+		for _, row := range df.Data {
+			if value := strings.TrimSpace(row[colIndex]); !isNull(value) {
+				values = append(values, value)
+			}
+		}
+		quality := columnQuality(values, len(df.Data), df.Types[header])
+		score.Columns[header] = quality
+		score.Score += quality.Score
+	}
+	if len(df.Headers) > 0 {
+		score.Score /= float64(len(df.Headers))
+	}
+	return score
+}
+
+// columnQuality scores the non-empty values of a column with the given number of rows
+func columnQuality(values []string, rows int, t Type) ColumnQuality {
+	if t == TypeString {
+		t = dominantType(values)
+	}
+	quality := ColumnQuality{Type: t}
+	if len(values) == 0 {
+		return quality
+	}
+
+	valid := 0
+	distinct := make(map[string]struct{}, len(values))
+	shapes := make(map[string]int)
+	for _, value := range values {
+		if conformsTo(value, t) {
+			valid++
+		}
+		distinct[value] = struct{}{}
+		shapes[valueShape(value)]++
+	}
+	common := 0
+	for _, n := range shapes {
+		common = max(common, n)
+	}
+
+	n := float64(len(values))
+	quality.Completeness = n / float64(rows)
+	quality.Validity = float64(valid) / n
+	quality.Uniqueness = float64(len(distinct)) / n
+	quality.Consistency = float64(common) / n
+	quality.Score = (quality.Completeness + quality.Validity + quality.Uniqueness + quality.Consistency) / 4
+	return quality
+}
+
+// dominantType returns the type of more than half of the values: float for numbers (int if none
+// has a fraction), bool, or else string
+func dominantType(values []string) Type {
+	ints, floats, bools := 0, 0, 0
+	for _, value := range values {
+		switch {
+		case isInt(value):
+			ints++
+		case isFloat(value):
+			floats++
+		case value == "true" || value == "false":
+			bools++
+		}
+	}
+	switch half := len(values) / 2; {
+	case ints > half:
+		return TypeInt
+	case ints+floats > half:
+		return TypeFloat
+	case bools > half:
+		return TypeBool
+	}
+	return TypeString
+}
+
+// conformsTo reports whether value can be read as type t. Dates are tried with the common layouts.
+func conformsTo(value string, t Type) bool {
+	var err error
+	switch t {
+	case TypeInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case TypeFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case TypeBool:
+		_, err = strconv.ParseBool(value)
+	case TypeDate:
+		_, err = parseDate(value, time.DateOnly)
+	case TypeJSON:
+		return json.Valid([]byte(value))
+	}
+	return err == nil
+}
+
+// valueShape returns the shape of a value, e.g. "Aa 9" for "Ali 42"
+func valueShape(value string) string {
+	var b strings.Builder
+	last := rune(-1)
+	for _, r := range value {
+		switch {
+		case unicode.IsUpper(r):
+			r = 'A'
+		case unicode.IsLetter(r):
+			r = 'a'
+		case unicode.IsDigit(r):
+			r = '9'
+		}
+		if r != last {
+			b.WriteRune(r)
+			last = r
+		}
+	}
+	return b.String()
+}
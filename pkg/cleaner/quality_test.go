@@ -0,0 +1,75 @@
+package cleaner
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQualityScore(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "age", "city", "notes"}, [][]string{
+		{"1", "30", "Istanbul", ""},
+		{"2", "n/a", "istanbul", ""},
+		{"3", "25", "Ankara", ""},
+		{"4", "", "Ankara", ""},
+	})
+
+	score := df.QualityScore()
+
+	expected := map[string]ColumnQuality{
+		"id":    {Type: TypeInt, Completeness: 1, Validity: 1, Uniqueness: 1, Consistency: 1, Score: 1},
+		"age":   {Type: TypeInt, Completeness: 0.75, Validity: 2.0 / 3, Uniqueness: 1, Consistency: 2.0 / 3},
+		"city":  {Type: TypeString, Completeness: 1, Validity: 1, Uniqueness: 0.75, Consistency: 0.75},
+		"notes": {Type: TypeString},
+	}
+	for column, want := range expected {
+		got := score.Columns[column]
+		if column != "id" {
+			want.Score = (want.Completeness + want.Validity + want.Uniqueness + want.Consistency) / 4
+		}
+		if got.Type != want.Type || !near(got.Completeness, want.Completeness) || !near(got.Validity, want.Validity) ||
+			!near(got.Uniqueness, want.Uniqueness) || !near(got.Consistency, want.Consistency) || !near(got.Score, want.Score) {
+			t.Errorf("column %s: expected %+v, got %+v", column, want, got)
+		}
+	}
+
+	var total float64
+	for _, c := range score.Columns {
+		total += c.Score
+	}
+	if !near(score.Score, total/4) {
+		t.Errorf("expected overall score %v, got %v", total/4, score.Score)
+	}
+}
+
+func TestQualityScore_DeclaredType(t *testing.T) {
+	df, _ := NewDataFrame([]string{"joined"}, [][]string{{"2024-01-02"}, {"02/01/2024"}, {"soon"}})
+	df.Types["joined"] = TypeDate
+
+	got := df.QualityScore().Columns["joined"]
+	if !near(got.Validity, 2.0/3) {
+		t.Errorf("expected 2 of 3 dates to be valid, got %v", got.Validity)
+	}
+	if !near(got.Consistency, 1.0/3) {
+		t.Errorf("expected every date to have its own shape, got %v", got.Consistency)
+	}
+}
+
+func TestValueShape(t *testing.T) {
+	cases := map[string]string{
+		"2024-01-02": "9-9-9",
+		"02/01/2024": "9/9/9",
+		"Ali 42":     "Aa 9",
+		"ŞENOL":      "A",
+		"":           "",
+	}
+	for value, want := range cases {
+		if got := valueShape(value); got != want {
+			t.Errorf("valueShape(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+// near reports whether a and b are equal up to rounding
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,62 @@
+package cleaner
+
+import "fmt"
+
+// ReferenceCheck lists the values of a column that are missing from a reference column
+type ReferenceCheck struct {
+	Rows   []int    // Indices of the rows whose value is missing, in row order
+	Values []string // Distinct missing values, in the order they first appear
+}
+
+// Valid reports whether every value was found in the reference column
+func (c *ReferenceCheck) Valid() bool {
+	return len(c.Rows) == 0
+}
+
+// CheckReferences checks the column like a foreign key: every non-empty value must appear in
+// refColumn of ref, e.g. the country codes of orders in the code column of a countries table.
+// Empty values are not checked. The DataFrames are not modified.
+func (df *DataFrame) CheckReferences(column string, ref *DataFrame, refColumn string) (*ReferenceCheck, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := ref.referenceKeys(refColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &ReferenceCheck{}
+	seen := make(map[string]struct{})
+	for i, row := range df.Data {
+		value := row[colIndex]
+		if value == "" {
+			continue
+		}
+		if _, ok := keys[value]; ok {
+			continue
+		}
+		check.Rows = append(check.Rows, i)
+		if _, ok := seen[value]; !ok {
+			seen[value] = struct{}{}
+			check.Values = append(check.Values, value)
+		}
+	}
+	return check, nil
+}
+
+// referenceKeys returns the set of the values of the reference column
+func (df *DataFrame) referenceKeys(column string) (map[string]struct{}, error) {
+	if df == nil {
+		return nil, fmt.Errorf("reference DataFrame is nil")
+	}
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, fmt.Errorf("reference %w", err)
+	}
+	keys := make(map[string]struct{}, len(df.Data))
+	for _, row := range df.Data {
+		keys[row[colIndex]] = struct{}{}
+	}
+	return keys, nil
+}
@@ -0,0 +1,72 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckReferences(t *testing.T) {
+	countries, _ := NewDataFrame([]string{"code", "name"}, [][]string{{"TR", "Türkiye"}, {"DE", "Germany"}})
+	orders, _ := NewDataFrame([]string{"id", "country"}, [][]string{
+		{"1", "TR"},
+		{"2", "XX"},
+		{"3", ""},
+		{"4", "DE"},
+		{"5", "XX"},
+		{"6", "tr"},
+	})
+
+	check, err := orders.CheckReferences("country", countries, "code")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if check.Valid() {
+		t.Error("expected the check to fail")
+	}
+	if !reflect.DeepEqual(check.Rows, []int{1, 4, 5}) {
+		t.Errorf("expected rows [1 4 5], got %v", check.Rows)
+	}
+	if !reflect.DeepEqual(check.Values, []string{"XX", "tr"}) {
+		t.Errorf("expected values [XX tr], got %v", check.Values)
+	}
+
+	if _, err := orders.CheckReferences("country", countries, "iso"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestPipelineRequireReferences(t *testing.T) {
+	countries, _ := NewDataFrame([]string{"code"}, [][]string{{"TR"}})
+	orders, _ := NewDataFrame([]string{"id", "country"}, [][]string{{"1", "TR"}, {"2", "XX"}})
+
+	result, err := NewPipeline().RequireReferences("country", countries, "code").CaptureRejects().Run(orders)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if len(result.Data) != 1 || result.Data[0][0] != "1" {
+		t.Errorf("expected only order 1 to be kept, got %v", result.Data)
+	}
+	rejects := result.Rejects()
+	if len(rejects.Data) != 1 || rejects.Data[0][2] != "require_references(country): value not in reference column code" {
+		t.Errorf("unexpected rejects: %v", rejects.Data)
+	}
+}
+
+func TestRequireReferencesStep(t *testing.T) {
+	countries, _ := NewDataFrame([]string{"code"}, [][]string{{"TR"}})
+	step := RequireReferencesStep("country", countries, "code")
+
+	for _, data := range [][][]string{{{"TR"}, {"XX"}}, {{""}, {"TR"}}} {
+		chunk, _ := NewDataFrame([]string{"country"}, data)
+		out, err := step.ProcessChunk(chunk)
+		if err != nil {
+			t.Fatalf("ProcessChunk error: %v", err)
+		}
+		for _, row := range out.Data {
+			if row[0] == "XX" {
+				t.Errorf("expected XX to be dropped, got %v", out.Data)
+			}
+		}
+	}
+}
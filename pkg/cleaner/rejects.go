@@ -0,0 +1,82 @@
+package cleaner
+
+import (
+	"slices"
+	"sort"
+)
+
+// RejectReasonColumn is the column of a rejects DataFrame that says why each row was rejected
+const RejectReasonColumn = "reject_reason"
+
+// rejectedRow is a row dropped by a step, as it was when the step dropped it
+type rejectedRow struct {
+	row    int
+	values []string
+	reason string
+}
+
+// rejectedRow copies row i of df with the reason the step rejected it, e.g.
+// "filter_outliers(age): value outside [0, 120]"
+func (p *Pipeline) rejectedRow(df *DataFrame, i, step int, reason string) rejectedRow {
+	name := p.steps[step].name
+	if column := p.steps[step].column; column != "" {
+		name += "(" + column + ")"
+	}
+	if reason != "" {
+		name += ": " + reason
+	}
+	return rejectedRow{row: i, values: slices.Clone(df.Data[i]), reason: name}
+}
+
+// rejectCollector collects the rejected rows of all passes of a run. Passes may see different
+// columns, e.g. after SplitColumn, so every row is stored with the headers of its pass.
+type rejectCollector struct {
+	headers []string
+	rows    []map[string]string
+	reasons []string
+}
+
+// add adds the rejected rows of a pass in row order
+func (c *rejectCollector) add(headers []string, rows []rejectedRow) {
+	sort.Slice(rows, func(a, b int) bool { return rows[a].row < rows[b].row })
+	for _, header := range headers {
+		if !slices.Contains(c.headers, header) {
+			c.headers = append(c.headers, header)
+		}
+	}
+	for _, r := range rows {
+		record := make(map[string]string, len(headers))
+		for j, header := range headers {
+			record[header] = r.values[j]
+		}
+		c.rows = append(c.rows, record)
+		c.reasons = append(c.reasons, r.reason)
+	}
+}
+
+// dataFrame returns the rejected rows with the reason as the last column. Columns missing from
+// the pass a row was rejected in are empty.
+func (c *rejectCollector) dataFrame() *DataFrame {
+	headers := append(slices.Clone(c.headers), RejectReasonColumn)
+	data := make([][]string, len(c.rows))
+	for i, record := range c.rows {
+		row := make([]string, len(headers))
+		for j, header := range c.headers {
+			row[j] = record[header]
+		}
+		row[len(row)-1] = c.reasons[i]
+		data[i] = row
+	}
+
+	types := make(map[string]Type, len(headers))
+	for _, header := range headers {
+		types[header] = TypeString
+	}
+	return &DataFrame{Headers: headers, Data: data, Types: types}
+}
+
+// Rejects returns the rows dropped by the pipeline run that produced df, with a reject_reason
+// column, or nil if the pipeline did not capture rejects (see Pipeline.CaptureRejects)
+func (df *DataFrame) Rejects() *DataFrame {
+	return df.rejects
+}
@@ -0,0 +1,97 @@
+package cleaner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPipelineCaptureRejects(t *testing.T) {
+	df, _ := NewDataFrame([]string{"name", "age", "joined"}, [][]string{
+		{"Ali", "30", "2024-01-02"},
+		{"Ayşe", "200", "2024-01-03"},
+		{"Veli", "abc", "2024-01-04"},
+		{"Can", "40", "not a date"},
+	})
+
+	result, err := NewPipeline().
+		FilterOutliers("age", 0, 120).
+		CleanDates("joined", "2006-01-02").
+		CaptureRejects().
+		Run(df, WithMaxWorkers(1))
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	if len(result.Data) != 1 || result.Data[0][0] != "Ali" {
+		t.Fatalf("expected only Ali to be kept, got %v", result.Data)
+	}
+
+	rejects := result.Rejects()
+	if rejects == nil {
+		t.Fatal("expected rejects")
+	}
+	if !reflect.DeepEqual(rejects.Headers, []string{"name", "age", "joined", RejectReasonColumn}) {
+		t.Errorf("unexpected headers: %v", rejects.Headers)
+	}
+	if len(rejects.Data) != 3 {
+		t.Fatalf("expected 3 rejected rows, got %v", rejects.Data)
+	}
+	expected := []struct{ name, reason string }{
+		{"Ayşe", "filter_outliers(age): value outside [0, 120]"},
+		{"Veli", "filter_outliers(age): row 2: conversion error"},
+		{"Can", "clean_dates(joined): row 3, column joined: date format not found: not a date"},
+	}
+	for i, want := range expected {
+		row := rejects.Data[i]
+		if row[0] != want.name || !strings.HasPrefix(row[3], want.reason) {
+			t.Errorf("reject %d: expected %s (%s), got %v", i, want.name, want.reason, row)
+		}
+	}
+	if stats := result.Stats(); stats.RowsDropped() != 3 {
+		t.Errorf("expected 3 dropped rows in stats, got %d", stats.RowsDropped())
+	}
+}
+
+func TestPipelineCaptureRejects_AcrossFrameSteps(t *testing.T) {
+	df, _ := NewDataFrame([]string{"full_name", "age"}, [][]string{
+		{"Ali Can", "-1"},
+		{"Ayşe Kaya", "30"},
+		{"Veli Ak", "110"},
+	})
+
+	result, err := NewPipeline().
+		FilterOutliers("age", 0, 120).
+		SplitColumn("full_name", " ", []string{"first", "last"}).
+		FilterOutliers("age", 0, 100).
+		CaptureRejects().
+		Run(df, WithMaxWorkers(1))
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	rejects := result.Rejects()
+	if len(rejects.Data) != 2 {
+		t.Fatalf("expected 2 rejects, got %v", rejects.Data)
+	}
+	// Ali was rejected before the split, Veli after it
+	if rejects.Data[0][0] != "Ali Can" || rejects.Data[1][0] != "" || rejects.Data[1][2] != "Veli" {
+		t.Errorf("unexpected rejects: %v", rejects.Data)
+	}
+	for _, header := range []string{"full_name", "age", "first", "last", RejectReasonColumn} {
+		if rejects.getColumnIndex(header) == -1 {
+			t.Errorf("expected column %s in rejects, got %v", header, rejects.Headers)
+		}
+	}
+}
+
+func TestPipelineWithoutCaptureRejects(t *testing.T) {
+	df, _ := NewDataFrame([]string{"age"}, [][]string{{"1"}, {"500"}})
+	result, err := NewPipeline().FilterOutliers("age", 0, 120).Run(df)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if result.Rejects() != nil {
+		t.Errorf("expected no rejects without CaptureRejects")
+	}
+}
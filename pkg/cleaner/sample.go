@@ -0,0 +1,110 @@
+package cleaner
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// Sample returns a new DataFrame with n rows chosen at random, e.g. a QA sample of a large file.
+// The rows keep their order and are shared with df copy-on-write. The same seed selects the same
+// rows of the same input; with n at least the number of rows, all rows are returned.
+func (df *DataFrame) Sample(n int, seed int64) (*DataFrame, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("sample size must not be negative: %d", n)
+	}
+	rows := make([]int, len(df.Data))
+	for i := range rows {
+		rows[i] = i
+	}
+	kept := make([]bool, len(df.Data))
+	pickRows(newSampleRand(seed), rows, n, kept)
+	return df.sampled(kept), nil
+}
+
+// SampleFraction returns a new DataFrame with the given share of the rows, between 0 and 1,
+// chosen at random like Sample. The number of rows is rounded to the nearest integer.
+func (df *DataFrame) SampleFraction(fraction float64, seed int64) (*DataFrame, error) {
+	if fraction < 0 || fraction > 1 {
+		return nil, fmt.Errorf("sample fraction must be in [0, 1]: %g", fraction)
+	}
+	return df.Sample(int(math.Round(fraction*float64(len(df.Data)))), seed)
+}
+
+// SampleStratified returns a new DataFrame with the given share of the rows of every value of the
+// column, chosen at random like Sample, so the sample keeps the distribution of the column, e.g.
+// of a country or a status. Every value, including the empty one, keeps at least one row, so rare
+// values are represented.
+func (df *DataFrame) SampleStratified(column string, fraction float64, seed int64) (*DataFrame, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	if fraction < 0 || fraction > 1 {
+		return nil, fmt.Errorf("sample fraction must be in [0, 1]: %g", fraction)
+	}
+
+	// Strata in order of first appearance, so the selection does not depend on map order
+	strata := make(map[string]int)
+	var rowsOf [][]int
+	for i, row := range df.Data {
+		s, ok := strata[row[colIndex]]
+		if !ok {
+			s = len(rowsOf)
+			strata[row[colIndex]] = s
+			rowsOf = append(rowsOf, nil)
+		}
+		rowsOf[s] = append(rowsOf[s], i)
+	}
+
+	kept := make([]bool, len(df.Data))
+	rng := newSampleRand(seed)
+	for _, rows := range rowsOf {
+		n := int(math.Round(fraction * float64(len(rows))))
+		if fraction > 0 {
+			n = max(n, 1)
+		}
+		pickRows(rng, rows, n, kept)
+	}
+	return df.sampled(kept), nil
+}
+
+// SampleStep keeps every row of the stream with the probability fraction, so a sample of a file
+// larger than memory can be taken in one pass. The sample holds about fraction of the rows; the
+// same seed and chunk size select the same rows of the same input.
+func SampleStep(fraction float64, seed int64) StreamStep {
+	rng := newSampleRand(seed)
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		if fraction < 0 || fraction > 1 {
+			return nil, fmt.Errorf("sample fraction must be in [0, 1]: %g", fraction)
+		}
+		kept := make([]bool, len(chunk.Data))
+		for i := range kept {
+			kept[i] = rng.Float64() < fraction
+		}
+		chunk.retainRows(kept)
+		return chunk, nil
+	})
+}
+
+// newSampleRand returns the random source of a seed
+func newSampleRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(seed), 0))
+}
+
+// pickRows marks n of the rows as kept, chosen with a partial Fisher-Yates shuffle of rows
+func pickRows(rng *rand.Rand, rows []int, n int, kept []bool) {
+	n = min(n, len(rows))
+	for k := 0; k < n; k++ {
+		j := k + rng.IntN(len(rows)-k)
+		rows[k], rows[j] = rows[j], rows[k]
+		kept[rows[k]] = true
+	}
+}
+
+// sampled returns a copy of df with the kept rows
+func (df *DataFrame) sampled(kept []bool) *DataFrame {
+	sample := df.Copy()
+	sample.retainRows(kept)
+	return sample
+}
@@ -0,0 +1,117 @@
+package cleaner
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/formats"
+)
+
+func sampleTestFrame(rows int) *DataFrame {
+	data := make([][]string, rows)
+	for i := range data {
+		country := "TR"
+		if i%10 == 0 {
+			country = "DE"
+		}
+		data[i] = []string{fmt.Sprint(i), country}
+	}
+	df, _ := NewDataFrame([]string{"id", "country"}, data)
+	return df
+}
+
+func TestSample(t *testing.T) {
+	df := sampleTestFrame(1000)
+
+	sample, err := df.Sample(50, 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sample.Data) != 50 || len(df.Data) != 1000 {
+		t.Fatalf("expected 50 sampled rows and an unchanged input, got %d and %d", len(sample.Data), len(df.Data))
+	}
+	seen := make(map[string]bool)
+	previous := -1
+	for _, row := range sample.Data {
+		var id int
+		fmt.Sscan(row[0], &id)
+		if id <= previous || seen[row[0]] {
+			t.Fatalf("expected distinct rows in input order, got %v after %d", row, previous)
+		}
+		previous = id
+		seen[row[0]] = true
+	}
+
+	again, _ := df.Sample(50, 42)
+	if !reflect.DeepEqual(again.Data, sample.Data) {
+		t.Error("expected the same seed to select the same rows")
+	}
+	other, _ := df.Sample(50, 7)
+	if reflect.DeepEqual(other.Data, sample.Data) {
+		t.Error("expected another seed to select other rows")
+	}
+
+	if all, _ := df.Sample(5000, 1); len(all.Data) != 1000 {
+		t.Errorf("expected all rows, got %d", len(all.Data))
+	}
+	if _, err := df.Sample(-1, 1); err == nil {
+		t.Error("expected an error for a negative size")
+	}
+}
+
+func TestSampleFraction(t *testing.T) {
+	df := sampleTestFrame(1000)
+	sample, err := df.SampleFraction(0.25, 1)
+	if err != nil || len(sample.Data) != 250 {
+		t.Errorf("expected 250 rows, got %d %v", len(sample.Data), err)
+	}
+	if _, err := df.SampleFraction(1.5, 1); err == nil {
+		t.Error("expected an error for a fraction above 1")
+	}
+}
+
+func TestSampleStratified(t *testing.T) {
+	df := sampleTestFrame(1000)
+	df.Data[5][1] = "AZ" // A single row of its stratum
+
+	sample, err := df.SampleStratified("country", 0.1, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, row := range sample.Data {
+		counts[row[1]]++
+	}
+	if !reflect.DeepEqual(counts, map[string]int{"TR": 90, "DE": 10, "AZ": 1}) {
+		t.Errorf("unexpected counts per country: %v", counts)
+	}
+}
+
+func TestSampleStep(t *testing.T) {
+	src := formats.NewRawRowReader([]string{"id", "country"}, sampleTestFrame(10000).Data)
+	stats, err := NewStream(src, WithChunkSize(1000)).Then(SampleStep(0.1, 5)).Run(&collectWriter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.RowsWritten < 850 || stats.RowsWritten > 1150 {
+		t.Errorf("expected about 1000 rows, got %d", stats.RowsWritten)
+	}
+}
+
+func TestPipelineSample(t *testing.T) {
+	pipeline := NewPipeline()
+	if err := pipeline.Actions("trim", "sample_stratified:country=0.5=9", "sample:10=9"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sample, err := pipeline.Run(sampleTestFrame(100))
+	if err != nil || len(sample.Data) != 10 {
+		t.Errorf("expected 10 rows, got %d %v", len(sample.Data), err)
+	}
+
+	for _, action := range []string{"sample:-1", "sample_fraction:2", "sample_stratified:country", "sample:10=x"} {
+		if err := NewPipeline().Action(action); err == nil {
+			t.Errorf("%s: expected a usage error", action)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cleaner
+
+import (
+	"sync/atomic"
+)
+
+// AnyMatch reports whether any value in the column satisfies the predicate. The column is scanned in
+// parallel and the scan stops at the first match, so validation checks don't need a full pass or a
+// transformation. The predicate is called concurrently and must be safe for concurrent use.
+func (df *DataFrame) AnyMatch(column string, predicate func(value string) bool, options ...func(*ParallelOptions)) (bool, error) {
+	var found atomic.Bool
+	err := df.scanColumn("any_match", column, options, func(colIndex, start, end int) {
+		for i := start; i < end && !found.Load(); i++ {
+			if predicate(df.Data[i][colIndex]) {
+				found.Store(true)
+			}
+		}
+	})
+	if err != nil {
+		return false, err
+	}
+	return found.Load(), nil
+}
+
+// CountMatching returns the number of values in the column that satisfy the predicate, counted in
+// parallel. The predicate is called concurrently and must be safe for concurrent use.
+func (df *DataFrame) CountMatching(column string, predicate func(value string) bool, options ...func(*ParallelOptions)) (int, error) {
+	var count atomic.Int64
+	err := df.scanColumn("count_matching", column, options, func(colIndex, start, end int) {
+		n := 0
+		for i := start; i < end; i++ {
+			if predicate(df.Data[i][colIndex]) {
+				n++
+			}
+		}
+		count.Add(int64(n))
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(count.Load()), nil
+}
+
+// AnyInvalidDate reports whether the column has a non-empty value that is not a date in one of the
+// common layouts recognized by CleanDates. It stops at the first invalid value.
+func (df *DataFrame) AnyInvalidDate(column string, options ...func(*ParallelOptions)) (bool, error) {
+	return df.AnyMatch(column, isInvalidDate, options...)
+}
+
+// isInvalidDate reports whether a non-empty value cannot be parsed as a date
+func isInvalidDate(value string) bool {
+	if value == "" {
+		return false
+	}
+	_, err := parseDate(value, commonDateLayouts[0])
+	return err != nil
+}
+
+// scanColumn calls scan with [start, end) row ranges of the column in parallel, without modifying
+// the DataFrame
+func (df *DataFrame) scanColumn(operation, column string, options []func(*ParallelOptions), scan func(colIndex, start, end int)) error {
+	_, err := df.runObserved(operation, column, options, func(opts *ParallelOptions) (*DataFrame, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		err = processChunks(opts, len(df.Data), func(start, end int) {
+			scan(colIndex, start, end)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return df, nil
+	})
+	return err
+}
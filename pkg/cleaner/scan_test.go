@@ -0,0 +1,82 @@
+package cleaner
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCountMatching(t *testing.T) {
+	df := benchmarkDataFrame(10000, 2)
+
+	for _, workers := range []int{1, 4} {
+		count, err := df.CountMatching("col0", func(v string) bool { return v == "" }, WithMaxWorkers(workers))
+		if err != nil {
+			t.Fatalf("CountMatching error: %v", err)
+		}
+		if count != 1000 {
+			t.Errorf("workers=%d: expected 1000 empty values, got %d", workers, count)
+		}
+	}
+
+	predicate := func(v string) bool { return strings.HasPrefix(v, "  Value 1") }
+	want := 0
+	for _, row := range df.Data {
+		if predicate(row[1]) {
+			want++
+		}
+	}
+	count, err := df.CountMatching("col1", predicate, WithMaxWorkers(3))
+	if err != nil {
+		t.Fatalf("CountMatching error: %v", err)
+	}
+	if count != want {
+		t.Errorf("expected %d matches, got %d", want, count)
+	}
+}
+
+func TestAnyMatchStopsEarly(t *testing.T) {
+	df := benchmarkDataFrame(100000, 1)
+
+	var calls atomic.Int64
+	found, err := df.AnyMatch("col0", func(v string) bool {
+		calls.Add(1)
+		return v == ""
+	}, WithMaxWorkers(1))
+	if err != nil {
+		t.Fatalf("AnyMatch error: %v", err)
+	}
+	if !found {
+		t.Error("expected a match")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected the scan to stop at the first row, got %d calls", calls.Load())
+	}
+
+	found, err = df.AnyMatch("col0", func(v string) bool { return v == "absent" }, WithMaxWorkers(4))
+	if err != nil || found {
+		t.Errorf("expected no match, got %v, %v", found, err)
+	}
+
+	if _, err := df.AnyMatch("missing", func(string) bool { return true }); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestAnyInvalidDate(t *testing.T) {
+	df, _ := NewDataFrame([]string{"date"}, [][]string{{"2024-01-15"}, {""}, {"15/01/2024"}, {"2024-01-15T10:00:00Z"}})
+
+	invalid, err := df.AnyInvalidDate("date")
+	if err != nil {
+		t.Fatalf("AnyInvalidDate error: %v", err)
+	}
+	if invalid {
+		t.Error("expected all dates to be valid")
+	}
+
+	df.Data[1][0] = "yesterday"
+	if invalid, _ := df.AnyInvalidDate("date", WithMaxWorkers(2)); !invalid {
+		t.Error("expected an invalid date")
+	}
+}
@@ -0,0 +1,124 @@
+package cleaner
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FileSchema is the columns of a file and their inferred types
+type FileSchema struct {
+	Path    string          `json:"path"`
+	Rows    int             `json:"rows"`
+	Columns []string        `json:"columns"`
+	Types   map[string]Type `json:"types"`
+}
+
+// TypeMismatch is a column whose type in a file differs from its type in the first file having it
+type TypeMismatch struct {
+	Column   string `json:"column"`
+	Expected Type   `json:"expected"`
+	Actual   Type   `json:"actual"`
+}
+
+// SchemaDifference is how the schema of a file differs from the schema of the first file
+type SchemaDifference struct {
+	Path       string         `json:"path"`
+	Missing    []string       `json:"missing,omitempty"`    // Columns of the first file the file lacks
+	Extra      []string       `json:"extra,omitempty"`      // Columns of the file the first file lacks
+	Mismatched []TypeMismatch `json:"mismatched,omitempty"` // Columns with a different type
+}
+
+// SchemaReport is the result of CompareSchemas
+type SchemaReport struct {
+	Files []FileSchema `json:"files"`
+	// Columns is the union of the columns of all files, in the order they are first seen
+	Columns []string `json:"columns"`
+	// Differences has an entry for every file after the first whose schema differs from it
+	Differences []SchemaDifference `json:"differences"`
+}
+
+// Consistent reports whether all files have the same columns with the same types
+func (r *SchemaReport) Consistent() bool {
+	return len(r.Differences) == 0
+}
+
+// CompareSchemas reads the files and compares their columns and inferred types with those of the
+// first file, e.g. to check that months of exports can be concatenated. The format of a file is
+// taken from its extension: .csv, .json, .xlsx, .parquet, .xml or .yaml. Column order is ignored.
+// A column missing from the first file is compared with its type in the first file having it.
+func CompareSchemas(paths ...string) (*SchemaReport, error) {
+	if len(paths) < 2 {
+		return nil, fmt.Errorf("at least two files must be specified, got %d", len(paths))
+	}
+
+	report := &SchemaReport{Files: make([]FileSchema, 0, len(paths))}
+	for _, path := range paths {
+		df, err := readFileByExtension(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		df.InferTypes()
+		report.Files = append(report.Files, FileSchema{
+			Path:    path,
+			Rows:    len(df.Data),
+			Columns: slices.Clone(df.Headers),
+			Types:   maps.Clone(df.Types),
+		})
+	}
+
+	// The type of every column is the type in the first file having it
+	expected := make(map[string]Type)
+	for _, file := range report.Files {
+		for _, column := range file.Columns {
+			if _, ok := expected[column]; !ok {
+				expected[column] = file.Types[column]
+				report.Columns = append(report.Columns, column)
+			}
+		}
+	}
+
+	first := report.Files[0]
+	for _, file := range report.Files[1:] {
+		diff := SchemaDifference{Path: file.Path}
+		for _, column := range first.Columns {
+			if !slices.Contains(file.Columns, column) {
+				diff.Missing = append(diff.Missing, column)
+			}
+		}
+		for _, column := range file.Columns {
+			if !slices.Contains(first.Columns, column) {
+				diff.Extra = append(diff.Extra, column)
+			}
+			if t := file.Types[column]; t != expected[column] {
+				diff.Mismatched = append(diff.Mismatched, TypeMismatch{Column: column, Expected: expected[column], Actual: t})
+			}
+		}
+		if len(diff.Missing) > 0 || len(diff.Extra) > 0 || len(diff.Mismatched) > 0 {
+			report.Differences = append(report.Differences, diff)
+		}
+	}
+	return report, nil
+}
+
+// readFileByExtension reads a file with the reader of its extension and the default options
+func readFileByExtension(path string) (*DataFrame, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return ReadCSV(path)
+	case ".json":
+		return ReadJSON(path)
+	case ".xlsx", ".xls":
+		return ReadExcel(path)
+	case ".parquet":
+		return ReadParquet(path)
+	case ".xml":
+		return ReadXML(path)
+	case ".yaml", ".yml":
+		return ReadYAML(path)
+	default:
+		return nil, fmt.Errorf("unsupported file format: %q", ext)
+	}
+}
@@ -0,0 +1,70 @@
+package cleaner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCompareSchemas(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"jan.csv":  "id,name,amount\n1,Ali,10\n2,Ayşe,20\n",
+		"feb.csv":  "name,id,amount\nCan,3,30\n",
+		"mar.csv":  "id,name,amount,note\n4,Elif,12.5,late\n",
+		"apr.json": `[{"id": "x5", "amount": 7}]`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	report, err := CompareSchemas(path("jan.csv"), path("feb.csv"), path("mar.csv"), path("apr.json"))
+	if err != nil {
+		t.Fatalf("CompareSchemas error: %v", err)
+	}
+
+	if !reflect.DeepEqual(report.Columns, []string{"id", "name", "amount", "note"}) {
+		t.Errorf("unexpected columns: %v", report.Columns)
+	}
+	if report.Files[0].Rows != 2 || report.Files[0].Types["amount"] != TypeInt {
+		t.Errorf("unexpected first file schema: %+v", report.Files[0])
+	}
+
+	// feb only differs in column order
+	expected := []SchemaDifference{
+		{
+			Path:       path("mar.csv"),
+			Extra:      []string{"note"},
+			Mismatched: []TypeMismatch{{Column: "amount", Expected: TypeInt, Actual: TypeFloat}},
+		},
+		{
+			Path:       path("apr.json"),
+			Missing:    []string{"name"},
+			Mismatched: []TypeMismatch{{Column: "id", Expected: TypeInt, Actual: TypeString}},
+		},
+	}
+	if !reflect.DeepEqual(report.Differences, expected) {
+		t.Errorf("differences = %+v, expected = %+v", report.Differences, expected)
+	}
+	if report.Consistent() {
+		t.Error("expected an inconsistent report")
+	}
+
+	report, err = CompareSchemas(path("jan.csv"), path("feb.csv"))
+	if err != nil || !report.Consistent() {
+		t.Errorf("expected consistent schemas, got %+v, %v", report, err)
+	}
+}
+
+func TestCompareSchemas_Errors(t *testing.T) {
+	if _, err := CompareSchemas("only.csv"); err == nil {
+		t.Error("expected an error for a single file")
+	}
+	if _, err := CompareSchemas("a.csv", "b.txt"); err == nil {
+		t.Error("expected an error for a missing or unsupported file")
+	}
+}
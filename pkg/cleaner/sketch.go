@@ -0,0 +1,235 @@
+package cleaner
+
+import (
+	"cmp"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// hllPrecision is the number of hash bits that select a HyperLogLog register: 2^14 registers of
+// one byte each, for a standard error of about 0.8%
+const hllPrecision = 14
+
+// hyperLogLog estimates the number of distinct strings added to it in fixed memory
+type hyperLogLog struct {
+	registers []uint8
+}
+
+// newHyperLogLog returns an empty HyperLogLog sketch
+func newHyperLogLog() *hyperLogLog {
+	return &hyperLogLog{registers: make([]uint8, 1<<hllPrecision)}
+}
+
+// add adds value to the sketch
+func (h *hyperLogLog) add(value string) {
+	x := hashString(value)
+	index := x >> (64 - hllPrecision)
+	// The sentinel bit caps the rank when the remaining bits are all zero
+	rank := uint8(bits.LeadingZeros64(x<<hllPrecision|1<<(hllPrecision-1))) + 1
+	if rank > h.registers[index] {
+		h.registers[index] = rank
+	}
+}
+
+// count returns the estimated number of distinct values added. It uses the estimator of Ertl,
+// "New cardinality estimation algorithms for HyperLogLog sketches" (2017), which is unbiased
+// from small to large cardinalities without empirical correction tables.
+func (h *hyperLogLog) count() int {
+	const q = 64 - hllPrecision
+	var histogram [q + 2]int
+	for _, r := range h.registers {
+		histogram[r]++
+	}
+	m := float64(len(h.registers))
+	z := m * hllTau(1-float64(histogram[q+1])/m)
+	for k := q; k >= 1; k-- {
+		z = 0.5 * (z + float64(histogram[k]))
+	}
+	z += m * hllSigma(float64(histogram[0])/m)
+	return int(math.Round(m * m / (2 * math.Ln2) / z))
+}
+
+// hllSigma is the series of the estimator for the share x of empty registers
+func hllSigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+	y, z := 1.0, x
+	for {
+		x *= x
+		previous := z
+		z += x * y
+		y += y
+		if z == previous {
+			return z
+		}
+	}
+}
+
+// hllTau is the series of the estimator for the share x of saturated registers
+func hllTau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		previous := z
+		y *= 0.5
+		z -= (1 - x) * (1 - x) * y
+		if z == previous {
+			return z / 3
+		}
+	}
+}
+
+// Dimensions of the count-min sketch: 4 rows of 2^14 counters overestimate a count by at most
+// 0.02% of the values added with a probability above 98%
+const (
+	cmsDepth = 4
+	cmsWidth = 1 << 14
+)
+
+// countMinSketch estimates how often strings were added to it in fixed memory. Estimates are
+// never below the true count.
+type countMinSketch struct {
+	counters [cmsDepth][]uint64
+}
+
+// newCountMinSketch returns an empty count-min sketch
+func newCountMinSketch() *countMinSketch {
+	s := &countMinSketch{}
+	for d := range s.counters {
+		s.counters[d] = make([]uint64, cmsWidth)
+	}
+	return s
+}
+
+// add counts value once more and returns its estimated count
+func (s *countMinSketch) add(value string) uint64 {
+	x := hashString(value)
+	h1, h2 := x&0xffffffff, x>>32|1
+	estimate := uint64(math.MaxUint64)
+	for d := range s.counters {
+		i := (h1 + uint64(d)*h2) % cmsWidth
+		s.counters[d][i]++
+		estimate = min(estimate, s.counters[d][i])
+	}
+	return estimate
+}
+
+// topK keeps the k values with the highest estimated counts of a count-min sketch, the heavy
+// hitters of a stream of values, in memory independent of the number of distinct values
+type topK struct {
+	k      int
+	sketch *countMinSketch
+	items  []ValueCount   // Min-heap by count, then by reverse value order
+	index  map[string]int // Position of every value in items
+}
+
+// newTopK returns an empty tracker of the k most frequent values
+func newTopK(k int) *topK {
+	return &topK{k: k, sketch: newCountMinSketch(), index: make(map[string]int, k)}
+}
+
+// add counts value once more
+func (t *topK) add(value string) {
+	count := int(t.sketch.add(value))
+	if t.k == 0 {
+		return
+	}
+	if i, ok := t.index[value]; ok {
+		t.items[i].Count = count
+		t.down(i)
+		return
+	}
+	item := ValueCount{Value: value, Count: count}
+	if len(t.items) < t.k {
+		t.items = append(t.items, item)
+		t.index[value] = len(t.items) - 1
+		t.up(len(t.items) - 1)
+		return
+	}
+	if !t.less(t.items[0], item) {
+		return
+	}
+	delete(t.index, t.items[0].Value)
+	t.items[0] = item
+	t.index[value] = 0
+	t.down(0)
+}
+
+// result returns the kept values, the most frequent first and values with the same count in
+// sorted order
+func (t *topK) result() []ValueCount {
+	items := slices.Clone(t.items)
+	slices.SortFunc(items, func(a, b ValueCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return items
+}
+
+// less orders the heap so the least frequent value, the last one in sorted order on a tie, is
+// replaced first
+func (t *topK) less(a, b ValueCount) bool {
+	if a.Count != b.Count {
+		return a.Count < b.Count
+	}
+	return a.Value > b.Value
+}
+
+// swap swaps two heap items and their positions
+func (t *topK) swap(i, j int) {
+	t.items[i], t.items[j] = t.items[j], t.items[i]
+	t.index[t.items[i].Value] = i
+	t.index[t.items[j].Value] = j
+}
+
+// up moves the item at i up the heap
+func (t *topK) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !t.less(t.items[i], t.items[parent]) {
+			return
+		}
+		t.swap(i, parent)
+		i = parent
+	}
+}
+
+// down moves the item at i down the heap
+func (t *topK) down(i int) {
+	for {
+		smallest := i
+		for child := 2*i + 1; child <= 2*i+2; child++ {
+			if child < len(t.items) && t.less(t.items[child], t.items[smallest]) {
+				smallest = child
+			}
+		}
+		if smallest == i {
+			return
+		}
+		t.swap(i, smallest)
+		i = smallest
+	}
+}
+
+// hashString returns a 64-bit hash of value whose bits are evenly distributed, as sketches need:
+// FNV-1a followed by the finalizer of MurmurHash3
+func hashString(value string) uint64 {
+	x := uint64(14695981039346656037)
+	for i := 0; i < len(value); i++ {
+		x ^= uint64(value[i])
+		x *= 1099511628211
+	}
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,37 @@
+package cleaner
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 10000, 200000} {
+		h := newHyperLogLog()
+		for i := range n {
+			// Every value twice; repeats must not be counted
+			h.add("value-" + strconv.Itoa(i))
+			h.add("value-" + strconv.Itoa(i))
+		}
+		got := h.count()
+		if math.Abs(float64(got-n)) > 0.02*float64(n) {
+			t.Errorf("%d distinct values: estimated %d", n, got)
+		}
+	}
+}
+
+func TestTopKReplacesLeastFrequent(t *testing.T) {
+	top := newTopK(2)
+	for _, value := range []string{"a", "b", "c", "c", "c", "b", "d", "d", "d", "d"} {
+		top.add(value)
+	}
+	expected := []ValueCount{{"d", 4}, {"c", 3}}
+	if got := top.result(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if got := newTopK(0).result(); len(got) != 0 {
+		t.Errorf("expected no values for k 0, got %v", got)
+	}
+}
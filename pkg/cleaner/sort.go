@@ -0,0 +1,58 @@
+package cleaner
+
+import (
+	"errors"
+	"slices"
+)
+
+// SortBy sorts the rows by the keys: by the first key, then by the next one for equal values, and
+// so on. The sort is stable, so rows equal in all keys keep their order. Numeric keys compare the
+// values as numbers, with non-numeric values after the numbers. Strings are compared by byte value
+// or by the collation set with WithCollation.
+func (df *DataFrame) SortBy(keys []SortKey) (*DataFrame, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no sort keys")
+	}
+	indices := make([]int, len(keys))
+	for k, key := range keys {
+		j, err := df.requireColumn(key.Column)
+		if err != nil {
+			return nil, err
+		}
+		indices[k] = j
+	}
+
+	compare := rowComparator(keys, indices, df.stringComparator())
+	order := make([]int, len(df.Data))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return compare(df.Data[a], df.Data[b])
+	})
+
+	// Rows move with their ownership; the row slice is new, so copies keep their order
+	sorted := make([][]string, len(df.Data))
+	var owned []bool
+	if df.cow != nil {
+		owned = make([]bool, len(df.Data))
+	}
+	for k, i := range order {
+		sorted[k] = df.Data[i]
+		if owned != nil {
+			owned[k] = i < len(df.cow.owned) && df.cow.owned[i]
+		}
+	}
+	df.Data = sorted
+	if owned != nil {
+		df.cow = &cowState{owned: owned}
+	}
+	return df, nil
+}
+
+// SortByColumn sorts the rows by one column, see SortBy. Int and float columns are compared as
+// numbers, the others lexicographically.
+func (df *DataFrame) SortByColumn(column string, descending bool) (*DataFrame, error) {
+	numeric := df.Types[column] == TypeInt || df.Types[column] == TypeFloat
+	return df.SortBy([]SortKey{{Column: column, Descending: descending, Numeric: numeric}})
+}
@@ -0,0 +1,67 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSortBy(t *testing.T) {
+	df, _ := NewDataFrame([]string{"name", "city", "age"}, [][]string{
+		{"Ali", "İzmir", "30"},
+		{"Veli", "Ankara", "9"},
+		{"Ayşe", "İzmir", "100"},
+		{"Can", "Ankara", ""},
+		{"Ece", "Ankara", "9"},
+	})
+	copied := df.Copy()
+
+	df, err := df.SortBy([]SortKey{{Column: "city"}, {Column: "age", Numeric: true, Descending: true}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Empty values sort after numbers, equal rows keep their order
+	expected := [][]string{
+		{"Can", "Ankara", ""},
+		{"Veli", "Ankara", "9"},
+		{"Ece", "Ankara", "9"},
+		{"Ayşe", "İzmir", "100"},
+		{"Ali", "İzmir", "30"},
+	}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("unexpected order: %v", df.Data)
+	}
+	if copied.Data[0][0] != "Ali" {
+		t.Error("expected copies to keep their order")
+	}
+
+	// A sorted copy-on-write row is still copied before a write
+	df.SetValue(0, "name", "John")
+	if copied.Data[3][0] != "Can" {
+		t.Error("expected the shared row not to change")
+	}
+
+	if _, err := df.SortBy([]SortKey{{Column: "missing"}}); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := df.SortBy(nil); err == nil {
+		t.Error("expected an error without keys")
+	}
+}
+
+func TestSortByColumn(t *testing.T) {
+	df, _ := NewDataFrame([]string{"price"}, [][]string{{"9.5"}, {"100"}, {"20"}})
+	df.InferTypes()
+	df, err := df.SortByColumn("price", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(df.Data, [][]string{{"100"}, {"20"}, {"9.5"}}) {
+		t.Errorf("expected a numeric sort, got %v", df.Data)
+	}
+
+	df, _ = NewPipeline().SortBy([]SortKey{{Column: "price"}}).Run(df)
+	if !reflect.DeepEqual(df.Data, [][]string{{"100"}, {"20"}, {"9.5"}}) {
+		t.Errorf("expected a lexicographic sort, got %v", df.Data)
+	}
+}
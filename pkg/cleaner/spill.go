@@ -0,0 +1,189 @@
+package cleaner
+
+import (
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+)
+
+// spillPartitionCount is the number of files rows are hash-partitioned into once a step spills
+const spillPartitionCount = 16
+
+// rowSize estimates the memory held by a row: the slice and string headers plus the string bytes
+func rowSize(row []string) int64 {
+	size := int64(24 + 16*len(row))
+	for _, value := range row {
+		size += int64(len(value))
+	}
+	return size
+}
+
+// spillDir lazily creates a temporary directory for spill files and removes it on Close
+type spillDir struct {
+	parent string
+	path   string
+	files  int
+}
+
+// create creates a new spill file
+func (d *spillDir) create(prefix string) (*os.File, error) {
+	if d.path == "" {
+		path, err := os.MkdirTemp(d.parent, "cleango-spill-*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create spill directory: %w", err)
+		}
+		d.path = path
+	}
+	d.files++
+	file, err := os.Create(filepath.Join(d.path, fmt.Sprintf("%s-%d", prefix, d.files)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spill file: %w", err)
+	}
+	return file, nil
+}
+
+// Close removes the spill directory and all files in it
+func (d *spillDir) Close() error {
+	if d.path == "" {
+		return nil
+	}
+	err := os.RemoveAll(d.path)
+	d.path = ""
+	return err
+}
+
+// runWriter writes rows to a spill file
+type runWriter struct {
+	file *os.File
+	buf  *bufio.Writer
+	enc  *gob.Encoder
+}
+
+func newRunWriter(file *os.File) *runWriter {
+	buf := bufio.NewWriter(file)
+	return &runWriter{file: file, buf: buf, enc: gob.NewEncoder(buf)}
+}
+
+// Write appends a row to the file
+func (w *runWriter) Write(row []string) error {
+	return w.enc.Encode(row)
+}
+
+// Close flushes and closes the file
+func (w *runWriter) Close() error {
+	if err := w.buf.Flush(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	return w.file.Close()
+}
+
+// runReader reads rows back from a spill file
+type runReader struct {
+	file *os.File
+	dec  *gob.Decoder
+}
+
+func openRun(path string) (*runReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spill file: %w", err)
+	}
+	return &runReader{file: file, dec: gob.NewDecoder(bufio.NewReader(file))}, nil
+}
+
+// Next returns the next row, or io.EOF at the end of the file
+func (r *runReader) Next() ([]string, error) {
+	var row []string
+	if err := r.dec.Decode(&row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// Close closes the file
+func (r *runReader) Close() error {
+	return r.file.Close()
+}
+
+// spillPartitions hash-partitions rows by key into spill files, so every key ends up in
+// exactly one partition and partitions can be processed one at a time in memory
+type spillPartitions struct {
+	dir     *spillDir
+	prefix  string
+	writers [spillPartitionCount]*runWriter
+	paths   []string
+}
+
+func newSpillPartitions(dir *spillDir, prefix string) *spillPartitions {
+	return &spillPartitions{dir: dir, prefix: prefix}
+}
+
+// Write appends the row to the partition of key
+func (p *spillPartitions) Write(key string, row []string) error {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	index := h.Sum32() % spillPartitionCount
+
+	if p.writers[index] == nil {
+		file, err := p.dir.create(p.prefix)
+		if err != nil {
+			return err
+		}
+		p.writers[index] = newRunWriter(file)
+		p.paths = append(p.paths, file.Name())
+	}
+	return p.writers[index].Write(row)
+}
+
+// Finish closes all partitions and returns the paths of the non-empty ones
+func (p *spillPartitions) Finish() ([]string, error) {
+	for i, w := range p.writers {
+		if w == nil {
+			continue
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		p.writers[i] = nil
+	}
+	return p.paths, nil
+}
+
+// rowBatcher collects rows and emits them as DataFrames of at most size rows
+type rowBatcher struct {
+	headers []string
+	size    int
+	rows    [][]string
+	emit    func(*DataFrame) error
+}
+
+func newRowBatcher(headers []string, size int, emit func(*DataFrame) error) *rowBatcher {
+	return &rowBatcher{headers: headers, size: size, emit: emit}
+}
+
+// Add appends a row and emits a batch when it is full
+func (b *rowBatcher) Add(row []string) error {
+	b.rows = append(b.rows, row)
+	if len(b.rows) >= b.size {
+		return b.Flush()
+	}
+	return nil
+}
+
+// Flush emits the collected rows
+func (b *rowBatcher) Flush() error {
+	if len(b.rows) == 0 {
+		return nil
+	}
+	// Following steps may rename headers in place, so every batch gets its own copy
+	df, err := NewDataFrame(append([]string(nil), b.headers...), b.rows)
+	if err != nil {
+		return err
+	}
+	b.rows = nil
+	return b.emit(df)
+}
@@ -0,0 +1,67 @@
+package cleaner
+
+// ColumnStats counts what the last pipeline run did to a column
+type ColumnStats struct {
+	Modified      int `json:"modified"`       // Cells whose value was changed
+	NullsReplaced int `json:"nulls_replaced"` // Empty cells that were given a value
+	RowsDropped   int `json:"rows_dropped"`   // Rows removed by a filter on the column
+	ParseFailures int `json:"parse_failures"` // Values that could not be parsed and were left unchanged
+}
+
+// Stats summarizes the last pipeline run that produced a DataFrame
+type Stats struct {
+	RowsIn  int                    `json:"rows_in"`
+	RowsOut int                    `json:"rows_out"`
+	Columns map[string]ColumnStats `json:"columns"` // Only columns with at least one count
+}
+
+// Stats returns the statistics of the pipeline run that produced df. Row-wise pipeline steps are
+// counted per column; frame steps such as SplitColumn and custom Then steps only change the row
+// totals. Without a pipeline run, no column counts are reported.
+func (df *DataFrame) Stats() Stats {
+	if df.stats == nil {
+		return Stats{RowsIn: len(df.Data), RowsOut: len(df.Data), Columns: map[string]ColumnStats{}}
+	}
+	stats := *df.stats
+	stats.Columns = make(map[string]ColumnStats, len(df.stats.Columns))
+	for column, counts := range df.stats.Columns {
+		stats.Columns[column] = counts
+	}
+	return stats
+}
+
+// RowsDropped returns the number of rows removed by the run
+func (s Stats) RowsDropped() int {
+	return s.RowsIn - s.RowsOut
+}
+
+// countChange counts a cell that changed from before to after
+func (c *ColumnStats) countChange(before, after string) {
+	if before == after {
+		return
+	}
+	c.Modified++
+	if before == "" {
+		c.NullsReplaced++
+	}
+}
+
+// add adds the counts of other
+func (c *ColumnStats) add(other ColumnStats) {
+	c.Modified += other.Modified
+	c.NullsReplaced += other.NullsReplaced
+	c.RowsDropped += other.RowsDropped
+	c.ParseFailures += other.ParseFailures
+}
+
+// addColumns adds counts indexed like headers to the column statistics
+func (s *Stats) addColumns(headers []string, counts []ColumnStats) {
+	for j, c := range counts {
+		if c == (ColumnStats{}) {
+			continue
+		}
+		total := s.Columns[headers[j]]
+		total.add(c)
+		s.Columns[headers[j]] = total
+	}
+}
@@ -0,0 +1,74 @@
+package cleaner
+
+import (
+	"testing"
+)
+
+func TestPipelineStats(t *testing.T) {
+	df, _ := NewDataFrame([]string{"name", "age", "joined"}, [][]string{
+		{" Ali ", "", "2024-01-02"},
+		{"Ayşe", "200", "02/01/2024"},
+		{"Veli ", "30", "not a date"},
+		{"Can", "", ""},
+	})
+
+	result, err := NewPipeline().
+		Trim().
+		ReplaceNulls("age", "0").
+		FilterOutliers("age", 0, 120).
+		CleanDates("joined", "2006-01-02").
+		OnError(func(err *StepError) error { return nil }).
+		Run(df, WithMaxWorkers(1))
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	stats := result.Stats()
+	if stats.RowsIn != 4 || stats.RowsOut != 3 || stats.RowsDropped() != 1 {
+		t.Errorf("unexpected row counts: %+v", stats)
+	}
+	expected := map[string]ColumnStats{
+		"name":   {Modified: 2},
+		"age":    {Modified: 2, NullsReplaced: 2, RowsDropped: 1},
+		"joined": {ParseFailures: 1},
+	}
+	for column, want := range expected {
+		if got := stats.Columns[column]; got != want {
+			t.Errorf("column %s: expected %+v, got %+v", column, want, got)
+		}
+	}
+	if len(stats.Columns) != len(expected) {
+		t.Errorf("expected only changed columns, got %v", stats.Columns)
+	}
+}
+
+func TestPipelineStats_Parallel(t *testing.T) {
+	rows := make([][]string, 10000)
+	for i := range rows {
+		rows[i] = []string{" x ", ""}
+	}
+	df, _ := NewDataFrame([]string{"a", "b"}, rows)
+
+	result, err := NewPipeline().Trim().ReplaceNulls("b", "-").Run(df, WithMaxWorkers(4))
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	stats := result.Stats()
+	if stats.Columns["a"].Modified != 10000 || stats.Columns["b"].NullsReplaced != 10000 {
+		t.Errorf("unexpected counts: %+v", stats.Columns)
+	}
+}
+
+func TestStats_WithoutRun(t *testing.T) {
+	df, _ := NewDataFrame([]string{"a"}, [][]string{{"1"}, {"2"}})
+	stats := df.Stats()
+	if stats.RowsIn != 2 || stats.RowsOut != 2 || len(stats.Columns) != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// Each run reports only its own counts
+	NewPipeline().ReplaceNulls("a", "0").Run(df)
+	if stats := df.Stats(); len(stats.Columns) != 0 {
+		t.Errorf("expected no counts for a run without changes, got %+v", stats.Columns)
+	}
+}
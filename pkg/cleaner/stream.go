@@ -0,0 +1,496 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mstgnz/cleango/pkg/formats"
+)
+
+// StreamStep is an operation applied to every chunk of a stream.
+//
+// Column operations (trim, null replacement, dates, case, regex, split, rename, outlier filter) only
+// look at one row at a time and are chunk-safe. Operations that need the whole dataset, such as
+// deduplication, keep state across chunks inside the step; see DedupStep.
+type StreamStep interface {
+	ProcessChunk(chunk *DataFrame) (*DataFrame, error)
+}
+
+// StreamFlusher is implemented by steps that hold rows back and emit them after the last chunk
+// (e.g. sorting or grouping). Flush passes the held rows to emit, which may be called several times.
+type StreamFlusher interface {
+	Flush(emit func(*DataFrame) error) error
+}
+
+// streamConfigurable is implemented by steps that depend on the stream options, e.g. the memory limit
+type streamConfigurable interface {
+	configure(opts *StreamOptions)
+}
+
+// StreamStepFunc adapts a chunk-safe function to a StreamStep
+type StreamStepFunc func(chunk *DataFrame) (*DataFrame, error)
+
+// ProcessChunk calls f(chunk)
+func (f StreamStepFunc) ProcessChunk(chunk *DataFrame) (*DataFrame, error) {
+	return f(chunk)
+}
+
+// StreamOptions contains stream processing options
+type StreamOptions struct {
+	ChunkSize   int
+	Context     context.Context
+	MemoryLimit int64               // Bytes held by stateful steps before spilling to disk (0 = no limit)
+	TempDir     string              // Directory for spill files (default: os.TempDir())
+	CSVOptions  []formats.CSVOption // CSV options used by AppendClean
+	DedupWindow int                 // Rows DedupStep looks back for duplicates (0 = the whole stream)
+	// ApproxDedupKeys and ApproxDedupRate size the Bloom filter of DedupStep (0 keys = exact keys)
+	ApproxDedupKeys int
+	ApproxDedupRate float64
+	ColumnStats     bool // Gather the statistics of the output columns, see WithColumnStats
+}
+
+// StreamOption is a function type for setting stream options
+type StreamOption func(*StreamOptions)
+
+// defaultStreamOptions returns default stream options
+func defaultStreamOptions() *StreamOptions {
+	return &StreamOptions{
+		ChunkSize: 50000,
+		Context:   context.Background(),
+	}
+}
+
+// WithChunkSize sets the number of rows read per chunk
+func WithChunkSize(rows int) StreamOption {
+	return func(o *StreamOptions) {
+		if rows > 0 {
+			o.ChunkSize = rows
+		}
+	}
+}
+
+// WithStreamContext sets the context for cancelling a stream between chunks
+func WithStreamContext(ctx context.Context) StreamOption {
+	return func(o *StreamOptions) {
+		if ctx != nil {
+			o.Context = ctx
+		}
+	}
+}
+
+// WithMemoryLimit sets how many bytes stateful steps (sort, dedup, group by) may hold in memory.
+// Beyond the limit they spill intermediate runs to temporary files.
+func WithMemoryLimit(bytes int64) StreamOption {
+	return func(o *StreamOptions) {
+		if bytes > 0 {
+			o.MemoryLimit = bytes
+		}
+	}
+}
+
+// WithDedupWindow makes DedupStep only drop rows whose key appeared in the previous n rows, holding
+// at most n keys in memory. Duplicates further apart are kept. It takes precedence over
+// WithApproxDedup and WithMemoryLimit.
+func WithDedupWindow(n int) StreamOption {
+	return func(o *StreamOptions) {
+		if n > 0 {
+			o.DedupWindow = n
+		}
+	}
+}
+
+// WithApproxDedup makes DedupStep remember keys in a Bloom filter sized for expectedKeys distinct
+// keys, instead of keeping every key. Memory stays fixed at about 1.2 bytes per expected key for a
+// 1% rate, but a new key is mistaken for a duplicate and its row dropped with the false positive
+// rate, which grows beyond expectedKeys. It takes precedence over WithMemoryLimit.
+func WithApproxDedup(expectedKeys int, falsePositiveRate float64) StreamOption {
+	return func(o *StreamOptions) {
+		if expectedKeys > 0 {
+			o.ApproxDedupKeys = expectedKeys
+			o.ApproxDedupRate = falsePositiveRate
+		}
+	}
+}
+
+// WithColumnStats makes the stream gather the count, nulls, min, max and approximate distinct
+// values of every output column in StreamStats.Columns, so a huge file is profiled in the pass
+// that cleans it. Memory stays fixed at about 16 KB per column, for the distinct values sketch.
+func WithColumnStats() StreamOption {
+	return func(o *StreamOptions) {
+		o.ColumnStats = true
+	}
+}
+
+// WithTempDir sets the directory used for spill files
+func WithTempDir(dir string) StreamOption {
+	return func(o *StreamOptions) {
+		o.TempDir = dir
+	}
+}
+
+// StreamStats contains the result counters of a stream run
+type StreamStats struct {
+	Chunks      int
+	RowsRead    int
+	RowsWritten int
+	Headers     []string
+	Columns     []StreamColumnStats // Statistics of the output columns, with WithColumnStats
+}
+
+// Stream reads a source in chunks, applies steps to each chunk and writes the result incrementally,
+// so files larger than memory can be cleaned.
+type Stream struct {
+	source formats.RowReader
+	steps  []StreamStep
+	opts   *StreamOptions
+}
+
+// NewStream creates a stream over the source
+func NewStream(source formats.RowReader, options ...StreamOption) *Stream {
+	opts := defaultStreamOptions()
+	for _, option := range options {
+		option(opts)
+	}
+	return &Stream{source: source, opts: opts}
+}
+
+// Then appends steps to the stream
+func (s *Stream) Then(steps ...StreamStep) *Stream {
+	s.steps = append(s.steps, steps...)
+	return s
+}
+
+// Run processes the whole source and writes the result to sink. Both source and sink are closed,
+// and spill files of stateful steps are removed. On failure, a sink implementing
+// formats.RowAborter is aborted instead of closed.
+func (s *Stream) Run(sink formats.RowWriter) (*StreamStats, error) {
+	for _, step := range s.steps {
+		if c, ok := step.(streamConfigurable); ok {
+			c.configure(s.opts)
+		}
+	}
+
+	stats, err := s.run(sink)
+	for _, step := range s.steps {
+		if c, ok := step.(io.Closer); ok {
+			if closeErr := c.Close(); err == nil && closeErr != nil {
+				err = fmt.Errorf("failed to clean up stream step: %w", closeErr)
+			}
+		}
+	}
+	if closeErr := s.source.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close stream source: %w", closeErr)
+	}
+	if aborter, ok := sink.(formats.RowAborter); ok && err != nil {
+		// Keep the previous output rather than replacing it with a partial one
+		aborter.Abort()
+	} else if closeErr := sink.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close stream sink: %w", closeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (s *Stream) run(sink formats.RowWriter) (*StreamStats, error) {
+	stats := &StreamStats{}
+	headers := s.source.Headers()
+	headersWritten := false
+	var columnStats *columnStatsCollector
+
+	emit := func(df *DataFrame) error {
+		if !headersWritten {
+			stats.Headers = df.Headers
+			if err := sink.WriteHeaders(df.Headers); err != nil {
+				return err
+			}
+			headersWritten = true
+			if s.opts.ColumnStats {
+				columnStats = newColumnStatsCollector(df.Headers)
+			}
+		}
+		if len(df.Data) == 0 {
+			return nil
+		}
+		stats.RowsWritten += len(df.Data)
+		if columnStats != nil {
+			columnStats.add(df.Data)
+		}
+		return sink.WriteRows(df.Data)
+	}
+
+	for {
+		if err := s.opts.Context.Err(); err != nil {
+			return nil, err
+		}
+
+		rows, err := s.source.Read(s.opts.ChunkSize)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d: %w", stats.Chunks, err)
+		}
+
+		// Steps may rename headers in place, so every chunk gets its own copy
+		chunk, err := NewDataFrame(append([]string(nil), headers...), rows)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d: %w", stats.Chunks, err)
+		}
+		stats.Chunks++
+		stats.RowsRead += len(rows)
+
+		out, err := s.apply(chunk, 0)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d: %w", stats.Chunks-1, err)
+		}
+		if err := emit(out); err != nil {
+			return nil, err
+		}
+	}
+
+	// Flush stateful steps in order; rows emitted by a flush pass through the following steps
+	for i, step := range s.steps {
+		flusher, ok := step.(StreamFlusher)
+		if !ok {
+			continue
+		}
+		err := flusher.Flush(func(df *DataFrame) error {
+			if err := s.opts.Context.Err(); err != nil {
+				return err
+			}
+			out, err := s.apply(df, i+1)
+			if err != nil {
+				return err
+			}
+			return emit(out)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("flush step %d: %w", i, err)
+		}
+	}
+
+	// Empty source: derive the output headers from an empty chunk
+	if !headersWritten {
+		out, err := s.emptyOutput(headers)
+		if err != nil {
+			return nil, err
+		}
+		if err := emit(out); err != nil {
+			return nil, err
+		}
+	}
+
+	if columnStats != nil {
+		stats.Columns = columnStats.result(stats.Headers)
+	}
+	return stats, nil
+}
+
+// apply runs the steps starting at index from on the chunk
+func (s *Stream) apply(chunk *DataFrame, from int) (*DataFrame, error) {
+	var err error
+	for i := from; i < len(s.steps); i++ {
+		if chunk, err = s.steps[i].ProcessChunk(chunk); err != nil {
+			return nil, fmt.Errorf("step %d: %w", i, err)
+		}
+	}
+	return chunk, nil
+}
+
+// emptyOutput passes an empty DataFrame through all steps to derive the output headers
+func (s *Stream) emptyOutput(headers []string) (*DataFrame, error) {
+	df, err := NewDataFrame(append([]string(nil), headers...), nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range s.steps {
+		if df, err = s.steps[i].ProcessChunk(df); err != nil {
+			return nil, fmt.Errorf("step %d: %w", i, err)
+		}
+	}
+	return df, nil
+}
+
+// TrimStep trims all cells of each chunk
+func TrimStep() StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.TrimColumns(), nil
+	})
+}
+
+// SanitizeControlCharsStep removes non-printable characters and normalizes exotic spaces in each chunk
+func SanitizeControlCharsStep(columns ...string) StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.SanitizeControlChars(columns...)
+	})
+}
+
+// NormalizeNumericFormatsStep converts the formatted numbers of the column into plain decimals in each chunk
+func NormalizeNumericFormatsStep(column string) StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.NormalizeNumericFormats(column)
+	})
+}
+
+// ConvertUnitsStep converts the numbers of the column from one unit to another in each chunk
+func ConvertUnitsStep(column, from, to string, options ...UnitOption) StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.ConvertUnits(column, from, to, options...)
+	})
+}
+
+// FakeColumnStep replaces the values of the column with deterministic fakes in each chunk
+func FakeColumnStep(column string, kind FakeKind, seed int64) StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.FakeColumn(column, kind, seed)
+	})
+}
+
+// ReplaceNullsStep replaces empty values of the column in each chunk
+func ReplaceNullsStep(column, defaultValue string) StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.ReplaceNulls(column, defaultValue)
+	})
+}
+
+// CleanDatesStep normalizes the dates of the column in each chunk
+func CleanDatesStep(column, layout string, options ...DateOption) StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.CleanDates(column, layout, options...)
+	})
+}
+
+// NormalizeCaseStep converts the column to upper or lower case in each chunk
+func NormalizeCaseStep(column string, toUpper bool) StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.NormalizeCase(column, toUpper)
+	})
+}
+
+// CleanWithRegexStep replaces regex matches in the column of each chunk.
+// The pattern is compiled once for the whole stream.
+func CleanWithRegexStep(column, pattern, replacement string) StreamStep {
+	re, compileErr := compileRegex(pattern)
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		if compileErr != nil {
+			return nil, compileErr
+		}
+		colIndex := chunk.getColumnIndex(column)
+		if colIndex == -1 {
+			return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
+		}
+		for i := range chunk.Data {
+			chunk.setCell(i, colIndex, re.ReplaceAllString(chunk.Data[i][colIndex], replacement))
+		}
+		return chunk, nil
+	})
+}
+
+// SplitColumnStep splits the column into new columns in each chunk
+func SplitColumnStep(column, separator string, newColumns []string) StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.SplitColumn(column, separator, newColumns)
+	})
+}
+
+// CoalesceColumnsStep merges the columns into newName in each chunk
+func CoalesceColumnsStep(newName string, columns ...string) StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.CoalesceColumns(newName, columns...)
+	})
+}
+
+// AddRowNumberStep adds the row number as the first column, numbering the rows across chunks
+func AddRowNumberStep(name string) StreamStep {
+	next := 1
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		first := next
+		next += len(chunk.Data)
+		return chunk.addRowNumber(name, first)
+	})
+}
+
+// NormalizeHeadersStep renames the columns to database-safe names in each chunk
+func NormalizeHeadersStep(style HeaderStyle, transliterate bool) StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.NormalizeHeaders(style, transliterate)
+	})
+}
+
+// RenameColumnStep renames the column in each chunk
+func RenameColumnStep(oldName, newName string) StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.RenameColumn(oldName, newName)
+	})
+}
+
+// FilterOutliersStep drops rows whose value is outside [min, max] in each chunk
+func FilterOutliersStep(column string, min, max float64) StreamStep {
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		return chunk.FilterOutliers(column, min, max)
+	})
+}
+
+// RequireReferencesStep drops the rows whose value of the column is missing from refColumn of ref in
+// each chunk
+func RequireReferencesStep(column string, ref *DataFrame, refColumn string) StreamStep {
+	var keys map[string]struct{}
+	return StreamStepFunc(func(chunk *DataFrame) (*DataFrame, error) {
+		colIndex, err := chunk.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		if keys == nil {
+			if keys, err = ref.referenceKeys(refColumn); err != nil {
+				return nil, err
+			}
+		}
+		keep := make([]bool, len(chunk.Data))
+		for i, row := range chunk.Data {
+			_, ok := keys[row[colIndex]]
+			keep[i] = ok || row[colIndex] == ""
+		}
+		chunk.retainRows(keep)
+		return chunk, nil
+	})
+}
+
+// columnIndices resolves column names to indices. No columns means all columns.
+func (df *DataFrame) columnIndices(columns []string) ([]int, error) {
+	if len(columns) == 0 {
+		indices := make([]int, len(df.Headers))
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices, nil
+	}
+
+	indices := make([]int, len(columns))
+	for i, column := range columns {
+		indices[i] = df.getColumnIndex(column)
+		if indices[i] == -1 {
+			return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
+		}
+	}
+	return indices, nil
+}
+
+// rowKey joins the values at indices into a single comparable key
+func rowKey(row []string, indices []int) string {
+	if len(indices) == 1 {
+		return row[indices[0]]
+	}
+	var sb strings.Builder
+	for i, idx := range indices {
+		if i > 0 {
+			sb.WriteByte(0)
+		}
+		sb.WriteString(row[idx])
+	}
+	return sb.String()
+}
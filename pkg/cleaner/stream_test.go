@@ -0,0 +1,263 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mstgnz/cleango/pkg/formats"
+)
+
+// collectWriter is a RowWriter that keeps everything in memory
+type collectWriter struct {
+	headers []string
+	rows    [][]string
+	writes  int
+	closed  bool
+}
+
+func (w *collectWriter) WriteHeaders(headers []string) error {
+	w.headers = headers
+	return nil
+}
+
+func (w *collectWriter) WriteRows(rows [][]string) error {
+	w.writes++
+	w.rows = append(w.rows, rows...)
+	return nil
+}
+
+func (w *collectWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestStream_ChunkSafeSteps(t *testing.T) {
+	source := formats.NewRawRowReader(
+		[]string{"name", "age", "created_at"},
+		[][]string{
+			{"  ali  ", "", "2024/01/15"},
+			{"ayşe", "25", "2024-02-20"},
+			{" mehmet", "", "15/03/2024"},
+		},
+	)
+	sink := &collectWriter{}
+
+	stats, err := NewStream(source, WithChunkSize(2)).
+		Then(
+			TrimStep(),
+			NormalizeCaseStep("name", true),
+			ReplaceNullsStep("age", "0"),
+			CleanDatesStep("created_at", "2006-01-02"),
+			RenameColumnStep("created_at", "created"),
+		).
+		Run(sink)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	if stats.Chunks != 2 || stats.RowsRead != 3 || stats.RowsWritten != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if sink.writes != 2 {
+		t.Errorf("expected output written per chunk, got %d writes", sink.writes)
+	}
+	if !sink.closed {
+		t.Error("sink should be closed")
+	}
+
+	expectedHeaders := []string{"name", "age", "created"}
+	if !reflect.DeepEqual(sink.headers, expectedHeaders) {
+		t.Errorf("headers = %v, expected = %v", sink.headers, expectedHeaders)
+	}
+	expected := [][]string{
+		{"ALI", "0", "2024-01-15"},
+		{"AYŞE", "25", "2024-02-20"},
+		{"MEHMET", "0", "2024-03-15"},
+	}
+	if !reflect.DeepEqual(sink.rows, expected) {
+		t.Errorf("rows = %v, expected = %v", sink.rows, expected)
+	}
+}
+
+func TestStream_DedupAcrossChunks(t *testing.T) {
+	source := formats.NewRawRowReader(
+		[]string{"email", "name"},
+		[][]string{
+			{"a@x.com", "A"},
+			{"b@x.com", "B"},
+			{"a@x.com", "A2"},
+			{"c@x.com", "C"},
+			{"b@x.com", "B2"},
+		},
+	)
+	sink := &collectWriter{}
+
+	stats, err := NewStream(source, WithChunkSize(2)).Then(DedupStep("email")).Run(sink)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	if stats.RowsRead != 5 || stats.RowsWritten != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	expected := [][]string{{"a@x.com", "A"}, {"b@x.com", "B"}, {"c@x.com", "C"}}
+	if !reflect.DeepEqual(sink.rows, expected) {
+		t.Errorf("rows = %v, expected = %v", sink.rows, expected)
+	}
+}
+
+func TestStream_SplitAndFilter(t *testing.T) {
+	source := formats.NewRawRowReader(
+		[]string{"full_name", "age"},
+		[][]string{{"Ali Veli", "30"}, {"Ayşe Kaya", "150"}},
+	)
+	sink := &collectWriter{}
+
+	_, err := NewStream(source).
+		Then(
+			SplitColumnStep("full_name", " ", []string{"first", "last"}),
+			FilterOutliersStep("age", 0, 120),
+			CleanWithRegexStep("last", "[aeiou]", "_"),
+		).
+		Run(sink)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	if !reflect.DeepEqual(sink.headers, []string{"first", "last", "age"}) {
+		t.Errorf("unexpected headers: %v", sink.headers)
+	}
+	if !reflect.DeepEqual(sink.rows, [][]string{{"Ali", "V_l_", "30"}}) {
+		t.Errorf("unexpected rows: %v", sink.rows)
+	}
+}
+
+func TestStream_EmptySourceWritesHeaders(t *testing.T) {
+	source := formats.NewRawRowReader([]string{"full_name"}, nil)
+	sink := &collectWriter{}
+
+	stats, err := NewStream(source).
+		Then(SplitColumnStep("full_name", " ", []string{"first", "last"})).
+		Run(sink)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	if stats.Chunks != 0 || !reflect.DeepEqual(sink.headers, []string{"first", "last"}) {
+		t.Errorf("unexpected result: %+v headers=%v", stats, sink.headers)
+	}
+}
+
+func TestStream_StepError(t *testing.T) {
+	source := formats.NewRawRowReader([]string{"name"}, [][]string{{"Ali"}})
+	sink := &collectWriter{}
+
+	_, err := NewStream(source).Then(ReplaceNullsStep("missing", "x")).Run(sink)
+	if !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if !sink.closed {
+		t.Error("sink should be closed on error")
+	}
+}
+
+func TestStream_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	source := formats.NewRawRowReader([]string{"name"}, [][]string{{"Ali"}})
+	_, err := NewStream(source, WithStreamContext(ctx)).Then(TrimStep()).Run(&collectWriter{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestStream_CSVFiles(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.csv")
+	output := filepath.Join(dir, "out.csv")
+	if err := os.WriteFile(input, []byte("id,name\n1, Ali \n2,Ayşe\n1, Ali \n"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	source, err := formats.NewCSVRowReader(input)
+	if err != nil {
+		t.Fatalf("NewCSVRowReader error: %v", err)
+	}
+	sink, err := formats.NewCSVRowWriter(output)
+	if err != nil {
+		t.Fatalf("NewCSVRowWriter error: %v", err)
+	}
+
+	if _, err := NewStream(source, WithChunkSize(1)).Then(TrimStep(), DedupStep()).Run(sink); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	content, _ := os.ReadFile(output)
+	if string(content) != "id,name\n1,Ali\n2,Ayşe\n" {
+		t.Errorf("unexpected output: %q", content)
+	}
+}
+
+func TestAddRowNumberStep(t *testing.T) {
+	step := AddRowNumberStep("row")
+	for _, want := range [][]string{{"1", "2"}, {"3"}} {
+		data := make([][]string, len(want))
+		for i := range data {
+			data[i] = []string{"x"}
+		}
+		chunk, _ := NewDataFrame([]string{"v"}, data)
+		out, err := step.ProcessChunk(chunk)
+		if err != nil {
+			t.Fatalf("ProcessChunk error: %v", err)
+		}
+		for i, row := range out.Data {
+			if row[0] != want[i] {
+				t.Errorf("expected row number %s, got %s", want[i], row[0])
+			}
+		}
+	}
+}
+
+func TestStream_ColumnStats(t *testing.T) {
+	source := formats.NewRawRowReader(
+		[]string{"name", "age"},
+		[][]string{{" ali ", "30"}, {"veli", "9"}, {"", "120"}, {"ali", " "}, {"zeynep", "abc"}},
+	)
+	sink := &collectWriter{}
+
+	stats, err := NewStream(source, WithChunkSize(2), WithColumnStats()).
+		Then(TrimStep(), RenameColumnStep("name", "first_name")).
+		Run(sink)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	expected := []StreamColumnStats{
+		{Column: "first_name", Count: 4, Nulls: 1, Distinct: 3, Min: "ali", Max: "zeynep"},
+		{Column: "age", Count: 4, Nulls: 1, Distinct: 4, Min: "120", Max: "abc"},
+	}
+	if !reflect.DeepEqual(stats.Columns, expected) {
+		t.Errorf("unexpected column stats: %+v", stats.Columns)
+	}
+
+	source = formats.NewRawRowReader([]string{"age"}, [][]string{{"30"}, {"9"}, {"120"}})
+	stats, err = NewStream(source, WithColumnStats()).Run(&collectWriter{})
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	numeric := StreamColumnStats{Column: "age", Numeric: true, Count: 3, Distinct: 3, Min: "9", Max: "120"}
+	if !reflect.DeepEqual(stats.Columns, []StreamColumnStats{numeric}) {
+		t.Errorf("unexpected column stats: %+v", stats.Columns)
+	}
+
+	// Without the option nothing is gathered
+	source = formats.NewRawRowReader([]string{"age"}, [][]string{{"30"}})
+	if stats, _ := NewStream(source).Run(&collectWriter{}); stats.Columns != nil {
+		t.Errorf("expected no column stats, got %+v", stats.Columns)
+	}
+}
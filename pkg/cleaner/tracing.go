@@ -0,0 +1,52 @@
+package cleaner
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingObserver is an Observer that records every operation as an OpenTelemetry span named
+// "cleango.<operation>", with the column and row counts as attributes. Steps of a pipeline become
+// children of the pipeline span.
+type TracingObserver struct {
+	tracer trace.Tracer
+}
+
+// NewTracingObserver creates an observer that starts spans with tracer, e.g.
+// otel.Tracer("github.com/mstgnz/cleango")
+func NewTracingObserver(tracer trace.Tracer) *TracingObserver {
+	return &TracingObserver{tracer: tracer}
+}
+
+// OperationStarted starts the span of the operation
+func (o *TracingObserver) OperationStarted(ctx context.Context, event OperationEvent) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	attributes := []attribute.KeyValue{
+		attribute.String("cleango.operation", event.Operation),
+		attribute.Int("cleango.rows_in", event.RowsIn),
+	}
+	if event.Column != "" {
+		attributes = append(attributes, attribute.String("cleango.column", event.Column))
+	}
+	ctx, _ = o.tracer.Start(ctx, "cleango."+event.Operation, trace.WithTimestamp(event.Start), trace.WithAttributes(attributes...))
+	return ctx
+}
+
+// OperationFinished ends the span of the operation, recording its error if it failed
+func (o *TracingObserver) OperationFinished(ctx context.Context, event OperationEvent) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("cleango.rows_out", event.RowsOut),
+		attribute.Float64("cleango.duration_ms", float64(event.Duration.Microseconds())/1000),
+	)
+	if event.Err != nil {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	}
+	span.End(trace.WithTimestamp(event.Start.Add(event.Duration)))
+}
@@ -0,0 +1,79 @@
+package cleaner
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingObserver(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	observer := NewTracingObserver(provider.Tracer("test"))
+
+	_, err := NewPipeline().
+		Trim().
+		NormalizeCase("name", true).
+		Run(cowTestDataFrame(), WithObserver(observer))
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) < 2 {
+		t.Fatalf("expected pipeline and step spans, got %d", len(spans))
+	}
+	byName := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range spans {
+		byName[span.Name()] = span
+	}
+	pipeline, ok := byName["cleango.pipeline"]
+	if !ok {
+		t.Fatalf("expected a cleango.pipeline span, got %v", spanNames(spans))
+	}
+	for _, span := range spans {
+		if span != pipeline && span.Parent().SpanID() != pipeline.SpanContext().SpanID() {
+			t.Errorf("span %s is not a child of the pipeline span", span.Name())
+		}
+	}
+
+	attributes := make(map[attribute.Key]attribute.Value)
+	for _, kv := range pipeline.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	if attributes["cleango.rows_in"].AsInt64() != 3 || attributes["cleango.rows_out"].AsInt64() != 3 {
+		t.Errorf("unexpected row attributes: %v", pipeline.Attributes())
+	}
+	if _, ok := attributes["cleango.duration_ms"]; !ok {
+		t.Errorf("expected a duration attribute")
+	}
+}
+
+func TestTracingObserverRecordsErrors(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, err := cowTestDataFrame().NormalizeCaseParallel("missing", true, WithObserver(NewTracingObserver(provider.Tracer("test"))))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error || len(spans[0].Events()) == 0 {
+		t.Errorf("expected the error to be recorded, got status %v", spans[0].Status())
+	}
+}
+
+func spanNames(spans []sdktrace.ReadOnlySpan) []string {
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name()
+	}
+	return names
+}
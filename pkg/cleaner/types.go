@@ -0,0 +1,159 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// typeNames, names of the column types
+var typeNames = map[Type]string{
+	TypeString: "string",
+	TypeInt:    "int",
+	TypeFloat:  "float",
+	TypeDate:   "date",
+	TypeBool:   "bool",
+	TypeJSON:   "json",
+}
+
+// String returns the name of the type
+func (t Type) String() string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// MarshalText encodes the type by name so type maps serialize as {"age": "int"}
+func (t Type) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes a type from its name
+func (t *Type) UnmarshalText(text []byte) error {
+	for typ, name := range typeNames {
+		if name == string(text) {
+			*t = typ
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown type: %q", text)
+}
+
+// InferType returns the narrowest type that fits all non-empty values.
+// Integers widen to float, anything else that is not a number or boolean is a string.
+func InferType(values []string) Type {
+	result := Type(-1)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		var t Type
+		switch {
+		case isInt(value):
+			t = TypeInt
+		case isFloat(value):
+			t = TypeFloat
+		case value == "true" || value == "false":
+			t = TypeBool
+		default:
+			return TypeString
+		}
+
+		switch {
+		case result == -1 || result == t:
+			result = t
+		case (result == TypeInt && t == TypeFloat) || (result == TypeFloat && t == TypeInt):
+			result = TypeFloat
+		default:
+			return TypeString
+		}
+	}
+
+	if result == -1 {
+		return TypeString
+	}
+	return result
+}
+
+// InferTypes sets the type of every string column from its values.
+// Columns with a type assigned by a cleaning operation (e.g. dates) and the columns of
+// WithStringColumns are left untouched.
+func (df *DataFrame) InferTypes() *DataFrame {
+	values := make([]string, len(df.Data))
+	for colIndex, header := range df.Headers {
+		if t, ok := df.Types[header]; ok && t != TypeString || slices.Contains(df.stringColumns, header) {
+			continue
+		}
+		for i, row := range df.Data {
+			values[i] = row[colIndex]
+		}
+		df.Types[header] = InferType(values)
+	}
+	return df
+}
+
+// WithStringColumns keeps the columns as strings: their type is set to string, InferTypes leaves
+// them untouched and every writer, including the Parquet and Excel writers that otherwise guess
+// types from the values, writes them as text. Use it for numeric-looking identifiers such as
+// "007" or phone numbers, whose leading zeros would be lost as numbers.
+func (df *DataFrame) WithStringColumns(columns []string) *DataFrame {
+	for _, column := range columns {
+		if !slices.Contains(df.stringColumns, column) {
+			df.stringColumns = append(df.stringColumns, column)
+		}
+		if _, ok := df.Types[column]; ok {
+			df.Types[column] = TypeString
+		}
+	}
+	return df
+}
+
+// StringColumns returns the columns set with WithStringColumns
+func (df *DataFrame) StringColumns() []string {
+	return df.stringColumns
+}
+
+// ParseValue converts a cell into its native Go value for the given type.
+// Empty cells become nil and values that no longer match the type are returned as strings.
+func ParseValue(value string, t Type) interface{} {
+	if value == "" {
+		return nil
+	}
+
+	switch t {
+	case TypeInt:
+		if v, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+			return v
+		}
+	case TypeFloat:
+		if v, err := parseFloat(value); err == nil {
+			return v
+		}
+	case TypeBool:
+		if v, err := strconv.ParseBool(strings.TrimSpace(value)); err == nil {
+			return v
+		}
+	case TypeJSON:
+		if json.Valid([]byte(value)) {
+			return json.RawMessage(value)
+		}
+	}
+	return value
+}
+
+// isInt reports whether s is a base 10 integer
+func isInt(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err == nil
+}
+
+// isFloat reports whether s is a floating point number
+func isFloat(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
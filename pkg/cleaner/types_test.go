@@ -0,0 +1,137 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTypeString(t *testing.T) {
+	tests := map[Type]string{
+		TypeString: "string",
+		TypeInt:    "int",
+		TypeFloat:  "float",
+		TypeDate:   "date",
+		TypeBool:   "bool",
+		TypeJSON:   "json",
+		Type(99):   "unknown",
+	}
+
+	for typ, expected := range tests {
+		if typ.String() != expected {
+			t.Errorf("Type(%d).String() = %q, expected = %q", int(typ), typ.String(), expected)
+		}
+	}
+
+	b, err := json.Marshal(map[string]Type{"age": TypeInt})
+	if err != nil {
+		t.Fatalf("json.Marshal error: %v", err)
+	}
+	if string(b) != `{"age":"int"}` {
+		t.Errorf("json.Marshal(types) = %s, expected = {\"age\":\"int\"}", b)
+	}
+
+	var decoded map[string]Type
+	if err := json.Unmarshal(b, &decoded); err != nil || decoded["age"] != TypeInt {
+		t.Errorf("json.Unmarshal(%s) = %v, %v, expected age to be int", b, decoded, err)
+	}
+	var typ Type
+	if err := typ.UnmarshalText([]byte("decimal")); err == nil {
+		t.Error("expected an error for an unknown type name")
+	}
+}
+
+func TestInferType(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected Type
+	}{
+		{"Integers", []string{"1", "2", "", "30"}, TypeInt},
+		{"Mixed numbers", []string{"1", "2.5"}, TypeFloat},
+		{"Booleans", []string{"true", "false", ""}, TypeBool},
+		{"Strings", []string{"1", "abc"}, TypeString},
+		{"Number and bool", []string{"1", "true"}, TypeString},
+		{"All empty", []string{"", ""}, TypeString},
+		{"Leading zero stays int", []string{"007"}, TypeInt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InferType(tt.values); got != tt.expected {
+				t.Errorf("InferType(%v) = %v, expected = %v", tt.values, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestInferTypes(t *testing.T) {
+	df, err := NewDataFrame([]string{"name", "age", "score", "active", "created_at"}, [][]string{
+		{"Ali", "30", "1.5", "true", "2024-01-01"},
+		{"Ayşe", "", "2", "false", "2024-01-02"},
+	})
+	if err != nil {
+		t.Fatalf("DataFrame creation failed: %v", err)
+	}
+	df.Types["created_at"] = TypeDate
+
+	df.InferTypes()
+
+	expected := map[string]Type{
+		"name":       TypeString,
+		"age":        TypeInt,
+		"score":      TypeFloat,
+		"active":     TypeBool,
+		"created_at": TypeDate,
+	}
+	for column, typ := range expected {
+		if df.Types[column] != typ {
+			t.Errorf("Types[%s] = %v, expected = %v", column, df.Types[column], typ)
+		}
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		value    string
+		typ      Type
+		expected interface{}
+	}{
+		{"42", TypeInt, int64(42)},
+		{"4.5", TypeFloat, 4.5},
+		{"true", TypeBool, true},
+		{"", TypeInt, nil},
+		{"", TypeString, nil},
+		{"N/A", TypeInt, "N/A"},
+		{"2024-01-01", TypeDate, "2024-01-01"},
+		{"42", TypeString, "42"},
+	}
+
+	for _, tt := range tests {
+		if got := ParseValue(tt.value, tt.typ); got != tt.expected {
+			t.Errorf("ParseValue(%q, %v) = %#v, expected = %#v", tt.value, tt.typ, got, tt.expected)
+		}
+	}
+
+	raw, ok := ParseValue(`{"a":1}`, TypeJSON).(json.RawMessage)
+	if !ok || string(raw) != `{"a":1}` {
+		t.Errorf("ParseValue(json) = %#v, expected raw JSON", raw)
+	}
+}
+
+func TestWithStringColumns(t *testing.T) {
+	df, _ := NewDataFrame([]string{"id", "age"}, [][]string{{"007", "30"}, {"042", "25"}})
+	df.InferTypes()
+
+	df.WithStringColumns([]string{"id"})
+	if df.Types["id"] != TypeString {
+		t.Errorf("expected id to become a string column, got %v", df.Types["id"])
+	}
+
+	df.InferTypes()
+	if df.Types["id"] != TypeString || df.Types["age"] != TypeInt {
+		t.Errorf("expected InferTypes to leave id untouched, got %v", df.Types)
+	}
+	if got := df.Copy().StringColumns(); len(got) != 1 || got[0] != "id" {
+		t.Errorf("expected copies to keep the string columns, got %v", got)
+	}
+}
@@ -0,0 +1,156 @@
+package cleaner
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownUnit is returned for a unit ConvertUnits does not know
+var ErrUnknownUnit = errors.New("unknown unit")
+
+// ErrIncompatibleUnits is returned for units of different families, e.g. kg and km
+var ErrIncompatibleUnits = errors.New("incompatible units")
+
+// unit is a unit of measure. A value in the unit is (value+offset)*factor in the base unit of its
+// family: grams, meters, degrees Celsius or bytes.
+type unit struct {
+	family string
+	factor float64
+	offset float64
+}
+
+// units are the known units, keyed by their lower case name
+var units = map[string]unit{
+	// Mass
+	"mg": {family: "mass", factor: 0.001},
+	"g":  {family: "mass", factor: 1},
+	"kg": {family: "mass", factor: 1000},
+	"t":  {family: "mass", factor: 1e6},
+	"oz": {family: "mass", factor: 28.349523125},
+	"lb": {family: "mass", factor: 453.59237},
+
+	// Length
+	"mm": {family: "length", factor: 0.001},
+	"cm": {family: "length", factor: 0.01},
+	"m":  {family: "length", factor: 1},
+	"km": {family: "length", factor: 1000},
+	"in": {family: "length", factor: 0.0254},
+	"ft": {family: "length", factor: 0.3048},
+	"yd": {family: "length", factor: 0.9144},
+	"mi": {family: "length", factor: 1609.344},
+
+	// Temperature
+	"c": {family: "temperature", factor: 1},
+	"f": {family: "temperature", factor: 5.0 / 9, offset: -32},
+	"k": {family: "temperature", factor: 1, offset: -273.15},
+
+	// Data size; KB, MB, ... are decimal and KiB, MiB, ... binary
+	"b":   {family: "bytes", factor: 1},
+	"kb":  {family: "bytes", factor: 1e3},
+	"mb":  {family: "bytes", factor: 1e6},
+	"gb":  {family: "bytes", factor: 1e9},
+	"tb":  {family: "bytes", factor: 1e12},
+	"kib": {family: "bytes", factor: 1 << 10},
+	"mib": {family: "bytes", factor: 1 << 20},
+	"gib": {family: "bytes", factor: 1 << 30},
+	"tib": {family: "bytes", factor: 1 << 40},
+}
+
+// unitAliases are the other accepted names of the units
+var unitAliases = map[string]string{
+	"lbs": "lb", "°c": "c", "°f": "f", "celsius": "c", "fahrenheit": "f", "kelvin": "k",
+	"byte": "b", "bytes": "b",
+}
+
+// lookupUnit returns the unit with the given name, ignoring case
+func lookupUnit(name string) (unit, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := unitAliases[key]; ok {
+		key = alias
+	}
+	u, ok := units[key]
+	if !ok {
+		return unit{}, fmt.Errorf("%w: %s", ErrUnknownUnit, name)
+	}
+	return u, nil
+}
+
+// UnitOption configures ConvertUnits
+type UnitOption func(*unitOptions)
+
+type unitOptions struct {
+	precision int
+}
+
+// WithUnitPrecision rounds the converted values to the given number of decimals. By default they
+// are rounded to 12 significant digits and written in their shortest representation.
+func WithUnitPrecision(decimals int) UnitOption {
+	return func(o *unitOptions) {
+		o.precision = decimals
+	}
+}
+
+// unitConverter returns the function converting a value from one unit to the other and
+// formatting it with the options
+func unitConverter(from, to string, options []UnitOption) (func(value string) (string, error), error) {
+	fromUnit, err := lookupUnit(from)
+	if err != nil {
+		return nil, err
+	}
+	toUnit, err := lookupUnit(to)
+	if err != nil {
+		return nil, err
+	}
+	if fromUnit.family != toUnit.family {
+		return nil, fmt.Errorf("%w: %s (%s) and %s (%s)", ErrIncompatibleUnits, from, fromUnit.family, to, toUnit.family)
+	}
+	opts := unitOptions{precision: -1}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return func(value string) (string, error) {
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return "", fmt.Errorf("conversion error: %w", err)
+		}
+		converted := (v+fromUnit.offset)*fromUnit.factor/toUnit.factor - toUnit.offset
+		if opts.precision < 0 {
+			// Drop the floating point noise of the conversion, e.g. 211.99999999999991 for 100 °C
+			converted, _ = strconv.ParseFloat(strconv.FormatFloat(converted, 'g', 12, 64), 64)
+		}
+		return strconv.FormatFloat(converted, 'f', opts.precision, 64), nil
+	}, nil
+}
+
+// ConvertUnits converts the numbers of the column from one unit to another of the same family:
+// mass (mg, g, kg, t, oz, lb), length (mm, cm, m, km, in, ft, yd, mi), temperature (C, F, K, also
+// written °C and °F) or data size (B, KB, MB, GB, TB and the binary KiB, MiB, GiB, TiB). Unit names
+// ignore case. Empty values are left unchanged and a value that is not a number is an error.
+func (df *DataFrame) ConvertUnits(column, from, to string, options ...UnitOption) (*DataFrame, error) {
+	colIndex := df.getColumnIndex(column)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, column)
+	}
+	convert, err := unitConverter(from, to, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range df.Data {
+		value := df.Data[i][colIndex]
+		if value == "" {
+			continue
+		}
+		converted, err := convert(value)
+		if err != nil {
+			return nil, fmt.Errorf("row %d, column %s: %w", i, column, err)
+		}
+		df.setCell(i, colIndex, converted)
+	}
+
+	df.Types[column] = TypeFloat
+	return df, nil
+}
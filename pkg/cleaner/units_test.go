@@ -0,0 +1,73 @@
+package cleaner
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConvertUnits(t *testing.T) {
+	cases := []struct {
+		value, from, to string
+		options         []UnitOption
+		want            string
+	}{
+		{"10", "lb", "kg", nil, "4.5359237"},
+		{"10", "LBS", "kg", []UnitOption{WithUnitPrecision(2)}, "4.54"},
+		{"5", "km", "mi", []UnitOption{WithUnitPrecision(3)}, "3.107"},
+		{"100", "°C", "°F", nil, "212"},
+		{"98.6", "F", "C", nil, "37"},
+		{"-40", "f", "c", nil, "-40"},
+		{"0", "C", "K", nil, "273.15"},
+		{"1", "GiB", "MB", nil, "1073.741824"},
+		{"2048", "KiB", "MiB", nil, "2"},
+	}
+	for _, c := range cases {
+		df, _ := NewDataFrame([]string{"v"}, [][]string{{c.value}, {""}})
+		if _, err := df.ConvertUnits("v", c.from, c.to, c.options...); err != nil {
+			t.Errorf("%s %s to %s: unexpected error: %v", c.value, c.from, c.to, err)
+			continue
+		}
+		if got := df.Data[0][0]; got != c.want {
+			t.Errorf("%s %s to %s: expected %s, got %s", c.value, c.from, c.to, c.want, got)
+		}
+		if df.Data[1][0] != "" {
+			t.Errorf("expected the empty value to be left unchanged, got %q", df.Data[1][0])
+		}
+		if df.Types["v"] != TypeFloat {
+			t.Errorf("expected float type, got %v", df.Types["v"])
+		}
+	}
+}
+
+func TestConvertUnits_Errors(t *testing.T) {
+	df, _ := NewDataFrame([]string{"v"}, [][]string{{"1"}, {"heavy"}})
+
+	if _, err := df.ConvertUnits("v", "kg", "km"); !errors.Is(err, ErrIncompatibleUnits) {
+		t.Errorf("expected ErrIncompatibleUnits, got %v", err)
+	}
+	if _, err := df.ConvertUnits("v", "stone", "kg"); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("expected ErrUnknownUnit, got %v", err)
+	}
+	if _, err := df.ConvertUnits("v", "kg", "g"); err == nil {
+		t.Error("expected an error for a value that is not a number")
+	}
+	if _, err := df.ConvertUnits("w", "kg", "g"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestPipelineConvertUnits(t *testing.T) {
+	df, _ := NewDataFrame([]string{"temp"}, [][]string{{"32"}, {"212"}})
+
+	result, err := NewPipeline().ConvertUnits("temp", "F", "C").Run(df)
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if result.Data[0][0] != "0" || result.Data[1][0] != "100" {
+		t.Errorf("expected 0 and 100, got %v", result.Data)
+	}
+
+	if _, err := NewPipeline().ConvertUnits("temp", "F", "kg").Run(df); !errors.Is(err, ErrIncompatibleUnits) {
+		t.Errorf("expected ErrIncompatibleUnits, got %v", err)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // ErrColumnNotFound is the error returned when a column is not found
@@ -18,65 +20,148 @@ func trimSpace(s string) string {
 	return strings.TrimSpace(s)
 }
 
-// toUpperCase converts a string to uppercase
+// toUpperCase converts a string to uppercase with the case mapping of the default locale
 func toUpperCase(s string) string {
+	if c := caseMapping(); c != nil {
+		return strings.ToUpperSpecial(c, s)
+	}
 	return strings.ToUpper(s)
 }
 
-// toLowerCase converts a string to lowercase
+// toLowerCase converts a string to lowercase with the case mapping of the default locale
 func toLowerCase(s string) string {
+	if c := caseMapping(); c != nil {
+		return strings.ToLowerSpecial(c, s)
+	}
 	return strings.ToLower(s)
 }
 
+// sanitizeControlChars removes control and format characters such as zero-width spaces and byte
+// order marks, and replaces tabs, line breaks and other Unicode spaces such as NBSP with a space
+func sanitizeControlChars(s string) string {
+	clean := true
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] >= 0x7f {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return s
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return r
+		case unicode.IsSpace(r) || unicode.In(r, unicode.Zs, unicode.Zl, unicode.Zp):
+			return ' '
+		case unicode.In(r, unicode.Cc, unicode.Cf):
+			return -1
+		}
+		return r
+	}, s)
+}
+
 // parseFloat converts a string to float64
 func parseFloat(s string) (float64, error) {
 	return strconv.ParseFloat(strings.TrimSpace(s), 64)
 }
 
-// compileRegex compiles a regex pattern
+// compileRegex compiles a regex pattern or a named pattern reference such as "@email", reusing
+// compiled patterns from the regex cache
 func compileRegex(pattern string) (*regexp.Regexp, error) {
+	pattern, err := resolvePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if re, ok := regexCache.Get(pattern); ok {
+		return re, nil
+	}
 	re, err := regexp.Compile(pattern)
 	if err != nil {
 		return nil, fmt.Errorf("invalid regex pattern: %w", err)
 	}
+	regexCache.Put(pattern, re)
 	return re, nil
 }
 
-// parseDate converts a string to time.Time
+// commonDateLayouts are the input layouts tried after the user-provided layout
+var commonDateLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"02-01-2006",
+	"02/01/2006",
+	"01-02-2006",
+	"01/02/2006",
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05",
+	"02-01-2006 15:04:05",
+	"02/01/2006 15:04:05",
+	"01-02-2006 15:04:05",
+	"01/02/2006 15:04:05",
+	time.RFC3339,
+	time.RFC3339Nano,
+	time.RFC1123,
+	time.RFC1123Z,
+	time.RFC822,
+	time.RFC822Z,
+	time.ANSIC,
+	time.UnixDate,
+	time.RubyDate,
+}
+
+// dateLayouts returns the input layouts to try for the output layout, in order: the layout, the
+// default date formats and the common layouts
+func dateLayouts(layout string) []string {
+	// The key includes the date formats, so layouts cached before SetDefaults are never returned
+	extra := defaults.Load().DateFormats
+	key := layout
+	if len(extra) > 0 {
+		key += "\n" + strings.Join(extra, "\n")
+	}
+	if formats, ok := dateLayoutCache.Get(key); ok {
+		return formats
+	}
+
+	formats := make([]string, 0, len(extra)+len(commonDateLayouts)+1)
+	formats = append(formats, layout)
+	for _, format := range slices.Concat(extra, commonDateLayouts) {
+		if !slices.Contains(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+	dateLayoutCache.Put(key, formats)
+	return formats
+}
+
+// parseDate converts a string to time.Time. Integers no layout matches are read as Unix timestamps
+// in the unit detected from their length.
 func parseDate(s string, layout string) (time.Time, error) {
-	// Try different formats
-	formats := []string{
-		layout,
-		"2006-01-02",
-		"2006/01/02",
-		"02-01-2006",
-		"02/01/2006",
-		"01-02-2006",
-		"01/02/2006",
-		"2006-01-02 15:04:05",
-		"2006/01/02 15:04:05",
-		"02-01-2006 15:04:05",
-		"02/01/2006 15:04:05",
-		"01-02-2006 15:04:05",
-		"01/02/2006 15:04:05",
-		time.RFC3339,
-		time.RFC3339Nano,
-		time.RFC1123,
-		time.RFC1123Z,
-		time.RFC822,
-		time.RFC822Z,
-		time.ANSIC,
-		time.UnixDate,
-		time.RubyDate,
+	return parseDateEpoch(s, layout, EpochAuto)
+}
+
+// parseDateEpoch converts a string to time.Time, reading integers as Unix timestamps in the given
+// unit before trying the layouts unless the unit is EpochAuto
+func parseDateEpoch(s string, layout string, epoch EpochUnit) (time.Time, error) {
+	if epoch != EpochAuto {
+		if t, ok := parseEpoch(s, epoch); ok {
+			return t, nil
+		}
 	}
 
-	for _, format := range formats {
+	for _, format := range dateLayouts(layout) {
 		t, err := time.Parse(format, s)
 		if err == nil {
 			return t, nil
 		}
 	}
 
+	if epoch == EpochAuto {
+		if t, ok := parseEpoch(s, EpochAuto); ok {
+			return t, nil
+		}
+	}
 	return time.Time{}, fmt.Errorf("could not parse date: %s", s)
 }
 
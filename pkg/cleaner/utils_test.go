@@ -192,3 +192,24 @@ func TestSortInts(t *testing.T) {
 		}
 	}
 }
+
+func TestSanitizeControlChars(t *testing.T) {
+	cases := map[string]string{
+		"plain text":          "plain text",
+		"a\u00a0b":            "a b",
+		"zero\u200bwidth":     "zerowidth",
+		"\ufeffbom":           "bom",
+		"tab\there":           "tab here",
+		"line\r\nbreak":       "line  break",
+		"bell\a and null\x00": "bell and null",
+		"narrow\u202fspace":   "narrow space",
+		"Ayşe\u3000":          "Ayşe ",
+		"soft\u00adhyphen":    "softhyphen",
+		"line\u2028separator": "line separator",
+	}
+	for input, want := range cases {
+		if got := sanitizeControlChars(input); got != want {
+			t.Errorf("sanitizeControlChars(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
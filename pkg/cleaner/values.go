@@ -0,0 +1,138 @@
+package cleaner
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// ValueCount is a value of a column and the number of rows holding it
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Unique returns the distinct values of the column, sorted, e.g. to spot typos and casing
+// variants such as "Ankara" and "ankara" in a categorical column. The empty value is included
+// if the column has one. Values are compared exactly.
+func (df *DataFrame) Unique(column string) ([]string, error) {
+	counts, err := df.countValues(column)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	slices.Sort(values)
+	return values, nil
+}
+
+// ValueCounts returns the distinct values of the column with the number of rows holding each,
+// the most frequent first and values with the same count in sorted order:
+//
+//	counts, err := df.ValueCounts("status")
+//	for _, c := range counts {
+//		fmt.Println(c.Value, c.Count)
+//	}
+//
+// Rare values at the end of the list are often the typos. The empty value is counted like the
+// others.
+func (df *DataFrame) ValueCounts(column string) ([]ValueCount, error) {
+	counts, err := df.countValues(column)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]ValueCount, 0, len(counts))
+	for value, count := range counts {
+		pairs = append(pairs, ValueCount{Value: value, Count: count})
+	}
+	slices.SortFunc(pairs, func(a, b ValueCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return pairs, nil
+}
+
+// ApproxDistinct estimates the number of distinct values of the column, like len(Unique(column))
+// but in fixed memory of 16 KB, e.g. for columns with hundreds of millions of values. The
+// estimate is within about 1% of the exact count.
+func (df *DataFrame) ApproxDistinct(column string) (int, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return 0, err
+	}
+	sketch := newHyperLogLog()
+	for _, row := range df.Data {
+		sketch.add(row[colIndex])
+	}
+	return min(sketch.count(), len(df.Data)), nil
+}
+
+// TopK returns the k most frequent values of the column, like the first k of ValueCounts but
+// counted with a count-min sketch in fixed memory, e.g. for columns with hundreds of millions of
+// values. Counts are estimates that may exceed the exact ones by about 0.02% of the rows, so
+// values of nearly the same frequency may swap places. Fewer than k values are returned if the
+// column has fewer distinct values.
+func (df *DataFrame) TopK(column string, k int) ([]ValueCount, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	if k < 0 {
+		return nil, fmt.Errorf("k must not be negative: %d", k)
+	}
+	top := newTopK(k)
+	for _, row := range df.Data {
+		top.add(row[colIndex])
+	}
+	return top.result(), nil
+}
+
+// countValues returns the number of rows holding every value of the column
+func (df *DataFrame) countValues(column string) (map[string]int, error) {
+	colIndex, err := df.requireColumn(column)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, row := range df.Data {
+		counts[row[colIndex]]++
+	}
+	return counts, nil
+}
+
+// MapValues replaces the values of the column found in mapping with their mapped value in one
+// pass, e.g. coded values ("01" to "Active") or known misspellings ("Istambul" to "Istanbul").
+// Values not in mapping are kept if keepUnmapped is true and emptied otherwise, e.g. to fill them
+// with ReplaceNulls later. Values are compared exactly; an empty value is only replaced if
+// mapping has the empty key.
+func (df *DataFrame) MapValues(column string, mapping map[string]string, keepUnmapped bool) (*DataFrame, error) {
+	return df.mapColumn(column, valueMapper(mapping, keepUnmapped))
+}
+
+// MapValuesParallel replaces the values of the column like MapValues, in parallel
+func (df *DataFrame) MapValuesParallel(column string, mapping map[string]string, keepUnmapped bool, options ...func(*ParallelOptions)) (*DataFrame, error) {
+	return df.runObserved("map_values", column, options, func(opts *ParallelOptions) (*DataFrame, error) {
+		colIndex, err := df.requireColumn(column)
+		if err != nil {
+			return nil, err
+		}
+		return df.parallelizeColumns(opts, []int{colIndex}, valueMapper(mapping, keepUnmapped))
+	})
+}
+
+// valueMapper returns the value of mapping for a value, the value itself or "" if it is unmapped
+func valueMapper(mapping map[string]string, keepUnmapped bool) func(value string) string {
+	return func(value string) string {
+		if mapped, ok := mapping[value]; ok {
+			return mapped
+		}
+		if keepUnmapped {
+			return value
+		}
+		return ""
+	}
+}
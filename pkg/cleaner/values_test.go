@@ -0,0 +1,135 @@
+package cleaner
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestUniqueAndValueCounts(t *testing.T) {
+	df, _ := NewDataFrame([]string{"city"}, [][]string{
+		{"Izmir"}, {"ankara"}, {"Ankara"}, {""}, {"Izmir"}, {"Ankara"}, {"Izmir"},
+	})
+
+	unique, err := df.Unique("city")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(unique, []string{"", "Ankara", "Izmir", "ankara"}) {
+		t.Errorf("unexpected unique values: %q", unique)
+	}
+
+	counts, err := df.ValueCounts("city")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []ValueCount{{"Izmir", 3}, {"Ankara", 2}, {"", 1}, {"ankara", 1}}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("unexpected value counts: %v", counts)
+	}
+
+	if _, err := df.Unique("missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := df.ValueCounts("missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	status := map[string]string{"01": "Active", "02": "Inactive", "": "Unknown"}
+
+	df, _ := NewDataFrame([]string{"status"}, [][]string{{"01"}, {"02"}, {"09"}, {""}})
+	result, err := df.MapValues("status", status, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Data, [][]string{{"Active"}, {"Inactive"}, {"09"}, {"Unknown"}}) {
+		t.Errorf("unexpected rows: %v", result.Data)
+	}
+
+	df, _ = NewDataFrame([]string{"status"}, [][]string{{"01"}, {"02"}, {"09"}, {""}})
+	result, err = df.MapValuesParallel("status", status, false, WithMaxWorkers(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Data, [][]string{{"Active"}, {"Inactive"}, {""}, {"Unknown"}}) {
+		t.Errorf("unexpected rows: %v", result.Data)
+	}
+
+	if _, err := df.MapValues("missing", status, true); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := df.MapValuesParallel("missing", status, true); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
+
+func TestPipelineMapValues(t *testing.T) {
+	df, _ := NewDataFrame([]string{"city"}, [][]string{{"Istambul"}, {"Ankara"}, {"Izmr"}})
+	pipeline := NewPipeline()
+	if err := pipeline.Action("map_values:city=Istambul:Istanbul,Izmr:Izmir"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := pipeline.Run(df)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Data, [][]string{{"Istanbul"}, {"Ankara"}, {"Izmir"}}) {
+		t.Errorf("unexpected rows: %v", result.Data)
+	}
+
+	result, _ = NewPipeline().MapValues("city", map[string]string{"Ankara": "ANK"}, false).Run(result)
+	if !reflect.DeepEqual(result.Data, [][]string{{""}, {"ANK"}, {""}}) {
+		t.Errorf("unexpected rows: %v", result.Data)
+	}
+
+	for _, action := range []string{"map_values:city", "map_values:city=a", "map_values:city=a:b=drop", "map_values:=a:b"} {
+		if err := NewPipeline().Action(action); err == nil {
+			t.Errorf("%s: expected a usage error", action)
+		}
+	}
+}
+
+func TestApproxDistinctAndTopK(t *testing.T) {
+	// Zipf-like frequencies: value-i appears 1000/(i+1) times
+	var rows [][]string
+	for i := range 500 {
+		for range 1000 / (i + 1) {
+			rows = append(rows, []string{"value-" + strconv.Itoa(i)})
+		}
+	}
+	df, _ := NewDataFrame([]string{"sku"}, rows)
+
+	distinct, err := df.ApproxDistinct("sku")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if distinct < 490 || distinct > 510 {
+		t.Errorf("expected about 500 distinct values, got %d", distinct)
+	}
+
+	top, err := df.TopK("sku", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []ValueCount{{"value-0", 1000}, {"value-1", 500}, {"value-2", 333}}
+	if !reflect.DeepEqual(top, expected) {
+		t.Errorf("unexpected top values: %v", top)
+	}
+
+	small, _ := NewDataFrame([]string{"city"}, [][]string{{"Izmir"}, {"Ankara"}, {"Izmir"}})
+	if top, _ := small.TopK("city", 5); !reflect.DeepEqual(top, []ValueCount{{"Izmir", 2}, {"Ankara", 1}}) {
+		t.Errorf("unexpected top values: %v", top)
+	}
+	if _, err := small.TopK("city", -1); err == nil {
+		t.Error("expected an error for a negative k")
+	}
+	if _, err := small.TopK("missing", 1); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+	if _, err := small.ApproxDistinct("missing"); !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected ErrColumnNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package formats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// AgeRecipients returns an Encryptor for one or more age recipients, the "age1..." public keys of
+// age-keygen, so the files can be decrypted with the age tool and any of the matching identities:
+//
+//	age --decrypt -i key.txt cleaned.csv.age > cleaned.csv
+//
+// Only X25519 recipients are supported, not SSH keys. See AgePassphrase for a passphrase.
+func AgeRecipients(recipients ...string) (Encryptor, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("at least one age recipient must be specified")
+	}
+	parsed := make(ageEncryptor, 0, len(recipients))
+	for _, recipient := range recipients {
+		r, err := age.ParseX25519Recipient(strings.TrimSpace(recipient))
+		if err != nil {
+			return nil, fmt.Errorf("invalid age recipient %s: %w", recipient, err)
+		}
+		parsed = append(parsed, r)
+	}
+	return parsed, nil
+}
+
+// GenerateAgeIdentity returns a new age identity, the secret "AGE-SECRET-KEY-1..." key, and its
+// recipient, the "age1..." public key, like age-keygen
+func GenerateAgeIdentity() (identity, recipient string, err error) {
+	key, err := age.GenerateX25519Identity()
+	if err != nil {
+		return "", "", err
+	}
+	return key.String(), key.Recipient().String(), nil
+}
+
+// DecryptAge returns a reader of the data of an age file encrypted for the recipient of one of the
+// identities, "AGE-SECRET-KEY-1..." keys. A file for other recipients or data that fails to
+// decrypt is an ErrDecrypt error.
+func DecryptAge(r io.Reader, identities ...string) (io.Reader, error) {
+	if len(identities) == 0 {
+		return nil, errors.New("at least one age identity must be specified")
+	}
+	parsed := make([]age.Identity, 0, len(identities))
+	for _, identity := range identities {
+		i, err := age.ParseX25519Identity(strings.TrimSpace(identity))
+		if err != nil {
+			return nil, fmt.Errorf("invalid age identity: %w", err)
+		}
+		parsed = append(parsed, i)
+	}
+	return decryptAge(r, parsed...)
+}
@@ -0,0 +1,164 @@
+package formats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrOutputExists is returned when a writer must not overwrite an existing file, see WithOverwrite
+var ErrOutputExists = errors.New("output file already exists")
+
+// atomicFile is a temporary file in the directory of its destination. Commit renames it over the
+// destination, so readers see either the previous file or the complete new one, never a truncated
+// file left behind by a crash or an error mid-write.
+type atomicFile struct {
+	*os.File
+	path      string
+	fsync     bool
+	overwrite bool
+	encrypted io.WriteCloser // Encrypts the content on its way to the file, nil without encryption
+}
+
+// createAtomic creates the temporary file for path. With fsync, Commit flushes the file and the
+// directory entry to stable storage before returning. Without overwrite, an existing file at path
+// is an ErrOutputExists, both now and when committing. With an encryptor, the content written to
+// Writer is encrypted.
+func createAtomic(path string, fsync, overwrite bool, encryptor Encryptor) (*atomicFile, error) {
+	if !overwrite {
+		if _, err := os.Lstat(path); err == nil {
+			return nil, fmt.Errorf("%w: %s", ErrOutputExists, path)
+		}
+	}
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	file, err := os.CreateTemp(dir, "."+base+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	// CreateTemp uses 0600; keep the mode of a replaced file, like os.Create would
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := file.Chmod(mode); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	f := &atomicFile{File: file, path: path, fsync: fsync, overwrite: overwrite}
+	if encryptor != nil {
+		if f.encrypted, err = encryptor.Encrypt(file); err != nil {
+			f.Abort()
+			return nil, fmt.Errorf("failed to encrypt %s: %w", path, err)
+		}
+	}
+	return f, nil
+}
+
+// Writer returns the writer of the content of the file, which encrypts it if the file was created
+// with an encryptor
+func (f *atomicFile) Writer() io.Writer {
+	if f.encrypted != nil {
+		return f.encrypted
+	}
+	return f.File
+}
+
+// Commit closes the temporary file and renames it to the destination. The temporary file is
+// removed if that fails.
+func (f *atomicFile) Commit() error {
+	if f.encrypted != nil {
+		if err := f.encrypted.Close(); err != nil {
+			f.Abort()
+			return err
+		}
+	}
+	if f.fsync {
+		if err := f.Sync(); err != nil {
+			f.Abort()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.publish(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if f.fsync {
+		syncDir(filepath.Dir(f.path))
+	}
+	return nil
+}
+
+// publish moves the closed temporary file to the destination. Without overwrite, a hard link
+// creates the destination only if it does not exist, so a file created by someone else in the
+// meantime is not replaced; file systems without hard links fall back to a check and a rename.
+func (f *atomicFile) publish() error {
+	if f.overwrite {
+		return os.Rename(f.Name(), f.path)
+	}
+	err := os.Link(f.Name(), f.path)
+	if err == nil {
+		os.Remove(f.Name())
+		return nil
+	}
+	if errors.Is(err, os.ErrExist) {
+		return ErrOutputExists
+	}
+	if _, statErr := os.Lstat(f.path); statErr == nil {
+		return ErrOutputExists
+	}
+	return os.Rename(f.Name(), f.path)
+}
+
+// Abort closes and removes the temporary file, leaving the destination untouched
+func (f *atomicFile) Abort() error {
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// writeAtomic writes a file through write and replaces path with it only if write succeeds
+func writeAtomic(path string, fsync, overwrite bool, encryptor Encryptor, write func(w io.Writer) error) error {
+	file, err := createAtomic(path, fsync, overwrite, encryptor)
+	if err != nil {
+		return err
+	}
+	if err := write(file.Writer()); err != nil {
+		file.Abort()
+		return err
+	}
+	if err := file.Commit(); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// syncDir flushes the directory entries of dir, so a rename survives a power loss. It is best
+// effort: some platforms cannot sync directories.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
+
+// WriteFile writes content to path the way the writers of the formats do: through a temporary file
+// that replaces path only once it is complete. Without overwrite an existing file is an
+// ErrOutputExists. The content is written as is, e.g. the bytes of a file written before.
+func WriteFile(path string, content []byte, fsync, overwrite bool) error {
+	return writeAtomic(path, fsync, overwrite, nil, func(w io.Writer) error {
+		_, err := w.Write(content)
+		return err
+	})
+}
@@ -0,0 +1,206 @@
+package formats
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// assertOnlyFile checks that the directory holds nothing but the file with the content
+func assertOnlyFile(t *testing.T, path, content string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	if string(data) != content {
+		t.Errorf("content = %q, expected = %q", data, content)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("failed to read directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary files, got %d entries", len(entries))
+	}
+}
+
+func TestAtomicWrite_FailureKeepsDestination(t *testing.T) {
+	headers := []string{"name"}
+	data := [][]string{{"Ali"}}
+
+	tests := []struct {
+		name  string
+		file  string
+		write func(path string) error
+	}{
+		{"csv", "out.csv", func(path string) error {
+			// A quote is not a valid delimiter, so the first record fails
+			return WriteCSVFromRaw(headers, data, path, WithDelimiter('"'))
+		}},
+		{"excel", "out.txt", func(path string) error {
+			// The extension is not a workbook format
+			return WriteExcelFromRaw(headers, data, path)
+		}},
+		{"xml", "out.xml", func(path string) error {
+			return WriteXMLFromRaw([]string{"invalid name"}, data, path, WithXMLRootElement(""))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte("previous"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.write(path); err == nil {
+				t.Fatal("expected an error")
+			}
+			assertOnlyFile(t, path, "previous")
+		})
+	}
+}
+
+func TestAtomicWrite_ReplacesDestination(t *testing.T) {
+	dir := t.TempDir()
+	headers := []string{"name"}
+	data := [][]string{{"Ali"}}
+
+	path := filepath.Join(dir, "out.csv")
+	if err := os.WriteFile(path, []byte("previous"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteCSVFromRaw(headers, data, path, WithFsync(true)); err != nil {
+		t.Fatalf("WriteCSVFromRaw error: %v", err)
+	}
+	assertOnlyFile(t, path, "name\nAli\n")
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, expected the mode of the replaced file", info.Mode().Perm())
+	}
+
+	writers := map[string]func(path string) error{
+		"out.json":    func(path string) error { return WriteJSONFromRaw(headers, data, path, WithJSONFsync(true)) },
+		"out.xml":     func(path string) error { return WriteXMLFromRaw(headers, data, path, WithXMLFsync(true)) },
+		"out.yaml":    func(path string) error { return WriteYAMLFromRaw(headers, data, path, WithYAMLFsync(true)) },
+		"out.xlsx":    func(path string) error { return WriteExcelFromRaw(headers, data, path, WithExcelFsync(true)) },
+		"out.parquet": func(path string) error { return WriteParquetFromRaw(headers, data, path, WithParquetFsync(true)) },
+	}
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := write(path); err != nil {
+				t.Fatalf("write error: %v", err)
+			}
+			entries, _ := os.ReadDir(filepath.Dir(path))
+			if len(entries) != 1 || entries[0].Name() != name {
+				t.Errorf("expected only %s, got %v", name, entries)
+			}
+			if info, _ := os.Stat(path); info.Mode().Perm() != 0644 {
+				t.Errorf("mode = %v, expected 0644", info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestRowWriter_Abort(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.json")
+	if err := os.WriteFile(path, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	writer, err := NewJSONRowWriter(path)
+	if err != nil {
+		t.Fatalf("NewJSONRowWriter error: %v", err)
+	}
+	if err := writer.WriteHeaders([]string{"name"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteRows([][]string{{"Ali"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Abort(); err != nil {
+		t.Fatalf("Abort error: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close after Abort error: %v", err)
+	}
+	assertOnlyFile(t, path, "[]")
+
+	// Appended rows cannot be taken back; Abort only closes the file
+	path = filepath.Join(t.TempDir(), "out.csv")
+	if err := os.WriteFile(path, []byte("name\nAli\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	csvWriter, err := NewCSVRowWriter(path, WithAppend(true))
+	if err != nil {
+		t.Fatalf("NewCSVRowWriter error: %v", err)
+	}
+	if err := csvWriter.Abort(); err != nil {
+		t.Fatalf("Abort error: %v", err)
+	}
+	assertOnlyFile(t, path, "name\nAli\n")
+}
+
+func TestWithOverwrite(t *testing.T) {
+	headers := []string{"name"}
+	data := [][]string{{"Ali"}}
+
+	writers := map[string]func(path string) error{
+		"out.csv":     func(path string) error { return WriteCSVFromRaw(headers, data, path, WithOverwrite(false)) },
+		"out.json":    func(path string) error { return WriteJSONFromRaw(headers, data, path, WithJSONOverwrite(false)) },
+		"out.xml":     func(path string) error { return WriteXMLFromRaw(headers, data, path, WithXMLOverwrite(false)) },
+		"out.yaml":    func(path string) error { return WriteYAMLFromRaw(headers, data, path, WithYAMLOverwrite(false)) },
+		"out.xlsx":    func(path string) error { return WriteExcelFromRaw(headers, data, path, WithExcelOverwrite(false)) },
+		"out.parquet": func(path string) error { return WriteParquetFromRaw(headers, data, path, WithParquetOverwrite(false)) },
+	}
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := write(path); err != nil {
+				t.Fatalf("write error for a new file: %v", err)
+			}
+			if err := os.WriteFile(path, []byte("previous"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := write(path); !errors.Is(err, ErrOutputExists) {
+				t.Errorf("expected ErrOutputExists, got %v", err)
+			}
+			assertOnlyFile(t, path, "previous")
+		})
+	}
+}
+
+func TestWithOverwrite_FileCreatedWhileWriting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	writer, err := NewJSONRowWriter(path, WithJSONOverwrite(false))
+	if err != nil {
+		t.Fatalf("NewJSONRowWriter error: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); !errors.Is(err, ErrOutputExists) {
+		t.Errorf("expected ErrOutputExists, got %v", err)
+	}
+	assertOnlyFile(t, path, "[]")
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.csv")
+	if err := os.WriteFile(path, []byte("previous"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, []byte("name\nAli\n"), true, true); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	assertOnlyFile(t, path, "name\nAli\n")
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, expected the mode of the replaced file", info.Mode().Perm())
+	}
+
+	if err := WriteFile(path, []byte("other"), false, false); !errors.Is(err, ErrOutputExists) {
+		t.Errorf("expected ErrOutputExists, got %v", err)
+	}
+	assertOnlyFile(t, path, "name\nAli\n")
+}
@@ -0,0 +1,288 @@
+package formats
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// BigQuery write dispositions
+const (
+	BigQueryWriteAppend   = "WRITE_APPEND"
+	BigQueryWriteTruncate = "WRITE_TRUNCATE"
+	BigQueryWriteEmpty    = "WRITE_EMPTY"
+)
+
+// BigQueryField is a column of a BigQuery table schema
+type BigQueryField struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // STRING, INTEGER, FLOAT, BOOLEAN, DATE, TIMESTAMP or JSON
+	Mode string `json:"mode,omitempty"`
+}
+
+// BigQueryOptions contains BigQuery loading options
+type BigQueryOptions struct {
+	Context          context.Context
+	HTTPClient       *http.Client // Authorized client, e.g. from golang.org/x/oauth2/google.DefaultClient
+	Endpoint         string       // API root (default: https://bigquery.googleapis.com)
+	Location         string       // Dataset location, e.g. EU (default: detected by BigQuery)
+	WriteDisposition string       // Default: BigQueryWriteAppend
+	PollInterval     time.Duration
+}
+
+// BigQueryOption is a function type for setting BigQuery options
+type BigQueryOption func(*BigQueryOptions)
+
+// defaultBigQueryOptions returns default BigQuery options
+func defaultBigQueryOptions() BigQueryOptions {
+	return BigQueryOptions{
+		Context:          context.Background(),
+		Endpoint:         "https://bigquery.googleapis.com",
+		WriteDisposition: BigQueryWriteAppend,
+		PollInterval:     time.Second,
+	}
+}
+
+// WithBigQueryContext sets the context of the load job requests
+func WithBigQueryContext(ctx context.Context) BigQueryOption {
+	return func(o *BigQueryOptions) {
+		if ctx != nil {
+			o.Context = ctx
+		}
+	}
+}
+
+// WithBigQueryClient sets the authorized HTTP client used to call the BigQuery API
+func WithBigQueryClient(client *http.Client) BigQueryOption {
+	return func(o *BigQueryOptions) {
+		o.HTTPClient = client
+	}
+}
+
+// WithBigQueryEndpoint sets the root URL of the BigQuery API
+func WithBigQueryEndpoint(endpoint string) BigQueryOption {
+	return func(o *BigQueryOptions) {
+		o.Endpoint = strings.TrimRight(endpoint, "/")
+	}
+}
+
+// WithBigQueryLocation sets the location of the dataset the job runs in
+func WithBigQueryLocation(location string) BigQueryOption {
+	return func(o *BigQueryOptions) {
+		o.Location = location
+	}
+}
+
+// WithWriteDisposition sets whether the load appends to, replaces or requires an empty table
+func WithWriteDisposition(disposition string) BigQueryOption {
+	return func(o *BigQueryOptions) {
+		o.WriteDisposition = disposition
+	}
+}
+
+// WithPollInterval sets how often the load job status is checked
+func WithPollInterval(interval time.Duration) BigQueryOption {
+	return func(o *BigQueryOptions) {
+		if interval > 0 {
+			o.PollInterval = interval
+		}
+	}
+}
+
+// bigQueryJob is the part of a BigQuery job resource used to start and follow a load job
+type bigQueryJob struct {
+	JobReference *struct {
+		JobID    string `json:"jobId"`
+		Location string `json:"location"`
+	} `json:"jobReference,omitempty"`
+	Configuration struct {
+		Load *bigQueryLoad `json:"load,omitempty"`
+	} `json:"configuration"`
+	Status *struct {
+		State       string           `json:"state"`
+		ErrorResult *bigQueryError   `json:"errorResult"`
+		Errors      []*bigQueryError `json:"errors"`
+	} `json:"status,omitempty"`
+}
+
+// bigQueryLoad is the configuration of a load job
+type bigQueryLoad struct {
+	DestinationTable struct {
+		ProjectID string `json:"projectId"`
+		DatasetID string `json:"datasetId"`
+		TableID   string `json:"tableId"`
+	} `json:"destinationTable"`
+	Schema struct {
+		Fields []BigQueryField `json:"fields"`
+	} `json:"schema"`
+	SourceFormat      string `json:"sourceFormat"`
+	WriteDisposition  string `json:"writeDisposition"`
+	CreateDisposition string `json:"createDisposition"`
+	AllowQuotedLines  bool   `json:"allowQuotedNewlines"`
+}
+
+// bigQueryError is an error reported by a job
+type bigQueryError struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// WriteBigQueryFromRaw loads raw data into a BigQuery table with a load job. The rows are staged as
+// CSV through a resumable upload; the table is created with schema if it does not exist. Empty values
+// are loaded as NULL. The call returns when the job has finished.
+func WriteBigQueryFromRaw(headers []string, data [][]string, schema []BigQueryField, projectID, dataset, table string, options ...BigQueryOption) error {
+	opts := defaultBigQueryOptions()
+	for _, option := range options {
+		option(&opts)
+	}
+	if opts.HTTPClient == nil {
+		return errors.New("BigQuery requires an authorized HTTP client, see WithBigQueryClient")
+	}
+	if len(schema) != len(headers) {
+		return fmt.Errorf("schema has %d fields, data has %d columns", len(schema), len(headers))
+	}
+
+	var staged bytes.Buffer
+	writer := csv.NewWriter(&staged)
+	if err := writer.WriteAll(data); err != nil {
+		return fmt.Errorf("failed to stage BigQuery rows: %w", err)
+	}
+
+	job := &bigQueryJob{}
+	job.Configuration.Load = &bigQueryLoad{
+		SourceFormat:      "CSV",
+		WriteDisposition:  opts.WriteDisposition,
+		CreateDisposition: "CREATE_IF_NEEDED",
+		AllowQuotedLines:  true,
+	}
+	job.Configuration.Load.DestinationTable.ProjectID = projectID
+	job.Configuration.Load.DestinationTable.DatasetID = dataset
+	job.Configuration.Load.DestinationTable.TableID = table
+	job.Configuration.Load.Schema.Fields = schema
+
+	client := &bigQueryClient{opts: opts, projectID: projectID}
+	job, err := client.upload(job, staged.Bytes())
+	if err != nil {
+		return err
+	}
+	return client.wait(job)
+}
+
+// bigQueryClient calls the BigQuery REST API
+type bigQueryClient struct {
+	opts      BigQueryOptions
+	projectID string
+}
+
+// upload starts a resumable upload session for the job and sends the staged data
+func (c *bigQueryClient) upload(job *bigQueryJob, data []byte) (*bigQueryJob, error) {
+	config, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode BigQuery job: %w", err)
+	}
+
+	startURL := fmt.Sprintf("%s/upload/bigquery/v2/projects/%s/jobs?uploadType=resumable", c.opts.Endpoint, url.PathEscape(c.projectID))
+	req, err := http.NewRequestWithContext(c.opts.Context, http.MethodPost, startURL, bytes.NewReader(config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery upload: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Type", "application/octet-stream")
+	resp, err := c.do(req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start BigQuery upload: %w", err)
+	}
+	session := resp.Header.Get("Location")
+	if session == "" {
+		return nil, errors.New("failed to start BigQuery upload: no upload session returned")
+	}
+
+	req, err = http.NewRequestWithContext(c.opts.Context, http.MethodPut, session, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery upload: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	started := &bigQueryJob{}
+	if _, err := c.do(req, started); err != nil {
+		return nil, fmt.Errorf("failed to upload BigQuery rows: %w", err)
+	}
+	return started, nil
+}
+
+// wait polls the job until it is done and returns its error, if any
+func (c *bigQueryClient) wait(job *bigQueryJob) error {
+	if job.JobReference == nil {
+		return errors.New("BigQuery did not return a job reference")
+	}
+	jobURL := fmt.Sprintf("%s/bigquery/v2/projects/%s/jobs/%s", c.opts.Endpoint, url.PathEscape(c.projectID), url.PathEscape(job.JobReference.JobID))
+	location := job.JobReference.Location
+	if location == "" {
+		location = c.opts.Location
+	}
+	if location != "" {
+		jobURL += "?location=" + url.QueryEscape(location)
+	}
+
+	for {
+		if job.Status != nil && job.Status.State == "DONE" {
+			if job.Status.ErrorResult != nil {
+				message := job.Status.ErrorResult.Message
+				if len(job.Status.Errors) > 1 {
+					message = fmt.Sprintf("%s (%d errors)", message, len(job.Status.Errors))
+				}
+				return fmt.Errorf("BigQuery load job %s failed: %s: %s", job.JobReference.JobID, job.Status.ErrorResult.Reason, message)
+			}
+			return nil
+		}
+
+		select {
+		case <-c.opts.Context.Done():
+			return c.opts.Context.Err()
+		case <-time.After(c.opts.PollInterval):
+		}
+
+		req, err := http.NewRequestWithContext(c.opts.Context, http.MethodGet, jobURL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create BigQuery job request: %w", err)
+		}
+		reference := job.JobReference
+		job = &bigQueryJob{}
+		if _, err := c.do(req, job); err != nil {
+			return fmt.Errorf("failed to get BigQuery job status: %w", err)
+		}
+		if job.JobReference == nil {
+			job.JobReference = reference
+		}
+	}
+}
+
+// do sends the request and decodes a successful JSON response into result, if it is not nil
+func (c *bigQueryClient) do(req *http.Request, result interface{}) (*http.Response, error) {
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	if result != nil {
+		if err := json.Unmarshal(body, result); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return resp, nil
+}
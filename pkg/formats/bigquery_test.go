@@ -0,0 +1,98 @@
+package formats
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeBigQuery serves the resumable upload and job status endpoints of a load job
+func fakeBigQuery(t *testing.T, jobError string) (*httptest.Server, *bigQueryJob, *string) {
+	t.Helper()
+	config := &bigQueryJob{}
+	uploaded := new(string)
+	polls := 0
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/upload/bigquery/v2/projects/my-project/jobs":
+			if r.URL.Query().Get("uploadType") != "resumable" {
+				http.Error(w, "expected a resumable upload", http.StatusBadRequest)
+				return
+			}
+			json.NewDecoder(r.Body).Decode(config)
+			w.Header().Set("Location", server.URL+"/session/1")
+		case r.Method == http.MethodPut && r.URL.Path == "/session/1":
+			body, _ := io.ReadAll(r.Body)
+			*uploaded = string(body)
+			io.WriteString(w, `{"jobReference":{"jobId":"job_1","location":"EU"},"status":{"state":"RUNNING"}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/bigquery/v2/projects/my-project/jobs/job_1":
+			if r.URL.Query().Get("location") != "EU" {
+				http.Error(w, "missing location", http.StatusBadRequest)
+				return
+			}
+			if polls++; polls < 2 {
+				io.WriteString(w, `{"status":{"state":"RUNNING"}}`)
+				return
+			}
+			if jobError != "" {
+				io.WriteString(w, `{"status":{"state":"DONE","errorResult":{"reason":"invalid","message":"`+jobError+`"}}}`)
+				return
+			}
+			io.WriteString(w, `{"status":{"state":"DONE"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, config, uploaded
+}
+
+func TestWriteBigQueryFromRaw(t *testing.T) {
+	server, config, uploaded := fakeBigQuery(t, "")
+
+	headers := []string{"id", "note"}
+	data := [][]string{{"1", "a, b"}, {"2", ""}}
+	schema := []BigQueryField{{Name: "id", Type: "INTEGER"}, {Name: "note", Type: "STRING"}}
+	err := WriteBigQueryFromRaw(headers, data, schema, "my-project", "sales", "orders",
+		WithBigQueryClient(server.Client()),
+		WithBigQueryEndpoint(server.URL),
+		WithWriteDisposition(BigQueryWriteTruncate),
+		WithPollInterval(time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("WriteBigQueryFromRaw error: %v", err)
+	}
+
+	load := config.Configuration.Load
+	if load == nil || load.DestinationTable.TableID != "orders" || load.DestinationTable.DatasetID != "sales" ||
+		load.WriteDisposition != BigQueryWriteTruncate || load.SourceFormat != "CSV" || len(load.Schema.Fields) != 2 {
+		t.Errorf("unexpected job configuration: %+v", load)
+	}
+	if *uploaded != "1,\"a, b\"\n2,\n" {
+		t.Errorf("unexpected staged data: %q", *uploaded)
+	}
+}
+
+func TestWriteBigQueryFromRaw_Errors(t *testing.T) {
+	server, _, _ := fakeBigQuery(t, "bad value")
+	schema := []BigQueryField{{Name: "id", Type: "INTEGER"}}
+
+	err := WriteBigQueryFromRaw([]string{"id"}, [][]string{{"x"}}, schema, "my-project", "sales", "orders",
+		WithBigQueryClient(server.Client()), WithBigQueryEndpoint(server.URL), WithPollInterval(time.Millisecond))
+	if err == nil || !strings.Contains(err.Error(), "bad value") {
+		t.Errorf("expected the job error, got %v", err)
+	}
+
+	if err := WriteBigQueryFromRaw([]string{"id"}, nil, schema, "p", "d", "t"); err == nil {
+		t.Error("expected an error without an HTTP client")
+	}
+	if err := WriteBigQueryFromRaw([]string{"id", "name"}, nil, schema, "p", "d", "t", WithBigQueryClient(server.Client())); err == nil {
+		t.Error("expected a schema length error")
+	}
+}
@@ -0,0 +1,153 @@
+package formats
+
+import (
+	"bufio"
+	"io"
+	"slices"
+	"sync"
+	"unicode/utf8"
+)
+
+// writeBufferSize is the size of the pooled file write buffers
+const writeBufferSize = 64 * 1024
+
+// writeBufferPool holds file write buffers reused across writers
+var writeBufferPool = sync.Pool{
+	New: func() any {
+		return bufio.NewWriterSize(nil, writeBufferSize)
+	},
+}
+
+// getWriteBuffer returns a pooled buffered writer for w
+func getWriteBuffer(w io.Writer) *bufio.Writer {
+	buf := writeBufferPool.Get().(*bufio.Writer)
+	buf.Reset(w)
+	return buf
+}
+
+// putWriteBuffer returns a buffered writer to the pool. Unflushed data is discarded.
+func putWriteBuffer(buf *bufio.Writer) {
+	buf.Reset(nil)
+	writeBufferPool.Put(buf)
+}
+
+// jsonRecordEncoder encodes rows as JSON objects without building a map per row. Keys are written in
+// sorted order, like encoding/json does for maps, and the encoding buffer is reused across rows.
+type jsonRecordEncoder struct {
+	keys    [][]byte // Encoded keys followed by the separator, in sorted order
+	columns [][]int  // Column indices of every key; a duplicated header uses its last column
+	pretty  bool
+	buf     []byte
+}
+
+func newJSONRecordEncoder(headers []string, pretty bool) *jsonRecordEncoder {
+	unique := slices.Clone(headers)
+	slices.Sort(unique)
+	unique = slices.Compact(unique)
+
+	e := &jsonRecordEncoder{
+		keys:    make([][]byte, len(unique)),
+		columns: make([][]int, len(unique)),
+		pretty:  pretty,
+	}
+	for k, key := range unique {
+		e.keys[k] = appendJSONString(nil, key)
+		if pretty {
+			e.keys[k] = append(e.keys[k], ": "...)
+		} else {
+			e.keys[k] = append(e.keys[k], ':')
+		}
+	}
+	for j, header := range headers {
+		k, _ := slices.BinarySearch(unique, header)
+		e.columns[k] = append(e.columns[k], j)
+	}
+	return e
+}
+
+// encode returns the JSON object of the row. Columns missing from a short row are omitted.
+// The result is only valid until the next call. In pretty mode the object is indented as an
+// element of a top-level array.
+func (e *jsonRecordEncoder) encode(row []string) []byte {
+	e.buf = append(e.buf[:0], '{')
+	n := 0
+	for k, key := range e.keys {
+		j := -1
+		for _, col := range e.columns[k] {
+			if col < len(row) {
+				j = col
+			}
+		}
+		if j == -1 {
+			continue
+		}
+
+		if n > 0 {
+			e.buf = append(e.buf, ',')
+		}
+		if e.pretty {
+			e.buf = append(e.buf, "\n    "...)
+		}
+		e.buf = append(e.buf, key...)
+		e.buf = appendJSONString(e.buf, row[j])
+		n++
+	}
+	if e.pretty && n > 0 {
+		e.buf = append(e.buf, "\n  "...)
+	}
+	return append(e.buf, '}')
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends s as a JSON string, escaped exactly like encoding/json does
+// (including HTML characters, invalid UTF-8 and the line and paragraph separators)
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, s[start:i]...)
+			dst = utf8.AppendRune(dst, utf8.RuneError)
+		case r == '\u2028' || r == '\u2029':
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
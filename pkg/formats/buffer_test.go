@@ -0,0 +1,106 @@
+package formats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// marshalRecords is the map-based encoding the JSON writer must stay byte-compatible with
+func marshalRecords(t *testing.T, headers []string, data [][]string, pretty bool) string {
+	records := make([]map[string]interface{}, len(data))
+	for i, row := range data {
+		record := make(map[string]interface{})
+		for j, header := range headers {
+			if j < len(row) {
+				record[header] = row[j]
+			}
+		}
+		records[i] = record
+	}
+
+	var b []byte
+	var err error
+	if pretty {
+		b, err = json.MarshalIndent(records, "", "  ")
+	} else {
+		b, err = json.Marshal(records)
+	}
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	return string(b)
+}
+
+func TestWriteJSONFromRawMatchesEncodingJSON(t *testing.T) {
+	headers := []string{"name", "b", "a<&>", "name", "ünicode"}
+	data := [][]string{
+		{"Ali", "1", "x", "Ali2", "ç"},
+		{"quote \" back\\slash", "\n\t\r\b\f", "\x01\x1f", "<script>&", "\u2028\u2029"},
+		{"invalid \xff utf8", "short"},
+		{},
+		{"emoji 😀", "", "", "", ""},
+	}
+
+	for _, pretty := range []bool{false, true} {
+		for _, rows := range [][][]string{data, nil} {
+			path := filepath.Join(t.TempDir(), "out.json")
+			if err := WriteJSONFromRaw(headers, rows, path, WithPretty(pretty)); err != nil {
+				t.Fatalf("WriteJSONFromRaw error: %v", err)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := marshalRecords(t, headers, rows, pretty); string(got) != want {
+				t.Errorf("pretty=%v rows=%d:\ngot  %s\nwant %s", pretty, len(rows), got, want)
+			}
+		}
+	}
+}
+
+func TestAppendJSONString(t *testing.T) {
+	for r := rune(0); r < 0x3000; r++ {
+		s := fmt.Sprintf("a%cb", r)
+		want, _ := json.Marshal(s)
+		if got := appendJSONString(nil, s); string(got) != string(want) {
+			t.Fatalf("rune %U: got %s, want %s", r, got, want)
+		}
+	}
+}
+
+func benchmarkRows(n int) ([]string, [][]string) {
+	headers := []string{"id", "name", "email", "city", "created_at"}
+	data := make([][]string, n)
+	for i := range data {
+		data[i] = []string{fmt.Sprint(i), fmt.Sprintf("Name %d", i), fmt.Sprintf("user%d@example.com", i), "İstanbul", "2024-01-15"}
+	}
+	return headers, data
+}
+
+func BenchmarkWriteJSONFromRaw(b *testing.B) {
+	headers, data := benchmarkRows(100000)
+	path := filepath.Join(b.TempDir(), "out.json")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := WriteJSONFromRaw(headers, data, path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteCSVFromRaw(b *testing.B) {
+	headers, data := benchmarkRows(100000)
+	path := filepath.Join(b.TempDir(), "out.csv")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := WriteCSVFromRaw(headers, data, path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,10 +1,13 @@
 package formats
 
 import (
+	"bufio"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 )
 
 // DataFrame is an interface that defines the required methods for a data frame
@@ -19,6 +22,12 @@ type CSVOptions struct {
 	LazyQuotes  bool
 	SkipErrors  bool
 	CommentChar rune
+	Mmap        bool
+	Append      bool
+	Provenance  bool
+	Fsync       bool
+	Overwrite   bool
+	Encryptor   Encryptor
 }
 
 // CSVOption is a function type for setting CSV options
@@ -31,6 +40,7 @@ func defaultCSVOptions() CSVOptions {
 		LazyQuotes:  false,
 		SkipErrors:  false,
 		CommentChar: 0,
+		Overwrite:   true,
 	}
 }
 
@@ -62,6 +72,58 @@ func WithComment(commentChar rune) CSVOption {
 	}
 }
 
+// WithMmap determines whether the file is memory-mapped instead of read through a buffered reader.
+// Cell values are sliced from the mapped data instead of being allocated record by record; with
+// NewCSVRowReader they are only valid until the reader is closed.
+func WithMmap(mmap bool) CSVOption {
+	return func(o *CSVOptions) {
+		o.Mmap = mmap
+	}
+}
+
+// WithAppend determines whether NewCSVRowWriter appends to an existing file instead of truncating it.
+// The header line is only written if the file is empty.
+func WithAppend(appendRows bool) CSVOption {
+	return func(o *CSVOptions) {
+		o.Append = appendRows
+	}
+}
+
+// WithProvenance determines whether ReadCSVToRaw adds the _source_file and _source_row columns.
+// Rows skipped by WithSkipErrors still count, so _source_row is the position of the record in the file.
+func WithProvenance(provenance bool) CSVOption {
+	return func(o *CSVOptions) {
+		o.Provenance = provenance
+	}
+}
+
+// WithFsync determines whether written files are flushed to stable storage before they replace the
+// destination. Files are always written to a temporary file that is renamed on success; fsync also
+// makes the result survive a power loss, at the cost of slower writes.
+func WithFsync(fsync bool) CSVOption {
+	return func(o *CSVOptions) {
+		o.Fsync = fsync
+	}
+}
+
+// WithOverwrite determines whether writers replace an existing file. It is true by default; with
+// false, writing to an existing file fails with ErrOutputExists and leaves the file untouched, so
+// previously cleaned results are not lost by accident. Appending with WithAppend is always allowed.
+func WithOverwrite(overwrite bool) CSVOption {
+	return func(o *CSVOptions) {
+		o.Overwrite = overwrite
+	}
+}
+
+// WithEncryption encrypts written files with the encryptor, e.g. AgeRecipients or AgePassphrase, so
+// cleaned data that is still sensitive is only stored encrypted. It cannot be combined with
+// WithAppend.
+func WithEncryption(encryptor Encryptor) CSVOption {
+	return func(o *CSVOptions) {
+		o.Encryptor = encryptor
+	}
+}
+
 // ReadCSVToRaw reads a CSV file and returns raw data
 func ReadCSVToRaw(filePath string, options ...CSVOption) ([]string, [][]string, error) {
 	// Default settings
@@ -72,6 +134,10 @@ func ReadCSVToRaw(filePath string, options ...CSVOption) ([]string, [][]string,
 		option(&opts)
 	}
 
+	if opts.Mmap {
+		return readMappedCSV(filePath, opts)
+	}
+
 	// Open file
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -93,7 +159,7 @@ func ReadCSVToRaw(filePath string, options ...CSVOption) ([]string, [][]string,
 
 	// Read data
 	var rows [][]string
-	for {
+	for record := 1; ; record++ {
 		row, err := reader.Read()
 		if err == io.EOF {
 			break
@@ -104,14 +170,45 @@ func ReadCSVToRaw(filePath string, options ...CSVOption) ([]string, [][]string,
 			}
 			return nil, nil, fmt.Errorf("failed to read CSV row: %w", err)
 		}
+		if opts.Provenance {
+			row = provenanceRow(row, filePath, record)
+		}
 		rows = append(rows, row)
 	}
 
+	if opts.Provenance {
+		headers = append(headers, SourceFileColumn, SourceRowColumn)
+	}
 	return headers, rows, nil
 }
 
-// WriteCSVFromRaw writes raw data to a CSV file
+// WriteCSVFromRaw writes raw data to a CSV file through a pooled write buffer
 func WriteCSVFromRaw(headers []string, data [][]string, filePath string, options ...CSVOption) error {
+	writer, err := NewCSVRowWriter(filePath, options...)
+	if err != nil {
+		return err
+	}
+	if err := writer.WriteHeaders(headers); err != nil {
+		writer.Abort()
+		return err
+	}
+	if err := writer.WriteRows(data); err != nil {
+		writer.Abort()
+		return err
+	}
+	return writer.Close()
+}
+
+// CSVRowReader reads a CSV file incrementally
+type CSVRowReader struct {
+	close   func() error
+	reader  recordReader
+	headers []string
+	opts    CSVOptions
+}
+
+// NewCSVRowReader opens a CSV file for incremental reading
+func NewCSVRowReader(filePath string, options ...CSVOption) (*CSVRowReader, error) {
 	// Default settings
 	opts := defaultCSVOptions()
 
@@ -120,38 +217,207 @@ func WriteCSVFromRaw(headers []string, data [][]string, filePath string, options
 		option(&opts)
 	}
 
-	// Create file
-	file, err := os.Create(filePath)
+	var reader recordReader
+	var closeFn func() error
+	if opts.Mmap {
+		data, unmap, err := mmapFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open CSV file: %w", err)
+		}
+		reader, closeFn = newCSVScanner(data, opts), unmap
+	} else {
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open CSV file: %w", err)
+		}
+		csvReader := csv.NewReader(file)
+		csvReader.Comma = opts.Delimiter
+		csvReader.LazyQuotes = opts.LazyQuotes
+		csvReader.Comment = opts.CommentChar
+		reader, closeFn = csvReader, file.Close
+	}
+
+	headers, err := reader.Read()
 	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %w", err)
+		closeFn()
+		return nil, fmt.Errorf("failed to read CSV headers: %w", err)
 	}
-	defer file.Close()
+	// Headers outlive the reader, so they never point into mapped memory
+	for i := range headers {
+		headers[i] = strings.Clone(headers[i])
+	}
+
+	return &CSVRowReader{close: closeFn, reader: reader, headers: headers, opts: opts}, nil
+}
+
+// Headers returns the CSV headers
+func (r *CSVRowReader) Headers() []string {
+	return r.headers
+}
+
+// Read returns up to n rows, or io.EOF when the file is exhausted
+func (r *CSVRowReader) Read(n int) ([][]string, error) {
+	var rows [][]string
+	for n <= 0 || len(rows) < n {
+		row, err := r.reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if r.opts.SkipErrors {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read CSV row: %w", err)
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, io.EOF
+	}
+	return rows, nil
+}
+
+// Close closes the CSV file. With WithMmap, the file is unmapped and values returned by Read
+// must no longer be used.
+func (r *CSVRowReader) Close() error {
+	closeFn := r.close
+	r.close = func() error { return nil }
+	return closeFn()
+}
+
+// CSVRowWriter writes a CSV file incrementally. Unless it appends, the rows go to a temporary file
+// that replaces the destination on Close, so a failed write never leaves a truncated file.
+type CSVRowWriter struct {
+	file          *os.File
+	atomic        *atomicFile // nil when appending
+	failed        bool
+	buffer        *bufio.Writer
+	writer        *csv.Writer
+	headerWritten bool
+	skipHeaders   bool
+}
 
-	// Create CSV writer
-	writer := csv.NewWriter(file)
+// NewCSVRowWriter creates a CSV file for incremental writing
+func NewCSVRowWriter(filePath string, options ...CSVOption) (*CSVRowWriter, error) {
+	// Default settings
+	opts := defaultCSVOptions()
+
+	// Apply user-specified settings
+	for _, option := range options {
+		option(&opts)
+	}
+
+	var file *os.File
+	var out io.Writer
+	var atomic *atomicFile
+	var err error
+	skipHeaders := false
+	if opts.Append && opts.Encryptor != nil {
+		return nil, errors.New("failed to create CSV file: encrypted files cannot be appended to")
+	}
+	if opts.Append {
+		file, err = os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err == nil {
+			var info os.FileInfo
+			if info, err = file.Stat(); err == nil {
+				skipHeaders = info.Size() > 0
+			} else {
+				file.Close()
+			}
+		}
+		out = file
+	} else if atomic, err = createAtomic(filePath, opts.Fsync, opts.Overwrite, opts.Encryptor); err == nil {
+		file, out = atomic.File, atomic.Writer()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CSV file: %w", err)
+	}
+
+	// csv.Writer uses the pooled buffer directly since it is larger than its default buffer
+	buffer := getWriteBuffer(out)
+	writer := csv.NewWriter(buffer)
 	writer.Comma = opts.Delimiter
 
-	// Write headers
-	if err := writer.Write(headers); err != nil {
+	return &CSVRowWriter{file: file, atomic: atomic, buffer: buffer, writer: writer, skipHeaders: skipHeaders}, nil
+}
+
+// WriteHeaders writes the CSV header line. When appending to a non-empty file, the existing
+// header line is kept and nothing is written.
+func (w *CSVRowWriter) WriteHeaders(headers []string) error {
+	if w.skipHeaders {
+		w.headerWritten = true
+		return nil
+	}
+	if err := w.writer.Write(headers); err != nil {
+		w.failed = true
 		return fmt.Errorf("failed to write CSV headers: %w", err)
 	}
+	w.headerWritten = true
+	return nil
+}
 
-	// Write data
-	for _, row := range data {
-		if err := writer.Write(row); err != nil {
+// WriteRows appends rows to the CSV file
+func (w *CSVRowWriter) WriteRows(rows [][]string) error {
+	if !w.headerWritten {
+		return ErrHeadersNotWritten
+	}
+	for _, row := range rows {
+		if err := w.writer.Write(row); err != nil {
+			w.failed = true
 			return fmt.Errorf("failed to write CSV row: %w", err)
 		}
 	}
+	return nil
+}
 
-	// Flush buffer
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		return fmt.Errorf("CSV writer error: %w", err)
+// Close flushes the buffer and closes the CSV file. After a failed write, the destination is left
+// untouched as if Abort was called.
+func (w *CSVRowWriter) Close() error {
+	if w.buffer == nil {
+		return nil
+	}
+	if w.failed {
+		return w.Abort()
 	}
+	defer func() {
+		putWriteBuffer(w.buffer)
+		w.buffer = nil
+	}()
 
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		w.discard()
+		return fmt.Errorf("CSV writer error: %w", err)
+	}
+	if w.atomic == nil {
+		return w.file.Close()
+	}
+	if err := w.atomic.Commit(); err != nil {
+		return fmt.Errorf("failed to replace CSV file: %w", err)
+	}
 	return nil
 }
 
+// Abort discards the written rows and leaves the destination untouched. Rows already appended to an
+// existing file are kept.
+func (w *CSVRowWriter) Abort() error {
+	if w.buffer == nil {
+		return nil
+	}
+	putWriteBuffer(w.buffer)
+	w.buffer = nil
+	return w.discard()
+}
+
+// discard closes the file, removing it unless the writer appends
+func (w *CSVRowWriter) discard() error {
+	if w.atomic == nil {
+		return w.file.Close()
+	}
+	return w.atomic.Abort()
+}
+
 // WriteCSV writes DataFrame to a CSV file
 func WriteCSV(df DataFrame, filePath string, options ...CSVOption) error {
 	return WriteCSVFromRaw(df.GetHeaders(), df.GetData(), filePath, options...)
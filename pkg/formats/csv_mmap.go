@@ -0,0 +1,238 @@
+package formats
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// errQuote is returned for a quote in an unquoted field or a malformed quoted field
+var errQuote = errors.New(`extraneous or missing " in quoted-field`)
+
+// errFieldCount is returned when a record has a different number of fields than the header
+var errFieldCount = errors.New("wrong number of fields")
+
+// recordReader reads one CSV record at a time
+type recordReader interface {
+	Read() ([]string, error)
+}
+
+// csvScanner parses CSV records directly from an in-memory buffer such as a memory-mapped file.
+// Cell values are slices of the buffer: only quoted fields containing escaped quotes or line breaks
+// are copied. The values are only valid as long as the buffer is.
+type csvScanner struct {
+	data      []byte
+	pos       int
+	line      int
+	delimiter []byte
+	comment   rune
+	lazy      bool
+	fields    int
+}
+
+func newCSVScanner(data []byte, opts CSVOptions) *csvScanner {
+	delimiter := utf8.AppendRune(nil, opts.Delimiter)
+	return &csvScanner{data: data, line: 1, delimiter: delimiter, comment: opts.CommentChar, lazy: opts.LazyQuotes, fields: -1}
+}
+
+// Read returns the next record, or io.EOF at the end of the buffer
+func (s *csvScanner) Read() ([]string, error) {
+	for {
+		if s.pos >= len(s.data) {
+			return nil, io.EOF
+		}
+
+		// Skip comment and empty lines like encoding/csv
+		rest := s.data[s.pos:]
+		if s.comment != 0 && bytes.HasPrefix(rest, utf8.AppendRune(nil, s.comment)) {
+			s.skipLine()
+			continue
+		}
+		if rest[0] == '\n' || (rest[0] == '\r' && len(rest) > 1 && rest[1] == '\n') {
+			s.skipLine()
+			continue
+		}
+
+		line := s.line
+		record, err := s.readRecord()
+		if err != nil {
+			s.skipLine()
+			return nil, fmt.Errorf("record on line %d: %w", line, err)
+		}
+
+		if s.fields == -1 {
+			s.fields = len(record)
+		} else if len(record) != s.fields {
+			return nil, fmt.Errorf("record on line %d: %w", line, errFieldCount)
+		}
+		return record, nil
+	}
+}
+
+// readRecord reads the fields of one line
+func (s *csvScanner) readRecord() ([]string, error) {
+	var record []string
+	for {
+		var value string
+		var err error
+		if s.pos < len(s.data) && s.data[s.pos] == '"' {
+			value, err = s.readQuoted()
+		} else {
+			value, err = s.readUnquoted()
+		}
+		if err != nil {
+			return nil, err
+		}
+		record = append(record, value)
+
+		// After a field: delimiter, end of line or end of data
+		switch {
+		case s.pos >= len(s.data):
+			return record, nil
+		case bytes.HasPrefix(s.data[s.pos:], s.delimiter):
+			s.pos += len(s.delimiter)
+		case s.data[s.pos] == '\n':
+			s.pos++
+			s.line++
+			return record, nil
+		case s.data[s.pos] == '\r' && s.pos+1 < len(s.data) && s.data[s.pos+1] == '\n':
+			s.pos += 2
+			s.line++
+			return record, nil
+		default:
+			return nil, errQuote
+		}
+	}
+}
+
+// readUnquoted reads a field up to the next delimiter or line break
+func (s *csvScanner) readUnquoted() (string, error) {
+	start := s.pos
+	for s.pos < len(s.data) {
+		c := s.data[s.pos]
+		if c == '\n' || bytes.HasPrefix(s.data[s.pos:], s.delimiter) {
+			break
+		}
+		if c == '"' && !s.lazy {
+			return "", errQuote
+		}
+		s.pos++
+	}
+
+	end := s.pos
+	if end > start && s.data[end-1] == '\r' && end < len(s.data) && s.data[end] == '\n' {
+		// CRLF line break
+		end--
+	}
+	return bytesToString(s.data[start:end]), nil
+}
+
+// readQuoted reads a quoted field, unescaping doubled quotes
+func (s *csvScanner) readQuoted() (string, error) {
+	s.pos++ // opening quote
+	start := s.pos
+	needCopy := false
+
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '"':
+			if s.pos+1 < len(s.data) && s.data[s.pos+1] == '"' {
+				needCopy = true
+				s.pos += 2
+				continue
+			}
+			raw := s.data[start:s.pos]
+			s.pos++ // closing quote
+			if s.lazy && s.pos < len(s.data) && !s.atFieldEnd() {
+				// A quote inside a quoted field is kept literally in lazy mode
+				needCopy = true
+				continue
+			}
+			if !needCopy {
+				return bytesToString(raw), nil
+			}
+			value := strings.ReplaceAll(string(raw), `""`, `"`)
+			return strings.ReplaceAll(value, "\r\n", "\n"), nil
+		case '\n':
+			s.line++
+		case '\r':
+			needCopy = true
+		}
+		s.pos++
+	}
+
+	if s.lazy {
+		return string(s.data[start:]), nil
+	}
+	return "", errQuote
+}
+
+// atFieldEnd reports whether the position is at a delimiter or line break
+func (s *csvScanner) atFieldEnd() bool {
+	rest := s.data[s.pos:]
+	return bytes.HasPrefix(rest, s.delimiter) || rest[0] == '\n' || (rest[0] == '\r' && len(rest) > 1 && rest[1] == '\n')
+}
+
+// skipLine moves past the next line break
+func (s *csvScanner) skipLine() {
+	if i := bytes.IndexByte(s.data[s.pos:], '\n'); i >= 0 {
+		s.pos += i + 1
+		s.line++
+		return
+	}
+	s.pos = len(s.data)
+}
+
+// bytesToString returns a string sharing memory with b
+func bytesToString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return unsafe.String(&b[0], len(b))
+}
+
+// readMappedCSV reads a whole CSV file through a memory map. The file is copied once into a
+// single string that all cell values slice into, instead of one allocation per record.
+func readMappedCSV(filePath string, opts CSVOptions) ([]string, [][]string, error) {
+	data, unmap, err := mmapFile(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	content := string(data)
+	if err := unmap(); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmap CSV file: %w", err)
+	}
+
+	scanner := newCSVScanner(unsafe.Slice(unsafe.StringData(content), len(content)), opts)
+	headers, err := scanner.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV headers: %w", err)
+	}
+
+	var rows [][]string
+	for record := 1; ; record++ {
+		row, err := scanner.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if opts.SkipErrors {
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to read CSV row: %w", err)
+		}
+		if opts.Provenance {
+			row = provenanceRow(row, filePath, record)
+		}
+		rows = append(rows, row)
+	}
+
+	if opts.Provenance {
+		headers = append(headers, SourceFileColumn, SourceRowColumn)
+	}
+	return headers, rows, nil
+}
@@ -0,0 +1,181 @@
+package formats
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readAllStd parses input with encoding/csv for comparison
+func readAllStd(input string, opts CSVOptions) ([][]string, error) {
+	r := csv.NewReader(strings.NewReader(input))
+	r.Comma = opts.Delimiter
+	r.LazyQuotes = opts.LazyQuotes
+	r.Comment = opts.CommentChar
+	return r.ReadAll()
+}
+
+// readAllScanner parses input with csvScanner
+func readAllScanner(input string, opts CSVOptions) ([][]string, error) {
+	s := newCSVScanner([]byte(input), opts)
+	var records [][]string
+	for {
+		record, err := s.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+func TestCSVScannerMatchesEncodingCSV(t *testing.T) {
+	semicolon := defaultCSVOptions()
+	semicolon.Delimiter = ';'
+	comments := defaultCSVOptions()
+	comments.CommentChar = '#'
+	tab := defaultCSVOptions()
+	tab.Delimiter = '\t'
+
+	tests := []struct {
+		name  string
+		input string
+		opts  CSVOptions
+	}{
+		{"simple", "a,b,c\n1,2,3\n4,5,6\n", defaultCSVOptions()},
+		{"no trailing newline", "a,b\n1,2", defaultCSVOptions()},
+		{"empty fields", "a,b,c\n,,\n1,,3\n", defaultCSVOptions()},
+		{"crlf", "a,b\r\n1,2\r\n", defaultCSVOptions()},
+		{"quoted", "a,b\n\"x, y\",\"say \"\"hi\"\"\"\n", defaultCSVOptions()},
+		{"multiline quoted", "a,b\n\"line1\nline2\",2\n", defaultCSVOptions()},
+		{"multiline crlf", "a,b\r\n\"line1\r\nline2\",2\r\n", defaultCSVOptions()},
+		{"empty lines", "a,b\n\n1,2\n\n", defaultCSVOptions()},
+		{"semicolon", "a;b\n1;2\n", semicolon},
+		{"tab", "a\tb\n1\t2\n", tab},
+		{"comments", "a,b\n# comment\n1,2\n", comments},
+		{"unicode", "ad,şehir\nAyşe,İstanbul\n", defaultCSVOptions()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, err := readAllStd(tt.input, tt.opts)
+			if err != nil {
+				t.Fatalf("encoding/csv error: %v", err)
+			}
+			got, err := readAllScanner(tt.input, tt.opts)
+			if err != nil {
+				t.Fatalf("csvScanner error: %v", err)
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("csvScanner = %q, expected = %q", got, expected)
+			}
+		})
+	}
+}
+
+func TestCSVScannerErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   error
+	}{
+		{"bare quote", "a,b\n1,x\"y\n", errQuote},
+		{"unterminated quote", "a,b\n1,\"abc\n", errQuote},
+		{"field count", "a,b\n1,2,3\n", errFieldCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readAllScanner(tt.input, defaultCSVOptions()); !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	lazy := defaultCSVOptions()
+	lazy.LazyQuotes = true
+	got, err := readAllScanner("a,b\n1,x\"y\n", lazy)
+	if err != nil || got[1][1] != `x"y` {
+		t.Errorf("lazy quotes: got %q, %v", got, err)
+	}
+}
+
+func TestReadCSVToRawWithMmap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.csv")
+	content := "name;city\nAli;\"İstanbul\"\n\"Ayşe \"\"A\"\"\";Ankara\nbad;row;here\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	expectedHeaders, expectedRows, err := ReadCSVToRaw(path, WithDelimiter(';'), WithSkipErrors(true))
+	if err != nil {
+		t.Fatalf("ReadCSVToRaw error: %v", err)
+	}
+	headers, rows, err := ReadCSVToRaw(path, WithDelimiter(';'), WithSkipErrors(true), WithMmap(true))
+	if err != nil {
+		t.Fatalf("ReadCSVToRaw with mmap error: %v", err)
+	}
+	if !reflect.DeepEqual(headers, expectedHeaders) || !reflect.DeepEqual(rows, expectedRows) {
+		t.Errorf("mmap result = %q %q, expected = %q %q", headers, rows, expectedHeaders, expectedRows)
+	}
+
+	if _, _, err := ReadCSVToRaw(path, WithDelimiter(';'), WithMmap(true)); err == nil {
+		t.Error("expected error for malformed row without SkipErrors")
+	}
+}
+
+func TestCSVRowReaderWithMmap(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(path, []byte("id,name\n1,Ali\n2,Ayşe\n3,Mehmet\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	r, err := NewCSVRowReader(path, WithMmap(true))
+	if err != nil {
+		t.Fatalf("NewCSVRowReader error: %v", err)
+	}
+	var rows [][]string
+	for {
+		chunk, err := r.Read(2)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read error: %v", err)
+		}
+		for _, row := range chunk {
+			rows = append(rows, []string{strings.Clone(row[0]), strings.Clone(row[1])})
+		}
+	}
+	headers := r.Headers()
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("second Close error: %v", err)
+	}
+
+	// Headers stay valid after the file is unmapped
+	if !reflect.DeepEqual(headers, []string{"id", "name"}) {
+		t.Errorf("Headers() = %v", headers)
+	}
+	if !reflect.DeepEqual(rows, [][]string{{"1", "Ali"}, {"2", "Ayşe"}, {"3", "Mehmet"}}) {
+		t.Errorf("rows = %v", rows)
+	}
+
+	empty := filepath.Join(dir, "empty.csv")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := NewCSVRowReader(empty, WithMmap(true)); err == nil {
+		t.Error("expected error for empty file")
+	}
+}
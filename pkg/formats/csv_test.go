@@ -2,6 +2,7 @@ package formats
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -211,3 +212,27 @@ func TestWriteCSV(t *testing.T) {
 		}
 	}
 }
+
+func TestCSVRowWriterAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.csv")
+	for _, rows := range [][][]string{{{"1", "a"}}, {{"2", "b"}}} {
+		writer, err := NewCSVRowWriter(path, WithAppend(true))
+		if err != nil {
+			t.Fatalf("NewCSVRowWriter error: %v", err)
+		}
+		if err := writer.WriteHeaders([]string{"id", "name"}); err != nil {
+			t.Fatalf("WriteHeaders error: %v", err)
+		}
+		if err := writer.WriteRows(rows); err != nil {
+			t.Fatalf("WriteRows error: %v", err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Close error: %v", err)
+		}
+	}
+
+	content, _ := os.ReadFile(path)
+	if string(content) != "id,name\n1,a\n2,b\n" {
+		t.Errorf("unexpected content: %q", content)
+	}
+}
@@ -0,0 +1,183 @@
+package formats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// BulkIndexer sends an Elasticsearch bulk API payload (newline-delimited JSON).
+// The body is reused for the next batch after Bulk returns.
+type BulkIndexer interface {
+	Bulk(ctx context.Context, body []byte) error
+}
+
+// ElasticsearchClient sends bulk requests to an Elasticsearch cluster over HTTP
+type ElasticsearchClient struct {
+	URL        string       // Base URL of the cluster, e.g. http://localhost:9200
+	Header     http.Header  // Extra request headers, e.g. Authorization
+	HTTPClient *http.Client // Defaults to http.DefaultClient
+}
+
+// NewElasticsearchClient creates a client for the cluster at url
+func NewElasticsearchClient(url string) *ElasticsearchClient {
+	return &ElasticsearchClient{URL: strings.TrimRight(url, "/"), Header: http.Header{}}
+}
+
+// bulkResponse is the part of the bulk API response needed to report failed documents
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// Bulk posts the payload to the _bulk endpoint. It fails if the request fails or any document is rejected.
+func (c *ElasticsearchClient) Bulk(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL+"/_bulk", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create bulk request: %w", err)
+	}
+	for key, values := range c.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("bulk request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read bulk response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("bulk request failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(data))
+	}
+
+	var result bulkResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("failed to parse bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil
+	}
+
+	failed := 0
+	reason := ""
+	for _, item := range result.Items {
+		for _, status := range item {
+			if status.Error != nil {
+				if failed == 0 {
+					reason = status.Error.Type + ": " + status.Error.Reason
+				}
+				failed++
+			}
+		}
+	}
+	return fmt.Errorf("%d of %d documents were rejected, first error: %s", failed, len(result.Items), reason)
+}
+
+// ElasticsearchOptions contains Elasticsearch writing options
+type ElasticsearchOptions struct {
+	Context   context.Context
+	IDColumn  string // Column used as document _id (empty: generated by Elasticsearch)
+	BatchSize int    // Documents per bulk request
+}
+
+// ElasticsearchOption is a function type for setting Elasticsearch options
+type ElasticsearchOption func(*ElasticsearchOptions)
+
+// defaultElasticsearchOptions returns default Elasticsearch options
+func defaultElasticsearchOptions() ElasticsearchOptions {
+	return ElasticsearchOptions{
+		Context:   context.Background(),
+		BatchSize: 1000,
+	}
+}
+
+// WithElasticsearchContext sets the context of the bulk requests
+func WithElasticsearchContext(ctx context.Context) ElasticsearchOption {
+	return func(o *ElasticsearchOptions) {
+		if ctx != nil {
+			o.Context = ctx
+		}
+	}
+}
+
+// WithIDColumn sets the column whose values are used as document IDs. Rows with an empty ID get
+// a generated one.
+func WithIDColumn(column string) ElasticsearchOption {
+	return func(o *ElasticsearchOptions) {
+		o.IDColumn = column
+	}
+}
+
+// WithBulkBatchSize sets how many documents are sent per bulk request
+func WithBulkBatchSize(size int) ElasticsearchOption {
+	return func(o *ElasticsearchOptions) {
+		if size > 0 {
+			o.BatchSize = size
+		}
+	}
+}
+
+// WriteElasticsearchFromRaw indexes every row as a document into index through the bulk API.
+// Documents are encoded like JSON records, one bulk request per batch.
+func WriteElasticsearchFromRaw(headers []string, data [][]string, client BulkIndexer, index string, options ...ElasticsearchOption) error {
+	opts := defaultElasticsearchOptions()
+	for _, option := range options {
+		option(&opts)
+	}
+
+	idIndex := -1
+	if opts.IDColumn != "" {
+		if idIndex = slices.Index(headers, opts.IDColumn); idIndex == -1 {
+			return fmt.Errorf("ID column not found: %s", opts.IDColumn)
+		}
+	}
+
+	encoder := newJSONRecordEncoder(headers, false)
+	action := appendJSONString([]byte(`{"index":{"_index":`), index)
+	var body []byte
+	for start := 0; start < len(data); start += opts.BatchSize {
+		end := min(start+opts.BatchSize, len(data))
+
+		body = body[:0]
+		for _, row := range data[start:end] {
+			body = append(body, action...)
+			if idIndex != -1 && idIndex < len(row) && row[idIndex] != "" {
+				body = append(body, `,"_id":`...)
+				body = appendJSONString(body, row[idIndex])
+			}
+			body = append(body, "}}\n"...)
+			body = append(body, encoder.encode(row)...)
+			body = append(body, '\n')
+		}
+
+		if err := client.Bulk(opts.Context, body); err != nil {
+			return fmt.Errorf("rows %d-%d: %w", start, end-1, err)
+		}
+	}
+	return nil
+}
+
+// WriteElasticsearch writes DataFrame to an Elasticsearch index
+func WriteElasticsearch(df DataFrame, client BulkIndexer, index string, options ...ElasticsearchOption) error {
+	return WriteElasticsearchFromRaw(df.GetHeaders(), df.GetData(), client, index, options...)
+}
@@ -0,0 +1,87 @@
+package formats
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// bulkRecorder records the bulk payloads it receives
+type bulkRecorder struct {
+	bodies []string
+}
+
+func (r *bulkRecorder) Bulk(_ context.Context, body []byte) error {
+	r.bodies = append(r.bodies, string(body))
+	return nil
+}
+
+func TestWriteElasticsearchFromRaw(t *testing.T) {
+	headers := []string{"id", "name"}
+	data := [][]string{{"1", "Ali"}, {"", "Ayşe"}, {"3", "<b>"}}
+
+	recorder := &bulkRecorder{}
+	err := WriteElasticsearchFromRaw(headers, data, recorder, "people", WithIDColumn("id"), WithBulkBatchSize(2))
+	if err != nil {
+		t.Fatalf("WriteElasticsearchFromRaw error: %v", err)
+	}
+
+	expected := []string{
+		`{"index":{"_index":"people","_id":"1"}}` + "\n" + `{"id":"1","name":"Ali"}` + "\n" +
+			`{"index":{"_index":"people"}}` + "\n" + `{"id":"","name":"Ayşe"}` + "\n",
+		`{"index":{"_index":"people","_id":"3"}}` + "\n" + `{"id":"3","name":"\u003cb\u003e"}` + "\n",
+	}
+	if len(recorder.bodies) != len(expected) {
+		t.Fatalf("expected %d bulk requests, got %d", len(expected), len(recorder.bodies))
+	}
+	for i := range expected {
+		if recorder.bodies[i] != expected[i] {
+			t.Errorf("request %d: expected %q, got %q", i, expected[i], recorder.bodies[i])
+		}
+	}
+
+	if err := WriteElasticsearchFromRaw(headers, data, recorder, "people", WithIDColumn("missing")); err == nil {
+		t.Error("expected an error for a missing ID column")
+	}
+}
+
+func TestElasticsearchClient_Bulk(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_bulk" || r.Header.Get("Content-Type") != "application/x-ndjson" || r.Header.Get("Authorization") != "ApiKey secret" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		if strings.Contains(received, "reject") {
+			io.WriteString(w, `{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]}`)
+			return
+		}
+		io.WriteString(w, `{"errors":false,"items":[{"index":{"status":201}}]}`)
+	}))
+	defer server.Close()
+
+	client := NewElasticsearchClient(server.URL + "/")
+	client.Header.Set("Authorization", "ApiKey secret")
+
+	if err := WriteElasticsearchFromRaw([]string{"name"}, [][]string{{"Ali"}}, client, "people"); err != nil {
+		t.Fatalf("Bulk error: %v", err)
+	}
+	if received != `{"index":{"_index":"people"}}`+"\n"+`{"name":"Ali"}`+"\n" {
+		t.Errorf("unexpected payload: %q", received)
+	}
+
+	err := WriteElasticsearchFromRaw([]string{"name"}, [][]string{{"ok"}, {"reject"}}, client, "people")
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 documents were rejected") || !strings.Contains(err.Error(), "mapper_parsing_exception") {
+		t.Errorf("expected a rejected document error, got %v", err)
+	}
+
+	client.Header.Del("Authorization")
+	if err := client.Bulk(context.Background(), []byte("{}\n")); err == nil || !strings.Contains(err.Error(), "status 400") {
+		t.Errorf("expected a status error, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package formats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"filippo.io/age"
+)
+
+// ErrDecrypt is returned when encrypted data cannot be decrypted: a wrong key or passphrase, a
+// truncated file or data that was modified after it was encrypted
+var ErrDecrypt = errors.New("failed to decrypt")
+
+// Encryptor encrypts written files at rest, e.g. cleaned data that is still sensitive before it
+// is handed off. See AgeRecipients and AgePassphrase.
+type Encryptor interface {
+	// Encrypt returns a writer that encrypts what is written to it into w. Close writes the end
+	// of the encrypted data; it does not close w.
+	Encrypt(w io.Writer) (io.WriteCloser, error)
+}
+
+// ageEncryptor encrypts in the age format, https://age-encryption.org/v1, with filippo.io/age
+type ageEncryptor []age.Recipient
+
+// Encrypt implements Encryptor
+func (recipients ageEncryptor) Encrypt(w io.Writer) (io.WriteCloser, error) {
+	return age.Encrypt(w, recipients...)
+}
+
+// AgePassphrase returns an Encryptor that encrypts in the age format with a passphrase, e.g. from
+// a secret manager, so the files can be decrypted with the age tool or DecryptAgePassphrase:
+//
+//	age --decrypt cleaned.csv.age > cleaned.csv
+//
+// The file key is wrapped with scrypt, which makes every file take about a second to encrypt.
+func AgePassphrase(passphrase string) (Encryptor, error) {
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return ageEncryptor{recipient}, nil
+}
+
+// DecryptAgePassphrase returns a reader of the data of an age file encrypted with the passphrase.
+// A wrong passphrase or data that fails to decrypt is an ErrDecrypt error.
+func DecryptAgePassphrase(r io.Reader, passphrase string) (io.Reader, error) {
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return decryptAge(r, identity)
+}
+
+// decryptAge returns a reader of the data of an age file for one of the identities, with the
+// errors of the header and of the data wrapped in ErrDecrypt
+func decryptAge(r io.Reader, identities ...age.Identity) (io.Reader, error) {
+	decrypted, err := age.Decrypt(r, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecrypt, err)
+	}
+	return &decryptReader{r: decrypted}, nil
+}
+
+// decryptReader wraps the errors of the encrypted data, other than io.EOF, in ErrDecrypt
+type decryptReader struct {
+	r io.Reader
+}
+
+// Read implements io.Reader
+func (d *decryptReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("%w: %w", ErrDecrypt, err)
+	}
+	return n, err
+}
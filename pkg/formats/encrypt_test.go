@@ -0,0 +1,246 @@
+package formats
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	agetest "c2sp.org/CCTV/age"
+)
+
+// encryptBytes encrypts data with the encryptor
+func encryptBytes(t *testing.T, encryptor Encryptor, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := encryptor.Encrypt(&buf)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestAgePassphrase_RoundTrip(t *testing.T) {
+	encryptor, err := AgePassphrase("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("AgePassphrase failed: %v", err)
+	}
+	data := bytes.Repeat([]byte("name,email\n"), 10000)
+	encrypted := encryptBytes(t, encryptor, data)
+	if bytes.Contains(encrypted, []byte("name,email")) {
+		t.Error("encrypted data contains the plain text")
+	}
+
+	r, err := DecryptAgePassphrase(bytes.NewReader(encrypted), "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("DecryptAgePassphrase failed: %v", err)
+	}
+	decrypted, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if !bytes.Equal(decrypted, data) {
+		t.Error("decrypted data differs")
+	}
+
+	if _, err := DecryptAgePassphrase(bytes.NewReader(encrypted), "wrong"); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("expected ErrDecrypt for a wrong passphrase, got %v", err)
+	}
+	if _, err := AgePassphrase(""); err == nil {
+		t.Error("expected an error for an empty passphrase")
+	}
+}
+
+func TestAgeRecipients_RoundTrip(t *testing.T) {
+	identity, recipient, err := GenerateAgeIdentity()
+	if err != nil {
+		t.Fatalf("GenerateAgeIdentity failed: %v", err)
+	}
+	if !strings.HasPrefix(identity, "AGE-SECRET-KEY-1") || !strings.HasPrefix(recipient, "age1") {
+		t.Fatalf("unexpected keys %q, %q", identity, recipient)
+	}
+	otherIdentity, otherRecipient, _ := GenerateAgeIdentity()
+
+	encryptor, err := AgeRecipients(recipient, otherRecipient)
+	if err != nil {
+		t.Fatalf("AgeRecipients failed: %v", err)
+	}
+	data := bytes.Repeat([]byte("name,email\n"), 10000)
+	encrypted := encryptBytes(t, encryptor, data)
+	if !bytes.HasPrefix(encrypted, []byte("age-encryption.org/v1\n")) {
+		t.Errorf("missing age header: %q", encrypted[:22])
+	}
+
+	// Every recipient can decrypt the file
+	for _, id := range []string{identity, otherIdentity} {
+		r, err := DecryptAge(bytes.NewReader(encrypted), id)
+		if err != nil {
+			t.Fatalf("DecryptAge failed: %v", err)
+		}
+		decrypted, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if !bytes.Equal(decrypted, data) {
+			t.Error("decrypted data differs")
+		}
+	}
+
+	strangerIdentity, _, _ := GenerateAgeIdentity()
+	if _, err := DecryptAge(bytes.NewReader(encrypted), strangerIdentity); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("expected ErrDecrypt for another identity, got %v", err)
+	}
+}
+
+func TestAgeRecipients_InvalidRecipient(t *testing.T) {
+	for _, recipients := range [][]string{
+		nil,
+		{"age1invalid"},
+		// A valid recipient with one character changed fails the checksum
+		{"age1zvkyg2lqzraa2lnjvqej32nkuu0ues2s82hzrye869xeexvn73equnujwk"},
+	} {
+		if _, err := AgeRecipients(recipients...); err == nil {
+			t.Errorf("expected an error for %v", recipients)
+		}
+	}
+	if _, err := AgeRecipients("age1zvkyg2lqzraa2lnjvqej32nkuu0ues2s82hzrye869xeexvn73equnujwj"); err != nil {
+		t.Errorf("AgeRecipients failed: %v", err)
+	}
+}
+
+func TestWriters_Encryption(t *testing.T) {
+	identity, recipient, _ := GenerateAgeIdentity()
+	encryptor, _ := AgeRecipients(recipient)
+	headers := []string{"name", "email"}
+	data := [][]string{{"Ali", "ali@example.com"}}
+
+	tests := []struct {
+		name  string
+		file  string
+		write func(path string) error
+		want  string
+	}{
+		{"csv", "out.csv", func(path string) error {
+			return WriteCSVFromRaw(headers, data, path, WithEncryption(encryptor))
+		}, "name,email\nAli,ali@example.com\n"},
+		{"json", "out.json", func(path string) error {
+			return WriteJSONFromRaw(headers, data, path, WithJSONEncryption(encryptor))
+		}, `"email":"ali@example.com"`},
+		{"yaml", "out.yaml", func(path string) error {
+			return WriteYAMLFromRaw(headers, data, path, WithYAMLEncryption(encryptor))
+		}, "ali@example.com"},
+		{"xml", "out.xml", func(path string) error {
+			return WriteXMLFromRaw(headers, data, path, WithXMLEncryption(encryptor))
+		}, "<email>ali@example.com</email>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := tt.write(path); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			r, err := DecryptAge(file, identity)
+			if err != nil {
+				t.Fatalf("DecryptAge failed: %v", err)
+			}
+			decrypted, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("read failed: %v", err)
+			}
+			if !strings.Contains(string(decrypted), tt.want) {
+				t.Errorf("decrypted = %q, expected to contain %q", decrypted, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVRowWriter_EncryptionWithAppend(t *testing.T) {
+	_, recipient, _ := GenerateAgeIdentity()
+	encryptor, _ := AgeRecipients(recipient)
+	path := filepath.Join(t.TempDir(), "out.csv")
+	if _, err := NewCSVRowWriter(path, WithAppend(true), WithEncryption(encryptor)); err == nil {
+		t.Error("expected an error when appending to an encrypted file")
+	}
+}
+
+// TestDecryptAge_Vectors decrypts the age test vectors of the Community Cryptography Test Vectors,
+// https://c2sp.org/CCTV/age, so files are read like the age tool reads them. Armored vectors are
+// skipped since written files are never armored.
+func TestDecryptAge_Vectors(t *testing.T) {
+	names, err := fs.ReadDir(agetest.Vectors, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		contents, err := fs.ReadFile(agetest.Vectors, name.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Run(name.Name(), func(t *testing.T) {
+			t.Parallel()
+			var expect, payload string
+			var identities, passphrases []string
+			header, file, _ := bytes.Cut(contents, []byte("\n\n"))
+			for _, line := range strings.Split(string(header), "\n") {
+				key, value, _ := strings.Cut(line, ": ")
+				switch key {
+				case "expect":
+					expect = value
+				case "payload":
+					payload = value
+				case "identity":
+					identities = append(identities, value)
+				case "passphrase":
+					passphrases = append(passphrases, value)
+				case "armored":
+					t.Skip("armored file")
+				}
+			}
+
+			var r io.Reader
+			var err error
+			switch {
+			case len(passphrases) == 0:
+				r, err = DecryptAge(bytes.NewReader(file), identities...)
+			case len(passphrases) == 1 && len(identities) == 0:
+				r, err = DecryptAgePassphrase(bytes.NewReader(file), passphrases[0])
+			default:
+				t.Skip("several kinds of identities")
+			}
+			var decrypted []byte
+			if err == nil {
+				decrypted, err = io.ReadAll(r)
+			}
+
+			if expect != "success" {
+				if !errors.Is(err, ErrDecrypt) {
+					t.Errorf("expected ErrDecrypt for %s, got %v", expect, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decrypt failed: %v", err)
+			}
+			if sum := sha256.Sum256(decrypted); hex.EncodeToString(sum[:]) != payload {
+				t.Errorf("payload hash %x, expected %s", sum, payload)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package formats
 
 import (
 	"fmt"
+	"io"
+	"slices"
 	"strconv"
 
 	"github.com/xuri/excelize/v2"
@@ -9,7 +11,12 @@ import (
 
 // ExcelOptions, Excel reading and writing options
 type ExcelOptions struct {
-	SheetName string // Sheet name
+	SheetName     string    // Sheet name
+	StringColumns []string  // Columns written as text even if their values look like numbers
+	Provenance    bool      // Add the _source_file and _source_row columns when reading
+	Fsync         bool      // Flush written files to stable storage before they replace the destination
+	Overwrite     bool      // Replace an existing file when writing
+	Encryptor     Encryptor // Encrypts written files, nil to write them in plain text
 }
 
 // ExcelOption, Excel options
@@ -19,6 +26,7 @@ type ExcelOption func(*ExcelOptions)
 func defaultExcelOptions() *ExcelOptions {
 	return &ExcelOptions{
 		SheetName: "Sheet1",
+		Overwrite: true,
 	}
 }
 
@@ -29,6 +37,42 @@ func WithSheetName(sheetName string) ExcelOption {
 	}
 }
 
+// WithExcelStringColumns, the columns are written as text, so identifiers such as "007" keep their leading zeros
+func WithExcelStringColumns(columns []string) ExcelOption {
+	return func(o *ExcelOptions) {
+		o.StringColumns = append(o.StringColumns, columns...)
+	}
+}
+
+// WithExcelProvenance, ReadExcelToRaw adds the _source_file and _source_row columns. _source_row counts the
+// data rows, so it is the worksheet row number minus one.
+func WithExcelProvenance(provenance bool) ExcelOption {
+	return func(o *ExcelOptions) {
+		o.Provenance = provenance
+	}
+}
+
+// WithExcelFsync, written files are flushed to stable storage before they replace the destination, see WithFsync
+func WithExcelFsync(fsync bool) ExcelOption {
+	return func(o *ExcelOptions) {
+		o.Fsync = fsync
+	}
+}
+
+// WithExcelOverwrite, writers replace an existing file, see WithOverwrite
+func WithExcelOverwrite(overwrite bool) ExcelOption {
+	return func(o *ExcelOptions) {
+		o.Overwrite = overwrite
+	}
+}
+
+// WithExcelEncryption, written files are encrypted with the encryptor, see WithEncryption
+func WithExcelEncryption(encryptor Encryptor) ExcelOption {
+	return func(o *ExcelOptions) {
+		o.Encryptor = encryptor
+	}
+}
+
 // ReadExcelToRaw, read Excel file and return raw data
 func ReadExcelToRaw(filePath string, options ...ExcelOption) ([]string, [][]string, error) {
 	// Default options
@@ -77,10 +121,13 @@ func ReadExcelToRaw(filePath string, options ...ExcelOption) ([]string, [][]stri
 	// Get data rows
 	data := rows[1:]
 
+	if opts.Provenance {
+		headers, data = addProvenance(filePath, headers, data)
+	}
 	return headers, data, nil
 }
 
-// WriteExcelFromRaw, ham veriyi Excel dosyasına yazar
+// WriteExcelFromRaw writes raw data to an Excel file
 func WriteExcelFromRaw(headers []string, data [][]string, filePath string, options ...ExcelOption) error {
 	// Default options
 	opts := defaultExcelOptions()
@@ -115,6 +162,12 @@ func WriteExcelFromRaw(headers []string, data [][]string, filePath string, optio
 		f.SetCellValue(opts.SheetName, cell, header)
 	}
 
+	// Columns that must stay text
+	text := make([]bool, len(headers))
+	for j, header := range headers {
+		text[j] = slices.Contains(opts.StringColumns, header)
+	}
+
 	// Write data
 	for i, row := range data {
 		for j, value := range row {
@@ -123,6 +176,11 @@ func WriteExcelFromRaw(headers []string, data [][]string, filePath string, optio
 				return fmt.Errorf("cell coordinates cannot be calculated: %w", err)
 			}
 
+			if j < len(text) && text[j] {
+				f.SetCellStr(opts.SheetName, cell, value)
+				continue
+			}
+
 			// Save numeric values as numbers
 			if isNumeric(value) {
 				if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
@@ -144,8 +202,13 @@ func WriteExcelFromRaw(headers []string, data [][]string, filePath string, optio
 		}
 	}
 
-	// Save file
-	if err := f.SaveAs(filePath); err != nil {
+	// Save file; the path selects the workbook content type, the temporary file replaces the
+	// destination only once it is complete
+	f.Path = filePath
+	err := writeAtomic(filePath, opts.Fsync, opts.Overwrite, opts.Encryptor, func(w io.Writer) error {
+		return f.Write(w)
+	})
+	if err != nil {
 		return fmt.Errorf("excel file cannot be saved: %w", err)
 	}
 
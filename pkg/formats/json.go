@@ -1,15 +1,21 @@
 package formats
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 )
 
 // JSONOptions contains JSON reading and writing options
 type JSONOptions struct {
-	Pretty bool // Format JSON nicely
+	Pretty     bool      // Format JSON nicely
+	Provenance bool      // Add the _source_file and _source_row columns when reading
+	Fsync      bool      // Flush written files to stable storage before they replace the destination
+	Overwrite  bool      // Replace an existing file when writing
+	Encryptor  Encryptor // Encrypts written files, nil to write them in plain text
 }
 
 // JSONOption is a function type for setting JSON options
@@ -18,7 +24,8 @@ type JSONOption func(*JSONOptions)
 // defaultJSONOptions returns default JSON options
 func defaultJSONOptions() JSONOptions {
 	return JSONOptions{
-		Pretty: false,
+		Pretty:    false,
+		Overwrite: true,
 	}
 }
 
@@ -29,6 +36,35 @@ func WithPretty(pretty bool) JSONOption {
 	}
 }
 
+// WithJSONProvenance determines whether ReadJSONToRaw adds the _source_file and _source_row columns
+func WithJSONProvenance(provenance bool) JSONOption {
+	return func(o *JSONOptions) {
+		o.Provenance = provenance
+	}
+}
+
+// WithJSONFsync determines whether written files are flushed to stable storage before they replace
+// the destination, see WithFsync
+func WithJSONFsync(fsync bool) JSONOption {
+	return func(o *JSONOptions) {
+		o.Fsync = fsync
+	}
+}
+
+// WithJSONOverwrite determines whether writers replace an existing file, see WithOverwrite
+func WithJSONOverwrite(overwrite bool) JSONOption {
+	return func(o *JSONOptions) {
+		o.Overwrite = overwrite
+	}
+}
+
+// WithJSONEncryption encrypts written files with the encryptor, see WithEncryption
+func WithJSONEncryption(encryptor Encryptor) JSONOption {
+	return func(o *JSONOptions) {
+		o.Encryptor = encryptor
+	}
+}
+
 // ReadJSONToRaw reads a JSON file and returns raw data
 func ReadJSONToRaw(filePath string, options ...JSONOption) ([]string, [][]string, error) {
 	// Default settings
@@ -53,11 +89,22 @@ func ReadJSONToRaw(filePath string, options ...JSONOption) ([]string, [][]string
 		return nil, nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
+	headers, rows := recordsToRaw(data)
+	if opts.Provenance {
+		headers, rows = addProvenance(filePath, headers, rows)
+	}
+	return headers, rows, nil
+}
+
+// recordsToRaw flattens decoded JSON records into raw data. Every top-level key becomes a column
+// (sorted for deterministic output); nested objects and arrays are kept as JSON strings and missing
+// keys become empty values.
+func recordsToRaw(records []map[string]interface{}) ([]string, [][]string) {
 	// Collect headers
 	headers := make(map[string]bool)
 
 	// Iterate through all records to collect unique headers
-	for _, record := range data {
+	for _, record := range records {
 		for key := range record {
 			headers[key] = true
 		}
@@ -71,8 +118,8 @@ func ReadJSONToRaw(filePath string, options ...JSONOption) ([]string, [][]string
 	sort.Strings(headerSlice)
 
 	// Convert data
-	rows := make([][]string, len(data))
-	for i, record := range data {
+	rows := make([][]string, len(records))
+	for i, record := range records {
 		row := make([]string, len(headerSlice))
 		for j, header := range headerSlice {
 			if val, ok := record[header]; ok {
@@ -84,11 +131,37 @@ func ReadJSONToRaw(filePath string, options ...JSONOption) ([]string, [][]string
 		rows[i] = row
 	}
 
-	return headerSlice, rows, nil
+	return headerSlice, rows
 }
 
-// WriteJSONFromRaw writes raw data to a JSON file
+// WriteJSONFromRaw writes raw data to a JSON file. Records are encoded one by one into a pooled
+// buffer, so the output is never held in memory as a whole.
 func WriteJSONFromRaw(headers []string, data [][]string, filePath string, options ...JSONOption) error {
+	writer, err := NewJSONRowWriter(filePath, options...)
+	if err != nil {
+		return err
+	}
+	if err := writer.WriteHeaders(headers); err != nil {
+		writer.Abort()
+		return err
+	}
+	if err := writer.WriteRows(data); err != nil {
+		writer.Abort()
+		return err
+	}
+	return writer.Close()
+}
+
+// JSONRowReader reads a JSON array file incrementally.
+// The file is scanned once up front to collect the headers, so only one record is held in memory at a time.
+type JSONRowReader struct {
+	file    *os.File
+	decoder *json.Decoder
+	headers []string
+}
+
+// NewJSONRowReader opens a JSON array file for incremental reading
+func NewJSONRowReader(filePath string, options ...JSONOption) (*JSONRowReader, error) {
 	// Default settings
 	opts := defaultJSONOptions()
 
@@ -97,38 +170,206 @@ func WriteJSONFromRaw(headers []string, data [][]string, filePath string, option
 		option(&opts)
 	}
 
-	// Convert data to JSON format
-	jsonData := make([]map[string]interface{}, len(data))
-	for i, row := range data {
-		record := make(map[string]interface{})
-		for j, header := range headers {
-			if j < len(row) {
-				record[header] = row[j]
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JSON file: %w", err)
+	}
+
+	// First pass: collect headers
+	headers := make(map[string]bool)
+	decoder, err := openJSONArray(file)
+	if err == nil {
+		for decoder.More() {
+			var record map[string]interface{}
+			if err = decoder.Decode(&record); err != nil {
+				break
+			}
+			for key := range record {
+				headers[key] = true
 			}
 		}
-		jsonData[i] = record
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
-	// Convert to JSON
-	var jsonBytes []byte
-	var err error
-	if opts.Pretty {
-		jsonBytes, err = json.MarshalIndent(jsonData, "", "  ")
-	} else {
-		jsonBytes, err = json.Marshal(jsonData)
+	headerSlice := make([]string, 0, len(headers))
+	for header := range headers {
+		headerSlice = append(headerSlice, header)
 	}
+	sort.Strings(headerSlice)
+
+	// Second pass starts from the beginning
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to rewind JSON file: %w", err)
+	}
+	if decoder, err = openJSONArray(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	return &JSONRowReader{file: file, decoder: decoder, headers: headerSlice}, nil
+}
+
+// openJSONArray returns a decoder positioned after the opening bracket of a JSON array
+func openJSONArray(r io.Reader) (*json.Decoder, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	token, err := decoder.Token()
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+		return nil, err
 	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("expected JSON array, got %v", token)
+	}
+	return decoder, nil
+}
+
+// Headers returns the sorted union of all record keys
+func (r *JSONRowReader) Headers() []string {
+	return r.headers
+}
 
-	// Write to file
-	if err := os.WriteFile(filePath, jsonBytes, 0644); err != nil {
+// Read returns up to n rows, or io.EOF when the array is exhausted
+func (r *JSONRowReader) Read(n int) ([][]string, error) {
+	var rows [][]string
+	for (n <= 0 || len(rows) < n) && r.decoder.More() {
+		var record map[string]interface{}
+		if err := r.decoder.Decode(&record); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		row := make([]string, len(r.headers))
+		for j, header := range r.headers {
+			if val, ok := record[header]; ok {
+				row[j] = formatJSONValue(val)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, io.EOF
+	}
+	return rows, nil
+}
+
+// Close closes the JSON file
+func (r *JSONRowReader) Close() error {
+	return r.file.Close()
+}
+
+// JSONRowWriter writes a JSON array file incrementally. The array goes to a temporary file that
+// replaces the destination on Close, so a failed write never leaves a truncated file.
+type JSONRowWriter struct {
+	file    *atomicFile
+	failed  bool
+	writer  *bufio.Writer
+	encoder *jsonRecordEncoder
+	pretty  bool
+	count   int
+}
+
+// NewJSONRowWriter creates a JSON file for incremental writing
+func NewJSONRowWriter(filePath string, options ...JSONOption) (*JSONRowWriter, error) {
+	// Default settings
+	opts := defaultJSONOptions()
+
+	// Apply user-specified settings
+	for _, option := range options {
+		option(&opts)
+	}
+
+	file, err := createAtomic(filePath, opts.Fsync, opts.Overwrite, opts.Encryptor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JSON file: %w", err)
+	}
+
+	return &JSONRowWriter{file: file, writer: getWriteBuffer(file.Writer()), pretty: opts.Pretty}, nil
+}
+
+// WriteHeaders sets the record keys and opens the JSON array
+func (w *JSONRowWriter) WriteHeaders(headers []string) error {
+	w.encoder = newJSONRecordEncoder(headers, w.pretty)
+	if _, err := w.writer.WriteString("["); err != nil {
+		w.failed = true
 		return fmt.Errorf("failed to write JSON file: %w", err)
 	}
+	return nil
+}
+
+// WriteRows appends rows to the JSON array as objects
+func (w *JSONRowWriter) WriteRows(rows [][]string) error {
+	if w.encoder == nil {
+		return ErrHeadersNotWritten
+	}
 
+	for _, row := range rows {
+		sep := ","
+		if w.count == 0 {
+			sep = ""
+		}
+		if w.pretty {
+			sep += "\n  "
+		}
+		if _, err := w.writer.WriteString(sep); err != nil {
+			w.failed = true
+			return fmt.Errorf("failed to write JSON file: %w", err)
+		}
+		if _, err := w.writer.Write(w.encoder.encode(row)); err != nil {
+			w.failed = true
+			return fmt.Errorf("failed to write JSON file: %w", err)
+		}
+		w.count++
+	}
 	return nil
 }
 
+// Close closes the JSON array, flushes the buffer and replaces the destination with the file.
+// After a failed write, the destination is left untouched as if Abort was called.
+func (w *JSONRowWriter) Close() error {
+	if w.writer == nil {
+		return nil
+	}
+	if w.failed {
+		return w.Abort()
+	}
+	defer func() {
+		putWriteBuffer(w.writer)
+		w.writer = nil
+	}()
+
+	end := "]"
+	if w.pretty && w.count > 0 {
+		end = "\n]"
+	}
+	if w.encoder == nil {
+		end = "[]"
+	}
+	if _, err := w.writer.WriteString(end); err != nil {
+		w.file.Abort()
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+	if err := w.writer.Flush(); err != nil {
+		w.file.Abort()
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+	if err := w.file.Commit(); err != nil {
+		return fmt.Errorf("failed to replace JSON file: %w", err)
+	}
+	return nil
+}
+
+// Abort discards the written rows and leaves the destination untouched
+func (w *JSONRowWriter) Abort() error {
+	if w.writer == nil {
+		return nil
+	}
+	putWriteBuffer(w.writer)
+	w.writer = nil
+	return w.file.Abort()
+}
+
 // WriteJSON writes DataFrame to a JSON file
 func WriteJSON(df interface {
 	GetHeaders() []string
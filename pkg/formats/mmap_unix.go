@@ -0,0 +1,31 @@
+//go:build unix
+
+package formats
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile maps the file read-only into memory. The returned function unmaps it.
+func mmapFile(filePath string) ([]byte, func() error, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Size() == 0 {
+		return nil, func() error { return nil }, nil
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,184 @@
+package formats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoOptions contains MongoDB reading and writing options
+type MongoOptions struct {
+	Context   context.Context
+	BatchSize int // Documents per cursor batch and per insert
+}
+
+// MongoOption is a function type for setting MongoDB options
+type MongoOption func(*MongoOptions)
+
+// defaultMongoOptions returns default MongoDB options
+func defaultMongoOptions() MongoOptions {
+	return MongoOptions{
+		Context:   context.Background(),
+		BatchSize: 1000,
+	}
+}
+
+// WithMongoContext sets the context for connecting, reading and writing
+func WithMongoContext(ctx context.Context) MongoOption {
+	return func(o *MongoOptions) {
+		if ctx != nil {
+			o.Context = ctx
+		}
+	}
+}
+
+// WithMongoBatchSize sets how many documents are fetched per cursor batch and inserted per request
+func WithMongoBatchSize(size int) MongoOption {
+	return func(o *MongoOptions) {
+		if size > 0 {
+			o.BatchSize = size
+		}
+	}
+}
+
+// ReadMongoToRaw reads the documents of a collection that match filter and returns raw data.
+// Documents are flattened like JSON records: every top-level field becomes a column, nested
+// documents and arrays are kept as JSON strings. ObjectIDs are written as hex strings and dates
+// in RFC 3339 format. A nil filter matches all documents.
+func ReadMongoToRaw(uri, database, collection string, filter interface{}, options ...MongoOption) ([]string, [][]string, error) {
+	opts := defaultMongoOptions()
+	for _, option := range options {
+		option(&opts)
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	client, err := connectMongo(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer client.Disconnect(opts.Context)
+
+	cursor, err := client.Database(database).Collection(collection).Find(opts.Context, filter, findOptions(opts))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query MongoDB collection: %w", err)
+	}
+	defer cursor.Close(opts.Context)
+
+	var records []map[string]interface{}
+	for cursor.Next(opts.Context) {
+		var document bson.D
+		if err := cursor.Decode(&document); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode MongoDB document: %w", err)
+		}
+		records = append(records, mongoRecord(document))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read MongoDB collection: %w", err)
+	}
+
+	headers, rows := recordsToRaw(records)
+	return headers, rows, nil
+}
+
+// WriteMongoFromRaw inserts every row as a document into the collection. Values are written as
+// strings and empty values are omitted, mirroring how missing fields are read.
+func WriteMongoFromRaw(headers []string, data [][]string, uri, database, collection string, options ...MongoOption) error {
+	opts := defaultMongoOptions()
+	for _, option := range options {
+		option(&opts)
+	}
+
+	client, err := connectMongo(uri)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(opts.Context)
+
+	coll := client.Database(database).Collection(collection)
+	for start := 0; start < len(data); start += opts.BatchSize {
+		end := min(start+opts.BatchSize, len(data))
+		if _, err := coll.InsertMany(opts.Context, rowsToDocuments(headers, data[start:end])); err != nil {
+			return fmt.Errorf("failed to insert MongoDB documents: %w", err)
+		}
+	}
+	return nil
+}
+
+// WriteMongo writes DataFrame to a MongoDB collection
+func WriteMongo(df DataFrame, uri, database, collection string, options ...MongoOption) error {
+	return WriteMongoFromRaw(df.GetHeaders(), df.GetData(), uri, database, collection, options...)
+}
+
+// connectMongo creates a client for the connection string
+func connectMongo(uri string) (*mongo.Client, error) {
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	return client, nil
+}
+
+// findOptions returns the cursor options of a read
+func findOptions(opts MongoOptions) *options.FindOptionsBuilder {
+	return options.Find().SetBatchSize(int32(min(opts.BatchSize, 1<<31-1)))
+}
+
+// mongoRecord converts a document to a JSON record so it can be flattened like a JSON file
+func mongoRecord(document bson.D) map[string]interface{} {
+	record := make(map[string]interface{}, len(document))
+	for _, element := range document {
+		record[element.Key] = mongoValue(element.Value)
+	}
+	return record
+}
+
+// mongoValue converts a BSON value to its JSON equivalent
+func mongoValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.D:
+		return mongoRecord(v)
+	case bson.M:
+		record := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			record[key] = mongoValue(item)
+		}
+		return record
+	case bson.A:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = mongoValue(item)
+		}
+		return items
+	case bson.ObjectID:
+		return v.Hex()
+	case bson.DateTime:
+		return v.Time().UTC().Format(time.RFC3339Nano)
+	case bson.Decimal128:
+		return v.String()
+	case int32:
+		return float64(v)
+	default:
+		return v
+	}
+}
+
+// rowsToDocuments converts rows to documents with fields in header order
+func rowsToDocuments(headers []string, rows [][]string) []interface{} {
+	documents := make([]interface{}, len(rows))
+	for i, row := range rows {
+		document := make(bson.D, 0, len(headers))
+		for j, header := range headers {
+			if j < len(row) && row[j] != "" {
+				document = append(document, bson.E{Key: header, Value: row[j]})
+			}
+		}
+		documents[i] = document
+	}
+	return documents
+}
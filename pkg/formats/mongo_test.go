@@ -0,0 +1,64 @@
+package formats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMongoRecordsFlattenLikeJSON(t *testing.T) {
+	id, _ := bson.ObjectIDFromHex("65f1a2b3c4d5e6f708192a3b")
+	created := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	documents := []bson.D{
+		{
+			{Key: "_id", Value: id},
+			{Key: "name", Value: "Ali"},
+			{Key: "age", Value: int32(30)},
+			{Key: "created_at", Value: bson.NewDateTimeFromTime(created)},
+			{Key: "address", Value: bson.D{{Key: "city", Value: "Izmir"}}},
+			{Key: "tags", Value: bson.A{"a", int64(2)}},
+		},
+		{
+			{Key: "name", Value: "Ayşe"},
+			{Key: "score", Value: 9.5},
+		},
+	}
+
+	records := make([]map[string]interface{}, len(documents))
+	for i, document := range documents {
+		records[i] = mongoRecord(document)
+	}
+	headers, rows := recordsToRaw(records)
+
+	expectedHeaders := []string{"_id", "address", "age", "created_at", "name", "score", "tags"}
+	if !reflect.DeepEqual(headers, expectedHeaders) {
+		t.Errorf("expected headers %v, got %v", expectedHeaders, headers)
+	}
+	expectedRows := [][]string{
+		{"65f1a2b3c4d5e6f708192a3b", `{"city":"Izmir"}`, "30", "2024-03-01T12:30:00Z", "Ali", "", `["a",2]`},
+		{"", "", "", "", "Ayşe", "9.5", ""},
+	}
+	if !reflect.DeepEqual(rows, expectedRows) {
+		t.Errorf("expected rows %v, got %v", expectedRows, rows)
+	}
+}
+
+func TestRowsToDocuments(t *testing.T) {
+	documents := rowsToDocuments([]string{"id", "name", "city"}, [][]string{{"1", "Ali", ""}, {"2", "Ayşe", "Izmir"}})
+
+	expected := []interface{}{
+		bson.D{{Key: "id", Value: "1"}, {Key: "name", Value: "Ali"}},
+		bson.D{{Key: "id", Value: "2"}, {Key: "name", Value: "Ayşe"}, {Key: "city", Value: "Izmir"}},
+	}
+	if !reflect.DeepEqual(documents, expected) {
+		t.Errorf("expected %v, got %v", expected, documents)
+	}
+}
+
+func TestReadMongoToRaw_InvalidURI(t *testing.T) {
+	if _, _, err := ReadMongoToRaw("not-a-uri", "db", "items", nil); err == nil {
+		t.Error("expected an error for an invalid connection string")
+	}
+}
@@ -1,12 +1,17 @@
 package formats
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go-source/writerfile"
+	"github.com/xitongsys/parquet-go/common"
 	"github.com/xitongsys/parquet-go/parquet"
 	"github.com/xitongsys/parquet-go/reader"
 	"github.com/xitongsys/parquet-go/writer"
@@ -14,7 +19,12 @@ import (
 
 // ParquetOptions, Parquet includes read and write options
 type ParquetOptions struct {
-	Compression parquet.CompressionCodec // Compression algorithm
+	Compression   parquet.CompressionCodec // Compression algorithm
+	StringColumns []string                 // Columns written as strings even if their values look like numbers
+	Provenance    bool                     // Add the _source_file and _source_row columns when reading
+	Fsync         bool                     // Flush written files to stable storage before they replace the destination
+	Overwrite     bool                     // Replace an existing file when writing
+	Encryptor     Encryptor                // Encrypts written files, nil to write them in plain text
 }
 
 // ParquetOption, Function type for setting Parquet options
@@ -24,6 +34,7 @@ type ParquetOption func(*ParquetOptions)
 func defaultParquetOptions() *ParquetOptions {
 	return &ParquetOptions{
 		Compression: parquet.CompressionCodec_SNAPPY,
+		Overwrite:   true,
 	}
 }
 
@@ -34,12 +45,48 @@ func WithCompression(compression parquet.CompressionCodec) ParquetOption {
 	}
 }
 
+// WithParquetStringColumns, the columns are written as strings, so identifiers such as "007" keep their leading zeros
+func WithParquetStringColumns(columns []string) ParquetOption {
+	return func(o *ParquetOptions) {
+		o.StringColumns = append(o.StringColumns, columns...)
+	}
+}
+
+// WithParquetProvenance, ReadParquetToRaw adds the _source_file and _source_row columns
+func WithParquetProvenance(provenance bool) ParquetOption {
+	return func(o *ParquetOptions) {
+		o.Provenance = provenance
+	}
+}
+
+// WithParquetFsync, written files are flushed to stable storage before they replace the destination, see WithFsync
+func WithParquetFsync(fsync bool) ParquetOption {
+	return func(o *ParquetOptions) {
+		o.Fsync = fsync
+	}
+}
+
+// WithParquetOverwrite, writers replace an existing file, see WithOverwrite
+func WithParquetOverwrite(overwrite bool) ParquetOption {
+	return func(o *ParquetOptions) {
+		o.Overwrite = overwrite
+	}
+}
+
+// WithParquetEncryption, written files are encrypted with the encryptor, see WithEncryption
+func WithParquetEncryption(encryptor Encryptor) ParquetOption {
+	return func(o *ParquetOptions) {
+		o.Encryptor = encryptor
+	}
+}
+
 // ParquetRecord, Represents a record in a Parquet file
 type ParquetRecord map[string]interface{}
 
-// ReadParquetToRaw, Reads the Parquet file and returns the raw data
+// ReadParquetToRaw, Reads the Parquet file and returns the raw data. Columns keep the order of the
+// schema and null values become empty strings.
 func ReadParquetToRaw(filePath string, options ...ParquetOption) ([]string, [][]string, error) {
-	// Varsayılan ayarlar
+	// Default settings
 	opts := defaultParquetOptions()
 
 	// Apply user-specified settings
@@ -54,53 +101,82 @@ func ReadParquetToRaw(filePath string, options ...ParquetOption) ([]string, [][]
 	}
 	defer fr.Close()
 
-	// Create parquet reader
+	// Create parquet reader; rows are read into a struct type built from the file schema
 	pr, err := reader.NewParquetReader(fr, nil, 4)
 	if err != nil {
 		return nil, nil, fmt.Errorf("parquet reader could not be created: %w", err)
 	}
 	defer pr.ReadStop()
 
-	// Get the number of records
-	numRows := int(pr.GetNumRows())
-	if numRows == 0 {
-		return nil, nil, fmt.Errorf("parquet file is empty")
+	rowType, err := pr.SchemaHandler.GetType(pr.SchemaHandler.GetRootInName())
+	if err != nil {
+		return nil, nil, fmt.Errorf("parquet schema could not be read: %w", err)
 	}
 
-	// Verileri oku
-	records := make([]ParquetRecord, numRows)
-	if err := pr.Read(&records); err != nil {
-		return nil, nil, fmt.Errorf("parquet data could not be read: %w", err)
+	// Headers are the external names of the top-level fields
+	root := pr.SchemaHandler.GetRootInName()
+	headers := make([]string, rowType.NumField())
+	for i := range headers {
+		name := rowType.Field(i).Name
+		exPath := pr.SchemaHandler.InPathToExPath[root+common.PAR_GO_PATH_DELIMITER+name]
+		if j := strings.LastIndex(exPath, common.PAR_GO_PATH_DELIMITER); j != -1 {
+			name = exPath[j+len(common.PAR_GO_PATH_DELIMITER):]
+		}
+		headers[i] = name
 	}
 
-	// Collect titles (from the first record)
-	if len(records) == 0 {
-		return nil, nil, fmt.Errorf("parquet records not found")
+	numRows := int(pr.GetNumRows())
+	data := make([][]string, 0, numRows)
+	if numRows == 0 {
+		if opts.Provenance {
+			headers, data = addProvenance(filePath, headers, data)
+		}
+		return headers, data, nil
 	}
 
-	headers := make([]string, 0)
-	for header := range records[0] {
-		headers = append(headers, header)
+	records, err := pr.ReadByNumber(numRows)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parquet data could not be read: %w", err)
 	}
 
 	// Convert data to string matrix
-	data := make([][]string, numRows)
-	for i, record := range records {
+	for _, record := range records {
+		v := reflect.ValueOf(record)
 		row := make([]string, len(headers))
-		for j, header := range headers {
-			if val, ok := record[header]; ok {
-				row[j] = fmt.Sprintf("%v", val)
-			} else {
-				row[j] = "" // Empty string for missing values
-			}
+		for j := range headers {
+			row[j] = formatParquetValue(v.Field(j))
 		}
-		data[i] = row
+		data = append(data, row)
 	}
 
+	if opts.Provenance {
+		headers, data = addProvenance(filePath, headers, data)
+	}
 	return headers, data, nil
 }
 
-// WriteParquetFromRaw, writes raw data to Parquet file
+// formatParquetValue converts a value read from a Parquet file to string; nulls become empty strings
+func formatParquetValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+// WriteParquetFromRaw, writes raw data to Parquet file. The type of every column is determined from
+// all its values: int64, double or boolean if every non-empty value parses as one, otherwise string.
+// Empty values of typed columns are written as nulls.
 func WriteParquetFromRaw(headers []string, data [][]string, filePath string, options ...ParquetOption) error {
 	// Default settings
 	opts := defaultParquetOptions()
@@ -110,18 +186,22 @@ func WriteParquetFromRaw(headers []string, data [][]string, filePath string, opt
 		option(opts)
 	}
 
-	// Create Parquet file
-	fw, err := local.NewLocalFileWriter(filePath)
+	columnTypes := parquetColumnTypes(headers, data, opts.StringColumns)
+	schema, err := parquetSchema(headers, columnTypes)
 	if err != nil {
-		return fmt.Errorf("parquet file could not be created: %w", err)
+		return err
 	}
-	defer fw.Close()
 
-	// Create schematic for Parquet printer
-	schema := generateParquetSchema(headers, data)
+	// The file replaces the destination only once it is complete
+	return writeAtomic(filePath, opts.Fsync, opts.Overwrite, opts.Encryptor, func(file io.Writer) error {
+		return writeParquet(file, headers, data, schema, columnTypes, opts)
+	})
+}
 
-	// Parquet yazıcı oluştur
-	pw, err := writer.NewParquetWriter(fw, schema, 4)
+// writeParquet writes the Parquet file of the rows to file
+func writeParquet(file io.Writer, headers []string, data [][]string, schema string, columnTypes []parquet.Type, opts *ParquetOptions) error {
+	// Create the Parquet writer
+	pw, err := writer.NewJSONWriter(schema, writerfile.NewWriterFile(file), 4)
 	if err != nil {
 		return fmt.Errorf("failed to create parquet printer: %w", err)
 	}
@@ -131,49 +211,25 @@ func WriteParquetFromRaw(headers []string, data [][]string, filePath string, opt
 
 	// Transform and write data
 	for _, row := range data {
-		record := make(ParquetRecord)
+		record := make(ParquetRecord, len(headers))
 		for i, header := range headers {
+			value := ""
 			if i < len(row) {
-				// Determine the data type and convert appropriately
-				value := row[i]
-				if isNumeric(value) {
-					// Numerical value
-					if strings.Contains(value, ".") {
-						// Decimal number
-						if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
-							record[header] = floatVal
-							continue
-						}
-					} else {
-						// Whole number
-						if intVal, err := strconv.ParseInt(value, 10, 64); err == nil {
-							record[header] = intVal
-							continue
-						}
-					}
-				}
-
-				// Boolean value
-				if value == "true" || value == "false" {
-					if boolVal, err := strconv.ParseBool(value); err == nil {
-						record[header] = boolVal
-						continue
-					}
-				}
-
-				// String value
-				record[header] = value
-			} else {
-				record[header] = ""
+				value = row[i]
 			}
+			record[header] = parquetValue(value, columnTypes[i])
 		}
 
-		if err := pw.Write(record); err != nil {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("parquet write error: %w", err)
+		}
+		if err := pw.Write(string(line)); err != nil {
 			return fmt.Errorf("parquet write error: %w", err)
 		}
 	}
 
-	// Yazıcıyı kapat
+	// Close the writer
 	if err := pw.WriteStop(); err != nil {
 		return fmt.Errorf("parquet printer failed to close: %w", err)
 	}
@@ -186,62 +242,91 @@ func WriteParquet(df DataFrame, filePath string, options ...ParquetOption) error
 	return WriteParquetFromRaw(df.GetHeaders(), df.GetData(), filePath, options...)
 }
 
-// generateParquetSchema, creates Parquet schema for given headers and data
-func generateParquetSchema(headers []string, data [][]string) interface{} {
-	// Veri tiplerini belirle
-	types := make(map[string]reflect.Type)
-	for _, header := range headers {
-		types[header] = reflect.TypeOf("")
-	}
-
-	// Identify the data types by examining the first few lines
-	sampleSize := 10
-	if len(data) < sampleSize {
-		sampleSize = len(data)
-	}
-
-	for i := 0; i < sampleSize; i++ {
-		row := data[i]
-		for j, header := range headers {
-			if j < len(row) {
-				value := row[j]
-				if value == "" {
-					continue
-				}
-
-				// Check for numeric value
-				if isNumeric(value) {
-					if strings.Contains(value, ".") {
-						types[header] = reflect.TypeOf(float64(0))
-					} else {
-						types[header] = reflect.TypeOf(int64(0))
-					}
-					continue
-				}
-
-				// Check if it is a Boolean value
-				if value == "true" || value == "false" {
-					types[header] = reflect.TypeOf(bool(false))
-					continue
-				}
+// parquetColumnTypes returns the Parquet type of every column: INT64, DOUBLE or BOOLEAN if all its
+// non-empty values parse as one, otherwise BYTE_ARRAY. The string columns are always BYTE_ARRAY.
+func parquetColumnTypes(headers []string, data [][]string, stringColumns []string) []parquet.Type {
+	columnTypes := make([]parquet.Type, len(headers))
+	for j, header := range headers {
+		columnTypes[j] = parquet.Type_BYTE_ARRAY
+		if slices.Contains(stringColumns, header) {
+			continue
+		}
+
+		isInt, isFloat, isBool, seen := true, true, true, false
+		for _, row := range data {
+			if j >= len(row) || row[j] == "" {
+				continue
+			}
+			value := row[j]
+			seen = true
+			if isInt {
+				_, err := strconv.ParseInt(value, 10, 64)
+				isInt = err == nil
+			}
+			if isFloat {
+				isFloat = isNumeric(value)
 			}
+			if isBool {
+				isBool = value == "true" || value == "false"
+			}
+			if !isInt && !isFloat && !isBool {
+				break
+			}
+		}
+
+		switch {
+		case !seen:
+		case isInt:
+			columnTypes[j] = parquet.Type_INT64
+		case isFloat:
+			columnTypes[j] = parquet.Type_DOUBLE
+		case isBool:
+			columnTypes[j] = parquet.Type_BOOLEAN
 		}
 	}
+	return columnTypes
+}
+
+// parquetSchema returns the JSON schema of a file with the given columns. All columns are optional.
+func parquetSchema(headers []string, columnTypes []parquet.Type) (string, error) {
+	type field struct {
+		Tag    string  `json:"Tag"`
+		Fields []field `json:"Fields,omitempty"`
+	}
 
-	// Create dynamic schema
-	record := make(ParquetRecord)
-	for _, header := range headers {
-		switch types[header].Kind() {
-		case reflect.Int64:
-			record[header] = int64(0)
-		case reflect.Float64:
-			record[header] = float64(0)
-		case reflect.Bool:
-			record[header] = false
-		default:
-			record[header] = ""
+	root := field{Tag: "name=parquet_go_root, repetitiontype=REQUIRED"}
+	for j, header := range headers {
+		if strings.ContainsAny(header, ",=") {
+			return "", fmt.Errorf("parquet column names cannot contain ',' or '=': %q", header)
 		}
+		tag := "name=" + header + ", type=" + columnTypes[j].String()
+		if columnTypes[j] == parquet.Type_BYTE_ARRAY {
+			tag += ", convertedtype=UTF8"
+		}
+		root.Fields = append(root.Fields, field{Tag: tag + ", repetitiontype=OPTIONAL"})
 	}
 
-	return record
+	schema, err := json.Marshal(root)
+	if err != nil {
+		return "", fmt.Errorf("parquet schema could not be created: %w", err)
+	}
+	return string(schema), nil
+}
+
+// parquetValue converts a value to the type of its column; empty values of typed columns are null
+func parquetValue(value string, columnType parquet.Type) interface{} {
+	if value == "" && columnType != parquet.Type_BYTE_ARRAY {
+		return nil
+	}
+	switch columnType {
+	case parquet.Type_INT64:
+		v, _ := strconv.ParseInt(value, 10, 64)
+		return v
+	case parquet.Type_DOUBLE:
+		v, _ := strconv.ParseFloat(value, 64)
+		return v
+	case parquet.Type_BOOLEAN:
+		return value == "true"
+	}
+	return value
 }
@@ -0,0 +1,65 @@
+package formats
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+func TestParquetRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "people.parquet")
+	headers := []string{"name", "age", "score", "active", "zip"}
+	data := [][]string{
+		{"Ali", "30", "9.5", "true", "01234"},
+		{"Ayşe", "", "7", "false", "34000"},
+	}
+
+	if err := WriteParquetFromRaw(headers, data, path, WithParquetStringColumns([]string{"zip"})); err != nil {
+		t.Fatalf("WriteParquetFromRaw error: %v", err)
+	}
+
+	gotHeaders, gotData, err := ReadParquetToRaw(path)
+	if err != nil {
+		t.Fatalf("ReadParquetToRaw error: %v", err)
+	}
+	if !reflect.DeepEqual(gotHeaders, headers) {
+		t.Errorf("expected headers %v, got %v", headers, gotHeaders)
+	}
+	if !reflect.DeepEqual(gotData, data) {
+		t.Errorf("expected data %v, got %v", data, gotData)
+	}
+}
+
+func TestParquetRoundTrip_Empty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.parquet")
+	if err := WriteParquetFromRaw([]string{"id"}, nil, path); err != nil {
+		t.Fatalf("WriteParquetFromRaw error: %v", err)
+	}
+
+	headers, data, err := ReadParquetToRaw(path)
+	if err != nil {
+		t.Fatalf("ReadParquetToRaw error: %v", err)
+	}
+	if !reflect.DeepEqual(headers, []string{"id"}) || len(data) != 0 {
+		t.Errorf("expected the id header and no rows, got %v %v", headers, data)
+	}
+}
+
+func TestParquetColumnTypes(t *testing.T) {
+	headers := []string{"int", "float", "bool", "text", "empty", "id"}
+	data := [][]string{
+		{"1", "1.5", "true", "a", "", "007"},
+		{"", "2", "false", "1", "", "42"},
+	}
+
+	got := parquetColumnTypes(headers, data, []string{"id"})
+	expected := []parquet.Type{
+		parquet.Type_INT64, parquet.Type_DOUBLE, parquet.Type_BOOLEAN,
+		parquet.Type_BYTE_ARRAY, parquet.Type_BYTE_ARRAY, parquet.Type_BYTE_ARRAY,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,26 @@
+package formats
+
+import "strconv"
+
+// Provenance columns added by the readers with the provenance options, e.g. WithProvenance
+const (
+	SourceFileColumn = "_source_file" // Path of the file the row was read from
+	SourceRowColumn  = "_source_row"  // Position of the row among the records of the file, starting at 1
+)
+
+// addProvenance appends the provenance columns to rows read in order from filePath. Rows shorter
+// than the headers, e.g. Excel rows ending in empty cells, are padded first.
+func addProvenance(filePath string, headers []string, data [][]string) ([]string, [][]string) {
+	for i, row := range data {
+		for len(row) < len(headers) {
+			row = append(row, "")
+		}
+		data[i] = provenanceRow(row, filePath, i+1)
+	}
+	return append(headers, SourceFileColumn, SourceRowColumn), data
+}
+
+// provenanceRow appends the file path and the record number to row
+func provenanceRow(row []string, filePath string, record int) []string {
+	return append(row, filePath, strconv.Itoa(record))
+}
@@ -0,0 +1,60 @@
+package formats
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadCSVToRaw_Provenance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "people.csv")
+	// The second record has a field too many and is skipped
+	if err := os.WriteFile(path, []byte("name,age\nAli,30\nbad,1,2\nAyşe,25\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	for _, mmap := range []bool{false, true} {
+		headers, data, err := ReadCSVToRaw(path, WithProvenance(true), WithSkipErrors(true), WithMmap(mmap))
+		if err != nil {
+			t.Fatalf("ReadCSVToRaw error: %v", err)
+		}
+		if !reflect.DeepEqual(headers, []string{"name", "age", SourceFileColumn, SourceRowColumn}) {
+			t.Errorf("mmap %v: unexpected headers: %v", mmap, headers)
+		}
+		expected := [][]string{{"Ali", "30", path, "1"}, {"Ayşe", "25", path, "3"}}
+		if !reflect.DeepEqual(data, expected) {
+			t.Errorf("mmap %v: expected %v, got %v", mmap, expected, data)
+		}
+	}
+}
+
+func TestReadJSONToRaw_Provenance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "people.json")
+	if err := os.WriteFile(path, []byte(`[{"name":"Ali"},{"name":"Ayşe"}]`), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	headers, data, err := ReadJSONToRaw(path, WithJSONProvenance(true))
+	if err != nil {
+		t.Fatalf("ReadJSONToRaw error: %v", err)
+	}
+	if !reflect.DeepEqual(headers, []string{"name", SourceFileColumn, SourceRowColumn}) {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+	if !reflect.DeepEqual(data, [][]string{{"Ali", path, "1"}, {"Ayşe", path, "2"}}) {
+		t.Errorf("unexpected data: %v", data)
+	}
+}
+
+func TestAddProvenance_PadsShortRows(t *testing.T) {
+	headers, data := addProvenance("book.xlsx", []string{"a", "b"}, [][]string{{"1"}, {"2", "3"}})
+
+	if !reflect.DeepEqual(headers, []string{"a", "b", SourceFileColumn, SourceRowColumn}) {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+	expected := [][]string{{"1", "", "book.xlsx", "1"}, {"2", "3", "book.xlsx", "2"}}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("expected %v, got %v", expected, data)
+	}
+}
@@ -0,0 +1,107 @@
+package formats
+
+import (
+	"errors"
+	"io"
+)
+
+// RowReader reads rows incrementally so a source does not have to fit in memory
+type RowReader interface {
+	// Headers returns the column headers of the source
+	Headers() []string
+	// Read returns up to n rows. It returns io.EOF when no rows remain.
+	Read(n int) ([][]string, error)
+	// Close releases the underlying file
+	Close() error
+}
+
+// RowWriter writes rows incrementally
+type RowWriter interface {
+	// WriteHeaders writes the column headers. It must be called once before WriteRows.
+	WriteHeaders(headers []string) error
+	// WriteRows appends rows to the output
+	WriteRows(rows [][]string) error
+	// Close flushes buffered output and releases the underlying file
+	Close() error
+}
+
+// RowAborter is implemented by row writers that can discard their output instead of replacing the
+// destination on Close, such as the CSV and JSON row writers
+type RowAborter interface {
+	// Abort releases the underlying file and leaves the destination untouched
+	Abort() error
+}
+
+// ErrHeadersNotWritten is returned when rows are written before the headers
+var ErrHeadersNotWritten = errors.New("headers must be written before rows")
+
+// rawRowReader serves rows that are already in memory
+type rawRowReader struct {
+	headers []string
+	data    [][]string
+	offset  int
+}
+
+// NewRawRowReader returns a RowReader over in-memory data. It is used for formats that
+// cannot be read incrementally (Excel, Parquet, XML, YAML) after reading them with the *ToRaw functions.
+func NewRawRowReader(headers []string, data [][]string) RowReader {
+	return &rawRowReader{headers: headers, data: data}
+}
+
+func (r *rawRowReader) Headers() []string {
+	return r.headers
+}
+
+func (r *rawRowReader) Read(n int) ([][]string, error) {
+	if r.offset >= len(r.data) {
+		return nil, io.EOF
+	}
+	end := r.offset + n
+	if n <= 0 || end > len(r.data) {
+		end = len(r.data)
+	}
+	rows := r.data[r.offset:end]
+	r.offset = end
+	return rows, nil
+}
+
+func (r *rawRowReader) Close() error {
+	return nil
+}
+
+// bufferedRowWriter collects rows and writes them all on Close
+type bufferedRowWriter struct {
+	headers []string
+	data    [][]string
+	write   func(headers []string, data [][]string) error
+}
+
+// NewBufferedRowWriter returns a RowWriter that collects rows in memory and passes them to write
+// on Close. It is used for formats that cannot be written incrementally, e.g.:
+//
+//	formats.NewBufferedRowWriter(func(h []string, d [][]string) error {
+//		return formats.WriteExcelFromRaw(h, d, "out.xlsx")
+//	})
+func NewBufferedRowWriter(write func(headers []string, data [][]string) error) RowWriter {
+	return &bufferedRowWriter{write: write}
+}
+
+func (w *bufferedRowWriter) WriteHeaders(headers []string) error {
+	w.headers = headers
+	return nil
+}
+
+func (w *bufferedRowWriter) WriteRows(rows [][]string) error {
+	if w.headers == nil {
+		return ErrHeadersNotWritten
+	}
+	w.data = append(w.data, rows...)
+	return nil
+}
+
+func (w *bufferedRowWriter) Close() error {
+	if w.headers == nil {
+		return ErrHeadersNotWritten
+	}
+	return w.write(w.headers, w.data)
+}
@@ -0,0 +1,184 @@
+package formats
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRawRowReader(t *testing.T) {
+	r := NewRawRowReader([]string{"id"}, [][]string{{"1"}, {"2"}, {"3"}})
+
+	first, err := r.Read(2)
+	if err != nil || len(first) != 2 {
+		t.Fatalf("Read(2) = %v, %v; expected 2 rows", first, err)
+	}
+	second, err := r.Read(2)
+	if err != nil || len(second) != 1 || second[0][0] != "3" {
+		t.Fatalf("Read(2) = %v, %v; expected last row", second, err)
+	}
+	if _, err := r.Read(2); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestBufferedRowWriter(t *testing.T) {
+	var gotHeaders []string
+	var gotData [][]string
+	w := NewBufferedRowWriter(func(headers []string, data [][]string) error {
+		gotHeaders, gotData = headers, data
+		return nil
+	})
+
+	if err := w.WriteRows([][]string{{"x"}}); !errors.Is(err, ErrHeadersNotWritten) {
+		t.Errorf("expected ErrHeadersNotWritten, got %v", err)
+	}
+
+	_ = w.WriteHeaders([]string{"id"})
+	_ = w.WriteRows([][]string{{"1"}})
+	_ = w.WriteRows([][]string{{"2"}})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	if !reflect.DeepEqual(gotHeaders, []string{"id"}) || len(gotData) != 2 {
+		t.Errorf("unexpected buffered output: %v %v", gotHeaders, gotData)
+	}
+}
+
+func TestCSVRowReaderWriter(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.csv")
+	output := filepath.Join(dir, "out.csv")
+	if err := os.WriteFile(input, []byte("id;name\n1;Ali\n2;Ayşe\n3;Mehmet\n"), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	r, err := NewCSVRowReader(input, WithDelimiter(';'))
+	if err != nil {
+		t.Fatalf("NewCSVRowReader error: %v", err)
+	}
+	defer r.Close()
+
+	w, err := NewCSVRowWriter(output)
+	if err != nil {
+		t.Fatalf("NewCSVRowWriter error: %v", err)
+	}
+	if err := w.WriteHeaders(r.Headers()); err != nil {
+		t.Fatalf("WriteHeaders error: %v", err)
+	}
+
+	chunks := 0
+	for {
+		rows, err := r.Read(2)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read error: %v", err)
+		}
+		chunks++
+		if err := w.WriteRows(rows); err != nil {
+			t.Fatalf("WriteRows error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	if chunks != 2 {
+		t.Errorf("expected 2 chunks, got %d", chunks)
+	}
+	content, _ := os.ReadFile(output)
+	if string(content) != "id,name\n1,Ali\n2,Ayşe\n3,Mehmet\n" {
+		t.Errorf("unexpected output: %q", content)
+	}
+}
+
+func TestJSONRowReaderWriter(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.json")
+	output := filepath.Join(dir, "out.json")
+	data := `[{"name":"Ali","age":30},{"name":"Ayşe","city":"Ankara"},{"name":"Mehmet","active":true}]`
+	if err := os.WriteFile(input, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	r, err := NewJSONRowReader(input)
+	if err != nil {
+		t.Fatalf("NewJSONRowReader error: %v", err)
+	}
+	defer r.Close()
+
+	expectedHeaders := []string{"active", "age", "city", "name"}
+	if !reflect.DeepEqual(r.Headers(), expectedHeaders) {
+		t.Fatalf("Headers() = %v, expected = %v", r.Headers(), expectedHeaders)
+	}
+
+	rows, err := r.Read(2)
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("Read(2) = %v, %v", rows, err)
+	}
+	if !reflect.DeepEqual(rows[0], []string{"", "30", "", "Ali"}) {
+		t.Errorf("unexpected first row: %v", rows[0])
+	}
+	rest, err := r.Read(2)
+	if err != nil || len(rest) != 1 || rest[0][0] != "true" {
+		t.Fatalf("Read(2) = %v, %v", rest, err)
+	}
+	if _, err := r.Read(2); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+
+	for _, pretty := range []bool{false, true} {
+		w, err := NewJSONRowWriter(output, WithPretty(pretty))
+		if err != nil {
+			t.Fatalf("NewJSONRowWriter error: %v", err)
+		}
+		_ = w.WriteHeaders(expectedHeaders)
+		_ = w.WriteRows(rows)
+		_ = w.WriteRows(rest)
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close error: %v", err)
+		}
+
+		headers, written, err := ReadJSONToRaw(output)
+		if err != nil {
+			t.Fatalf("pretty=%v: output is not valid JSON: %v", pretty, err)
+		}
+		if !reflect.DeepEqual(headers, expectedHeaders) || len(written) != 3 {
+			t.Errorf("pretty=%v: unexpected output %v %v", pretty, headers, written)
+		}
+	}
+}
+
+func TestJSONRowWriter_Empty(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "empty.json")
+	w, err := NewJSONRowWriter(output)
+	if err != nil {
+		t.Fatalf("NewJSONRowWriter error: %v", err)
+	}
+	_ = w.WriteHeaders([]string{"id"})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	content, _ := os.ReadFile(output)
+	if string(content) != "[]" {
+		t.Errorf("expected empty array, got %q", content)
+	}
+}
+
+func TestJSONRowReader_NotArray(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "object.json")
+	if err := os.WriteFile(input, []byte(`{"name":"Ali"}`), 0644); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	if _, err := NewJSONRowReader(input); err == nil {
+		t.Error("expected error for non-array JSON")
+	}
+}
@@ -11,9 +11,13 @@ import (
 
 // XMLOptions contains XML reading and writing options
 type XMLOptions struct {
-	RootElement string // Root element name for XML
-	ItemElement string // Item element name for XML
-	Pretty      bool   // Format XML nicely
+	RootElement string    // Root element name for XML
+	ItemElement string    // Item element name for XML
+	Pretty      bool      // Format XML nicely
+	Provenance  bool      // Add the _source_file and _source_row columns when reading
+	Fsync       bool      // Flush written files to stable storage before they replace the destination
+	Overwrite   bool      // Replace an existing file when writing
+	Encryptor   Encryptor // Encrypts written files, nil to write them in plain text
 }
 
 // XMLOption is a function type for setting XML options
@@ -25,6 +29,7 @@ func defaultXMLOptions() XMLOptions {
 		RootElement: "root",
 		ItemElement: "item",
 		Pretty:      false,
+		Overwrite:   true,
 	}
 }
 
@@ -49,6 +54,35 @@ func WithXMLPretty(pretty bool) XMLOption {
 	}
 }
 
+// WithXMLProvenance determines whether ReadXMLToRaw adds the _source_file and _source_row columns
+func WithXMLProvenance(provenance bool) XMLOption {
+	return func(o *XMLOptions) {
+		o.Provenance = provenance
+	}
+}
+
+// WithXMLFsync determines whether written files are flushed to stable storage before they replace
+// the destination, see WithFsync
+func WithXMLFsync(fsync bool) XMLOption {
+	return func(o *XMLOptions) {
+		o.Fsync = fsync
+	}
+}
+
+// WithXMLOverwrite determines whether writers replace an existing file, see WithOverwrite
+func WithXMLOverwrite(overwrite bool) XMLOption {
+	return func(o *XMLOptions) {
+		o.Overwrite = overwrite
+	}
+}
+
+// WithXMLEncryption encrypts written files with the encryptor, see WithEncryption
+func WithXMLEncryption(encryptor Encryptor) XMLOption {
+	return func(o *XMLOptions) {
+		o.Encryptor = encryptor
+	}
+}
+
 // ReadXMLToRaw reads an XML file and returns raw data
 func ReadXMLToRaw(filePath string, options ...XMLOption) ([]string, [][]string, error) {
 	// Default settings
@@ -155,6 +189,9 @@ func ReadXMLToRaw(filePath string, options ...XMLOption) ([]string, [][]string,
 		rows[i] = row
 	}
 
+	if opts.Provenance {
+		headerSlice, rows = addProvenance(filePath, headerSlice, rows)
+	}
 	return headerSlice, rows, nil
 }
 
@@ -168,13 +205,14 @@ func WriteXMLFromRaw(headers []string, data [][]string, filePath string, options
 		option(&opts)
 	}
 
-	// Create file
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create XML file: %w", err)
-	}
-	defer file.Close()
+	// The file replaces the destination only once it is complete
+	return writeAtomic(filePath, opts.Fsync, opts.Overwrite, opts.Encryptor, func(file io.Writer) error {
+		return writeXML(file, headers, data, opts)
+	})
+}
 
+// writeXML writes the XML document of the rows to file
+func writeXML(file io.Writer, headers []string, data [][]string, opts XMLOptions) error {
 	// Create XML encoder
 	encoder := xml.NewEncoder(file)
 	if opts.Pretty {
@@ -182,7 +220,7 @@ func WriteXMLFromRaw(headers []string, data [][]string, filePath string, options
 	}
 
 	// Write XML header
-	if _, err := file.WriteString(xml.Header); err != nil {
+	if _, err := io.WriteString(file, xml.Header); err != nil {
 		return fmt.Errorf("failed to write XML header: %w", err)
 	}
 
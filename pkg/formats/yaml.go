@@ -2,6 +2,7 @@ package formats
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sort"
 
@@ -10,7 +11,11 @@ import (
 
 // YAMLOptions contains YAML reading and writing options
 type YAMLOptions struct {
-	Pretty bool // Format YAML nicely
+	Pretty     bool      // Format YAML nicely
+	Provenance bool      // Add the _source_file and _source_row columns when reading
+	Fsync      bool      // Flush written files to stable storage before they replace the destination
+	Overwrite  bool      // Replace an existing file when writing
+	Encryptor  Encryptor // Encrypts written files, nil to write them in plain text
 }
 
 // YAMLOption is a function type for setting YAML options
@@ -19,7 +24,8 @@ type YAMLOption func(*YAMLOptions)
 // defaultYAMLOptions returns default YAML options
 func defaultYAMLOptions() YAMLOptions {
 	return YAMLOptions{
-		Pretty: false,
+		Pretty:    false,
+		Overwrite: true,
 	}
 }
 
@@ -30,6 +36,35 @@ func WithYAMLPretty(pretty bool) YAMLOption {
 	}
 }
 
+// WithYAMLProvenance determines whether ReadYAMLToRaw adds the _source_file and _source_row columns
+func WithYAMLProvenance(provenance bool) YAMLOption {
+	return func(o *YAMLOptions) {
+		o.Provenance = provenance
+	}
+}
+
+// WithYAMLFsync determines whether written files are flushed to stable storage before they replace
+// the destination, see WithFsync
+func WithYAMLFsync(fsync bool) YAMLOption {
+	return func(o *YAMLOptions) {
+		o.Fsync = fsync
+	}
+}
+
+// WithYAMLOverwrite determines whether writers replace an existing file, see WithOverwrite
+func WithYAMLOverwrite(overwrite bool) YAMLOption {
+	return func(o *YAMLOptions) {
+		o.Overwrite = overwrite
+	}
+}
+
+// WithYAMLEncryption encrypts written files with the encryptor, see WithEncryption
+func WithYAMLEncryption(encryptor Encryptor) YAMLOption {
+	return func(o *YAMLOptions) {
+		o.Encryptor = encryptor
+	}
+}
+
 // ReadYAMLToRaw reads a YAML file and returns raw data
 func ReadYAMLToRaw(filePath string, options ...YAMLOption) ([]string, [][]string, error) {
 	// Default settings
@@ -85,6 +120,9 @@ func ReadYAMLToRaw(filePath string, options ...YAMLOption) ([]string, [][]string
 		rows[i] = row
 	}
 
+	if opts.Provenance {
+		headerSlice, rows = addProvenance(filePath, headerSlice, rows)
+	}
 	return headerSlice, rows, nil
 }
 
@@ -110,26 +148,23 @@ func WriteYAMLFromRaw(headers []string, data [][]string, filePath string, option
 		yamlData[i] = record
 	}
 
-	// Create file
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create YAML file: %w", err)
-	}
-	defer file.Close()
-
-	// Create YAML encoder
-	encoder := yaml.NewEncoder(file)
-	if opts.Pretty {
-		encoder.SetIndent(2)
-	}
-	defer encoder.Close()
-
-	// Write data
-	if err := encoder.Encode(yamlData); err != nil {
-		return fmt.Errorf("failed to encode YAML: %w", err)
-	}
+	// The file replaces the destination only once it is complete
+	return writeAtomic(filePath, opts.Fsync, opts.Overwrite, opts.Encryptor, func(file io.Writer) error {
+		// Create YAML encoder
+		encoder := yaml.NewEncoder(file)
+		if opts.Pretty {
+			encoder.SetIndent(2)
+		}
 
-	return nil
+		// Write data
+		if err := encoder.Encode(yamlData); err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		return nil
+	})
 }
 
 // WriteYAML writes DataFrame to a YAML file
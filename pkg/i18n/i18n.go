@@ -0,0 +1,146 @@
+// Package i18n translates the user-facing messages of the CLI, the API server and the library errors.
+//
+// Messages are identified by their English text, so untranslated messages fall back to English.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Language is a two-letter ISO 639-1 language code
+type Language string
+
+// Supported languages
+const (
+	English Language = "en"
+	Turkish Language = "tr"
+)
+
+// DefaultLanguage is used when no supported language is requested
+const DefaultLanguage = English
+
+// EnvVar is the environment variable that selects the language of the CLI and the API server
+const EnvVar = "CLEANGO_LANG"
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[Language]map[string]string{
+		Turkish: turkish,
+	}
+)
+
+// Register adds translations for lang, keyed by the English message. Existing translations are replaced.
+func Register(lang Language, translations map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	catalog, ok := catalogs[lang]
+	if !ok {
+		catalog = make(map[string]string, len(translations))
+		catalogs[lang] = catalog
+	}
+	for message, translation := range translations {
+		catalog[message] = translation
+	}
+}
+
+// Supported reports whether lang is English or has registered translations
+func Supported(lang Language) bool {
+	if lang == English {
+		return true
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Parse returns the supported language of a tag such as "tr", "tr-TR" or "tr_TR.UTF-8", or
+// DefaultLanguage if the language is not supported
+func Parse(tag string) Language {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_.;"); i != -1 {
+		tag = tag[:i]
+	}
+	if lang := Language(tag); Supported(lang) {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// FromEnv returns the language selected by CLEANGO_LANG
+func FromEnv() Language {
+	return Parse(os.Getenv(EnvVar))
+}
+
+// FromAcceptLanguage returns the first supported language of an Accept-Language header, or
+// fallback if none is supported. Quality values are ignored; languages are taken in header order.
+func FromAcceptLanguage(header string, fallback Language) Language {
+	for _, part := range strings.Split(header, ",") {
+		tag := strings.ToLower(strings.TrimSpace(part))
+		if i := strings.IndexAny(tag, "-_;"); i != -1 {
+			tag = tag[:i]
+		}
+		if tag != "" && tag != "*" && Supported(Language(tag)) {
+			return Language(tag)
+		}
+	}
+	return fallback
+}
+
+// T translates message to lang. With arguments, the translated message is used as the format.
+func T(lang Language, message string, args ...interface{}) string {
+	translated := lookup(lang, message)
+	if len(args) == 0 {
+		return translated
+	}
+	return fmt.Sprintf(translated, args...)
+}
+
+// Error translates the message of err. Every error in its chain whose message has a translation,
+// such as cleaner.ErrColumnNotFound, is translated within the full message.
+func Error(lang Language, err error) string {
+	if err == nil {
+		return ""
+	}
+	message := err.Error()
+	if lang == English {
+		return message
+	}
+
+	pending := []error{err}
+	for len(pending) > 0 {
+		e := pending[0]
+		pending = pending[1:]
+		text := e.Error()
+		if translated := lookup(lang, text); translated != text {
+			message = strings.ReplaceAll(message, text, translated)
+			continue
+		}
+		switch u := e.(type) {
+		case interface{ Unwrap() []error }:
+			pending = append(pending, u.Unwrap()...)
+		default:
+			if next := errors.Unwrap(e); next != nil {
+				pending = append(pending, next)
+			}
+		}
+	}
+	return message
+}
+
+// lookup returns the translation of message, or message itself
+func lookup(lang Language, message string) string {
+	if lang == English {
+		return message
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	if translated, ok := catalogs[lang][message]; ok {
+		return translated
+	}
+	return message
+}
@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]Language{
+		"tr":          Turkish,
+		"TR":          Turkish,
+		"tr-TR":       Turkish,
+		"tr_TR.UTF-8": Turkish,
+		"en-US":       English,
+		"de":          DefaultLanguage,
+		"":            DefaultLanguage,
+	}
+	for tag, expected := range tests {
+		if got := Parse(tag); got != expected {
+			t.Errorf("Parse(%q) = %q, want %q", tag, got, expected)
+		}
+	}
+}
+
+func TestFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected Language
+	}{
+		{"tr-TR,tr;q=0.9,en;q=0.8", Turkish},
+		{"de-DE, tr;q=0.5", Turkish},
+		{"en-GB", English},
+		{"de, fr", Turkish},
+		{"*", Turkish},
+		{"", Turkish},
+	}
+	for _, tt := range tests {
+		if got := FromAcceptLanguage(tt.header, Turkish); got != tt.expected {
+			t.Errorf("FromAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.expected)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T(Turkish, "Statistics: %d rows, %d columns", 3, 2); got != "İstatistikler: 3 satır, 2 sütun" {
+		t.Errorf("unexpected translation: %q", got)
+	}
+	if got := T(English, "Statistics: %d rows, %d columns", 3, 2); got != "Statistics: 3 rows, 2 columns" {
+		t.Errorf("unexpected English message: %q", got)
+	}
+	if got := T(Turkish, "not in the catalog"); got != "not in the catalog" {
+		t.Errorf("expected untranslated messages in English, got %q", got)
+	}
+}
+
+func TestError(t *testing.T) {
+	errColumnNotFound := errors.New("column not found")
+	err := fmt.Errorf("read error: %w", fmt.Errorf("%w: age", errColumnNotFound))
+
+	if got := Error(Turkish, err); got != "read error: sütun bulunamadı: age" {
+		t.Errorf("unexpected translation: %q", got)
+	}
+	if got := Error(English, err); got != err.Error() {
+		t.Errorf("expected the English message, got %q", got)
+	}
+
+	joined := errors.Join(errors.New("headers must be written before rows"), errors.New("other"))
+	if got := Error(Turkish, joined); got != "satırlardan önce başlıklar yazılmalıdır\nother" {
+		t.Errorf("unexpected translation of joined errors: %q", got)
+	}
+	if Error(Turkish, nil) != "" {
+		t.Errorf("expected empty message for nil error")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	German := Language("de")
+	Register(German, map[string]string{"column not found": "Spalte nicht gefunden"})
+	defer func() {
+		mu.Lock()
+		delete(catalogs, German)
+		mu.Unlock()
+	}()
+
+	if Parse("de-AT") != German {
+		t.Errorf("expected registered language to be supported")
+	}
+	if got := T(German, "column not found"); got != "Spalte nicht gefunden" {
+		t.Errorf("unexpected translation: %q", got)
+	}
+}
@@ -0,0 +1,224 @@
+package i18n
+
+// turkish is the Turkish catalog, keyed by the English message
+var turkish = map[string]string{
+	// Library errors
+	"column not found": "sütun bulunamadı",
+	"input is smaller than when it was last processed": "girdi son işlendiği zamankinden daha küçük",
+	"headers must be written before rows":              "satırlardan önce başlıklar yazılmalıdır",
+	"output quota exceeded":                            "çıktı kotası aşıldı",
+	"value not allowed":                                "değere izin verilmiyor",
+
+	// CLI
+	"Usage: cleango <command> [arguments]":                         "Kullanım: cleango <komut> [argümanlar]",
+	"Commands:":                                                    "Komutlar:",
+	"  clean    Performs data cleaning operation":                  "  clean    Veri temizleme işlemi yapar",
+	"  profile  Scores the data quality of every column":           "  profile  Her sütunun veri kalitesini puanlar",
+	"  schema   Prints or compares the columns and types of files": "  schema   Dosyaların sütunlarını ve türlerini yazdırır veya karşılaştırır",
+	"Error: %s":           "Hata: %s",
+	"Unknown command %q.": "Bilinmeyen komut %q.",
+	"Clean whitespace at the beginning and end of all cells":                                                                   "Tüm hücrelerin başındaki ve sonundaki boşlukları temizle",
+	"Remove control characters and zero-width spaces and replace exotic spaces such as NBSP in all cells":                      "Tüm hücrelerde kontrol karakterlerini ve sıfır genişlikli boşlukları kaldır, NBSP gibi özel boşlukları değiştir",
+	"Date format (e.g.: created_at:2006-01-02)":                                                                                "Tarih formatı (örn.: created_at:2006-01-02)",
+	"Replace empty values (e.g.: age:0,name:Unknown)":                                                                          "Boş değerleri değiştir (örn.: age:0,name:Unknown)",
+	"Convert scientific notation, percents, accounting negatives and currency amounts to plain numbers (e.g.: price,discount)": "Bilimsel gösterimi, yüzdeleri, muhasebe negatiflerini ve para tutarlarını düz sayılara dönüştür (örn.: price,discount)",
+	"Unit conversion with optional decimals (e.g.: weight:lb:kg:2,temp:F:C)":                                                   "İsteğe bağlı ondalık basamaklı birim dönüşümü (örn.: weight:lb:kg:2,temp:F:C)",
+	"Replace values with deterministic fakes (e.g.: customer:name,mail:email,tel:phone,addr:address)":                          "Değerleri deterministik sahte değerlerle değiştir (örn.: customer:name,mail:email,tel:phone,addr:address)",
+	"Seed of the fakes; the same value gets the same fake for the same seed":                                                   "Sahte değerlerin tohumu; aynı tohumla aynı değer aynı sahte değeri alır",
+	"Upper/lower case conversion (e.g.: name:upper,description:lower)":                                                         "Büyük/küçük harf dönüşümü (örn.: name:upper,description:lower)",
+	"Directory of the output file, created if missing":                                                                         "Çıktı dosyasının dizini, yoksa oluşturulur",
+	"Output file (default: cleaned_[input] next to the input or in --output-dir)":                                              "Çıktı dosyası (varsayılan: girdinin yanında veya --output-dir içinde cleaned_[girdi])",
+	"CSV delimiter character":                   "CSV ayırıcı karakteri",
+	"Output format (csv, json, excel, parquet)": "Çıktı formatı (csv, json, excel, parquet)",
+	"Cleaning with regex; write ':' and ',' in a pattern as \\: and \\, (e.g.: name:[0-9]+:,description:\\s+: ,time:([0-9]{2})\\:([0-9]{2}):$1h$2)": "Regex ile temizleme; desendeki ':' ve ',' karakterlerini \\: ve \\, olarak yazın (örn.: name:[0-9]+:,description:\\s+: ,time:([0-9]{2})\\:([0-9]{2}):$1h$2)",
+	"Cleaning with regex as a JSON array of {\"column\", \"pattern\", \"replacement\"} objects, applied after --regex":                              "{\"column\", \"pattern\", \"replacement\"} nesnelerinden oluşan JSON dizisiyle regex temizleme, --regex'ten sonra uygulanır",
+	"Column splitting (e.g.: full_name: :first_name,last_name)":                                                                                     "Sütun bölme (örn.: full_name: :first_name,last_name)",
+	"Outlier value filtering (e.g.: age:18:65)":                                                                                                     "Aykırı değer filtreleme (örn.: age:18:65)",
+	"Excel worksheet name": "Excel çalışma sayfası adı",
+	"Parquet compression algorithm (snappy, gzip, lz4, zstd, uncompressed)": "Parquet sıkıştırma algoritması (snappy, gzip, lz4, zstd, uncompressed)",
+	"Use parallel processing": "Paralel işleme kullan",
+	"Compare and aggregate numbers as exact decimals instead of floating point, e.g. for monetary values":        "Sayıları kayan nokta yerine tam ondalık olarak karşılaştır ve topla, örn. para tutarları için",
+	"Number of workers for parallel processing (0: as many as CPU cores)":                                        "Paralel işleme için işçi sayısı (0: CPU çekirdeği kadar)",
+	"Print wall time, rows/sec and peak memory of every operation":                                               "Her işlemin süresini, satır/sn değerini ve en yüksek bellek kullanımını yazdır",
+	"Columns written as text even if they look like numbers, e.g. identifiers with leading zeros (e.g.: id,zip)": "Sayıya benzese de metin olarak yazılan sütunlar, örn. baştaki sıfırları olan kimlikler (örn.: id,zip)",
+	"Rename the columns to database-safe ASCII names after cleaning (snake, camel)":                              "Temizlemeden sonra sütunları veritabanı için güvenli ASCII adlarla yeniden adlandır (snake, camel)",
+	"Add the _source_file and _source_row columns to trace rows back to the input file":                          "Satırları girdi dosyasına kadar izlemek için _source_file ve _source_row sütunlarını ekle",
+	"YAML or JSON file with column profiles for the apply_profile action":                                        "apply_profile eylemi için sütun profillerini içeren YAML veya JSON dosyası",
+	"Pipeline spec file (YAML or JSON) whose actions run before the other cleaning flags":                        "Eylemleri diğer temizleme bayraklarından önce çalışan pipeline tanım dosyası (YAML veya JSON)",
+	"Name of a column added with the 1-based number of every input row":                                          "Her girdi satırının 1'den başlayan numarasıyla eklenen sütunun adı",
+	"File for the rows dropped by filters or failing to parse, with a reject_reason column":                      "Filtrelerin çıkardığı veya ayrıştırılamayan satırlar için reject_reason sütunlu dosya",
+	"Overwrite existing output and rejects files":                                                                "Mevcut çıktı ve reddedilen satır dosyalarının üzerine yaz",
+	"output file %s already exists, use --force to overwrite it":                                                 "çıktı dosyası %s zaten mevcut, üzerine yazmak için --force kullanın",
+	"Clean CSV and JSON inputs chunk by chunk instead of loading them into memory":                               "CSV ve JSON girdilerini belleğe yüklemek yerine parça parça temizle",
+	"Input size above which the input is streamed automatically, e.g. 512MB or 2GiB (0: never)":                  "Girdinin otomatik olarak akış halinde işlendiği boyut sınırı, örn. 512MB veya 2GiB (0: hiçbir zaman)",
+	"invalid --stream-threshold: %s":                                                                             "geçersiz --stream-threshold: %s",
+	"%s input cannot be streamed":                                                                                "%s girdisi akış halinde işlenemez",
+	"%s output cannot be streamed":                                                                               "%s çıktısı akış halinde işlenemez",
+	"%s needs the whole input":                                                                                   "%s tüm girdiyi gerektirir",
+	"the %s action needs the whole input":                                                                        "%s eylemi tüm girdiyi gerektirir",
+	"--stream is not possible: %s":                                                                               "--stream kullanılamaz: %s",
+	"Warning: %s is %s, larger than --stream-threshold, but is loaded into memory because %s":                    "Uyarı: %s %s boyutunda, --stream-threshold değerinden büyük, ancak belleğe yükleniyor çünkü %s",
+	"%s is %s, larger than --stream-threshold: streaming":                                                        "%s %s boyutunda, --stream-threshold değerinden büyük: akış halinde işleniyor",
+	"Statistics: %d rows read, %d rows written in %d chunks":                                                     "İstatistikler: %d parçada %d satır okundu, %d satır yazıldı",
+	"  Column\tCount\tNulls\t~Distinct\tMin\tMax":                                                                "  Sütun\tSayı\tBoş\t~Farklı\tEn küçük\tEn büyük",
+	"Encrypt the output and rejects files with age for comma-separated recipients, or with the passphrase in an environment variable (e.g.: age1...,age1... or env:CLEANGO_PASSPHRASE)": "Çıktı ve reddedilenler dosyalarını age ile virgülle ayrılmış alıcılar için veya bir ortam değişkenindeki parolayla şifrele (örn.: age1...,age1... veya env:CLEANGO_PASSPHRASE)",
+	"invalid --encrypt-for: %w": "geçersiz --encrypt-for: %w",
+	"JSON manifest file listing the written files with their row counts, SHA-256 checksums, the pipeline hash and timestamps": "Yazılan dosyaları satır sayıları, SHA-256 sağlama toplamları, pipeline özeti ve zaman damgalarıyla listeleyen JSON manifest dosyası",
+	"manifest write error: %w": "manifest yazma hatası: %w",
+	"Manifest written to %s":   "Manifest %s dosyasına yazıldı",
+	"environment variable %s of the encryption passphrase is not set":   "şifreleme parolasının %s ortam değişkeni tanımlı değil",
+	"at least one age recipient must be specified":                      "en az bir age alıcısı belirtilmelidir",
+	"rejects write error: %w":                                           "reddedilen satırlar yazma hatası: %w",
+	"%d rejected rows written to %s":                                    "%d reddedilen satır %s dosyasına yazıldı",
+	"Language of the messages (en, tr)":                                 "Mesajların dili (en, tr)",
+	"input file not specified — usage: cleango clean [flags] <file>":    "girdi dosyası belirtilmedi — kullanım: cleango clean [bayraklar] <dosya>",
+	"unsupported file format — supported: .csv, .json, .xlsx, .parquet": "desteklenmeyen dosya formatı — desteklenenler: .csv, .json, .xlsx, .parquet",
+	"read error: %w":           "okuma hatası: %w",
+	"write error: %w":          "yazma hatası: %w",
+	"invalid --regex-json: %w": "geçersiz --regex-json: %w",
+	"invalid --regex-json: rule %d needs a column and a pattern":               "geçersiz --regex-json: %d. kuralın bir sütunu ve bir deseni olmalı",
+	"Cleaned data written to %s":                                               "Temizlenen veri %s dosyasına yazıldı",
+	"  Estimated memory: %s":                                                   "  Tahmini bellek: %s",
+	"Statistics: %d rows, %d columns":                                          "İstatistikler: %d satır, %d sütun",
+	"  Rows dropped: %d":                                                       "  Çıkarılan satır: %d",
+	"  %s: %d modified, %d nulls replaced, %d rows dropped, %d parse failures": "  %s: %d değiştirildi, %d boş değer dolduruldu, %d satır çıkarıldı, %d ayrıştırma hatası",
+	"Report the columns whose most common value fills at least this share of the rows (e.g.: 0.99, 1 for single-valued columns)": "En yaygın değeri satırların en az bu oranını dolduran sütunları bildir (örn.: 0.99, tek değerli sütunlar için 1)",
+	"No constant columns (threshold %g)":                               "Sabit sütun yok (eşik %g)",
+	"Constant columns (threshold %g): %s":                              "Sabit sütunlar (eşik %g): %s",
+	"Print the scores as JSON":                                         "Puanları JSON olarak yazdır",
+	"input file not specified — usage: cleango profile [flags] <file>": "girdi dosyası belirtilmedi — kullanım: cleango profile [bayraklar] <dosya>",
+	"Quality score: %.2f (%d rows, %d columns)":                        "Kalite puanı: %.2f (%d satır, %d sütun)",
+	"Summary:": "Özet:",
+	"  Column\tCount\tNulls\tDistinct\tMin\tMax\tMean\tMedian\tStdDev": "  Sütun\tSayı\tBoş\tFarklı\tEn küçük\tEn büyük\tOrtalama\tMedyan\tStd. sapma",
+	"Estimated memory: %s": "Tahmini bellek: %s",
+	"  Column\tType\tCompleteness\tValidity\tUniqueness\tConsistency\tScore\tMemory":             "  Sütun\tTür\tTamlık\tGeçerlilik\tBenzersizlik\tTutarlılık\tPuan\tBellek",
+	"Report the columns that are missing, extra or of another type compared with the first file": "İlk dosyaya göre eksik, fazla veya farklı türde olan sütunları bildir",
+	"Print the schemas as JSON": "Şemaları JSON olarak yazdır",
+	"input files not specified — usage: cleango schema [--compare] [flags] <file>...": "girdi dosyaları belirtilmedi — kullanım: cleango schema [--compare] [bayraklar] <dosya>...",
+	"%s (%d rows, %d columns)":                                "%s (%d satır, %d sütun)",
+	"All %d files have the same columns and types as %s":      "%d dosyanın tümü %s ile aynı sütunlara ve türlere sahip",
+	"Compared with %s:":                                       "%s ile karşılaştırıldığında:",
+	"    missing: %s":                                         "    eksik: %s",
+	"    extra: %s":                                           "    fazla: %s",
+	"    type of %s: %s instead of %s":                        "    %[1]s türü: %[3]s yerine %[2]s",
+	"schemas differ":                                          "şemalar farklı",
+	"  lint     Checks a pipeline file without touching data": "  lint     Bir pipeline dosyasını verilere dokunmadan denetler",
+	"Data file whose columns the actions are checked against": "Eylemlerin sütunlarına göre denetlendiği veri dosyası",
+	"JSON schema file written by cleango schema --json whose columns the actions are checked against": "Eylemlerin sütunlarına göre denetlendiği, cleango schema --json ile yazılmış JSON şema dosyası",
+	"Print the problems as JSON": "Sorunları JSON olarak yazdır",
+	"pipeline file not specified — usage: cleango lint [--sample <file>|--schema <file>] [flags] <pipeline>": "pipeline dosyası belirtilmedi — kullanım: cleango lint [--sample <dosya>|--schema <dosya>] [bayraklar] <pipeline>",
+	"%s: %d actions, no problems found":        "%s: %d eylem, sorun bulunamadı",
+	"%s: %d problems found":                    "%s: %d sorun bulundu",
+	"  action %d (%s): %s":                     "  eylem %d (%s): %s",
+	"pipeline is invalid":                      "pipeline geçersiz",
+	"Timings:":                                 "Süreler:",
+	"  Operation\tTime\tRows/sec\tPeak memory": "  İşlem\tSüre\tSatır/sn\tEn yüksek bellek",
+	"  total\t%s\t\t%s":                        "  toplam\t%s\t\t%s",
+	" in parallel":                             " paralel olarak",
+	"%s error: %s":                             "%s hatası: %s",
+	"Sanitization":                             "Karakter temizleme",
+	"Control characters removed%s":             "Kontrol karakterleri%s kaldırıldı",
+	"Trim":                                     "Kırpma",
+	"Trim operation applied%s":                 "Kırpma işlemi%s uygulandı",
+	"Date cleaning":                            "Tarih temizleme",
+	"Date format cleaning applied%s for column %s": "Tarih formatı temizleme%s uygulandı, sütun: %s",
+	"Null replacement": "Boş değer değiştirme",
+	"Null values in column %s replaced with %s%s":         "%s sütunundaki boş değerler %s ile değiştirildi%s",
+	"Numeric format normalization":                        "Sayı formatı normalleştirme",
+	"Numeric formats normalized%s for column %s":          "Sayı formatları%s normalleştirildi, sütun: %s",
+	"Unit conversion":                                     "Birim dönüşümü",
+	"Unit conversion error: invalid precision":            "Birim dönüşümü hatası: geçersiz hassasiyet",
+	"Column %s converted from %s to %s%s":                 "%s sütunu %s biriminden %s birimine dönüştürüldü%s",
+	"Header normalization":                                "Başlık normalleştirme",
+	"Column names normalized to %s case":                  "Sütun adları %s biçimine dönüştürüldü",
+	"Anonymization":                                       "Anonimleştirme",
+	"Column %s replaced with fake %s values%s":            "%s sütunu sahte %s değerleriyle değiştirildi%s",
+	"Case conversion":                                     "Harf dönüşümü",
+	"upper case conversion applied%s for column %s":       "Büyük harf dönüşümü%s uygulandı, sütun: %s",
+	"lower case conversion applied%s for column %s":       "Küçük harf dönüşümü%s uygulandı, sütun: %s",
+	"Regex cleaning":                                      "Regex temizleme",
+	"Action %s applied%s":                                 "%s eylemi%s uygulandı",
+	"Regex cleaning applied%s for column %s":              "Regex temizleme%s uygulandı, sütun: %s",
+	"Column splitting":                                    "Sütun bölme",
+	"Column %s split with %s":                             "%s sütunu bölündü: %s",
+	"Outlier filtering":                                   "Aykırı değer filtreleme",
+	"Outlier filtering error: invalid number":             "Aykırı değer filtreleme hatası: geçersiz sayı",
+	"Outliers filtered in column %s (min: %g, max: %g)%s": "%s sütunundaki aykırı değerler filtrelendi (min: %g, maks: %g)%s",
+
+	// API server
+	"CleanGo API starting on port %s":                                  "CleanGo API %s portunda başlatılıyor",
+	"Server error: %v":                                                 "Sunucu hatası: %v",
+	"Shutting down server...":                                          "Sunucu kapatılıyor...",
+	"Server forced to shutdown: %v":                                    "Sunucu zorla kapatıldı: %v",
+	"Server stopped":                                                   "Sunucu durduruldu",
+	"Pipeline store error: %v":                                         "Pipeline deposu hatası: %v",
+	"Column profiles error: %v":                                        "Sütun profilleri hatası: %v",
+	"Default pipeline error: %v":                                       "Varsayılan pipeline hatası: %v",
+	"Result cache error: %v":                                           "Sonuç önbelleği hatası: %v",
+	"Output workspace error: %v":                                       "Çıktı çalışma alanı hatası: %v",
+	"Tenant configuration error: %v":                                   "Kiracı yapılandırma hatası: %v",
+	"Audit log error: %v":                                              "Denetim kaydı hatası: %v",
+	"Output cleanup failed: %v":                                        "Çıktı temizliği başarısız: %v",
+	"Tracing error: %v":                                                "İzleme hatası: %v",
+	"Tracing shutdown error: %v":                                       "İzleme kapatma hatası: %v",
+	"failed to encode JSON response: %v":                               "JSON yanıtı kodlanamadı: %v",
+	"normalize_dates: invalid epoch unit":                              "normalize_dates: geçersiz epoch birimi",
+	"drop_constant_columns: invalid threshold":                         "drop_constant_columns: geçersiz eşik",
+	"fake_column: invalid kind or seed":                                "fake_column: geçersiz tür veya tohum",
+	"convert_units: invalid precision":                                 "convert_units: geçersiz hassasiyet",
+	"dedupe_fuzzy: invalid threshold":                                  "dedupe_fuzzy: geçersiz eşik",
+	"filter_outliers: invalid number":                                  "filter_outliers: geçersiz sayı",
+	"[%s] result cache read failed: %v":                                "[%s] sonuç önbelleği okunamadı: %v",
+	"[%s] audit log write failed: %v":                                  "[%s] denetim kaydı yazılamadı: %v",
+	"[%s] result cache write failed: %v":                               "[%s] sonuç önbelleğine yazılamadı: %v",
+	"Data cleaned successfully":                                        "Veri başarıyla temizlendi",
+	"Data profiled successfully":                                       "Veri başarıyla profillendi",
+	"File cleaned successfully":                                        "Dosya başarıyla temizlendi",
+	"File converted successfully":                                      "Dosya başarıyla dönüştürüldü",
+	"Output must differ from the input file":                           "Çıktı girdi dosyasından farklı olmalıdır",
+	"Request has no uploaded file":                                     "İstekte yüklenmiş bir dosya yok",
+	"Uploaded file could not be stored":                                "Yüklenen dosya saklanamadı",
+	"Invalid preserve_types":                                           "Geçersiz preserve_types",
+	"Invalid parallel":                                                 "Geçersiz parallel",
+	"Invalid max_workers":                                              "Geçersiz max_workers",
+	"Upload is larger than the allowed size":                           "Yükleme izin verilen boyuttan büyük",
+	"Upload limit error: %v":                                           "Yükleme sınırı hatası: %v",
+	"Only POST requests are supported":                                 "Yalnızca POST istekleri desteklenir",
+	"Only GET and POST requests are supported":                         "Yalnızca GET ve POST istekleri desteklenir",
+	"Only GET, PUT and DELETE requests are supported":                  "Yalnızca GET, PUT ve DELETE istekleri desteklenir",
+	"Request body is not valid JSON":                                   "İstek gövdesi geçerli bir JSON değil",
+	"Data cannot be empty":                                             "Veri boş olamaz",
+	"Data cannot be converted to a DataFrame":                          "Veri DataFrame'e dönüştürülemiyor",
+	"Data cannot be converted to Arrow":                                "Veri Arrow'a dönüştürülemiyor",
+	"File path not specified":                                          "Dosya yolu belirtilmedi",
+	"Invalid file path":                                                "Geçersiz dosya yolu",
+	"File path is outside the allowed directory":                       "Dosya yolu izin verilen dizinin dışında",
+	"Unsupported file format":                                          "Desteklenmeyen dosya formatı",
+	"Unsupported output format":                                        "Desteklenmeyen çıktı formatı",
+	"Invalid format options":                                           "Geçersiz format seçenekleri",
+	"File could not be read":                                           "Dosya okunamadı",
+	"File could not be written":                                        "Dosya yazılamadı",
+	"Action could not be applied":                                      "İşlem uygulanamadı",
+	"Server worker budget exhausted":                                   "Sunucu işçi kapasitesi tükendi",
+	"Pipeline not found":                                               "Pipeline bulunamadı",
+	"Pipeline already exists":                                          "Pipeline zaten mevcut",
+	"Pipeline could not be stored":                                     "Pipeline kaydedilemedi",
+	"Pipeline contains an invalid action":                              "Pipeline geçersiz bir işlem içeriyor",
+	"Pipeline must contain at least one action":                        "Pipeline en az bir işlem içermelidir",
+	"Pipeline name must contain only letters, digits, '.', '_' or '-'": "Pipeline adı yalnızca harf, rakam, '.', '_' veya '-' içermelidir",
+	"response_limit and offset must not be negative":                   "response_limit ve offset negatif olamaz",
+	"batch_size must be a positive integer":                            "batch_size pozitif bir tam sayı olmalıdır",
+	"Only DELETE requests are supported":                               "Yalnızca DELETE istekleri desteklenir",
+	"Dry run completed, no data was returned":                          "Deneme çalıştırması tamamlandı, veri döndürülmedi",
+	"Dry run completed, no file was written":                           "Deneme çalıştırması tamamlandı, dosya yazılmadı",
+	"Job not found":                                                    "İş bulunamadı",
+	"Job could not be deleted":                                         "İş silinemedi",
+	"Output is larger than the workspace quota":                        "Çıktı, çalışma alanı kotasından büyük",
+	"Only GET requests are supported":                                  "Yalnızca GET istekleri desteklenir",
+	"Audit log is not enabled":                                         "Denetim kaydı etkin değil",
+	"Audit log could not be read":                                      "Denetim kaydı okunamadı",
+	"since must be an RFC 3339 time":                                   "since bir RFC 3339 zamanı olmalıdır",
+	"limit must be a positive integer":                                 "limit pozitif bir tam sayı olmalıdır",
+	"Missing or invalid API key":                                       "API anahtarı eksik veya geçersiz",
+	"Tenant ID must contain only letters, digits, '.', '_' or '-'":     "Kiracı kimliği yalnızca harf, rakam, '.', '_' veya '-' içermelidir",
+}